@@ -7,10 +7,23 @@ import (
 	"github.com/joho/godotenv"
 )
 
+// Proveedores WebSocket soportados
+const (
+	// ProviderAlchemy usa alchemy_minedTransactions y requiere ALCHEMY_API_KEY
+	ProviderAlchemy = "alchemy"
+	// ProviderStandard usa eth_subscribe("logs") con cualquier nodo JSON-RPC
+	ProviderStandard = "standard"
+)
+
 type Config struct {
+	// Provider es alchemy o standard; sin WS_PROVIDER se usa alchemy si hay
+	// ALCHEMY_API_KEY y standard si no
+	Provider      string
 	AlchemyAPIKey string
 	AlchemyWSURL  string
-	Port          string
+	// EthWSURL es el endpoint WebSocket completo del proveedor standard
+	EthWSURL string
+	Port     string
 }
 
 func LoadConfig() *Config {
@@ -22,11 +35,24 @@ func LoadConfig() *Config {
 	config := &Config{
 		AlchemyAPIKey: getEnv("ALCHEMY_API_KEY", ""),
 		AlchemyWSURL:  getEnv("ALCHEMY_WS_URL", "wss://eth-sepolia.g.alchemy.com/v2"),
+		EthWSURL:      getEnv("ETH_WS_URL", "ws://localhost:8545"),
 		Port:          getEnv("PORT", "8081"),
 	}
 
-	if config.AlchemyAPIKey == "" {
-		log.Fatal("ALCHEMY_API_KEY es requerida")
+	defaultProvider := ProviderStandard
+	if config.AlchemyAPIKey != "" {
+		defaultProvider = ProviderAlchemy
+	}
+	config.Provider = getEnv("WS_PROVIDER", defaultProvider)
+
+	switch config.Provider {
+	case ProviderAlchemy:
+		if config.AlchemyAPIKey == "" {
+			log.Fatal("ALCHEMY_API_KEY es requerida con WS_PROVIDER=alchemy")
+		}
+	case ProviderStandard:
+	default:
+		log.Fatalf("WS_PROVIDER inválido: %s (usar %s o %s)", config.Provider, ProviderAlchemy, ProviderStandard)
 	}
 
 	return config
@@ -37,4 +63,4 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
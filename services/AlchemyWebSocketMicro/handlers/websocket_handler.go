@@ -90,11 +90,10 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 	confirmMsg := models.WebSocketMessage{
 		Type:         "connected",
 		ContractAddr: contractAddress,
-		Data:         map[string]string{"status": "monitoring started"},
+		Data:         map[string]string{"status": "monitoring started", "format": services.FormatRaw},
 	}
 	
-	if msgBytes, err := json.Marshal(confirmMsg); err == nil {
-		conn.WriteMessage(websocket.TextMessage, msgBytes)
+	if err := h.alchemyService.SendToClient(contractAddress, conn, confirmMsg); err == nil {
 		log.Printf("📤 Mensaje de confirmación enviado para: %s", contractAddress)
 	}
 
@@ -112,18 +111,37 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 
 		log.Printf("📨 Mensaje recibido del cliente %s (tipo: %d): %s", contractAddress, messageType, string(message))
 
-		// Echo del mensaje (opcional, para debugging)
-		if messageType == websocket.TextMessage {
-			echoMsg := models.WebSocketMessage{
-				Type:         "echo",
+		if messageType != websocket.TextMessage {
+			continue
+		}
+
+		// {"action":"subscribe","format":"raw|decoded|both"} elige el formato
+		var clientMsg models.ClientMessage
+		if err := json.Unmarshal(message, &clientMsg); err == nil && clientMsg.Action == "subscribe" {
+			reply := models.WebSocketMessage{
+				Type:         "subscribed",
 				ContractAddr: contractAddress,
-				Data:         string(message),
+				Data:         map[string]string{"format": clientMsg.Format},
 			}
-			
-			if msgBytes, err := json.Marshal(echoMsg); err == nil {
-				conn.WriteMessage(websocket.TextMessage, msgBytes)
-				log.Printf("📤 Echo enviado para: %s", contractAddress)
+			if err := h.alchemyService.SetClientFormat(contractAddress, conn, clientMsg.Format); err != nil {
+				log.Printf("❌ Formato rechazado para %s: %v", contractAddress, err)
+				reply.Type = "error"
+				reply.Data = map[string]string{"error": err.Error()}
 			}
+			if err := h.alchemyService.SendToClient(contractAddress, conn, reply); err != nil {
+				log.Printf("❌ Error enviando respuesta a cliente: %v", err)
+			}
+			continue
+		}
+
+		// Echo del mensaje (opcional, para debugging)
+		echoMsg := models.WebSocketMessage{
+			Type:         "echo",
+			ContractAddr: contractAddress,
+			Data:         string(message),
+		}
+		if err := h.alchemyService.SendToClient(contractAddress, conn, echoMsg); err == nil {
+			log.Printf("📤 Echo enviado para: %s", contractAddress)
 		}
 	}
 }
@@ -144,6 +162,8 @@ func (h *WebSocketHandler) GetMonitorStatus(c *gin.Context) {
 		"message":       "Estado de monitoreo obtenido exitosamente",
 		"activeMonitors": len(statuses),
 		"subscriptions": statuses,
+		"provider":       h.alchemyService.ProviderName(),
+		"lastBlock":      h.alchemyService.LastBlock(),
 	})
 }
 
@@ -16,16 +16,26 @@ func main() {
 	// Cargar configuración
 	cfg := config.LoadConfig()
 	log.Printf("⚙️ Configuración cargada - Puerto: %s", cfg.Port)
-	log.Printf("🔗 Alchemy WebSocket URL: %s", cfg.AlchemyWSURL)
 
-	// Inicializar servicio de Alchemy
-	alchemyService := services.NewAlchemyService(cfg.AlchemyWSURL, cfg.AlchemyAPIKey)
-	
-	log.Printf("🔌 Iniciando conexión con Alchemy...")
+	// Elegir el proveedor WebSocket
+	var provider services.Provider
+	switch cfg.Provider {
+	case config.ProviderAlchemy:
+		provider = services.NewAlchemyProvider(cfg.AlchemyWSURL, cfg.AlchemyAPIKey)
+		log.Printf("🔗 Alchemy WebSocket URL: %s", cfg.AlchemyWSURL)
+	default:
+		provider = services.NewStandardProvider(cfg.EthWSURL)
+		log.Printf("🔗 WebSocket URL: %s", cfg.EthWSURL)
+	}
+
+	// Inicializar servicio de monitoreo
+	alchemyService := services.NewAlchemyService(provider)
+
+	log.Printf("🔌 Iniciando conexión con %s...", provider.Name())
 	if err := alchemyService.Start(); err != nil {
-		log.Fatalf("❌ Error iniciando servicio de Alchemy: %v", err)
+		log.Fatalf("❌ Error iniciando servicio de %s: %v", provider.Name(), err)
 	}
-	log.Printf("✅ Servicio de Alchemy iniciado exitosamente")
+	log.Printf("✅ Servicio de %s iniciado exitosamente", provider.Name())
 
 	// Inicializar handlers
 	wsHandler := handlers.NewWebSocketHandler(alchemyService)
@@ -89,7 +99,8 @@ func main() {
 		c.JSON(200, gin.H{
 			"service":     "AlchemyWebSocketMicro",
 			"version":     "1.0.0",
-			"description": "Microservicio WebSocket para monitoreo de transacciones Ethereum via Alchemy",
+			"description": "Microservicio WebSocket para monitoreo de contratos Ethereum via Alchemy o cualquier nodo JSON-RPC",
+			"provider":    provider.Name(),
 			"endpoints": gin.H{
 				"health":    "/api/v1/health",
 				"websocket": "/ws/monitor/{contractAddress}",
@@ -36,6 +36,18 @@ type AddressFilter struct {
 	To string `json:"to"`
 }
 
+// LogFilter filtro de la suscripción estándar eth_subscribe("logs")
+type LogFilter struct {
+	Address string `json:"address"`
+}
+
+// BlockHeader campos de la notificación newHeads que usa el servicio
+type BlockHeader struct {
+	Number    string `json:"number"`
+	Hash      string `json:"hash"`
+	Timestamp string `json:"timestamp"`
+}
+
 // TransactionNotification notificación de transacción
 type TransactionNotification struct {
 	JSONRPC string `json:"jsonrpc"`
@@ -46,6 +58,69 @@ type TransactionNotification struct {
 	} `json:"params"`
 }
 
+// ContractNotification campos de una notificación de contrato. Un log de
+// eth_subscribe("logs") trae topics y data; alchemy_minedTransactions trae la
+// transacción en transaction.
+type ContractNotification struct {
+	Address         string            `json:"address"`
+	Topics          []string          `json:"topics"`
+	Data            string            `json:"data"`
+	BlockNumber     string            `json:"blockNumber"`
+	TransactionHash string            `json:"transactionHash"`
+	LogIndex        string            `json:"logIndex"`
+	Removed         bool              `json:"removed"`
+	Transaction     *MinedTransaction `json:"transaction"`
+	Hash            string            `json:"hash"`
+	From            string            `json:"from"`
+	Input           string            `json:"input"`
+}
+
+// MinedTransaction transacción minada que envía alchemy_minedTransactions
+type MinedTransaction struct {
+	Hash        string `json:"hash"`
+	From        string `json:"from"`
+	Input       string `json:"input"`
+	BlockNumber string `json:"blockNumber"`
+}
+
+// TransactionReceipt campos de eth_getTransactionReceipt que usa el servicio
+type TransactionReceipt struct {
+	Status      string       `json:"status"`
+	From        string       `json:"from"`
+	BlockNumber string       `json:"blockNumber"`
+	Logs        []ReceiptLog `json:"logs"`
+}
+
+// ReceiptLog log del recibo de una transacción
+type ReceiptLog struct {
+	Address  string   `json:"address"`
+	Topics   []string `json:"topics"`
+	Data     string   `json:"data"`
+	LogIndex string   `json:"logIndex"`
+}
+
+// DecodedEventData datos de un mensaje decodificado de LoteTracing. Las
+// transacciones fallidas no emiten eventos: llevan solo la función y sus
+// parámetros.
+type DecodedEventData struct {
+	Event       string                 `json:"event,omitempty"`
+	Status      string                 `json:"status"`
+	TxHash      string                 `json:"txHash"`
+	BlockNumber uint64                 `json:"blockNumber,omitempty"`
+	LogIndex    *uint64                `json:"logIndex,omitempty"`
+	From        string                 `json:"from,omitempty"`
+	Params      map[string]interface{} `json:"params,omitempty"`
+	Function    string                 `json:"function,omitempty"`
+	CallParams  map[string]interface{} `json:"callParams,omitempty"`
+}
+
+// ClientMessage mensaje que envía el cliente WebSocket, por ejemplo
+// {"action":"subscribe","format":"decoded"}
+type ClientMessage struct {
+	Action string `json:"action"`
+	Format string `json:"format"`
+}
+
 // WebSocketMessage mensaje para enviar a clientes WebSocket
 type WebSocketMessage struct {
 	Type         string      `json:"type"`
@@ -6,66 +6,113 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
 )
 
+// newHeadsTarget identifica en las solicitudes pendientes la suscripción a
+// bloques nuevos, que no pertenece a ningún contrato
+const newHeadsTarget = "newHeads"
+
+// AlchemyService mantiene la conexión WebSocket con el proveedor y reenvía a
+// los clientes la actividad de los contratos que siguen. El proveedor decide
+// la URL y la suscripción de cada contrato (ver Provider).
 type AlchemyService struct {
-	wsURL       string
-	apiKey      string
+	provider    Provider
 	conn        *websocket.Conn
 	subscribers map[string]*Subscription
 	mu          sync.RWMutex
 	reconnectCh chan bool
+
+	// writeMu serializa las escrituras en la conexión con el proveedor
+	writeMu sync.Mutex
+	// requestID numera las solicitudes eth_subscribe
+	requestID atomic.Int64
+
+	// pending asocia cada solicitud eth_subscribe sin respuesta con su
+	// contrato o con newHeadsTarget
+	pendingMu           sync.Mutex
+	pending             map[int]string
+	headsSubscriptionID string
+	lastBlock           uint64
+	// calls espera la respuesta de cada llamada JSON-RPC en curso
+	calls map[int]chan models.AlchemyResponse
+
+	// decodeQueue ordena las notificaciones que se decodifican, que
+	// necesitan consultar el recibo sin bloquear la lectura
+	decodeQueue chan decodeJob
 }
 
 type Subscription struct {
 	ContractAddress string
 	SubscriptionID  string
-	Clients         map[*websocket.Conn]bool
-	ClientsMu       sync.RWMutex
+	// Clients guarda el formato de mensajes que eligió cada cliente
+	Clients   map[*websocket.Conn]string
+	ClientsMu sync.RWMutex
 }
 
-func NewAlchemyService(wsURL, apiKey string) *AlchemyService {
+func NewAlchemyService(provider Provider) *AlchemyService {
 	return &AlchemyService{
-		wsURL:       fmt.Sprintf("%s/%s", wsURL, apiKey),
-		apiKey:      apiKey,
+		provider:    provider,
 		subscribers: make(map[string]*Subscription),
 		reconnectCh: make(chan bool, 1),
+		pending:     make(map[int]string),
+		calls:       make(map[int]chan models.AlchemyResponse),
+		decodeQueue: make(chan decodeJob, 256),
 	}
 }
 
 func (a *AlchemyService) Start() error {
-	log.Printf("🚀 Iniciando AlchemyService con URL: %s", a.wsURL)
-	
+	log.Printf("🚀 Iniciando AlchemyService con proveedor %s", a.provider.Name())
+
 	if err := a.connect(); err != nil {
-		return fmt.Errorf("error conectando a Alchemy: %v", err)
+		return fmt.Errorf("error conectando a %s: %v", a.provider.Name(), err)
 	}
 
 	go a.handleReconnection()
 	go a.readMessages()
+	go a.decodeNotifications()
 
 	return nil
 }
 
 func (a *AlchemyService) connect() error {
-	log.Printf("🔌 Conectando a Alchemy WebSocket...")
-	
-	conn, _, err := websocket.DefaultDialer.Dial(a.wsURL, nil)
+	log.Printf("🔌 Conectando a WebSocket de %s...", a.provider.Name())
+
+	conn, _, err := websocket.DefaultDialer.Dial(a.provider.URL(), nil)
 	if err != nil {
 		return fmt.Errorf("error en dial: %v", err)
 	}
 
+	// Las suscripciones de la conexión anterior dejan de existir en el nodo
+	a.pendingMu.Lock()
+	a.pending = make(map[int]string)
+	a.headsSubscriptionID = ""
+	a.pendingMu.Unlock()
+	a.mu.Lock()
+	for _, sub := range a.subscribers {
+		sub.SubscriptionID = ""
+	}
+	a.mu.Unlock()
+
+	a.writeMu.Lock()
 	a.conn = conn
-	log.Printf("✅ Conectado exitosamente a Alchemy WebSocket")
+	a.writeMu.Unlock()
+	log.Printf("✅ Conectado exitosamente a %s WebSocket", a.provider.Name())
+
+	// newHeads es estándar en todos los proveedores
+	if err := a.subscribe([]interface{}{"newHeads"}, newHeadsTarget); err != nil {
+		log.Printf("⚠️ Error suscribiendo a newHeads: %v", err)
+	}
 	return nil
 }
 
 func (a *AlchemyService) handleReconnection() {
 	for range a.reconnectCh {
-		log.Printf("🔄 Intentando reconectar a Alchemy...")
-		
+		log.Printf("🔄 Intentando reconectar a %s...", a.provider.Name())
+
 		for {
 			if err := a.connect(); err != nil {
 				log.Printf("❌ Error en reconexión: %v. Reintentando en 5s...", err)
@@ -74,7 +121,8 @@ func (a *AlchemyService) handleReconnection() {
 			}
 
 			log.Printf("✅ Reconectado exitosamente")
-			
+			go a.readMessages()
+
 			// Re-suscribir a todos los contratos activos
 			a.mu.RLock()
 			for contractAddr := range a.subscribers {
@@ -86,26 +134,27 @@ func (a *AlchemyService) handleReconnection() {
 	}
 }
 
+// readMessages lee la conexión actual hasta que falla. Solo cierra esa
+// conexión: tras pedir la reconexión, a.conn puede ser ya la nueva.
 func (a *AlchemyService) readMessages() {
-	defer func() {
-		if a.conn != nil {
-			a.conn.Close()
+	a.writeMu.Lock()
+	conn := a.conn
+	a.writeMu.Unlock()
+
+	if conn == nil {
+		log.Printf("⚠️ Conexión WebSocket es nil, solicitando reconexión...")
+		select {
+		case a.reconnectCh <- true:
+		default:
 		}
-	}()
+		return
+	}
+	defer conn.Close()
 
 	for {
-		if a.conn == nil {
-			log.Printf("⚠️ Conexión WebSocket es nil, solicitando reconexión...")
-			select {
-			case a.reconnectCh <- true:
-			default:
-			}
-			return
-		}
-
-		_, message, err := a.conn.ReadMessage()
+		_, message, err := conn.ReadMessage()
 		if err != nil {
-			log.Printf("❌ Error leyendo mensaje de Alchemy: %v", err)
+			log.Printf("❌ Error leyendo mensaje de %s: %v", a.provider.Name(), err)
 			select {
 			case a.reconnectCh <- true:
 			default:
@@ -113,7 +162,7 @@ func (a *AlchemyService) readMessages() {
 			return
 		}
 
-		log.Printf("📨 Mensaje recibido de Alchemy: %s", string(message))
+		log.Printf("📨 Mensaje recibido de %s: %s", a.provider.Name(), string(message))
 		a.handleAlchemyMessage(message)
 	}
 }
@@ -121,7 +170,25 @@ func (a *AlchemyService) readMessages() {
 func (a *AlchemyService) handleAlchemyMessage(message []byte) {
 	// Intentar parsear como respuesta de suscripción
 	var response models.AlchemyResponse
-	if err := json.Unmarshal(message, &response); err == nil && response.Result != nil {
+	if err := json.Unmarshal(message, &response); err == nil && response.ID != 0 {
+		a.pendingMu.Lock()
+		call, ok := a.calls[response.ID]
+		delete(a.calls, response.ID)
+		a.pendingMu.Unlock()
+		if ok {
+			call <- response
+			return
+		}
+	}
+	if response.Error != nil {
+		a.pendingMu.Lock()
+		target := a.pending[response.ID]
+		delete(a.pending, response.ID)
+		a.pendingMu.Unlock()
+		log.Printf("❌ Suscripción rechazada para %s - ID: %d: %s", target, response.ID, response.Error.Message)
+		return
+	}
+	if response.Result != nil {
 		log.Printf("📋 Respuesta de suscripción recibida - ID: %d", response.ID)
 		a.handleSubscriptionResponse(&response)
 		return
@@ -135,7 +202,7 @@ func (a *AlchemyService) handleAlchemyMessage(message []byte) {
 		return
 	}
 
-	log.Printf("⚠️ Mensaje no reconocido de Alchemy: %s", string(message))
+	log.Printf("⚠️ Mensaje no reconocido de %s: %s", a.provider.Name(), string(message))
 }
 
 func (a *AlchemyService) handleSubscriptionResponse(response *models.AlchemyResponse) {
@@ -146,20 +213,37 @@ func (a *AlchemyService) handleSubscriptionResponse(response *models.AlchemyResp
 	}
 
 	log.Printf("✅ Suscripción creada exitosamente - ID: %s", subscriptionID)
-	
-	// Actualizar el subscription ID en la suscripción correspondiente
+
+	// La respuesta lleva el ID de la solicitud, que indica a qué contrato
+	// pertenece la suscripción
+	a.pendingMu.Lock()
+	target, ok := a.pending[response.ID]
+	delete(a.pending, response.ID)
+	if target == newHeadsTarget {
+		a.headsSubscriptionID = subscriptionID
+	}
+	a.pendingMu.Unlock()
+	if !ok || target == newHeadsTarget {
+		return
+	}
+
 	a.mu.Lock()
-	for _, sub := range a.subscribers {
-		if sub.SubscriptionID == "" {
-			sub.SubscriptionID = subscriptionID
-			log.Printf("🔗 Subscription ID %s asignado a contrato %s", subscriptionID, sub.ContractAddress)
-			break
-		}
+	if sub, exists := a.subscribers[target]; exists {
+		sub.SubscriptionID = subscriptionID
+		log.Printf("🔗 Subscription ID %s asignado a contrato %s", subscriptionID, sub.ContractAddress)
 	}
 	a.mu.Unlock()
 }
 
 func (a *AlchemyService) handleTransactionNotification(notification *models.TransactionNotification) {
+	a.pendingMu.Lock()
+	isHead := notification.Params.Subscription == a.headsSubscriptionID
+	a.pendingMu.Unlock()
+	if isHead {
+		a.handleNewHead(notification.Params.Result)
+		return
+	}
+
 	log.Printf("💰 Procesando transacción - Subscription: %s", notification.Params.Subscription)
 	log.Printf("📄 Datos de transacción: %s", string(notification.Params.Result))
 
@@ -183,12 +267,62 @@ func (a *AlchemyService) handleTransactionNotification(notification *models.Tran
 
 	// Crear mensaje para clientes WebSocket
 	wsMessage := models.WebSocketMessage{
-		Type:         "transaction",
+		Type:         a.provider.MessageType(),
 		ContractAddr: targetSub.ContractAddress,
 		Data:         json.RawMessage(notification.Params.Result),
 		Timestamp:    time.Now().Unix(),
 	}
+	a.broadcast(targetSub, wsMessage, FormatRaw)
+
+	// Los clientes con formato decoded reciben los eventos decodificados
+	// cuando el worker obtiene el recibo
+	if targetSub.wants(FormatDecoded) {
+		select {
+		case a.decodeQueue <- decodeJob{sub: targetSub, result: notification.Params.Result}:
+		default:
+			log.Printf("⚠️ Cola de decodificación llena, notificación descartada para: %s", targetSub.ContractAddress)
+		}
+	}
+}
 
+// handleNewHead registra el último bloque y lo reenvía a todos los clientes
+func (a *AlchemyService) handleNewHead(result json.RawMessage) {
+	var header models.BlockHeader
+	if err := json.Unmarshal(result, &header); err != nil {
+		log.Printf("❌ Error parseando bloque: %v", err)
+		return
+	}
+	number, err := hexToUint64(header.Number)
+	if err != nil {
+		log.Printf("❌ Número de bloque inválido %q: %v", header.Number, err)
+		return
+	}
+
+	a.pendingMu.Lock()
+	a.lastBlock = number
+	a.pendingMu.Unlock()
+	log.Printf("🧱 Nuevo bloque: %d", number)
+
+	a.mu.RLock()
+	subs := make([]*Subscription, 0, len(a.subscribers))
+	for _, sub := range a.subscribers {
+		subs = append(subs, sub)
+	}
+	a.mu.RUnlock()
+
+	for _, sub := range subs {
+		a.broadcast(sub, models.WebSocketMessage{
+			Type:         MessageTypeNewHead,
+			ContractAddr: sub.ContractAddress,
+			Data:         header,
+			Timestamp:    time.Now().Unix(),
+		}, "")
+	}
+}
+
+// broadcast envía el mensaje a los clientes de la suscripción que eligieron
+// el formato (vacío lo envía a todos) y quita los que ya no responden
+func (a *AlchemyService) broadcast(sub *Subscription, wsMessage models.WebSocketMessage, format string) {
 	messageBytes, err := json.Marshal(wsMessage)
 	if err != nil {
 		log.Printf("❌ Error serializando mensaje: %v", err)
@@ -196,21 +330,78 @@ func (a *AlchemyService) handleTransactionNotification(notification *models.Tran
 	}
 
 	// Enviar a todos los clientes conectados
-	targetSub.ClientsMu.RLock()
-	clientCount := len(targetSub.Clients)
-	log.Printf("📤 Enviando a %d clientes conectados", clientCount)
-	
-	for client := range targetSub.Clients {
+	sub.ClientsMu.Lock()
+	defer sub.ClientsMu.Unlock()
+	log.Printf("📤 Enviando %s a los clientes conectados", wsMessage.Type)
+
+	for client, clientFormat := range sub.Clients {
+		if format != "" && clientFormat != format && clientFormat != FormatBoth {
+			continue
+		}
 		if err := client.WriteMessage(websocket.TextMessage, messageBytes); err != nil {
 			log.Printf("❌ Error enviando mensaje a cliente: %v", err)
 			// Remover cliente desconectado
-			delete(targetSub.Clients, client)
+			delete(sub.Clients, client)
 			client.Close()
 		} else {
 			log.Printf("✅ Mensaje enviado exitosamente a cliente")
 		}
 	}
-	targetSub.ClientsMu.RUnlock()
+}
+
+// wants indica si algún cliente de la suscripción recibe el formato
+func (s *Subscription) wants(format string) bool {
+	s.ClientsMu.RLock()
+	defer s.ClientsMu.RUnlock()
+	for _, clientFormat := range s.Clients {
+		if clientFormat == format || clientFormat == FormatBoth {
+			return true
+		}
+	}
+	return false
+}
+
+// SetClientFormat cambia el formato de mensajes que recibe el cliente
+func (a *AlchemyService) SetClientFormat(contractAddress string, client *websocket.Conn, format string) error {
+	if format != FormatRaw && format != FormatDecoded && format != FormatBoth {
+		return fmt.Errorf("formato inválido %q: use %s, %s o %s", format, FormatRaw, FormatDecoded, FormatBoth)
+	}
+
+	a.mu.RLock()
+	sub, exists := a.subscribers[contractAddress]
+	a.mu.RUnlock()
+	if !exists {
+		return fmt.Errorf("no hay suscripción para %s", contractAddress)
+	}
+
+	sub.ClientsMu.Lock()
+	defer sub.ClientsMu.Unlock()
+	if _, ok := sub.Clients[client]; !ok {
+		return fmt.Errorf("cliente no suscrito a %s", contractAddress)
+	}
+	sub.Clients[client] = format
+	log.Printf("🎛️ Cliente de %s recibirá formato %s", contractAddress, format)
+	return nil
+}
+
+// SendToClient escribe un mensaje a un cliente de la suscripción sin pisar
+// los envíos de broadcast, que comparten la conexión
+func (a *AlchemyService) SendToClient(contractAddress string, client *websocket.Conn, wsMessage models.WebSocketMessage) error {
+	messageBytes, err := json.Marshal(wsMessage)
+	if err != nil {
+		return fmt.Errorf("error serializando mensaje: %v", err)
+	}
+
+	a.mu.RLock()
+	sub, exists := a.subscribers[contractAddress]
+	a.mu.RUnlock()
+	if !exists {
+		return client.WriteMessage(websocket.TextMessage, messageBytes)
+	}
+
+	sub.ClientsMu.Lock()
+	defer sub.ClientsMu.Unlock()
+	return client.WriteMessage(websocket.TextMessage, messageBytes)
 }
 
 func (a *AlchemyService) SubscribeToContract(contractAddress string, client *websocket.Conn) error {
@@ -223,7 +414,7 @@ func (a *AlchemyService) SubscribeToContract(contractAddress string, client *web
 	if sub, exists := a.subscribers[contractAddress]; exists {
 		log.Printf("📌 Suscripción existente encontrada para: %s", contractAddress)
 		sub.ClientsMu.Lock()
-		sub.Clients[client] = true
+		sub.Clients[client] = FormatRaw
 		sub.ClientsMu.Unlock()
 		log.Printf("👥 Cliente agregado a suscripción existente. Total clientes: %d", len(sub.Clients))
 		return nil
@@ -232,34 +423,30 @@ func (a *AlchemyService) SubscribeToContract(contractAddress string, client *web
 	// Crear nueva suscripción
 	subscription := &Subscription{
 		ContractAddress: contractAddress,
-		Clients:         make(map[*websocket.Conn]bool),
+		Clients:         make(map[*websocket.Conn]string),
 	}
-	subscription.Clients[client] = true
+	subscription.Clients[client] = FormatRaw
 	a.subscribers[contractAddress] = subscription
 
 	log.Printf("🆕 Nueva suscripción creada para: %s", contractAddress)
 
-	// Suscribirse a Alchemy
+	// Suscribirse en el proveedor
 	return a.subscribeToContract(contractAddress)
 }
 
 func (a *AlchemyService) subscribeToContract(contractAddress string) error {
-	log.Printf("📡 Enviando suscripción a Alchemy para: %s", contractAddress)
+	log.Printf("📡 Enviando suscripción a %s para: %s", a.provider.Name(), contractAddress)
+	return a.subscribe(a.provider.ContractSubscription(contractAddress), contractAddress)
+}
 
+// subscribe envía eth_subscribe y recuerda a qué contrato (o newHeadsTarget)
+// corresponde la respuesta
+func (a *AlchemyService) subscribe(params []interface{}, target string) error {
 	request := models.AlchemyRequest{
 		JSONRPC: "2.0",
 		Method:  "eth_subscribe",
-		Params: []interface{}{
-			"alchemy_minedTransactions",
-			models.SubscriptionParams{
-				Addresses: []models.AddressFilter{
-					{To: contractAddress},
-				},
-				IncludeRemoved: false,
-				HashesOnly:     false,
-			},
-		},
-		ID: int(time.Now().Unix()),
+		Params:  params,
+		ID:      int(a.requestID.Add(1)),
 	}
 
 	requestBytes, err := json.Marshal(request)
@@ -267,17 +454,23 @@ func (a *AlchemyService) subscribeToContract(contractAddress string) error {
 		return fmt.Errorf("error serializando request: %v", err)
 	}
 
-	log.Printf("📤 Enviando request a Alchemy: %s", string(requestBytes))
+	log.Printf("📤 Enviando request a %s: %s", a.provider.Name(), string(requestBytes))
+
+	a.pendingMu.Lock()
+	a.pending[request.ID] = target
+	a.pendingMu.Unlock()
 
+	a.writeMu.Lock()
+	defer a.writeMu.Unlock()
 	if a.conn == nil {
 		return fmt.Errorf("conexión WebSocket no disponible")
 	}
 
 	if err := a.conn.WriteMessage(websocket.TextMessage, requestBytes); err != nil {
-		return fmt.Errorf("error enviando mensaje a Alchemy: %v", err)
+		return fmt.Errorf("error enviando mensaje a %s: %v", a.provider.Name(), err)
 	}
 
-	log.Printf("✅ Request enviado exitosamente a Alchemy")
+	log.Printf("✅ Request enviado exitosamente a %s", a.provider.Name())
 	return nil
 }
 
@@ -321,4 +514,16 @@ func (a *AlchemyService) GetActiveSubscriptions() []models.MonitorStatus {
 	}
 
 	return statuses
-}
\ No newline at end of file
+}
+
+// ProviderName devuelve el nombre del proveedor WebSocket
+func (a *AlchemyService) ProviderName() string {
+	return a.provider.Name()
+}
+
+// LastBlock devuelve el último bloque recibido por newHeads; 0 si aún no llegó ninguno
+func (a *AlchemyService) LastBlock() uint64 {
+	a.pendingMu.Lock()
+	defer a.pendingMu.Unlock()
+	return a.lastBlock
+}
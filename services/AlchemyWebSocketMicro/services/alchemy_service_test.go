@@ -0,0 +1,384 @@
+package services
+
+import (
+	"AlchemyWebSocketMicro/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	contractA = "0x00000000000000000000000000000000000000aa"
+	contractB = "0x00000000000000000000000000000000000000bb"
+)
+
+// fakeNode es un nodo JSON-RPC por WebSocket: responde eth_subscribe con el
+// ID "sub-<id de la solicitud>" y eth_getTransactionReceipt con los recibos
+// registrados, y permite enviar notificaciones y cortar la conexión
+type fakeNode struct {
+	t      *testing.T
+	server *httptest.Server
+	// requests recibe cada solicitud y conns cada conexión, en orden
+	requests chan models.AlchemyRequest
+	conns    chan *nodeConn
+	paths    chan string
+
+	mu       sync.Mutex
+	receipts map[string]interface{}
+}
+
+// nodeConn serializa las escrituras del nodo y del test en una conexión
+type nodeConn struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+func (c *nodeConn) send(message interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.WriteJSON(message)
+}
+
+func newFakeNode(t *testing.T) *fakeNode {
+	t.Helper()
+	node := &fakeNode{
+		t:        t,
+		requests: make(chan models.AlchemyRequest, 64),
+		conns:    make(chan *nodeConn, 8),
+		paths:    make(chan string, 8),
+		receipts: make(map[string]interface{}),
+	}
+
+	var mu sync.Mutex
+	var open []*websocket.Conn
+	upgrader := websocket.Upgrader{}
+	node.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		mu.Lock()
+		open = append(open, conn)
+		mu.Unlock()
+		nc := &nodeConn{conn: conn}
+		node.paths <- r.URL.Path
+		node.conns <- nc
+		node.serve(nc)
+	}))
+	t.Cleanup(func() {
+		mu.Lock()
+		for _, conn := range open {
+			conn.Close()
+		}
+		mu.Unlock()
+		node.server.Close()
+	})
+	return node
+}
+
+func (n *fakeNode) url() string {
+	return "ws" + strings.TrimPrefix(n.server.URL, "http")
+}
+
+func (n *fakeNode) serve(nc *nodeConn) {
+	for {
+		var request models.AlchemyRequest
+		if err := nc.conn.ReadJSON(&request); err != nil {
+			return
+		}
+		n.requests <- request
+
+		var result interface{}
+		switch request.Method {
+		case "eth_subscribe":
+			result = fmt.Sprintf("sub-%d", request.ID)
+		case "eth_getTransactionReceipt":
+			params, _ := request.Params.([]interface{})
+			if len(params) == 1 {
+				hash, _ := params[0].(string)
+				n.mu.Lock()
+				result = n.receipts[hash]
+				n.mu.Unlock()
+			}
+		}
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": result}
+		if err := nc.send(response); err != nil {
+			return
+		}
+	}
+}
+
+// setReceipt registra el recibo que devuelve eth_getTransactionReceipt
+func (n *fakeNode) setReceipt(txHash string, receipt interface{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.receipts[txHash] = receipt
+}
+
+// nextConn espera la siguiente conexión del servicio
+func (n *fakeNode) nextConn() *nodeConn {
+	n.t.Helper()
+	select {
+	case nc := <-n.conns:
+		return nc
+	case <-time.After(5 * time.Second):
+		n.t.Fatal("Timed out waiting for the service to connect")
+		return nil
+	}
+}
+
+// nextSubscribe espera la siguiente solicitud eth_subscribe y devuelve sus
+// parámetros como JSON y el ID de suscripción que le asignó el nodo
+func (n *fakeNode) nextSubscribe() (string, string) {
+	n.t.Helper()
+	for {
+		select {
+		case request := <-n.requests:
+			if request.Method != "eth_subscribe" {
+				continue
+			}
+			if request.JSONRPC != "2.0" {
+				n.t.Errorf("Expected jsonrpc 2.0, got %q", request.JSONRPC)
+			}
+			params, err := json.Marshal(request.Params)
+			if err != nil {
+				n.t.Fatalf("Failed to encode params: %v", err)
+			}
+			return string(params), fmt.Sprintf("sub-%d", request.ID)
+		case <-time.After(5 * time.Second):
+			n.t.Fatal("Timed out waiting for eth_subscribe")
+			return "", ""
+		}
+	}
+}
+
+// notify envía una notificación eth_subscription al servicio
+func (nc *nodeConn) notify(t *testing.T, subscriptionID string, result interface{}) {
+	t.Helper()
+	err := nc.send(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "eth_subscription",
+		"params":  map[string]interface{}{"subscription": subscriptionID, "result": result},
+	})
+	if err != nil {
+		t.Fatalf("Failed to send notification: %v", err)
+	}
+}
+
+// newClient devuelve los dos extremos de una conexión WebSocket: el del
+// servidor se registra en el servicio y el del cliente lee lo que recibe
+func newClient(t *testing.T) (*websocket.Conn, *websocket.Conn) {
+	t.Helper()
+	serverSide := make(chan *websocket.Conn, 1)
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		serverSide <- conn
+	}))
+	t.Cleanup(server.Close)
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Failed to dial client: %v", err)
+	}
+	conn := <-serverSide
+	t.Cleanup(func() {
+		client.Close()
+		conn.Close()
+	})
+	return conn, client
+}
+
+// readMessage lee el siguiente mensaje que el servicio envió al cliente
+func readMessage(t *testing.T, client *websocket.Conn) models.WebSocketMessage {
+	t.Helper()
+	client.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var message models.WebSocketMessage
+	if err := client.ReadJSON(&message); err != nil {
+		t.Fatalf("Failed to read client message: %v", err)
+	}
+	return message
+}
+
+// waitSubscriptionID espera a que el servicio asigne al contrato el ID de
+// suscripción del nodo
+func waitSubscriptionID(t *testing.T, service *AlchemyService, contractAddress, subscriptionID string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, status := range service.GetActiveSubscriptions() {
+			if status.ContractAddress == contractAddress && status.SubscriptionID == subscriptionID {
+				return
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Subscription %s was never assigned to %s: %+v", subscriptionID, contractAddress, service.GetActiveSubscriptions())
+}
+
+// startService conecta el servicio al nodo y devuelve la conexión y el ID de
+// la suscripción newHeads
+func startService(t *testing.T, node *fakeNode, provider Provider) (*AlchemyService, *nodeConn, string) {
+	t.Helper()
+	service := NewAlchemyService(provider)
+	if err := service.Start(); err != nil {
+		t.Fatalf("Failed to start service: %v", err)
+	}
+	nc := node.nextConn()
+	params, headsID := node.nextSubscribe()
+	if params != `["newHeads"]` {
+		t.Fatalf(`Expected ["newHeads"] as the first subscription, got %s`, params)
+	}
+	return service, nc, headsID
+}
+
+func TestSubscribePayloads(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider func(url string) Provider
+		path     string
+		params   string
+	}{
+		{
+			name:     "standard",
+			provider: func(url string) Provider { return NewStandardProvider(url) },
+			path:     "/",
+			params:   `["logs",{"address":"` + contractA + `"}]`,
+		},
+		{
+			name:     "alchemy",
+			provider: func(url string) Provider { return NewAlchemyProvider(url+"/v2/", "clave") },
+			path:     "/v2/clave",
+			params:   `["alchemy_minedTransactions",{"addresses":[{"to":"` + contractA + `"}],"hashesOnly":false,"includeRemoved":false}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := newFakeNode(t)
+			service, _, _ := startService(t, node, tt.provider(node.url()))
+			if path := <-node.paths; path != tt.path {
+				t.Errorf("Expected to dial %s, got %s", tt.path, path)
+			}
+
+			conn, _ := newClient(t)
+			if err := service.SubscribeToContract(contractA, conn); err != nil {
+				t.Fatalf("Failed to subscribe: %v", err)
+			}
+			params, subscriptionID := node.nextSubscribe()
+			if params != tt.params {
+				t.Errorf("Expected params %s, got %s", tt.params, params)
+			}
+			waitSubscriptionID(t, service, contractA, subscriptionID)
+
+			// Un segundo cliente del mismo contrato no suscribe de nuevo
+			other, _ := newClient(t)
+			if err := service.SubscribeToContract(contractA, other); err != nil {
+				t.Fatalf("Failed to add client: %v", err)
+			}
+			select {
+			case request := <-node.requests:
+				t.Errorf("Expected no new request for an existing subscription, got %+v", request)
+			case <-time.After(100 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestNotificationRouting(t *testing.T) {
+	node := newFakeNode(t)
+	service, nc, headsID := startService(t, node, NewStandardProvider(node.url()))
+
+	connA, clientA := newClient(t)
+	if err := service.SubscribeToContract(contractA, connA); err != nil {
+		t.Fatalf("Failed to subscribe A: %v", err)
+	}
+	_, subA := node.nextSubscribe()
+	connB, clientB := newClient(t)
+	if err := service.SubscribeToContract(contractB, connB); err != nil {
+		t.Fatalf("Failed to subscribe B: %v", err)
+	}
+	_, subB := node.nextSubscribe()
+	waitSubscriptionID(t, service, contractA, subA)
+	waitSubscriptionID(t, service, contractB, subB)
+
+	// La notificación de B solo llega a los clientes de B
+	nc.notify(t, subB, map[string]interface{}{"address": contractB, "data": "0x01"})
+	message := readMessage(t, clientB)
+	if message.Type != MessageTypeLog || message.ContractAddr != contractB {
+		t.Errorf("Expected a log for B, got %+v", message)
+	}
+	if data, _ := message.Data.(map[string]interface{}); data["data"] != "0x01" {
+		t.Errorf("Expected the raw notification, got %v", message.Data)
+	}
+
+	// Una suscripción desconocida no se reenvía a nadie
+	nc.notify(t, "sub-desconocida", map[string]interface{}{"address": contractA})
+
+	// newHeads llega a todos; el primer mensaje de A demuestra que no recibió
+	// ni la notificación de B ni la desconocida
+	nc.notify(t, headsID, map[string]interface{}{"number": "0x2a", "hash": "0xabc"})
+	for name, client := range map[string]*websocket.Conn{"A": clientA, "B": clientB} {
+		message := readMessage(t, client)
+		if message.Type != MessageTypeNewHead {
+			t.Errorf("Expected newHead as the next message for %s, got %+v", name, message)
+		}
+	}
+	if block := service.LastBlock(); block != 42 {
+		t.Errorf("Expected last block 42, got %d", block)
+	}
+
+	nc.notify(t, subA, map[string]interface{}{"address": contractA})
+	if message := readMessage(t, clientA); message.Type != MessageTypeLog || message.ContractAddr != contractA {
+		t.Errorf("Expected a log for A, got %+v", message)
+	}
+}
+
+func TestResubscribeAfterDisconnect(t *testing.T) {
+	node := newFakeNode(t)
+	service, nc, _ := startService(t, node, NewStandardProvider(node.url()))
+
+	conn, client := newClient(t)
+	if err := service.SubscribeToContract(contractA, conn); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	_, oldID := node.nextSubscribe()
+	waitSubscriptionID(t, service, contractA, oldID)
+
+	// El nodo corta la conexión: el servicio reconecta y vuelve a suscribir
+	// newHeads y el contrato
+	nc.conn.Close()
+	nc = node.nextConn()
+	params, headsID := node.nextSubscribe()
+	if params != `["newHeads"]` {
+		t.Fatalf(`Expected ["newHeads"] after reconnecting, got %s`, params)
+	}
+	params, newID := node.nextSubscribe()
+	if want := `["logs",{"address":"` + contractA + `"}]`; params != want {
+		t.Fatalf("Expected %s after reconnecting, got %s", want, params)
+	}
+	waitSubscriptionID(t, service, contractA, newID)
+
+	// El ID de la conexión anterior ya no enruta; el nuevo sí
+	nc.notify(t, oldID, map[string]interface{}{"data": "0x01"})
+	nc.notify(t, newID, map[string]interface{}{"data": "0x02"})
+	message := readMessage(t, client)
+	if data, _ := message.Data.(map[string]interface{}); message.Type != MessageTypeLog || data["data"] != "0x02" {
+		t.Errorf("Expected the notification of the new subscription, got %+v", message)
+	}
+
+	nc.notify(t, headsID, map[string]interface{}{"number": "0x10"})
+	if message := readMessage(t, client); message.Type != MessageTypeNewHead {
+		t.Errorf("Expected newHead from the new connection, got %+v", message)
+	}
+}
@@ -0,0 +1,316 @@
+package services
+
+import (
+	"AlchemyWebSocketMicro/models"
+	"CrearLoteMicro/utils"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Formatos de mensaje que puede elegir cada cliente con el mensaje subscribe
+const (
+	// FormatRaw reenvía la notificación del proveedor sin tocarla (por defecto)
+	FormatRaw = "raw"
+	// FormatDecoded envía los eventos de LoteTracing decodificados
+	FormatDecoded = "decoded"
+	// FormatBoth envía ambos
+	FormatBoth = "both"
+)
+
+// Tipos de los mensajes decodificados, uno por evento de LoteTracing
+const (
+	MessageTypeTemperaturaRegistrada = "temperaturaRegistrada"
+	MessageTypeCustodiaTransferida   = "custodiaTransferida"
+	MessageTypeLoteComprometido      = "loteComprometido"
+)
+
+// Estado del recibo que acompaña a cada mensaje decodificado
+const (
+	ReceiptStatusSuccess = "success"
+	ReceiptStatusFailed  = "failed"
+	// ReceiptStatusPending indica que el nodo aún no devuelve el recibo
+	ReceiptStatusPending = "pending"
+	// ReceiptStatusRemoved indica que la transacción salió de la cadena por
+	// una reorganización
+	ReceiptStatusRemoved = "removed"
+)
+
+// callTimeout es la espera máxima de una llamada JSON-RPC al proveedor
+const callTimeout = 10 * time.Second
+
+// eventMessageTypes asocia cada evento del ABI con su tipo de mensaje; el
+// resto de eventos (roles, pausa) no se envían decodificados
+var eventMessageTypes = map[string]string{
+	"TemperaturaRegistrada": MessageTypeTemperaturaRegistrada,
+	"CustodiaTransferida":   MessageTypeCustodiaTransferida,
+	"LoteComprometido":      MessageTypeLoteComprometido,
+}
+
+// functionMessageTypes asocia cada función con el evento que emite si se
+// ejecuta, para informar las transacciones que fallaron
+var functionMessageTypes = map[string]string{
+	"registrarTemperatura":   MessageTypeTemperaturaRegistrada,
+	"transferirCustodia":     MessageTypeCustodiaTransferida,
+	"aceptarCustodia":        MessageTypeCustodiaTransferida,
+	"aceptarCustodiaFirmada": MessageTypeCustodiaTransferida,
+}
+
+// decodeJob es una notificación de contrato pendiente de decodificar
+type decodeJob struct {
+	sub    *Subscription
+	result json.RawMessage
+}
+
+// decodeNotifications decodifica en orden las notificaciones encoladas y las
+// envía a los clientes que eligieron el formato decoded
+func (a *AlchemyService) decodeNotifications() {
+	for job := range a.decodeQueue {
+		messages, err := a.decodeNotification(job.sub.ContractAddress, job.result)
+		if err != nil {
+			log.Printf("❌ Error decodificando notificación de %s: %v", job.sub.ContractAddress, err)
+			continue
+		}
+		for _, message := range messages {
+			log.Printf("🧩 Evento decodificado %s en %s", message.Type, job.sub.ContractAddress)
+			a.broadcast(job.sub, message, FormatDecoded)
+		}
+	}
+}
+
+// decodeNotification convierte una notificación del proveedor en los mensajes
+// tipados de LoteTracing. Acepta logs (proveedor standard) y transacciones
+// minadas (alchemy_minedTransactions).
+func (a *AlchemyService) decodeNotification(contractAddress string, result json.RawMessage) ([]models.WebSocketMessage, error) {
+	var notification models.ContractNotification
+	if err := json.Unmarshal(result, &notification); err != nil {
+		return nil, fmt.Errorf("error parseando notificación: %v", err)
+	}
+
+	if len(notification.Topics) > 0 {
+		return a.decodeLog(contractAddress, &notification)
+	}
+
+	tx := notification.Transaction
+	if tx == nil {
+		// Algunos proveedores envían la transacción sin envolver
+		tx = &models.MinedTransaction{
+			Hash:        notification.Hash,
+			From:        notification.From,
+			Input:       notification.Input,
+			BlockNumber: notification.BlockNumber,
+		}
+	}
+	if tx.Hash == "" || tx.Input == "" {
+		return nil, fmt.Errorf("notificación sin log ni transacción")
+	}
+	return a.decodeTransaction(contractAddress, tx, notification.Removed)
+}
+
+// decodeLog decodifica el log recibido por eth_subscribe("logs")
+func (a *AlchemyService) decodeLog(contractAddress string, notification *models.ContractNotification) ([]models.WebSocketMessage, error) {
+	event, err := utils.DecodeEventLog(notification.Topics, notification.Data)
+	if err != nil {
+		return nil, err
+	}
+	messageType, ok := eventMessageTypes[event.EventName]
+	if !ok {
+		return nil, nil
+	}
+
+	status := ReceiptStatusRemoved
+	from := ""
+	if !notification.Removed {
+		receipt, err := a.getReceipt(notification.TransactionHash)
+		if err != nil {
+			return nil, err
+		}
+		status = receiptStatus(receipt)
+		if receipt != nil {
+			from = receipt.From
+		}
+	}
+
+	return []models.WebSocketMessage{decodedMessage(contractAddress, messageType, models.DecodedEventData{
+		Event:       event.EventName,
+		Status:      status,
+		TxHash:      notification.TransactionHash,
+		BlockNumber: parseBlockNumber(notification.BlockNumber),
+		LogIndex:    parseLogIndex(notification.LogIndex),
+		From:        from,
+		Params:      event.Parameters,
+	})}, nil
+}
+
+// decodeTransaction decodifica la llamada al contrato y los eventos de su
+// recibo. Si la transacción falló no hay eventos: se informa el que habría
+// emitido la función con los parámetros de la llamada.
+func (a *AlchemyService) decodeTransaction(contractAddress string, tx *models.MinedTransaction, removed bool) ([]models.WebSocketMessage, error) {
+	call, err := utils.DecodeInputData(tx.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	var receipt *models.TransactionReceipt
+	if !removed {
+		if receipt, err = a.getReceipt(tx.Hash); err != nil {
+			return nil, err
+		}
+	}
+	status := receiptStatus(receipt)
+	if removed {
+		status = ReceiptStatusRemoved
+	}
+
+	var messages []models.WebSocketMessage
+	if status == ReceiptStatusSuccess {
+		for _, receiptLog := range receipt.Logs {
+			if !strings.EqualFold(receiptLog.Address, contractAddress) {
+				continue
+			}
+			event, err := utils.DecodeEventLog(receiptLog.Topics, receiptLog.Data)
+			if err != nil {
+				log.Printf("⚠️ Log no decodificable en %s: %v", tx.Hash, err)
+				continue
+			}
+			messageType, ok := eventMessageTypes[event.EventName]
+			if !ok {
+				continue
+			}
+			messages = append(messages, decodedMessage(contractAddress, messageType, models.DecodedEventData{
+				Event:       event.EventName,
+				Status:      status,
+				TxHash:      tx.Hash,
+				BlockNumber: parseBlockNumber(receipt.BlockNumber),
+				LogIndex:    parseLogIndex(receiptLog.LogIndex),
+				From:        tx.From,
+				Params:      event.Parameters,
+				Function:    call.FunctionName,
+				CallParams:  call.Parameters,
+			}))
+		}
+		return messages, nil
+	}
+
+	messageType, ok := functionMessageTypes[call.FunctionName]
+	if !ok {
+		return nil, nil
+	}
+	return []models.WebSocketMessage{decodedMessage(contractAddress, messageType, models.DecodedEventData{
+		Status:      status,
+		TxHash:      tx.Hash,
+		BlockNumber: parseBlockNumber(tx.BlockNumber),
+		From:        tx.From,
+		Function:    call.FunctionName,
+		CallParams:  call.Parameters,
+	})}, nil
+}
+
+// getReceipt consulta el recibo de la transacción; nil si aún no existe
+func (a *AlchemyService) getReceipt(txHash string) (*models.TransactionReceipt, error) {
+	result, err := a.call("eth_getTransactionReceipt", []interface{}{txHash})
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo recibo de %s: %v", txHash, err)
+	}
+	if len(result) == 0 || string(result) == "null" {
+		return nil, nil
+	}
+
+	var receipt models.TransactionReceipt
+	if err := json.Unmarshal(result, &receipt); err != nil {
+		return nil, fmt.Errorf("error parseando recibo de %s: %v", txHash, err)
+	}
+	return &receipt, nil
+}
+
+// call envía una solicitud JSON-RPC y espera su respuesta, que entrega
+// handleAlchemyMessage
+func (a *AlchemyService) call(method string, params []interface{}) (json.RawMessage, error) {
+	request := models.AlchemyRequest{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+		ID:      int(a.requestID.Add(1)),
+	}
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("error serializando request: %v", err)
+	}
+
+	response := make(chan models.AlchemyResponse, 1)
+	a.pendingMu.Lock()
+	a.calls[request.ID] = response
+	a.pendingMu.Unlock()
+	defer func() {
+		a.pendingMu.Lock()
+		delete(a.calls, request.ID)
+		a.pendingMu.Unlock()
+	}()
+
+	a.writeMu.Lock()
+	if a.conn == nil {
+		a.writeMu.Unlock()
+		return nil, fmt.Errorf("conexión WebSocket no disponible")
+	}
+	err = a.conn.WriteMessage(websocket.TextMessage, requestBytes)
+	a.writeMu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("error enviando mensaje a %s: %v", a.provider.Name(), err)
+	}
+
+	select {
+	case resp := <-response:
+		if resp.Error != nil {
+			return nil, fmt.Errorf("%s: %s", method, resp.Error.Message)
+		}
+		return resp.Result, nil
+	case <-time.After(callTimeout):
+		return nil, fmt.Errorf("%s sin respuesta tras %s", method, callTimeout)
+	}
+}
+
+// receiptStatus traduce el status del recibo; sin recibo está pendiente
+func receiptStatus(receipt *models.TransactionReceipt) string {
+	if receipt == nil {
+		return ReceiptStatusPending
+	}
+	if receipt.Status == "0x1" {
+		return ReceiptStatusSuccess
+	}
+	return ReceiptStatusFailed
+}
+
+func decodedMessage(contractAddress, messageType string, data models.DecodedEventData) models.WebSocketMessage {
+	return models.WebSocketMessage{
+		Type:         messageType,
+		ContractAddr: contractAddress,
+		Data:         data,
+		Timestamp:    time.Now().Unix(),
+	}
+}
+
+func parseBlockNumber(value string) uint64 {
+	number, _ := hexToUint64(value)
+	return number
+}
+
+func parseLogIndex(value string) *uint64 {
+	index, err := hexToUint64(value)
+	if err != nil {
+		return nil
+	}
+	return &index
+}
+
+// hexToUint64 convierte una cantidad JSON-RPC ("0x1a") a número
+func hexToUint64(value string) (uint64, error) {
+	if !strings.HasPrefix(value, "0x") {
+		return 0, fmt.Errorf("cantidad sin prefijo 0x: %q", value)
+	}
+	return strconv.ParseUint(strings.TrimPrefix(value, "0x"), 16, 64)
+}
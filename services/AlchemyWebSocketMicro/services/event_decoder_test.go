@@ -0,0 +1,401 @@
+package services
+
+import (
+	"AlchemyWebSocketMicro/models"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// loteTracingFixture son los logs y transacciones reales de LoteTracing que
+// comparte el decodificador de CrearLoteMicro (ver utils/decoder_test.go)
+type loteTracingFixture struct {
+	Contract     string                    `json:"contract"`
+	Logs         []json.RawMessage         `json:"logs"`
+	Transactions []models.MinedTransaction `json:"transactions"`
+}
+
+const fixtureFabricante = "0x57C863d904EAdFb6F56eb7676738CD13c62B4fC0"
+
+func loadFixture(t *testing.T) *loteTracingFixture {
+	t.Helper()
+	content, err := os.ReadFile("../../CrearLoteMicro/utils/testdata/lote_tracing.json")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	var fixture loteTracingFixture
+	if err := json.Unmarshal(content, &fixture); err != nil {
+		t.Fatalf("Failed to parse fixture: %v", err)
+	}
+	return &fixture
+}
+
+// receiptLogs devuelve los logs de la transacción como los trae su recibo
+func (f *loteTracingFixture) receiptLogs(t *testing.T, txHash string) []models.ReceiptLog {
+	t.Helper()
+	var logs []models.ReceiptLog
+	for _, raw := range f.Logs {
+		var notification models.ContractNotification
+		if err := json.Unmarshal(raw, &notification); err != nil {
+			t.Fatalf("Failed to parse log: %v", err)
+		}
+		if notification.TransactionHash == txHash {
+			logs = append(logs, models.ReceiptLog{
+				Address:  notification.Address,
+				Topics:   notification.Topics,
+				Data:     notification.Data,
+				LogIndex: notification.LogIndex,
+			})
+		}
+	}
+	return logs
+}
+
+// transaction devuelve la transacción del fixture que contiene el log
+func (f *loteTracingFixture) transaction(t *testing.T, log int) models.MinedTransaction {
+	t.Helper()
+	var notification models.ContractNotification
+	if err := json.Unmarshal(f.Logs[log], &notification); err != nil {
+		t.Fatalf("Failed to parse log: %v", err)
+	}
+	for _, tx := range f.Transactions {
+		if tx.Hash == notification.TransactionHash {
+			return tx
+		}
+	}
+	t.Fatalf("No transaction for log %d", log)
+	return models.MinedTransaction{}
+}
+
+// withLog modifica un log del fixture sin tocar el original
+func withLog(t *testing.T, raw json.RawMessage, change func(fields map[string]interface{})) json.RawMessage {
+	t.Helper()
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		t.Fatalf("Failed to parse log: %v", err)
+	}
+	change(fields)
+	changed, _ := json.Marshal(fields)
+	return changed
+}
+
+func uint64Ptr(value uint64) *uint64 { return &value }
+
+func TestDecodeNotification_Logs(t *testing.T) {
+	fixture := loadFixture(t)
+	node := newFakeNode(t)
+	service, _, _ := startService(t, node, NewStandardProvider(node.url()))
+
+	for _, tx := range fixture.Transactions {
+		node.setReceipt(tx.Hash, models.TransactionReceipt{Status: "0x1", From: tx.From, BlockNumber: tx.BlockNumber})
+	}
+	pendiente := withLog(t, fixture.Logs[6], func(fields map[string]interface{}) {
+		fields["transactionHash"] = "0x" + strings.Repeat("ab", 32)
+	})
+
+	tests := []struct {
+		name        string
+		result      json.RawMessage
+		messageType string
+		data        *models.DecodedEventData
+		err         string
+	}{
+		{
+			name:        "TemperaturaRegistrada",
+			result:      fixture.Logs[6],
+			messageType: MessageTypeTemperaturaRegistrada,
+			data: &models.DecodedEventData{
+				Event:       "TemperaturaRegistrada",
+				Status:      ReceiptStatusSuccess,
+				TxHash:      fixture.transaction(t, 6).Hash,
+				BlockNumber: 5,
+				LogIndex:    uint64Ptr(0),
+				From:        fixtureFabricante,
+				Params: map[string]interface{}{
+					"oraculo":   fixtureFabricante,
+					"sensorId":  "SENSOR-01",
+					"tempMin":   int8(3),
+					"tempMax":   int8(7),
+					"enRango":   true,
+					"timestamp": "50",
+				},
+			},
+		},
+		{
+			name:        "LoteComprometido",
+			result:      fixture.Logs[14],
+			messageType: MessageTypeLoteComprometido,
+			data: &models.DecodedEventData{
+				Event:       "LoteComprometido",
+				Status:      ReceiptStatusSuccess,
+				TxHash:      fixture.transaction(t, 14).Hash,
+				BlockNumber: 12,
+				LogIndex:    uint64Ptr(1),
+				From:        fixtureFabricante,
+				Params: map[string]interface{}{
+					"propietario":  fixtureFabricante,
+					"tempMin":      int8(1),
+					"tempMax":      int8(12),
+					"comprometido": true,
+					"motivo":       "Temperatura fuera de rango",
+				},
+			},
+		},
+		{
+			name:        "CustodiaTransferida",
+			result:      fixture.Logs[17],
+			messageType: MessageTypeCustodiaTransferida,
+			data: &models.DecodedEventData{
+				Event:       "CustodiaTransferida",
+				Status:      ReceiptStatusSuccess,
+				TxHash:      fixture.transaction(t, 17).Hash,
+				BlockNumber: 14,
+				LogIndex:    uint64Ptr(1),
+				From:        "0x93F043862503ACd5FD3C00a8Ad12fDD60Df7AA4c",
+				Params: map[string]interface{}{
+					"propietarioAnterior": fixtureFabricante,
+					"nuevoPropietario":    "0x93F043862503ACd5FD3C00a8Ad12fDD60Df7AA4c",
+					"comprometido":        true,
+					"motivo":              "Custodia Aceptada",
+				},
+			},
+		},
+		{
+			// El loteId indexado llega como hash; LoteCreado no se reenvía
+			name:   "LoteCreado is not forwarded",
+			result: fixture.Logs[2],
+		},
+		{
+			name:   "RolOtorgado is not forwarded",
+			result: fixture.Logs[1],
+		},
+		{
+			name:   "CustodiaAceptada is not forwarded",
+			result: fixture.Logs[16],
+		},
+		{
+			name: "removed by a reorg",
+			result: withLog(t, fixture.Logs[14], func(fields map[string]interface{}) {
+				fields["removed"] = true
+			}),
+			messageType: MessageTypeLoteComprometido,
+			data: &models.DecodedEventData{
+				Event:       "LoteComprometido",
+				Status:      ReceiptStatusRemoved,
+				TxHash:      fixture.transaction(t, 14).Hash,
+				BlockNumber: 12,
+				LogIndex:    uint64Ptr(1),
+				Params: map[string]interface{}{
+					"propietario":  fixtureFabricante,
+					"tempMin":      int8(1),
+					"tempMax":      int8(12),
+					"comprometido": true,
+					"motivo":       "Temperatura fuera de rango",
+				},
+			},
+		},
+		{
+			name:        "receipt not available yet",
+			result:      pendiente,
+			messageType: MessageTypeTemperaturaRegistrada,
+			data: &models.DecodedEventData{
+				Event:       "TemperaturaRegistrada",
+				Status:      ReceiptStatusPending,
+				TxHash:      "0x" + strings.Repeat("ab", 32),
+				BlockNumber: 5,
+				LogIndex:    uint64Ptr(0),
+				Params: map[string]interface{}{
+					"oraculo":   fixtureFabricante,
+					"sensorId":  "SENSOR-01",
+					"tempMin":   int8(3),
+					"tempMax":   int8(7),
+					"enRango":   true,
+					"timestamp": "50",
+				},
+			},
+		},
+		{
+			name: "unknown topic0",
+			result: withLog(t, fixture.Logs[6], func(fields map[string]interface{}) {
+				fields["topics"] = []string{"0x" + strings.Repeat("00", 32)}
+			}),
+			err: "evento desconocido",
+		},
+		{
+			name: "malformed data",
+			result: withLog(t, fixture.Logs[6], func(fields map[string]interface{}) {
+				fields["data"] = "0x1234"
+			}),
+			err: "error desempaquetando data",
+		},
+		{
+			name:   "neither log nor transaction",
+			result: json.RawMessage(`{"blockNumber":"0x1"}`),
+			err:    "notificación sin log ni transacción",
+		},
+		{
+			name:   "not an object",
+			result: json.RawMessage(`"0x1"`),
+			err:    "error parseando notificación",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages, err := service.decodeNotification(fixture.Contract, tt.result)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Expected error %q, got %v (%+v)", tt.err, err, messages)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected notification to decode, got %v", err)
+			}
+			if tt.data == nil {
+				if len(messages) != 0 {
+					t.Errorf("Expected no messages, got %+v", messages)
+				}
+				return
+			}
+			if len(messages) != 1 {
+				t.Fatalf("Expected one message, got %+v", messages)
+			}
+			if messages[0].Type != tt.messageType || messages[0].ContractAddr != fixture.Contract {
+				t.Errorf("Expected %s for %s, got %s for %s", tt.messageType, fixture.Contract, messages[0].Type, messages[0].ContractAddr)
+			}
+			if data, _ := messages[0].Data.(models.DecodedEventData); !reflect.DeepEqual(data, *tt.data) {
+				t.Errorf("Unexpected data\n got: %#v\nwant: %#v", messages[0].Data, *tt.data)
+			}
+		})
+	}
+}
+
+func TestDecodeNotification_MinedTransactions(t *testing.T) {
+	fixture := loadFixture(t)
+	node := newFakeNode(t)
+	service, _, _ := startService(t, node, NewAlchemyProvider(node.url(), "clave"))
+
+	lectura := fixture.transaction(t, 13)
+	aceptacion := fixture.transaction(t, 17)
+	node.setReceipt(lectura.Hash, models.TransactionReceipt{Status: "0x1", BlockNumber: lectura.BlockNumber, Logs: fixture.receiptLogs(t, lectura.Hash)})
+	node.setReceipt(aceptacion.Hash, models.TransactionReceipt{Status: "0x1", BlockNumber: aceptacion.BlockNumber, Logs: fixture.receiptLogs(t, aceptacion.Hash)})
+	fallida := lectura
+	fallida.Hash = "0x" + strings.Repeat("cd", 32)
+	node.setReceipt(fallida.Hash, models.TransactionReceipt{Status: "0x0", BlockNumber: fallida.BlockNumber})
+
+	wrap := func(tx models.MinedTransaction) json.RawMessage {
+		result, _ := json.Marshal(map[string]interface{}{"removed": false, "transaction": tx})
+		return result
+	}
+	llamadaLectura := map[string]interface{}{"_tempMin": int8(1), "_tempMax": int8(12), "_sensorId": "SENSOR-02"}
+
+	tests := []struct {
+		name   string
+		result json.RawMessage
+		types  []string
+		check  func(t *testing.T, data []models.DecodedEventData)
+		err    string
+	}{
+		{
+			// Una lectura fuera de rango emite TemperaturaRegistrada y LoteComprometido
+			name:   "registrarTemperatura",
+			result: wrap(lectura),
+			types:  []string{MessageTypeTemperaturaRegistrada, MessageTypeLoteComprometido},
+			check: func(t *testing.T, data []models.DecodedEventData) {
+				for i, event := range []string{"TemperaturaRegistrada", "LoteComprometido"} {
+					if data[i].Event != event || data[i].Status != ReceiptStatusSuccess || data[i].Function != "registrarTemperatura" ||
+						*data[i].LogIndex != uint64(i) || data[i].From != fixtureFabricante || !reflect.DeepEqual(data[i].CallParams, llamadaLectura) {
+						t.Errorf("Unexpected %s message %+v", event, data[i])
+					}
+				}
+				if data[1].Params["comprometido"] != true {
+					t.Errorf("Expected the lote to be compromised, got %v", data[1].Params)
+				}
+			},
+		},
+		{
+			// CustodiaAceptada no se reenvía; solo CustodiaTransferida
+			name:   "aceptarCustodia",
+			result: wrap(aceptacion),
+			types:  []string{MessageTypeCustodiaTransferida},
+			check: func(t *testing.T, data []models.DecodedEventData) {
+				if data[0].Function != "aceptarCustodia" || data[0].Params["nuevoPropietario"] != aceptacion.From || data[0].BlockNumber != 14 {
+					t.Errorf("Unexpected transfer message %+v", data[0])
+				}
+			},
+		},
+		{
+			// Sin envolver en transaction, como lo envían algunos proveedores
+			name:   "unwrapped transaction",
+			result: json.RawMessage(`{"hash":"` + lectura.Hash + `","from":"` + lectura.From + `","input":"` + lectura.Input + `","blockNumber":"` + lectura.BlockNumber + `"}`),
+			types:  []string{MessageTypeTemperaturaRegistrada, MessageTypeLoteComprometido},
+		},
+		{
+			// Una transacción fallida no emite eventos: se informa la llamada
+			name:   "failed transaction",
+			result: wrap(fallida),
+			types:  []string{MessageTypeTemperaturaRegistrada},
+			check: func(t *testing.T, data []models.DecodedEventData) {
+				if data[0].Event != "" || data[0].Status != ReceiptStatusFailed || data[0].Function != "registrarTemperatura" ||
+					data[0].LogIndex != nil || !reflect.DeepEqual(data[0].CallParams, llamadaLectura) {
+					t.Errorf("Unexpected failed message %+v", data[0])
+				}
+			},
+		},
+		{
+			name: "removed by a reorg",
+			result: func() json.RawMessage {
+				result, _ := json.Marshal(map[string]interface{}{"removed": true, "transaction": lectura})
+				return result
+			}(),
+			types: []string{MessageTypeTemperaturaRegistrada},
+			check: func(t *testing.T, data []models.DecodedEventData) {
+				if data[0].Status != ReceiptStatusRemoved || data[0].Event != "" {
+					t.Errorf("Unexpected removed message %+v", data[0])
+				}
+			},
+		},
+		{
+			name:   "malformed input",
+			result: wrap(models.MinedTransaction{Hash: lectura.Hash, Input: "0x1234"}),
+			err:    "input data demasiado corto",
+		},
+		{
+			name:   "malformed call parameters",
+			result: wrap(models.MinedTransaction{Hash: lectura.Hash, Input: lectura.Input[:10] + "00"}),
+			err:    "error desempaquetando parámetros",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages, err := service.decodeNotification(fixture.Contract, tt.result)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Expected error %q, got %v (%+v)", tt.err, err, messages)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected notification to decode, got %v", err)
+			}
+
+			types := make([]string, 0, len(messages))
+			data := make([]models.DecodedEventData, 0, len(messages))
+			for _, message := range messages {
+				types = append(types, message.Type)
+				decoded, _ := message.Data.(models.DecodedEventData)
+				data = append(data, decoded)
+			}
+			if !reflect.DeepEqual(types, tt.types) {
+				t.Fatalf("Expected messages %v, got %v", tt.types, types)
+			}
+			if tt.check != nil {
+				tt.check(t, data)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"AlchemyWebSocketMicro/models"
+	"fmt"
+	"strings"
+)
+
+// Tipos de mensaje con los que se reenvían las notificaciones a los clientes
+const (
+	MessageTypeTransaction = "transaction"
+	MessageTypeLog         = "log"
+	MessageTypeNewHead     = "newHead"
+)
+
+// Provider es un endpoint WebSocket JSON-RPC de Ethereum. Define a qué URL
+// conectarse y con qué suscripción se sigue la actividad de un contrato; el
+// resto del protocolo (eth_subscribe, eth_subscription, newHeads) es común a
+// todos los nodos.
+type Provider interface {
+	// Name identifica al proveedor en los logs y en el estado del monitoreo
+	Name() string
+	// URL es el endpoint WebSocket al que se conecta el servicio
+	URL() string
+	// ContractSubscription devuelve los parámetros de eth_subscribe que
+	// siguen la actividad del contrato
+	ContractSubscription(contractAddress string) []interface{}
+	// MessageType es el tipo de mensaje de las notificaciones del contrato
+	MessageType() string
+}
+
+// AlchemyProvider usa la suscripción alchemy_minedTransactions, que entrega
+// la transacción completa (con su input) de cada llamada minada al contrato
+type AlchemyProvider struct {
+	wsURL  string
+	apiKey string
+}
+
+// NewAlchemyProvider crea el proveedor con la URL base de Alchemy y la API key
+func NewAlchemyProvider(wsURL, apiKey string) *AlchemyProvider {
+	return &AlchemyProvider{wsURL: strings.TrimSuffix(wsURL, "/"), apiKey: apiKey}
+}
+
+func (p *AlchemyProvider) Name() string { return "alchemy" }
+
+func (p *AlchemyProvider) URL() string { return fmt.Sprintf("%s/%s", p.wsURL, p.apiKey) }
+
+func (p *AlchemyProvider) ContractSubscription(contractAddress string) []interface{} {
+	return []interface{}{
+		"alchemy_minedTransactions",
+		models.SubscriptionParams{
+			Addresses: []models.AddressFilter{
+				{To: contractAddress},
+			},
+			IncludeRemoved: false,
+			HashesOnly:     false,
+		},
+	}
+}
+
+func (p *AlchemyProvider) MessageType() string { return MessageTypeTransaction }
+
+// StandardProvider usa la suscripción estándar eth_subscribe("logs"), que
+// funciona con cualquier nodo o proveedor con WebSocket: Infura, un nodo
+// propio, anvil o geth --dev. Entrega los eventos que emite el contrato.
+type StandardProvider struct {
+	wsURL string
+}
+
+// NewStandardProvider crea el proveedor con la URL WebSocket completa del nodo
+func NewStandardProvider(wsURL string) *StandardProvider {
+	return &StandardProvider{wsURL: wsURL}
+}
+
+func (p *StandardProvider) Name() string { return "standard" }
+
+func (p *StandardProvider) URL() string { return p.wsURL }
+
+func (p *StandardProvider) ContractSubscription(contractAddress string) []interface{} {
+	return []interface{}{
+		"logs",
+		models.LogFilter{Address: contractAddress},
+	}
+}
+
+func (p *StandardProvider) MessageType() string { return MessageTypeLog }
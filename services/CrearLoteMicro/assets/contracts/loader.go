@@ -14,6 +14,12 @@ var loteTracingABI string
 //go:embed LoteTracing.bytecode
 var loteTracingBytecode string
 
+//go:embed LoteTracingFactory.abi.json
+var loteTracingFactoryABI string
+
+//go:embed LoteTracingFactory.bytecode
+var loteTracingFactoryBytecode string
+
 //go:embed contract_info.json
 var contractInfoJSON string
 
@@ -41,6 +47,16 @@ func GetLoteTracingBytecode() string {
 	return strings.TrimSpace(loteTracingBytecode)
 }
 
+// GetLoteTracingFactoryABI retorna el ABI del contrato LoteTracingFactory
+func GetLoteTracingFactoryABI() string {
+	return strings.TrimSpace(loteTracingFactoryABI)
+}
+
+// GetLoteTracingFactoryBytecode retorna el bytecode del contrato LoteTracingFactory
+func GetLoteTracingFactoryBytecode() string {
+	return strings.TrimSpace(loteTracingFactoryBytecode)
+}
+
 // GetContractInfo retorna la información del contrato
 func GetContractInfo() (*ContractInfo, error) {
 	var info ContractInfo
@@ -64,6 +80,10 @@ func ValidateContract() error {
 	if !strings.HasPrefix(strings.TrimSpace(loteTracingBytecode), "0x") {
 		return fmt.Errorf("Bytecode del contrato tiene formato inválido")
 	}
+
+	if !strings.HasPrefix(strings.TrimSpace(loteTracingFactoryBytecode), "0x") {
+		return fmt.Errorf("Bytecode del contrato LoteTracingFactory tiene formato inválido")
+	}
 	
 	_, err := GetContractInfo()
 	if err != nil {
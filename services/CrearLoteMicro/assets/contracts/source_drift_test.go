@@ -0,0 +1,302 @@
+package contracts
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// Directorio de las fuentes a partir de las que se generan los assets con Hardhat
+const contractsDir = "../../../../smartcontract/lotetracing/contracts"
+
+// contrato relaciona una fuente Solidity con sus assets embebidos
+type contrato struct {
+	nombre   string
+	abi      string
+	bytecode string
+}
+
+func (c contrato) source() string {
+	return filepath.Join(contractsDir, c.nombre+".sol")
+}
+
+var contratos = []contrato{
+	{nombre: "LoteTracing", abi: loteTracingABI, bytecode: loteTracingBytecode},
+	{nombre: "LoteTracingFactory", abi: loteTracingFactoryABI, bytecode: loteTracingFactoryBytecode},
+}
+
+// Versión y ajustes de compilación de hardhat.config.ts
+const (
+	solcVersion   = "0.8.28"
+	optimizerRuns = "200"
+	evmVersion    = "london"
+)
+
+var (
+	commentRe     = regexp.MustCompile(`(?s)/\*.*?\*/|//[^\n]*`)
+	eventRe       = regexp.MustCompile(`(?s)\bevent\s+(\w+)\s*\(([^)]*)\)\s*;`)
+	functionRe    = regexp.MustCompile(`(?s)\bfunction\s+(\w+)\s*\(([^)]*)\)([^{;]*)`)
+	constructorRe = regexp.MustCompile(`(?s)\bconstructor\s*\(([^)]*)\)([^{]*)`)
+	returnsRe     = regexp.MustCompile(`(?s)\breturns\s*\(([^)]*)\)`)
+	stateVarRe    = regexp.MustCompile(`(?m)^\s*(\w+)\s+public\s+(?:immutable\s+|constant\s+)?(\w+)\s*[;=]`)
+	mappingVarRe  = regexp.MustCompile(`(?m)^\s*mapping\s*\(\s*(\w+)\s*=>\s*(\w+)\s*\)\s+public\s+(\w+)\s*;`)
+	literalRe     = regexp.MustCompile(`"([^"\\]*)"`)
+	keccakRe      = regexp.MustCompile(`\bkeccak256\s*\(\s*"[^"\\]*"\s*\)`)
+	metadataRe    = regexp.MustCompile(`a264697066735822[0-9a-f]{68}64736f6c6343[0-9a-f]{6}0033`)
+)
+
+// firma resume lo que la ABI expone de un evento, función o constructor
+type firma struct {
+	inputs          []string
+	outputs         []string
+	stateMutability string
+}
+
+func (f firma) String() string {
+	return fmt.Sprintf("(%s) -> (%s) %s", strings.Join(f.inputs, ", "), strings.Join(f.outputs, ", "), f.stateMutability)
+}
+
+func readSource(t *testing.T, c contrato) string {
+	t.Helper()
+	content, err := os.ReadFile(c.source())
+	if err != nil {
+		t.Skipf("%s.sol not available: %v", c.nombre, err)
+	}
+	return commentRe.ReplaceAllString(string(content), "")
+}
+
+// parseParams convierte "string memory _loteId, int8 _tempMin" en sus tipos,
+// marcando los parámetros indexados de los eventos
+func parseParams(params string) []string {
+	var types []string
+	for _, param := range strings.Split(params, ",") {
+		fields := strings.Fields(param)
+		if len(fields) == 0 {
+			continue
+		}
+		typ := canonicalType(fields[0])
+		for _, field := range fields[1:] {
+			if field == "indexed" {
+				typ += " indexed"
+			}
+		}
+		types = append(types, typ)
+	}
+	return types
+}
+
+func canonicalType(typ string) string {
+	switch typ {
+	case "uint":
+		return "uint256"
+	case "int":
+		return "int256"
+	case "address payable":
+		return "address"
+	}
+	return typ
+}
+
+func mutability(modifiers string) string {
+	for _, m := range []string{"view", "pure", "payable"} {
+		if regexp.MustCompile(`\b` + m + `\b`).MatchString(modifiers) {
+			return m
+		}
+	}
+	return "nonpayable"
+}
+
+func sourceSignatures(source string) (events, functions map[string]firma, constructor firma) {
+	events = make(map[string]firma)
+	for _, m := range eventRe.FindAllStringSubmatch(source, -1) {
+		events[m[1]] = firma{inputs: parseParams(m[2])}
+	}
+
+	functions = make(map[string]firma)
+	for _, m := range stateVarRe.FindAllStringSubmatch(source, -1) {
+		functions[m[2]] = firma{outputs: []string{canonicalType(m[1])}, stateMutability: "view"}
+	}
+	for _, m := range mappingVarRe.FindAllStringSubmatch(source, -1) {
+		functions[m[3]] = firma{inputs: []string{canonicalType(m[1])}, outputs: []string{canonicalType(m[2])}, stateMutability: "view"}
+	}
+	for _, m := range functionRe.FindAllStringSubmatch(source, -1) {
+		modifiers := m[3]
+		if !regexp.MustCompile(`\b(external|public)\b`).MatchString(modifiers) {
+			continue
+		}
+		f := firma{inputs: parseParams(m[2]), stateMutability: mutability(modifiers)}
+		if r := returnsRe.FindStringSubmatch(modifiers); r != nil {
+			f.outputs = parseParams(r[1])
+		}
+		functions[m[1]] = f
+	}
+
+	if m := constructorRe.FindStringSubmatch(source); m != nil {
+		constructor = firma{inputs: parseParams(m[1]), stateMutability: mutability(m[2])}
+	}
+	return events, functions, constructor
+}
+
+func argumentTypes(args abi.Arguments, events bool) []string {
+	var types []string
+	for _, arg := range args {
+		typ := arg.Type.String()
+		if events && arg.Indexed {
+			typ += " indexed"
+		}
+		types = append(types, typ)
+	}
+	return types
+}
+
+func abiSignatures(t *testing.T, c contrato) (events, functions map[string]firma, constructor firma) {
+	t.Helper()
+	parsed, err := abi.JSON(strings.NewReader(c.abi))
+	if err != nil {
+		t.Fatalf("Failed to parse ABI asset: %v", err)
+	}
+
+	events = make(map[string]firma)
+	for name, event := range parsed.Events {
+		events[name] = firma{inputs: argumentTypes(event.Inputs, true)}
+	}
+	functions = make(map[string]firma)
+	for name, method := range parsed.Methods {
+		functions[name] = firma{
+			inputs:          argumentTypes(method.Inputs, false),
+			outputs:         argumentTypes(method.Outputs, false),
+			stateMutability: method.StateMutability,
+		}
+	}
+	constructor = firma{inputs: argumentTypes(parsed.Constructor.Inputs, false), stateMutability: parsed.Constructor.StateMutability}
+	return events, functions, constructor
+}
+
+func compareSignatures(t *testing.T, c contrato, kind string, source, asset map[string]firma) {
+	t.Helper()
+	names := make(map[string]bool)
+	for name := range source {
+		names[name] = true
+	}
+	for name := range asset {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		src, inSource := source[name]
+		got, inAsset := asset[name]
+		switch {
+		case !inAsset:
+			t.Errorf("%s %s is declared in %s.sol but missing from the ABI asset", kind, name, c.nombre)
+		case !inSource:
+			t.Errorf("%s %s is in the ABI asset but no longer declared in %s.sol", kind, name, c.nombre)
+		case src.String() != got.String():
+			t.Errorf("%s %s differs: source %s, ABI asset %s", kind, name, src, got)
+		}
+	}
+}
+
+// TestABIMatchesSource falla si el ABI de un contrato no corresponde a su
+// fuente; se corrige con make update-contract-assets
+func TestABIMatchesSource(t *testing.T) {
+	for _, c := range contratos {
+		t.Run(c.nombre, func(t *testing.T) {
+			source := readSource(t, c)
+			srcEvents, srcFunctions, srcConstructor := sourceSignatures(source)
+			abiEvents, abiFunctions, abiConstructor := abiSignatures(t, c)
+
+			compareSignatures(t, c, "event", srcEvents, abiEvents)
+			compareSignatures(t, c, "function", srcFunctions, abiFunctions)
+			compareSignatures(t, c, "constructor", map[string]firma{"": srcConstructor}, map[string]firma{"": abiConstructor})
+		})
+	}
+}
+
+// TestBytecodeContainsSourceStrings comprueba que los mensajes de require y
+// los motivos de los eventos de la fuente están en el bytecode. Solc guarda
+// los literales largos en fragmentos de 32 bytes y el optimizador puede
+// desplazar el último, así que de esos solo se buscan los fragmentos completos.
+// Los argumentos de keccak256 se calculan al compilar y no llegan al bytecode.
+func TestBytecodeContainsSourceStrings(t *testing.T) {
+	for _, c := range contratos {
+		t.Run(c.nombre, func(t *testing.T) {
+			source := keccakRe.ReplaceAllString(readSource(t, c), "")
+			bytecode := strings.ToLower(strings.TrimSpace(c.bytecode))
+
+			for _, m := range literalRe.FindAllStringSubmatch(source, -1) {
+				literal := []byte(m[1])
+				chunks := [][]byte{literal}
+				if len(literal) > 32 {
+					chunks = nil
+					for start := 0; start+32 <= len(literal); start += 32 {
+						chunks = append(chunks, literal[start:start+32])
+					}
+				}
+				for _, chunk := range chunks {
+					if !strings.Contains(bytecode, hex.EncodeToString(chunk)) {
+						t.Errorf("String %q from %s.sol is not in the bytecode asset", m[1], c.nombre)
+						break
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestBytecodeMatchesSolc recompila las fuentes con los ajustes de Hardhat y
+// compara el bytecode sin los metadatos. Solo se ejecuta si solc 0.8.28 está
+// disponible en el PATH o en la variable SOLC.
+func TestBytecodeMatchesSolc(t *testing.T) {
+	solc := os.Getenv("SOLC")
+	if solc == "" {
+		path, err := exec.LookPath("solc")
+		if err != nil {
+			t.Skip("solc not available; set SOLC to enable the bytecode comparison")
+		}
+		solc = path
+	}
+
+	version, err := exec.Command(solc, "--version").Output()
+	if err != nil {
+		t.Fatalf("Failed to run %s: %v", solc, err)
+	}
+	if !strings.Contains(string(version), solcVersion) {
+		t.Skipf("solc %s required for the bytecode comparison, found %s", solcVersion, strings.TrimSpace(string(version)))
+	}
+
+	for _, c := range contratos {
+		t.Run(c.nombre, func(t *testing.T) {
+			readSource(t, c)
+
+			out, err := exec.Command(solc,
+				"--optimize", "--optimize-runs", optimizerRuns,
+				"--evm-version", evmVersion,
+				"--bin", filepath.Clean(c.source()),
+			).Output()
+			if err != nil {
+				t.Fatalf("Failed to compile %s.sol: %v", c.nombre, err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+			compiled := strings.TrimSpace(lines[len(lines)-1])
+			asset := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(c.bytecode)), "0x")
+
+			if metadataRe.ReplaceAllString(compiled, "") != metadataRe.ReplaceAllString(asset, "") {
+				t.Errorf("Bytecode asset differs from solc output for %s.sol; run make update-contract-assets", c.nombre)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+// Package bindings contiene los bindings tipados de los contratos LoteTracing
+// y LoteTracingFactory, generados con el generador de abigen a partir de los
+// assets de assets/contracts. No edite lote_tracing.go ni
+// lote_tracing_factory.go a mano; tras actualizar los assets ejecute:
+//
+//	go generate ./bindings
+package bindings
+
+//go:generate go run gen.go
@@ -0,0 +1,53 @@
+//go:build ignore
+
+// gen regenera los bindings de los contratos con bind.Bind, el mismo
+// generador que usa abigen. Para LoteTracing equivale a:
+//
+//	abigen --abi ../assets/contracts/LoteTracing.abi.json \
+//	       --bin ../assets/contracts/LoteTracing.bytecode \
+//	       --pkg bindings --type LoteTracing --out lote_tracing.go
+//
+// Se invoca así porque cmd/abigen de go-ethereum v1.13.5 no enlaza con las
+// versiones recientes de Go.
+package main
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// contratos relaciona cada contrato de assets/contracts con su archivo Go
+var contratos = map[string]string{
+	"LoteTracing":        "lote_tracing.go",
+	"LoteTracingFactory": "lote_tracing_factory.go",
+}
+
+func main() {
+	for contrato, archivo := range contratos {
+		abiJSON, err := os.ReadFile("../assets/contracts/" + contrato + ".abi.json")
+		if err != nil {
+			log.Fatalf("Error leyendo ABI de %s: %v", contrato, err)
+		}
+		bytecode, err := os.ReadFile("../assets/contracts/" + contrato + ".bytecode")
+		if err != nil {
+			log.Fatalf("Error leyendo bytecode de %s: %v", contrato, err)
+		}
+
+		code, err := bind.Bind(
+			[]string{contrato},
+			[]string{string(abiJSON)},
+			[]string{strings.TrimSpace(string(bytecode))},
+			nil, "bindings", bind.LangGo, nil, nil,
+		)
+		if err != nil {
+			log.Fatalf("Error generando bindings de %s: %v", contrato, err)
+		}
+
+		if err := os.WriteFile(archivo, []byte(code), 0644); err != nil {
+			log.Fatalf("Error escribiendo bindings de %s: %v", contrato, err)
+		}
+	}
+}
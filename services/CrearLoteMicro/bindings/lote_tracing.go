@@ -0,0 +1,3077 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package bindings
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// LoteTracingMetaData contains all meta data concerning the LoteTracing contract.
+var LoteTracingMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_loteId\",\"type\":\"string\"},{\"internalType\":\"int8\",\"name\":\"_tempMin\",\"type\":\"int8\"},{\"internalType\":\"int8\",\"name\":\"_tempMax\",\"type\":\"int8\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"adminAnterior\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"nuevoAdmin\",\"type\":\"address\"}],\"name\":\"AdminTransferido\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint32\",\"name\":\"propuesta\",\"type\":\"uint32\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"destinatario\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"firmada\",\"type\":\"bool\"}],\"name\":\"CustodiaAceptada\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint32\",\"name\":\"propuesta\",\"type\":\"uint32\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"propietario\",\"type\":\"address\"}],\"name\":\"CustodiaCancelada\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint32\",\"name\":\"propuesta\",\"type\":\"uint32\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"propietario\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"destinatario\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint64\",\"name\":\"expira\",\"type\":\"uint64\"},{\"indexed\":false,\"internalType\":\"bytes32\",\"name\":\"envio\",\"type\":\"bytes32\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"detallesEnvio\",\"type\":\"string\"}],\"name\":\"CustodiaPropuesta\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint32\",\"name\":\"propuesta\",\"type\":\"uint32\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"destinatario\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"motivo\",\"type\":\"string\"}],\"name\":\"CustodiaRechazada\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"propietarioAnterior\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"nuevoPropietario\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"comprometido\",\"type\":\"bool\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"motivo\",\"type\":\"string\"}],\"name\":\"CustodiaTransferida\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"oraculo\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"raizMerkle\",\"type\":\"bytes32\"},{\"indexed\":false,\"internalType\":\"int8\",\"name\":\"tempMin\",\"type\":\"int8\"},{\"indexed\":false,\"internalType\":\"int8\",\"name\":\"tempMax\",\"type\":\"int8\"},{\"indexed\":false,\"internalType\":\"uint32\",\"name\":\"totalLecturas\",\"type\":\"uint32\"},{\"indexed\":false,\"internalType\":\"uint64\",\"name\":\"desde\",\"type\":\"uint64\"},{\"indexed\":false,\"internalType\":\"uint64\",\"name\":\"hasta\",\"type\":\"uint64\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"enRango\",\"type\":\"bool\"}],\"name\":\"LecturasAncladas\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"propietario\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"int8\",\"name\":\"tempMin\",\"type\":\"int8\"},{\"indexed\":false,\"internalType\":\"int8\",\"name\":\"tempMax\",\"type\":\"int8\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"comprometido\",\"type\":\"bool\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"motivo\",\"type\":\"string\"}],\"name\":\"LoteComprometido\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"string\",\"name\":\"loteId\",\"type\":\"string\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"fabricante\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"int8\",\"name\":\"temperaturaMinima\",\"type\":\"int8\"},{\"indexed\":false,\"internalType\":\"int8\",\"name\":\"temperaturaMaxima\",\"type\":\"int8\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"motivo\",\"type\":\"string\"}],\"name\":\"LoteCreado\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"rol\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"cuenta\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"admin\",\"type\":\"address\"}],\"name\":\"RolOtorgado\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"rol\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"cuenta\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"admin\",\"type\":\"address\"}],\"name\":\"RolRevocado\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"oraculo\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"sensorId\",\"type\":\"string\"},{\"indexed\":false,\"internalType\":\"int8\",\"name\":\"tempMin\",\"type\":\"int8\"},{\"indexed\":false,\"internalType\":\"int8\",\"name\":\"tempMax\",\"type\":\"int8\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"enRango\",\"type\":\"bool\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"timestamp\",\"type\":\"uint256\"}],\"name\":\"TemperaturaRegistrada\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"ACEPTACION_CUSTODIA_TYPEHASH\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"DOMAIN_SEPARATOR\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"PLAZO_MAXIMO_CUSTODIA\",\"outputs\":[{\"internalType\":\"uint64\",\"name\":\"\",\"type\":\"uint64\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"ROL_AUDITOR\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"ROL_DISTRIBUIDOR\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"ROL_FABRICANTE\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"ROL_FARMACIA\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"ROL_ORACULO\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"aceptarCustodia\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"_v\",\"type\":\"uint8\"},{\"internalType\":\"bytes32\",\"name\":\"_r\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_s\",\"type\":\"bytes32\"}],\"name\":\"aceptarCustodiaFirmada\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"admin\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"name\":\"anclajes\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_raizMerkle\",\"type\":\"bytes32\"},{\"internalType\":\"int8\",\"name\":\"_tempMin\",\"type\":\"int8\"},{\"internalType\":\"int8\",\"name\":\"_tempMax\",\"type\":\"int8\"},{\"internalType\":\"uint32\",\"name\":\"_totalLecturas\",\"type\":\"uint32\"},{\"internalType\":\"uint64\",\"name\":\"_desde\",\"type\":\"uint64\"},{\"internalType\":\"uint64\",\"name\":\"_hasta\",\"type\":\"uint64\"}],\"name\":\"anclarLecturas\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"cancelarCustodia\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"comprometido\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_loteId\",\"type\":\"string\"},{\"internalType\":\"int8\",\"name\":\"_tempMin\",\"type\":\"int8\"},{\"internalType\":\"int8\",\"name\":\"_tempMax\",\"type\":\"int8\"}],\"name\":\"crearNuevoLote\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"custodioPropuesto\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"envioPropuesto\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"fabricante\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"hashAceptacionCustodia\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"loteId\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_rol\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"_cuenta\",\"type\":\"address\"}],\"name\":\"otorgarRol\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"propietarioActual\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_destinatario\",\"type\":\"address\"},{\"internalType\":\"uint64\",\"name\":\"_plazo\",\"type\":\"uint64\"},{\"internalType\":\"string\",\"name\":\"_detallesEnvio\",\"type\":\"string\"}],\"name\":\"proponerCustodia\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"propuestaCustodia\",\"outputs\":[{\"internalType\":\"uint32\",\"name\":\"\",\"type\":\"uint32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"propuestaExpira\",\"outputs\":[{\"internalType\":\"uint64\",\"name\":\"\",\"type\":\"uint64\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_motivo\",\"type\":\"string\"}],\"name\":\"rechazarCustodia\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"int8\",\"name\":\"_tempMin\",\"type\":\"int8\"},{\"internalType\":\"int8\",\"name\":\"_tempMax\",\"type\":\"int8\"},{\"internalType\":\"string\",\"name\":\"_sensorId\",\"type\":\"string\"}],\"name\":\"registrarTemperatura\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_rol\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"_cuenta\",\"type\":\"address\"}],\"name\":\"revocarRol\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"tempRegMaxima\",\"outputs\":[{\"internalType\":\"int8\",\"name\":\"\",\"type\":\"int8\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"tempRegMinima\",\"outputs\":[{\"internalType\":\"int8\",\"name\":\"\",\"type\":\"int8\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"temperaturaMaxima\",\"outputs\":[{\"internalType\":\"int8\",\"name\":\"\",\"type\":\"int8\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"temperaturaMinima\",\"outputs\":[{\"internalType\":\"int8\",\"name\":\"\",\"type\":\"int8\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_rol\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"_cuenta\",\"type\":\"address\"}],\"name\":\"tieneRol\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_nuevoAdmin\",\"type\":\"address\"}],\"name\":\"transferirAdmin\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"transferirCustodia\",\"outputs\":[],\"stateMutability\":\"pure\",\"type\":\"function\"}]",
+	Bin: "0x6108006080523461035d57612052380360a052606060a0511061035d5760a05160805180910160805260c05260a05161205260c0513960c05151806801000000000000000090101561035d578060e05260c0510151806801000000000000000090101561035d576101005260a0516101005160e051602001011161035d5761010051601f01601f1916608051809101608052610120526101005160e05161205201602001610120513960c05160200151808060000b141561035d576101405260c05160400151808060000b141561035d5761016052600060005260206000206101805260005480600116156100fc5760011c601f0160051c610100565b5060005b6101a052610100516020111561013c5761012051516101005160031b610100038091901c901b6101005160011b1760005560006101c052610194565b6101005160011b60011760005561010051601f0160051c6101c05260006101e0525b6101c0516101e0511015610193576101e05160051b6101205101516101e0516101805101556101e0516001016101e05261015e565b5b6101a0516101c05110156101be5760006101c0516101805101556101c0516001016101c052610194565b3360201b6101605160ff1660081b176101405160ff1617600155336002553360007f2e1a6fe73e94eadae803896e18a2db28d96e0c2bbfb1c6d0e6132a56bc35752d6000600090a3337f9103686c38eda3206383eed83797692d5a917d0483bb421df64162a374cb40e6600052600360205260406000206020526000526040600020546102cd576001337f9103686c38eda3206383eed83797692d5a917d0483bb421df64162a374cb40e66000526003602052604060002060205260005260406000205533337f9103686c38eda3206383eed83797692d5a917d0483bb421df64162a374cb40e67f5e212e0516f5eb70b18b4a50f0f4548dcff7cef8a35fc67114244716bdc9f1a56000600090a45b336101005161012051207fc144c2e3dd9d75c64005477d5ae1809dc476485e047bae2a8bce83faa249cf2460a060805180910160805261014051816000015261016051816020015260608160400152600b81606001526a4c6f74652043726561646f60a81b816080015260a090a3611cf080610362600039336101b4523361149352336114c85233611641526000f35b600080fd6108006080523461186057600436106118605760003560e01c6116cf565b7f9103686c38eda3206383eed83797692d5a917d0483bb421df64162a374cb40e660005260206000f35b7fb574002f4d0c1963acb3b6edd4006841deeb44df50ae4d854bb1dcb16f3c5dbb60005260206000f35b7f7033d01fb9d6aadcf57cd6aa2743e4a8c3fee21c2e6489aa87b03c057d45d21e60005260206000f35b7f25b58247febe2426ac51d83b2eb4847a62f5eebd7b67589482ddf30e1c5b7ab460005260206000f35b7fd8994f6d76f930dc5ea8c60e38e6334a87bb8539cc3082ac6828681c33316e3d60005260206000f35b60005480600116156101035760011c61010a565b60ff1660011c5b60a05260a051601f01601f191660400160c05260c05160805180910160805260e052602060e0515260a05160e051602001526000546001166101595760005460ff191660e051604001526101aa565b60006000526020600020610100526000610120525b60c0516101205160051b60400110156101aa57610120516101005101546101205160051b60e0510160400152610120516001016101205261016e565b60c05160e051f35b7f000000000000000000000000000000000000000000000000000000000000000060005260206000f35b60015460ff1660000b60005260206000f35b60015460081c60ff1660000b60005260206000f35b60015460101c60ff1660000b60005260206000f35b60015460181c60ff1660000b60005260206000f35b60015460201c73ffffffffffffffffffffffffffffffffffffffff1660005260206000f35b60015460c01c60ff1660005260206000f35b60025460005260206000f35b600435610140526024358060a01c611860576101605261016051610140516000526003602052604060002060205260005260406000205460005260206000f35b600435610140526024358060a01c61186057610160523360025414156118655761016051156118b6576000610140517f9103686c38eda3206383eed83797692d5a917d0483bb421df64162a374cb40e61417610140517fb574002f4d0c1963acb3b6edd4006841deeb44df50ae4d854bb1dcb16f3c5dbb1417610140517f7033d01fb9d6aadcf57cd6aa2743e4a8c3fee21c2e6489aa87b03c057d45d21e1417610140517f25b58247febe2426ac51d83b2eb4847a62f5eebd7b67589482ddf30e1c5b7ab41417610140517fd8994f6d76f930dc5ea8c60e38e6334a87bb8539cc3082ac6828681c33316e3d1417156118ea5761016051610140516000526003602052604060002060205260005260406000205461041c5760016101605161014051600052600360205260406000206020526000526040600020553361016051610140517f5e212e0516f5eb70b18b4a50f0f4548dcff7cef8a35fc67114244716bdc9f1a56000600090a45b005b600435610140526024358060a01c611860576101605233600254141561186557610160516101405160005260036020526040600020602052600052604060002054156104b85760006101605161014051600052600360205260406000206020526000526040600020553361016051610140517f0de29865220d629a87a2d6905a4847aabf59e478cc2ecacffdd9567946184a546000600090a45b005b6004358060a01c61186057610160523360025414156118655761016051156118b65761016051337f2e1a6fe73e94eadae803896e18a2db28d96e0c2bbfb1c6d0e6132a56bc35752d6000600090a361016051600255005b600435808060000b14156118605761018052602435808060000b1415611860576101a0526044358068010000000000000000901015611860576004018035806801000000000000000090101561186057806101c05290602001806101e05201361061186057337f25b58247febe2426ac51d83b2eb4847a62f5eebd7b67589482ddf30e1c5b7ab46000526003602052604060002060205260005260406000205415611918576001546101805160ff1660101b9062ff00001916176101a05160ff1660181b9063ff000000191617610200526102005160ff1660000b61018051126102005160081c60ff1660000b6101a051131761022052337f345281d77e0fd6c1a457f709d18f3162796f1a315cebb4062e9338ae8915d6156101c051601f01601f191660c00160805180910160805260a081600001526101c0518160a001526101c0516101e0518260c001376101805181602001526101a0518160400152610220511581606001524281608001526101c051601f01601f191660c00190a261022051156107435761020051600160ff1660c01b9078ff000000000000000000000000000000000000000000000000191617600155337f26174a1d6632f37659819648fe37603b6961a9c4af42e0dc262b371b8320d76b60c06080518091016080526101805181600001526101a05181602001526001816040015260808160600152601a81608001527954656d70657261747572612066756572612064652072616e676f60301b8160a0015260c090a2005b61020051600155005b60043561024052602435808060000b14156118605761018052604435808060000b1415611860576101a0526064358060201c61186057610260526084358060401c611860576102805260a4358060401c611860576102a052337f25b58247febe2426ac51d83b2eb4847a62f5eebd7b67589482ddf30e1c5b7ab460005260036020526040600020602052600052604060002054156119185761024051156119555761024051600052600460205260406000208054151561198b574290556001546101805160ff1660101b9062ff00001916176101a05160ff1660181b9063ff000000191617610200526102005160ff1660000b61018051126102005160081c60ff1660000b6101a05113176102205261024051337fe134739a47f9d7603abaecf66751ba2e1d49cb0e8b6c9b24ade8dca68ca7c9c260c06080518091016080526101805181600001526101a05181602001526102605181604001526102805181606001526102a051816080015261022051158160a0015260c090a3610220511561097a5761020051600160ff1660c01b9078ff000000000000000000000000000000000000000000000000191617600155337f26174a1d6632f37659819648fe37603b6961a9c4af42e0dc262b371b8320d76b60c06080518091016080526101805181600001526101a05181602001526001816040015260808160600152601a81608001527954656d70657261747572612066756572612064652072616e676f60301b8160a0015260c090a2005b61020051600155005b600435600052600460205260406000205460005260206000f35b60016119c1575b7fa54e2d55d27f0d70f010b8deee8f679c101164fda5ace7079d2d2641ef3c7d2b60005260206000f35b62278d0060005260206000f35b60055473ffffffffffffffffffffffffffffffffffffffff1660005260206000f35b60055460a01c67ffffffffffffffff1660005260206000f35b60055460e01c63ffffffff1660005260206000f35b60065460005260206000f35b60a06080518091016080527f8b73c3c69bb8fe3d512ecc4cf759cc79239f7b179b0ffacaa9a75d522b39400f81600001527f27ec14925c7b93b1ead7800142d05e69dbb1fa38c4a5830b9c90683b78930a5181602001527fc89efdaa54c0f20c7adf612882df0950f5a951637e0307cdcb4c672f298b8bc6816040015246816060015230816080015260a0902060005260206000f35b60806080518091016080527fa54e2d55d27f0d70f010b8deee8f679c101164fda5ace7079d2d2641ef3c7d2b816000015260055460e01c63ffffffff16816020015260055473ffffffffffffffffffffffffffffffffffffffff1681604001526006548160600152608090206102c05260606080518091016080526102e05261190160f01b6102e0515260a06080518091016080527f8b73c3c69bb8fe3d512ecc4cf759cc79239f7b179b0ffacaa9a75d522b39400f81600001527f27ec14925c7b93b1ead7800142d05e69dbb1fa38c4a5830b9c90683b78930a5181602001527fc89efdaa54c0f20c7adf612882df0950f5a951637e0307cdcb4c672f298b8bc6816040015246816060015230816080015260a090206102e051600201526102c0516102e0516022015260426102e0512060005260206000f35b6004358060a01c61186057610160526024358060401c61186057610300526044358068010000000000000000901015611860576004018035806801000000000000000090101561186057806101c05290602001806101e0520136106118605760015460201c73ffffffffffffffffffffffffffffffffffffffff16331415611a0d5761016051156118b657610160517fb574002f4d0c1963acb3b6edd4006841deeb44df50ae4d854bb1dcb16f3c5dbb60005260036020526040600020602052600052604060002054610160517f7033d01fb9d6aadcf57cd6aa2743e4a8c3fee21c2e6489aa87b03c057d45d21e600052600360205260406000206020526000526040600020541715611a6357610300511562278d00610300511117611abc5760055460a01c67ffffffffffffffff16421160055473ffffffffffffffffffffffffffffffffffffffff16151715611aec5760055460e01c63ffffffff1660010160e01b61030051420160a01b1761016051176005556101c051601f01601f191660805180910160805280610320526101c0516101e0518237506101c0516103205120600655610160513360055460e01c63ffffffff167fc269da83cd23ce0baec7f297f618e05e091944e148142e68ba4313f77b532b916101c051601f01601f191660800160805180910160805260055460a01c67ffffffffffffffff1681600001526006548160200152606081604001526101c05181606001526101c0516101e05182608001376101c051601f01601f191660800190a4005b3361016052600061034052611021565b6004358060081c61186057610360526044357f7fffffffffffffffffffffffffffffff5d576e7357a4501ddfe92f46681b20a010611b2d5760806080518091016080527fa54e2d55d27f0d70f010b8deee8f679c101164fda5ace7079d2d2641ef3c7d2b816000015260055460e01c63ffffffff16816020015260055473ffffffffffffffffffffffffffffffffffffffff1681604001526006548160600152608090206102c05260606080518091016080526102e05261190160f01b6102e0515260a06080518091016080527f8b73c3c69bb8fe3d512ecc4cf759cc79239f7b179b0ffacaa9a75d522b39400f81600001527f27ec14925c7b93b1ead7800142d05e69dbb1fa38c4a5830b9c90683b78930a5181602001527fc89efdaa54c0f20c7adf612882df0950f5a951637e0307cdcb4c672f298b8bc6816040015246816060015230816080015260a090206102e051600201526102c0516102e0516022015260426102e05120608060805180910160805261038052610380515261036051610380516020015260243561038051604001526044356103805160600152600080526020600060806103805160015afa1561186057600051610160526101605115611b2d576001610340525b60055473ffffffffffffffffffffffffffffffffffffffff1615611b5d5760055473ffffffffffffffffffffffffffffffffffffffff16610160511415611b985760055460a01c67ffffffffffffffff164211611be857610160517fb574002f4d0c1963acb3b6edd4006841deeb44df50ae4d854bb1dcb16f3c5dbb60005260036020526040600020602052600052604060002054610160517f7033d01fb9d6aadcf57cd6aa2743e4a8c3fee21c2e6489aa87b03c057d45d21e600052600360205260406000206020526000526040600020541715611a635760015460201c73ffffffffffffffffffffffffffffffffffffffff166103a0526001546101605173ffffffffffffffffffffffffffffffffffffffff1660201b9077ffffffffffffffffffffffffffffffffffffffff000000001916176001556005547bffffffffffffffffffffffffffffffffffffffffffffffffffffffff19166005556101605160055460e01c63ffffffff167f7b728cbf6546147b6e9df89dbd52b04f41bb072761d3b20299affa324e0001e46020608051809101608052610340518160000152602090a3610160516103a0517f6eb1b8213490de25bc203f2a9ea5a4ff9058d06be46d42380a317522d53d3fbe608060805180910160805260015460c01c60ff168160000152604081602001526011816040015270437573746f64696120416365707461646160781b8160600152608090a3005b6004358068010000000000000000901015611860576004018035806801000000000000000090101561186057806101c05290602001806101e0520136106118605760055473ffffffffffffffffffffffffffffffffffffffff1615611b5d5760055473ffffffffffffffffffffffffffffffffffffffff16331415611b98576005547bffffffffffffffffffffffffffffffffffffffffffffffffffffffff19166005553360055460e01c63ffffffff167f19bd74c7453489924ad3dc0856202b9732a0944e02bb912b4cb399c220891e076101c051601f01601f1916604001608051809101608052602081600001526101c05181602001526101c0516101e05182604001376101c051601f01601f191660400190a3005b60015460201c73ffffffffffffffffffffffffffffffffffffffff16331415611a0d5760055473ffffffffffffffffffffffffffffffffffffffff1615611b5d576005547bffffffffffffffffffffffffffffffffffffffffffffffffffffffff19166005553360055460e01c63ffffffff167f375952440020e19b868c2dee25f73b6a1a5ab0d14073d5180599c59902d284b26000600090a3005b6004358068010000000000000000901015611860576004018035806801000000000000000090101561186057806101c05290602001806101e05201361061186057602435808060000b14156118605761018052604435808060000b1415611860576101a052337f9103686c38eda3206383eed83797692d5a917d0483bb421df64162a374cb40e66000526003602052604060002060205260005260406000205415611918577f0000000000000000000000000000000000000000000000000000000000000000331415611c275760055460e01c63ffffffff16157f000000000000000000000000000000000000000000000000000000000000000060015460201c73ffffffffffffffffffffffffffffffffffffffff16141615611c7e5760015460c01c60ff16611cbd576101c051601f01601f191660805180910160805280610320526101c0516101e051823750600060005260206000206103c05260005480600116156115635760011c601f0160051c611567565b5060005b6103e0526101c051602011156115a35761032051516101c05160031b610100038091901c901b6101c05160011b176000556000610400526115fb565b6101c05160011b6001176000556101c051601f0160051c610400526000610420525b610400516104205110156115fa576104205160051b610320510151610420516103c051015561042051600101610420526115c5565b5b6103e051610400511015611625576000610400516103c051015561040051600101610400526115fb565b3360201b6101a05160ff1660081b176101805160ff16176001557f00000000000000000000000000000000000000000000000000000000000000006101c05161032051207fc144c2e3dd9d75c64005477d5ae1809dc476485e047bae2a8bce83faa249cf2460a06080518091016080526101805181600001526101a051816020015260608160400152600b81606001526a4c6f74652043726561646f60a81b816080015260a090a3005b806310da85a71461001d578063d6640a2714610047578063d98b79ff14610071578063e54a2f901461009b578063da69922b146100c5578063d48cf490146100ef57806346ed76f1146101b2578063af1e6253146101dc5780632ba6b752146101ee5780633f3a74a414610203578063902e6d661461021857806395defb561461022d57806386b7d1e014610252578063f851a44014610264578063bd8a95ba14610270578063f8b114c5146102b05780633001c0971461041e578063bbe99a1e146104ba578063f94006761461051157806363639ec91461074c5780635c9510cd146109835780631ccbe36b1461099d578063c2d4819e146109a45780635a705d94146109ce5780632baca244146109db5780633cde69d8146109fd578063cc31ff4014610a165780638a46c60114610a2b5780633644e51514610a37578063c1cd473514610acd578063c14c828e14610c0857806392ac8b1814610e43578063f7eafc4a14610e535780632e9d871b14611238578063c9c09fa614611350578063d827fe39146113ec57611860565b600080fd5b6308c379a060e01b6000526020600452602b6024527f416363696f6e20736f6c6f207065726d6974696461207061726120656c2061646044526a6d696e6973747261646f7260a81b60645260846000fd5b6308c379a060e01b6000526020600452601260245271446972656363696f6e20696e76616c69646160701b60445260646000fd5b6308c379a060e01b6000526020600452600c6024526b526f6c20696e76616c69646f60a01b60445260646000fd5b6308c379a060e01b6000526020600452601b6024527a4375656e74612073696e20656c20726f6c2072657175657269646f60281b60445260646000fd5b6308c379a060e01b60005260206004526014602452735261697a204d65726b6c6520696e76616c69646160601b60445260646000fd5b6308c379a060e01b60005260206004526014602452734c6563747572617320796120616e636c6164617360601b60445260646000fd5b6308c379a060e01b600052602060045260266024527f5573652070726f706f6e6572437573746f6469612079206163657074617243756044526573746f64696160d01b60645260846000fd5b6308c379a060e01b600052602060045260306024527f416363696f6e20736f6c6f207065726d6974696461207061726120656c2070726044526f6f706965746172696f2061637475616c60801b60645260846000fd5b6308c379a060e01b600052602060045260336024527f456c206e7565766f2070726f706965746172696f206e6f2065732064697374726044527269627569646f72206e69206661726d6163696160681b60645260846000fd5b6308c379a060e01b6000526020600452600e6024526d506c617a6f20696e76616c69646f60901b60445260646000fd5b6308c379a060e01b6000526020600452601f6024527e50726f70756573746120646520637573746f6469612070656e6469656e746560081b60445260646000fd5b6308c379a060e01b6000526020600452600e6024526d4669726d6120696e76616c69646160901b60445260646000fd5b6308c379a060e01b600052602060045260196024527853696e2070726f70756573746120646520637573746f64696160381b60445260646000fd5b6308c379a060e01b6000526020600452602a6024527f416363696f6e20736f6c6f207065726d6974696461207061726120656c206465604452697374696e61746172696f60b01b60645260846000fd5b6308c379a060e01b6000526020600452601d6024527c50726f70756573746120646520637573746f6469612076656e6369646160181b60445260646000fd5b6308c379a060e01b600052602060045260316024527f416363696f6e20736f6c6f207065726d6974696461207061726120656c2066616044527062726963616e74652064656c206c6f746560781b60645260846000fd5b6308c379a060e01b6000526020600452601d6024527c456c206c6f74652079612063616d62696f20646520637573746f64696160181b60445260646000fd5b6308c379a060e01b60005260206004526011602452704c6f746520636f6d70726f6d657469646f60781b60445260646000fd",
+}
+
+// LoteTracingABI is the input ABI used to generate the binding from.
+// Deprecated: Use LoteTracingMetaData.ABI instead.
+var LoteTracingABI = LoteTracingMetaData.ABI
+
+// LoteTracingBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use LoteTracingMetaData.Bin instead.
+var LoteTracingBin = LoteTracingMetaData.Bin
+
+// DeployLoteTracing deploys a new Ethereum contract, binding an instance of LoteTracing to it.
+func DeployLoteTracing(auth *bind.TransactOpts, backend bind.ContractBackend, _loteId string, _tempMin int8, _tempMax int8) (common.Address, *types.Transaction, *LoteTracing, error) {
+	parsed, err := LoteTracingMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(LoteTracingBin), backend, _loteId, _tempMin, _tempMax)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &LoteTracing{LoteTracingCaller: LoteTracingCaller{contract: contract}, LoteTracingTransactor: LoteTracingTransactor{contract: contract}, LoteTracingFilterer: LoteTracingFilterer{contract: contract}}, nil
+}
+
+// LoteTracing is an auto generated Go binding around an Ethereum contract.
+type LoteTracing struct {
+	LoteTracingCaller     // Read-only binding to the contract
+	LoteTracingTransactor // Write-only binding to the contract
+	LoteTracingFilterer   // Log filterer for contract events
+}
+
+// LoteTracingCaller is an auto generated read-only Go binding around an Ethereum contract.
+type LoteTracingCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// LoteTracingTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type LoteTracingTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// LoteTracingFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type LoteTracingFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// LoteTracingSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type LoteTracingSession struct {
+	Contract     *LoteTracing      // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// LoteTracingCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type LoteTracingCallerSession struct {
+	Contract *LoteTracingCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts      // Call options to use throughout this session
+}
+
+// LoteTracingTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type LoteTracingTransactorSession struct {
+	Contract     *LoteTracingTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts      // Transaction auth options to use throughout this session
+}
+
+// LoteTracingRaw is an auto generated low-level Go binding around an Ethereum contract.
+type LoteTracingRaw struct {
+	Contract *LoteTracing // Generic contract binding to access the raw methods on
+}
+
+// LoteTracingCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type LoteTracingCallerRaw struct {
+	Contract *LoteTracingCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// LoteTracingTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type LoteTracingTransactorRaw struct {
+	Contract *LoteTracingTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewLoteTracing creates a new instance of LoteTracing, bound to a specific deployed contract.
+func NewLoteTracing(address common.Address, backend bind.ContractBackend) (*LoteTracing, error) {
+	contract, err := bindLoteTracing(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracing{LoteTracingCaller: LoteTracingCaller{contract: contract}, LoteTracingTransactor: LoteTracingTransactor{contract: contract}, LoteTracingFilterer: LoteTracingFilterer{contract: contract}}, nil
+}
+
+// NewLoteTracingCaller creates a new read-only instance of LoteTracing, bound to a specific deployed contract.
+func NewLoteTracingCaller(address common.Address, caller bind.ContractCaller) (*LoteTracingCaller, error) {
+	contract, err := bindLoteTracing(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingCaller{contract: contract}, nil
+}
+
+// NewLoteTracingTransactor creates a new write-only instance of LoteTracing, bound to a specific deployed contract.
+func NewLoteTracingTransactor(address common.Address, transactor bind.ContractTransactor) (*LoteTracingTransactor, error) {
+	contract, err := bindLoteTracing(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingTransactor{contract: contract}, nil
+}
+
+// NewLoteTracingFilterer creates a new log filterer instance of LoteTracing, bound to a specific deployed contract.
+func NewLoteTracingFilterer(address common.Address, filterer bind.ContractFilterer) (*LoteTracingFilterer, error) {
+	contract, err := bindLoteTracing(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingFilterer{contract: contract}, nil
+}
+
+// bindLoteTracing binds a generic wrapper to an already deployed contract.
+func bindLoteTracing(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := LoteTracingMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_LoteTracing *LoteTracingRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _LoteTracing.Contract.LoteTracingCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_LoteTracing *LoteTracingRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _LoteTracing.Contract.LoteTracingTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_LoteTracing *LoteTracingRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _LoteTracing.Contract.LoteTracingTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_LoteTracing *LoteTracingCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _LoteTracing.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_LoteTracing *LoteTracingTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _LoteTracing.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_LoteTracing *LoteTracingTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _LoteTracing.Contract.contract.Transact(opts, method, params...)
+}
+
+// ACEPTACIONCUSTODIATYPEHASH is a free data retrieval call binding the contract method 0xc2d4819e.
+//
+// Solidity: function ACEPTACION_CUSTODIA_TYPEHASH() view returns(bytes32)
+func (_LoteTracing *LoteTracingCaller) ACEPTACIONCUSTODIATYPEHASH(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "ACEPTACION_CUSTODIA_TYPEHASH")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// ACEPTACIONCUSTODIATYPEHASH is a free data retrieval call binding the contract method 0xc2d4819e.
+//
+// Solidity: function ACEPTACION_CUSTODIA_TYPEHASH() view returns(bytes32)
+func (_LoteTracing *LoteTracingSession) ACEPTACIONCUSTODIATYPEHASH() ([32]byte, error) {
+	return _LoteTracing.Contract.ACEPTACIONCUSTODIATYPEHASH(&_LoteTracing.CallOpts)
+}
+
+// ACEPTACIONCUSTODIATYPEHASH is a free data retrieval call binding the contract method 0xc2d4819e.
+//
+// Solidity: function ACEPTACION_CUSTODIA_TYPEHASH() view returns(bytes32)
+func (_LoteTracing *LoteTracingCallerSession) ACEPTACIONCUSTODIATYPEHASH() ([32]byte, error) {
+	return _LoteTracing.Contract.ACEPTACIONCUSTODIATYPEHASH(&_LoteTracing.CallOpts)
+}
+
+// DOMAINSEPARATOR is a free data retrieval call binding the contract method 0x3644e515.
+//
+// Solidity: function DOMAIN_SEPARATOR() view returns(bytes32)
+func (_LoteTracing *LoteTracingCaller) DOMAINSEPARATOR(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "DOMAIN_SEPARATOR")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// DOMAINSEPARATOR is a free data retrieval call binding the contract method 0x3644e515.
+//
+// Solidity: function DOMAIN_SEPARATOR() view returns(bytes32)
+func (_LoteTracing *LoteTracingSession) DOMAINSEPARATOR() ([32]byte, error) {
+	return _LoteTracing.Contract.DOMAINSEPARATOR(&_LoteTracing.CallOpts)
+}
+
+// DOMAINSEPARATOR is a free data retrieval call binding the contract method 0x3644e515.
+//
+// Solidity: function DOMAIN_SEPARATOR() view returns(bytes32)
+func (_LoteTracing *LoteTracingCallerSession) DOMAINSEPARATOR() ([32]byte, error) {
+	return _LoteTracing.Contract.DOMAINSEPARATOR(&_LoteTracing.CallOpts)
+}
+
+// PLAZOMAXIMOCUSTODIA is a free data retrieval call binding the contract method 0x5a705d94.
+//
+// Solidity: function PLAZO_MAXIMO_CUSTODIA() view returns(uint64)
+func (_LoteTracing *LoteTracingCaller) PLAZOMAXIMOCUSTODIA(opts *bind.CallOpts) (uint64, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "PLAZO_MAXIMO_CUSTODIA")
+
+	if err != nil {
+		return *new(uint64), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(uint64)).(*uint64)
+
+	return out0, err
+
+}
+
+// PLAZOMAXIMOCUSTODIA is a free data retrieval call binding the contract method 0x5a705d94.
+//
+// Solidity: function PLAZO_MAXIMO_CUSTODIA() view returns(uint64)
+func (_LoteTracing *LoteTracingSession) PLAZOMAXIMOCUSTODIA() (uint64, error) {
+	return _LoteTracing.Contract.PLAZOMAXIMOCUSTODIA(&_LoteTracing.CallOpts)
+}
+
+// PLAZOMAXIMOCUSTODIA is a free data retrieval call binding the contract method 0x5a705d94.
+//
+// Solidity: function PLAZO_MAXIMO_CUSTODIA() view returns(uint64)
+func (_LoteTracing *LoteTracingCallerSession) PLAZOMAXIMOCUSTODIA() (uint64, error) {
+	return _LoteTracing.Contract.PLAZOMAXIMOCUSTODIA(&_LoteTracing.CallOpts)
+}
+
+// ROLAUDITOR is a free data retrieval call binding the contract method 0xda69922b.
+//
+// Solidity: function ROL_AUDITOR() view returns(bytes32)
+func (_LoteTracing *LoteTracingCaller) ROLAUDITOR(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "ROL_AUDITOR")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// ROLAUDITOR is a free data retrieval call binding the contract method 0xda69922b.
+//
+// Solidity: function ROL_AUDITOR() view returns(bytes32)
+func (_LoteTracing *LoteTracingSession) ROLAUDITOR() ([32]byte, error) {
+	return _LoteTracing.Contract.ROLAUDITOR(&_LoteTracing.CallOpts)
+}
+
+// ROLAUDITOR is a free data retrieval call binding the contract method 0xda69922b.
+//
+// Solidity: function ROL_AUDITOR() view returns(bytes32)
+func (_LoteTracing *LoteTracingCallerSession) ROLAUDITOR() ([32]byte, error) {
+	return _LoteTracing.Contract.ROLAUDITOR(&_LoteTracing.CallOpts)
+}
+
+// ROLDISTRIBUIDOR is a free data retrieval call binding the contract method 0xd6640a27.
+//
+// Solidity: function ROL_DISTRIBUIDOR() view returns(bytes32)
+func (_LoteTracing *LoteTracingCaller) ROLDISTRIBUIDOR(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "ROL_DISTRIBUIDOR")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// ROLDISTRIBUIDOR is a free data retrieval call binding the contract method 0xd6640a27.
+//
+// Solidity: function ROL_DISTRIBUIDOR() view returns(bytes32)
+func (_LoteTracing *LoteTracingSession) ROLDISTRIBUIDOR() ([32]byte, error) {
+	return _LoteTracing.Contract.ROLDISTRIBUIDOR(&_LoteTracing.CallOpts)
+}
+
+// ROLDISTRIBUIDOR is a free data retrieval call binding the contract method 0xd6640a27.
+//
+// Solidity: function ROL_DISTRIBUIDOR() view returns(bytes32)
+func (_LoteTracing *LoteTracingCallerSession) ROLDISTRIBUIDOR() ([32]byte, error) {
+	return _LoteTracing.Contract.ROLDISTRIBUIDOR(&_LoteTracing.CallOpts)
+}
+
+// ROLFABRICANTE is a free data retrieval call binding the contract method 0x10da85a7.
+//
+// Solidity: function ROL_FABRICANTE() view returns(bytes32)
+func (_LoteTracing *LoteTracingCaller) ROLFABRICANTE(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "ROL_FABRICANTE")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// ROLFABRICANTE is a free data retrieval call binding the contract method 0x10da85a7.
+//
+// Solidity: function ROL_FABRICANTE() view returns(bytes32)
+func (_LoteTracing *LoteTracingSession) ROLFABRICANTE() ([32]byte, error) {
+	return _LoteTracing.Contract.ROLFABRICANTE(&_LoteTracing.CallOpts)
+}
+
+// ROLFABRICANTE is a free data retrieval call binding the contract method 0x10da85a7.
+//
+// Solidity: function ROL_FABRICANTE() view returns(bytes32)
+func (_LoteTracing *LoteTracingCallerSession) ROLFABRICANTE() ([32]byte, error) {
+	return _LoteTracing.Contract.ROLFABRICANTE(&_LoteTracing.CallOpts)
+}
+
+// ROLFARMACIA is a free data retrieval call binding the contract method 0xd98b79ff.
+//
+// Solidity: function ROL_FARMACIA() view returns(bytes32)
+func (_LoteTracing *LoteTracingCaller) ROLFARMACIA(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "ROL_FARMACIA")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// ROLFARMACIA is a free data retrieval call binding the contract method 0xd98b79ff.
+//
+// Solidity: function ROL_FARMACIA() view returns(bytes32)
+func (_LoteTracing *LoteTracingSession) ROLFARMACIA() ([32]byte, error) {
+	return _LoteTracing.Contract.ROLFARMACIA(&_LoteTracing.CallOpts)
+}
+
+// ROLFARMACIA is a free data retrieval call binding the contract method 0xd98b79ff.
+//
+// Solidity: function ROL_FARMACIA() view returns(bytes32)
+func (_LoteTracing *LoteTracingCallerSession) ROLFARMACIA() ([32]byte, error) {
+	return _LoteTracing.Contract.ROLFARMACIA(&_LoteTracing.CallOpts)
+}
+
+// ROLORACULO is a free data retrieval call binding the contract method 0xe54a2f90.
+//
+// Solidity: function ROL_ORACULO() view returns(bytes32)
+func (_LoteTracing *LoteTracingCaller) ROLORACULO(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "ROL_ORACULO")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// ROLORACULO is a free data retrieval call binding the contract method 0xe54a2f90.
+//
+// Solidity: function ROL_ORACULO() view returns(bytes32)
+func (_LoteTracing *LoteTracingSession) ROLORACULO() ([32]byte, error) {
+	return _LoteTracing.Contract.ROLORACULO(&_LoteTracing.CallOpts)
+}
+
+// ROLORACULO is a free data retrieval call binding the contract method 0xe54a2f90.
+//
+// Solidity: function ROL_ORACULO() view returns(bytes32)
+func (_LoteTracing *LoteTracingCallerSession) ROLORACULO() ([32]byte, error) {
+	return _LoteTracing.Contract.ROLORACULO(&_LoteTracing.CallOpts)
+}
+
+// Admin is a free data retrieval call binding the contract method 0xf851a440.
+//
+// Solidity: function admin() view returns(address)
+func (_LoteTracing *LoteTracingCaller) Admin(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "admin")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Admin is a free data retrieval call binding the contract method 0xf851a440.
+//
+// Solidity: function admin() view returns(address)
+func (_LoteTracing *LoteTracingSession) Admin() (common.Address, error) {
+	return _LoteTracing.Contract.Admin(&_LoteTracing.CallOpts)
+}
+
+// Admin is a free data retrieval call binding the contract method 0xf851a440.
+//
+// Solidity: function admin() view returns(address)
+func (_LoteTracing *LoteTracingCallerSession) Admin() (common.Address, error) {
+	return _LoteTracing.Contract.Admin(&_LoteTracing.CallOpts)
+}
+
+// Anclajes is a free data retrieval call binding the contract method 0x5c9510cd.
+//
+// Solidity: function anclajes(bytes32 ) view returns(uint256)
+func (_LoteTracing *LoteTracingCaller) Anclajes(opts *bind.CallOpts, arg0 [32]byte) (*big.Int, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "anclajes", arg0)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Anclajes is a free data retrieval call binding the contract method 0x5c9510cd.
+//
+// Solidity: function anclajes(bytes32 ) view returns(uint256)
+func (_LoteTracing *LoteTracingSession) Anclajes(arg0 [32]byte) (*big.Int, error) {
+	return _LoteTracing.Contract.Anclajes(&_LoteTracing.CallOpts, arg0)
+}
+
+// Anclajes is a free data retrieval call binding the contract method 0x5c9510cd.
+//
+// Solidity: function anclajes(bytes32 ) view returns(uint256)
+func (_LoteTracing *LoteTracingCallerSession) Anclajes(arg0 [32]byte) (*big.Int, error) {
+	return _LoteTracing.Contract.Anclajes(&_LoteTracing.CallOpts, arg0)
+}
+
+// Comprometido is a free data retrieval call binding the contract method 0x86b7d1e0.
+//
+// Solidity: function comprometido() view returns(bool)
+func (_LoteTracing *LoteTracingCaller) Comprometido(opts *bind.CallOpts) (bool, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "comprometido")
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// Comprometido is a free data retrieval call binding the contract method 0x86b7d1e0.
+//
+// Solidity: function comprometido() view returns(bool)
+func (_LoteTracing *LoteTracingSession) Comprometido() (bool, error) {
+	return _LoteTracing.Contract.Comprometido(&_LoteTracing.CallOpts)
+}
+
+// Comprometido is a free data retrieval call binding the contract method 0x86b7d1e0.
+//
+// Solidity: function comprometido() view returns(bool)
+func (_LoteTracing *LoteTracingCallerSession) Comprometido() (bool, error) {
+	return _LoteTracing.Contract.Comprometido(&_LoteTracing.CallOpts)
+}
+
+// CustodioPropuesto is a free data retrieval call binding the contract method 0x2baca244.
+//
+// Solidity: function custodioPropuesto() view returns(address)
+func (_LoteTracing *LoteTracingCaller) CustodioPropuesto(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "custodioPropuesto")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// CustodioPropuesto is a free data retrieval call binding the contract method 0x2baca244.
+//
+// Solidity: function custodioPropuesto() view returns(address)
+func (_LoteTracing *LoteTracingSession) CustodioPropuesto() (common.Address, error) {
+	return _LoteTracing.Contract.CustodioPropuesto(&_LoteTracing.CallOpts)
+}
+
+// CustodioPropuesto is a free data retrieval call binding the contract method 0x2baca244.
+//
+// Solidity: function custodioPropuesto() view returns(address)
+func (_LoteTracing *LoteTracingCallerSession) CustodioPropuesto() (common.Address, error) {
+	return _LoteTracing.Contract.CustodioPropuesto(&_LoteTracing.CallOpts)
+}
+
+// EnvioPropuesto is a free data retrieval call binding the contract method 0x8a46c601.
+//
+// Solidity: function envioPropuesto() view returns(bytes32)
+func (_LoteTracing *LoteTracingCaller) EnvioPropuesto(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "envioPropuesto")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// EnvioPropuesto is a free data retrieval call binding the contract method 0x8a46c601.
+//
+// Solidity: function envioPropuesto() view returns(bytes32)
+func (_LoteTracing *LoteTracingSession) EnvioPropuesto() ([32]byte, error) {
+	return _LoteTracing.Contract.EnvioPropuesto(&_LoteTracing.CallOpts)
+}
+
+// EnvioPropuesto is a free data retrieval call binding the contract method 0x8a46c601.
+//
+// Solidity: function envioPropuesto() view returns(bytes32)
+func (_LoteTracing *LoteTracingCallerSession) EnvioPropuesto() ([32]byte, error) {
+	return _LoteTracing.Contract.EnvioPropuesto(&_LoteTracing.CallOpts)
+}
+
+// Fabricante is a free data retrieval call binding the contract method 0x46ed76f1.
+//
+// Solidity: function fabricante() view returns(address)
+func (_LoteTracing *LoteTracingCaller) Fabricante(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "fabricante")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Fabricante is a free data retrieval call binding the contract method 0x46ed76f1.
+//
+// Solidity: function fabricante() view returns(address)
+func (_LoteTracing *LoteTracingSession) Fabricante() (common.Address, error) {
+	return _LoteTracing.Contract.Fabricante(&_LoteTracing.CallOpts)
+}
+
+// Fabricante is a free data retrieval call binding the contract method 0x46ed76f1.
+//
+// Solidity: function fabricante() view returns(address)
+func (_LoteTracing *LoteTracingCallerSession) Fabricante() (common.Address, error) {
+	return _LoteTracing.Contract.Fabricante(&_LoteTracing.CallOpts)
+}
+
+// HashAceptacionCustodia is a free data retrieval call binding the contract method 0xc1cd4735.
+//
+// Solidity: function hashAceptacionCustodia() view returns(bytes32)
+func (_LoteTracing *LoteTracingCaller) HashAceptacionCustodia(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "hashAceptacionCustodia")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// HashAceptacionCustodia is a free data retrieval call binding the contract method 0xc1cd4735.
+//
+// Solidity: function hashAceptacionCustodia() view returns(bytes32)
+func (_LoteTracing *LoteTracingSession) HashAceptacionCustodia() ([32]byte, error) {
+	return _LoteTracing.Contract.HashAceptacionCustodia(&_LoteTracing.CallOpts)
+}
+
+// HashAceptacionCustodia is a free data retrieval call binding the contract method 0xc1cd4735.
+//
+// Solidity: function hashAceptacionCustodia() view returns(bytes32)
+func (_LoteTracing *LoteTracingCallerSession) HashAceptacionCustodia() ([32]byte, error) {
+	return _LoteTracing.Contract.HashAceptacionCustodia(&_LoteTracing.CallOpts)
+}
+
+// LoteId is a free data retrieval call binding the contract method 0xd48cf490.
+//
+// Solidity: function loteId() view returns(string)
+func (_LoteTracing *LoteTracingCaller) LoteId(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "loteId")
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// LoteId is a free data retrieval call binding the contract method 0xd48cf490.
+//
+// Solidity: function loteId() view returns(string)
+func (_LoteTracing *LoteTracingSession) LoteId() (string, error) {
+	return _LoteTracing.Contract.LoteId(&_LoteTracing.CallOpts)
+}
+
+// LoteId is a free data retrieval call binding the contract method 0xd48cf490.
+//
+// Solidity: function loteId() view returns(string)
+func (_LoteTracing *LoteTracingCallerSession) LoteId() (string, error) {
+	return _LoteTracing.Contract.LoteId(&_LoteTracing.CallOpts)
+}
+
+// PropietarioActual is a free data retrieval call binding the contract method 0x95defb56.
+//
+// Solidity: function propietarioActual() view returns(address)
+func (_LoteTracing *LoteTracingCaller) PropietarioActual(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "propietarioActual")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// PropietarioActual is a free data retrieval call binding the contract method 0x95defb56.
+//
+// Solidity: function propietarioActual() view returns(address)
+func (_LoteTracing *LoteTracingSession) PropietarioActual() (common.Address, error) {
+	return _LoteTracing.Contract.PropietarioActual(&_LoteTracing.CallOpts)
+}
+
+// PropietarioActual is a free data retrieval call binding the contract method 0x95defb56.
+//
+// Solidity: function propietarioActual() view returns(address)
+func (_LoteTracing *LoteTracingCallerSession) PropietarioActual() (common.Address, error) {
+	return _LoteTracing.Contract.PropietarioActual(&_LoteTracing.CallOpts)
+}
+
+// PropuestaCustodia is a free data retrieval call binding the contract method 0xcc31ff40.
+//
+// Solidity: function propuestaCustodia() view returns(uint32)
+func (_LoteTracing *LoteTracingCaller) PropuestaCustodia(opts *bind.CallOpts) (uint32, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "propuestaCustodia")
+
+	if err != nil {
+		return *new(uint32), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(uint32)).(*uint32)
+
+	return out0, err
+
+}
+
+// PropuestaCustodia is a free data retrieval call binding the contract method 0xcc31ff40.
+//
+// Solidity: function propuestaCustodia() view returns(uint32)
+func (_LoteTracing *LoteTracingSession) PropuestaCustodia() (uint32, error) {
+	return _LoteTracing.Contract.PropuestaCustodia(&_LoteTracing.CallOpts)
+}
+
+// PropuestaCustodia is a free data retrieval call binding the contract method 0xcc31ff40.
+//
+// Solidity: function propuestaCustodia() view returns(uint32)
+func (_LoteTracing *LoteTracingCallerSession) PropuestaCustodia() (uint32, error) {
+	return _LoteTracing.Contract.PropuestaCustodia(&_LoteTracing.CallOpts)
+}
+
+// PropuestaExpira is a free data retrieval call binding the contract method 0x3cde69d8.
+//
+// Solidity: function propuestaExpira() view returns(uint64)
+func (_LoteTracing *LoteTracingCaller) PropuestaExpira(opts *bind.CallOpts) (uint64, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "propuestaExpira")
+
+	if err != nil {
+		return *new(uint64), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(uint64)).(*uint64)
+
+	return out0, err
+
+}
+
+// PropuestaExpira is a free data retrieval call binding the contract method 0x3cde69d8.
+//
+// Solidity: function propuestaExpira() view returns(uint64)
+func (_LoteTracing *LoteTracingSession) PropuestaExpira() (uint64, error) {
+	return _LoteTracing.Contract.PropuestaExpira(&_LoteTracing.CallOpts)
+}
+
+// PropuestaExpira is a free data retrieval call binding the contract method 0x3cde69d8.
+//
+// Solidity: function propuestaExpira() view returns(uint64)
+func (_LoteTracing *LoteTracingCallerSession) PropuestaExpira() (uint64, error) {
+	return _LoteTracing.Contract.PropuestaExpira(&_LoteTracing.CallOpts)
+}
+
+// TempRegMaxima is a free data retrieval call binding the contract method 0x902e6d66.
+//
+// Solidity: function tempRegMaxima() view returns(int8)
+func (_LoteTracing *LoteTracingCaller) TempRegMaxima(opts *bind.CallOpts) (int8, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "tempRegMaxima")
+
+	if err != nil {
+		return *new(int8), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(int8)).(*int8)
+
+	return out0, err
+
+}
+
+// TempRegMaxima is a free data retrieval call binding the contract method 0x902e6d66.
+//
+// Solidity: function tempRegMaxima() view returns(int8)
+func (_LoteTracing *LoteTracingSession) TempRegMaxima() (int8, error) {
+	return _LoteTracing.Contract.TempRegMaxima(&_LoteTracing.CallOpts)
+}
+
+// TempRegMaxima is a free data retrieval call binding the contract method 0x902e6d66.
+//
+// Solidity: function tempRegMaxima() view returns(int8)
+func (_LoteTracing *LoteTracingCallerSession) TempRegMaxima() (int8, error) {
+	return _LoteTracing.Contract.TempRegMaxima(&_LoteTracing.CallOpts)
+}
+
+// TempRegMinima is a free data retrieval call binding the contract method 0x3f3a74a4.
+//
+// Solidity: function tempRegMinima() view returns(int8)
+func (_LoteTracing *LoteTracingCaller) TempRegMinima(opts *bind.CallOpts) (int8, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "tempRegMinima")
+
+	if err != nil {
+		return *new(int8), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(int8)).(*int8)
+
+	return out0, err
+
+}
+
+// TempRegMinima is a free data retrieval call binding the contract method 0x3f3a74a4.
+//
+// Solidity: function tempRegMinima() view returns(int8)
+func (_LoteTracing *LoteTracingSession) TempRegMinima() (int8, error) {
+	return _LoteTracing.Contract.TempRegMinima(&_LoteTracing.CallOpts)
+}
+
+// TempRegMinima is a free data retrieval call binding the contract method 0x3f3a74a4.
+//
+// Solidity: function tempRegMinima() view returns(int8)
+func (_LoteTracing *LoteTracingCallerSession) TempRegMinima() (int8, error) {
+	return _LoteTracing.Contract.TempRegMinima(&_LoteTracing.CallOpts)
+}
+
+// TemperaturaMaxima is a free data retrieval call binding the contract method 0x2ba6b752.
+//
+// Solidity: function temperaturaMaxima() view returns(int8)
+func (_LoteTracing *LoteTracingCaller) TemperaturaMaxima(opts *bind.CallOpts) (int8, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "temperaturaMaxima")
+
+	if err != nil {
+		return *new(int8), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(int8)).(*int8)
+
+	return out0, err
+
+}
+
+// TemperaturaMaxima is a free data retrieval call binding the contract method 0x2ba6b752.
+//
+// Solidity: function temperaturaMaxima() view returns(int8)
+func (_LoteTracing *LoteTracingSession) TemperaturaMaxima() (int8, error) {
+	return _LoteTracing.Contract.TemperaturaMaxima(&_LoteTracing.CallOpts)
+}
+
+// TemperaturaMaxima is a free data retrieval call binding the contract method 0x2ba6b752.
+//
+// Solidity: function temperaturaMaxima() view returns(int8)
+func (_LoteTracing *LoteTracingCallerSession) TemperaturaMaxima() (int8, error) {
+	return _LoteTracing.Contract.TemperaturaMaxima(&_LoteTracing.CallOpts)
+}
+
+// TemperaturaMinima is a free data retrieval call binding the contract method 0xaf1e6253.
+//
+// Solidity: function temperaturaMinima() view returns(int8)
+func (_LoteTracing *LoteTracingCaller) TemperaturaMinima(opts *bind.CallOpts) (int8, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "temperaturaMinima")
+
+	if err != nil {
+		return *new(int8), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(int8)).(*int8)
+
+	return out0, err
+
+}
+
+// TemperaturaMinima is a free data retrieval call binding the contract method 0xaf1e6253.
+//
+// Solidity: function temperaturaMinima() view returns(int8)
+func (_LoteTracing *LoteTracingSession) TemperaturaMinima() (int8, error) {
+	return _LoteTracing.Contract.TemperaturaMinima(&_LoteTracing.CallOpts)
+}
+
+// TemperaturaMinima is a free data retrieval call binding the contract method 0xaf1e6253.
+//
+// Solidity: function temperaturaMinima() view returns(int8)
+func (_LoteTracing *LoteTracingCallerSession) TemperaturaMinima() (int8, error) {
+	return _LoteTracing.Contract.TemperaturaMinima(&_LoteTracing.CallOpts)
+}
+
+// TieneRol is a free data retrieval call binding the contract method 0xbd8a95ba.
+//
+// Solidity: function tieneRol(bytes32 _rol, address _cuenta) view returns(bool)
+func (_LoteTracing *LoteTracingCaller) TieneRol(opts *bind.CallOpts, _rol [32]byte, _cuenta common.Address) (bool, error) {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "tieneRol", _rol, _cuenta)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// TieneRol is a free data retrieval call binding the contract method 0xbd8a95ba.
+//
+// Solidity: function tieneRol(bytes32 _rol, address _cuenta) view returns(bool)
+func (_LoteTracing *LoteTracingSession) TieneRol(_rol [32]byte, _cuenta common.Address) (bool, error) {
+	return _LoteTracing.Contract.TieneRol(&_LoteTracing.CallOpts, _rol, _cuenta)
+}
+
+// TieneRol is a free data retrieval call binding the contract method 0xbd8a95ba.
+//
+// Solidity: function tieneRol(bytes32 _rol, address _cuenta) view returns(bool)
+func (_LoteTracing *LoteTracingCallerSession) TieneRol(_rol [32]byte, _cuenta common.Address) (bool, error) {
+	return _LoteTracing.Contract.TieneRol(&_LoteTracing.CallOpts, _rol, _cuenta)
+}
+
+// TransferirCustodia is a free data retrieval call binding the contract method 0x1ccbe36b.
+//
+// Solidity: function transferirCustodia(address ) pure returns()
+func (_LoteTracing *LoteTracingCaller) TransferirCustodia(opts *bind.CallOpts, arg0 common.Address) error {
+	var out []interface{}
+	err := _LoteTracing.contract.Call(opts, &out, "transferirCustodia", arg0)
+
+	if err != nil {
+		return err
+	}
+
+	return err
+
+}
+
+// TransferirCustodia is a free data retrieval call binding the contract method 0x1ccbe36b.
+//
+// Solidity: function transferirCustodia(address ) pure returns()
+func (_LoteTracing *LoteTracingSession) TransferirCustodia(arg0 common.Address) error {
+	return _LoteTracing.Contract.TransferirCustodia(&_LoteTracing.CallOpts, arg0)
+}
+
+// TransferirCustodia is a free data retrieval call binding the contract method 0x1ccbe36b.
+//
+// Solidity: function transferirCustodia(address ) pure returns()
+func (_LoteTracing *LoteTracingCallerSession) TransferirCustodia(arg0 common.Address) error {
+	return _LoteTracing.Contract.TransferirCustodia(&_LoteTracing.CallOpts, arg0)
+}
+
+// AceptarCustodia is a paid mutator transaction binding the contract method 0x92ac8b18.
+//
+// Solidity: function aceptarCustodia() returns()
+func (_LoteTracing *LoteTracingTransactor) AceptarCustodia(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _LoteTracing.contract.Transact(opts, "aceptarCustodia")
+}
+
+// AceptarCustodia is a paid mutator transaction binding the contract method 0x92ac8b18.
+//
+// Solidity: function aceptarCustodia() returns()
+func (_LoteTracing *LoteTracingSession) AceptarCustodia() (*types.Transaction, error) {
+	return _LoteTracing.Contract.AceptarCustodia(&_LoteTracing.TransactOpts)
+}
+
+// AceptarCustodia is a paid mutator transaction binding the contract method 0x92ac8b18.
+//
+// Solidity: function aceptarCustodia() returns()
+func (_LoteTracing *LoteTracingTransactorSession) AceptarCustodia() (*types.Transaction, error) {
+	return _LoteTracing.Contract.AceptarCustodia(&_LoteTracing.TransactOpts)
+}
+
+// AceptarCustodiaFirmada is a paid mutator transaction binding the contract method 0xf7eafc4a.
+//
+// Solidity: function aceptarCustodiaFirmada(uint8 _v, bytes32 _r, bytes32 _s) returns()
+func (_LoteTracing *LoteTracingTransactor) AceptarCustodiaFirmada(opts *bind.TransactOpts, _v uint8, _r [32]byte, _s [32]byte) (*types.Transaction, error) {
+	return _LoteTracing.contract.Transact(opts, "aceptarCustodiaFirmada", _v, _r, _s)
+}
+
+// AceptarCustodiaFirmada is a paid mutator transaction binding the contract method 0xf7eafc4a.
+//
+// Solidity: function aceptarCustodiaFirmada(uint8 _v, bytes32 _r, bytes32 _s) returns()
+func (_LoteTracing *LoteTracingSession) AceptarCustodiaFirmada(_v uint8, _r [32]byte, _s [32]byte) (*types.Transaction, error) {
+	return _LoteTracing.Contract.AceptarCustodiaFirmada(&_LoteTracing.TransactOpts, _v, _r, _s)
+}
+
+// AceptarCustodiaFirmada is a paid mutator transaction binding the contract method 0xf7eafc4a.
+//
+// Solidity: function aceptarCustodiaFirmada(uint8 _v, bytes32 _r, bytes32 _s) returns()
+func (_LoteTracing *LoteTracingTransactorSession) AceptarCustodiaFirmada(_v uint8, _r [32]byte, _s [32]byte) (*types.Transaction, error) {
+	return _LoteTracing.Contract.AceptarCustodiaFirmada(&_LoteTracing.TransactOpts, _v, _r, _s)
+}
+
+// AnclarLecturas is a paid mutator transaction binding the contract method 0x63639ec9.
+//
+// Solidity: function anclarLecturas(bytes32 _raizMerkle, int8 _tempMin, int8 _tempMax, uint32 _totalLecturas, uint64 _desde, uint64 _hasta) returns()
+func (_LoteTracing *LoteTracingTransactor) AnclarLecturas(opts *bind.TransactOpts, _raizMerkle [32]byte, _tempMin int8, _tempMax int8, _totalLecturas uint32, _desde uint64, _hasta uint64) (*types.Transaction, error) {
+	return _LoteTracing.contract.Transact(opts, "anclarLecturas", _raizMerkle, _tempMin, _tempMax, _totalLecturas, _desde, _hasta)
+}
+
+// AnclarLecturas is a paid mutator transaction binding the contract method 0x63639ec9.
+//
+// Solidity: function anclarLecturas(bytes32 _raizMerkle, int8 _tempMin, int8 _tempMax, uint32 _totalLecturas, uint64 _desde, uint64 _hasta) returns()
+func (_LoteTracing *LoteTracingSession) AnclarLecturas(_raizMerkle [32]byte, _tempMin int8, _tempMax int8, _totalLecturas uint32, _desde uint64, _hasta uint64) (*types.Transaction, error) {
+	return _LoteTracing.Contract.AnclarLecturas(&_LoteTracing.TransactOpts, _raizMerkle, _tempMin, _tempMax, _totalLecturas, _desde, _hasta)
+}
+
+// AnclarLecturas is a paid mutator transaction binding the contract method 0x63639ec9.
+//
+// Solidity: function anclarLecturas(bytes32 _raizMerkle, int8 _tempMin, int8 _tempMax, uint32 _totalLecturas, uint64 _desde, uint64 _hasta) returns()
+func (_LoteTracing *LoteTracingTransactorSession) AnclarLecturas(_raizMerkle [32]byte, _tempMin int8, _tempMax int8, _totalLecturas uint32, _desde uint64, _hasta uint64) (*types.Transaction, error) {
+	return _LoteTracing.Contract.AnclarLecturas(&_LoteTracing.TransactOpts, _raizMerkle, _tempMin, _tempMax, _totalLecturas, _desde, _hasta)
+}
+
+// CancelarCustodia is a paid mutator transaction binding the contract method 0xc9c09fa6.
+//
+// Solidity: function cancelarCustodia() returns()
+func (_LoteTracing *LoteTracingTransactor) CancelarCustodia(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _LoteTracing.contract.Transact(opts, "cancelarCustodia")
+}
+
+// CancelarCustodia is a paid mutator transaction binding the contract method 0xc9c09fa6.
+//
+// Solidity: function cancelarCustodia() returns()
+func (_LoteTracing *LoteTracingSession) CancelarCustodia() (*types.Transaction, error) {
+	return _LoteTracing.Contract.CancelarCustodia(&_LoteTracing.TransactOpts)
+}
+
+// CancelarCustodia is a paid mutator transaction binding the contract method 0xc9c09fa6.
+//
+// Solidity: function cancelarCustodia() returns()
+func (_LoteTracing *LoteTracingTransactorSession) CancelarCustodia() (*types.Transaction, error) {
+	return _LoteTracing.Contract.CancelarCustodia(&_LoteTracing.TransactOpts)
+}
+
+// CrearNuevoLote is a paid mutator transaction binding the contract method 0xd827fe39.
+//
+// Solidity: function crearNuevoLote(string _loteId, int8 _tempMin, int8 _tempMax) returns()
+func (_LoteTracing *LoteTracingTransactor) CrearNuevoLote(opts *bind.TransactOpts, _loteId string, _tempMin int8, _tempMax int8) (*types.Transaction, error) {
+	return _LoteTracing.contract.Transact(opts, "crearNuevoLote", _loteId, _tempMin, _tempMax)
+}
+
+// CrearNuevoLote is a paid mutator transaction binding the contract method 0xd827fe39.
+//
+// Solidity: function crearNuevoLote(string _loteId, int8 _tempMin, int8 _tempMax) returns()
+func (_LoteTracing *LoteTracingSession) CrearNuevoLote(_loteId string, _tempMin int8, _tempMax int8) (*types.Transaction, error) {
+	return _LoteTracing.Contract.CrearNuevoLote(&_LoteTracing.TransactOpts, _loteId, _tempMin, _tempMax)
+}
+
+// CrearNuevoLote is a paid mutator transaction binding the contract method 0xd827fe39.
+//
+// Solidity: function crearNuevoLote(string _loteId, int8 _tempMin, int8 _tempMax) returns()
+func (_LoteTracing *LoteTracingTransactorSession) CrearNuevoLote(_loteId string, _tempMin int8, _tempMax int8) (*types.Transaction, error) {
+	return _LoteTracing.Contract.CrearNuevoLote(&_LoteTracing.TransactOpts, _loteId, _tempMin, _tempMax)
+}
+
+// OtorgarRol is a paid mutator transaction binding the contract method 0xf8b114c5.
+//
+// Solidity: function otorgarRol(bytes32 _rol, address _cuenta) returns()
+func (_LoteTracing *LoteTracingTransactor) OtorgarRol(opts *bind.TransactOpts, _rol [32]byte, _cuenta common.Address) (*types.Transaction, error) {
+	return _LoteTracing.contract.Transact(opts, "otorgarRol", _rol, _cuenta)
+}
+
+// OtorgarRol is a paid mutator transaction binding the contract method 0xf8b114c5.
+//
+// Solidity: function otorgarRol(bytes32 _rol, address _cuenta) returns()
+func (_LoteTracing *LoteTracingSession) OtorgarRol(_rol [32]byte, _cuenta common.Address) (*types.Transaction, error) {
+	return _LoteTracing.Contract.OtorgarRol(&_LoteTracing.TransactOpts, _rol, _cuenta)
+}
+
+// OtorgarRol is a paid mutator transaction binding the contract method 0xf8b114c5.
+//
+// Solidity: function otorgarRol(bytes32 _rol, address _cuenta) returns()
+func (_LoteTracing *LoteTracingTransactorSession) OtorgarRol(_rol [32]byte, _cuenta common.Address) (*types.Transaction, error) {
+	return _LoteTracing.Contract.OtorgarRol(&_LoteTracing.TransactOpts, _rol, _cuenta)
+}
+
+// ProponerCustodia is a paid mutator transaction binding the contract method 0xc14c828e.
+//
+// Solidity: function proponerCustodia(address _destinatario, uint64 _plazo, string _detallesEnvio) returns()
+func (_LoteTracing *LoteTracingTransactor) ProponerCustodia(opts *bind.TransactOpts, _destinatario common.Address, _plazo uint64, _detallesEnvio string) (*types.Transaction, error) {
+	return _LoteTracing.contract.Transact(opts, "proponerCustodia", _destinatario, _plazo, _detallesEnvio)
+}
+
+// ProponerCustodia is a paid mutator transaction binding the contract method 0xc14c828e.
+//
+// Solidity: function proponerCustodia(address _destinatario, uint64 _plazo, string _detallesEnvio) returns()
+func (_LoteTracing *LoteTracingSession) ProponerCustodia(_destinatario common.Address, _plazo uint64, _detallesEnvio string) (*types.Transaction, error) {
+	return _LoteTracing.Contract.ProponerCustodia(&_LoteTracing.TransactOpts, _destinatario, _plazo, _detallesEnvio)
+}
+
+// ProponerCustodia is a paid mutator transaction binding the contract method 0xc14c828e.
+//
+// Solidity: function proponerCustodia(address _destinatario, uint64 _plazo, string _detallesEnvio) returns()
+func (_LoteTracing *LoteTracingTransactorSession) ProponerCustodia(_destinatario common.Address, _plazo uint64, _detallesEnvio string) (*types.Transaction, error) {
+	return _LoteTracing.Contract.ProponerCustodia(&_LoteTracing.TransactOpts, _destinatario, _plazo, _detallesEnvio)
+}
+
+// RechazarCustodia is a paid mutator transaction binding the contract method 0x2e9d871b.
+//
+// Solidity: function rechazarCustodia(string _motivo) returns()
+func (_LoteTracing *LoteTracingTransactor) RechazarCustodia(opts *bind.TransactOpts, _motivo string) (*types.Transaction, error) {
+	return _LoteTracing.contract.Transact(opts, "rechazarCustodia", _motivo)
+}
+
+// RechazarCustodia is a paid mutator transaction binding the contract method 0x2e9d871b.
+//
+// Solidity: function rechazarCustodia(string _motivo) returns()
+func (_LoteTracing *LoteTracingSession) RechazarCustodia(_motivo string) (*types.Transaction, error) {
+	return _LoteTracing.Contract.RechazarCustodia(&_LoteTracing.TransactOpts, _motivo)
+}
+
+// RechazarCustodia is a paid mutator transaction binding the contract method 0x2e9d871b.
+//
+// Solidity: function rechazarCustodia(string _motivo) returns()
+func (_LoteTracing *LoteTracingTransactorSession) RechazarCustodia(_motivo string) (*types.Transaction, error) {
+	return _LoteTracing.Contract.RechazarCustodia(&_LoteTracing.TransactOpts, _motivo)
+}
+
+// RegistrarTemperatura is a paid mutator transaction binding the contract method 0xf9400676.
+//
+// Solidity: function registrarTemperatura(int8 _tempMin, int8 _tempMax, string _sensorId) returns()
+func (_LoteTracing *LoteTracingTransactor) RegistrarTemperatura(opts *bind.TransactOpts, _tempMin int8, _tempMax int8, _sensorId string) (*types.Transaction, error) {
+	return _LoteTracing.contract.Transact(opts, "registrarTemperatura", _tempMin, _tempMax, _sensorId)
+}
+
+// RegistrarTemperatura is a paid mutator transaction binding the contract method 0xf9400676.
+//
+// Solidity: function registrarTemperatura(int8 _tempMin, int8 _tempMax, string _sensorId) returns()
+func (_LoteTracing *LoteTracingSession) RegistrarTemperatura(_tempMin int8, _tempMax int8, _sensorId string) (*types.Transaction, error) {
+	return _LoteTracing.Contract.RegistrarTemperatura(&_LoteTracing.TransactOpts, _tempMin, _tempMax, _sensorId)
+}
+
+// RegistrarTemperatura is a paid mutator transaction binding the contract method 0xf9400676.
+//
+// Solidity: function registrarTemperatura(int8 _tempMin, int8 _tempMax, string _sensorId) returns()
+func (_LoteTracing *LoteTracingTransactorSession) RegistrarTemperatura(_tempMin int8, _tempMax int8, _sensorId string) (*types.Transaction, error) {
+	return _LoteTracing.Contract.RegistrarTemperatura(&_LoteTracing.TransactOpts, _tempMin, _tempMax, _sensorId)
+}
+
+// RevocarRol is a paid mutator transaction binding the contract method 0x3001c097.
+//
+// Solidity: function revocarRol(bytes32 _rol, address _cuenta) returns()
+func (_LoteTracing *LoteTracingTransactor) RevocarRol(opts *bind.TransactOpts, _rol [32]byte, _cuenta common.Address) (*types.Transaction, error) {
+	return _LoteTracing.contract.Transact(opts, "revocarRol", _rol, _cuenta)
+}
+
+// RevocarRol is a paid mutator transaction binding the contract method 0x3001c097.
+//
+// Solidity: function revocarRol(bytes32 _rol, address _cuenta) returns()
+func (_LoteTracing *LoteTracingSession) RevocarRol(_rol [32]byte, _cuenta common.Address) (*types.Transaction, error) {
+	return _LoteTracing.Contract.RevocarRol(&_LoteTracing.TransactOpts, _rol, _cuenta)
+}
+
+// RevocarRol is a paid mutator transaction binding the contract method 0x3001c097.
+//
+// Solidity: function revocarRol(bytes32 _rol, address _cuenta) returns()
+func (_LoteTracing *LoteTracingTransactorSession) RevocarRol(_rol [32]byte, _cuenta common.Address) (*types.Transaction, error) {
+	return _LoteTracing.Contract.RevocarRol(&_LoteTracing.TransactOpts, _rol, _cuenta)
+}
+
+// TransferirAdmin is a paid mutator transaction binding the contract method 0xbbe99a1e.
+//
+// Solidity: function transferirAdmin(address _nuevoAdmin) returns()
+func (_LoteTracing *LoteTracingTransactor) TransferirAdmin(opts *bind.TransactOpts, _nuevoAdmin common.Address) (*types.Transaction, error) {
+	return _LoteTracing.contract.Transact(opts, "transferirAdmin", _nuevoAdmin)
+}
+
+// TransferirAdmin is a paid mutator transaction binding the contract method 0xbbe99a1e.
+//
+// Solidity: function transferirAdmin(address _nuevoAdmin) returns()
+func (_LoteTracing *LoteTracingSession) TransferirAdmin(_nuevoAdmin common.Address) (*types.Transaction, error) {
+	return _LoteTracing.Contract.TransferirAdmin(&_LoteTracing.TransactOpts, _nuevoAdmin)
+}
+
+// TransferirAdmin is a paid mutator transaction binding the contract method 0xbbe99a1e.
+//
+// Solidity: function transferirAdmin(address _nuevoAdmin) returns()
+func (_LoteTracing *LoteTracingTransactorSession) TransferirAdmin(_nuevoAdmin common.Address) (*types.Transaction, error) {
+	return _LoteTracing.Contract.TransferirAdmin(&_LoteTracing.TransactOpts, _nuevoAdmin)
+}
+
+// LoteTracingAdminTransferidoIterator is returned from FilterAdminTransferido and is used to iterate over the raw logs and unpacked data for AdminTransferido events raised by the LoteTracing contract.
+type LoteTracingAdminTransferidoIterator struct {
+	Event *LoteTracingAdminTransferido // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LoteTracingAdminTransferidoIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LoteTracingAdminTransferido)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LoteTracingAdminTransferido)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LoteTracingAdminTransferidoIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LoteTracingAdminTransferidoIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LoteTracingAdminTransferido represents a AdminTransferido event raised by the LoteTracing contract.
+type LoteTracingAdminTransferido struct {
+	AdminAnterior common.Address
+	NuevoAdmin    common.Address
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterAdminTransferido is a free log retrieval operation binding the contract event 0x2e1a6fe73e94eadae803896e18a2db28d96e0c2bbfb1c6d0e6132a56bc35752d.
+//
+// Solidity: event AdminTransferido(address indexed adminAnterior, address indexed nuevoAdmin)
+func (_LoteTracing *LoteTracingFilterer) FilterAdminTransferido(opts *bind.FilterOpts, adminAnterior []common.Address, nuevoAdmin []common.Address) (*LoteTracingAdminTransferidoIterator, error) {
+
+	var adminAnteriorRule []interface{}
+	for _, adminAnteriorItem := range adminAnterior {
+		adminAnteriorRule = append(adminAnteriorRule, adminAnteriorItem)
+	}
+	var nuevoAdminRule []interface{}
+	for _, nuevoAdminItem := range nuevoAdmin {
+		nuevoAdminRule = append(nuevoAdminRule, nuevoAdminItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.FilterLogs(opts, "AdminTransferido", adminAnteriorRule, nuevoAdminRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingAdminTransferidoIterator{contract: _LoteTracing.contract, event: "AdminTransferido", logs: logs, sub: sub}, nil
+}
+
+// WatchAdminTransferido is a free log subscription operation binding the contract event 0x2e1a6fe73e94eadae803896e18a2db28d96e0c2bbfb1c6d0e6132a56bc35752d.
+//
+// Solidity: event AdminTransferido(address indexed adminAnterior, address indexed nuevoAdmin)
+func (_LoteTracing *LoteTracingFilterer) WatchAdminTransferido(opts *bind.WatchOpts, sink chan<- *LoteTracingAdminTransferido, adminAnterior []common.Address, nuevoAdmin []common.Address) (event.Subscription, error) {
+
+	var adminAnteriorRule []interface{}
+	for _, adminAnteriorItem := range adminAnterior {
+		adminAnteriorRule = append(adminAnteriorRule, adminAnteriorItem)
+	}
+	var nuevoAdminRule []interface{}
+	for _, nuevoAdminItem := range nuevoAdmin {
+		nuevoAdminRule = append(nuevoAdminRule, nuevoAdminItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.WatchLogs(opts, "AdminTransferido", adminAnteriorRule, nuevoAdminRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LoteTracingAdminTransferido)
+				if err := _LoteTracing.contract.UnpackLog(event, "AdminTransferido", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseAdminTransferido is a log parse operation binding the contract event 0x2e1a6fe73e94eadae803896e18a2db28d96e0c2bbfb1c6d0e6132a56bc35752d.
+//
+// Solidity: event AdminTransferido(address indexed adminAnterior, address indexed nuevoAdmin)
+func (_LoteTracing *LoteTracingFilterer) ParseAdminTransferido(log types.Log) (*LoteTracingAdminTransferido, error) {
+	event := new(LoteTracingAdminTransferido)
+	if err := _LoteTracing.contract.UnpackLog(event, "AdminTransferido", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LoteTracingCustodiaAceptadaIterator is returned from FilterCustodiaAceptada and is used to iterate over the raw logs and unpacked data for CustodiaAceptada events raised by the LoteTracing contract.
+type LoteTracingCustodiaAceptadaIterator struct {
+	Event *LoteTracingCustodiaAceptada // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LoteTracingCustodiaAceptadaIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LoteTracingCustodiaAceptada)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LoteTracingCustodiaAceptada)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LoteTracingCustodiaAceptadaIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LoteTracingCustodiaAceptadaIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LoteTracingCustodiaAceptada represents a CustodiaAceptada event raised by the LoteTracing contract.
+type LoteTracingCustodiaAceptada struct {
+	Propuesta    uint32
+	Destinatario common.Address
+	Firmada      bool
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterCustodiaAceptada is a free log retrieval operation binding the contract event 0x7b728cbf6546147b6e9df89dbd52b04f41bb072761d3b20299affa324e0001e4.
+//
+// Solidity: event CustodiaAceptada(uint32 indexed propuesta, address indexed destinatario, bool firmada)
+func (_LoteTracing *LoteTracingFilterer) FilterCustodiaAceptada(opts *bind.FilterOpts, propuesta []uint32, destinatario []common.Address) (*LoteTracingCustodiaAceptadaIterator, error) {
+
+	var propuestaRule []interface{}
+	for _, propuestaItem := range propuesta {
+		propuestaRule = append(propuestaRule, propuestaItem)
+	}
+	var destinatarioRule []interface{}
+	for _, destinatarioItem := range destinatario {
+		destinatarioRule = append(destinatarioRule, destinatarioItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.FilterLogs(opts, "CustodiaAceptada", propuestaRule, destinatarioRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingCustodiaAceptadaIterator{contract: _LoteTracing.contract, event: "CustodiaAceptada", logs: logs, sub: sub}, nil
+}
+
+// WatchCustodiaAceptada is a free log subscription operation binding the contract event 0x7b728cbf6546147b6e9df89dbd52b04f41bb072761d3b20299affa324e0001e4.
+//
+// Solidity: event CustodiaAceptada(uint32 indexed propuesta, address indexed destinatario, bool firmada)
+func (_LoteTracing *LoteTracingFilterer) WatchCustodiaAceptada(opts *bind.WatchOpts, sink chan<- *LoteTracingCustodiaAceptada, propuesta []uint32, destinatario []common.Address) (event.Subscription, error) {
+
+	var propuestaRule []interface{}
+	for _, propuestaItem := range propuesta {
+		propuestaRule = append(propuestaRule, propuestaItem)
+	}
+	var destinatarioRule []interface{}
+	for _, destinatarioItem := range destinatario {
+		destinatarioRule = append(destinatarioRule, destinatarioItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.WatchLogs(opts, "CustodiaAceptada", propuestaRule, destinatarioRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LoteTracingCustodiaAceptada)
+				if err := _LoteTracing.contract.UnpackLog(event, "CustodiaAceptada", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseCustodiaAceptada is a log parse operation binding the contract event 0x7b728cbf6546147b6e9df89dbd52b04f41bb072761d3b20299affa324e0001e4.
+//
+// Solidity: event CustodiaAceptada(uint32 indexed propuesta, address indexed destinatario, bool firmada)
+func (_LoteTracing *LoteTracingFilterer) ParseCustodiaAceptada(log types.Log) (*LoteTracingCustodiaAceptada, error) {
+	event := new(LoteTracingCustodiaAceptada)
+	if err := _LoteTracing.contract.UnpackLog(event, "CustodiaAceptada", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LoteTracingCustodiaCanceladaIterator is returned from FilterCustodiaCancelada and is used to iterate over the raw logs and unpacked data for CustodiaCancelada events raised by the LoteTracing contract.
+type LoteTracingCustodiaCanceladaIterator struct {
+	Event *LoteTracingCustodiaCancelada // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LoteTracingCustodiaCanceladaIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LoteTracingCustodiaCancelada)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LoteTracingCustodiaCancelada)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LoteTracingCustodiaCanceladaIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LoteTracingCustodiaCanceladaIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LoteTracingCustodiaCancelada represents a CustodiaCancelada event raised by the LoteTracing contract.
+type LoteTracingCustodiaCancelada struct {
+	Propuesta   uint32
+	Propietario common.Address
+	Raw         types.Log // Blockchain specific contextual infos
+}
+
+// FilterCustodiaCancelada is a free log retrieval operation binding the contract event 0x375952440020e19b868c2dee25f73b6a1a5ab0d14073d5180599c59902d284b2.
+//
+// Solidity: event CustodiaCancelada(uint32 indexed propuesta, address indexed propietario)
+func (_LoteTracing *LoteTracingFilterer) FilterCustodiaCancelada(opts *bind.FilterOpts, propuesta []uint32, propietario []common.Address) (*LoteTracingCustodiaCanceladaIterator, error) {
+
+	var propuestaRule []interface{}
+	for _, propuestaItem := range propuesta {
+		propuestaRule = append(propuestaRule, propuestaItem)
+	}
+	var propietarioRule []interface{}
+	for _, propietarioItem := range propietario {
+		propietarioRule = append(propietarioRule, propietarioItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.FilterLogs(opts, "CustodiaCancelada", propuestaRule, propietarioRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingCustodiaCanceladaIterator{contract: _LoteTracing.contract, event: "CustodiaCancelada", logs: logs, sub: sub}, nil
+}
+
+// WatchCustodiaCancelada is a free log subscription operation binding the contract event 0x375952440020e19b868c2dee25f73b6a1a5ab0d14073d5180599c59902d284b2.
+//
+// Solidity: event CustodiaCancelada(uint32 indexed propuesta, address indexed propietario)
+func (_LoteTracing *LoteTracingFilterer) WatchCustodiaCancelada(opts *bind.WatchOpts, sink chan<- *LoteTracingCustodiaCancelada, propuesta []uint32, propietario []common.Address) (event.Subscription, error) {
+
+	var propuestaRule []interface{}
+	for _, propuestaItem := range propuesta {
+		propuestaRule = append(propuestaRule, propuestaItem)
+	}
+	var propietarioRule []interface{}
+	for _, propietarioItem := range propietario {
+		propietarioRule = append(propietarioRule, propietarioItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.WatchLogs(opts, "CustodiaCancelada", propuestaRule, propietarioRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LoteTracingCustodiaCancelada)
+				if err := _LoteTracing.contract.UnpackLog(event, "CustodiaCancelada", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseCustodiaCancelada is a log parse operation binding the contract event 0x375952440020e19b868c2dee25f73b6a1a5ab0d14073d5180599c59902d284b2.
+//
+// Solidity: event CustodiaCancelada(uint32 indexed propuesta, address indexed propietario)
+func (_LoteTracing *LoteTracingFilterer) ParseCustodiaCancelada(log types.Log) (*LoteTracingCustodiaCancelada, error) {
+	event := new(LoteTracingCustodiaCancelada)
+	if err := _LoteTracing.contract.UnpackLog(event, "CustodiaCancelada", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LoteTracingCustodiaPropuestaIterator is returned from FilterCustodiaPropuesta and is used to iterate over the raw logs and unpacked data for CustodiaPropuesta events raised by the LoteTracing contract.
+type LoteTracingCustodiaPropuestaIterator struct {
+	Event *LoteTracingCustodiaPropuesta // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LoteTracingCustodiaPropuestaIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LoteTracingCustodiaPropuesta)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LoteTracingCustodiaPropuesta)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LoteTracingCustodiaPropuestaIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LoteTracingCustodiaPropuestaIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LoteTracingCustodiaPropuesta represents a CustodiaPropuesta event raised by the LoteTracing contract.
+type LoteTracingCustodiaPropuesta struct {
+	Propuesta     uint32
+	Propietario   common.Address
+	Destinatario  common.Address
+	Expira        uint64
+	Envio         [32]byte
+	DetallesEnvio string
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterCustodiaPropuesta is a free log retrieval operation binding the contract event 0xc269da83cd23ce0baec7f297f618e05e091944e148142e68ba4313f77b532b91.
+//
+// Solidity: event CustodiaPropuesta(uint32 indexed propuesta, address indexed propietario, address indexed destinatario, uint64 expira, bytes32 envio, string detallesEnvio)
+func (_LoteTracing *LoteTracingFilterer) FilterCustodiaPropuesta(opts *bind.FilterOpts, propuesta []uint32, propietario []common.Address, destinatario []common.Address) (*LoteTracingCustodiaPropuestaIterator, error) {
+
+	var propuestaRule []interface{}
+	for _, propuestaItem := range propuesta {
+		propuestaRule = append(propuestaRule, propuestaItem)
+	}
+	var propietarioRule []interface{}
+	for _, propietarioItem := range propietario {
+		propietarioRule = append(propietarioRule, propietarioItem)
+	}
+	var destinatarioRule []interface{}
+	for _, destinatarioItem := range destinatario {
+		destinatarioRule = append(destinatarioRule, destinatarioItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.FilterLogs(opts, "CustodiaPropuesta", propuestaRule, propietarioRule, destinatarioRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingCustodiaPropuestaIterator{contract: _LoteTracing.contract, event: "CustodiaPropuesta", logs: logs, sub: sub}, nil
+}
+
+// WatchCustodiaPropuesta is a free log subscription operation binding the contract event 0xc269da83cd23ce0baec7f297f618e05e091944e148142e68ba4313f77b532b91.
+//
+// Solidity: event CustodiaPropuesta(uint32 indexed propuesta, address indexed propietario, address indexed destinatario, uint64 expira, bytes32 envio, string detallesEnvio)
+func (_LoteTracing *LoteTracingFilterer) WatchCustodiaPropuesta(opts *bind.WatchOpts, sink chan<- *LoteTracingCustodiaPropuesta, propuesta []uint32, propietario []common.Address, destinatario []common.Address) (event.Subscription, error) {
+
+	var propuestaRule []interface{}
+	for _, propuestaItem := range propuesta {
+		propuestaRule = append(propuestaRule, propuestaItem)
+	}
+	var propietarioRule []interface{}
+	for _, propietarioItem := range propietario {
+		propietarioRule = append(propietarioRule, propietarioItem)
+	}
+	var destinatarioRule []interface{}
+	for _, destinatarioItem := range destinatario {
+		destinatarioRule = append(destinatarioRule, destinatarioItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.WatchLogs(opts, "CustodiaPropuesta", propuestaRule, propietarioRule, destinatarioRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LoteTracingCustodiaPropuesta)
+				if err := _LoteTracing.contract.UnpackLog(event, "CustodiaPropuesta", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseCustodiaPropuesta is a log parse operation binding the contract event 0xc269da83cd23ce0baec7f297f618e05e091944e148142e68ba4313f77b532b91.
+//
+// Solidity: event CustodiaPropuesta(uint32 indexed propuesta, address indexed propietario, address indexed destinatario, uint64 expira, bytes32 envio, string detallesEnvio)
+func (_LoteTracing *LoteTracingFilterer) ParseCustodiaPropuesta(log types.Log) (*LoteTracingCustodiaPropuesta, error) {
+	event := new(LoteTracingCustodiaPropuesta)
+	if err := _LoteTracing.contract.UnpackLog(event, "CustodiaPropuesta", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LoteTracingCustodiaRechazadaIterator is returned from FilterCustodiaRechazada and is used to iterate over the raw logs and unpacked data for CustodiaRechazada events raised by the LoteTracing contract.
+type LoteTracingCustodiaRechazadaIterator struct {
+	Event *LoteTracingCustodiaRechazada // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LoteTracingCustodiaRechazadaIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LoteTracingCustodiaRechazada)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LoteTracingCustodiaRechazada)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LoteTracingCustodiaRechazadaIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LoteTracingCustodiaRechazadaIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LoteTracingCustodiaRechazada represents a CustodiaRechazada event raised by the LoteTracing contract.
+type LoteTracingCustodiaRechazada struct {
+	Propuesta    uint32
+	Destinatario common.Address
+	Motivo       string
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterCustodiaRechazada is a free log retrieval operation binding the contract event 0x19bd74c7453489924ad3dc0856202b9732a0944e02bb912b4cb399c220891e07.
+//
+// Solidity: event CustodiaRechazada(uint32 indexed propuesta, address indexed destinatario, string motivo)
+func (_LoteTracing *LoteTracingFilterer) FilterCustodiaRechazada(opts *bind.FilterOpts, propuesta []uint32, destinatario []common.Address) (*LoteTracingCustodiaRechazadaIterator, error) {
+
+	var propuestaRule []interface{}
+	for _, propuestaItem := range propuesta {
+		propuestaRule = append(propuestaRule, propuestaItem)
+	}
+	var destinatarioRule []interface{}
+	for _, destinatarioItem := range destinatario {
+		destinatarioRule = append(destinatarioRule, destinatarioItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.FilterLogs(opts, "CustodiaRechazada", propuestaRule, destinatarioRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingCustodiaRechazadaIterator{contract: _LoteTracing.contract, event: "CustodiaRechazada", logs: logs, sub: sub}, nil
+}
+
+// WatchCustodiaRechazada is a free log subscription operation binding the contract event 0x19bd74c7453489924ad3dc0856202b9732a0944e02bb912b4cb399c220891e07.
+//
+// Solidity: event CustodiaRechazada(uint32 indexed propuesta, address indexed destinatario, string motivo)
+func (_LoteTracing *LoteTracingFilterer) WatchCustodiaRechazada(opts *bind.WatchOpts, sink chan<- *LoteTracingCustodiaRechazada, propuesta []uint32, destinatario []common.Address) (event.Subscription, error) {
+
+	var propuestaRule []interface{}
+	for _, propuestaItem := range propuesta {
+		propuestaRule = append(propuestaRule, propuestaItem)
+	}
+	var destinatarioRule []interface{}
+	for _, destinatarioItem := range destinatario {
+		destinatarioRule = append(destinatarioRule, destinatarioItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.WatchLogs(opts, "CustodiaRechazada", propuestaRule, destinatarioRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LoteTracingCustodiaRechazada)
+				if err := _LoteTracing.contract.UnpackLog(event, "CustodiaRechazada", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseCustodiaRechazada is a log parse operation binding the contract event 0x19bd74c7453489924ad3dc0856202b9732a0944e02bb912b4cb399c220891e07.
+//
+// Solidity: event CustodiaRechazada(uint32 indexed propuesta, address indexed destinatario, string motivo)
+func (_LoteTracing *LoteTracingFilterer) ParseCustodiaRechazada(log types.Log) (*LoteTracingCustodiaRechazada, error) {
+	event := new(LoteTracingCustodiaRechazada)
+	if err := _LoteTracing.contract.UnpackLog(event, "CustodiaRechazada", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LoteTracingCustodiaTransferidaIterator is returned from FilterCustodiaTransferida and is used to iterate over the raw logs and unpacked data for CustodiaTransferida events raised by the LoteTracing contract.
+type LoteTracingCustodiaTransferidaIterator struct {
+	Event *LoteTracingCustodiaTransferida // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LoteTracingCustodiaTransferidaIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LoteTracingCustodiaTransferida)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LoteTracingCustodiaTransferida)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LoteTracingCustodiaTransferidaIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LoteTracingCustodiaTransferidaIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LoteTracingCustodiaTransferida represents a CustodiaTransferida event raised by the LoteTracing contract.
+type LoteTracingCustodiaTransferida struct {
+	PropietarioAnterior common.Address
+	NuevoPropietario    common.Address
+	Comprometido        bool
+	Motivo              string
+	Raw                 types.Log // Blockchain specific contextual infos
+}
+
+// FilterCustodiaTransferida is a free log retrieval operation binding the contract event 0x6eb1b8213490de25bc203f2a9ea5a4ff9058d06be46d42380a317522d53d3fbe.
+//
+// Solidity: event CustodiaTransferida(address indexed propietarioAnterior, address indexed nuevoPropietario, bool comprometido, string motivo)
+func (_LoteTracing *LoteTracingFilterer) FilterCustodiaTransferida(opts *bind.FilterOpts, propietarioAnterior []common.Address, nuevoPropietario []common.Address) (*LoteTracingCustodiaTransferidaIterator, error) {
+
+	var propietarioAnteriorRule []interface{}
+	for _, propietarioAnteriorItem := range propietarioAnterior {
+		propietarioAnteriorRule = append(propietarioAnteriorRule, propietarioAnteriorItem)
+	}
+	var nuevoPropietarioRule []interface{}
+	for _, nuevoPropietarioItem := range nuevoPropietario {
+		nuevoPropietarioRule = append(nuevoPropietarioRule, nuevoPropietarioItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.FilterLogs(opts, "CustodiaTransferida", propietarioAnteriorRule, nuevoPropietarioRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingCustodiaTransferidaIterator{contract: _LoteTracing.contract, event: "CustodiaTransferida", logs: logs, sub: sub}, nil
+}
+
+// WatchCustodiaTransferida is a free log subscription operation binding the contract event 0x6eb1b8213490de25bc203f2a9ea5a4ff9058d06be46d42380a317522d53d3fbe.
+//
+// Solidity: event CustodiaTransferida(address indexed propietarioAnterior, address indexed nuevoPropietario, bool comprometido, string motivo)
+func (_LoteTracing *LoteTracingFilterer) WatchCustodiaTransferida(opts *bind.WatchOpts, sink chan<- *LoteTracingCustodiaTransferida, propietarioAnterior []common.Address, nuevoPropietario []common.Address) (event.Subscription, error) {
+
+	var propietarioAnteriorRule []interface{}
+	for _, propietarioAnteriorItem := range propietarioAnterior {
+		propietarioAnteriorRule = append(propietarioAnteriorRule, propietarioAnteriorItem)
+	}
+	var nuevoPropietarioRule []interface{}
+	for _, nuevoPropietarioItem := range nuevoPropietario {
+		nuevoPropietarioRule = append(nuevoPropietarioRule, nuevoPropietarioItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.WatchLogs(opts, "CustodiaTransferida", propietarioAnteriorRule, nuevoPropietarioRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LoteTracingCustodiaTransferida)
+				if err := _LoteTracing.contract.UnpackLog(event, "CustodiaTransferida", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseCustodiaTransferida is a log parse operation binding the contract event 0x6eb1b8213490de25bc203f2a9ea5a4ff9058d06be46d42380a317522d53d3fbe.
+//
+// Solidity: event CustodiaTransferida(address indexed propietarioAnterior, address indexed nuevoPropietario, bool comprometido, string motivo)
+func (_LoteTracing *LoteTracingFilterer) ParseCustodiaTransferida(log types.Log) (*LoteTracingCustodiaTransferida, error) {
+	event := new(LoteTracingCustodiaTransferida)
+	if err := _LoteTracing.contract.UnpackLog(event, "CustodiaTransferida", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LoteTracingLecturasAncladasIterator is returned from FilterLecturasAncladas and is used to iterate over the raw logs and unpacked data for LecturasAncladas events raised by the LoteTracing contract.
+type LoteTracingLecturasAncladasIterator struct {
+	Event *LoteTracingLecturasAncladas // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LoteTracingLecturasAncladasIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LoteTracingLecturasAncladas)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LoteTracingLecturasAncladas)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LoteTracingLecturasAncladasIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LoteTracingLecturasAncladasIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LoteTracingLecturasAncladas represents a LecturasAncladas event raised by the LoteTracing contract.
+type LoteTracingLecturasAncladas struct {
+	Oraculo       common.Address
+	RaizMerkle    [32]byte
+	TempMin       int8
+	TempMax       int8
+	TotalLecturas uint32
+	Desde         uint64
+	Hasta         uint64
+	EnRango       bool
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterLecturasAncladas is a free log retrieval operation binding the contract event 0xe134739a47f9d7603abaecf66751ba2e1d49cb0e8b6c9b24ade8dca68ca7c9c2.
+//
+// Solidity: event LecturasAncladas(address indexed oraculo, bytes32 indexed raizMerkle, int8 tempMin, int8 tempMax, uint32 totalLecturas, uint64 desde, uint64 hasta, bool enRango)
+func (_LoteTracing *LoteTracingFilterer) FilterLecturasAncladas(opts *bind.FilterOpts, oraculo []common.Address, raizMerkle [][32]byte) (*LoteTracingLecturasAncladasIterator, error) {
+
+	var oraculoRule []interface{}
+	for _, oraculoItem := range oraculo {
+		oraculoRule = append(oraculoRule, oraculoItem)
+	}
+	var raizMerkleRule []interface{}
+	for _, raizMerkleItem := range raizMerkle {
+		raizMerkleRule = append(raizMerkleRule, raizMerkleItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.FilterLogs(opts, "LecturasAncladas", oraculoRule, raizMerkleRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingLecturasAncladasIterator{contract: _LoteTracing.contract, event: "LecturasAncladas", logs: logs, sub: sub}, nil
+}
+
+// WatchLecturasAncladas is a free log subscription operation binding the contract event 0xe134739a47f9d7603abaecf66751ba2e1d49cb0e8b6c9b24ade8dca68ca7c9c2.
+//
+// Solidity: event LecturasAncladas(address indexed oraculo, bytes32 indexed raizMerkle, int8 tempMin, int8 tempMax, uint32 totalLecturas, uint64 desde, uint64 hasta, bool enRango)
+func (_LoteTracing *LoteTracingFilterer) WatchLecturasAncladas(opts *bind.WatchOpts, sink chan<- *LoteTracingLecturasAncladas, oraculo []common.Address, raizMerkle [][32]byte) (event.Subscription, error) {
+
+	var oraculoRule []interface{}
+	for _, oraculoItem := range oraculo {
+		oraculoRule = append(oraculoRule, oraculoItem)
+	}
+	var raizMerkleRule []interface{}
+	for _, raizMerkleItem := range raizMerkle {
+		raizMerkleRule = append(raizMerkleRule, raizMerkleItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.WatchLogs(opts, "LecturasAncladas", oraculoRule, raizMerkleRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LoteTracingLecturasAncladas)
+				if err := _LoteTracing.contract.UnpackLog(event, "LecturasAncladas", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseLecturasAncladas is a log parse operation binding the contract event 0xe134739a47f9d7603abaecf66751ba2e1d49cb0e8b6c9b24ade8dca68ca7c9c2.
+//
+// Solidity: event LecturasAncladas(address indexed oraculo, bytes32 indexed raizMerkle, int8 tempMin, int8 tempMax, uint32 totalLecturas, uint64 desde, uint64 hasta, bool enRango)
+func (_LoteTracing *LoteTracingFilterer) ParseLecturasAncladas(log types.Log) (*LoteTracingLecturasAncladas, error) {
+	event := new(LoteTracingLecturasAncladas)
+	if err := _LoteTracing.contract.UnpackLog(event, "LecturasAncladas", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LoteTracingLoteComprometidoIterator is returned from FilterLoteComprometido and is used to iterate over the raw logs and unpacked data for LoteComprometido events raised by the LoteTracing contract.
+type LoteTracingLoteComprometidoIterator struct {
+	Event *LoteTracingLoteComprometido // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LoteTracingLoteComprometidoIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LoteTracingLoteComprometido)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LoteTracingLoteComprometido)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LoteTracingLoteComprometidoIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LoteTracingLoteComprometidoIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LoteTracingLoteComprometido represents a LoteComprometido event raised by the LoteTracing contract.
+type LoteTracingLoteComprometido struct {
+	Propietario  common.Address
+	TempMin      int8
+	TempMax      int8
+	Comprometido bool
+	Motivo       string
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterLoteComprometido is a free log retrieval operation binding the contract event 0x26174a1d6632f37659819648fe37603b6961a9c4af42e0dc262b371b8320d76b.
+//
+// Solidity: event LoteComprometido(address indexed propietario, int8 tempMin, int8 tempMax, bool comprometido, string motivo)
+func (_LoteTracing *LoteTracingFilterer) FilterLoteComprometido(opts *bind.FilterOpts, propietario []common.Address) (*LoteTracingLoteComprometidoIterator, error) {
+
+	var propietarioRule []interface{}
+	for _, propietarioItem := range propietario {
+		propietarioRule = append(propietarioRule, propietarioItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.FilterLogs(opts, "LoteComprometido", propietarioRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingLoteComprometidoIterator{contract: _LoteTracing.contract, event: "LoteComprometido", logs: logs, sub: sub}, nil
+}
+
+// WatchLoteComprometido is a free log subscription operation binding the contract event 0x26174a1d6632f37659819648fe37603b6961a9c4af42e0dc262b371b8320d76b.
+//
+// Solidity: event LoteComprometido(address indexed propietario, int8 tempMin, int8 tempMax, bool comprometido, string motivo)
+func (_LoteTracing *LoteTracingFilterer) WatchLoteComprometido(opts *bind.WatchOpts, sink chan<- *LoteTracingLoteComprometido, propietario []common.Address) (event.Subscription, error) {
+
+	var propietarioRule []interface{}
+	for _, propietarioItem := range propietario {
+		propietarioRule = append(propietarioRule, propietarioItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.WatchLogs(opts, "LoteComprometido", propietarioRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LoteTracingLoteComprometido)
+				if err := _LoteTracing.contract.UnpackLog(event, "LoteComprometido", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseLoteComprometido is a log parse operation binding the contract event 0x26174a1d6632f37659819648fe37603b6961a9c4af42e0dc262b371b8320d76b.
+//
+// Solidity: event LoteComprometido(address indexed propietario, int8 tempMin, int8 tempMax, bool comprometido, string motivo)
+func (_LoteTracing *LoteTracingFilterer) ParseLoteComprometido(log types.Log) (*LoteTracingLoteComprometido, error) {
+	event := new(LoteTracingLoteComprometido)
+	if err := _LoteTracing.contract.UnpackLog(event, "LoteComprometido", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LoteTracingLoteCreadoIterator is returned from FilterLoteCreado and is used to iterate over the raw logs and unpacked data for LoteCreado events raised by the LoteTracing contract.
+type LoteTracingLoteCreadoIterator struct {
+	Event *LoteTracingLoteCreado // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LoteTracingLoteCreadoIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LoteTracingLoteCreado)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LoteTracingLoteCreado)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LoteTracingLoteCreadoIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LoteTracingLoteCreadoIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LoteTracingLoteCreado represents a LoteCreado event raised by the LoteTracing contract.
+type LoteTracingLoteCreado struct {
+	LoteId            common.Hash
+	Fabricante        common.Address
+	TemperaturaMinima int8
+	TemperaturaMaxima int8
+	Motivo            string
+	Raw               types.Log // Blockchain specific contextual infos
+}
+
+// FilterLoteCreado is a free log retrieval operation binding the contract event 0xc144c2e3dd9d75c64005477d5ae1809dc476485e047bae2a8bce83faa249cf24.
+//
+// Solidity: event LoteCreado(string indexed loteId, address indexed fabricante, int8 temperaturaMinima, int8 temperaturaMaxima, string motivo)
+func (_LoteTracing *LoteTracingFilterer) FilterLoteCreado(opts *bind.FilterOpts, loteId []string, fabricante []common.Address) (*LoteTracingLoteCreadoIterator, error) {
+
+	var loteIdRule []interface{}
+	for _, loteIdItem := range loteId {
+		loteIdRule = append(loteIdRule, loteIdItem)
+	}
+	var fabricanteRule []interface{}
+	for _, fabricanteItem := range fabricante {
+		fabricanteRule = append(fabricanteRule, fabricanteItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.FilterLogs(opts, "LoteCreado", loteIdRule, fabricanteRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingLoteCreadoIterator{contract: _LoteTracing.contract, event: "LoteCreado", logs: logs, sub: sub}, nil
+}
+
+// WatchLoteCreado is a free log subscription operation binding the contract event 0xc144c2e3dd9d75c64005477d5ae1809dc476485e047bae2a8bce83faa249cf24.
+//
+// Solidity: event LoteCreado(string indexed loteId, address indexed fabricante, int8 temperaturaMinima, int8 temperaturaMaxima, string motivo)
+func (_LoteTracing *LoteTracingFilterer) WatchLoteCreado(opts *bind.WatchOpts, sink chan<- *LoteTracingLoteCreado, loteId []string, fabricante []common.Address) (event.Subscription, error) {
+
+	var loteIdRule []interface{}
+	for _, loteIdItem := range loteId {
+		loteIdRule = append(loteIdRule, loteIdItem)
+	}
+	var fabricanteRule []interface{}
+	for _, fabricanteItem := range fabricante {
+		fabricanteRule = append(fabricanteRule, fabricanteItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.WatchLogs(opts, "LoteCreado", loteIdRule, fabricanteRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LoteTracingLoteCreado)
+				if err := _LoteTracing.contract.UnpackLog(event, "LoteCreado", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseLoteCreado is a log parse operation binding the contract event 0xc144c2e3dd9d75c64005477d5ae1809dc476485e047bae2a8bce83faa249cf24.
+//
+// Solidity: event LoteCreado(string indexed loteId, address indexed fabricante, int8 temperaturaMinima, int8 temperaturaMaxima, string motivo)
+func (_LoteTracing *LoteTracingFilterer) ParseLoteCreado(log types.Log) (*LoteTracingLoteCreado, error) {
+	event := new(LoteTracingLoteCreado)
+	if err := _LoteTracing.contract.UnpackLog(event, "LoteCreado", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LoteTracingRolOtorgadoIterator is returned from FilterRolOtorgado and is used to iterate over the raw logs and unpacked data for RolOtorgado events raised by the LoteTracing contract.
+type LoteTracingRolOtorgadoIterator struct {
+	Event *LoteTracingRolOtorgado // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LoteTracingRolOtorgadoIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LoteTracingRolOtorgado)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LoteTracingRolOtorgado)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LoteTracingRolOtorgadoIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LoteTracingRolOtorgadoIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LoteTracingRolOtorgado represents a RolOtorgado event raised by the LoteTracing contract.
+type LoteTracingRolOtorgado struct {
+	Rol    [32]byte
+	Cuenta common.Address
+	Admin  common.Address
+	Raw    types.Log // Blockchain specific contextual infos
+}
+
+// FilterRolOtorgado is a free log retrieval operation binding the contract event 0x5e212e0516f5eb70b18b4a50f0f4548dcff7cef8a35fc67114244716bdc9f1a5.
+//
+// Solidity: event RolOtorgado(bytes32 indexed rol, address indexed cuenta, address indexed admin)
+func (_LoteTracing *LoteTracingFilterer) FilterRolOtorgado(opts *bind.FilterOpts, rol [][32]byte, cuenta []common.Address, admin []common.Address) (*LoteTracingRolOtorgadoIterator, error) {
+
+	var rolRule []interface{}
+	for _, rolItem := range rol {
+		rolRule = append(rolRule, rolItem)
+	}
+	var cuentaRule []interface{}
+	for _, cuentaItem := range cuenta {
+		cuentaRule = append(cuentaRule, cuentaItem)
+	}
+	var adminRule []interface{}
+	for _, adminItem := range admin {
+		adminRule = append(adminRule, adminItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.FilterLogs(opts, "RolOtorgado", rolRule, cuentaRule, adminRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingRolOtorgadoIterator{contract: _LoteTracing.contract, event: "RolOtorgado", logs: logs, sub: sub}, nil
+}
+
+// WatchRolOtorgado is a free log subscription operation binding the contract event 0x5e212e0516f5eb70b18b4a50f0f4548dcff7cef8a35fc67114244716bdc9f1a5.
+//
+// Solidity: event RolOtorgado(bytes32 indexed rol, address indexed cuenta, address indexed admin)
+func (_LoteTracing *LoteTracingFilterer) WatchRolOtorgado(opts *bind.WatchOpts, sink chan<- *LoteTracingRolOtorgado, rol [][32]byte, cuenta []common.Address, admin []common.Address) (event.Subscription, error) {
+
+	var rolRule []interface{}
+	for _, rolItem := range rol {
+		rolRule = append(rolRule, rolItem)
+	}
+	var cuentaRule []interface{}
+	for _, cuentaItem := range cuenta {
+		cuentaRule = append(cuentaRule, cuentaItem)
+	}
+	var adminRule []interface{}
+	for _, adminItem := range admin {
+		adminRule = append(adminRule, adminItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.WatchLogs(opts, "RolOtorgado", rolRule, cuentaRule, adminRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LoteTracingRolOtorgado)
+				if err := _LoteTracing.contract.UnpackLog(event, "RolOtorgado", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseRolOtorgado is a log parse operation binding the contract event 0x5e212e0516f5eb70b18b4a50f0f4548dcff7cef8a35fc67114244716bdc9f1a5.
+//
+// Solidity: event RolOtorgado(bytes32 indexed rol, address indexed cuenta, address indexed admin)
+func (_LoteTracing *LoteTracingFilterer) ParseRolOtorgado(log types.Log) (*LoteTracingRolOtorgado, error) {
+	event := new(LoteTracingRolOtorgado)
+	if err := _LoteTracing.contract.UnpackLog(event, "RolOtorgado", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LoteTracingRolRevocadoIterator is returned from FilterRolRevocado and is used to iterate over the raw logs and unpacked data for RolRevocado events raised by the LoteTracing contract.
+type LoteTracingRolRevocadoIterator struct {
+	Event *LoteTracingRolRevocado // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LoteTracingRolRevocadoIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LoteTracingRolRevocado)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LoteTracingRolRevocado)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LoteTracingRolRevocadoIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LoteTracingRolRevocadoIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LoteTracingRolRevocado represents a RolRevocado event raised by the LoteTracing contract.
+type LoteTracingRolRevocado struct {
+	Rol    [32]byte
+	Cuenta common.Address
+	Admin  common.Address
+	Raw    types.Log // Blockchain specific contextual infos
+}
+
+// FilterRolRevocado is a free log retrieval operation binding the contract event 0x0de29865220d629a87a2d6905a4847aabf59e478cc2ecacffdd9567946184a54.
+//
+// Solidity: event RolRevocado(bytes32 indexed rol, address indexed cuenta, address indexed admin)
+func (_LoteTracing *LoteTracingFilterer) FilterRolRevocado(opts *bind.FilterOpts, rol [][32]byte, cuenta []common.Address, admin []common.Address) (*LoteTracingRolRevocadoIterator, error) {
+
+	var rolRule []interface{}
+	for _, rolItem := range rol {
+		rolRule = append(rolRule, rolItem)
+	}
+	var cuentaRule []interface{}
+	for _, cuentaItem := range cuenta {
+		cuentaRule = append(cuentaRule, cuentaItem)
+	}
+	var adminRule []interface{}
+	for _, adminItem := range admin {
+		adminRule = append(adminRule, adminItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.FilterLogs(opts, "RolRevocado", rolRule, cuentaRule, adminRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingRolRevocadoIterator{contract: _LoteTracing.contract, event: "RolRevocado", logs: logs, sub: sub}, nil
+}
+
+// WatchRolRevocado is a free log subscription operation binding the contract event 0x0de29865220d629a87a2d6905a4847aabf59e478cc2ecacffdd9567946184a54.
+//
+// Solidity: event RolRevocado(bytes32 indexed rol, address indexed cuenta, address indexed admin)
+func (_LoteTracing *LoteTracingFilterer) WatchRolRevocado(opts *bind.WatchOpts, sink chan<- *LoteTracingRolRevocado, rol [][32]byte, cuenta []common.Address, admin []common.Address) (event.Subscription, error) {
+
+	var rolRule []interface{}
+	for _, rolItem := range rol {
+		rolRule = append(rolRule, rolItem)
+	}
+	var cuentaRule []interface{}
+	for _, cuentaItem := range cuenta {
+		cuentaRule = append(cuentaRule, cuentaItem)
+	}
+	var adminRule []interface{}
+	for _, adminItem := range admin {
+		adminRule = append(adminRule, adminItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.WatchLogs(opts, "RolRevocado", rolRule, cuentaRule, adminRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LoteTracingRolRevocado)
+				if err := _LoteTracing.contract.UnpackLog(event, "RolRevocado", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseRolRevocado is a log parse operation binding the contract event 0x0de29865220d629a87a2d6905a4847aabf59e478cc2ecacffdd9567946184a54.
+//
+// Solidity: event RolRevocado(bytes32 indexed rol, address indexed cuenta, address indexed admin)
+func (_LoteTracing *LoteTracingFilterer) ParseRolRevocado(log types.Log) (*LoteTracingRolRevocado, error) {
+	event := new(LoteTracingRolRevocado)
+	if err := _LoteTracing.contract.UnpackLog(event, "RolRevocado", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LoteTracingTemperaturaRegistradaIterator is returned from FilterTemperaturaRegistrada and is used to iterate over the raw logs and unpacked data for TemperaturaRegistrada events raised by the LoteTracing contract.
+type LoteTracingTemperaturaRegistradaIterator struct {
+	Event *LoteTracingTemperaturaRegistrada // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LoteTracingTemperaturaRegistradaIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LoteTracingTemperaturaRegistrada)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LoteTracingTemperaturaRegistrada)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LoteTracingTemperaturaRegistradaIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LoteTracingTemperaturaRegistradaIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LoteTracingTemperaturaRegistrada represents a TemperaturaRegistrada event raised by the LoteTracing contract.
+type LoteTracingTemperaturaRegistrada struct {
+	Oraculo   common.Address
+	SensorId  string
+	TempMin   int8
+	TempMax   int8
+	EnRango   bool
+	Timestamp *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterTemperaturaRegistrada is a free log retrieval operation binding the contract event 0x345281d77e0fd6c1a457f709d18f3162796f1a315cebb4062e9338ae8915d615.
+//
+// Solidity: event TemperaturaRegistrada(address indexed oraculo, string sensorId, int8 tempMin, int8 tempMax, bool enRango, uint256 timestamp)
+func (_LoteTracing *LoteTracingFilterer) FilterTemperaturaRegistrada(opts *bind.FilterOpts, oraculo []common.Address) (*LoteTracingTemperaturaRegistradaIterator, error) {
+
+	var oraculoRule []interface{}
+	for _, oraculoItem := range oraculo {
+		oraculoRule = append(oraculoRule, oraculoItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.FilterLogs(opts, "TemperaturaRegistrada", oraculoRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingTemperaturaRegistradaIterator{contract: _LoteTracing.contract, event: "TemperaturaRegistrada", logs: logs, sub: sub}, nil
+}
+
+// WatchTemperaturaRegistrada is a free log subscription operation binding the contract event 0x345281d77e0fd6c1a457f709d18f3162796f1a315cebb4062e9338ae8915d615.
+//
+// Solidity: event TemperaturaRegistrada(address indexed oraculo, string sensorId, int8 tempMin, int8 tempMax, bool enRango, uint256 timestamp)
+func (_LoteTracing *LoteTracingFilterer) WatchTemperaturaRegistrada(opts *bind.WatchOpts, sink chan<- *LoteTracingTemperaturaRegistrada, oraculo []common.Address) (event.Subscription, error) {
+
+	var oraculoRule []interface{}
+	for _, oraculoItem := range oraculo {
+		oraculoRule = append(oraculoRule, oraculoItem)
+	}
+
+	logs, sub, err := _LoteTracing.contract.WatchLogs(opts, "TemperaturaRegistrada", oraculoRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LoteTracingTemperaturaRegistrada)
+				if err := _LoteTracing.contract.UnpackLog(event, "TemperaturaRegistrada", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseTemperaturaRegistrada is a log parse operation binding the contract event 0x345281d77e0fd6c1a457f709d18f3162796f1a315cebb4062e9338ae8915d615.
+//
+// Solidity: event TemperaturaRegistrada(address indexed oraculo, string sensorId, int8 tempMin, int8 tempMax, bool enRango, uint256 timestamp)
+func (_LoteTracing *LoteTracingFilterer) ParseTemperaturaRegistrada(log types.Log) (*LoteTracingTemperaturaRegistrada, error) {
+	event := new(LoteTracingTemperaturaRegistrada)
+	if err := _LoteTracing.contract.UnpackLog(event, "TemperaturaRegistrada", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
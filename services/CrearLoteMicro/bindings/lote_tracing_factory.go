@@ -0,0 +1,1298 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package bindings
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// LoteTracingFactoryMetaData contains all meta data concerning the LoteTracingFactory contract.
+var LoteTracingFactoryMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"adminAnterior\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"nuevoAdmin\",\"type\":\"address\"}],\"name\":\"AdminTransferido\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"string\",\"name\":\"loteId\",\"type\":\"string\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"propietarioAnterior\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"nuevoPropietario\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"comprometido\",\"type\":\"bool\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"motivo\",\"type\":\"string\"}],\"name\":\"CustodiaTransferida\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"cuenta\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"autorizado\",\"type\":\"bool\"}],\"name\":\"FabricanteAutorizado\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"string\",\"name\":\"loteId\",\"type\":\"string\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"propietario\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"int8\",\"name\":\"tempMin\",\"type\":\"int8\"},{\"indexed\":false,\"internalType\":\"int8\",\"name\":\"tempMax\",\"type\":\"int8\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"comprometido\",\"type\":\"bool\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"motivo\",\"type\":\"string\"}],\"name\":\"LoteComprometido\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"string\",\"name\":\"loteId\",\"type\":\"string\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"fabricante\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"int8\",\"name\":\"temperaturaMinima\",\"type\":\"int8\"},{\"indexed\":false,\"internalType\":\"int8\",\"name\":\"temperaturaMaxima\",\"type\":\"int8\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"motivo\",\"type\":\"string\"}],\"name\":\"LoteCreado\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"admin\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_cuenta\",\"type\":\"address\"},{\"internalType\":\"bool\",\"name\":\"_autorizado\",\"type\":\"bool\"}],\"name\":\"autorizarFabricante\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_loteId\",\"type\":\"string\"},{\"internalType\":\"int8\",\"name\":\"_tempMin\",\"type\":\"int8\"},{\"internalType\":\"int8\",\"name\":\"_tempMax\",\"type\":\"int8\"}],\"name\":\"crearLote\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_loteId\",\"type\":\"string\"}],\"name\":\"existeLote\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"fabricantes\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_loteId\",\"type\":\"string\"}],\"name\":\"obtenerLote\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"fabricante\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"propietarioActual\",\"type\":\"address\"},{\"internalType\":\"int8\",\"name\":\"temperaturaMinima\",\"type\":\"int8\"},{\"internalType\":\"int8\",\"name\":\"temperaturaMaxima\",\"type\":\"int8\"},{\"internalType\":\"int8\",\"name\":\"tempRegMinima\",\"type\":\"int8\"},{\"internalType\":\"int8\",\"name\":\"tempRegMaxima\",\"type\":\"int8\"},{\"internalType\":\"bool\",\"name\":\"comprometido\",\"type\":\"bool\"},{\"internalType\":\"uint64\",\"name\":\"bloqueCreacion\",\"type\":\"uint64\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"},{\"internalType\":\"int8\",\"name\":\"\",\"type\":\"int8\"},{\"internalType\":\"int8\",\"name\":\"\",\"type\":\"int8\"}],\"name\":\"registrarTemperatura\",\"outputs\":[],\"stateMutability\":\"pure\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"totalLotes\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_nuevoAdmin\",\"type\":\"address\"}],\"name\":\"transferirAdmin\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"},{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"transferirCustodia\",\"outputs\":[],\"stateMutability\":\"pure\",\"type\":\"function\"}]",
+	Bin: "0x6108006080523461008f573360005560013360005260016020526040600020553360007f2e1a6fe73e94eadae803896e18a2db28d96e0c2bbfb1c6d0e6132a56bc35752d6000600090a3337f4503f7a399337ed9d65012c24e7d6d80d5b79fd0a9f647e4eec0d9982659f460602060805180910160805260018160000152602090a261075f806100946000396000f35b600080fd6108006080523461054b576004361061054b5760003560e01c6104d8565b60005460005260206000f35b6004358060a01c61054b57600052600160205260406000205460005260206000f35b60035460005260206000f35b600435806801000000000000000090101561054b576004018035806801000000000000000090101561054b578060a052906020018060c05201361061054b5760a051601f01601f19166080518091016080528060e05260a05160c05182375060a05160e0512080610100526000526002602052604060002061012052610120515473ffffffffffffffffffffffffffffffffffffffff16151560005260206000f35b600435806801000000000000000090101561054b576004018035806801000000000000000090101561054b578060a052906020018060c05201361061054b5760a051601f01601f19166080518091016080528060e05260a05160c05182375060a05160e0512080610100526000526002602052604060002061012052610120515473ffffffffffffffffffffffffffffffffffffffff1615610550576101205154610140526101205160010154610160526101006080518091016080526101405173ffffffffffffffffffffffffffffffffffffffff1681600001526101605173ffffffffffffffffffffffffffffffffffffffff1681602001526101605160a01c60ff1660000b81604001526101605160a81c60ff1660000b81606001526101605160b01c60ff1660000b81608001526101605160b81c60ff1660000b8160a001526101605160c01c60ff168160c001526101405160a01c67ffffffffffffffff168160e0015261010090f35b6004358060a01c61054b57610180526024358060011061054b576101a0523360005414156105845761018051156105d5576101a051610180516000526001602052604060002055610180517f4503f7a399337ed9d65012c24e7d6d80d5b79fd0a9f647e4eec0d9982659f46060206080518091016080526101a0518160000152602090a2005b6004358060a01c61054b57610180523360005414156105845761018051156105d55761018051337f2e1a6fe73e94eadae803896e18a2db28d96e0c2bbfb1c6d0e6132a56bc35752d6000600090a361018051600055005b600435806801000000000000000090101561054b576004018035806801000000000000000090101561054b578060a052906020018060c05201361061054b57602435808060000b141561054b576101c052604435808060000b141561054b576101e052336000526001602052604060002054156106095760a05115610653576101e0516101c051136106935760a051601f01601f19166080518091016080528060e05260a05160c05182375060a05160e0512080610100526000526002602052604060002061012052610120515473ffffffffffffffffffffffffffffffffffffffff166106d2574360a01b331761012051556101e05160ff1660a81b6101c05160ff1660a01b173317610120516001015560035460010160035533610100517fc144c2e3dd9d75c64005477d5ae1809dc476485e047bae2a8bce83faa249cf2460a06080518091016080526101c05181600001526101e051816020015260608160400152600b81606001526a4c6f74652043726561646f60a81b816080015260a090a3005b6001610705575b6001610705575b8063f851a4401461001d578063b41371c1146100295780633855a3731461004b57806374165bd514610057578063e71a8124146100f95780635657c08714610267578063bbe99a1e146102ed5780634a2feee814610344578063fccd0b6d146104ca5780639fc1c384146104d15761054b565b600080fd5b6308c379a060e01b60005260206004526012602452714c6f7465206e6f20656e636f6e747261646f60701b60445260646000fd5b6308c379a060e01b6000526020600452602b6024527f416363696f6e20736f6c6f207065726d6974696461207061726120656c2061646044526a6d696e6973747261646f7260a81b60645260846000fd5b6308c379a060e01b6000526020600452601260245271446972656363696f6e20696e76616c69646160701b60445260646000fd5b6308c379a060e01b600052602060045260246024527f4375656e7461206e6f206175746f72697a61646120636f6d6f2066616272696360445263616e746560e01b60645260846000fd5b6308c379a060e01b6000526020600452601e6024527d456c206c6f74654964206e6f20707565646520657374617220766163696f60101b60445260646000fd5b6308c379a060e01b6000526020600452601d6024527c52616e676f2064652074656d706572617475726120696e76616c69646f60181b60445260646000fd5b6308c379a060e01b6000526020600452601160245270456c206c6f74652079612065786973746560781b60445260646000fd5b6308c379a060e01b600052602060045260346024527f4c6f746573206465206c6120666163746f727920646520736f6c6f206c656374604452737572613a20757365204c6f746554726163696e6760601b60645260846000fd",
+}
+
+// LoteTracingFactoryABI is the input ABI used to generate the binding from.
+// Deprecated: Use LoteTracingFactoryMetaData.ABI instead.
+var LoteTracingFactoryABI = LoteTracingFactoryMetaData.ABI
+
+// LoteTracingFactoryBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use LoteTracingFactoryMetaData.Bin instead.
+var LoteTracingFactoryBin = LoteTracingFactoryMetaData.Bin
+
+// DeployLoteTracingFactory deploys a new Ethereum contract, binding an instance of LoteTracingFactory to it.
+func DeployLoteTracingFactory(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *LoteTracingFactory, error) {
+	parsed, err := LoteTracingFactoryMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(LoteTracingFactoryBin), backend)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &LoteTracingFactory{LoteTracingFactoryCaller: LoteTracingFactoryCaller{contract: contract}, LoteTracingFactoryTransactor: LoteTracingFactoryTransactor{contract: contract}, LoteTracingFactoryFilterer: LoteTracingFactoryFilterer{contract: contract}}, nil
+}
+
+// LoteTracingFactory is an auto generated Go binding around an Ethereum contract.
+type LoteTracingFactory struct {
+	LoteTracingFactoryCaller     // Read-only binding to the contract
+	LoteTracingFactoryTransactor // Write-only binding to the contract
+	LoteTracingFactoryFilterer   // Log filterer for contract events
+}
+
+// LoteTracingFactoryCaller is an auto generated read-only Go binding around an Ethereum contract.
+type LoteTracingFactoryCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// LoteTracingFactoryTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type LoteTracingFactoryTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// LoteTracingFactoryFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type LoteTracingFactoryFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// LoteTracingFactorySession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type LoteTracingFactorySession struct {
+	Contract     *LoteTracingFactory // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts       // Call options to use throughout this session
+	TransactOpts bind.TransactOpts   // Transaction auth options to use throughout this session
+}
+
+// LoteTracingFactoryCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type LoteTracingFactoryCallerSession struct {
+	Contract *LoteTracingFactoryCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts             // Call options to use throughout this session
+}
+
+// LoteTracingFactoryTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type LoteTracingFactoryTransactorSession struct {
+	Contract     *LoteTracingFactoryTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts             // Transaction auth options to use throughout this session
+}
+
+// LoteTracingFactoryRaw is an auto generated low-level Go binding around an Ethereum contract.
+type LoteTracingFactoryRaw struct {
+	Contract *LoteTracingFactory // Generic contract binding to access the raw methods on
+}
+
+// LoteTracingFactoryCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type LoteTracingFactoryCallerRaw struct {
+	Contract *LoteTracingFactoryCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// LoteTracingFactoryTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type LoteTracingFactoryTransactorRaw struct {
+	Contract *LoteTracingFactoryTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewLoteTracingFactory creates a new instance of LoteTracingFactory, bound to a specific deployed contract.
+func NewLoteTracingFactory(address common.Address, backend bind.ContractBackend) (*LoteTracingFactory, error) {
+	contract, err := bindLoteTracingFactory(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingFactory{LoteTracingFactoryCaller: LoteTracingFactoryCaller{contract: contract}, LoteTracingFactoryTransactor: LoteTracingFactoryTransactor{contract: contract}, LoteTracingFactoryFilterer: LoteTracingFactoryFilterer{contract: contract}}, nil
+}
+
+// NewLoteTracingFactoryCaller creates a new read-only instance of LoteTracingFactory, bound to a specific deployed contract.
+func NewLoteTracingFactoryCaller(address common.Address, caller bind.ContractCaller) (*LoteTracingFactoryCaller, error) {
+	contract, err := bindLoteTracingFactory(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingFactoryCaller{contract: contract}, nil
+}
+
+// NewLoteTracingFactoryTransactor creates a new write-only instance of LoteTracingFactory, bound to a specific deployed contract.
+func NewLoteTracingFactoryTransactor(address common.Address, transactor bind.ContractTransactor) (*LoteTracingFactoryTransactor, error) {
+	contract, err := bindLoteTracingFactory(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingFactoryTransactor{contract: contract}, nil
+}
+
+// NewLoteTracingFactoryFilterer creates a new log filterer instance of LoteTracingFactory, bound to a specific deployed contract.
+func NewLoteTracingFactoryFilterer(address common.Address, filterer bind.ContractFilterer) (*LoteTracingFactoryFilterer, error) {
+	contract, err := bindLoteTracingFactory(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingFactoryFilterer{contract: contract}, nil
+}
+
+// bindLoteTracingFactory binds a generic wrapper to an already deployed contract.
+func bindLoteTracingFactory(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := LoteTracingFactoryMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_LoteTracingFactory *LoteTracingFactoryRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _LoteTracingFactory.Contract.LoteTracingFactoryCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_LoteTracingFactory *LoteTracingFactoryRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _LoteTracingFactory.Contract.LoteTracingFactoryTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_LoteTracingFactory *LoteTracingFactoryRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _LoteTracingFactory.Contract.LoteTracingFactoryTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_LoteTracingFactory *LoteTracingFactoryCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _LoteTracingFactory.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_LoteTracingFactory *LoteTracingFactoryTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _LoteTracingFactory.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_LoteTracingFactory *LoteTracingFactoryTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _LoteTracingFactory.Contract.contract.Transact(opts, method, params...)
+}
+
+// Admin is a free data retrieval call binding the contract method 0xf851a440.
+//
+// Solidity: function admin() view returns(address)
+func (_LoteTracingFactory *LoteTracingFactoryCaller) Admin(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _LoteTracingFactory.contract.Call(opts, &out, "admin")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Admin is a free data retrieval call binding the contract method 0xf851a440.
+//
+// Solidity: function admin() view returns(address)
+func (_LoteTracingFactory *LoteTracingFactorySession) Admin() (common.Address, error) {
+	return _LoteTracingFactory.Contract.Admin(&_LoteTracingFactory.CallOpts)
+}
+
+// Admin is a free data retrieval call binding the contract method 0xf851a440.
+//
+// Solidity: function admin() view returns(address)
+func (_LoteTracingFactory *LoteTracingFactoryCallerSession) Admin() (common.Address, error) {
+	return _LoteTracingFactory.Contract.Admin(&_LoteTracingFactory.CallOpts)
+}
+
+// ExisteLote is a free data retrieval call binding the contract method 0x74165bd5.
+//
+// Solidity: function existeLote(string _loteId) view returns(bool)
+func (_LoteTracingFactory *LoteTracingFactoryCaller) ExisteLote(opts *bind.CallOpts, _loteId string) (bool, error) {
+	var out []interface{}
+	err := _LoteTracingFactory.contract.Call(opts, &out, "existeLote", _loteId)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// ExisteLote is a free data retrieval call binding the contract method 0x74165bd5.
+//
+// Solidity: function existeLote(string _loteId) view returns(bool)
+func (_LoteTracingFactory *LoteTracingFactorySession) ExisteLote(_loteId string) (bool, error) {
+	return _LoteTracingFactory.Contract.ExisteLote(&_LoteTracingFactory.CallOpts, _loteId)
+}
+
+// ExisteLote is a free data retrieval call binding the contract method 0x74165bd5.
+//
+// Solidity: function existeLote(string _loteId) view returns(bool)
+func (_LoteTracingFactory *LoteTracingFactoryCallerSession) ExisteLote(_loteId string) (bool, error) {
+	return _LoteTracingFactory.Contract.ExisteLote(&_LoteTracingFactory.CallOpts, _loteId)
+}
+
+// Fabricantes is a free data retrieval call binding the contract method 0xb41371c1.
+//
+// Solidity: function fabricantes(address ) view returns(bool)
+func (_LoteTracingFactory *LoteTracingFactoryCaller) Fabricantes(opts *bind.CallOpts, arg0 common.Address) (bool, error) {
+	var out []interface{}
+	err := _LoteTracingFactory.contract.Call(opts, &out, "fabricantes", arg0)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// Fabricantes is a free data retrieval call binding the contract method 0xb41371c1.
+//
+// Solidity: function fabricantes(address ) view returns(bool)
+func (_LoteTracingFactory *LoteTracingFactorySession) Fabricantes(arg0 common.Address) (bool, error) {
+	return _LoteTracingFactory.Contract.Fabricantes(&_LoteTracingFactory.CallOpts, arg0)
+}
+
+// Fabricantes is a free data retrieval call binding the contract method 0xb41371c1.
+//
+// Solidity: function fabricantes(address ) view returns(bool)
+func (_LoteTracingFactory *LoteTracingFactoryCallerSession) Fabricantes(arg0 common.Address) (bool, error) {
+	return _LoteTracingFactory.Contract.Fabricantes(&_LoteTracingFactory.CallOpts, arg0)
+}
+
+// ObtenerLote is a free data retrieval call binding the contract method 0xe71a8124.
+//
+// Solidity: function obtenerLote(string _loteId) view returns(address fabricante, address propietarioActual, int8 temperaturaMinima, int8 temperaturaMaxima, int8 tempRegMinima, int8 tempRegMaxima, bool comprometido, uint64 bloqueCreacion)
+func (_LoteTracingFactory *LoteTracingFactoryCaller) ObtenerLote(opts *bind.CallOpts, _loteId string) (struct {
+	Fabricante        common.Address
+	PropietarioActual common.Address
+	TemperaturaMinima int8
+	TemperaturaMaxima int8
+	TempRegMinima     int8
+	TempRegMaxima     int8
+	Comprometido      bool
+	BloqueCreacion    uint64
+}, error) {
+	var out []interface{}
+	err := _LoteTracingFactory.contract.Call(opts, &out, "obtenerLote", _loteId)
+
+	outstruct := new(struct {
+		Fabricante        common.Address
+		PropietarioActual common.Address
+		TemperaturaMinima int8
+		TemperaturaMaxima int8
+		TempRegMinima     int8
+		TempRegMaxima     int8
+		Comprometido      bool
+		BloqueCreacion    uint64
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Fabricante = *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	outstruct.PropietarioActual = *abi.ConvertType(out[1], new(common.Address)).(*common.Address)
+	outstruct.TemperaturaMinima = *abi.ConvertType(out[2], new(int8)).(*int8)
+	outstruct.TemperaturaMaxima = *abi.ConvertType(out[3], new(int8)).(*int8)
+	outstruct.TempRegMinima = *abi.ConvertType(out[4], new(int8)).(*int8)
+	outstruct.TempRegMaxima = *abi.ConvertType(out[5], new(int8)).(*int8)
+	outstruct.Comprometido = *abi.ConvertType(out[6], new(bool)).(*bool)
+	outstruct.BloqueCreacion = *abi.ConvertType(out[7], new(uint64)).(*uint64)
+
+	return *outstruct, err
+
+}
+
+// ObtenerLote is a free data retrieval call binding the contract method 0xe71a8124.
+//
+// Solidity: function obtenerLote(string _loteId) view returns(address fabricante, address propietarioActual, int8 temperaturaMinima, int8 temperaturaMaxima, int8 tempRegMinima, int8 tempRegMaxima, bool comprometido, uint64 bloqueCreacion)
+func (_LoteTracingFactory *LoteTracingFactorySession) ObtenerLote(_loteId string) (struct {
+	Fabricante        common.Address
+	PropietarioActual common.Address
+	TemperaturaMinima int8
+	TemperaturaMaxima int8
+	TempRegMinima     int8
+	TempRegMaxima     int8
+	Comprometido      bool
+	BloqueCreacion    uint64
+}, error) {
+	return _LoteTracingFactory.Contract.ObtenerLote(&_LoteTracingFactory.CallOpts, _loteId)
+}
+
+// ObtenerLote is a free data retrieval call binding the contract method 0xe71a8124.
+//
+// Solidity: function obtenerLote(string _loteId) view returns(address fabricante, address propietarioActual, int8 temperaturaMinima, int8 temperaturaMaxima, int8 tempRegMinima, int8 tempRegMaxima, bool comprometido, uint64 bloqueCreacion)
+func (_LoteTracingFactory *LoteTracingFactoryCallerSession) ObtenerLote(_loteId string) (struct {
+	Fabricante        common.Address
+	PropietarioActual common.Address
+	TemperaturaMinima int8
+	TemperaturaMaxima int8
+	TempRegMinima     int8
+	TempRegMaxima     int8
+	Comprometido      bool
+	BloqueCreacion    uint64
+}, error) {
+	return _LoteTracingFactory.Contract.ObtenerLote(&_LoteTracingFactory.CallOpts, _loteId)
+}
+
+// RegistrarTemperatura is a free data retrieval call binding the contract method 0xfccd0b6d.
+//
+// Solidity: function registrarTemperatura(string , int8 , int8 ) pure returns()
+func (_LoteTracingFactory *LoteTracingFactoryCaller) RegistrarTemperatura(opts *bind.CallOpts, arg0 string, arg1 int8, arg2 int8) error {
+	var out []interface{}
+	err := _LoteTracingFactory.contract.Call(opts, &out, "registrarTemperatura", arg0, arg1, arg2)
+
+	if err != nil {
+		return err
+	}
+
+	return err
+
+}
+
+// RegistrarTemperatura is a free data retrieval call binding the contract method 0xfccd0b6d.
+//
+// Solidity: function registrarTemperatura(string , int8 , int8 ) pure returns()
+func (_LoteTracingFactory *LoteTracingFactorySession) RegistrarTemperatura(arg0 string, arg1 int8, arg2 int8) error {
+	return _LoteTracingFactory.Contract.RegistrarTemperatura(&_LoteTracingFactory.CallOpts, arg0, arg1, arg2)
+}
+
+// RegistrarTemperatura is a free data retrieval call binding the contract method 0xfccd0b6d.
+//
+// Solidity: function registrarTemperatura(string , int8 , int8 ) pure returns()
+func (_LoteTracingFactory *LoteTracingFactoryCallerSession) RegistrarTemperatura(arg0 string, arg1 int8, arg2 int8) error {
+	return _LoteTracingFactory.Contract.RegistrarTemperatura(&_LoteTracingFactory.CallOpts, arg0, arg1, arg2)
+}
+
+// TotalLotes is a free data retrieval call binding the contract method 0x3855a373.
+//
+// Solidity: function totalLotes() view returns(uint256)
+func (_LoteTracingFactory *LoteTracingFactoryCaller) TotalLotes(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _LoteTracingFactory.contract.Call(opts, &out, "totalLotes")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// TotalLotes is a free data retrieval call binding the contract method 0x3855a373.
+//
+// Solidity: function totalLotes() view returns(uint256)
+func (_LoteTracingFactory *LoteTracingFactorySession) TotalLotes() (*big.Int, error) {
+	return _LoteTracingFactory.Contract.TotalLotes(&_LoteTracingFactory.CallOpts)
+}
+
+// TotalLotes is a free data retrieval call binding the contract method 0x3855a373.
+//
+// Solidity: function totalLotes() view returns(uint256)
+func (_LoteTracingFactory *LoteTracingFactoryCallerSession) TotalLotes() (*big.Int, error) {
+	return _LoteTracingFactory.Contract.TotalLotes(&_LoteTracingFactory.CallOpts)
+}
+
+// TransferirCustodia is a free data retrieval call binding the contract method 0x9fc1c384.
+//
+// Solidity: function transferirCustodia(string , address ) pure returns()
+func (_LoteTracingFactory *LoteTracingFactoryCaller) TransferirCustodia(opts *bind.CallOpts, arg0 string, arg1 common.Address) error {
+	var out []interface{}
+	err := _LoteTracingFactory.contract.Call(opts, &out, "transferirCustodia", arg0, arg1)
+
+	if err != nil {
+		return err
+	}
+
+	return err
+
+}
+
+// TransferirCustodia is a free data retrieval call binding the contract method 0x9fc1c384.
+//
+// Solidity: function transferirCustodia(string , address ) pure returns()
+func (_LoteTracingFactory *LoteTracingFactorySession) TransferirCustodia(arg0 string, arg1 common.Address) error {
+	return _LoteTracingFactory.Contract.TransferirCustodia(&_LoteTracingFactory.CallOpts, arg0, arg1)
+}
+
+// TransferirCustodia is a free data retrieval call binding the contract method 0x9fc1c384.
+//
+// Solidity: function transferirCustodia(string , address ) pure returns()
+func (_LoteTracingFactory *LoteTracingFactoryCallerSession) TransferirCustodia(arg0 string, arg1 common.Address) error {
+	return _LoteTracingFactory.Contract.TransferirCustodia(&_LoteTracingFactory.CallOpts, arg0, arg1)
+}
+
+// AutorizarFabricante is a paid mutator transaction binding the contract method 0x5657c087.
+//
+// Solidity: function autorizarFabricante(address _cuenta, bool _autorizado) returns()
+func (_LoteTracingFactory *LoteTracingFactoryTransactor) AutorizarFabricante(opts *bind.TransactOpts, _cuenta common.Address, _autorizado bool) (*types.Transaction, error) {
+	return _LoteTracingFactory.contract.Transact(opts, "autorizarFabricante", _cuenta, _autorizado)
+}
+
+// AutorizarFabricante is a paid mutator transaction binding the contract method 0x5657c087.
+//
+// Solidity: function autorizarFabricante(address _cuenta, bool _autorizado) returns()
+func (_LoteTracingFactory *LoteTracingFactorySession) AutorizarFabricante(_cuenta common.Address, _autorizado bool) (*types.Transaction, error) {
+	return _LoteTracingFactory.Contract.AutorizarFabricante(&_LoteTracingFactory.TransactOpts, _cuenta, _autorizado)
+}
+
+// AutorizarFabricante is a paid mutator transaction binding the contract method 0x5657c087.
+//
+// Solidity: function autorizarFabricante(address _cuenta, bool _autorizado) returns()
+func (_LoteTracingFactory *LoteTracingFactoryTransactorSession) AutorizarFabricante(_cuenta common.Address, _autorizado bool) (*types.Transaction, error) {
+	return _LoteTracingFactory.Contract.AutorizarFabricante(&_LoteTracingFactory.TransactOpts, _cuenta, _autorizado)
+}
+
+// CrearLote is a paid mutator transaction binding the contract method 0x4a2feee8.
+//
+// Solidity: function crearLote(string _loteId, int8 _tempMin, int8 _tempMax) returns()
+func (_LoteTracingFactory *LoteTracingFactoryTransactor) CrearLote(opts *bind.TransactOpts, _loteId string, _tempMin int8, _tempMax int8) (*types.Transaction, error) {
+	return _LoteTracingFactory.contract.Transact(opts, "crearLote", _loteId, _tempMin, _tempMax)
+}
+
+// CrearLote is a paid mutator transaction binding the contract method 0x4a2feee8.
+//
+// Solidity: function crearLote(string _loteId, int8 _tempMin, int8 _tempMax) returns()
+func (_LoteTracingFactory *LoteTracingFactorySession) CrearLote(_loteId string, _tempMin int8, _tempMax int8) (*types.Transaction, error) {
+	return _LoteTracingFactory.Contract.CrearLote(&_LoteTracingFactory.TransactOpts, _loteId, _tempMin, _tempMax)
+}
+
+// CrearLote is a paid mutator transaction binding the contract method 0x4a2feee8.
+//
+// Solidity: function crearLote(string _loteId, int8 _tempMin, int8 _tempMax) returns()
+func (_LoteTracingFactory *LoteTracingFactoryTransactorSession) CrearLote(_loteId string, _tempMin int8, _tempMax int8) (*types.Transaction, error) {
+	return _LoteTracingFactory.Contract.CrearLote(&_LoteTracingFactory.TransactOpts, _loteId, _tempMin, _tempMax)
+}
+
+// TransferirAdmin is a paid mutator transaction binding the contract method 0xbbe99a1e.
+//
+// Solidity: function transferirAdmin(address _nuevoAdmin) returns()
+func (_LoteTracingFactory *LoteTracingFactoryTransactor) TransferirAdmin(opts *bind.TransactOpts, _nuevoAdmin common.Address) (*types.Transaction, error) {
+	return _LoteTracingFactory.contract.Transact(opts, "transferirAdmin", _nuevoAdmin)
+}
+
+// TransferirAdmin is a paid mutator transaction binding the contract method 0xbbe99a1e.
+//
+// Solidity: function transferirAdmin(address _nuevoAdmin) returns()
+func (_LoteTracingFactory *LoteTracingFactorySession) TransferirAdmin(_nuevoAdmin common.Address) (*types.Transaction, error) {
+	return _LoteTracingFactory.Contract.TransferirAdmin(&_LoteTracingFactory.TransactOpts, _nuevoAdmin)
+}
+
+// TransferirAdmin is a paid mutator transaction binding the contract method 0xbbe99a1e.
+//
+// Solidity: function transferirAdmin(address _nuevoAdmin) returns()
+func (_LoteTracingFactory *LoteTracingFactoryTransactorSession) TransferirAdmin(_nuevoAdmin common.Address) (*types.Transaction, error) {
+	return _LoteTracingFactory.Contract.TransferirAdmin(&_LoteTracingFactory.TransactOpts, _nuevoAdmin)
+}
+
+// LoteTracingFactoryAdminTransferidoIterator is returned from FilterAdminTransferido and is used to iterate over the raw logs and unpacked data for AdminTransferido events raised by the LoteTracingFactory contract.
+type LoteTracingFactoryAdminTransferidoIterator struct {
+	Event *LoteTracingFactoryAdminTransferido // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LoteTracingFactoryAdminTransferidoIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LoteTracingFactoryAdminTransferido)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LoteTracingFactoryAdminTransferido)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LoteTracingFactoryAdminTransferidoIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LoteTracingFactoryAdminTransferidoIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LoteTracingFactoryAdminTransferido represents a AdminTransferido event raised by the LoteTracingFactory contract.
+type LoteTracingFactoryAdminTransferido struct {
+	AdminAnterior common.Address
+	NuevoAdmin    common.Address
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterAdminTransferido is a free log retrieval operation binding the contract event 0x2e1a6fe73e94eadae803896e18a2db28d96e0c2bbfb1c6d0e6132a56bc35752d.
+//
+// Solidity: event AdminTransferido(address indexed adminAnterior, address indexed nuevoAdmin)
+func (_LoteTracingFactory *LoteTracingFactoryFilterer) FilterAdminTransferido(opts *bind.FilterOpts, adminAnterior []common.Address, nuevoAdmin []common.Address) (*LoteTracingFactoryAdminTransferidoIterator, error) {
+
+	var adminAnteriorRule []interface{}
+	for _, adminAnteriorItem := range adminAnterior {
+		adminAnteriorRule = append(adminAnteriorRule, adminAnteriorItem)
+	}
+	var nuevoAdminRule []interface{}
+	for _, nuevoAdminItem := range nuevoAdmin {
+		nuevoAdminRule = append(nuevoAdminRule, nuevoAdminItem)
+	}
+
+	logs, sub, err := _LoteTracingFactory.contract.FilterLogs(opts, "AdminTransferido", adminAnteriorRule, nuevoAdminRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingFactoryAdminTransferidoIterator{contract: _LoteTracingFactory.contract, event: "AdminTransferido", logs: logs, sub: sub}, nil
+}
+
+// WatchAdminTransferido is a free log subscription operation binding the contract event 0x2e1a6fe73e94eadae803896e18a2db28d96e0c2bbfb1c6d0e6132a56bc35752d.
+//
+// Solidity: event AdminTransferido(address indexed adminAnterior, address indexed nuevoAdmin)
+func (_LoteTracingFactory *LoteTracingFactoryFilterer) WatchAdminTransferido(opts *bind.WatchOpts, sink chan<- *LoteTracingFactoryAdminTransferido, adminAnterior []common.Address, nuevoAdmin []common.Address) (event.Subscription, error) {
+
+	var adminAnteriorRule []interface{}
+	for _, adminAnteriorItem := range adminAnterior {
+		adminAnteriorRule = append(adminAnteriorRule, adminAnteriorItem)
+	}
+	var nuevoAdminRule []interface{}
+	for _, nuevoAdminItem := range nuevoAdmin {
+		nuevoAdminRule = append(nuevoAdminRule, nuevoAdminItem)
+	}
+
+	logs, sub, err := _LoteTracingFactory.contract.WatchLogs(opts, "AdminTransferido", adminAnteriorRule, nuevoAdminRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LoteTracingFactoryAdminTransferido)
+				if err := _LoteTracingFactory.contract.UnpackLog(event, "AdminTransferido", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseAdminTransferido is a log parse operation binding the contract event 0x2e1a6fe73e94eadae803896e18a2db28d96e0c2bbfb1c6d0e6132a56bc35752d.
+//
+// Solidity: event AdminTransferido(address indexed adminAnterior, address indexed nuevoAdmin)
+func (_LoteTracingFactory *LoteTracingFactoryFilterer) ParseAdminTransferido(log types.Log) (*LoteTracingFactoryAdminTransferido, error) {
+	event := new(LoteTracingFactoryAdminTransferido)
+	if err := _LoteTracingFactory.contract.UnpackLog(event, "AdminTransferido", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LoteTracingFactoryCustodiaTransferidaIterator is returned from FilterCustodiaTransferida and is used to iterate over the raw logs and unpacked data for CustodiaTransferida events raised by the LoteTracingFactory contract.
+type LoteTracingFactoryCustodiaTransferidaIterator struct {
+	Event *LoteTracingFactoryCustodiaTransferida // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LoteTracingFactoryCustodiaTransferidaIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LoteTracingFactoryCustodiaTransferida)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LoteTracingFactoryCustodiaTransferida)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LoteTracingFactoryCustodiaTransferidaIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LoteTracingFactoryCustodiaTransferidaIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LoteTracingFactoryCustodiaTransferida represents a CustodiaTransferida event raised by the LoteTracingFactory contract.
+type LoteTracingFactoryCustodiaTransferida struct {
+	LoteId              common.Hash
+	PropietarioAnterior common.Address
+	NuevoPropietario    common.Address
+	Comprometido        bool
+	Motivo              string
+	Raw                 types.Log // Blockchain specific contextual infos
+}
+
+// FilterCustodiaTransferida is a free log retrieval operation binding the contract event 0xd546cc459918b102e7e595ed6265142e1e58fb2f93f2a3a635b10f5dd9e0c530.
+//
+// Solidity: event CustodiaTransferida(string indexed loteId, address indexed propietarioAnterior, address indexed nuevoPropietario, bool comprometido, string motivo)
+func (_LoteTracingFactory *LoteTracingFactoryFilterer) FilterCustodiaTransferida(opts *bind.FilterOpts, loteId []string, propietarioAnterior []common.Address, nuevoPropietario []common.Address) (*LoteTracingFactoryCustodiaTransferidaIterator, error) {
+
+	var loteIdRule []interface{}
+	for _, loteIdItem := range loteId {
+		loteIdRule = append(loteIdRule, loteIdItem)
+	}
+	var propietarioAnteriorRule []interface{}
+	for _, propietarioAnteriorItem := range propietarioAnterior {
+		propietarioAnteriorRule = append(propietarioAnteriorRule, propietarioAnteriorItem)
+	}
+	var nuevoPropietarioRule []interface{}
+	for _, nuevoPropietarioItem := range nuevoPropietario {
+		nuevoPropietarioRule = append(nuevoPropietarioRule, nuevoPropietarioItem)
+	}
+
+	logs, sub, err := _LoteTracingFactory.contract.FilterLogs(opts, "CustodiaTransferida", loteIdRule, propietarioAnteriorRule, nuevoPropietarioRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingFactoryCustodiaTransferidaIterator{contract: _LoteTracingFactory.contract, event: "CustodiaTransferida", logs: logs, sub: sub}, nil
+}
+
+// WatchCustodiaTransferida is a free log subscription operation binding the contract event 0xd546cc459918b102e7e595ed6265142e1e58fb2f93f2a3a635b10f5dd9e0c530.
+//
+// Solidity: event CustodiaTransferida(string indexed loteId, address indexed propietarioAnterior, address indexed nuevoPropietario, bool comprometido, string motivo)
+func (_LoteTracingFactory *LoteTracingFactoryFilterer) WatchCustodiaTransferida(opts *bind.WatchOpts, sink chan<- *LoteTracingFactoryCustodiaTransferida, loteId []string, propietarioAnterior []common.Address, nuevoPropietario []common.Address) (event.Subscription, error) {
+
+	var loteIdRule []interface{}
+	for _, loteIdItem := range loteId {
+		loteIdRule = append(loteIdRule, loteIdItem)
+	}
+	var propietarioAnteriorRule []interface{}
+	for _, propietarioAnteriorItem := range propietarioAnterior {
+		propietarioAnteriorRule = append(propietarioAnteriorRule, propietarioAnteriorItem)
+	}
+	var nuevoPropietarioRule []interface{}
+	for _, nuevoPropietarioItem := range nuevoPropietario {
+		nuevoPropietarioRule = append(nuevoPropietarioRule, nuevoPropietarioItem)
+	}
+
+	logs, sub, err := _LoteTracingFactory.contract.WatchLogs(opts, "CustodiaTransferida", loteIdRule, propietarioAnteriorRule, nuevoPropietarioRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LoteTracingFactoryCustodiaTransferida)
+				if err := _LoteTracingFactory.contract.UnpackLog(event, "CustodiaTransferida", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseCustodiaTransferida is a log parse operation binding the contract event 0xd546cc459918b102e7e595ed6265142e1e58fb2f93f2a3a635b10f5dd9e0c530.
+//
+// Solidity: event CustodiaTransferida(string indexed loteId, address indexed propietarioAnterior, address indexed nuevoPropietario, bool comprometido, string motivo)
+func (_LoteTracingFactory *LoteTracingFactoryFilterer) ParseCustodiaTransferida(log types.Log) (*LoteTracingFactoryCustodiaTransferida, error) {
+	event := new(LoteTracingFactoryCustodiaTransferida)
+	if err := _LoteTracingFactory.contract.UnpackLog(event, "CustodiaTransferida", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LoteTracingFactoryFabricanteAutorizadoIterator is returned from FilterFabricanteAutorizado and is used to iterate over the raw logs and unpacked data for FabricanteAutorizado events raised by the LoteTracingFactory contract.
+type LoteTracingFactoryFabricanteAutorizadoIterator struct {
+	Event *LoteTracingFactoryFabricanteAutorizado // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LoteTracingFactoryFabricanteAutorizadoIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LoteTracingFactoryFabricanteAutorizado)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LoteTracingFactoryFabricanteAutorizado)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LoteTracingFactoryFabricanteAutorizadoIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LoteTracingFactoryFabricanteAutorizadoIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LoteTracingFactoryFabricanteAutorizado represents a FabricanteAutorizado event raised by the LoteTracingFactory contract.
+type LoteTracingFactoryFabricanteAutorizado struct {
+	Cuenta     common.Address
+	Autorizado bool
+	Raw        types.Log // Blockchain specific contextual infos
+}
+
+// FilterFabricanteAutorizado is a free log retrieval operation binding the contract event 0x4503f7a399337ed9d65012c24e7d6d80d5b79fd0a9f647e4eec0d9982659f460.
+//
+// Solidity: event FabricanteAutorizado(address indexed cuenta, bool autorizado)
+func (_LoteTracingFactory *LoteTracingFactoryFilterer) FilterFabricanteAutorizado(opts *bind.FilterOpts, cuenta []common.Address) (*LoteTracingFactoryFabricanteAutorizadoIterator, error) {
+
+	var cuentaRule []interface{}
+	for _, cuentaItem := range cuenta {
+		cuentaRule = append(cuentaRule, cuentaItem)
+	}
+
+	logs, sub, err := _LoteTracingFactory.contract.FilterLogs(opts, "FabricanteAutorizado", cuentaRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingFactoryFabricanteAutorizadoIterator{contract: _LoteTracingFactory.contract, event: "FabricanteAutorizado", logs: logs, sub: sub}, nil
+}
+
+// WatchFabricanteAutorizado is a free log subscription operation binding the contract event 0x4503f7a399337ed9d65012c24e7d6d80d5b79fd0a9f647e4eec0d9982659f460.
+//
+// Solidity: event FabricanteAutorizado(address indexed cuenta, bool autorizado)
+func (_LoteTracingFactory *LoteTracingFactoryFilterer) WatchFabricanteAutorizado(opts *bind.WatchOpts, sink chan<- *LoteTracingFactoryFabricanteAutorizado, cuenta []common.Address) (event.Subscription, error) {
+
+	var cuentaRule []interface{}
+	for _, cuentaItem := range cuenta {
+		cuentaRule = append(cuentaRule, cuentaItem)
+	}
+
+	logs, sub, err := _LoteTracingFactory.contract.WatchLogs(opts, "FabricanteAutorizado", cuentaRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LoteTracingFactoryFabricanteAutorizado)
+				if err := _LoteTracingFactory.contract.UnpackLog(event, "FabricanteAutorizado", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseFabricanteAutorizado is a log parse operation binding the contract event 0x4503f7a399337ed9d65012c24e7d6d80d5b79fd0a9f647e4eec0d9982659f460.
+//
+// Solidity: event FabricanteAutorizado(address indexed cuenta, bool autorizado)
+func (_LoteTracingFactory *LoteTracingFactoryFilterer) ParseFabricanteAutorizado(log types.Log) (*LoteTracingFactoryFabricanteAutorizado, error) {
+	event := new(LoteTracingFactoryFabricanteAutorizado)
+	if err := _LoteTracingFactory.contract.UnpackLog(event, "FabricanteAutorizado", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LoteTracingFactoryLoteComprometidoIterator is returned from FilterLoteComprometido and is used to iterate over the raw logs and unpacked data for LoteComprometido events raised by the LoteTracingFactory contract.
+type LoteTracingFactoryLoteComprometidoIterator struct {
+	Event *LoteTracingFactoryLoteComprometido // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LoteTracingFactoryLoteComprometidoIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LoteTracingFactoryLoteComprometido)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LoteTracingFactoryLoteComprometido)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LoteTracingFactoryLoteComprometidoIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LoteTracingFactoryLoteComprometidoIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LoteTracingFactoryLoteComprometido represents a LoteComprometido event raised by the LoteTracingFactory contract.
+type LoteTracingFactoryLoteComprometido struct {
+	LoteId       common.Hash
+	Propietario  common.Address
+	TempMin      int8
+	TempMax      int8
+	Comprometido bool
+	Motivo       string
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterLoteComprometido is a free log retrieval operation binding the contract event 0x7479f80c3d505696d4d9c1b9b856c087a96c6e67e2cdc137fa2321320b4e04b5.
+//
+// Solidity: event LoteComprometido(string indexed loteId, address indexed propietario, int8 tempMin, int8 tempMax, bool comprometido, string motivo)
+func (_LoteTracingFactory *LoteTracingFactoryFilterer) FilterLoteComprometido(opts *bind.FilterOpts, loteId []string, propietario []common.Address) (*LoteTracingFactoryLoteComprometidoIterator, error) {
+
+	var loteIdRule []interface{}
+	for _, loteIdItem := range loteId {
+		loteIdRule = append(loteIdRule, loteIdItem)
+	}
+	var propietarioRule []interface{}
+	for _, propietarioItem := range propietario {
+		propietarioRule = append(propietarioRule, propietarioItem)
+	}
+
+	logs, sub, err := _LoteTracingFactory.contract.FilterLogs(opts, "LoteComprometido", loteIdRule, propietarioRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingFactoryLoteComprometidoIterator{contract: _LoteTracingFactory.contract, event: "LoteComprometido", logs: logs, sub: sub}, nil
+}
+
+// WatchLoteComprometido is a free log subscription operation binding the contract event 0x7479f80c3d505696d4d9c1b9b856c087a96c6e67e2cdc137fa2321320b4e04b5.
+//
+// Solidity: event LoteComprometido(string indexed loteId, address indexed propietario, int8 tempMin, int8 tempMax, bool comprometido, string motivo)
+func (_LoteTracingFactory *LoteTracingFactoryFilterer) WatchLoteComprometido(opts *bind.WatchOpts, sink chan<- *LoteTracingFactoryLoteComprometido, loteId []string, propietario []common.Address) (event.Subscription, error) {
+
+	var loteIdRule []interface{}
+	for _, loteIdItem := range loteId {
+		loteIdRule = append(loteIdRule, loteIdItem)
+	}
+	var propietarioRule []interface{}
+	for _, propietarioItem := range propietario {
+		propietarioRule = append(propietarioRule, propietarioItem)
+	}
+
+	logs, sub, err := _LoteTracingFactory.contract.WatchLogs(opts, "LoteComprometido", loteIdRule, propietarioRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LoteTracingFactoryLoteComprometido)
+				if err := _LoteTracingFactory.contract.UnpackLog(event, "LoteComprometido", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseLoteComprometido is a log parse operation binding the contract event 0x7479f80c3d505696d4d9c1b9b856c087a96c6e67e2cdc137fa2321320b4e04b5.
+//
+// Solidity: event LoteComprometido(string indexed loteId, address indexed propietario, int8 tempMin, int8 tempMax, bool comprometido, string motivo)
+func (_LoteTracingFactory *LoteTracingFactoryFilterer) ParseLoteComprometido(log types.Log) (*LoteTracingFactoryLoteComprometido, error) {
+	event := new(LoteTracingFactoryLoteComprometido)
+	if err := _LoteTracingFactory.contract.UnpackLog(event, "LoteComprometido", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LoteTracingFactoryLoteCreadoIterator is returned from FilterLoteCreado and is used to iterate over the raw logs and unpacked data for LoteCreado events raised by the LoteTracingFactory contract.
+type LoteTracingFactoryLoteCreadoIterator struct {
+	Event *LoteTracingFactoryLoteCreado // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LoteTracingFactoryLoteCreadoIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LoteTracingFactoryLoteCreado)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LoteTracingFactoryLoteCreado)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LoteTracingFactoryLoteCreadoIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LoteTracingFactoryLoteCreadoIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LoteTracingFactoryLoteCreado represents a LoteCreado event raised by the LoteTracingFactory contract.
+type LoteTracingFactoryLoteCreado struct {
+	LoteId            common.Hash
+	Fabricante        common.Address
+	TemperaturaMinima int8
+	TemperaturaMaxima int8
+	Motivo            string
+	Raw               types.Log // Blockchain specific contextual infos
+}
+
+// FilterLoteCreado is a free log retrieval operation binding the contract event 0xc144c2e3dd9d75c64005477d5ae1809dc476485e047bae2a8bce83faa249cf24.
+//
+// Solidity: event LoteCreado(string indexed loteId, address indexed fabricante, int8 temperaturaMinima, int8 temperaturaMaxima, string motivo)
+func (_LoteTracingFactory *LoteTracingFactoryFilterer) FilterLoteCreado(opts *bind.FilterOpts, loteId []string, fabricante []common.Address) (*LoteTracingFactoryLoteCreadoIterator, error) {
+
+	var loteIdRule []interface{}
+	for _, loteIdItem := range loteId {
+		loteIdRule = append(loteIdRule, loteIdItem)
+	}
+	var fabricanteRule []interface{}
+	for _, fabricanteItem := range fabricante {
+		fabricanteRule = append(fabricanteRule, fabricanteItem)
+	}
+
+	logs, sub, err := _LoteTracingFactory.contract.FilterLogs(opts, "LoteCreado", loteIdRule, fabricanteRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LoteTracingFactoryLoteCreadoIterator{contract: _LoteTracingFactory.contract, event: "LoteCreado", logs: logs, sub: sub}, nil
+}
+
+// WatchLoteCreado is a free log subscription operation binding the contract event 0xc144c2e3dd9d75c64005477d5ae1809dc476485e047bae2a8bce83faa249cf24.
+//
+// Solidity: event LoteCreado(string indexed loteId, address indexed fabricante, int8 temperaturaMinima, int8 temperaturaMaxima, string motivo)
+func (_LoteTracingFactory *LoteTracingFactoryFilterer) WatchLoteCreado(opts *bind.WatchOpts, sink chan<- *LoteTracingFactoryLoteCreado, loteId []string, fabricante []common.Address) (event.Subscription, error) {
+
+	var loteIdRule []interface{}
+	for _, loteIdItem := range loteId {
+		loteIdRule = append(loteIdRule, loteIdItem)
+	}
+	var fabricanteRule []interface{}
+	for _, fabricanteItem := range fabricante {
+		fabricanteRule = append(fabricanteRule, fabricanteItem)
+	}
+
+	logs, sub, err := _LoteTracingFactory.contract.WatchLogs(opts, "LoteCreado", loteIdRule, fabricanteRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LoteTracingFactoryLoteCreado)
+				if err := _LoteTracingFactory.contract.UnpackLog(event, "LoteCreado", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseLoteCreado is a log parse operation binding the contract event 0xc144c2e3dd9d75c64005477d5ae1809dc476485e047bae2a8bce83faa249cf24.
+//
+// Solidity: event LoteCreado(string indexed loteId, address indexed fabricante, int8 temperaturaMinima, int8 temperaturaMaxima, string motivo)
+func (_LoteTracingFactory *LoteTracingFactoryFilterer) ParseLoteCreado(log types.Log) (*LoteTracingFactoryLoteCreado, error) {
+	event := new(LoteTracingFactoryLoteCreado)
+	if err := _LoteTracingFactory.contract.UnpackLog(event, "LoteCreado", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
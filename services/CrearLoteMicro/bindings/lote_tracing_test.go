@@ -0,0 +1,45 @@
+package bindings
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// TestBindingsUpToDate falla si los bindings no corresponden a los assets
+// actuales de los contratos; se corrige con go generate ./bindings
+func TestBindingsUpToDate(t *testing.T) {
+	for contrato, archivo := range map[string]string{
+		"LoteTracing":        "lote_tracing.go",
+		"LoteTracingFactory": "lote_tracing_factory.go",
+	} {
+		abiJSON, err := os.ReadFile("../assets/contracts/" + contrato + ".abi.json")
+		if err != nil {
+			t.Fatalf("Failed to read %s ABI: %v", contrato, err)
+		}
+		bytecode, err := os.ReadFile("../assets/contracts/" + contrato + ".bytecode")
+		if err != nil {
+			t.Fatalf("Failed to read %s bytecode: %v", contrato, err)
+		}
+
+		want, err := bind.Bind(
+			[]string{contrato},
+			[]string{string(abiJSON)},
+			[]string{strings.TrimSpace(string(bytecode))},
+			nil, "bindings", bind.LangGo, nil, nil,
+		)
+		if err != nil {
+			t.Fatalf("Failed to generate %s bindings: %v", contrato, err)
+		}
+
+		got, err := os.ReadFile(archivo)
+		if err != nil {
+			t.Fatalf("Failed to read bindings: %v", err)
+		}
+		if string(got) != want {
+			t.Errorf("%s is out of date with assets/contracts; run go generate ./bindings", archivo)
+		}
+	}
+}
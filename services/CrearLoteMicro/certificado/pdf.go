@@ -0,0 +1,234 @@
+// Package certificado genera el PDF del certificado de cadena de frío de un
+// lote. El PDF se escribe sin dependencias, con las fuentes estándar, y lleva
+// adjunto el documento JSON firmado para poder verificarlo.
+package certificado
+
+import (
+	"CrearLoteMicro/models"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NombreAdjunto es el nombre del JSON firmado adjunto al PDF
+const NombreAdjunto = "certificado.json"
+
+// ErrSinAdjunto se devuelve cuando el PDF no lleva el certificado adjunto
+var ErrSinAdjunto = errors.New("el PDF no tiene el certificado adjunto")
+
+// Página A4 en puntos
+const (
+	anchoPagina = 595
+	altoPagina  = 842
+	margen      = 50
+)
+
+var adjuntoRe = regexp.MustCompile(`/Type /EmbeddedFile /Subtype /application#2Fjson /Length (\d+) >>\nstream\n`)
+
+// linea es una línea de texto del documento
+type linea struct {
+	fuente string
+	tamano float64
+	texto  string
+	// espacio es la separación extra antes de la línea
+	espacio float64
+}
+
+// PDF genera el certificado en PDF con el documento firmado adjunto
+func PDF(documento models.CertificadoFirmado) ([]byte, error) {
+	adjunto, err := json.MarshalIndent(documento, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error serializando certificado: %v", err)
+	}
+	return escribirPDF(paginar(lineasCertificado(documento)), adjunto, time.Unix(documento.Certificado.EmitidoEn, 0).UTC()), nil
+}
+
+// ExtraerDocumento devuelve el documento firmado adjunto a un PDF generado
+// por PDF
+func ExtraerDocumento(pdf []byte) (*models.CertificadoFirmado, error) {
+	m := adjuntoRe.FindSubmatchIndex(pdf)
+	if m == nil {
+		return nil, ErrSinAdjunto
+	}
+	longitud, err := strconv.Atoi(string(pdf[m[2]:m[3]]))
+	if err != nil || m[1]+longitud > len(pdf) {
+		return nil, ErrSinAdjunto
+	}
+
+	var documento models.CertificadoFirmado
+	if err := json.Unmarshal(pdf[m[1]:m[1]+longitud], &documento); err != nil {
+		return nil, fmt.Errorf("error leyendo el certificado adjunto: %v", err)
+	}
+	return &documento, nil
+}
+
+// lineasCertificado redacta el contenido del certificado
+func lineasCertificado(documento models.CertificadoFirmado) []linea {
+	c := documento.Certificado
+	var lineas []linea
+	titulo := func(texto string) {
+		lineas = append(lineas, linea{fuente: "F2", tamano: 12, texto: texto, espacio: 10})
+	}
+	texto := func(formato string, args ...interface{}) {
+		lineas = append(lineas, linea{fuente: "F1", tamano: 10, texto: fmt.Sprintf(formato, args...)})
+	}
+	mono := func(formato string, args ...interface{}) {
+		lineas = append(lineas, linea{fuente: "F3", tamano: 7.5, texto: fmt.Sprintf(formato, args...)})
+	}
+
+	lineas = append(lineas, linea{fuente: "F2", tamano: 18, texto: "Certificado de Cadena de Frío"})
+	estado := "CADENA DE FRÍO ÍNTEGRA"
+	if !c.CadenaFrioIntacta {
+		estado = "CADENA DE FRÍO COMPROMETIDA"
+	}
+	lineas = append(lineas, linea{fuente: "F2", tamano: 12, texto: estado, espacio: 4})
+
+	titulo("Lote")
+	texto("Lote: %s", c.Lote.LoteID)
+	texto("Contrato: %s", c.ContractAddress)
+	texto("Red: %s (chain ID %d)", c.Red, c.ChainID)
+	texto("Fabricante: %s", c.Lote.Fabricante)
+	texto("Propietario actual: %s", c.Lote.PropietarioActual)
+	texto("Rango permitido: %d °C a %d °C", c.Lote.TemperaturaMinima, c.Lote.TemperaturaMaxima)
+	texto("Rango registrado: %d °C a %d °C en %d lecturas", c.Lote.TempRegMinima, c.Lote.TempRegMaxima, c.TotalLecturas)
+
+	titulo("Cadena de custodia")
+	for i, eslabon := range c.Custodia {
+		texto("%d. %s - %s", i+1, fecha(eslabon.Timestamp), eslabon.Motivo)
+		if eslabon.PropietarioAnterior != "" {
+			mono("   de %s a %s", eslabon.PropietarioAnterior, eslabon.NuevoPropietario)
+		} else {
+			mono("   propietario %s", eslabon.NuevoPropietario)
+		}
+		mono("   bloque %d, tx %s", eslabon.BlockNumber, eslabon.TxHash)
+	}
+
+	titulo("Excursiones de temperatura")
+	if len(c.Excursiones) == 0 {
+		texto("Sin excursiones de temperatura.")
+	}
+	for i, excursion := range c.Excursiones {
+		origen := excursion.Evento
+		switch {
+		case excursion.SensorID != "":
+			origen = "sensor " + excursion.SensorID
+		case excursion.TotalLecturas > 0:
+			origen = fmt.Sprintf("%d lecturas ancladas", excursion.TotalLecturas)
+		}
+		texto("%d. %s - %d °C a %d °C (%s)", i+1, fecha(excursion.Timestamp), excursion.TempMin, excursion.TempMax, origen)
+		mono("   bloque %d, tx %s", excursion.BlockNumber, excursion.TxHash)
+	}
+
+	titulo(fmt.Sprintf("Transacciones (%d)", len(c.Transacciones)))
+	for _, referencia := range c.Transacciones {
+		mono("%-9d %-4d %-22s %s", referencia.BlockNumber, referencia.LogIndex, referencia.Evento, referencia.TxHash)
+	}
+
+	titulo("Firma")
+	texto("Emitido: %s", fecha(uint64(c.EmitidoEn)))
+	texto("Emisor: %s", c.Emisor)
+	mono("Bloque de referencia %d, %s", c.BloqueReferencia, c.HashBloqueReferencia)
+	mono("Hash del certificado %s", documento.Hash)
+	firma := documento.Firma
+	for len(firma) > 70 {
+		mono("Firma EIP-191 %s", firma[:70])
+		firma = firma[70:]
+	}
+	mono("              %s", firma)
+	lineas = append(lineas, linea{fuente: "F1", tamano: 8, espacio: 10,
+		texto: "El documento firmado va adjunto como " + NombreAdjunto + "; verifíquelo con POST /api/v1/lote/certificado/verificar."})
+	return lineas
+}
+
+func fecha(timestamp uint64) string {
+	return time.Unix(int64(timestamp), 0).UTC().Format("2006-01-02 15:04 UTC")
+}
+
+// paginar reparte las líneas en páginas y devuelve el contenido de cada una
+func paginar(lineas []linea) [][]byte {
+	var paginas [][]byte
+	var contenido bytes.Buffer
+	y := float64(altoPagina - margen)
+	for _, l := range lineas {
+		alto := l.tamano*1.4 + l.espacio
+		if y-alto < margen && contenido.Len() > 0 {
+			paginas = append(paginas, append([]byte(nil), contenido.Bytes()...))
+			contenido.Reset()
+			y = altoPagina - margen
+		}
+		y -= alto
+		fmt.Fprintf(&contenido, "BT /%s %.1f Tf %d %.1f Td (%s) Tj ET\n", l.fuente, l.tamano, margen, y, escapar(l.texto))
+	}
+	return append(paginas, contenido.Bytes())
+}
+
+// escapar convierte el texto a WinAnsiEncoding y escapa los caracteres
+// especiales de las cadenas PDF
+func escapar(texto string) string {
+	var b strings.Builder
+	for _, r := range texto {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			// Latin-1 coincide con WinAnsiEncoding en este rango
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// escribirPDF arma el archivo: catálogo, páginas, fuentes estándar y el
+// adjunto, con su tabla de referencias cruzadas
+func escribirPDF(paginas [][]byte, adjunto []byte, creado time.Time) []byte {
+	var pdf bytes.Buffer
+	var offsets []int
+	objeto := func(cuerpo string) {
+		offsets = append(offsets, pdf.Len())
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", len(offsets), cuerpo)
+	}
+	stream := func(diccionario string, contenido []byte) string {
+		return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", diccionario, len(contenido), contenido)
+	}
+
+	// 1 catálogo, 2 páginas, 3-5 fuentes, 6 especificación del adjunto,
+	// 7 adjunto, 8 información; después cada página con su contenido
+	const primeraPagina = 9
+	kids := make([]string, len(paginas))
+	for i := range paginas {
+		kids[i] = fmt.Sprintf("%d 0 R", primeraPagina+2*i)
+	}
+
+	pdf.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	objeto(fmt.Sprintf("<< /Type /Catalog /Pages 2 0 R /Names << /EmbeddedFiles << /Names [(%s) 6 0 R] >> >> /AF [6 0 R] >>", NombreAdjunto))
+	objeto(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(paginas)))
+	for _, fuente := range []string{"Helvetica", "Helvetica-Bold", "Courier"} {
+		objeto(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", fuente))
+	}
+	objeto(fmt.Sprintf("<< /Type /Filespec /F (%s) /UF (%s) /AFRelationship /Source /Desc (Certificado firmado) /EF << /F 7 0 R >> >>", NombreAdjunto, NombreAdjunto))
+	objeto(stream("/Type /EmbeddedFile /Subtype /application#2Fjson", adjunto))
+	objeto(fmt.Sprintf("<< /Title (Certificado de Cadena de Fr\\355o) /Producer (CrearLoteMicro) /CreationDate (D:%s) >>", creado.Format("20060102150405Z")))
+	for i, contenido := range paginas {
+		objeto(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R /F3 5 0 R >> >> /Contents %d 0 R >>",
+			anchoPagina, altoPagina, primeraPagina+2*i+1))
+		objeto(stream("", contenido))
+	}
+
+	xref := pdf.Len()
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R /Info 8 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return pdf.Bytes()
+}
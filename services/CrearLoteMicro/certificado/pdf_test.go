@@ -0,0 +1,92 @@
+package certificado
+
+import (
+	"CrearLoteMicro/models"
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func testDocumento(transacciones int) models.CertificadoFirmado {
+	certificado := models.CertificadoLote{
+		Version:         1,
+		Red:             "local",
+		ChainID:         1337,
+		ContractAddress: "0x5FbDB2315678afecb367f032d93F642f64180aa3",
+		Lote:            models.LoteInfoResponse{LoteID: "LOTE (001)", TemperaturaMinima: 2, TemperaturaMaxima: 8},
+		Custodia: []models.EslabonCustodia{
+			{NuevoPropietario: "0x01", Motivo: "Lote Creado", Timestamp: 1700000000},
+			{PropietarioAnterior: "0x01", NuevoPropietario: "0x02", Motivo: "Custodia Transferida", Timestamp: 1700000100},
+		},
+		Excursiones:       []models.ExcursionTemperatura{{Evento: "TemperaturaRegistrada", SensorID: "SENSOR-01", TempMin: 1, TempMax: 12}},
+		EmitidoEn:         1700000200,
+		CadenaFrioIntacta: false,
+	}
+	for i := 0; i < transacciones; i++ {
+		certificado.Transacciones = append(certificado.Transacciones, models.ReferenciaTransaccion{
+			Evento:      "TemperaturaRegistrada",
+			TxHash:      fmt.Sprintf("0x%064x", i),
+			BlockNumber: uint64(i),
+		})
+	}
+	return models.CertificadoFirmado{Certificado: certificado, Hash: "0xabc", Firma: "0x" + string(bytes.Repeat([]byte("ab"), 65))}
+}
+
+func TestPDF_EmbedsSignedDocument(t *testing.T) {
+	documento := testDocumento(3)
+	pdf, err := PDF(documento)
+	if err != nil {
+		t.Fatalf("Failed to render PDF: %v", err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.7\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Error("Expected a PDF header and trailer")
+	}
+	// Acentos en WinAnsiEncoding y paréntesis escapados
+	if !bytes.Contains(pdf, []byte(`(Certificado de Cadena de Fr\355o) Tj`)) || !bytes.Contains(pdf, []byte(`LOTE \(001\)`)) {
+		t.Error("Expected escaped text in the page content")
+	}
+
+	extraido, err := ExtraerDocumento(pdf)
+	if err != nil {
+		t.Fatalf("Failed to extract document: %v", err)
+	}
+	if extraido.Hash != documento.Hash || extraido.Certificado.Lote.LoteID != "LOTE (001)" || len(extraido.Certificado.Custodia) != 2 {
+		t.Errorf("Unexpected embedded document %+v", extraido)
+	}
+
+	if _, err := ExtraerDocumento([]byte("%PDF-1.7\n")); !errors.Is(err, ErrSinAdjunto) {
+		t.Errorf("Expected ErrSinAdjunto, got %v", err)
+	}
+}
+
+func TestPDF_CrossReferenceAndPages(t *testing.T) {
+	pdf, err := PDF(testDocumento(200))
+	if err != nil {
+		t.Fatalf("Failed to render PDF: %v", err)
+	}
+
+	// Cada entrada de la tabla xref apunta al inicio de su objeto
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	if startxref == nil {
+		t.Fatal("Expected startxref")
+	}
+	inicio, _ := strconv.Atoi(string(startxref[1]))
+	if !bytes.HasPrefix(pdf[inicio:], []byte("xref\n")) {
+		t.Fatalf("Expected xref table at offset %d", inicio)
+	}
+	entradas := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[inicio:], -1)
+	for i, entrada := range entradas {
+		offset, _ := strconv.Atoi(string(entrada[1]))
+		if !bytes.HasPrefix(pdf[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))) {
+			t.Errorf("Expected object %d at offset %d", i+1, offset)
+		}
+	}
+
+	paginas := regexp.MustCompile(`/Count (\d+)`).FindSubmatch(pdf)
+	if n, _ := strconv.Atoi(string(paginas[1])); n < 3 {
+		t.Errorf("Expected 200 transactions to span several pages, got %d", n)
+	}
+}
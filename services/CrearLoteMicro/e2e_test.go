@@ -0,0 +1,890 @@
+package main
+
+import (
+	"CrearLoteMicro/config"
+	"CrearLoteMicro/handlers"
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/services"
+	"CrearLoteMicro/signer"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/gin-gonic/gin"
+)
+
+// apiResponse es models.Response con data sin decodificar
+type apiResponse struct {
+	Success bool                      `json:"success"`
+	Message string                    `json:"message"`
+	Data    json.RawMessage           `json:"data"`
+	TxHash  string                    `json:"txHash"`
+	Estado  *models.EstadoTransaccion `json:"estado"`
+	Network string                    `json:"network"`
+}
+
+type e2eAPI struct {
+	t       *testing.T
+	router  *gin.Engine
+	signers *signer.Registry
+	// apiKey se envía en cada solicitud; vacía no envía ninguna
+	apiKey string
+}
+
+// e2eAPIKey es la API key del cliente de las pruebas, con todas las cuentas
+const e2eAPIKey = "clave-e2e"
+
+// newE2EAPI levanta la API completa sobre la blockchain simulada
+func newE2EAPI(t *testing.T) *e2eAPI {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	cfg := &config.Config{
+		Tx: config.TxConfig{
+			PollInterval: 10 * time.Millisecond,
+			WaitTimeout:  10 * time.Second,
+		},
+		Indexer: config.IndexerConfig{
+			PollInterval: 10 * time.Millisecond,
+		},
+		Simulated: config.SimulatedConfig{
+			Accounts:   []string{"fabricante", "distribuidor"},
+			BalanceEth: 10,
+			Factory:    true,
+		},
+		Networks: []config.NetworkConfig{{
+			Name:          "local",
+			Type:          config.NetworkSimulated,
+			ChainID:       1337,
+			Confirmations: 1,
+			GasPolicy:     config.GasPolicyEIP1559,
+		}},
+		DefaultNetwork: "local",
+		Oracle: config.OracleConfig{
+			Enabled: true,
+			Account: "fabricante",
+			Window:  time.Hour,
+		},
+		Certificate: config.CertificateConfig{
+			Account: "fabricante",
+		},
+		Public: config.PublicConfig{
+			RateLimit: 60,
+			Burst:     5,
+		},
+	}
+	if err := cfg.ValidateNetworks(); err != nil {
+		t.Fatalf("Invalid network config: %v", err)
+	}
+
+	signers := signer.NewRegistry(false)
+	network, err := iniciarRed(ctx, cfg, cfg.Networks[0], signers)
+	if err != nil {
+		t.Fatalf("Failed to start simulated chain: %v", err)
+	}
+	networks := services.NewNetworkRegistry(cfg.DefaultNetwork)
+	if err := networks.Register(network); err != nil {
+		t.Fatalf("Failed to register network: %v", err)
+	}
+
+	oraculo, err := iniciarOraculo(ctx, cfg, networks, signers)
+	if err != nil {
+		t.Fatalf("Failed to start oracle: %v", err)
+	}
+	loteHandler := handlers.NewLoteHandler(networks, signers)
+	loteHandler.UsarOraculo(oraculo, cfg.Oracle.Account)
+	certificador, err := cuentaCertificados(cfg, signers)
+	if err != nil {
+		t.Fatalf("Failed to get certificate account: %v", err)
+	}
+	loteHandler.UsarCertificador(certificador)
+
+	clientes, err := signer.LoadCallers(config.AuthConfig{Clients: []config.APIClient{
+		{Name: "e2e", Key: e2eAPIKey, Accounts: []string{"*"}},
+		{Name: "distribucion", Key: "clave-distribucion", Accounts: []string{"distribuidor"}},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to load API clients: %v", err)
+	}
+
+	router, err := newRouter(loteHandler, clientes, cfg.Public)
+	if err != nil {
+		t.Fatalf("Failed to build router: %v", err)
+	}
+
+	return &e2eAPI{
+		t:       t,
+		router:  router,
+		signers: signers,
+		apiKey:  e2eAPIKey,
+	}
+}
+
+func (a *e2eAPI) do(method, path string, body interface{}, data interface{}) (int, apiResponse) {
+	a.t.Helper()
+
+	var content []byte
+	if body != nil {
+		var err error
+		if content, err = json.Marshal(body); err != nil {
+			a.t.Fatalf("Failed to encode request: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(content))
+	req.Header.Set("Content-Type", "application/json")
+	if a.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+a.apiKey)
+	}
+	recorder := httptest.NewRecorder()
+	a.router.ServeHTTP(recorder, req)
+
+	var response apiResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		a.t.Fatalf("%s %s: invalid JSON response %q: %v", method, path, recorder.Body.String(), err)
+	}
+	if data != nil && len(response.Data) > 0 {
+		if err := json.Unmarshal(response.Data, data); err != nil {
+			a.t.Fatalf("%s %s: invalid data %s: %v", method, path, response.Data, err)
+		}
+	}
+	return recorder.Code, response
+}
+
+func (a *e2eAPI) address(account string) string {
+	a.t.Helper()
+	s, err := a.signers.Get(account)
+	if err != nil {
+		a.t.Fatalf("Unknown account %s: %v", account, err)
+	}
+	return s.Address().Hex()
+}
+
+func TestE2E_LoteLifecycle(t *testing.T) {
+	api := newE2EAPI(t)
+	distribuidor := api.address("distribuidor")
+
+	// Crear el lote desplegando el contrato
+	var deploy models.ContractDeployResponse
+	status, response := api.do(http.MethodPost, "/api/v1/lote/crear?wait=true", map[string]interface{}{
+		"account":        "fabricante",
+		"loteId":         "LOTE_E2E_001",
+		"temperaturaMin": 2,
+		"temperaturaMax": 8,
+	}, &deploy)
+	if status != http.StatusOK || response.Estado == nil || response.Estado.Estado != models.EstadoConfirmada || response.Network != "local" {
+		t.Fatalf("Expected confirmed deploy on the local network, got %d %+v", status, response)
+	}
+	if response.Estado.ContractAddress != deploy.ContractAddress || deploy.From != api.address("fabricante") {
+		t.Errorf("Expected deploy from fabricante at %s, got %+v", response.Estado.ContractAddress, deploy)
+	}
+	contrato := deploy.ContractAddress
+
+	// Sin el rol de oráculo no se registran lecturas
+	status, response = api.do(http.MethodPost, "/api/v1/lote/temperatura", map[string]interface{}{
+		"account":         "fabricante",
+		"contractAddress": contrato,
+		"tempMin":         3,
+		"tempMax":         7,
+	}, nil)
+	if status != http.StatusForbidden {
+		t.Errorf("Expected 403 without the oracle role, got %d %q", status, response.Message)
+	}
+
+	// El fabricante administra los roles del lote
+	for _, asignacion := range []struct{ rol, cuenta string }{
+		{"oraculo", api.address("fabricante")},
+		{"distribuidor", distribuidor},
+	} {
+		status, response = api.do(http.MethodPost, "/api/v1/lote/roles/otorgar?wait=true", map[string]interface{}{
+			"account":         "fabricante",
+			"contractAddress": contrato,
+			"rol":             asignacion.rol,
+			"cuenta":          asignacion.cuenta,
+		}, nil)
+		if status != http.StatusOK || response.Estado.Estado != models.EstadoConfirmada {
+			t.Fatalf("Expected %s role to be granted, got %d %+v", asignacion.rol, status, response)
+		}
+	}
+	if status, response := api.do(http.MethodPost, "/api/v1/lote/roles/otorgar", map[string]interface{}{
+		"account":         "distribuidor",
+		"contractAddress": contrato,
+		"rol":             "farmacia",
+		"cuenta":          distribuidor,
+	}, nil); status != http.StatusForbidden {
+		t.Errorf("Expected 403 for a grant by a non-admin, got %d %q", status, response.Message)
+	}
+	var roles models.RolesLoteResponse
+	status, _ = api.do(http.MethodGet, "/api/v1/lote/roles/"+contrato, nil, &roles)
+	if status != http.StatusOK || roles.Admin != api.address("fabricante") ||
+		strings.Join(roles.Roles["distribuidor"], ",") != distribuidor || strings.Join(roles.Roles["oraculo"], ",") != api.address("fabricante") {
+		t.Errorf("Unexpected lote roles %d %+v", status, roles)
+	}
+	var rolesCuenta struct {
+		Roles []string `json:"roles"`
+	}
+	status, _ = api.do(http.MethodGet, "/api/v1/lote/roles/"+contrato+"/"+api.address("fabricante"), nil, &rolesCuenta)
+	if status != http.StatusOK || strings.Join(rolesCuenta.Roles, ",") != "fabricante,oraculo" {
+		t.Errorf("Expected fabricante and oraculo roles, got %d %v", status, rolesCuenta.Roles)
+	}
+
+	// Una lectura fuera de rango compromete el lote
+	status, response = api.do(http.MethodPost, "/api/v1/lote/temperatura?wait=true", map[string]interface{}{
+		"account":         "fabricante",
+		"contractAddress": contrato,
+		"tempMin":         1,
+		"tempMax":         12,
+		"sensorId":        "SENSOR-E2E",
+	}, nil)
+	if status != http.StatusOK || response.Estado.Estado != models.EstadoConfirmada {
+		t.Fatalf("Expected temperature to be registered, got %d %+v", status, response)
+	}
+
+	// La transferencia directa se retiró en favor del traspaso en dos pasos
+	status, response = api.do(http.MethodPost, "/api/v1/lote/transferir", map[string]interface{}{
+		"account":          "fabricante",
+		"contractAddress":  contrato,
+		"nuevoPropietario": distribuidor,
+	}, nil)
+	if status != http.StatusGone || !strings.Contains(response.Message, "/api/v1/lote/custodia/proponer") {
+		t.Errorf("Expected 410 pointing to the custody proposal, got %d %q", status, response.Message)
+	}
+
+	// Ceder la custodia al distribuidor, que la acepta
+	status, response = api.do(http.MethodPost, "/api/v1/lote/custodia/proponer?wait=true", map[string]interface{}{
+		"account":         "fabricante",
+		"contractAddress": contrato,
+		"destinatario":    distribuidor,
+	}, nil)
+	if status != http.StatusOK || response.Estado.Estado != models.EstadoConfirmada {
+		t.Fatalf("Expected custody proposal, got %d %+v", status, response)
+	}
+	status, response = api.do(http.MethodPost, "/api/v1/lote/custodia/aceptar?wait=true", map[string]interface{}{
+		"account":         "distribuidor",
+		"contractAddress": contrato,
+	}, nil)
+	if status != http.StatusOK || response.Estado.Estado != models.EstadoConfirmada {
+		t.Fatalf("Expected custody acceptance, got %d %+v", status, response)
+	}
+	transferHash := response.TxHash
+
+	// El fabricante ya no es el propietario
+	status, response = api.do(http.MethodPost, "/api/v1/lote/custodia/proponer", map[string]interface{}{
+		"account":         "fabricante",
+		"contractAddress": contrato,
+		"destinatario":    distribuidor,
+	}, nil)
+	if status != http.StatusUnprocessableEntity || !strings.Contains(response.Message, "Accion solo permitida para el propietario actual") {
+		t.Errorf("Expected 422 with revert reason, got %d %q", status, response.Message)
+	}
+
+	// Estado actual del lote
+	var info models.LoteInfoResponse
+	status, _ = api.do(http.MethodGet, "/api/v1/lote/info/"+contrato, nil, &info)
+	if status != http.StatusOK {
+		t.Fatalf("Expected lote info, got %d", status)
+	}
+	if info.LoteID != "LOTE_E2E_001" || info.PropietarioActual != distribuidor || !info.Comprometido ||
+		info.TempRegMinima != 1 || info.TempRegMaxima != 12 {
+		t.Errorf("Unexpected lote info %+v", info)
+	}
+
+	// Historial de eventos
+	var cadena models.CadenaBlockchainResponse
+	status, _ = api.do(http.MethodGet, "/api/v1/lote/cadena/"+contrato, nil, &cadena)
+	if status != http.StatusOK {
+		t.Fatalf("Expected event history, got %d", status)
+	}
+	tipos := make([]string, 0, len(cadena.Eventos))
+	for _, evento := range cadena.Eventos {
+		tipos = append(tipos, evento.TipoEvento)
+	}
+	if strings.Join(tipos, ",") != "AdminTransferido,RolOtorgado,LoteCreado,RolOtorgado,RolOtorgado,TemperaturaRegistrada,LoteComprometido,CustodiaPropuesta,CustodiaAceptada,CustodiaTransferida" || cadena.TotalEventos != 10 {
+		t.Fatalf("Unexpected event history %v", tipos)
+	}
+	if cadena.LoteID != "LOTE_E2E_001" || cadena.Eventos[2].Datos["loteId"] != "LOTE_E2E_001" {
+		t.Errorf("Expected loteId in history, got %q and %v", cadena.LoteID, cadena.Eventos[2].Datos)
+	}
+	transferencia := cadena.Eventos[9]
+	if transferencia.Datos["nuevoPropietario"] != distribuidor || transferencia.Datos["comprometido"] != true {
+		t.Errorf("Unexpected transfer event %v", transferencia.Datos)
+	}
+	if cadena.IndexadoHasta < transferencia.BlockNumber || transferencia.Timestamp == 0 {
+		t.Errorf("Expected indexed block and timestamp, got %d and %d", cadena.IndexadoHasta, transferencia.Timestamp)
+	}
+
+	// Serie temporal de lecturas
+	var temperaturas models.TemperaturasLoteResponse
+	status, _ = api.do(http.MethodGet, "/api/v1/lote/temperaturas/"+contrato, nil, &temperaturas)
+	if status != http.StatusOK || temperaturas.TotalLecturas != 1 || temperaturas.LecturasFueraDeRango != 1 || temperaturas.LoteID != "LOTE_E2E_001" {
+		t.Fatalf("Expected one out-of-range reading, got %d %+v", status, temperaturas)
+	}
+	if lectura := temperaturas.Lecturas[0]; lectura.SensorID != "SENSOR-E2E" || lectura.TempMax != 12 || lectura.Timestamp == 0 {
+		t.Errorf("Unexpected reading %+v", lectura)
+	}
+	if status, _ := api.do(http.MethodGet, "/api/v1/lote/temperaturas/"+contrato+"?desde=ayer", nil, nil); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid timestamp, got %d", status)
+	}
+
+	// Estado de la transferencia por hash
+	status, response = api.do(http.MethodGet, "/api/v1/tx/"+transferHash, nil, nil)
+	if status != http.StatusOK || response.Estado == nil || response.Estado.Operacion != "aceptarCustodia" {
+		t.Errorf("Expected transfer status, got %d %+v", status, response)
+	}
+
+	// Registro de lotes por loteId y por propietario
+	var registro models.RegistroLote
+	status, _ = api.do(http.MethodGet, "/api/v1/lote/by-id/LOTE_E2E_001", nil, &registro)
+	if status != http.StatusOK || registro.ContractAddress != contrato || registro.Network != "local" {
+		t.Fatalf("Expected lote in registry, got %d %+v", status, registro)
+	}
+	if registro.PropietarioActual != distribuidor || !registro.Comprometido || registro.Fabricante != api.address("fabricante") || registro.BlockNumber == 0 {
+		t.Errorf("Unexpected registry entry %+v", registro)
+	}
+	var lotes []models.RegistroLote
+	status, _ = api.do(http.MethodGet, "/api/v1/lote/registro?comprometido=true&propietario="+distribuidor, nil, &lotes)
+	if status != http.StatusOK || len(lotes) != 1 || lotes[0].LoteID != "LOTE_E2E_001" {
+		t.Errorf("Expected lote owned by distribuidor, got %d %+v", status, lotes)
+	}
+	if status, _ := api.do(http.MethodGet, "/api/v1/lote/by-id/LOTE_DESCONOCIDO", nil, nil); status != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown loteId, got %d", status)
+	}
+}
+
+func TestE2E_FactoryLotes(t *testing.T) {
+	api := newE2EAPI(t)
+	fabricante := api.address("fabricante")
+	distribuidor := api.address("distribuidor")
+
+	var factory models.FactoryInfo
+	if status, _ := api.do(http.MethodGet, "/api/v1/factory", nil, &factory); status != http.StatusOK || factory.Admin != fabricante {
+		t.Fatalf("Expected factory administered by fabricante, got %d %+v", status, factory)
+	}
+
+	// Crear el lote en la factory, sin desplegar un contrato
+	status, response := api.do(http.MethodPost, "/api/v1/factory/lote?wait=true", map[string]interface{}{
+		"account":        "fabricante",
+		"loteId":         "LOTE_FACTORY_001",
+		"temperaturaMin": 2,
+		"temperaturaMax": 8,
+	}, nil)
+	if status != http.StatusOK || response.Estado == nil || response.Estado.Estado != models.EstadoConfirmada || response.Estado.Operacion != "crearLote" {
+		t.Fatalf("Expected confirmed lote creation, got %d %+v", status, response)
+	}
+
+	// Un loteId no se puede volver a crear y solo los fabricantes crean lotes
+	if status, _ := api.do(http.MethodPost, "/api/v1/factory/lote", map[string]interface{}{
+		"account": "fabricante", "loteId": "LOTE_FACTORY_001", "temperaturaMin": 1, "temperaturaMax": 4,
+	}, nil); status != http.StatusConflict {
+		t.Errorf("Expected 409 for a duplicated loteId, got %d", status)
+	}
+	status, response = api.do(http.MethodPost, "/api/v1/factory/lote", map[string]interface{}{
+		"account": "distribuidor", "loteId": "LOTE_FACTORY_002", "temperaturaMin": 2, "temperaturaMax": 8,
+	}, nil)
+	if status != http.StatusUnprocessableEntity || !strings.Contains(response.Message, "Cuenta no autorizada como fabricante") {
+		t.Errorf("Expected 422 for an unauthorised manufacturer, got %d %q", status, response.Message)
+	}
+	if status, _ := api.do(http.MethodPost, "/api/v1/factory/fabricantes?wait=true", map[string]interface{}{
+		"account": "fabricante", "fabricante": distribuidor, "autorizado": true,
+	}, nil); status != http.StatusOK {
+		t.Errorf("Expected distribuidor to be authorised, got %d", status)
+	}
+
+	// Los lotes de la factory son de solo lectura
+	for _, path := range []string{"/api/v1/factory/temperatura", "/api/v1/factory/transferir"} {
+		status, response := api.do(http.MethodPost, path, map[string]interface{}{
+			"account": "fabricante", "loteId": "LOTE_FACTORY_001", "tempMin": 1, "tempMax": 12, "nuevoPropietario": distribuidor,
+		}, nil)
+		if status != http.StatusGone || !strings.Contains(response.Message, "solo lectura") {
+			t.Errorf("Expected 410 from %s, got %d %q", path, status, response.Message)
+		}
+	}
+
+	var lote models.LoteFactoryInfo
+	status, _ = api.do(http.MethodGet, "/api/v1/factory/lote/LOTE_FACTORY_001", nil, &lote)
+	if status != http.StatusOK || lote.PropietarioActual != fabricante || lote.Comprometido || lote.Fabricante != fabricante {
+		t.Errorf("Unexpected factory lote %d %+v", status, lote)
+	}
+	var cadena models.CadenaBlockchainResponse
+	status, _ = api.do(http.MethodGet, "/api/v1/factory/cadena/LOTE_FACTORY_001", nil, &cadena)
+	if status != http.StatusOK || cadena.TotalEventos != 1 || cadena.Eventos[0].Datos["loteId"] != "LOTE_FACTORY_001" {
+		t.Errorf("Expected the lote history from the factory, got %d %+v", status, cadena)
+	}
+	if status, _ := api.do(http.MethodGet, "/api/v1/factory/lote/LOTE_DESCONOCIDO", nil, nil); status != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown factory lote, got %d", status)
+	}
+}
+
+func TestE2E_OracleAnchorsReadings(t *testing.T) {
+	api := newE2EAPI(t)
+	oraculo := api.address("fabricante")
+
+	var deploy models.ContractDeployResponse
+	if status, response := api.do(http.MethodPost, "/api/v1/lote/crear?wait=true", map[string]interface{}{
+		"account":        "fabricante",
+		"loteId":         "LOTE_E2E_ORACLE",
+		"temperaturaMin": 2,
+		"temperaturaMax": 8,
+	}, &deploy); status != http.StatusOK {
+		t.Fatalf("Expected deploy, got %d %q", status, response.Message)
+	}
+	contrato := deploy.ContractAddress
+	if status, response := api.do(http.MethodPost, "/api/v1/lote/roles/otorgar?wait=true", map[string]interface{}{
+		"account":         "fabricante",
+		"contractAddress": contrato,
+		"rol":             "oraculo",
+		"cuenta":          oraculo,
+	}, nil); status != http.StatusOK {
+		t.Fatalf("Expected oracle role to be granted, got %d %q", status, response.Message)
+	}
+
+	// Sin asignación el sensor no tiene lote
+	lectura := map[string]interface{}{
+		"id":        "evt_1",
+		"timestamp": "2026-10-19T10:00:00Z",
+		"type":      "sensor_reading",
+		"source":    "temperature_sensor_03",
+		"data":      map[string]interface{}{"temperature": 4.5, "humidity": 48, "status": "active"},
+	}
+	if status, _ := api.do(http.MethodPost, "/api/v1/oracle/lecturas", lectura, nil); status != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for an unassigned sensor, got %d", status)
+	}
+	if status, response := api.do(http.MethodPost, "/api/v1/oracle/sensores", map[string]interface{}{
+		"sensorId":        "temperature_sensor_03",
+		"contractAddress": contrato,
+	}, nil); status != http.StatusOK {
+		t.Fatalf("Expected sensor to be assigned, got %d %q", status, response.Message)
+	}
+
+	// Lecturas con el formato del mqtt-event-generator
+	for i, temperatura := range []float64{4.5, 3.2, 6.8} {
+		lectura["id"] = fmt.Sprintf("evt_%d", i+1)
+		lectura["data"] = map[string]interface{}{"temperature": temperatura, "humidity": 48, "status": "active"}
+		if status, response := api.do(http.MethodPost, "/api/v1/oracle/lecturas", lectura, nil); status != http.StatusAccepted {
+			t.Fatalf("Expected reading %d to be accepted, got %d %q", i, status, response.Message)
+		}
+	}
+	if status, _ := api.do(http.MethodGet, "/api/v1/oracle/prueba/evt_2", nil, nil); status != http.StatusConflict {
+		t.Errorf("Expected 409 while the batch is open, got %d", status)
+	}
+
+	var anclaje map[string]int
+	if status, response := api.do(http.MethodPost, "/api/v1/oracle/anclar", nil, &anclaje); status != http.StatusOK || anclaje["anclados"] != 1 {
+		t.Fatalf("Expected one batch anchored, got %d %q %v", status, response.Message, anclaje)
+	}
+
+	var prueba models.PruebaLectura
+	if status, response := api.do(http.MethodGet, "/api/v1/oracle/prueba/evt_2", nil, &prueba); status != http.StatusOK || prueba.Estado != models.LoteLecturasAnclado || len(prueba.Prueba) == 0 {
+		t.Fatalf("Expected proof of an anchored reading, got %d %q %+v", status, response.Message, prueba)
+	}
+	if prueba.Lectura.Temperatura != 3.2 || prueba.ContractAddress != contrato {
+		t.Errorf("Expected the second reading in %s, got %+v", contrato, prueba)
+	}
+
+	var verificacion models.VerificacionLectura
+	api.do(http.MethodPost, "/api/v1/oracle/verificar", map[string]interface{}{
+		"lectura":    prueba.Lectura,
+		"prueba":     prueba.Prueba,
+		"raizMerkle": prueba.RaizMerkle,
+	}, &verificacion)
+	if !verificacion.Valida || !verificacion.Anclada {
+		t.Errorf("Expected the reading to verify on-chain, got %+v", verificacion)
+	}
+
+	// El anclaje queda en el historial del contrato con el rango del lote
+	var cadena models.CadenaBlockchainResponse
+	api.do(http.MethodGet, "/api/v1/lote/cadena/"+contrato, nil, &cadena)
+	anclado := false
+	for _, evento := range cadena.Eventos {
+		if evento.TipoEvento == "LecturasAncladas" {
+			anclado = evento.Datos["raizMerkle"] == prueba.RaizMerkle && evento.Datos["tempMin"] == float64(3) && evento.Datos["tempMax"] == float64(7)
+		}
+	}
+	if !anclado {
+		t.Errorf("Expected a LecturasAncladas event with range 3..7, got %+v", cadena.Eventos)
+	}
+}
+
+func TestE2E_OracleWritesRequireOracleClient(t *testing.T) {
+	api := newE2EAPI(t)
+	escrituras := []struct {
+		path string
+		body interface{}
+	}{
+		{"/api/v1/oracle/lecturas", map[string]interface{}{"id": "evt_1", "sensorId": "temperature_sensor_03", "temperatura": 40}},
+		{"/api/v1/oracle/sensores", map[string]interface{}{"sensorId": "temperature_sensor_03", "contractAddress": api.address("distribuidor")}},
+		{"/api/v1/oracle/anclar", nil},
+	}
+
+	// Sin API key nadie asigna sensores, envía lecturas ni fuerza anclajes
+	api.apiKey = ""
+	for _, escritura := range escrituras {
+		if status, _ := api.do(http.MethodPost, escritura.path, escritura.body, nil); status != http.StatusUnauthorized {
+			t.Errorf("Expected 401 for an anonymous POST %s, got %d", escritura.path, status)
+		}
+	}
+	if status, _ := api.do(http.MethodGet, "/api/v1/oracle/sensores", nil, nil); status != http.StatusOK {
+		t.Errorf("Expected oracle reads to stay open, got %d", status)
+	}
+
+	// Un cliente que no puede usar la cuenta del oráculo tampoco
+	api.apiKey = "clave-distribucion"
+	for _, escritura := range escrituras {
+		if status, _ := api.do(http.MethodPost, escritura.path, escritura.body, nil); status != http.StatusForbidden {
+			t.Errorf("Expected 403 for POST %s outside the oracle allow-list, got %d", escritura.path, status)
+		}
+	}
+
+	var sensores map[string]interface{}
+	api.do(http.MethodGet, "/api/v1/oracle/sensores", nil, &sensores)
+	if asignados, _ := sensores["sensores"].(map[string]interface{}); len(asignados) != 0 {
+		t.Errorf("Expected no sensor to be assigned, got %v", asignados)
+	}
+}
+
+func TestE2E_WebsocketControlRequiresAPIKey(t *testing.T) {
+	api := newE2EAPI(t)
+	rutas := []string{"/api/v1/websocket/iniciar", "/api/v1/websocket/detener"}
+
+	// Sin API key nadie detiene ni arranca el vigilante
+	api.apiKey = ""
+	for _, ruta := range rutas {
+		if status, _ := api.do(http.MethodPost, ruta, nil, nil); status != http.StatusUnauthorized {
+			t.Errorf("Expected 401 for an anonymous POST %s, got %d", ruta, status)
+		}
+	}
+	if status, _ := api.do(http.MethodGet, "/api/v1/websocket", nil, nil); status != http.StatusOK {
+		t.Errorf("Expected the websocket status to stay open, got %d", status)
+	}
+
+	// Autenticado llega al vigilante, que la red simulada no tiene
+	api.apiKey = "clave-distribucion"
+	for _, ruta := range rutas {
+		if status, _ := api.do(http.MethodPost, ruta, nil, nil); status != http.StatusNotFound {
+			t.Errorf("Expected 404 for POST %s on a network without WebSocket, got %d", ruta, status)
+		}
+	}
+}
+
+func TestE2E_CustodyHandoverWithSignedAcceptance(t *testing.T) {
+	api := newE2EAPI(t)
+	distribuidor := api.address("distribuidor")
+
+	var deploy models.ContractDeployResponse
+	if status, response := api.do(http.MethodPost, "/api/v1/lote/crear?wait=true", map[string]interface{}{
+		"account":        "fabricante",
+		"loteId":         "LOTE_E2E_CUSTODIA",
+		"temperaturaMin": 2,
+		"temperaturaMax": 8,
+	}, &deploy); status != http.StatusOK {
+		t.Fatalf("Expected deploy, got %d %q", status, response.Message)
+	}
+	contrato := deploy.ContractAddress
+	if status, response := api.do(http.MethodPost, "/api/v1/lote/roles/otorgar?wait=true", map[string]interface{}{
+		"account":         "fabricante",
+		"contractAddress": contrato,
+		"rol":             "distribuidor",
+		"cuenta":          distribuidor,
+	}, nil); status != http.StatusOK {
+		t.Fatalf("Expected distributor role to be granted, got %d %q", status, response.Message)
+	}
+
+	if status, _ := api.do(http.MethodPost, "/api/v1/lote/custodia/proponer", map[string]interface{}{
+		"account":         "fabricante",
+		"contractAddress": contrato,
+		"destinatario":    distribuidor,
+		"plazo":           "1000h",
+	}, nil); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a term above 30 days, got %d", status)
+	}
+	envio := models.DetallesEnvio{Guia: "GUIA-E2E-1", Transportista: "FrioExpress", Origen: "Bodega Norte", Destino: "CD Sur", Bultos: 4}
+	if status, response := api.do(http.MethodPost, "/api/v1/lote/custodia/proponer?wait=true", map[string]interface{}{
+		"account":         "fabricante",
+		"contractAddress": contrato,
+		"destinatario":    distribuidor,
+		"plazo":           "48h",
+		"envio":           envio,
+	}, nil); status != http.StatusOK {
+		t.Fatalf("Expected custody to be proposed, got %d %q", status, response.Message)
+	}
+
+	var propuesta struct {
+		models.PropuestaCustodia
+		TypedData apitypes.TypedData `json:"typedData"`
+	}
+	if status, response := api.do(http.MethodGet, "/api/v1/lote/custodia/"+contrato, nil, &propuesta); status != http.StatusOK || !propuesta.Pendiente {
+		t.Fatalf("Expected a pending proposal, got %d %q %+v", status, response.Message, propuesta)
+	}
+	if propuesta.Destinatario != distribuidor || propuesta.DetallesEnvio == nil || *propuesta.DetallesEnvio != envio {
+		t.Errorf("Expected the proposal to the distributor with its shipment, got %+v", propuesta.PropuestaCustodia)
+	}
+
+	// El distribuidor firma los datos tipados con su cartera y el fabricante envía la firma
+	hash, _, err := apitypes.TypedDataAndHash(propuesta.TypedData)
+	if err != nil || hexutil.Encode(hash) != propuesta.HashAceptacion {
+		t.Fatalf("Expected the typed data to hash to %s, got %x %v", propuesta.HashAceptacion, hash, err)
+	}
+	clave, _ := crypto.ToECDSA(crypto.Keccak256([]byte("CrearLoteMicro/dev/distribuidor")))
+	firma, _ := crypto.Sign(hash, clave)
+	otra, _ := crypto.ToECDSA(crypto.Keccak256([]byte("CrearLoteMicro/dev/otra")))
+	ajena, _ := crypto.Sign(hash, otra)
+
+	if status, _ := api.do(http.MethodPost, "/api/v1/lote/custodia/aceptar", map[string]interface{}{
+		"account":         "fabricante",
+		"contractAddress": contrato,
+		"firma":           hexutil.Encode(ajena),
+	}, nil); status != http.StatusForbidden {
+		t.Errorf("Expected 403 for another account's signature, got %d", status)
+	}
+	var aceptada map[string]interface{}
+	if status, response := api.do(http.MethodPost, "/api/v1/lote/custodia/aceptar?wait=true", map[string]interface{}{
+		"account":         "fabricante",
+		"contractAddress": contrato,
+		"firma":           hexutil.Encode(firma),
+	}, &aceptada); status != http.StatusOK || aceptada["firmada"] != true {
+		t.Fatalf("Expected the signed acceptance to be relayed, got %d %q", status, response.Message)
+	}
+	if status, _ := api.do(http.MethodPost, "/api/v1/lote/custodia/aceptar", map[string]interface{}{
+		"account":         "distribuidor",
+		"contractAddress": contrato,
+	}, nil); status != http.StatusConflict {
+		t.Errorf("Expected 409 without a pending proposal, got %d", status)
+	}
+
+	var info models.LoteInfoResponse
+	api.do(http.MethodGet, "/api/v1/lote/info/"+contrato, nil, &info)
+	if info.PropietarioActual != distribuidor {
+		t.Errorf("Expected the distributor to own the lote, got %s", info.PropietarioActual)
+	}
+
+	var cadena models.CadenaBlockchainResponse
+	api.do(http.MethodGet, "/api/v1/lote/cadena/"+contrato, nil, &cadena)
+	var tipos []string
+	for _, evento := range cadena.Eventos {
+		tipos = append(tipos, evento.TipoEvento)
+	}
+	if !strings.HasSuffix(strings.Join(tipos, ","), "CustodiaPropuesta,CustodiaAceptada,CustodiaTransferida") {
+		t.Errorf("Expected the handover in the contract history, got %v", tipos)
+	}
+}
+
+func TestE2E_CertificateExportAndVerification(t *testing.T) {
+	api := newE2EAPI(t)
+
+	var deploy models.ContractDeployResponse
+	if status, response := api.do(http.MethodPost, "/api/v1/lote/crear?wait=true", map[string]interface{}{
+		"account":        "fabricante",
+		"loteId":         "LOTE_E2E_CERT",
+		"temperaturaMin": 2,
+		"temperaturaMax": 8,
+	}, &deploy); status != http.StatusOK {
+		t.Fatalf("Expected deploy, got %d %q", status, response.Message)
+	}
+	contrato := deploy.ContractAddress
+	for _, paso := range []struct {
+		path string
+		body map[string]interface{}
+	}{
+		{"/api/v1/lote/roles/otorgar", map[string]interface{}{"rol": "oraculo", "cuenta": api.address("fabricante")}},
+		{"/api/v1/lote/roles/otorgar", map[string]interface{}{"rol": "distribuidor", "cuenta": api.address("distribuidor")}},
+		{"/api/v1/lote/temperatura", map[string]interface{}{"tempMin": 3, "tempMax": 7, "sensorId": "SENSOR-E2E"}},
+		{"/api/v1/lote/custodia/proponer", map[string]interface{}{"destinatario": api.address("distribuidor")}},
+		{"/api/v1/lote/custodia/aceptar", map[string]interface{}{"account": "distribuidor"}},
+	} {
+		if paso.body["account"] == nil {
+			paso.body["account"] = "fabricante"
+		}
+		paso.body["contractAddress"] = contrato
+		if status, response := api.do(http.MethodPost, paso.path+"?wait=true", paso.body, nil); status != http.StatusOK {
+			t.Fatalf("POST %s: expected 200, got %d %q", paso.path, status, response.Message)
+		}
+	}
+
+	var documento models.CertificadoFirmado
+	status, response := api.do(http.MethodGet, "/api/v1/lote/certificado/"+contrato, nil, &documento)
+	if status != http.StatusOK || !documento.Certificado.CadenaFrioIntacta || documento.Certificado.Emisor != api.address("fabricante") ||
+		len(documento.Certificado.Custodia) != 2 || documento.Certificado.TotalLecturas != 1 {
+		t.Fatalf("Expected a signed certificate with intact cold chain, got %d %q %+v", status, response.Message, documento)
+	}
+
+	var verificacion models.VerificacionCertificado
+	status, response = api.do(http.MethodPost, "/api/v1/lote/certificado/verificar", documento, &verificacion)
+	if status != http.StatusOK || !verificacion.Valido || !verificacion.EmisorReconocido || response.Message != "Certificado válido" {
+		t.Errorf("Expected the certificate to verify, got %d %q %+v", status, response.Message, verificacion)
+	}
+
+	// El PDF lleva el certificado adjunto y también se puede verificar
+	recorder := httptest.NewRecorder()
+	api.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/lote/certificado/"+contrato+"?formato=pdf", nil))
+	if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Type") != "application/pdf" || !bytes.HasPrefix(recorder.Body.Bytes(), []byte("%PDF-")) {
+		t.Fatalf("Expected a PDF, got %d %s", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lote/certificado/verificar", bytes.NewReader(recorder.Body.Bytes()))
+	req.Header.Set("Content-Type", "application/pdf")
+	recorder = httptest.NewRecorder()
+	api.router.ServeHTTP(recorder, req)
+	var respuestaPDF struct {
+		Data models.VerificacionCertificado `json:"data"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &respuestaPDF); err != nil || recorder.Code != http.StatusOK || !respuestaPDF.Data.Valido {
+		t.Errorf("Expected the PDF certificate to verify, got %d %s", recorder.Code, recorder.Body.String())
+	}
+
+	// Un certificado alterado no es válido
+	documento.Certificado.Lote.PropietarioActual = api.address("fabricante")
+	status, response = api.do(http.MethodPost, "/api/v1/lote/certificado/verificar", documento, &verificacion)
+	if status != http.StatusOK || verificacion.Valido || verificacion.HashCoincide {
+		t.Errorf("Expected the altered certificate to fail, got %d %q %+v", status, response.Message, verificacion)
+	}
+
+	if status, _ := api.do(http.MethodGet, "/api/v1/lote/certificado/0x000000000000000000000000000000000000dEaD", nil, nil); status != http.StatusNotFound {
+		t.Errorf("Expected 404 without a contract, got %d", status)
+	}
+}
+
+func TestE2E_PublicLoteAndQR(t *testing.T) {
+	api := newE2EAPI(t)
+
+	var deploy models.ContractDeployResponse
+	if status, response := api.do(http.MethodPost, "/api/v1/lote/crear?wait=true", map[string]interface{}{
+		"account":        "fabricante",
+		"loteId":         "LOTE_E2E_PUBLICO",
+		"temperaturaMin": 2,
+		"temperaturaMax": 8,
+	}, &deploy); status != http.StatusOK {
+		t.Fatalf("Expected deploy, got %d %q", status, response.Message)
+	}
+
+	// El QR apunta a la página pública del lote
+	for _, formato := range []struct{ nombre, contentType, prefijo string }{
+		{"png", "image/png", "\x89PNG"},
+		{"svg", "image/svg+xml", "<svg"},
+	} {
+		recorder := httptest.NewRecorder()
+		api.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/lote/qr/LOTE_E2E_PUBLICO?tamano=128&formato="+formato.nombre, nil))
+		if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Type") != formato.contentType ||
+			!bytes.HasPrefix(recorder.Body.Bytes(), []byte(formato.prefijo)) {
+			t.Errorf("Expected a %s QR code, got %d %s", formato.nombre, recorder.Code, recorder.Header().Get("Content-Type"))
+		}
+		if destino := recorder.Header().Get("X-QR-URL"); destino != "http://example.com/api/v1/public/lote/LOTE_E2E_PUBLICO?network=local" {
+			t.Errorf("Unexpected QR URL %q", destino)
+		}
+	}
+	if status, _ := api.do(http.MethodGet, "/api/v1/lote/qr/LOTE_E2E_PUBLICO?tamano=10", nil, nil); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a tiny QR code, got %d", status)
+	}
+	if status, _ := api.do(http.MethodGet, "/api/v1/lote/qr/LOTE_INEXISTENTE", nil, nil); status != http.StatusNotFound {
+		t.Errorf("Expected 404 for the QR of an unknown lote, got %d", status)
+	}
+
+	var resumen models.ResumenPublicoLote
+	status, response := api.do(http.MethodGet, "/api/v1/public/lote/LOTE_E2E_PUBLICO", nil, &resumen)
+	if status != http.StatusOK || resumen.Estado != models.EstadoLoteIntegro || resumen.ContractAddress != deploy.ContractAddress ||
+		len(resumen.Custodia) != 1 || resumen.PropietarioActual != api.address("fabricante") {
+		t.Errorf("Expected an intact public summary, got %d %q %+v", status, response.Message, resumen)
+	}
+
+	// Los navegadores reciben la página HTML
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/public/lote/"+deploy.ContractAddress, nil)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	recorder := httptest.NewRecorder()
+	api.router.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK || !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/html") ||
+		!strings.Contains(recorder.Body.String(), "Cadena de frío íntegra") || recorder.Header().Get("Cache-Control") != "public, max-age=30" {
+		t.Errorf("Expected the public HTML page, got %d %s", recorder.Code, recorder.Body.String())
+	}
+
+	if status, _ := api.do(http.MethodGet, "/api/v1/public/lote/LOTE_INEXISTENTE", nil, nil); status != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown lote, got %d", status)
+	}
+
+	// Agotada la ráfaga, el endpoint público limita por IP. Sin proxies de
+	// confianza, cambiar X-Forwarded-For en cada solicitud no renueva la ráfaga
+	limitado := false
+	for i := 0; i < 10 && !limitado; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/public/lote/LOTE_E2E_PUBLICO", nil)
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("203.0.113.%d", i+1))
+		recorder := httptest.NewRecorder()
+		api.router.ServeHTTP(recorder, req)
+		limitado = recorder.Code == http.StatusTooManyRequests && recorder.Header().Get("Retry-After") != ""
+	}
+	if !limitado {
+		t.Error("Expected 429 with Retry-After after exhausting the burst")
+	}
+}
+
+func TestE2E_SimulatedConnectionAndAccounts(t *testing.T) {
+	api := newE2EAPI(t)
+
+	var redes []models.RedInfo
+	if status, _ := api.do(http.MethodGet, "/api/v1/redes", nil, &redes); status != http.StatusOK || len(redes) != 1 || !redes[0].PorDefecto || redes[0].ChainID != 1337 {
+		t.Errorf("Expected the local network as default, got %d %+v", status, redes)
+	}
+	if status, _ := api.do(http.MethodGet, "/api/v1/debug/conexion?network=mainnet", nil, nil); status != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown network, got %d", status)
+	}
+
+	var conexion map[string]interface{}
+	if status, _ := api.do(http.MethodGet, "/api/v1/debug/conexion", nil, &conexion); status != http.StatusOK || conexion["chainId"] != "1337" {
+		t.Errorf("Expected simulated chain 1337, got %d %v", status, conexion)
+	}
+
+	var cuentas struct {
+		Cuentas []signer.AccountInfo `json:"cuentas"`
+	}
+	if status, _ := api.do(http.MethodGet, "/api/v1/cuentas", nil, &cuentas); status != http.StatusOK || len(cuentas.Cuentas) != 2 || cuentas.Cuentas[0].Type != signer.KindDev {
+		t.Errorf("Expected two dev accounts, got %d %+v", status, cuentas)
+	}
+}
+
+func TestE2E_SigningRequiresAPIKeyWithAllowedAccount(t *testing.T) {
+	api := newE2EAPI(t)
+	crear := func(account string) map[string]interface{} {
+		return map[string]interface{}{
+			"account": account, "loteId": "LOTE_E2E_AUTH", "temperaturaMin": 2, "temperaturaMax": 8,
+		}
+	}
+
+	// Sin API key no se firma, aunque las rutas de lectura siguen abiertas
+	api.apiKey = ""
+	if status, _ := api.do(http.MethodPost, "/api/v1/lote/crear", crear("fabricante"), nil); status != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without an API key, got %d", status)
+	}
+	if status, _ := api.do(http.MethodGet, "/api/v1/cuentas", nil, nil); status != http.StatusOK {
+		t.Errorf("Expected read routes to stay open, got %d", status)
+	}
+
+	api.apiKey = "clave-desconocida"
+	if status, _ := api.do(http.MethodGet, "/api/v1/cuentas", nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for an unknown API key, got %d", status)
+	}
+
+	// El cliente de distribución solo puede firmar con distribuidor
+	api.apiKey = "clave-distribucion"
+	if status, response := api.do(http.MethodPost, "/api/v1/lote/crear", crear("fabricante"), nil); status != http.StatusForbidden {
+		t.Errorf("Expected 403 for an account outside the allow-list, got %d %q", status, response.Message)
+	}
+	if status, response := api.do(http.MethodPost, "/api/v1/lote/crear", crear("distribuidor"), nil); status != http.StatusOK {
+		t.Errorf("Expected the allowed account to sign, got %d %q", status, response.Message)
+	}
+
+	// Las claves privadas en el cuerpo están deshabilitadas por defecto
+	key, _ := crypto.GenerateKey()
+	body := crear("")
+	body["privateKey"] = hexutil.Encode(crypto.FromECDSA(key))[2:]
+	if status, _ := api.do(http.MethodPost, "/api/v1/lote/crear", body, nil); status != http.StatusBadRequest {
+		t.Errorf("Expected raw private keys to be rejected, got %d", status)
+	}
+}
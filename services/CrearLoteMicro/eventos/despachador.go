@@ -0,0 +1,149 @@
+// Package eventos publica los eventos de los contratos LoteTracing como
+// eventos de dominio en Kafka o RabbitMQ, para que el servicio de pedidos y
+// el almacén reaccionen a los lotes comprometidos y a los cambios de custodia.
+package eventos
+
+import (
+	"CrearLoteMicro/models"
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fuente entrega los eventos de dominio confirmados de una red y el último
+// bloque confirmado. La implementa services.BlockchainService.
+type Fuente interface {
+	EventosDominio(red string, confirmaciones uint64) ([]models.EventoDominioLote, uint64, error)
+}
+
+// Publicador envía un evento al broker. Publicar devuelve error si el broker
+// no confirmó el evento; el despachador lo reintenta en la siguiente pasada.
+type Publicador interface {
+	Publicar(ctx context.Context, evento models.EventoDominioLote) error
+	Close() error
+}
+
+// Options configura el despachador de eventos de una red
+type Options struct {
+	// Red es el nombre de la red de la que se publican los eventos
+	Red string
+	// Confirmaciones es la profundidad a la que un evento se publica
+	Confirmaciones uint64
+	// Intervalo es la frecuencia con la que se buscan eventos nuevos
+	Intervalo time.Duration
+	// Historial publica también los eventos anteriores al primer arranque;
+	// sin él, un despachador sin estado guardado empieza en el último bloque
+	// confirmado
+	Historial bool
+	// Store guarda lo publicado entre reinicios; nil lo mantiene en memoria
+	Store Store
+}
+
+// Despachador publica los eventos de dominio de una red en orden y como
+// mucho una vez por pasada. Guarda por contrato el último evento publicado:
+// tras un fallo del broker o un reinicio retoma desde ahí, así que la entrega
+// es al menos una vez y los consumidores deben descartar los EventID repetidos.
+type Despachador struct {
+	fuente     Fuente
+	publicador Publicador
+	options    Options
+
+	// mu serializa las pasadas
+	mu     sync.Mutex
+	estado *Estado
+}
+
+// New crea el despachador y carga lo ya publicado
+func New(fuente Fuente, publicador Publicador, options Options) (*Despachador, error) {
+	if options.Intervalo <= 0 {
+		options.Intervalo = 15 * time.Second
+	}
+
+	d := &Despachador{
+		fuente:     fuente,
+		publicador: publicador,
+		options:    options,
+	}
+	if options.Store != nil {
+		estado, err := options.Store.Load()
+		if err != nil {
+			return nil, err
+		}
+		d.estado = estado
+	}
+	return d, nil
+}
+
+// Run publica los eventos nuevos cada Intervalo hasta que se cancele el contexto
+func (d *Despachador) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.options.Intervalo)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.Publicar(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Eventos %s: %v", d.options.Red, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Publicar ejecuta una pasada y devuelve cuántos eventos publicó. Un fallo
+// del broker detiene la pasada en el evento que falló.
+func (d *Despachador) Publicar(ctx context.Context) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	eventos, confirmado, err := d.fuente.EventosDominio(d.options.Red, d.options.Confirmaciones)
+	if err != nil {
+		return 0, err
+	}
+
+	if d.estado == nil {
+		d.estado = &Estado{Cursores: make(map[string]Cursor)}
+		if !d.options.Historial {
+			d.estado.Inicio = confirmado + 1
+			log.Printf("Eventos %s: se publican los eventos desde el bloque %d", d.options.Red, d.estado.Inicio)
+		}
+		d.guardar()
+	}
+
+	publicados := 0
+	defer func() {
+		if publicados > 0 {
+			d.guardar()
+		}
+	}()
+	for _, evento := range eventos {
+		if evento.BlockNumber < d.estado.Inicio {
+			continue
+		}
+		contrato := strings.ToLower(evento.ContractAddress)
+		if cursor, ok := d.estado.Cursores[contrato]; ok && !cursor.anterior(evento) {
+			continue
+		}
+
+		if err := d.publicador.Publicar(ctx, evento); err != nil {
+			return publicados, err
+		}
+		d.estado.Cursores[contrato] = Cursor{BlockNumber: evento.BlockNumber, LogIndex: evento.LogIndex}
+		publicados++
+		log.Printf("Evento %s publicado: lote %s, contrato %s, tx %s", evento.EventType, evento.LoteID, evento.ContractAddress, evento.TxHash)
+	}
+	return publicados, nil
+}
+
+func (d *Despachador) guardar() {
+	if d.options.Store == nil {
+		return
+	}
+	if err := d.options.Store.Save(d.estado); err != nil {
+		log.Printf("Error guardando eventos publicados de %s: %v", d.options.Red, err)
+	}
+}
@@ -0,0 +1,170 @@
+package eventos
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/services"
+	"CrearLoteMicro/signer"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// publicadorPrueba guarda los eventos publicados y puede fallar a partir de
+// un número de eventos
+type publicadorPrueba struct {
+	eventos  []models.EventoDominioLote
+	fallarEn int
+}
+
+func (p *publicadorPrueba) Publicar(_ context.Context, evento models.EventoDominioLote) error {
+	if p.fallarEn > 0 && len(p.eventos)+1 >= p.fallarEn {
+		return errors.New("broker no disponible")
+	}
+	p.eventos = append(p.eventos, evento)
+	return nil
+}
+
+func (p *publicadorPrueba) Close() error { return nil }
+
+// newTestLote despliega un lote con rango 2..8, lo compromete con una lectura
+// fuera de rango y transfiere su custodia
+func newTestLote(t *testing.T) (*services.BlockchainService, map[string]signer.Signer, string) {
+	t.Helper()
+	cuentas := make(map[string]signer.Signer)
+	var addresses []common.Address
+	for _, name := range []string{"fabricante", "distribuidor"} {
+		cuentas[name], _ = signer.NewDevSigner(name)
+		addresses = append(addresses, cuentas[name].Address())
+	}
+
+	chain := services.NewSimulatedChain(services.SimulatedChainOptions{Accounts: addresses})
+	t.Cleanup(func() { chain.Close() })
+	bs, err := services.NewBlockchainServiceWithClient(chain, 1337, services.NonceManagerOptions{}, services.TxTrackerOptions{}, services.EventIndexerOptions{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	fabricante := cuentas["fabricante"]
+	contractAddress, _, err := bs.DeployContract(fabricante, "LOTE001", 2, 8)
+	if err != nil {
+		t.Fatalf("Failed to deploy contract: %v", err)
+	}
+	if _, err := bs.OtorgarRol(fabricante, contractAddress, services.RolOraculo, fabricante.Address().Hex()); err != nil {
+		t.Fatalf("Expected oracle role to be granted, got %v", err)
+	}
+	if _, err := bs.OtorgarRol(fabricante, contractAddress, services.RolDistribuidor, cuentas["distribuidor"].Address().Hex()); err != nil {
+		t.Fatalf("Expected distributor role to be granted, got %v", err)
+	}
+	if _, err := bs.RegistrarTemperatura(fabricante, contractAddress, 3, 11, "SENSOR-01"); err != nil {
+		t.Fatalf("Failed to register temperature: %v", err)
+	}
+	if _, err := bs.ProponerCustodia(fabricante, contractAddress, cuentas["distribuidor"].Address().Hex(), time.Hour, models.DetallesEnvio{}); err != nil {
+		t.Fatalf("Failed to propose custody: %v", err)
+	}
+	if _, err := bs.AceptarCustodia(cuentas["distribuidor"], contractAddress); err != nil {
+		t.Fatalf("Failed to accept custody: %v", err)
+	}
+	return bs, cuentas, contractAddress
+}
+
+func TestDespachador_PublishesConfirmedEventsOnce(t *testing.T) {
+	bs, cuentas, contractAddress := newTestLote(t)
+	publicador := &publicadorPrueba{}
+	despachador, err := New(bs, publicador, Options{Red: "local", Confirmaciones: 1, Historial: true})
+	if err != nil {
+		t.Fatalf("Failed to create dispatcher: %v", err)
+	}
+
+	publicados, err := despachador.Publicar(context.Background())
+	if err != nil || publicados != 2 {
+		t.Fatalf("Expected 2 events published, got %d %v", publicados, err)
+	}
+	comprometido, custodia := publicador.eventos[0], publicador.eventos[1]
+	if comprometido.EventType != models.EventoLoteComprometido || comprometido.LoteID != "LOTE001" || comprometido.ContractAddress != contractAddress ||
+		comprometido.Compromise == nil || comprometido.Compromise.TempMax != 11 || comprometido.Network != "local" || comprometido.ChainID != 1337 {
+		t.Errorf("Unexpected compromised event %+v", comprometido)
+	}
+	if comprometido.EventID != fmt.Sprintf("1337:%s:%d", comprometido.TxHash, comprometido.LogIndex) {
+		t.Errorf("Unexpected event ID %s", comprometido.EventID)
+	}
+	if custodia.EventType != models.EventoLoteCustodiaTransferida || custodia.Custody == nil || !custodia.Custody.Compromised ||
+		custodia.Custody.NewOwner != cuentas["distribuidor"].Address().Hex() || custodia.Custody.PreviousOwner != cuentas["fabricante"].Address().Hex() {
+		t.Errorf("Unexpected custody event %+v", custodia)
+	}
+
+	if publicados, _ := despachador.Publicar(context.Background()); publicados != 0 {
+		t.Errorf("Expected no event to be published twice, got %d", publicados)
+	}
+}
+
+func TestDespachador_WaitsForConfirmations(t *testing.T) {
+	bs, cuentas, contractAddress := newTestLote(t)
+	publicador := &publicadorPrueba{}
+	despachador, _ := New(bs, publicador, Options{Red: "local", Confirmaciones: 2, Historial: true})
+
+	// La transferencia está en el último bloque y aún no tiene 2 confirmaciones
+	if publicados, err := despachador.Publicar(context.Background()); err != nil || publicados != 1 {
+		t.Fatalf("Expected only the confirmed event, got %d %v", publicados, err)
+	}
+
+	if _, err := bs.RegistrarTemperatura(cuentas["fabricante"], contractAddress, 4, 6, "SENSOR-01"); err != nil {
+		t.Fatalf("Failed to register temperature: %v", err)
+	}
+	if publicados, _ := despachador.Publicar(context.Background()); publicados != 1 || publicador.eventos[1].EventType != models.EventoLoteCustodiaTransferida {
+		t.Errorf("Expected the custody event once confirmed, got %d %+v", publicados, publicador.eventos)
+	}
+}
+
+func TestDespachador_ResumesAfterBrokerFailureAndRestart(t *testing.T) {
+	bs, cuentas, contractAddress := newTestLote(t)
+	store := NewFileStore(filepath.Join(t.TempDir(), "published_events.json"))
+
+	// El broker cae tras el primer evento: el segundo se reintenta después
+	publicador := &publicadorPrueba{fallarEn: 2}
+	despachador, _ := New(bs, publicador, Options{Red: "local", Historial: true, Store: store})
+	if publicados, err := despachador.Publicar(context.Background()); err == nil || publicados != 1 {
+		t.Fatalf("Expected the broker failure after one event, got %d %v", publicados, err)
+	}
+	publicador.fallarEn = 0
+	if publicados, err := despachador.Publicar(context.Background()); err != nil || publicados != 1 ||
+		publicador.eventos[1].EventType != models.EventoLoteCustodiaTransferida {
+		t.Fatalf("Expected the pending event to be retried, got %d %v", publicados, err)
+	}
+
+	// Tras reiniciar solo se publican los eventos nuevos
+	reiniciado := &publicadorPrueba{}
+	despachador, err := New(bs, reiniciado, Options{Red: "local", Historial: true, Store: store})
+	if err != nil {
+		t.Fatalf("Failed to reload dispatcher: %v", err)
+	}
+	if publicados, _ := despachador.Publicar(context.Background()); publicados != 0 {
+		t.Errorf("Expected nothing to be republished after restart, got %d", publicados)
+	}
+	if _, err := bs.RegistrarTemperatura(cuentas["fabricante"], contractAddress, 3, 12, "SENSOR-01"); err != nil {
+		t.Fatalf("Failed to register temperature: %v", err)
+	}
+	if publicados, _ := despachador.Publicar(context.Background()); publicados != 1 || reiniciado.eventos[0].Compromise.TempMax != 12 {
+		t.Errorf("Expected the new compromised event, got %d %+v", publicados, reiniciado.eventos)
+	}
+}
+
+func TestDespachador_StartsAtHeadWithoutHistory(t *testing.T) {
+	bs, cuentas, contractAddress := newTestLote(t)
+	publicador := &publicadorPrueba{}
+	despachador, _ := New(bs, publicador, Options{Red: "local"})
+
+	if publicados, _ := despachador.Publicar(context.Background()); publicados != 0 {
+		t.Errorf("Expected past events to be skipped, got %d", publicados)
+	}
+	if _, err := bs.RegistrarTemperatura(cuentas["fabricante"], contractAddress, 1, 5, "SENSOR-01"); err != nil {
+		t.Fatalf("Failed to register temperature: %v", err)
+	}
+	if publicados, _ := despachador.Publicar(context.Background()); publicados != 1 || publicador.eventos[0].Compromise.TempMin != 1 {
+		t.Errorf("Expected the new compromised event, got %d %+v", publicados, publicador.eventos)
+	}
+}
@@ -0,0 +1,72 @@
+package eventos
+
+import (
+	"CrearLoteMicro/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+)
+
+// PublicadorKafka escribe los eventos en un topic de Kafka. La clave del
+// mensaje es el loteId, así que los eventos de un lote van a la misma
+// partición y se consumen en orden.
+type PublicadorKafka struct {
+	writer *kafka.Writer
+}
+
+// NewPublicadorKafka crea el publicador; con sasl autentica con SASL/PLAIN
+func NewPublicadorKafka(brokers []string, topic string, sasl bool, username, password string) *PublicadorKafka {
+	transport := &kafka.Transport{DialTimeout: 10 * time.Second}
+	if sasl {
+		transport.SASL = plain.Mechanism{Username: username, Password: password}
+	}
+
+	return &PublicadorKafka{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			Topic:                  topic,
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireAll,
+			AllowAutoTopicCreation: true,
+			WriteTimeout:           10 * time.Second,
+			Transport:              transport,
+		},
+	}
+}
+
+// Publicar escribe el evento y espera la confirmación de las réplicas
+func (p *PublicadorKafka) Publicar(ctx context.Context, evento models.EventoDominioLote) error {
+	value, err := json.Marshal(evento)
+	if err != nil {
+		return fmt.Errorf("error serializando evento: %v", err)
+	}
+
+	key := evento.LoteID
+	if key == "" {
+		key = evento.ContractAddress
+	}
+	err = p.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(key),
+		Value: value,
+		Headers: []kafka.Header{
+			{Key: "event_type", Value: []byte(evento.EventType)},
+			{Key: "event_id", Value: []byte(evento.EventID)},
+			{Key: "lote_id", Value: []byte(evento.LoteID)},
+			{Key: "contract_address", Value: []byte(evento.ContractAddress)},
+			{Key: "timestamp", Value: []byte(evento.Timestamp.Format(time.RFC3339))},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error escribiendo en Kafka %s: %v", p.writer.Topic, err)
+	}
+	return nil
+}
+
+// Close cierra el writer
+func (p *PublicadorKafka) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,134 @@
+package eventos
+
+import (
+	"CrearLoteMicro/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ErrEventoNoEnrutado se devuelve cuando ninguna cola recibe el evento
+var ErrEventoNoEnrutado = errors.New("ninguna cola enlazada recibe el evento")
+
+// PublicadorRabbitMQ publica los eventos en el exchange directo de la
+// plataforma con el tipo de evento como routing key. Usa confirmaciones del
+// broker y mensajes obligatorios, así que un evento que el broker no aceptó o
+// que no llegó a ninguna cola se reintenta.
+type PublicadorRabbitMQ struct {
+	url      string
+	exchange string
+	// cola se declara y se enlaza a los tipos de evento para que los eventos
+	// no se pierdan mientras no haya consumidores; vacía no declara ninguna
+	cola string
+
+	mu        sync.Mutex
+	conn      *amqp.Connection
+	channel   *amqp.Channel
+	devueltos chan amqp.Return
+}
+
+// NewPublicadorRabbitMQ crea el publicador. La conexión se abre con el
+// primer evento y se vuelve a abrir tras un error.
+func NewPublicadorRabbitMQ(url, exchange, cola string) *PublicadorRabbitMQ {
+	return &PublicadorRabbitMQ{url: url, exchange: exchange, cola: cola}
+}
+
+// conectarLocked abre la conexión y el canal en modo confirmación y declara
+// el exchange y la cola
+func (p *PublicadorRabbitMQ) conectarLocked() error {
+	conn, err := amqp.Dial(p.url)
+	if err != nil {
+		return fmt.Errorf("error conectando a RabbitMQ: %v", err)
+	}
+	channel, err := conn.Channel()
+	if err == nil {
+		err = channel.Confirm(false)
+	}
+	if err == nil {
+		// Mismos parámetros que el exchange del servicio de pedidos
+		err = channel.ExchangeDeclare(p.exchange, "direct", true, false, false, false, nil)
+	}
+	if err == nil && p.cola != "" {
+		_, err = channel.QueueDeclare(p.cola, true, false, false, false, nil)
+		for _, tipo := range []string{models.EventoLoteComprometido, models.EventoLoteCustodiaTransferida} {
+			if err == nil {
+				err = channel.QueueBind(p.cola, tipo, p.exchange, false, nil)
+			}
+		}
+	}
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error preparando el exchange %s: %v", p.exchange, err)
+	}
+
+	p.conn = conn
+	p.channel = channel
+	p.devueltos = channel.NotifyReturn(make(chan amqp.Return, 16))
+	return nil
+}
+
+// Publicar envía el evento y espera la confirmación del broker
+func (p *PublicadorRabbitMQ) Publicar(ctx context.Context, evento models.EventoDominioLote) error {
+	body, err := json.Marshal(evento)
+	if err != nil {
+		return fmt.Errorf("error serializando evento: %v", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.channel == nil || p.channel.IsClosed() {
+		p.cerrarLocked()
+		if err := p.conectarLocked(); err != nil {
+			return err
+		}
+	}
+
+	confirmacion, err := p.channel.PublishWithDeferredConfirmWithContext(ctx, p.exchange, evento.EventType, true, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    evento.EventID,
+		Type:         evento.EventType,
+		Timestamp:    evento.Timestamp,
+		Body:         body,
+	})
+	if err != nil {
+		p.cerrarLocked()
+		return fmt.Errorf("error publicando en RabbitMQ: %v", err)
+	}
+	confirmado, err := confirmacion.WaitContext(ctx)
+	if err != nil {
+		p.cerrarLocked()
+		return fmt.Errorf("error esperando confirmación de RabbitMQ: %v", err)
+	}
+	if !confirmado {
+		return fmt.Errorf("RabbitMQ rechazó el evento %s", evento.EventID)
+	}
+
+	// El broker devuelve los mensajes obligatorios sin cola antes de confirmarlos
+	select {
+	case devuelto := <-p.devueltos:
+		return fmt.Errorf("%w: %s (%s)", ErrEventoNoEnrutado, devuelto.RoutingKey, devuelto.ReplyText)
+	default:
+	}
+	return nil
+}
+
+func (p *PublicadorRabbitMQ) cerrarLocked() {
+	if p.conn != nil {
+		p.conn.Close()
+	}
+	p.conn = nil
+	p.channel = nil
+}
+
+// Close cierra la conexión
+func (p *PublicadorRabbitMQ) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cerrarLocked()
+	return nil
+}
@@ -0,0 +1,72 @@
+package eventos
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Estado es lo que el despachador ya publicó de una red
+type Estado struct {
+	// Inicio es el primer bloque cuyos eventos se publican
+	Inicio uint64 `json:"inicio"`
+	// Cursores es el último evento publicado de cada contrato, por dirección
+	// en minúsculas
+	Cursores map[string]Cursor `json:"cursores"`
+}
+
+// Cursor es la posición de un evento en la cadena
+type Cursor struct {
+	BlockNumber uint64 `json:"blockNumber"`
+	LogIndex    uint   `json:"logIndex"`
+}
+
+// anterior indica si el cursor está antes del evento
+func (c Cursor) anterior(evento models.EventoDominioLote) bool {
+	return c.BlockNumber < evento.BlockNumber || (c.BlockNumber == evento.BlockNumber && c.LogIndex < evento.LogIndex)
+}
+
+// Store guarda el estado del despachador entre reinicios
+type Store interface {
+	// Load devuelve nil si aún no se guardó nada
+	Load() (*Estado, error)
+	Save(estado *Estado) error
+}
+
+// FileStore guarda el estado del despachador en un archivo JSON
+type FileStore struct {
+	path string
+}
+
+// NewFileStore crea un almacén en el archivo indicado
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Load lee el estado guardado; un archivo inexistente equivale a ningún estado
+func (s *FileStore) Load() (*Estado, error) {
+	content, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo %s: %v", s.path, err)
+	}
+
+	var estado Estado
+	if err := json.Unmarshal(content, &estado); err != nil {
+		return nil, fmt.Errorf("error parseando %s: %v", s.path, err)
+	}
+	if estado.Cursores == nil {
+		estado.Cursores = make(map[string]Cursor)
+	}
+	return &estado, nil
+}
+
+// Save reemplaza el archivo de forma atómica
+func (s *FileStore) Save(estado *Estado) error {
+	return utils.GuardarJSONAtomico(s.path, estado)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/signer"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// claveCliente es la clave del contexto de Gin con el cliente autenticado
+const claveCliente = "clienteAPI"
+
+// Autenticar identifica al cliente por su API key, enviada como
+// "Authorization: Bearer <clave>" o en X-API-Key. Una clave desconocida
+// responde 401; sin clave la solicitud sigue sin cliente y solo lo exigen las
+// rutas que firman (resolverFirmante) o que cambian el servicio (exigirCliente).
+func Autenticar(clientes *signer.Callers) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clave := c.GetHeader("X-API-Key")
+		if autorizacion := c.GetHeader("Authorization"); clave == "" && autorizacion != "" {
+			var ok bool
+			if clave, ok = strings.CutPrefix(autorizacion, "Bearer "); !ok {
+				clave = ""
+			}
+		}
+		if clave == "" {
+			c.Next()
+			return
+		}
+
+		cliente, ok := clientes.Authenticate(strings.TrimSpace(clave))
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.Response{
+				Success: false,
+				Message: signer.ErrUnauthenticated.Error(),
+			})
+			return
+		}
+		c.Set(claveCliente, cliente)
+		c.Next()
+	}
+}
+
+// clienteAutenticado devuelve el cliente que dejó Autenticar, o nil
+func clienteAutenticado(c *gin.Context) *signer.Caller {
+	if valor, ok := c.Get(claveCliente); ok {
+		if cliente, ok := valor.(*signer.Caller); ok {
+			return cliente
+		}
+	}
+	return nil
+}
+
+// exigirCliente responde 401 y devuelve false si la solicitud no trae la API
+// key de un cliente
+func exigirCliente(c *gin.Context) bool {
+	if clienteAutenticado(c) != nil {
+		return true
+	}
+	c.JSON(http.StatusUnauthorized, models.Response{
+		Success: false,
+		Message: signer.ErrUnauthenticated.Error(),
+	})
+	return false
+}
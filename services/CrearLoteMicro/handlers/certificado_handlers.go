@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"CrearLoteMicro/certificado"
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/services"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+// EmitirCertificado devuelve el certificado de cadena de frío de un lote,
+// firmado por la cuenta de certificados, en JSON o con ?formato=pdf en PDF
+func (h *LoteHandler) EmitirCertificado(c *gin.Context) {
+	contractAddress := c.Param("contractAddress")
+	if !common.IsHexAddress(contractAddress) {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Formato de dirección de contrato inválido",
+		})
+		return
+	}
+	formato := c.DefaultQuery("formato", "json")
+	if formato != "json" && formato != "pdf" {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Formato inválido: " + formato + " (válidos: json, pdf)",
+		})
+		return
+	}
+	if h.certificador == nil {
+		c.JSON(http.StatusNotFound, models.Response{
+			Success: false,
+			Message: "Emisión de certificados deshabilitada (CERTIFICATE_ACCOUNT vacía)",
+		})
+		return
+	}
+
+	red, ok := h.resolverRed(c, "")
+	if !ok {
+		return
+	}
+
+	documento, err := red.Service.EmitirCertificado(h.certificador, red.Name, contractAddress)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrContractNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, models.Response{
+			Success: false,
+			Message: "Error emitiendo certificado: " + err.Error(),
+		})
+		return
+	}
+
+	if formato == "pdf" {
+		pdf, err := certificado.PDF(*documento)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.Response{
+				Success: false,
+				Message: "Error generando PDF: " + err.Error(),
+			})
+			return
+		}
+		c.Header("Content-Disposition", `attachment; filename="certificado-`+documento.Certificado.ContractAddress+`.pdf"`)
+		c.Data(http.StatusOK, "application/pdf", pdf)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Certificado emitido exitosamente",
+		Data:    documento,
+		Network: red.Name,
+	})
+}
+
+// VerificarCertificado comprueba un certificado contra la cadena. Acepta el
+// documento JSON firmado o, con Content-Type application/pdf, el PDF que lo
+// lleva adjunto. La red es ?network= o la del certificado.
+func (h *LoteHandler) VerificarCertificado(c *gin.Context) {
+	var documento models.CertificadoFirmado
+	if strings.HasPrefix(c.ContentType(), "application/pdf") {
+		pdf, err := io.ReadAll(c.Request.Body)
+		if err == nil {
+			var extraido *models.CertificadoFirmado
+			if extraido, err = certificado.ExtraerDocumento(pdf); err == nil {
+				documento = *extraido
+			}
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.Response{
+				Success: false,
+				Message: "PDF inválido: " + err.Error(),
+			})
+			return
+		}
+	} else if err := c.ShouldBindJSON(&documento); err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Datos de entrada inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	network := c.Query("network")
+	if network == "" {
+		network = documento.Certificado.Red
+	}
+	red, ok := h.resolverRed(c, network)
+	if !ok {
+		return
+	}
+
+	var emisor common.Address
+	if h.certificador != nil {
+		emisor = h.certificador.Address()
+	}
+	verificacion, err := red.Service.VerificarCertificado(documento, emisor)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrCertificadoInvalido) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, models.Response{
+			Success: false,
+			Message: "Error verificando certificado: " + err.Error(),
+		})
+		return
+	}
+
+	message := "Certificado válido"
+	if !verificacion.Valido {
+		message = "Certificado no válido"
+	} else if !verificacion.Vigente {
+		message = "Certificado válido; el lote tiene eventos posteriores al certificado"
+	}
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: message,
+		Data:    verificacion,
+		Network: red.Name,
+	})
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/services"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gin-gonic/gin"
+)
+
+// ProponerCustodia propone entregar un lote a un distribuidor o farmacia con
+// los detalles del envío; la custodia cambia cuando el destinatario acepta
+func (h *LoteHandler) ProponerCustodia(c *gin.Context) {
+	var req models.ProponerCustodiaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Datos de entrada inválidos: " + err.Error(),
+		})
+		return
+	}
+	for _, address := range []string{req.ContractAddress, req.Destinatario} {
+		if !common.IsHexAddress(address) {
+			c.JSON(http.StatusBadRequest, models.Response{
+				Success: false,
+				Message: "Dirección inválida: " + address,
+			})
+			return
+		}
+	}
+	plazo := services.PlazoCustodiaPorDefecto
+	if req.Plazo != "" {
+		var err error
+		if plazo, err = time.ParseDuration(req.Plazo); err != nil {
+			c.JSON(http.StatusBadRequest, models.Response{
+				Success: false,
+				Message: "Plazo inválido: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	firmante, ok := h.resolverFirmante(c, req.Account, req.PrivateKey, req.WalletAddress)
+	if !ok {
+		return
+	}
+	red, ok := h.resolverRed(c, req.Network)
+	if !ok {
+		return
+	}
+
+	transaccion, err := red.Service.ProponerCustodia(firmante, req.ContractAddress, req.Destinatario, plazo, req.Envio)
+	if err != nil {
+		c.JSON(estadoErrorTransaccion(err), models.Response{
+			Success: false,
+			Message: "Error proponiendo custodia: " + err.Error(),
+		})
+		return
+	}
+
+	h.responderTransaccion(c, red, models.Response{
+		Success: true,
+		Message: "Custodia propuesta exitosamente",
+		Data: map[string]interface{}{
+			"contractAddress": common.HexToAddress(req.ContractAddress).Hex(),
+			"destinatario":    common.HexToAddress(req.Destinatario).Hex(),
+			"plazo":           plazo.String(),
+		},
+		TxHash:      transaccion.TxHash,
+		Transaccion: transaccion,
+	})
+}
+
+// AceptarCustodia acepta la propuesta pendiente de un lote, con la cuenta del
+// destinatario o enviando su firma EIP-712
+func (h *LoteHandler) AceptarCustodia(c *gin.Context) {
+	var req models.AceptarCustodiaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Datos de entrada inválidos: " + err.Error(),
+		})
+		return
+	}
+	if !common.IsHexAddress(req.ContractAddress) {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Formato de dirección de contrato inválido",
+		})
+		return
+	}
+	var firma []byte
+	if req.Firma != "" {
+		var err error
+		if firma, err = hexutil.Decode(req.Firma); err != nil {
+			c.JSON(http.StatusBadRequest, models.Response{
+				Success: false,
+				Message: "Firma inválida: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	firmante, ok := h.resolverFirmante(c, req.Account, req.PrivateKey, req.WalletAddress)
+	if !ok {
+		return
+	}
+	red, ok := h.resolverRed(c, req.Network)
+	if !ok {
+		return
+	}
+
+	aceptar := func() (*models.TransaccionEnviada, error) {
+		return red.Service.AceptarCustodia(firmante, req.ContractAddress)
+	}
+	if firma != nil {
+		aceptar = func() (*models.TransaccionEnviada, error) {
+			return red.Service.AceptarCustodiaFirmada(firmante, req.ContractAddress, firma)
+		}
+	}
+	transaccion, err := aceptar()
+	if err != nil {
+		c.JSON(estadoErrorTransaccion(err), models.Response{
+			Success: false,
+			Message: "Error aceptando custodia: " + err.Error(),
+		})
+		return
+	}
+
+	h.responderTransaccion(c, red, models.Response{
+		Success: true,
+		Message: "Custodia aceptada exitosamente",
+		Data: map[string]interface{}{
+			"contractAddress": common.HexToAddress(req.ContractAddress).Hex(),
+			"firmada":         firma != nil,
+		},
+		TxHash:      transaccion.TxHash,
+		Transaccion: transaccion,
+	})
+}
+
+// RechazarCustodia rechaza la propuesta pendiente de un lote con la cuenta
+// del destinatario
+func (h *LoteHandler) RechazarCustodia(c *gin.Context) {
+	h.cerrarPropuesta(c, true)
+}
+
+// CancelarCustodia retira la propuesta pendiente de un lote con la cuenta del
+// propietario actual
+func (h *LoteHandler) CancelarCustodia(c *gin.Context) {
+	h.cerrarPropuesta(c, false)
+}
+
+func (h *LoteHandler) cerrarPropuesta(c *gin.Context, rechazar bool) {
+	var req models.RechazarCustodiaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Datos de entrada inválidos: " + err.Error(),
+		})
+		return
+	}
+	if !common.IsHexAddress(req.ContractAddress) {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Formato de dirección de contrato inválido",
+		})
+		return
+	}
+
+	firmante, ok := h.resolverFirmante(c, req.Account, req.PrivateKey, req.WalletAddress)
+	if !ok {
+		return
+	}
+	red, ok := h.resolverRed(c, req.Network)
+	if !ok {
+		return
+	}
+
+	accion, message := "cancelando", "Propuesta de custodia cancelada exitosamente"
+	cerrar := func() (*models.TransaccionEnviada, error) {
+		return red.Service.CancelarCustodia(firmante, req.ContractAddress)
+	}
+	if rechazar {
+		accion, message = "rechazando", "Propuesta de custodia rechazada exitosamente"
+		cerrar = func() (*models.TransaccionEnviada, error) {
+			return red.Service.RechazarCustodia(firmante, req.ContractAddress, req.Motivo)
+		}
+	}
+	transaccion, err := cerrar()
+	if err != nil {
+		c.JSON(estadoErrorTransaccion(err), models.Response{
+			Success: false,
+			Message: "Error " + accion + " custodia: " + err.Error(),
+		})
+		return
+	}
+
+	h.responderTransaccion(c, red, models.Response{
+		Success: true,
+		Message: message,
+		Data: map[string]interface{}{
+			"contractAddress": common.HexToAddress(req.ContractAddress).Hex(),
+		},
+		TxHash:      transaccion.TxHash,
+		Transaccion: transaccion,
+	})
+}
+
+// ObtenerPropuestaCustodia devuelve la propuesta de custodia de un lote con
+// los datos EIP-712 que firma el destinatario para aceptarla
+func (h *LoteHandler) ObtenerPropuestaCustodia(c *gin.Context) {
+	contractAddress := c.Param("contractAddress")
+	if !common.IsHexAddress(contractAddress) {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Formato de dirección de contrato inválido",
+		})
+		return
+	}
+
+	red, ok := h.resolverRed(c, "")
+	if !ok {
+		return
+	}
+
+	propuesta, err := red.Service.ObtenerPropuestaCustodia(contractAddress)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrContractNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, models.Response{
+			Success: false,
+			Message: "Error obteniendo propuesta de custodia: " + err.Error(),
+		})
+		return
+	}
+
+	message := "Propuesta de custodia obtenida exitosamente"
+	if !propuesta.Pendiente {
+		message = "El lote no tiene una propuesta de custodia pendiente"
+	}
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: message,
+		Data:    propuesta,
+		Network: red.Name,
+	})
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/services"
+	"errors"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+// estadoErrorFactory elige el código HTTP de un error de la factory
+func estadoErrorFactory(err error) int {
+	switch {
+	case errors.Is(err, services.ErrFactoryNotConfigured), errors.Is(err, services.ErrLoteNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrLoteExists):
+		return http.StatusConflict
+	default:
+		return estadoErrorTransaccion(err)
+	}
+}
+
+// DesplegarFactory despliega la LoteTracingFactory de la red con la cuenta
+// firmante como administrador
+func (h *LoteHandler) DesplegarFactory(c *gin.Context) {
+	var req models.DesplegarFactoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Datos de entrada inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	firmante, ok := h.resolverFirmante(c, req.Account, req.PrivateKey, "")
+	if !ok {
+		return
+	}
+	red, ok := h.resolverRed(c, req.Network)
+	if !ok {
+		return
+	}
+
+	factoryAddress, transaccion, err := red.Service.DesplegarFactory(firmante)
+	if err != nil {
+		c.JSON(estadoErrorTransaccion(err), models.Response{
+			Success: false,
+			Message: "Error desplegando factory: " + err.Error(),
+		})
+		return
+	}
+
+	h.responderTransaccion(c, red, models.Response{
+		Success: true,
+		Message: "Factory desplegada exitosamente",
+		Data: map[string]interface{}{
+			"factoryAddress": factoryAddress,
+			"admin":          firmante.Address().Hex(),
+		},
+		TxHash:      transaccion.TxHash,
+		Transaccion: transaccion,
+	})
+}
+
+// ObtenerFactory devuelve la dirección, el administrador y el número de
+// lotes de la factory de la red
+func (h *LoteHandler) ObtenerFactory(c *gin.Context) {
+	red, ok := h.resolverRed(c, "")
+	if !ok {
+		return
+	}
+
+	info, err := red.Service.InfoFactory()
+	if err != nil {
+		c.JSON(estadoErrorFactory(err), models.Response{
+			Success: false,
+			Message: "Error obteniendo factory: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Factory obtenida exitosamente",
+		Data:    info,
+		Network: red.Name,
+	})
+}
+
+// AutorizarFabricante concede o retira a una cuenta el permiso de crear
+// lotes en la factory
+func (h *LoteHandler) AutorizarFabricante(c *gin.Context) {
+	var req models.AutorizarFabricanteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Datos de entrada inválidos: " + err.Error(),
+		})
+		return
+	}
+	if !common.IsHexAddress(req.Fabricante) {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Dirección inválida: " + req.Fabricante,
+		})
+		return
+	}
+
+	firmante, ok := h.resolverFirmante(c, req.Account, req.PrivateKey, "")
+	if !ok {
+		return
+	}
+	red, ok := h.resolverRed(c, req.Network)
+	if !ok {
+		return
+	}
+
+	transaccion, err := red.Service.AutorizarFabricante(firmante, req.Fabricante, *req.Autorizado)
+	if err != nil {
+		c.JSON(estadoErrorFactory(err), models.Response{
+			Success: false,
+			Message: "Error autorizando fabricante: " + err.Error(),
+		})
+		return
+	}
+
+	message := "Fabricante autorizado exitosamente"
+	if !*req.Autorizado {
+		message = "Autorización de fabricante retirada exitosamente"
+	}
+	h.responderTransaccion(c, red, models.Response{
+		Success: true,
+		Message: message,
+		Data: map[string]interface{}{
+			"fabricante": common.HexToAddress(req.Fabricante).Hex(),
+			"autorizado": *req.Autorizado,
+		},
+		TxHash:      transaccion.TxHash,
+		Transaccion: transaccion,
+	})
+}
+
+// CrearLoteFactory crea un lote en la factory sin desplegar un contrato
+func (h *LoteHandler) CrearLoteFactory(c *gin.Context) {
+	var req models.CrearLoteFactoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Datos de entrada inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	firmante, ok := h.resolverFirmante(c, req.Account, req.PrivateKey, "")
+	if !ok {
+		return
+	}
+	red, ok := h.resolverRed(c, req.Network)
+	if !ok {
+		return
+	}
+
+	transaccion, err := red.Service.CrearLoteFactory(firmante, req.LoteID, req.TemperaturaMin, req.TemperaturaMax)
+	if err != nil {
+		c.JSON(estadoErrorFactory(err), models.Response{
+			Success: false,
+			Message: "Error creando lote en la factory: " + err.Error(),
+		})
+		return
+	}
+
+	factoryAddress, _ := red.Service.Factory()
+	h.responderTransaccion(c, red, models.Response{
+		Success: true,
+		Message: "Lote creado exitosamente en la factory",
+		Data: map[string]interface{}{
+			"factoryAddress": factoryAddress.Hex(),
+			"loteId":         req.LoteID,
+			"from":           firmante.Address().Hex(),
+		},
+		TxHash:      transaccion.TxHash,
+		Transaccion: transaccion,
+	})
+}
+
+// EscribirLoteFactory responde que los lotes de la factory son de solo
+// lectura: el contrato revierte registrarTemperatura y transferirCustodia,
+// que no aplican el rol de oráculo ni el traspaso de custodia en dos pasos
+func (h *LoteHandler) EscribirLoteFactory(c *gin.Context) {
+	c.JSON(http.StatusGone, models.Response{
+		Success: false,
+		Message: "Los lotes de la factory son de solo lectura: registre temperaturas y ceda la custodia en un LoteTracing (POST /api/v1/lote/crear)",
+	})
+}
+
+// ObtenerLoteFactory devuelve el estado de un lote de la factory
+func (h *LoteHandler) ObtenerLoteFactory(c *gin.Context) {
+	red, ok := h.resolverRed(c, "")
+	if !ok {
+		return
+	}
+
+	lote, err := red.Service.ObtenerLoteFactory(c.Param("loteId"))
+	if err != nil {
+		c.JSON(estadoErrorFactory(err), models.Response{
+			Success: false,
+			Message: "Error obteniendo lote: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Lote obtenido exitosamente",
+		Data:    lote,
+		Network: red.Name,
+	})
+}
+
+// ObtenerCadenaFactory devuelve el historial de eventos de un lote de la factory
+func (h *LoteHandler) ObtenerCadenaFactory(c *gin.Context) {
+	red, ok := h.resolverRed(c, "")
+	if !ok {
+		return
+	}
+
+	cadena, err := red.Service.ObtenerCadenaFactory(c.Param("loteId"))
+	if err != nil {
+		c.JSON(estadoErrorFactory(err), models.Response{
+			Success: false,
+			Message: "Error obteniendo cadena del lote: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Cadena del lote obtenida exitosamente",
+		Data:    cadena,
+		Network: red.Name,
+	})
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"CrearLoteMicro/models"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// limitePorIP es un token bucket por IP de cliente
+type limitePorIP struct {
+	tasa   rate.Limit
+	rafaga int
+
+	mu         sync.Mutex
+	clientes   map[string]*clienteLimitado
+	limpiadoEn time.Time
+}
+
+type clienteLimitado struct {
+	limiter *rate.Limiter
+	visto   time.Time
+}
+
+// inactividadCliente es el tiempo tras el que se olvida el bucket de un cliente
+const inactividadCliente = 5 * time.Minute
+
+// LimitePorIP limita cada IP de cliente a porMinuto solicitudes por minuto con
+// ráfagas de hasta rafaga solicitudes. Al superarlo responde 429 con
+// Retry-After. Con porMinuto <= 0 no limita.
+func LimitePorIP(porMinuto, rafaga int) gin.HandlerFunc {
+	if porMinuto <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	if rafaga <= 0 {
+		rafaga = 1
+	}
+	limite := &limitePorIP{
+		tasa:     rate.Limit(float64(porMinuto) / 60),
+		rafaga:   rafaga,
+		clientes: make(map[string]*clienteLimitado),
+	}
+
+	return func(c *gin.Context) {
+		ahora := time.Now()
+		reserva := limite.reservar(c.ClientIP(), ahora)
+		if espera := reserva.DelayFrom(ahora); espera > 0 {
+			reserva.CancelAt(ahora)
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(espera.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, models.Response{
+				Success: false,
+				Message: "Demasiadas solicitudes; reintente en " + espera.Round(time.Second).String(),
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+func (l *limitePorIP) reservar(ip string, ahora time.Time) *rate.Reservation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Olvidar los clientes inactivos como mucho una vez por minuto
+	if ahora.Sub(l.limpiadoEn) > time.Minute {
+		for clave, cliente := range l.clientes {
+			if ahora.Sub(cliente.visto) > inactividadCliente {
+				delete(l.clientes, clave)
+			}
+		}
+		l.limpiadoEn = ahora
+	}
+
+	cliente, ok := l.clientes[ip]
+	if !ok {
+		cliente = &clienteLimitado{limiter: rate.NewLimiter(l.tasa, l.rafaga)}
+		l.clientes[ip] = cliente
+	}
+	cliente.visto = ahora
+	return cliente.limiter.ReserveN(ahora, 1)
+}
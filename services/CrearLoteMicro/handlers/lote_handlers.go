@@ -2,27 +2,125 @@ package handlers
 
 import (
 	"CrearLoteMicro/models"
+	"CrearLoteMicro/oracle"
 	"CrearLoteMicro/services"
+	"CrearLoteMicro/signer"
 	"CrearLoteMicro/utils"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 )
 
 type LoteHandler struct {
-	blockchainService          *services.BlockchainService
-	blockchainWebsocketService *services.BlockchainWebsocketService
+	networks *services.NetworkRegistry
+	signers  *signer.Registry
+	// oraculo es el oráculo de sensores; nil si ORACLE_ENABLED=false
+	oraculo *oracle.Oraculo
+	// cuentaOraculo es la cuenta con la que firma el oráculo; solo los
+	// clientes que pueden usarla operan el oráculo
+	cuentaOraculo string
+	// certificador firma los certificados de los lotes; nil si
+	// CERTIFICATE_ACCOUNT está vacía
+	certificador signer.Signer
+	// urlPublica es la URL base de los códigos QR; vacía usa la de la solicitud
+	urlPublica string
 }
 
-func NewLoteHandler(blockchainService *services.BlockchainService, blockchainWebsocketService *services.BlockchainWebsocketService) *LoteHandler {
+func NewLoteHandler(networks *services.NetworkRegistry, signers *signer.Registry) *LoteHandler {
 	return &LoteHandler{
-		blockchainService:          blockchainService,
-		blockchainWebsocketService: blockchainWebsocketService,
+		networks: networks,
+		signers:  signers,
 	}
 }
 
+// UsarOraculo habilita los endpoints /oracle con el oráculo de sensores, que
+// firma con la cuenta con nombre
+func (h *LoteHandler) UsarOraculo(oraculo *oracle.Oraculo, cuenta string) {
+	h.oraculo = oraculo
+	h.cuentaOraculo = cuenta
+}
+
+// UsarCertificador habilita la emisión de certificados firmados por la cuenta
+func (h *LoteHandler) UsarCertificador(firmante signer.Signer) {
+	h.certificador = firmante
+}
+
+// UsarURLPublica fija la URL base a la que apuntan los códigos QR
+func (h *LoteHandler) UsarURLPublica(url string) {
+	h.urlPublica = url
+}
+
+// resolverRed obtiene la red de la solicitud: el campo network del cuerpo o el
+// parámetro ?network=, y sin ninguno la red por defecto. Responde 404 y
+// devuelve false si la red no está configurada.
+func (h *LoteHandler) resolverRed(c *gin.Context, network string) (*services.Network, bool) {
+	if network == "" {
+		network = c.Query("network")
+	}
+
+	red, err := h.networks.Get(network)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.Response{
+			Success: false,
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+	return red, true
+}
+
+// resolverFirmante obtiene el firmante de la solicitud a partir de la cuenta
+// con nombre o, si está permitido, de la clave privada. Firmar exige un cliente
+// autenticado con la cuenta permitida. Si la solicitud indica walletAddress
+// debe coincidir con la cuenta. Responde el error y devuelve false cuando no se
+// puede firmar.
+func (h *LoteHandler) resolverFirmante(c *gin.Context, account, privateKey, walletAddress string) (signer.Signer, bool) {
+	cliente := clienteAutenticado(c)
+	if cliente == nil {
+		c.JSON(http.StatusUnauthorized, models.Response{
+			Success: false,
+			Message: signer.ErrUnauthenticated.Error(),
+		})
+		return nil, false
+	}
+	if account != "" && !cliente.CanUse(account) {
+		c.JSON(http.StatusForbidden, models.Response{
+			Success: false,
+			Message: fmt.Sprintf("%v: %s no puede usar %s", signer.ErrAccountForbidden, cliente.Name, account),
+		})
+		return nil, false
+	}
+
+	firmante, err := h.signers.Resolve(account, privateKey)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, signer.ErrAccountNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, models.Response{
+			Success: false,
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+
+	if walletAddress != "" && !strings.EqualFold(walletAddress, firmante.Address().Hex()) {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: fmt.Sprintf("walletAddress %s no corresponde a la cuenta firmante %s", walletAddress, firmante.Address().Hex()),
+		})
+		return nil, false
+	}
+
+	return firmante, true
+}
+
 // CrearLote maneja la creación de un nuevo lote (deploy del contrato)
 func (h *LoteHandler) CrearLote(c *gin.Context) {
 	var req models.CrearLoteRequest
@@ -34,43 +132,48 @@ func (h *LoteHandler) CrearLote(c *gin.Context) {
 		return
 	}
 
-	// Validar que la clave privada no esté vacía
-	if req.PrivateKey == "" {
-		c.JSON(http.StatusBadRequest, models.Response{
-			Success: false,
-			Message: "La clave privada es requerida",
-		})
+	// Resolver la cuenta que firma
+	firmante, ok := h.resolverFirmante(c, req.Account, req.PrivateKey, req.WalletAddress)
+	if !ok {
+		return
+	}
+	red, ok := h.resolverRed(c, req.Network)
+	if !ok {
 		return
 	}
 
 	// Desplegar el contrato
-	contractAddress, txHash, err := h.blockchainService.DeployContract(
-		req.PrivateKey,
+	contractAddress, transaccion, err := red.Service.DeployContract(
+		firmante,
 		req.LoteID,
 		req.TemperaturaMin,
 		req.TemperaturaMax,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.Response{
+		c.JSON(estadoErrorTransaccion(err), models.Response{
 			Success: false,
 			Message: "Error desplegando contrato: " + err.Error(),
 		})
 		return
 	}
 	fmt.Println("deploying contract at:", contractAddress)
-	go h.blockchainWebsocketService.StartBlockchainWebsocket(contractAddress)
+	if red.Websocket != nil {
+		red.Websocket.Vigilar(contractAddress)
+	}
 
 	response := models.ContractDeployResponse{
 		ContractAddress: contractAddress,
-		TxHash:          txHash,
+		TxHash:          transaccion.TxHash,
 		LoteID:          req.LoteID,
+		From:            firmante.Address().Hex(),
 	}
 
-	c.JSON(http.StatusOK, models.Response{
-		Success: true,
-		Message: "Lote creado exitosamente con socket",
-		Data:    response,
-		TxHash:  txHash,
+	h.responderTransaccion(c, red, models.Response{
+		Success:     true,
+		Message:     "Lote creado exitosamente con socket",
+		Data:        response,
+		TxHash:      transaccion.TxHash,
+		Transaccion: transaccion,
 	})
 }
 
@@ -85,40 +188,53 @@ func (h *LoteHandler) RegistrarTemperatura(c *gin.Context) {
 		return
 	}
 
-	// Validar que la clave privada no esté vacía
-	if req.PrivateKey == "" {
-		c.JSON(http.StatusBadRequest, models.Response{
-			Success: false,
-			Message: "La clave privada es requerida",
-		})
+	// Resolver la cuenta que firma
+	firmante, ok := h.resolverFirmante(c, req.Account, req.PrivateKey, req.WalletAddress)
+	if !ok {
+		return
+	}
+	red, ok := h.resolverRed(c, req.Network)
+	if !ok {
 		return
 	}
 
 	// Registrar temperatura
-	txHash, err := h.blockchainService.RegistrarTemperatura(
-		req.PrivateKey,
+	transaccion, err := red.Service.RegistrarTemperatura(
+		firmante,
 		req.ContractAddress,
 		req.TempMin,
 		req.TempMax,
+		req.SensorID,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.Response{
+		c.JSON(estadoErrorTransaccion(err), models.Response{
 			Success: false,
 			Message: "Error registrando temperatura: " + err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.Response{
-		Success: true,
-		Message: "Temperatura registrada exitosamente",
-		TxHash:  txHash,
+	h.responderTransaccion(c, red, models.Response{
+		Success:     true,
+		Message:     "Temperatura registrada exitosamente",
+		TxHash:      transaccion.TxHash,
+		Transaccion: transaccion,
 	})
 }
 
-// TransferirCustodia maneja la transferencia de custodia de un lote
+// TransferirCustodia responde que la transferencia unilateral ya no existe: el
+// contrato revierte transferirCustodia y la custodia solo cambia cuando el
+// destinatario acepta una propuesta
 func (h *LoteHandler) TransferirCustodia(c *gin.Context) {
-	var req models.TransferirCustodiaRequest
+	c.JSON(http.StatusGone, models.Response{
+		Success: false,
+		Message: "La transferencia directa de custodia se retiró: use POST /api/v1/lote/custodia/proponer y que el destinatario la acepte en /api/v1/lote/custodia/aceptar",
+	})
+}
+
+// CrearNuevoLote maneja la creación de un nuevo lote en un contrato existente
+func (h *LoteHandler) CrearNuevoLote(c *gin.Context) {
+	var req models.CrearNuevoLoteRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, models.Response{
 			Success: false,
@@ -127,92 +243,272 @@ func (h *LoteHandler) TransferirCustodia(c *gin.Context) {
 		return
 	}
 
-	// Validar que la clave privada no esté vacía
-	if req.PrivateKey == "" {
+	// Resolver la cuenta que firma
+	firmante, ok := h.resolverFirmante(c, req.Account, req.PrivateKey, "")
+	if !ok {
+		return
+	}
+	red, ok := h.resolverRed(c, req.Network)
+	if !ok {
+		return
+	}
+
+	// Validar formato de dirección del contrato
+	if len(req.ContractAddress) != 42 || req.ContractAddress[:2] != "0x" {
 		c.JSON(http.StatusBadRequest, models.Response{
 			Success: false,
-			Message: "La clave privada es requerida",
+			Message: "Formato de dirección de contrato inválido",
 		})
 		return
 	}
 
-	// Transferir custodia
-	txHash, err := h.blockchainService.TransferirCustodia(
-		req.PrivateKey,
+	// Crear nuevo lote en el contrato existente
+	transaccion, err := red.Service.CrearNuevoLote(
+		firmante,
 		req.ContractAddress,
-		req.NuevoPropietario,
+		req.LoteID,
+		req.TemperaturaMin,
+		req.TemperaturaMax,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.Response{
+		c.JSON(estadoErrorTransaccion(err), models.Response{
 			Success: false,
-			Message: "Error transfiriendo custodia: " + err.Error(),
+			Message: "Error creando nuevo lote: " + err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.Response{
-		Success: true,
-		Message: "Custodia transferida exitosamente",
-		TxHash:  txHash,
+	response := map[string]interface{}{
+		"contractAddress": req.ContractAddress,
+		"loteId":          req.LoteID,
+		"txHash":          transaccion.TxHash,
+	}
+
+	h.responderTransaccion(c, red, models.Response{
+		Success:     true,
+		Message:     "Nuevo lote creado exitosamente en contrato existente",
+		Data:        response,
+		TxHash:      transaccion.TxHash,
+		Transaccion: transaccion,
 	})
 }
 
-// CrearNuevoLote maneja la creación de un nuevo lote en un contrato existente
-func (h *LoteHandler) CrearNuevoLote(c *gin.Context) {
-	var req models.CrearNuevoLoteRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.Response{
+// responderTransaccion responde una escritura ya enviada. Con ?wait=true
+// espera a que la transacción alcance las confirmaciones requeridas y responde
+// 422 si revirtió, 409 si otra transacción ocupó su nonce y 202 si no se
+// confirmó a tiempo.
+func (h *LoteHandler) responderTransaccion(c *gin.Context, red *services.Network, response models.Response) {
+	response.Network = red.Name
+	if esperar, _ := strconv.ParseBool(c.Query("wait")); !esperar {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	estado, err := red.Service.EsperarTransaccion(c.Request.Context(), response.TxHash)
+	if estado == nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
 			Success: false,
-			Message: "Datos de entrada inválidos: " + err.Error(),
+			Message: "Error esperando la transacción: " + err.Error(),
+			TxHash:  response.TxHash,
 		})
 		return
 	}
+	response.Estado = estado
+
+	switch {
+	case err != nil:
+		response.Message += "; la transacción aún no está confirmada, consulte GET /api/v1/tx/" + response.TxHash
+		c.JSON(http.StatusAccepted, response)
+	case estado.Estado == models.EstadoRevertida:
+		response.Success = false
+		response.Message = "La transacción revirtió"
+		if estado.MotivoReversion != "" {
+			response.Message += ": " + estado.MotivoReversion
+		}
+		c.JSON(http.StatusUnprocessableEntity, response)
+	case estado.Estado == models.EstadoReemplazada:
+		response.Success = false
+		response.Message = "La transacción fue reemplazada por otra con el mismo nonce"
+		c.JSON(http.StatusConflict, response)
+	default:
+		c.JSON(http.StatusOK, response)
+	}
+}
 
-	// Validar que la clave privada no esté vacía
-	if req.PrivateKey == "" {
+// ObtenerEstadoTransaccion devuelve el estado de confirmación de una transacción enviada por el servicio
+func (h *LoteHandler) ObtenerEstadoTransaccion(c *gin.Context) {
+	hash := c.Param("hash")
+	if len(hash) != 66 || !strings.HasPrefix(hash, "0x") {
 		c.JSON(http.StatusBadRequest, models.Response{
 			Success: false,
-			Message: "La clave privada es requerida",
+			Message: "Formato de hash de transacción inválido",
 		})
 		return
 	}
 
-	// Validar formato de dirección del contrato
-	if len(req.ContractAddress) != 42 || req.ContractAddress[:2] != "0x" {
+	// Sin ?network= se busca en todas las redes
+	redes := h.networks.All()
+	if network := c.Query("network"); network != "" {
+		red, ok := h.resolverRed(c, network)
+		if !ok {
+			return
+		}
+		redes = []*services.Network{red}
+	}
+
+	err := services.ErrTxNotTracked
+	for _, red := range redes {
+		var estado *models.EstadoTransaccion
+		estado, err = red.Service.EstadoTransaccion(hash)
+		if err != nil {
+			continue
+		}
+
+		c.JSON(http.StatusOK, models.Response{
+			Success: true,
+			Message: "Estado de la transacción obtenido exitosamente",
+			TxHash:  estado.TxHash,
+			Estado:  estado,
+			Network: red.Name,
+		})
+		return
+	}
+
+	c.JSON(http.StatusNotFound, models.Response{
+		Success: false,
+		Message: err.Error(),
+	})
+}
+
+// estadoErrorTransaccion elige el código HTTP de un error al enviar una transacción
+func estadoErrorTransaccion(err error) int {
+	switch {
+	case errors.Is(err, services.ErrTxNotPending), errors.Is(err, services.ErrTxAlreadyMined):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrTxQueueFull):
+		return http.StatusTooManyRequests
+	case errors.Is(err, services.ErrFeeAboveCeiling):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, services.ErrRolRequerido), errors.Is(err, services.ErrNoDestinatario), errors.Is(err, services.ErrFirmaInvalida):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrRolInvalido), errors.Is(err, services.ErrPlazoCustodiaInvalido):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrSinPropuestaCustodia), errors.Is(err, services.ErrPropuestaVencida),
+		errors.Is(err, services.ErrLoteNoReiniciable):
+		return http.StatusConflict
+	case strings.Contains(err.Error(), "execution reverted"):
+		// La estimación de gas detectó que la transacción revertiría
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ListarCuentas lista las cuentas firmantes configuradas, sin sus claves
+func (h *LoteHandler) ListarCuentas(c *gin.Context) {
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Cuentas firmantes obtenidas exitosamente",
+		Data: map[string]interface{}{
+			"cuentas":             h.signers.Accounts(),
+			"allowRawPrivateKeys": h.signers.AllowRawKeys(),
+		},
+	})
+}
+
+// ListarTransaccionesPendientes lista las transacciones sin confirmar de una
+// cuenta configurada o de una dirección
+func (h *LoteHandler) ListarTransaccionesPendientes(c *gin.Context) {
+	account := c.Param("account")
+
+	var address common.Address
+	if firmante, err := h.signers.Get(account); err == nil {
+		address = firmante.Address()
+	} else if common.IsHexAddress(account) {
+		address = common.HexToAddress(account)
+	} else {
+		c.JSON(http.StatusNotFound, models.Response{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	red, ok := h.resolverRed(c, "")
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Transacciones pendientes obtenidas exitosamente",
+		Data: map[string]interface{}{
+			"address":       address.Hex(),
+			"transacciones": red.Service.TransaccionesPendientes(address),
+		},
+		Network: red.Name,
+	})
+}
+
+// AcelerarTransaccion reenvía una transacción pendiente con mayor precio de gas
+func (h *LoteHandler) AcelerarTransaccion(c *gin.Context) {
+	h.reemplazarTransaccion(c, false)
+}
+
+// CancelarTransaccion reemplaza una transacción pendiente por una transferencia vacía
+func (h *LoteHandler) CancelarTransaccion(c *gin.Context) {
+	h.reemplazarTransaccion(c, true)
+}
+
+func (h *LoteHandler) reemplazarTransaccion(c *gin.Context, cancelar bool) {
+	var req models.ReemplazarTransaccionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, models.Response{
 			Success: false,
-			Message: "Formato de dirección de contrato inválido",
+			Message: "Datos de entrada inválidos: " + err.Error(),
 		})
 		return
 	}
 
-	// Crear nuevo lote en el contrato existente
-	txHash, err := h.blockchainService.CrearNuevoLote(
-		req.PrivateKey,
-		req.ContractAddress,
-		req.LoteID,
-		req.TemperaturaMin,
-		req.TemperaturaMax,
-	)
+	// Resolver la cuenta que firma
+	firmante, ok := h.resolverFirmante(c, req.Account, req.PrivateKey, "")
+	if !ok {
+		return
+	}
+	red, ok := h.resolverRed(c, req.Network)
+	if !ok {
+		return
+	}
+
+	var transaccion *models.TransaccionEnviada
+	var err error
+	if cancelar {
+		transaccion, err = red.Service.CancelarTransaccion(firmante, *req.Nonce)
+	} else {
+		transaccion, err = red.Service.AcelerarTransaccion(firmante, *req.Nonce)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.Response{
+		c.JSON(estadoErrorTransaccion(err), models.Response{
 			Success: false,
-			Message: "Error creando nuevo lote: " + err.Error(),
+			Message: "Error reemplazando transacción: " + err.Error(),
 		})
 		return
 	}
 
-	response := map[string]interface{}{
-		"contractAddress": req.ContractAddress,
-		"loteId":          req.LoteID,
-		"txHash":          txHash,
+	message := "Transacción acelerada exitosamente"
+	if cancelar {
+		message = "Transacción cancelada exitosamente"
 	}
-
 	c.JSON(http.StatusOK, models.Response{
 		Success: true,
-		Message: "Nuevo lote creado exitosamente en contrato existente",
-		Data:    response,
-		TxHash:  txHash,
+		Message: message,
+		Data: map[string]interface{}{
+			"nonce": *req.Nonce,
+			"from":  firmante.Address().Hex(),
+		},
+		TxHash:      transaccion.TxHash,
+		Transaccion: transaccion,
+		Network:     red.Name,
 	})
 }
 
@@ -246,8 +542,13 @@ func (h *LoteHandler) ObtenerLote(c *gin.Context) {
 		return
 	}
 
+	red, ok := h.resolverRed(c, "")
+	if !ok {
+		return
+	}
+
 	// Obtener información del lote
-	loteInfo, err := h.blockchainService.ObtenerInfoLote(contractAddress)
+	loteInfo, err := red.Service.ObtenerInfoLote(contractAddress)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.Response{
 			Success: false,
@@ -263,25 +564,152 @@ func (h *LoteHandler) ObtenerLote(c *gin.Context) {
 	})
 }
 
-// VerificarConexion endpoint para verificar la conexión a Sepolia
+// ObtenerLotePorID busca el contrato de un loteId en el registro de lotes
+func (h *LoteHandler) ObtenerLotePorID(c *gin.Context) {
+	loteID := c.Param("loteId")
+
+	// Sin ?network= se busca en todas las redes
+	redes := h.networks.All()
+	if network := c.Query("network"); network != "" {
+		red, ok := h.resolverRed(c, network)
+		if !ok {
+			return
+		}
+		redes = []*services.Network{red}
+	}
+
+	err := services.ErrLoteNotFound
+	for _, red := range redes {
+		var lote *models.RegistroLote
+		lote, err = red.Service.ObtenerLotePorID(loteID)
+		if errors.Is(err, services.ErrLoteNotFound) {
+			continue
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.Response{
+				Success: false,
+				Message: "Error obteniendo lote: " + err.Error(),
+			})
+			return
+		}
+
+		lote.Network = red.Name
+		c.JSON(http.StatusOK, models.Response{
+			Success: true,
+			Message: "Lote obtenido exitosamente",
+			Data:    lote,
+			Network: red.Name,
+		})
+		return
+	}
+
+	c.JSON(http.StatusNotFound, models.Response{
+		Success: false,
+		Message: err.Error(),
+	})
+}
+
+// BuscarLotes lista los lotes del registro filtrando por fabricante,
+// propietario actual y estado comprometido
+func (h *LoteHandler) BuscarLotes(c *gin.Context) {
+	filtro := services.FiltroLotes{
+		Fabricante:   c.Query("fabricante"),
+		Propietario:  c.Query("propietario"),
+		SoloVigentes: c.Query("vigente") == "true",
+	}
+	for _, address := range []string{filtro.Fabricante, filtro.Propietario} {
+		if address != "" && !common.IsHexAddress(address) {
+			c.JSON(http.StatusBadRequest, models.Response{
+				Success: false,
+				Message: "Dirección inválida: " + address,
+			})
+			return
+		}
+	}
+	if valor := c.Query("comprometido"); valor != "" {
+		comprometido, err := strconv.ParseBool(valor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.Response{
+				Success: false,
+				Message: "comprometido debe ser true o false",
+			})
+			return
+		}
+		filtro.Comprometido = &comprometido
+	}
+
+	// Sin ?network= se busca en todas las redes
+	redes := h.networks.All()
+	if network := c.Query("network"); network != "" {
+		red, ok := h.resolverRed(c, network)
+		if !ok {
+			return
+		}
+		redes = []*services.Network{red}
+	}
+
+	lotes := make([]models.RegistroLote, 0)
+	for _, red := range redes {
+		for _, lote := range red.Service.BuscarLotes(filtro) {
+			lote.Network = red.Name
+			lotes = append(lotes, lote)
+		}
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: fmt.Sprintf("%d lotes encontrados", len(lotes)),
+		Data:    lotes,
+	})
+}
+
+// VerificarConexion endpoint para verificar la conexión a la red
 func (h *LoteHandler) VerificarConexion(c *gin.Context) {
+	red, ok := h.resolverRed(c, "")
+	if !ok {
+		return
+	}
+
 	// Obtener el número de bloque actual para verificar conexión
-	blockNumber, err := h.blockchainService.Client.BlockNumber(context.Background())
+	blockNumber, err := red.Service.Client.BlockNumber(context.Background())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.Response{
 			Success: false,
-			Message: "Error conectando a Sepolia: " + err.Error(),
+			Message: "Error conectando a " + red.Name + ": " + err.Error(),
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, models.Response{
 		Success: true,
-		Message: "Conexión a Sepolia exitosa",
+		Message: "Conexión a " + red.Name + " exitosa",
 		Data: map[string]interface{}{
 			"blockNumber": blockNumber,
-			"chainId":     "11155111", // Sepolia Chain ID
+			"chainId":     red.Service.ChainID().String(),
 		},
+		Network: red.Name,
+	})
+}
+
+// ListarRedes lista los perfiles de red configurados
+func (h *LoteHandler) ListarRedes(c *gin.Context) {
+	var redes []models.RedInfo
+	for _, red := range h.networks.All() {
+		redes = append(redes, models.RedInfo{
+			Nombre:         red.Name,
+			Tipo:           red.Type,
+			ChainID:        red.ChainID,
+			Confirmaciones: red.Confirmations,
+			PoliticaGas:    red.GasPolicy,
+			WebSocket:      red.Websocket != nil,
+			PorDefecto:     red.Name == h.networks.Default(),
+		})
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Redes obtenidas exitosamente",
+		Data:    redes,
 	})
 }
 
@@ -307,10 +735,19 @@ func (h *LoteHandler) ObtenerCadenaBlockchain(c *gin.Context) {
 		return
 	}
 
-	// Obtener la cadena completa de eventos del contrato usando la versión optimizada
-	cadenaBlockchain, err := h.blockchainService.ObtenerCadenaBlockchainOptimizada(contractAddress)
+	red, ok := h.resolverRed(c, "")
+	if !ok {
+		return
+	}
+
+	// Obtener la cadena completa de eventos del contrato desde el índice
+	cadenaBlockchain, err := red.Service.ObtenerCadenaBlockchain(contractAddress)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.Response{
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrContractNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, models.Response{
 			Success: false,
 			Message: "Error obteniendo cadena blockchain: " + err.Error(),
 		})
@@ -324,6 +761,62 @@ func (h *LoteHandler) ObtenerCadenaBlockchain(c *gin.Context) {
 	})
 }
 
+// ObtenerTemperaturas devuelve la serie temporal de lecturas de temperatura
+// de un contrato LoteTracing, filtrable por sensorId y por rango de timestamps
+func (h *LoteHandler) ObtenerTemperaturas(c *gin.Context) {
+	contractAddress := c.Param("contractAddress")
+	if !common.IsHexAddress(contractAddress) {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Formato de dirección de contrato inválido",
+		})
+		return
+	}
+
+	filtro := services.FiltroLecturas{SensorID: c.Query("sensorId")}
+	for _, limite := range []struct {
+		nombre string
+		valor  *uint64
+	}{{"desde", &filtro.Desde}, {"hasta", &filtro.Hasta}} {
+		if valor := c.Query(limite.nombre); valor != "" {
+			timestamp, err := strconv.ParseUint(valor, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, models.Response{
+					Success: false,
+					Message: limite.nombre + " debe ser un timestamp Unix en segundos",
+				})
+				return
+			}
+			*limite.valor = timestamp
+		}
+	}
+
+	red, ok := h.resolverRed(c, "")
+	if !ok {
+		return
+	}
+
+	temperaturas, err := red.Service.ObtenerTemperaturas(contractAddress, filtro)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrContractNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, models.Response{
+			Success: false,
+			Message: "Error obteniendo temperaturas: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: fmt.Sprintf("%d lecturas de temperatura obtenidas", temperaturas.TotalLecturas),
+		Data:    temperaturas,
+		Network: red.Name,
+	})
+}
+
 // DecodificarInputData maneja la decodificación de input data de transacciones
 func (h *LoteHandler) DecodificarInputData(c *gin.Context) {
 	// Obtener el input data desde el cuerpo de la request o parámetro
@@ -408,8 +901,13 @@ func (h *LoteHandler) DiagnosticarContrato(c *gin.Context) {
 		return
 	}
 
+	red, ok := h.resolverRed(c, "")
+	if !ok {
+		return
+	}
+
 	// Realizar diagnóstico detallado
-	diagnostico, err := h.blockchainService.DiagnosticarContrato(contractAddress)
+	diagnostico, err := red.Service.DiagnosticarContrato(contractAddress)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.Response{
 			Success: false,
@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/oracle"
+	"CrearLoteMicro/signer"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+// estadoErrorOraculo elige el código HTTP de un error del oráculo
+func estadoErrorOraculo(err error) int {
+	switch {
+	case errors.Is(err, oracle.ErrLecturaInvalida):
+		return http.StatusBadRequest
+	case errors.Is(err, oracle.ErrLecturaNotFound), errors.Is(err, oracle.ErrLoteLecturasNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, oracle.ErrLecturaSinRaiz):
+		return http.StatusConflict
+	case errors.Is(err, oracle.ErrSensorSinLote), errors.Is(err, oracle.ErrSensorOtroLote):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// resolverOraculo responde 404 y devuelve false si el oráculo está deshabilitado
+func (h *LoteHandler) resolverOraculo(c *gin.Context) (*oracle.Oraculo, bool) {
+	if h.oraculo == nil {
+		c.JSON(http.StatusNotFound, models.Response{
+			Success: false,
+			Message: "Oráculo de sensores deshabilitado (ORACLE_ENABLED=false)",
+		})
+		return nil, false
+	}
+	return h.oraculo, true
+}
+
+// autorizarOperador exige, como resolverFirmante, un cliente autenticado que
+// pueda usar la cuenta del oráculo: sus lecturas, asignaciones y anclajes
+// acaban firmados con ella. Responde el error y devuelve false si no.
+func (h *LoteHandler) autorizarOperador(c *gin.Context) bool {
+	cliente := clienteAutenticado(c)
+	if cliente == nil {
+		c.JSON(http.StatusUnauthorized, models.Response{
+			Success: false,
+			Message: signer.ErrUnauthenticated.Error(),
+		})
+		return false
+	}
+	if !cliente.CanUse(h.cuentaOraculo) {
+		c.JSON(http.StatusForbidden, models.Response{
+			Success: false,
+			Message: fmt.Sprintf("%v: %s no puede usar %s", signer.ErrAccountForbidden, cliente.Name, h.cuentaOraculo),
+		})
+		return false
+	}
+	return true
+}
+
+// RecibirLectura añade al oráculo una lectura enviada por HTTP, en cualquiera
+// de los formatos que acepta la fuente MQTT o Kafka
+func (h *LoteHandler) RecibirLectura(c *gin.Context) {
+	oraculo, ok := h.resolverOraculo(c)
+	if !ok {
+		return
+	}
+	if !h.autorizarOperador(c) {
+		return
+	}
+
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Error leyendo la solicitud: " + err.Error(),
+		})
+		return
+	}
+	lectura, err := oracle.DecodificarLectura(payload)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Datos de entrada inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	nueva, err := oraculo.Agregar(lectura)
+	if err != nil {
+		c.JSON(estadoErrorOraculo(err), models.Response{
+			Success: false,
+			Message: "Error agregando lectura: " + err.Error(),
+		})
+		return
+	}
+
+	message := "Lectura agregada al lote abierto"
+	if !nueva {
+		message = "Lectura ya recibida"
+	}
+	c.JSON(http.StatusAccepted, models.Response{
+		Success: true,
+		Message: message,
+		Data: map[string]interface{}{
+			"id":        lectura.ID,
+			"sensorId":  lectura.SensorID,
+			"duplicada": !nueva,
+		},
+	})
+}
+
+// AsignarSensor relaciona un sensor con el contrato LoteTracing de su lote
+func (h *LoteHandler) AsignarSensor(c *gin.Context) {
+	oraculo, ok := h.resolverOraculo(c)
+	if !ok {
+		return
+	}
+	if !h.autorizarOperador(c) {
+		return
+	}
+
+	var req models.AsignarSensorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Datos de entrada inválidos: " + err.Error(),
+		})
+		return
+	}
+	if err := oraculo.AsignarSensor(req.SensorID, req.ContractAddress); err != nil {
+		c.JSON(estadoErrorOraculo(err), models.Response{
+			Success: false,
+			Message: "Error asignando sensor: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Sensor asignado exitosamente",
+		Data: map[string]interface{}{
+			"sensorId":        req.SensorID,
+			"contractAddress": common.HexToAddress(req.ContractAddress).Hex(),
+		},
+	})
+}
+
+// ListarSensores devuelve la asignación de sensores a contratos y la cuenta del oráculo
+func (h *LoteHandler) ListarSensores(c *gin.Context) {
+	oraculo, ok := h.resolverOraculo(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Sensores obtenidos exitosamente",
+		Data: map[string]interface{}{
+			"oraculo":  oraculo.Cuenta().Hex(),
+			"sensores": oraculo.Sensores(),
+		},
+	})
+}
+
+// ListarLotesLecturas devuelve los lotes de lecturas del oráculo, sin las
+// lecturas; ?contractAddress= filtra por contrato
+func (h *LoteHandler) ListarLotesLecturas(c *gin.Context) {
+	oraculo, ok := h.resolverOraculo(c)
+	if !ok {
+		return
+	}
+
+	contractAddress := c.Query("contractAddress")
+	if contractAddress != "" && !common.IsHexAddress(contractAddress) {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Formato de dirección de contrato inválido",
+		})
+		return
+	}
+
+	lotes := oraculo.Lotes(contractAddress)
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Lotes de lecturas obtenidos exitosamente",
+		Data: map[string]interface{}{
+			"total": len(lotes),
+			"lotes": lotes,
+		},
+	})
+}
+
+// ObtenerLoteLecturas devuelve un lote de lecturas con sus lecturas
+func (h *LoteHandler) ObtenerLoteLecturas(c *gin.Context) {
+	oraculo, ok := h.resolverOraculo(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "ID de lote de lecturas inválido",
+		})
+		return
+	}
+	lote, err := oraculo.Lote(id)
+	if err != nil {
+		c.JSON(estadoErrorOraculo(err), models.Response{
+			Success: false,
+			Message: "Error obteniendo lote de lecturas: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Lote de lecturas obtenido exitosamente",
+		Data:    lote,
+		TxHash:  lote.TxHash,
+	})
+}
+
+// AnclarLecturas cierra los lotes abiertos sin esperar a su ventana y ancla
+// los pendientes
+func (h *LoteHandler) AnclarLecturas(c *gin.Context) {
+	oraculo, ok := h.resolverOraculo(c)
+	if !ok {
+		return
+	}
+	if !h.autorizarOperador(c) {
+		return
+	}
+
+	anclados, err := oraculo.AnclarAhora()
+	if err != nil {
+		c.JSON(estadoErrorTransaccion(err), models.Response{
+			Success: false,
+			Message: "Error anclando lecturas: " + err.Error(),
+			Data:    map[string]interface{}{"anclados": anclados},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Lecturas ancladas exitosamente",
+		Data:    map[string]interface{}{"anclados": anclados},
+	})
+}
+
+// ObtenerPruebaLectura devuelve la prueba Merkle de que una lectura forma
+// parte de un lote anclado
+func (h *LoteHandler) ObtenerPruebaLectura(c *gin.Context) {
+	oraculo, ok := h.resolverOraculo(c)
+	if !ok {
+		return
+	}
+
+	prueba, err := oraculo.Prueba(c.Param("lecturaId"))
+	if err != nil {
+		c.JSON(estadoErrorOraculo(err), models.Response{
+			Success: false,
+			Message: "Error generando prueba: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Prueba Merkle generada exitosamente",
+		Data:    prueba,
+		TxHash:  prueba.TxHash,
+	})
+}
+
+// VerificarLectura comprueba una lectura con su prueba Merkle contra una raíz
+// anclada en el contrato de la lectura
+func (h *LoteHandler) VerificarLectura(c *gin.Context) {
+	oraculo, ok := h.resolverOraculo(c)
+	if !ok {
+		return
+	}
+
+	var req models.VerificarLecturaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Datos de entrada inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	verificacion, err := oraculo.Verificar(req)
+	if err != nil {
+		c.JSON(estadoErrorOraculo(err), models.Response{
+			Success: false,
+			Message: "Error verificando lectura: " + err.Error(),
+		})
+		return
+	}
+
+	message := "La lectura está incluida en un lote anclado"
+	if !verificacion.Valida {
+		message = "La lectura no se pudo verificar"
+	}
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: message,
+		Data:    verificacion,
+	})
+}
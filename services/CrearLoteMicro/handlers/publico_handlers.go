@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/services"
+	"CrearLoteMicro/utils"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+// Tamaño en píxeles de los códigos QR
+const (
+	tamanoQRPorDefecto = 256
+	tamanoQRMinimo     = 64
+	tamanoQRMaximo     = 2048
+)
+
+// paginaLotePublico es la página que ve quien escanea el código QR
+var paginaLotePublico = template.Must(template.New("lote").Funcs(template.FuncMap{
+	"fecha": func(timestamp uint64) string {
+		return time.Unix(int64(timestamp), 0).UTC().Format("02/01/2006 15:04 UTC")
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="es">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Lote {{.LoteID}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 40rem; margin: 1rem auto; padding: 0 1rem; color: #222; }
+.estado { padding: 1rem; border-radius: .5rem; color: #fff; font-size: 1.3rem; font-weight: bold; text-align: center; }
+.integro { background: #1b7f3b; }
+.comprometido { background: #b3261e; }
+li { margin-bottom: .5rem; }
+small, code { color: #555; word-break: break-all; }
+</style>
+</head>
+<body>
+<h1>Lote {{.LoteID}}</h1>
+{{if .Comprometido}}<p class="estado comprometido">Lote comprometido: no usar</p>
+{{else if .CadenaFrioIntacta}}<p class="estado integro">Cadena de frío íntegra</p>
+{{else}}<p class="estado comprometido">Lote con excursiones de temperatura</p>{{end}}
+<p>Rango permitido: {{.TemperaturaMinima}} °C a {{.TemperaturaMaxima}} °C · {{.TotalLecturas}} lecturas registradas</p>
+<h2>Custodia</h2>
+<ol>
+{{range .Custodia}}<li>{{fecha .Timestamp}} · {{.Motivo}}<br><small>{{.NuevoPropietario}}</small></li>
+{{end}}</ol>
+<h2>Excursiones de temperatura</h2>
+{{if .Excursiones}}<ol>
+{{range .Excursiones}}<li>{{fecha .Timestamp}} · {{.TempMin}} °C a {{.TempMax}} °C{{if .SensorID}} · sensor {{.SensorID}}{{end}}</li>
+{{end}}</ol>{{else}}<p>Sin excursiones de temperatura.</p>{{end}}
+<p><small>Datos leídos de la red {{.Red}}, contrato <code>{{.ContractAddress}}</code>, hasta el bloque {{.IndexadoHasta}}.</small></p>
+</body>
+</html>
+`))
+
+// buscarResumenPublico resume el lote de id, que es un loteId o la dirección
+// de su contrato, en la red indicada o, sin red, en la primera que lo tenga
+func (h *LoteHandler) buscarResumenPublico(c *gin.Context, id string) (*models.ResumenPublicoLote, bool) {
+	redes := h.networks.All()
+	if network := c.Query("network"); network != "" {
+		red, ok := h.resolverRed(c, network)
+		if !ok {
+			return nil, false
+		}
+		redes = []*services.Network{red}
+	}
+
+	for _, red := range redes {
+		contractAddress := id
+		if !common.IsHexAddress(id) {
+			lote, err := red.Service.ObtenerLotePorID(id)
+			if errors.Is(err, services.ErrLoteNotFound) {
+				continue
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, models.Response{
+					Success: false,
+					Message: "Error obteniendo lote: " + err.Error(),
+				})
+				return nil, false
+			}
+			contractAddress = lote.ContractAddress
+		}
+
+		resumen, err := red.Service.ResumenPublico(red.Name, contractAddress)
+		if errors.Is(err, services.ErrContractNotFound) {
+			continue
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.Response{
+				Success: false,
+				Message: "Error obteniendo lote: " + err.Error(),
+			})
+			return nil, false
+		}
+		return resumen, true
+	}
+
+	c.JSON(http.StatusNotFound, models.Response{
+		Success: false,
+		Message: "Lote no encontrado: " + id,
+	})
+	return nil, false
+}
+
+// ObtenerLotePublico muestra el estado, la custodia y las excursiones de un
+// lote por su loteId o la dirección de su contrato. Responde HTML a los
+// navegadores y JSON al resto; ?formato=html|json elige el formato.
+func (h *LoteHandler) ObtenerLotePublico(c *gin.Context) {
+	resumen, ok := h.buscarResumenPublico(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	// Los escaneos de un mismo envase se repiten; unos segundos de caché
+	// alivian el nodo sin ocultar un compromiso por mucho tiempo
+	c.Header("Cache-Control", "public, max-age=30")
+
+	formato := c.Query("formato")
+	if formato == "" && c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML {
+		formato = "html"
+	}
+	if formato == "html" {
+		c.Status(http.StatusOK)
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		if err := paginaLotePublico.Execute(c.Writer, resumen); err != nil {
+			c.Error(err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Lote " + resumen.Estado,
+		Data:    resumen,
+		Network: resumen.Red,
+	})
+}
+
+// GenerarQRLote genera el código QR que apunta a la verificación pública de
+// un lote, en PNG o con ?formato=svg en SVG. El lote debe existir.
+func (h *LoteHandler) GenerarQRLote(c *gin.Context) {
+	id := c.Param("id")
+	formato := c.DefaultQuery("formato", "png")
+	if formato != "png" && formato != "svg" {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Formato inválido: " + formato + " (válidos: png, svg)",
+		})
+		return
+	}
+	tamano := tamanoQRPorDefecto
+	if valor := c.Query("tamano"); valor != "" {
+		var err error
+		if tamano, err = strconv.Atoi(valor); err != nil || tamano < tamanoQRMinimo || tamano > tamanoQRMaximo {
+			c.JSON(http.StatusBadRequest, models.Response{
+				Success: false,
+				Message: "tamano debe ser un entero entre " + strconv.Itoa(tamanoQRMinimo) + " y " + strconv.Itoa(tamanoQRMaximo),
+			})
+			return
+		}
+	}
+
+	resumen, ok := h.buscarResumenPublico(c, id)
+	if !ok {
+		return
+	}
+
+	base := h.urlPublica
+	if base == "" {
+		esquema := "http"
+		if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+			esquema = "https"
+		}
+		base = esquema + "://" + c.Request.Host
+	}
+	destino := base + "/api/v1/public/lote/" + url.PathEscape(id) + "?network=" + url.QueryEscape(resumen.Red)
+
+	var codigo []byte
+	var err error
+	contentType := "image/png"
+	if formato == "svg" {
+		codigo, err = utils.QRSVG(destino, tamano)
+		contentType = "image/svg+xml"
+	} else {
+		codigo, err = utils.QRPNG(destino, tamano)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Header("X-QR-URL", destino)
+	c.Data(http.StatusOK, contentType, codigo)
+}
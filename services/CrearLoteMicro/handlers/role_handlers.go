@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/services"
+	"errors"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+// OtorgarRol asigna un rol de un contrato LoteTracing a una cuenta
+func (h *LoteHandler) OtorgarRol(c *gin.Context) {
+	h.cambiarRol(c, true)
+}
+
+// RevocarRol retira un rol de un contrato LoteTracing a una cuenta
+func (h *LoteHandler) RevocarRol(c *gin.Context) {
+	h.cambiarRol(c, false)
+}
+
+func (h *LoteHandler) cambiarRol(c *gin.Context, otorgar bool) {
+	var req models.RolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Datos de entrada inválidos: " + err.Error(),
+		})
+		return
+	}
+	for _, address := range []string{req.ContractAddress, req.Cuenta} {
+		if !common.IsHexAddress(address) {
+			c.JSON(http.StatusBadRequest, models.Response{
+				Success: false,
+				Message: "Dirección inválida: " + address,
+			})
+			return
+		}
+	}
+	if _, err := services.IDRol(req.Rol); err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	firmante, ok := h.resolverFirmante(c, req.Account, req.PrivateKey, "")
+	if !ok {
+		return
+	}
+	red, ok := h.resolverRed(c, req.Network)
+	if !ok {
+		return
+	}
+
+	cambiar, accion, message := red.Service.OtorgarRol, "otorgando", "Rol otorgado exitosamente"
+	if !otorgar {
+		cambiar, accion, message = red.Service.RevocarRol, "revocando", "Rol revocado exitosamente"
+	}
+	transaccion, err := cambiar(firmante, req.ContractAddress, req.Rol, req.Cuenta)
+	if err != nil {
+		c.JSON(estadoErrorTransaccion(err), models.Response{
+			Success: false,
+			Message: "Error " + accion + " rol: " + err.Error(),
+		})
+		return
+	}
+
+	h.responderTransaccion(c, red, models.Response{
+		Success: true,
+		Message: message,
+		Data: map[string]interface{}{
+			"contractAddress": common.HexToAddress(req.ContractAddress).Hex(),
+			"rol":             req.Rol,
+			"cuenta":          common.HexToAddress(req.Cuenta).Hex(),
+		},
+		TxHash:      transaccion.TxHash,
+		Transaccion: transaccion,
+	})
+}
+
+// ObtenerRoles devuelve el administrador de un contrato LoteTracing y las
+// cuentas de cada rol
+func (h *LoteHandler) ObtenerRoles(c *gin.Context) {
+	contractAddress := c.Param("contractAddress")
+	if !common.IsHexAddress(contractAddress) {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Success: false,
+			Message: "Formato de dirección de contrato inválido",
+		})
+		return
+	}
+
+	red, ok := h.resolverRed(c, "")
+	if !ok {
+		return
+	}
+
+	roles, err := red.Service.ObtenerRoles(contractAddress)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrContractNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, models.Response{
+			Success: false,
+			Message: "Error obteniendo roles: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Roles obtenidos exitosamente",
+		Data:    roles,
+		Network: red.Name,
+	})
+}
+
+// ObtenerRolesCuenta devuelve los roles de una cuenta en un contrato LoteTracing
+func (h *LoteHandler) ObtenerRolesCuenta(c *gin.Context) {
+	contractAddress, cuenta := c.Param("contractAddress"), c.Param("cuenta")
+	for _, address := range []string{contractAddress, cuenta} {
+		if !common.IsHexAddress(address) {
+			c.JSON(http.StatusBadRequest, models.Response{
+				Success: false,
+				Message: "Dirección inválida: " + address,
+			})
+			return
+		}
+	}
+
+	red, ok := h.resolverRed(c, "")
+	if !ok {
+		return
+	}
+
+	roles, err := red.Service.RolesCuenta(contractAddress, cuenta)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Success: false,
+			Message: "Error obteniendo roles de la cuenta: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Roles de la cuenta obtenidos exitosamente",
+		Data: map[string]interface{}{
+			"contractAddress": common.HexToAddress(contractAddress).Hex(),
+			"cuenta":          common.HexToAddress(cuenta).Hex(),
+			"roles":           roles,
+		},
+		Network: red.Name,
+	})
+}
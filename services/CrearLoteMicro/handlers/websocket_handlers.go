@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// resolverWebsocket obtiene el vigilante de la red de ?network=. Responde 404
+// y devuelve false si la red no existe o no tiene URL de WebSocket.
+func (h *LoteHandler) resolverWebsocket(c *gin.Context) (*services.Network, bool) {
+	red, ok := h.resolverRed(c, "")
+	if !ok {
+		return nil, false
+	}
+	if red.Websocket == nil {
+		c.JSON(http.StatusNotFound, models.Response{
+			Success: false,
+			Message: "La red " + red.Name + " no tiene WebSocket configurado (NETWORK_<NOMBRE>_WS)",
+			Network: red.Name,
+		})
+		return nil, false
+	}
+	return red, true
+}
+
+// ObtenerEstadoWebsocket devuelve el estado del vigilante de la red de
+// ?network= o, sin indicarla, el de todas las redes con WebSocket
+func (h *LoteHandler) ObtenerEstadoWebsocket(c *gin.Context) {
+	if c.Query("network") != "" {
+		red, ok := h.resolverWebsocket(c)
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, models.Response{
+			Success: true,
+			Message: "Estado del WebSocket obtenido exitosamente",
+			Data:    red.Websocket.Estado(),
+			Network: red.Name,
+		})
+		return
+	}
+
+	estados := []models.EstadoWebsocket{}
+	for _, red := range h.networks.All() {
+		if red.Websocket != nil {
+			estados = append(estados, red.Websocket.Estado())
+		}
+	}
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: "Estado del WebSocket obtenido exitosamente",
+		Data:    estados,
+	})
+}
+
+// IniciarWebsocket arranca el vigilante de la red. Exige un cliente autenticado.
+func (h *LoteHandler) IniciarWebsocket(c *gin.Context) {
+	if !exigirCliente(c) {
+		return
+	}
+	red, ok := h.resolverWebsocket(c)
+	if !ok {
+		return
+	}
+
+	message := "WebSocket iniciado"
+	if !red.Websocket.Iniciar() {
+		message = "WebSocket ya estaba en marcha"
+	}
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: message,
+		Data:    red.Websocket.Estado(),
+		Network: red.Name,
+	})
+}
+
+// DetenerWebsocket cierra la conexión del vigilante de la red; los contratos
+// se siguen indexando por sondeo. Exige un cliente autenticado.
+func (h *LoteHandler) DetenerWebsocket(c *gin.Context) {
+	if !exigirCliente(c) {
+		return
+	}
+	red, ok := h.resolverWebsocket(c)
+	if !ok {
+		return
+	}
+
+	message := "WebSocket detenido"
+	if !red.Websocket.Detener() {
+		message = "WebSocket ya estaba detenido"
+	}
+	c.JSON(http.StatusOK, models.Response{
+		Success: true,
+		Message: message,
+		Data:    red.Websocket.Estado(),
+		Network: red.Name,
+	})
+}
@@ -2,10 +2,19 @@ package main
 
 import (
 	"CrearLoteMicro/config"
+	"CrearLoteMicro/eventos"
 	"CrearLoteMicro/handlers"
+	"CrearLoteMicro/oracle"
 	"CrearLoteMicro/services"
+	"CrearLoteMicro/signer"
+	"context"
+	"fmt"
 	"log"
+	"math/big"
+	"path/filepath"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 )
 
@@ -13,25 +22,105 @@ func main() {
 	// Cargar configuración
 	cfg := config.LoadConfig()
 
-	// Inicializar servicio de blockchain
-	blockchainService, err := services.NewBlockchainService(cfg.SepoliaRPC, cfg.ChainID)
+	// Cargar cuentas firmantes
+	signers, err := signer.LoadRegistry(cfg.Signer)
 	if err != nil {
-		log.Fatalf("Error inicializando servicio de blockchain: %v", err)
+		log.Fatalf("Error cargando cuentas firmantes: %v", err)
+	}
+	if signers.AllowRawKeys() {
+		log.Printf("Claves privadas en solicitudes habilitadas (ALLOW_RAW_PRIVATE_KEYS=true)")
+	}
+
+	// Cargar los clientes de la API que pueden firmar
+	clientes, err := signer.LoadCallers(cfg.Auth)
+	if err != nil {
+		log.Fatalf("Error cargando clientes de la API: %v", err)
+	}
+	if clientes.Len() == 0 {
+		log.Printf("Sin API_CLIENTS: se rechazarán todas las solicitudes que firman")
+	}
+
+	if err := cfg.ValidateNetworks(); err != nil {
+		log.Fatalf("Error en la configuración de redes: %v", err)
+	}
+	if err := cfg.ValidateOracle(); err != nil {
+		log.Fatalf("Error en la configuración del oráculo: %v", err)
+	}
+	if err := cfg.ValidateEvents(); err != nil {
+		log.Fatalf("Error en la configuración de eventos: %v", err)
+	}
+
+	// Inicializar un servicio de blockchain por red; cada uno comprueba que
+	// el chain ID configurado coincida con eth_chainId
+	networks := services.NewNetworkRegistry(cfg.DefaultNetwork)
+	for _, networkCfg := range cfg.Networks {
+		network, err := iniciarRed(context.Background(), cfg, networkCfg, signers)
+		if err != nil {
+			log.Fatalf("Error inicializando red %s: %v", networkCfg.Name, err)
+		}
+		if err := networks.Register(network); err != nil {
+			log.Fatalf("Error registrando red %s: %v", networkCfg.Name, err)
+		}
+	}
+	log.Printf("Red por defecto: %s", cfg.DefaultNetwork)
+
+	if cfg.Events.Broker != "" {
+		if err := iniciarEventos(context.Background(), cfg, networks); err != nil {
+			log.Fatalf("Error inicializando la publicación de eventos: %v", err)
+		}
 	}
-	// Inicializar servicio de blockchainSocket
-	blockchainWebsocketService := services.NewBlockchainWebsocketService(cfg.SepoliaWS, blockchainService)
 
 	// Inicializar handlers
-	loteHandler := handlers.NewLoteHandler(blockchainService, blockchainWebsocketService)
+	loteHandler := handlers.NewLoteHandler(networks, signers)
+
+	if cfg.Oracle.Enabled {
+		oraculo, err := iniciarOraculo(context.Background(), cfg, networks, signers)
+		if err != nil {
+			log.Fatalf("Error inicializando oráculo de sensores: %v", err)
+		}
+		loteHandler.UsarOraculo(oraculo, cfg.Oracle.Account)
+	}
+	if cfg.Certificate.Account != "" {
+		certificador, err := cuentaCertificados(cfg, signers)
+		if err != nil {
+			log.Fatalf("Error en la cuenta de certificados: %v", err)
+		}
+		loteHandler.UsarCertificador(certificador)
+		log.Printf("Certificados de lote firmados por %s", certificador.Address().Hex())
+	}
+
+	loteHandler.UsarURLPublica(cfg.Public.BaseURL)
+
+	r, err := newRouter(loteHandler, clientes, cfg.Public)
+	if err != nil {
+		log.Fatalf("Error configurando el router: %v", err)
+	}
+
+	// Iniciar servidor
+	log.Printf("CrearLoteMicro iniciando en puerto %s", cfg.Port)
+
+	if err := r.Run(":" + cfg.Port); err != nil {
+		log.Fatalf("Error iniciando servidor: %v", err)
+	}
+}
 
-	// Configurar Gin
+// newRouter configura Gin con las rutas de la API. Las solicitudes que firman
+// requieren la API key de uno de los clientes. Las rutas /public no requieren
+// cuenta y se limitan por IP con publico.
+func newRouter(loteHandler *handlers.LoteHandler, clientes *signer.Callers, publico config.PublicConfig) (*gin.Engine, error) {
 	r := gin.Default()
 
+	// La IP del cliente, con la que se limitan las rutas /public, solo sale de
+	// X-Forwarded-For si la solicitud llega de un proxy de confianza
+	if err := r.SetTrustedProxies(publico.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("TRUSTED_PROXIES inválido: %v", err)
+	}
+
 	// Middleware para CORS
 	r.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -42,11 +131,34 @@ func main() {
 	})
 
 	// Rutas de la API
-	api := r.Group("/api/v1")
+	api := r.Group("/api/v1", handlers.Autenticar(clientes))
 	{
 		// Health check
 		api.GET("/health", loteHandler.HealthCheck)
 
+		// Perfiles de red configurados
+		api.GET("/redes", loteHandler.ListarRedes)
+
+		// Vigilantes WebSocket de las redes con NETWORK_<NOMBRE>_WS
+		ws := api.Group("/websocket")
+		{
+			ws.GET("", loteHandler.ObtenerEstadoWebsocket)
+			ws.POST("/iniciar", loteHandler.IniciarWebsocket)
+			ws.POST("/detener", loteHandler.DetenerWebsocket)
+		}
+
+		// Cuentas firmantes configuradas en el servidor
+		api.GET("/cuentas", loteHandler.ListarCuentas)
+		api.GET("/cuentas/:account/pendientes", loteHandler.ListarTransaccionesPendientes)
+
+		// Reemplazo de transacciones atascadas
+		tx := api.Group("/tx")
+		{
+			tx.GET("/:hash", loteHandler.ObtenerEstadoTransaccion)
+			tx.POST("/acelerar", loteHandler.AcelerarTransaccion)
+			tx.POST("/cancelar", loteHandler.CancelarTransaccion)
+		}
+
 		// Rutas de lote
 		lote := api.Group("/lote")
 		{
@@ -56,6 +168,54 @@ func main() {
 			lote.POST("/transferir", loteHandler.TransferirCustodia)
 			lote.GET("/info/:contractAddress", loteHandler.ObtenerLote)
 			lote.GET("/cadena/:contractAddress", loteHandler.ObtenerCadenaBlockchain)
+			lote.GET("/temperaturas/:contractAddress", loteHandler.ObtenerTemperaturas)
+			lote.GET("/by-id/:loteId", loteHandler.ObtenerLotePorID)
+			lote.GET("/registro", loteHandler.BuscarLotes)
+			lote.POST("/roles/otorgar", loteHandler.OtorgarRol)
+			lote.POST("/roles/revocar", loteHandler.RevocarRol)
+			lote.GET("/roles/:contractAddress", loteHandler.ObtenerRoles)
+			lote.GET("/roles/:contractAddress/:cuenta", loteHandler.ObtenerRolesCuenta)
+			lote.POST("/custodia/proponer", loteHandler.ProponerCustodia)
+			lote.POST("/custodia/aceptar", loteHandler.AceptarCustodia)
+			lote.POST("/custodia/rechazar", loteHandler.RechazarCustodia)
+			lote.POST("/custodia/cancelar", loteHandler.CancelarCustodia)
+			lote.GET("/custodia/:contractAddress", loteHandler.ObtenerPropuestaCustodia)
+			lote.GET("/certificado/:contractAddress", loteHandler.EmitirCertificado)
+			lote.POST("/certificado/verificar", loteHandler.VerificarCertificado)
+			lote.GET("/qr/:id", loteHandler.GenerarQRLote)
+		}
+
+		// Verificación pública de lotes, de solo lectura, para quien escanea
+		// el código QR del envase
+		public := api.Group("/public", handlers.LimitePorIP(publico.RateLimit, publico.Burst))
+		{
+			public.GET("/lote/:id", loteHandler.ObtenerLotePublico)
+		}
+
+		// Rutas de la LoteTracingFactory: lotes sin desplegar un contrato por lote
+		factory := api.Group("/factory")
+		{
+			factory.GET("", loteHandler.ObtenerFactory)
+			factory.POST("/desplegar", loteHandler.DesplegarFactory)
+			factory.POST("/fabricantes", loteHandler.AutorizarFabricante)
+			factory.POST("/lote", loteHandler.CrearLoteFactory)
+			factory.POST("/temperatura", loteHandler.EscribirLoteFactory)
+			factory.POST("/transferir", loteHandler.EscribirLoteFactory)
+			factory.GET("/lote/:loteId", loteHandler.ObtenerLoteFactory)
+			factory.GET("/cadena/:loteId", loteHandler.ObtenerCadenaFactory)
+		}
+
+		// Oráculo de sensores: lecturas ancladas en lotes con raíz Merkle
+		oraculo := api.Group("/oracle")
+		{
+			oraculo.POST("/lecturas", loteHandler.RecibirLectura)
+			oraculo.GET("/sensores", loteHandler.ListarSensores)
+			oraculo.POST("/sensores", loteHandler.AsignarSensor)
+			oraculo.GET("/lotes", loteHandler.ListarLotesLecturas)
+			oraculo.GET("/lotes/:id", loteHandler.ObtenerLoteLecturas)
+			oraculo.POST("/anclar", loteHandler.AnclarLecturas)
+			oraculo.GET("/prueba/:lecturaId", loteHandler.ObtenerPruebaLectura)
+			oraculo.POST("/verificar", loteHandler.VerificarLectura)
 		}
 
 		// Rutas de utilidades
@@ -74,12 +234,277 @@ func main() {
 		}
 	}
 
-	// Iniciar servidor
-	log.Printf("CrearLoteMicro iniciando en puerto %s", cfg.Port)
-	log.Printf("Conectado a Sepolia RPC: %s", cfg.SepoliaRPC)
-	log.Printf("Conectado a Sepolia WS: %s", cfg.SepoliaWS)
+	return r, nil
+}
 
-	if err := r.Run(":" + cfg.Port); err != nil {
-		log.Fatalf("Error iniciando servidor: %v", err)
+// iniciarRed crea el servicio de blockchain de un perfil de red con su
+// política de gas, sus confirmaciones y su archivo de estado de transacciones
+func iniciarRed(ctx context.Context, cfg *config.Config, networkCfg config.NetworkConfig, signers *signer.Registry) (*services.Network, error) {
+	txOptions := services.NonceManagerOptions{
+		QueueSize:        cfg.Tx.QueueSize,
+		StuckAfter:       cfg.Tx.StuckAfter,
+		PriceBumpPercent: cfg.Tx.PriceBumpPercent,
+		Fees: services.FeeOptions{
+			GasMarginPercent:        networkCfg.GasMarginPercent,
+			BaseFeeMultiplier:       networkCfg.BaseFeeMultiplier,
+			MaxFeePerGas:            networkCfg.MaxFeePerGas,
+			MaxFeePerGasByOperation: networkCfg.MaxFeePerGasByOperation,
+			Legacy:                  networkCfg.GasPolicy == config.GasPolicyLegacy,
+		},
 	}
+	trackerOptions := services.TxTrackerOptions{
+		Confirmations: networkCfg.Confirmations,
+		PollInterval:  cfg.Tx.PollInterval,
+		WaitTimeout:   cfg.Tx.WaitTimeout,
+		Retention:     cfg.Tx.StatusRetention,
+	}
+	indexerOptions := services.EventIndexerOptions{
+		PollInterval: cfg.Indexer.PollInterval,
+		BatchBlocks:  cfg.Indexer.BatchBlocks,
+		ReorgDepth:   cfg.Indexer.ReorgDepth,
+	}
+
+	network := &services.Network{
+		Name:          networkCfg.Name,
+		Type:          networkCfg.Type,
+		ChainID:       networkCfg.ChainID,
+		GasPolicy:     networkCfg.GasPolicy,
+		Confirmations: networkCfg.Confirmations,
+	}
+
+	if networkCfg.Type == config.NetworkSimulated {
+		// Blockchain en memoria: su estado se pierde al reiniciar, así que
+		// tampoco se guardan el de las transacciones, el índice de eventos ni
+		// el registro de lotes
+		blockchainService, err := iniciarBlockchainSimulada(ctx, cfg.Simulated, networkCfg.ChainID, signers, txOptions, trackerOptions, indexerOptions)
+		if err != nil {
+			return nil, err
+		}
+		network.Service = blockchainService
+		return network, nil
+	}
+
+	if cfg.Tx.StatusFile != "" {
+		trackerOptions.Store = services.NewFileTxStore(archivoEstadoRed(cfg.Tx.StatusFile, networkCfg.Name))
+	}
+	if cfg.Indexer.File != "" {
+		indexerOptions.Store = services.NewFileEventStore(archivoEstadoRed(cfg.Indexer.File, networkCfg.Name))
+	}
+	var loteStore services.LoteStore
+	if cfg.Indexer.LotesFile != "" {
+		loteStore = services.NewFileLoteStore(archivoEstadoRed(cfg.Indexer.LotesFile, networkCfg.Name))
+	}
+	blockchainService, err := services.NewBlockchainService(networkCfg.RPCURL, networkCfg.ChainID, txOptions, trackerOptions, indexerOptions, loteStore)
+	if err != nil {
+		return nil, err
+	}
+	network.Service = blockchainService
+	log.Printf("Red %s conectada: %s (chainId %d)", networkCfg.Name, networkCfg.RPCURL, networkCfg.ChainID)
+
+	if networkCfg.FactoryAddress != "" {
+		if err := blockchainService.UsarFactory(networkCfg.FactoryAddress); err != nil {
+			return nil, err
+		}
+		log.Printf("Red %s factory: %s", networkCfg.Name, networkCfg.FactoryAddress)
+	}
+
+	if networkCfg.WSURL != "" {
+		// El vigilante sigue por WebSocket todos los contratos del índice de
+		// la red y reconecta solo si la conexión cae
+		watcher, err := services.NewWebsocketWatcher(networkCfg.Name, blockchainService, services.WebsocketWatcherOptions{
+			URL:               networkCfg.WSURL,
+			ReconnectDelay:    cfg.Websocket.ReconnectDelay,
+			MaxReconnectDelay: cfg.Websocket.MaxReconnectDelay,
+			Refresco:          cfg.Websocket.RefreshInterval,
+		})
+		if err != nil {
+			return nil, err
+		}
+		network.Websocket = watcher
+		watcher.Iniciar()
+		log.Printf("Red %s WS: %s", networkCfg.Name, networkCfg.WSURL)
+	}
+	return network, nil
+}
+
+// iniciarOraculo crea el oráculo de sensores en su red, arranca el cierre de
+// ventanas y, si se configuró una fuente, la lectura de MQTT o Kafka
+func iniciarOraculo(ctx context.Context, cfg *config.Config, networks *services.NetworkRegistry, signers *signer.Registry) (*oracle.Oraculo, error) {
+	red, err := networks.Get(cfg.Oracle.Network)
+	if err != nil {
+		return nil, err
+	}
+	firmante, err := signers.Get(cfg.Oracle.Account)
+	if err != nil {
+		return nil, fmt.Errorf("cuenta del oráculo: %v", err)
+	}
+
+	options := oracle.Options{
+		Ventana:     cfg.Oracle.Window,
+		MaxLecturas: cfg.Oracle.MaxReadings,
+		Sensores:    cfg.Oracle.Sensors,
+	}
+	// En la blockchain simulada los anclajes se pierden al reiniciar, así que
+	// tampoco se guardan las lecturas
+	if cfg.Oracle.File != "" && red.Type != config.NetworkSimulated {
+		options.Store = oracle.NewFileStore(archivoEstadoRed(cfg.Oracle.File, red.Name))
+	}
+	oraculo, err := oracle.New(red.Service, firmante, options)
+	if err != nil {
+		return nil, err
+	}
+	go oraculo.Run(ctx)
+	log.Printf("Oráculo de sensores en la red %s con la cuenta %s (ventana %s)", red.Name, firmante.Address().Hex(), cfg.Oracle.Window)
+
+	var fuente oracle.Fuente
+	switch cfg.Oracle.Source {
+	case config.OracleSourceMQTT:
+		fuente = &oracle.FuenteMQTT{
+			Broker:   cfg.Oracle.MQTTBroker,
+			Topic:    cfg.Oracle.MQTTTopic,
+			ClientID: cfg.Oracle.MQTTClientID,
+			Username: cfg.Oracle.MQTTUsername,
+			Password: cfg.Oracle.MQTTPassword,
+		}
+	case config.OracleSourceKafka:
+		fuente = &oracle.FuenteKafka{
+			Brokers:  cfg.Oracle.KafkaBrokers,
+			Topic:    cfg.Oracle.KafkaTopic,
+			GroupID:  cfg.Oracle.KafkaGroupID,
+			SASL:     cfg.Oracle.KafkaSASL,
+			Username: cfg.Oracle.KafkaUsername,
+			Password: cfg.Oracle.KafkaPassword,
+		}
+	}
+	if fuente != nil {
+		go func() {
+			if err := oraculo.Consumir(ctx, fuente); err != nil {
+				log.Printf("Oráculo: la fuente %s se detuvo: %v", cfg.Oracle.Source, err)
+			}
+		}()
+	}
+
+	return oraculo, nil
+}
+
+// iniciarEventos publica en el broker configurado los eventos confirmados
+// de cada red, con un despachador por red
+func iniciarEventos(ctx context.Context, cfg *config.Config, networks *services.NetworkRegistry) error {
+	var publicador eventos.Publicador
+	switch cfg.Events.Broker {
+	case config.EventsBrokerKafka:
+		publicador = eventos.NewPublicadorKafka(cfg.Events.KafkaBrokers, cfg.Events.KafkaTopic, cfg.Events.KafkaSASL, cfg.Events.KafkaUsername, cfg.Events.KafkaPassword)
+		log.Printf("Eventos de lotes publicados en Kafka %s", cfg.Events.KafkaTopic)
+	case config.EventsBrokerRabbitMQ:
+		publicador = eventos.NewPublicadorRabbitMQ(cfg.Events.RabbitMQURL, cfg.Events.RabbitMQExchange, cfg.Events.RabbitMQQueue)
+		log.Printf("Eventos de lotes publicados en el exchange RabbitMQ %s", cfg.Events.RabbitMQExchange)
+	}
+
+	for _, red := range networks.All() {
+		options := eventos.Options{
+			Red:            red.Name,
+			Confirmaciones: red.Confirmations,
+			Intervalo:      cfg.Events.PollInterval,
+			Historial:      cfg.Events.History,
+		}
+		// La blockchain simulada empieza vacía en cada arranque, así que se
+		// publica todo su historial y no se guarda lo publicado
+		if red.Type == config.NetworkSimulated {
+			options.Historial = true
+		} else if cfg.Events.File != "" {
+			options.Store = eventos.NewFileStore(archivoEstadoRed(cfg.Events.File, red.Name))
+		}
+		despachador, err := eventos.New(red.Service, publicador, options)
+		if err != nil {
+			return err
+		}
+		go despachador.Run(ctx)
+	}
+	return nil
+}
+
+// cuentaCertificados obtiene la cuenta que firma los certificados, que debe
+// poder firmar mensajes
+func cuentaCertificados(cfg *config.Config, signers *signer.Registry) (signer.Signer, error) {
+	firmante, err := signers.Get(cfg.Certificate.Account)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := firmante.(signer.TextSigner); !ok {
+		return nil, fmt.Errorf("la cuenta %s (%s) no firma mensajes", cfg.Certificate.Account, firmante.Kind())
+	}
+	return firmante, nil
+}
+
+// archivoEstadoRed añade el nombre de la red a un archivo de estado, por
+// ejemplo ./data/tx_status.sepolia.json
+func archivoEstadoRed(path, network string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + network + ext
+}
+
+// iniciarBlockchainSimulada registra las cuentas de desarrollo, crea la
+// blockchain en memoria con su saldo y, si se configuró, despliega la factory
+// y un lote de ejemplo para que los endpoints tengan datos desde el arranque.
+// Las cuentas con nombre de rol (distribuidor, farmacia, oraculo, auditor)
+// reciben ese rol en el lote de ejemplo.
+func iniciarBlockchainSimulada(ctx context.Context, cfg config.SimulatedConfig, chainID int64, signers *signer.Registry, txOptions services.NonceManagerOptions, trackerOptions services.TxTrackerOptions, indexerOptions services.EventIndexerOptions) (*services.BlockchainService, error) {
+	if len(cfg.Accounts) == 0 {
+		return nil, fmt.Errorf("SIMULATED_ACCOUNTS no define ninguna cuenta")
+	}
+
+	var cuentas []signer.Signer
+	var direcciones []common.Address
+	for _, name := range cfg.Accounts {
+		cuenta, err := signer.NewDevSigner(name)
+		if err != nil {
+			return nil, err
+		}
+		if err := signers.Register(name, cuenta); err != nil {
+			return nil, err
+		}
+		cuentas = append(cuentas, cuenta)
+		direcciones = append(direcciones, cuenta.Address())
+		log.Printf("Cuenta de desarrollo %s: %s", name, cuenta.Address().Hex())
+	}
+
+	chain := services.NewSimulatedChain(services.SimulatedChainOptions{
+		Accounts:  direcciones,
+		Balance:   new(big.Int).Mul(big.NewInt(cfg.BalanceEth), big.NewInt(1e18)),
+		BlockTime: cfg.BlockTime,
+	})
+	go chain.Run(ctx)
+
+	blockchainService, err := services.NewBlockchainServiceWithClient(chain, chainID, txOptions, trackerOptions, indexerOptions, nil)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Blockchain simulada en memoria (chainId %d)", chainID)
+
+	if cfg.Factory {
+		factoryAddress, _, err := blockchainService.DesplegarFactory(cuentas[0])
+		if err != nil {
+			return nil, fmt.Errorf("error desplegando factory: %v", err)
+		}
+		log.Printf("LoteTracingFactory desplegada en %s", factoryAddress)
+	}
+
+	if cfg.DemoLote != "" {
+		contractAddress, _, err := blockchainService.DeployContract(cuentas[0], cfg.DemoLote, 2, 8)
+		if err != nil {
+			return nil, fmt.Errorf("error desplegando lote de ejemplo: %v", err)
+		}
+		log.Printf("Lote de ejemplo %s desplegado en %s", cfg.DemoLote, contractAddress)
+
+		for i, name := range cfg.Accounts {
+			if _, err := services.IDRol(name); err != nil || name == services.RolFabricante {
+				continue
+			}
+			if _, err := blockchainService.OtorgarRol(cuentas[0], contractAddress, name, cuentas[i].Address().Hex()); err != nil {
+				return nil, fmt.Errorf("error otorgando rol %s en el lote de ejemplo: %v", name, err)
+			}
+		}
+	}
+
+	return blockchainService, nil
 }
@@ -1,12 +1,19 @@
 package models
 
-// CrearLoteRequest representa la solicitud para crear un nuevo lote
+import "time"
+
+// CrearLoteRequest representa la solicitud para crear un nuevo lote.
+// Account es el nombre de una cuenta configurada en el servidor; PrivateKey
+// solo se acepta mientras ALLOW_RAW_PRIVATE_KEYS esté habilitado. Network es
+// el perfil de red donde se envía la transacción; vacío usa la red por defecto.
 type CrearLoteRequest struct {
-	LoteID           string `json:"loteId" binding:"required"`
-	TemperaturaMin   int8   `json:"temperaturaMin" binding:"required"`
-	TemperaturaMax   int8   `json:"temperaturaMax" binding:"required"`
-	WalletAddress    string `json:"walletAddress" binding:"required"`
-	PrivateKey       string `json:"privateKey" binding:"required"`
+	LoteID         string `json:"loteId" binding:"required"`
+	TemperaturaMin int8   `json:"temperaturaMin" binding:"required"`
+	TemperaturaMax int8   `json:"temperaturaMax" binding:"required"`
+	Account        string `json:"account,omitempty"`
+	WalletAddress  string `json:"walletAddress,omitempty"`
+	PrivateKey     string `json:"privateKey,omitempty"`
+	Network        string `json:"network,omitempty"`
 }
 
 // RegistrarTemperaturaRequest representa la solicitud para registrar temperatura
@@ -14,16 +21,61 @@ type RegistrarTemperaturaRequest struct {
 	ContractAddress string `json:"contractAddress" binding:"required"`
 	TempMin         int8   `json:"tempMin" binding:"required"`
 	TempMax         int8   `json:"tempMax" binding:"required"`
-	WalletAddress   string `json:"walletAddress" binding:"required"`
-	PrivateKey      string `json:"privateKey" binding:"required"`
+	SensorID        string `json:"sensorId,omitempty"`
+	Account         string `json:"account,omitempty"`
+	WalletAddress   string `json:"walletAddress,omitempty"`
+	PrivateKey      string `json:"privateKey,omitempty"`
+	Network         string `json:"network,omitempty"`
+}
+
+// DetallesEnvio describe el envío con el que se entrega un lote. Se publica
+// en el evento CustodiaPropuesta y el destinatario acepta su hash.
+type DetallesEnvio struct {
+	Guia          string `json:"guia,omitempty"`
+	Transportista string `json:"transportista,omitempty"`
+	Origen        string `json:"origen,omitempty"`
+	Destino       string `json:"destino,omitempty"`
+	Bultos        int    `json:"bultos,omitempty"`
+	FechaSalida   string `json:"fechaSalida,omitempty"`
+	Notas         string `json:"notas,omitempty"`
+}
+
+// ProponerCustodiaRequest representa la solicitud del propietario para
+// entregar un lote a un distribuidor o farmacia. Plazo es una duración de Go
+// (ej. "48h") durante la que el destinatario puede aceptar; vacío usa 72h.
+type ProponerCustodiaRequest struct {
+	ContractAddress string        `json:"contractAddress" binding:"required"`
+	Destinatario    string        `json:"destinatario" binding:"required"`
+	Plazo           string        `json:"plazo,omitempty"`
+	Envio           DetallesEnvio `json:"envio"`
+	Account         string        `json:"account,omitempty"`
+	WalletAddress   string        `json:"walletAddress,omitempty"`
+	PrivateKey      string        `json:"privateKey,omitempty"`
+	Network         string        `json:"network,omitempty"`
+}
+
+// AceptarCustodiaRequest representa la aceptación de una propuesta de
+// custodia. Sin Firma acepta la cuenta firmante, que debe ser el destinatario;
+// con Firma (EIP-712, 65 bytes en hex) la cuenta firmante solo envía la
+// aceptación del destinatario y paga el gas.
+type AceptarCustodiaRequest struct {
+	ContractAddress string `json:"contractAddress" binding:"required"`
+	Firma           string `json:"firma,omitempty"`
+	Account         string `json:"account,omitempty"`
+	WalletAddress   string `json:"walletAddress,omitempty"`
+	PrivateKey      string `json:"privateKey,omitempty"`
+	Network         string `json:"network,omitempty"`
 }
 
-// TransferirCustodiaRequest representa la solicitud para transferir custodia
-type TransferirCustodiaRequest struct {
-	ContractAddress   string `json:"contractAddress" binding:"required"`
-	NuevoPropietario  string `json:"nuevoPropietario" binding:"required"`
-	WalletAddress     string `json:"walletAddress" binding:"required"`
-	PrivateKey        string `json:"privateKey" binding:"required"`
+// RechazarCustodiaRequest representa el rechazo de una propuesta de custodia
+// por su destinatario, o su cancelación por el propietario (sin Motivo)
+type RechazarCustodiaRequest struct {
+	ContractAddress string `json:"contractAddress" binding:"required"`
+	Motivo          string `json:"motivo,omitempty"`
+	Account         string `json:"account,omitempty"`
+	WalletAddress   string `json:"walletAddress,omitempty"`
+	PrivateKey      string `json:"privateKey,omitempty"`
+	Network         string `json:"network,omitempty"`
 }
 
 // CrearNuevoLoteRequest representa la solicitud para crear un nuevo lote en un contrato existente
@@ -32,15 +84,80 @@ type CrearNuevoLoteRequest struct {
 	LoteID          string `json:"loteId" binding:"required"`
 	TemperaturaMin  int8   `json:"temperaturaMin" binding:"required"`
 	TemperaturaMax  int8   `json:"temperaturaMax" binding:"required"`
-	PrivateKey      string `json:"privateKey" binding:"required"`
+	Account         string `json:"account,omitempty"`
+	PrivateKey      string `json:"privateKey,omitempty"`
+	Network         string `json:"network,omitempty"`
+}
+
+// RolRequest representa la solicitud del administrador de un contrato para
+// otorgar o revocar un rol (fabricante, distribuidor, farmacia, oraculo o
+// auditor) a una cuenta
+type RolRequest struct {
+	ContractAddress string `json:"contractAddress" binding:"required"`
+	Rol             string `json:"rol" binding:"required"`
+	Cuenta          string `json:"cuenta" binding:"required"`
+	Account         string `json:"account,omitempty"`
+	PrivateKey      string `json:"privateKey,omitempty"`
+	Network         string `json:"network,omitempty"`
+}
+
+// DesplegarFactoryRequest representa la solicitud para desplegar una
+// LoteTracingFactory; la cuenta firmante queda como administrador
+type DesplegarFactoryRequest struct {
+	Account    string `json:"account,omitempty"`
+	PrivateKey string `json:"privateKey,omitempty"`
+	Network    string `json:"network,omitempty"`
+}
+
+// AutorizarFabricanteRequest representa la solicitud del administrador para
+// conceder o retirar a una cuenta el permiso de crear lotes en la factory
+type AutorizarFabricanteRequest struct {
+	Fabricante string `json:"fabricante" binding:"required"`
+	Autorizado *bool  `json:"autorizado" binding:"required"`
+	Account    string `json:"account,omitempty"`
+	PrivateKey string `json:"privateKey,omitempty"`
+	Network    string `json:"network,omitempty"`
+}
+
+// CrearLoteFactoryRequest representa la solicitud para crear un lote en la factory
+type CrearLoteFactoryRequest struct {
+	LoteID         string `json:"loteId" binding:"required"`
+	TemperaturaMin int8   `json:"temperaturaMin" binding:"required"`
+	TemperaturaMax int8   `json:"temperaturaMax" binding:"required"`
+	Account        string `json:"account,omitempty"`
+	PrivateKey     string `json:"privateKey,omitempty"`
+	Network        string `json:"network,omitempty"`
 }
 
 // Response representa una respuesta genérica de la API
 type Response struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
-	TxHash  string      `json:"txHash,omitempty"`
+	Success     bool                `json:"success"`
+	Message     string              `json:"message"`
+	Data        interface{}         `json:"data,omitempty"`
+	TxHash      string              `json:"txHash,omitempty"`
+	Transaccion *TransaccionEnviada `json:"transaccion,omitempty"`
+	Estado      *EstadoTransaccion  `json:"estado,omitempty"`
+	Network     string              `json:"network,omitempty"`
+}
+
+// TransaccionEnviada resume las comisiones elegidas y el coste de una transacción.
+// Las cantidades se expresan en wei como texto.
+type TransaccionEnviada struct {
+	TxHash               string `json:"txHash"`
+	From                 string `json:"from"`
+	Nonce                uint64 `json:"nonce"`
+	Operacion            string `json:"operacion,omitempty"`
+	Tipo                 string `json:"tipo"`
+	GasLimit             uint64 `json:"gasLimit"`
+	GasEstimado          uint64 `json:"gasEstimado,omitempty"`
+	BaseFee              string `json:"baseFee,omitempty"`
+	MaxFeePerGas         string `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas,omitempty"`
+	GasPrice             string `json:"gasPrice,omitempty"`
+	CostoEstimadoWei     string `json:"costoEstimadoWei"`
+	CostoMaximoWei       string `json:"costoMaximoWei"`
+	CostoEstimadoEth     string `json:"costoEstimadoEth"`
+	CostoMaximoEth       string `json:"costoMaximoEth"`
 }
 
 // ContractDeployResponse representa la respuesta al desplegar un contrato
@@ -48,6 +165,7 @@ type ContractDeployResponse struct {
 	ContractAddress string `json:"contractAddress"`
 	TxHash          string `json:"txHash"`
 	LoteID          string `json:"loteId"`
+	From            string `json:"from"`
 }
 
 // ObtenerLoteRequest representa la solicitud para obtener información de un lote
@@ -75,6 +193,10 @@ type EventoBlockchain struct {
 	TxHash          string                 `json:"txHash"`
 	Timestamp       uint64                 `json:"timestamp"`
 	Datos           map[string]interface{} `json:"datos"`
+	BlockHash       string                 `json:"blockHash,omitempty"`
+	LogIndex        uint                   `json:"logIndex"`
+	// From es la cuenta que envió la transacción; solo se indica en LoteCreado
+	From string `json:"from,omitempty"`
 }
 
 // CadenaBlockchainResponse representa el historial completo de eventos de un contrato
@@ -83,4 +205,453 @@ type CadenaBlockchainResponse struct {
 	LoteID          string              `json:"loteId"`
 	TotalEventos    int                 `json:"totalEventos"`
 	Eventos         []EventoBlockchain  `json:"eventos"`
-}
\ No newline at end of file
+	// IndexadoHasta es el último bloque incluido en el historial
+	IndexadoHasta   uint64              `json:"indexadoHasta"`
+}
+// TransaccionPendiente representa una transacción enviada por una cuenta que aún no se ha confirmado
+type TransaccionPendiente struct {
+	Nonce         uint64    `json:"nonce"`
+	TxHash        string    `json:"txHash"`
+	Operacion     string    `json:"operacion,omitempty"`
+	To            string    `json:"to,omitempty"`
+	MaxFeePerGas  string    `json:"maxFeePerGas"`
+	EnviadaEn     time.Time `json:"enviadaEn"`
+	Atascada      bool      `json:"atascada"`
+	HashesPrevios []string  `json:"hashesPrevios,omitempty"`
+}
+
+// ReemplazarTransaccionRequest representa la solicitud para acelerar o cancelar una transacción pendiente
+type ReemplazarTransaccionRequest struct {
+	Account    string  `json:"account,omitempty"`
+	PrivateKey string  `json:"privateKey,omitempty"`
+	Nonce      *uint64 `json:"nonce" binding:"required"`
+	Network    string  `json:"network,omitempty"`
+}
+
+// Estados de una transacción seguida por el servicio
+const (
+	EstadoPendiente   = "pendiente"
+	EstadoMinada      = "minada"
+	EstadoConfirmada  = "confirmada"
+	EstadoRevertida   = "revertida"
+	EstadoReemplazada = "reemplazada"
+)
+
+// EstadoTransaccion es el estado de confirmación de una transacción enviada
+type EstadoTransaccion struct {
+	TxHash                   string    `json:"txHash"`
+	From                     string    `json:"from"`
+	Nonce                    uint64    `json:"nonce"`
+	Operacion                string    `json:"operacion,omitempty"`
+	Estado                   string    `json:"estado"`
+	Final                    bool      `json:"final"`
+	BlockNumber              uint64    `json:"blockNumber,omitempty"`
+	BlockHash                string    `json:"blockHash,omitempty"`
+	Confirmaciones           uint64    `json:"confirmaciones"`
+	ConfirmacionesRequeridas uint64    `json:"confirmacionesRequeridas"`
+	GasUsado                 uint64    `json:"gasUsado,omitempty"`
+	ContractAddress          string    `json:"contractAddress,omitempty"`
+	MotivoReversion          string    `json:"motivoReversion,omitempty"`
+	ReemplazadaPor           string    `json:"reemplazadaPor,omitempty"`
+	Reorgs                   int       `json:"reorgs,omitempty"`
+	EnviadaEn                time.Time `json:"enviadaEn"`
+	ActualizadaEn            time.Time `json:"actualizadaEn"`
+}
+
+// RedInfo describe un perfil de red configurado
+type RedInfo struct {
+	Nombre         string `json:"nombre"`
+	Tipo           string `json:"tipo"`
+	ChainID        int64  `json:"chainId"`
+	Confirmaciones uint64 `json:"confirmaciones"`
+	PoliticaGas    string `json:"politicaGas"`
+	WebSocket      bool   `json:"webSocket"`
+	PorDefecto     bool   `json:"porDefecto"`
+}
+
+// ContratoIndexado es el historial de eventos de un contrato en el índice
+type ContratoIndexado struct {
+	ContractAddress string `json:"contractAddress"`
+	// DesdeBloque es el bloque a partir del cual el contrato puede tener eventos
+	DesdeBloque uint64 `json:"desdeBloque"`
+	// SiguienteBloque es el primer bloque aún no indexado del contrato
+	SiguienteBloque uint64             `json:"siguienteBloque"`
+	Eventos         []EventoBlockchain `json:"eventos"`
+}
+
+// BloqueIndexado es un bloque reciente usado para detectar reorganizaciones
+type BloqueIndexado struct {
+	Numero uint64 `json:"numero"`
+	Hash   string `json:"hash"`
+}
+
+// IndiceEventos es el estado guardado del indexador de eventos
+type IndiceEventos struct {
+	// Bloques son los últimos bloques indexados, del más antiguo al más reciente
+	Bloques   []BloqueIndexado   `json:"bloques"`
+	Contratos []ContratoIndexado `json:"contratos"`
+}
+
+// RegistroLote relaciona un loteId con su contrato y el estado que reflejan
+// sus eventos
+type RegistroLote struct {
+	LoteID            string `json:"loteId"`
+	ContractAddress   string `json:"contractAddress"`
+	Network           string `json:"network,omitempty"`
+	Fabricante        string `json:"fabricante"`
+	PropietarioActual string `json:"propietarioActual"`
+	Comprometido      bool   `json:"comprometido"`
+	TemperaturaMinima int8   `json:"temperaturaMinima"`
+	TemperaturaMaxima int8   `json:"temperaturaMaxima"`
+	// Vigente es false cuando crearNuevoLote reutilizó el contrato para otro lote
+	Vigente bool   `json:"vigente"`
+	TxHash  string `json:"txHash"`
+	// BlockNumber es 0 mientras el despliegue no se haya indexado
+	BlockNumber uint64 `json:"blockNumber"`
+	Timestamp   uint64 `json:"timestamp"`
+}
+
+// RolesLoteResponse es el administrador de un contrato LoteTracing y las
+// cuentas que tienen cada rol
+type RolesLoteResponse struct {
+	ContractAddress string              `json:"contractAddress"`
+	Admin           string              `json:"admin"`
+	Roles           map[string][]string `json:"roles"`
+}
+
+// LecturaTemperatura es una lectura emitida en TemperaturaRegistrada
+type LecturaTemperatura struct {
+	SensorID    string `json:"sensorId"`
+	TempMin     int8   `json:"tempMin"`
+	TempMax     int8   `json:"tempMax"`
+	EnRango     bool   `json:"enRango"`
+	Timestamp   uint64 `json:"timestamp"`
+	Oraculo     string `json:"oraculo"`
+	BlockNumber uint64 `json:"blockNumber"`
+	TxHash      string `json:"txHash"`
+}
+
+// TemperaturasLoteResponse es la serie temporal de lecturas de un contrato LoteTracing
+type TemperaturasLoteResponse struct {
+	ContractAddress      string               `json:"contractAddress"`
+	LoteID               string               `json:"loteId"`
+	TemperaturaMinima    int8                 `json:"temperaturaMinima"`
+	TemperaturaMaxima    int8                 `json:"temperaturaMaxima"`
+	TotalLecturas        int                  `json:"totalLecturas"`
+	LecturasFueraDeRango int                  `json:"lecturasFueraDeRango"`
+	Lecturas             []LecturaTemperatura `json:"lecturas"`
+	// IndexadoHasta es el último bloque incluido en la serie
+	IndexadoHasta uint64 `json:"indexadoHasta"`
+}
+
+// FactoryInfo describe la LoteTracingFactory de una red
+type FactoryInfo struct {
+	FactoryAddress string `json:"factoryAddress"`
+	Admin          string `json:"admin"`
+	TotalLotes     uint64 `json:"totalLotes"`
+}
+
+// LoteFactoryInfo es el estado de un lote registrado en la LoteTracingFactory
+type LoteFactoryInfo struct {
+	LoteID            string `json:"loteId"`
+	FactoryAddress    string `json:"factoryAddress"`
+	Fabricante        string `json:"fabricante"`
+	PropietarioActual string `json:"propietarioActual"`
+	TemperaturaMinima int8   `json:"temperaturaMinima"`
+	TemperaturaMaxima int8   `json:"temperaturaMaxima"`
+	TempRegMinima     int8   `json:"tempRegMinima"`
+	TempRegMaxima     int8   `json:"tempRegMaxima"`
+	Comprometido      bool   `json:"comprometido"`
+	BloqueCreacion    uint64 `json:"bloqueCreacion"`
+}
+
+// LecturaSensor es una lectura de un sensor recibida por el oráculo. Se queda
+// fuera de la cadena; on-chain solo se ancla la raíz Merkle de su lote.
+type LecturaSensor struct {
+	ID              string  `json:"id"`
+	SensorID        string  `json:"sensorId"`
+	ContractAddress string  `json:"contractAddress"`
+	Temperatura     float64 `json:"temperatura"`
+	Humedad         float64 `json:"humedad"`
+	// Timestamp es el momento de la lectura (segundos Unix)
+	Timestamp int64 `json:"timestamp"`
+}
+
+// Estados de un lote de lecturas del oráculo
+const (
+	// LoteLecturasAbierto sigue aceptando lecturas hasta cerrar su ventana
+	LoteLecturasAbierto = "abierto"
+	// LoteLecturasPendiente tiene la raíz calculada y espera a anclarse
+	LoteLecturasPendiente = "pendiente"
+	// LoteLecturasAnclado tiene enviada la transacción anclarLecturas
+	LoteLecturasAnclado = "anclado"
+)
+
+// LoteLecturas agrupa las lecturas de un contrato LoteTracing durante una
+// ventana del oráculo
+type LoteLecturas struct {
+	ID              uint64 `json:"id"`
+	ContractAddress string `json:"contractAddress"`
+	Estado          string `json:"estado"`
+	// AbiertoEn es cuándo llegó la primera lectura (segundos Unix)
+	AbiertoEn     int64  `json:"abiertoEn"`
+	RaizMerkle    string `json:"raizMerkle,omitempty"`
+	TempMin       int8   `json:"tempMin"`
+	TempMax       int8   `json:"tempMax"`
+	TotalLecturas int    `json:"totalLecturas"`
+	Desde         int64  `json:"desde"`
+	Hasta         int64  `json:"hasta"`
+	TxHash        string `json:"txHash,omitempty"`
+	// Error es el último fallo al anclar; el lote se reintenta mientras esté pendiente
+	Error    string          `json:"error,omitempty"`
+	Lecturas []LecturaSensor `json:"lecturas,omitempty"`
+}
+
+// PruebaLectura es la prueba Merkle de que una lectura forma parte de un lote anclado
+type PruebaLectura struct {
+	Lectura         LecturaSensor `json:"lectura"`
+	Hoja            string        `json:"hoja"`
+	Prueba          []string      `json:"prueba"`
+	RaizMerkle      string        `json:"raizMerkle"`
+	ContractAddress string        `json:"contractAddress"`
+	LoteLecturas    uint64        `json:"loteLecturas"`
+	Estado          string        `json:"estado"`
+	TxHash          string        `json:"txHash,omitempty"`
+}
+
+// VerificarLecturaRequest es una lectura con su prueba Merkle a verificar
+type VerificarLecturaRequest struct {
+	Lectura    LecturaSensor `json:"lectura"`
+	Prueba     []string      `json:"prueba"`
+	RaizMerkle string        `json:"raizMerkle" binding:"required"`
+}
+
+// VerificacionLectura es el resultado de verificar una lectura contra una raíz
+type VerificacionLectura struct {
+	Hoja       string `json:"hoja"`
+	RaizMerkle string `json:"raizMerkle"`
+	// Incluida indica que la prueba lleva de la hoja a la raíz
+	Incluida bool `json:"incluida"`
+	// Anclada indica que la raíz está guardada en el contrato de la lectura
+	Anclada          bool   `json:"anclada"`
+	TimestampAnclaje uint64 `json:"timestampAnclaje,omitempty"`
+	Valida           bool   `json:"valida"`
+}
+
+// AsignarSensorRequest asigna un sensor al contrato LoteTracing de su lote
+type AsignarSensorRequest struct {
+	SensorID        string `json:"sensorId" binding:"required"`
+	ContractAddress string `json:"contractAddress" binding:"required"`
+}
+
+// PropuestaCustodia es el estado del traspaso de custodia en dos pasos de un
+// contrato LoteTracing
+type PropuestaCustodia struct {
+	ContractAddress   string `json:"contractAddress"`
+	PropietarioActual string `json:"propietarioActual"`
+	// Propuesta es el número de la última propuesta del contrato
+	Propuesta uint32 `json:"propuesta"`
+	// Pendiente indica que hay una propuesta que el destinatario aún puede aceptar
+	Pendiente     bool           `json:"pendiente"`
+	Vencida       bool           `json:"vencida"`
+	Destinatario  string         `json:"destinatario,omitempty"`
+	Expira        int64          `json:"expira,omitempty"`
+	Envio         string         `json:"envio,omitempty"`
+	DetallesEnvio *DetallesEnvio `json:"detallesEnvio,omitempty"`
+	// HashAceptacion es el hash EIP-712 que firma el destinatario para aceptar
+	HashAceptacion string `json:"hashAceptacion,omitempty"`
+	// TypedData son los datos tipados EIP-712 de la aceptación, en el formato
+	// de eth_signTypedData_v4
+	TypedData interface{} `json:"typedData,omitempty"`
+}
+
+// CertificadoLote es el certificado de cadena de frío de un contrato
+// LoteTracing. Todos los campos proceden de la cadena; cada evento citado
+// tiene su referencia en Transacciones.
+type CertificadoLote struct {
+	Version         int              `json:"version"`
+	Red             string           `json:"red"`
+	ChainID         int64            `json:"chainId"`
+	ContractAddress string           `json:"contractAddress"`
+	Lote            LoteInfoResponse `json:"lote"`
+	// CadenaFrioIntacta indica que el lote no está comprometido y no tiene
+	// excursiones de temperatura
+	CadenaFrioIntacta bool                    `json:"cadenaFrioIntacta"`
+	TotalLecturas     int                     `json:"totalLecturas"`
+	Custodia          []EslabonCustodia       `json:"custodia"`
+	Excursiones       []ExcursionTemperatura  `json:"excursiones"`
+	Transacciones     []ReferenciaTransaccion `json:"transacciones"`
+	// BloqueReferencia es el último bloque indexado al emitir el certificado
+	BloqueReferencia     uint64 `json:"bloqueReferencia"`
+	HashBloqueReferencia string `json:"hashBloqueReferencia"`
+	Emisor               string `json:"emisor"`
+	EmitidoEn            int64  `json:"emitidoEn"`
+}
+
+// EslabonCustodia es un cambio de propietario del lote; el primero es la
+// creación del lote por el fabricante
+type EslabonCustodia struct {
+	PropietarioAnterior string `json:"propietarioAnterior,omitempty"`
+	NuevoPropietario    string `json:"nuevoPropietario"`
+	Motivo              string `json:"motivo"`
+	Timestamp           uint64 `json:"timestamp"`
+	BlockNumber         uint64 `json:"blockNumber"`
+	TxHash              string `json:"txHash"`
+}
+
+// ExcursionTemperatura es una lectura o un lote de lecturas anclado fuera
+// del rango permitido
+type ExcursionTemperatura struct {
+	Evento        string `json:"evento"`
+	SensorID      string `json:"sensorId,omitempty"`
+	RaizMerkle    string `json:"raizMerkle,omitempty"`
+	TotalLecturas uint32 `json:"totalLecturas,omitempty"`
+	TempMin       int8   `json:"tempMin"`
+	TempMax       int8   `json:"tempMax"`
+	Timestamp     uint64 `json:"timestamp"`
+	BlockNumber   uint64 `json:"blockNumber"`
+	TxHash        string `json:"txHash"`
+}
+
+// ReferenciaTransaccion ubica un evento del certificado en la cadena
+type ReferenciaTransaccion struct {
+	Evento      string `json:"evento"`
+	TxHash      string `json:"txHash"`
+	BlockNumber uint64 `json:"blockNumber"`
+	BlockHash   string `json:"blockHash"`
+	LogIndex    uint   `json:"logIndex"`
+}
+
+// CertificadoFirmado es el documento que se entrega: el certificado, el
+// keccak256 de su JSON y la firma EIP-191 (personal_sign) de ese hash
+type CertificadoFirmado struct {
+	Certificado CertificadoLote `json:"certificado" binding:"required"`
+	Hash        string          `json:"hash" binding:"required"`
+	Firma       string          `json:"firma" binding:"required"`
+}
+
+// VerificacionCertificado es el resultado de comprobar un certificado contra
+// la cadena
+type VerificacionCertificado struct {
+	Valido bool `json:"valido"`
+	// HashCoincide indica que el certificado no se modificó tras firmarse
+	HashCoincide bool `json:"hashCoincide"`
+	// FirmaValida indica que la firma es del emisor del certificado
+	FirmaValida bool   `json:"firmaValida"`
+	Emisor      string `json:"emisor,omitempty"`
+	// EmisorReconocido indica que el emisor es la cuenta que firma los
+	// certificados de este servicio
+	EmisorReconocido bool `json:"emisorReconocido"`
+	RedCoincide      bool `json:"redCoincide"`
+	// BloqueReferenciaValido indica que el bloque de referencia sigue en la
+	// cadena con el mismo hash
+	BloqueReferenciaValido bool `json:"bloqueReferenciaValido"`
+	// ContenidoCoincide indica que la custodia, las excursiones y las lecturas
+	// rehechas con los eventos citados coinciden con las del certificado
+	ContenidoCoincide bool                      `json:"contenidoCoincide"`
+	Transacciones     []VerificacionTransaccion `json:"transacciones"`
+	// EventosPosteriores son los eventos del contrato posteriores al bloque de
+	// referencia; con alguno el certificado ya no describe el estado actual
+	EventosPosteriores int      `json:"eventosPosteriores"`
+	Vigente            bool     `json:"vigente"`
+	Errores            []string `json:"errores,omitempty"`
+}
+
+// VerificacionTransaccion es el resultado de comprobar una referencia del
+// certificado
+type VerificacionTransaccion struct {
+	Evento         string `json:"evento"`
+	TxHash         string `json:"txHash"`
+	BlockNumber    uint64 `json:"blockNumber"`
+	Valida         bool   `json:"valida"`
+	Confirmaciones uint64 `json:"confirmaciones,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// Estados públicos de un lote
+const (
+	EstadoLoteIntegro      = "integro"
+	EstadoLoteComprometido = "comprometido"
+)
+
+// ResumenPublicoLote es lo que ve quien escanea el código QR de un envase:
+// el estado del lote, sus cambios de custodia y sus excursiones de temperatura
+type ResumenPublicoLote struct {
+	LoteID            string `json:"loteId"`
+	ContractAddress   string `json:"contractAddress"`
+	Red               string `json:"red"`
+	Estado            string `json:"estado"`
+	Comprometido      bool   `json:"comprometido"`
+	CadenaFrioIntacta bool   `json:"cadenaFrioIntacta"`
+	TemperaturaMinima int8   `json:"temperaturaMinima"`
+	TemperaturaMaxima int8   `json:"temperaturaMaxima"`
+	TotalLecturas     int    `json:"totalLecturas"`
+	PropietarioActual string `json:"propietarioActual"`
+	// Custodia y Excursiones cubren el lote desde su último LoteCreado
+	Custodia      []EslabonCustodia      `json:"custodia"`
+	Excursiones   []ExcursionTemperatura `json:"excursiones"`
+	IndexadoHasta uint64                 `json:"indexadoHasta"`
+}
+
+// Tipos de los eventos de dominio que se publican a la plataforma
+const (
+	EventoLoteComprometido        = "lote.compromised"
+	EventoLoteCustodiaTransferida = "lote.custody_transferred"
+)
+
+// EventoDominioLote es un evento de un contrato LoteTracing publicado en
+// Kafka o RabbitMQ. Usa los nombres en snake_case de los eventos del resto
+// de la plataforma (event_type, timestamp). EventID identifica el log en la
+// cadena, así que una reentrega conserva el mismo ID.
+type EventoDominioLote struct {
+	EventID         string    `json:"event_id"`
+	EventType       string    `json:"event_type"`
+	LoteID          string    `json:"lote_id"`
+	ContractAddress string    `json:"contract_address"`
+	Network         string    `json:"network"`
+	ChainID         int64     `json:"chain_id"`
+	TxHash          string    `json:"tx_hash"`
+	BlockNumber     uint64    `json:"block_number"`
+	LogIndex        uint      `json:"log_index"`
+	Timestamp       time.Time `json:"timestamp"`
+	// Compromise acompaña a lote.compromised
+	Compromise *CompromisoLote `json:"compromise,omitempty"`
+	// Custody acompaña a lote.custody_transferred
+	Custody *TraspasoCustodiaLote `json:"custody,omitempty"`
+}
+
+// CompromisoLote es la lectura fuera de rango que comprometió el lote
+type CompromisoLote struct {
+	Owner   string `json:"owner"`
+	TempMin int8   `json:"temp_min"`
+	TempMax int8   `json:"temp_max"`
+	Reason  string `json:"reason"`
+}
+
+// TraspasoCustodiaLote es un cambio de propietario del lote
+type TraspasoCustodiaLote struct {
+	PreviousOwner string `json:"previous_owner"`
+	NewOwner      string `json:"new_owner"`
+	// Compromised indica que el lote ya estaba comprometido al entregarse
+	Compromised bool   `json:"compromised"`
+	Reason      string `json:"reason"`
+}
+
+// EstadoWebsocket es el estado del vigilante WebSocket de una red
+type EstadoWebsocket struct {
+	Red string `json:"red"`
+	// Activo indica que el vigilante está en marcha, conectado o reintentando
+	Activo    bool `json:"activo"`
+	Conectado bool `json:"conectado"`
+	// Contratos son las direcciones suscritas en la conexión actual
+	Contratos        []string `json:"contratos"`
+	UltimoBloque     uint64   `json:"ultimoBloque"`
+	EventosRecibidos uint64   `json:"eventosRecibidos"`
+	Reconexiones     uint64   `json:"reconexiones"`
+	// BloquesRecuperados son los bloques indexados tras reconectar, que
+	// llegaron mientras no había conexión
+	BloquesRecuperados uint64     `json:"bloquesRecuperados"`
+	ConectadoDesde     *time.Time `json:"conectadoDesde,omitempty"`
+	UltimoError        string     `json:"ultimoError,omitempty"`
+	ProximoIntento     *time.Time `json:"proximoIntento,omitempty"`
+}
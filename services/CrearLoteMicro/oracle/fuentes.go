@@ -0,0 +1,107 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+)
+
+// Fuente entrega los mensajes con lecturas de un broker. Leer bloquea hasta
+// que se cancele el contexto o la conexión falle sin remedio.
+type Fuente interface {
+	Leer(ctx context.Context, entregar func(payload []byte)) error
+}
+
+// FuenteMQTT se suscribe al topic en el que publican los sensores (el mismo
+// que consume el mqtt-order-event-client)
+type FuenteMQTT struct {
+	Broker   string
+	Topic    string
+	ClientID string
+	Username string
+	Password string
+}
+
+// Leer se conecta al broker y entrega cada mensaje del topic. El cliente se
+// reconecta solo y vuelve a suscribirse al recuperar la conexión.
+func (f *FuenteMQTT) Leer(ctx context.Context, entregar func(payload []byte)) error {
+	handler := func(_ mqtt.Client, msg mqtt.Message) {
+		entregar(msg.Payload())
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(f.Broker).
+		SetClientID(f.ClientID).
+		SetUsername(f.Username).
+		SetPassword(f.Password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(5 * time.Second).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			log.Printf("Oráculo: conexión MQTT perdida: %v", err)
+		}).
+		SetOnConnectHandler(func(client mqtt.Client) {
+			token := client.Subscribe(f.Topic, 1, handler)
+			if token.Wait() && token.Error() != nil {
+				log.Printf("Oráculo: error suscribiendo a %s: %v", f.Topic, token.Error())
+				return
+			}
+			log.Printf("Oráculo suscrito a MQTT %s en %s", f.Topic, f.Broker)
+		})
+
+	client := mqtt.NewClient(opts)
+	if token := client.Connect(); token.Wait() && token.Error() != nil {
+		return fmt.Errorf("error conectando a MQTT %s: %v", f.Broker, token.Error())
+	}
+	defer client.Disconnect(250)
+
+	<-ctx.Done()
+	return nil
+}
+
+// FuenteKafka lee el topic de Kafka en el que escribe el puente MQTT
+// (order-status-events), con un grupo de consumidores para retomar la
+// lectura tras un reinicio
+type FuenteKafka struct {
+	Brokers  []string
+	Topic    string
+	GroupID  string
+	Username string
+	Password string
+	// SASL activa la autenticación SASL/PLAIN con Username y Password
+	SASL bool
+}
+
+// Leer entrega cada mensaje del topic y confirma su offset al grupo
+func (f *FuenteKafka) Leer(ctx context.Context, entregar func(payload []byte)) error {
+	dialer := &kafka.Dialer{Timeout: 10 * time.Second, DualStack: true}
+	if f.SASL {
+		dialer.SASLMechanism = plain.Mechanism{Username: f.Username, Password: f.Password}
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: f.Brokers,
+		Topic:   f.Topic,
+		GroupID: f.GroupID,
+		Dialer:  dialer,
+	})
+	defer reader.Close()
+	log.Printf("Oráculo leyendo Kafka %s (grupo %s)", f.Topic, f.GroupID)
+
+	for {
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) || ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("error leyendo Kafka %s: %v", f.Topic, err)
+		}
+		entregar(msg.Value)
+	}
+}
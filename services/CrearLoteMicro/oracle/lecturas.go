@@ -0,0 +1,102 @@
+package oracle
+
+import (
+	"CrearLoteMicro/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrLecturaInvalida se devuelve con un mensaje que no contiene una lectura
+var ErrLecturaInvalida = errors.New("lectura inválida")
+
+// mensajeLectura acepta los formatos de lectura que circulan por el sistema:
+// el evento del mqtt-event-generator (id, source, data), el OrderDamageEvent
+// que el puente publica en Kafka (eventId, sensorId, occurredAt, details) y
+// models.LecturaSensor. El contrato lo decide la asignación de sensores del
+// oráculo; un contractAddress en el mensaje debe coincidir con ella.
+type mensajeLectura struct {
+	ID              string          `json:"id"`
+	EventID         string          `json:"eventId"`
+	Source          string          `json:"source"`
+	SensorID        string          `json:"sensorId"`
+	ContractAddress string          `json:"contractAddress"`
+	Timestamp       json.RawMessage `json:"timestamp"`
+	OccurredAt      *time.Time      `json:"occurredAt"`
+	Temperatura     *float64        `json:"temperatura"`
+	Humedad         float64         `json:"humedad"`
+	Data            *datosSensor    `json:"data"`
+	Details         *datosSensor    `json:"details"`
+}
+
+type datosSensor struct {
+	Temperature *float64 `json:"temperature"`
+	Humidity    float64  `json:"humidity"`
+}
+
+// DecodificarLectura interpreta un mensaje JSON con una lectura. Sin
+// timestamp se usa la hora de recepción.
+func DecodificarLectura(payload []byte) (models.LecturaSensor, error) {
+	var mensaje mensajeLectura
+	if err := json.Unmarshal(payload, &mensaje); err != nil {
+		return models.LecturaSensor{}, fmt.Errorf("%w: %v", ErrLecturaInvalida, err)
+	}
+
+	lectura := models.LecturaSensor{
+		ID:              primero(mensaje.ID, mensaje.EventID),
+		SensorID:        primero(mensaje.SensorID, mensaje.Source),
+		ContractAddress: mensaje.ContractAddress,
+		Humedad:         mensaje.Humedad,
+	}
+	temperatura := mensaje.Temperatura
+	for _, datos := range []*datosSensor{mensaje.Data, mensaje.Details} {
+		if temperatura == nil && datos != nil && datos.Temperature != nil {
+			temperatura = datos.Temperature
+			lectura.Humedad = datos.Humidity
+		}
+	}
+	if lectura.ID == "" || lectura.SensorID == "" || temperatura == nil {
+		return models.LecturaSensor{}, fmt.Errorf("%w: se requieren id, sensor y temperatura", ErrLecturaInvalida)
+	}
+	lectura.Temperatura = *temperatura
+
+	timestamp, err := decodificarTimestamp(mensaje.Timestamp)
+	if err != nil {
+		return models.LecturaSensor{}, err
+	}
+	switch {
+	case !timestamp.IsZero():
+		lectura.Timestamp = timestamp.Unix()
+	case mensaje.OccurredAt != nil:
+		lectura.Timestamp = mensaje.OccurredAt.Unix()
+	default:
+		lectura.Timestamp = time.Now().Unix()
+	}
+	return lectura, nil
+}
+
+// decodificarTimestamp acepta segundos Unix o una fecha RFC 3339
+func decodificarTimestamp(raw json.RawMessage) (time.Time, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return time.Time{}, nil
+	}
+	var segundos int64
+	if err := json.Unmarshal(raw, &segundos); err == nil {
+		return time.Unix(segundos, 0), nil
+	}
+	var fecha time.Time
+	if err := json.Unmarshal(raw, &fecha); err != nil {
+		return time.Time{}, fmt.Errorf("%w: timestamp %s", ErrLecturaInvalida, raw)
+	}
+	return fecha, nil
+}
+
+func primero(valores ...string) string {
+	for _, valor := range valores {
+		if valor != "" {
+			return valor
+		}
+	}
+	return ""
+}
@@ -0,0 +1,116 @@
+package oracle
+
+import (
+	"CrearLoteMicro/models"
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// argumentosHoja es la codificación ABI de una lectura en su hoja:
+// abi.encode(address contrato, string id, string sensorId,
+// int256 temperaturaCentesimas, int256 humedadCentesimas, uint256 timestamp)
+var argumentosHoja = func() abi.Arguments {
+	tipo := func(nombre string) abi.Type {
+		t, err := abi.NewType(nombre, "", nil)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+	return abi.Arguments{
+		{Type: tipo("address")},
+		{Type: tipo("string")},
+		{Type: tipo("string")},
+		{Type: tipo("int256")},
+		{Type: tipo("int256")},
+		{Type: tipo("uint256")},
+	}
+}()
+
+// HojaLectura calcula la hoja Merkle de una lectura. Sigue el formato de
+// StandardMerkleTree de OpenZeppelin, keccak256(keccak256(abi.encode(...))),
+// para que la prueba también se pueda verificar con MerkleProof en Solidity.
+// Las temperaturas y humedades se redondean a centésimas.
+func HojaLectura(lectura models.LecturaSensor) (common.Hash, error) {
+	codificada, err := argumentosHoja.Pack(
+		common.HexToAddress(lectura.ContractAddress),
+		lectura.ID,
+		lectura.SensorID,
+		big.NewInt(int64(math.Round(lectura.Temperatura*100))),
+		big.NewInt(int64(math.Round(lectura.Humedad*100))),
+		new(big.Int).SetInt64(lectura.Timestamp),
+	)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("error codificando lectura: %v", err)
+	}
+	return crypto.Keccak256Hash(crypto.Keccak256(codificada)), nil
+}
+
+// ArbolMerkle es un árbol binario de hojas con pares ordenados: cada nodo es
+// keccak256 del par de hijos de menor a mayor, así que las pruebas no
+// necesitan indicar el lado de cada hermano. Un nodo sin pareja sube tal cual.
+type ArbolMerkle struct {
+	niveles [][]common.Hash
+}
+
+// NuevoArbolMerkle construye el árbol de las hojas en el orden dado
+func NuevoArbolMerkle(hojas []common.Hash) *ArbolMerkle {
+	arbol := &ArbolMerkle{niveles: [][]common.Hash{append([]common.Hash{}, hojas...)}}
+	for nivel := arbol.niveles[0]; len(nivel) > 1; {
+		siguiente := make([]common.Hash, 0, (len(nivel)+1)/2)
+		for i := 0; i < len(nivel); i += 2 {
+			if i+1 == len(nivel) {
+				siguiente = append(siguiente, nivel[i])
+				continue
+			}
+			siguiente = append(siguiente, hashPar(nivel[i], nivel[i+1]))
+		}
+		arbol.niveles = append(arbol.niveles, siguiente)
+		nivel = siguiente
+	}
+	return arbol
+}
+
+// Raiz devuelve la raíz del árbol; con una sola hoja es la propia hoja
+func (a *ArbolMerkle) Raiz() common.Hash {
+	ultimo := a.niveles[len(a.niveles)-1]
+	if len(ultimo) == 0 {
+		return common.Hash{}
+	}
+	return ultimo[0]
+}
+
+// Prueba devuelve los hermanos que llevan de la hoja i a la raíz
+func (a *ArbolMerkle) Prueba(i int) []common.Hash {
+	prueba := []common.Hash{}
+	for _, nivel := range a.niveles[:len(a.niveles)-1] {
+		hermano := i ^ 1
+		if hermano < len(nivel) {
+			prueba = append(prueba, nivel[hermano])
+		}
+		i /= 2
+	}
+	return prueba
+}
+
+// VerificarPrueba comprueba que la prueba lleva de la hoja a la raíz
+func VerificarPrueba(hoja common.Hash, prueba []common.Hash, raiz common.Hash) bool {
+	actual := hoja
+	for _, hermano := range prueba {
+		actual = hashPar(actual, hermano)
+	}
+	return actual == raiz
+}
+
+func hashPar(a, b common.Hash) common.Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256Hash(a[:], b[:])
+}
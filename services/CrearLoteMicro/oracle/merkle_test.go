@@ -0,0 +1,109 @@
+package oracle
+
+import (
+	"CrearLoteMicro/models"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestArbolMerkle_ProofsVerifyForEveryLeaf(t *testing.T) {
+	for _, total := range []int{1, 2, 3, 5, 8, 13} {
+		var hojas []common.Hash
+		for i := 0; i < total; i++ {
+			hojas = append(hojas, crypto.Keccak256Hash([]byte(fmt.Sprintf("lectura-%d", i))))
+		}
+		arbol := NuevoArbolMerkle(hojas)
+		if total == 1 && arbol.Raiz() != hojas[0] {
+			t.Errorf("Expected a single leaf to be the root, got %s", arbol.Raiz().Hex())
+		}
+
+		for i, hoja := range hojas {
+			prueba := arbol.Prueba(i)
+			if !VerificarPrueba(hoja, prueba, arbol.Raiz()) {
+				t.Errorf("%d leaves: expected proof of leaf %d to verify", total, i)
+			}
+			if total > 1 && VerificarPrueba(crypto.Keccak256Hash([]byte("otra")), prueba, arbol.Raiz()) {
+				t.Errorf("%d leaves: expected proof of leaf %d to reject another leaf", total, i)
+			}
+		}
+	}
+}
+
+func TestArbolMerkle_SortedPairsMatchOpenZeppelin(t *testing.T) {
+	a, b := common.HexToHash("0x01"), common.HexToHash("0x02")
+	// MerkleProof de OpenZeppelin: keccak256 del par ordenado
+	esperada := crypto.Keccak256Hash(a[:], b[:])
+	if raiz := NuevoArbolMerkle([]common.Hash{b, a}).Raiz(); raiz != esperada {
+		t.Errorf("Expected root %s, got %s", esperada.Hex(), raiz.Hex())
+	}
+}
+
+func TestHojaLectura_DependsOnEveryField(t *testing.T) {
+	base := models.LecturaSensor{
+		ID:              "evt_1",
+		SensorID:        "temperature_sensor_03",
+		ContractAddress: "0x00000000000000000000000000000000000000aa",
+		Temperatura:     4.25,
+		Humedad:         50,
+		Timestamp:       1700000000,
+	}
+	hoja, err := HojaLectura(base)
+	if err != nil {
+		t.Fatalf("Expected leaf, got %v", err)
+	}
+	if otra, _ := HojaLectura(base); otra != hoja {
+		t.Error("Expected the same reading to give the same leaf")
+	}
+
+	cambios := []func(l *models.LecturaSensor){
+		func(l *models.LecturaSensor) { l.ID = "evt_2" },
+		func(l *models.LecturaSensor) { l.SensorID = "temperature_sensor_04" },
+		func(l *models.LecturaSensor) { l.ContractAddress = "0x00000000000000000000000000000000000000bb" },
+		func(l *models.LecturaSensor) { l.Temperatura = 4.26 },
+		func(l *models.LecturaSensor) { l.Humedad = 51 },
+		func(l *models.LecturaSensor) { l.Timestamp++ },
+	}
+	for i, cambio := range cambios {
+		lectura := base
+		cambio(&lectura)
+		if otra, _ := HojaLectura(lectura); otra == hoja {
+			t.Errorf("Change %d: expected a different leaf", i)
+		}
+	}
+}
+
+func TestDecodificarLectura_AcceptsGeneratorAndBridgeFormats(t *testing.T) {
+	generador := `{"id":"evt_1","timestamp":"2026-10-19T10:00:00Z","type":"sensor_reading","source":"temperature_sensor_03","data":{"temperature":4.5,"humidity":48,"status":"active"}}`
+	lectura, err := DecodificarLectura([]byte(generador))
+	if err != nil {
+		t.Fatalf("Expected generator event to decode, got %v", err)
+	}
+	if lectura.ID != "evt_1" || lectura.SensorID != "temperature_sensor_03" || lectura.Temperatura != 4.5 || lectura.Humedad != 48 || lectura.Timestamp != 1792404000 {
+		t.Errorf("Unexpected generator reading %+v", lectura)
+	}
+
+	puente := `{"eventId":"evt_2","occurredAt":"2026-10-19T10:00:05Z","sensorId":"temperature_sensor_03","details":{"temperature":9.1,"humidity":40}}`
+	lectura, err = DecodificarLectura([]byte(puente))
+	if err != nil {
+		t.Fatalf("Expected bridge event to decode, got %v", err)
+	}
+	if lectura.ID != "evt_2" || lectura.Temperatura != 9.1 || lectura.Timestamp != 1792404005 {
+		t.Errorf("Unexpected bridge reading %+v", lectura)
+	}
+
+	propia := `{"id":"r-1","sensorId":"s-1","contractAddress":"0x00000000000000000000000000000000000000aa","temperatura":-1.5,"timestamp":1700000000}`
+	lectura, err = DecodificarLectura([]byte(propia))
+	if err != nil || lectura.Temperatura != -1.5 || lectura.Timestamp != 1700000000 || lectura.ContractAddress == "" {
+		t.Errorf("Unexpected native reading %+v, %v", lectura, err)
+	}
+
+	for _, invalida := range []string{`{`, `{"id":"x","source":"s"}`, `{"source":"s","data":{"temperature":1}}`} {
+		if _, err := DecodificarLectura([]byte(invalida)); !errors.Is(err, ErrLecturaInvalida) {
+			t.Errorf("Expected ErrLecturaInvalida for %s, got %v", invalida, err)
+		}
+	}
+}
@@ -0,0 +1,516 @@
+package oracle
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/services"
+	"CrearLoteMicro/signer"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrSensorSinLote se devuelve con una lectura de un sensor no asignado a
+// ningún contrato LoteTracing
+var ErrSensorSinLote = errors.New("el sensor no está asignado a ningún lote")
+
+// ErrSensorOtroLote se devuelve con una lectura cuyo contractAddress no es el
+// contrato asignado a su sensor
+var ErrSensorOtroLote = errors.New("el sensor está asignado a otro lote")
+
+// ErrLoteLecturasNotFound se devuelve cuando el oráculo no conoce el lote de lecturas
+var ErrLoteLecturasNotFound = errors.New("lote de lecturas no encontrado")
+
+// ErrLecturaNotFound se devuelve cuando el oráculo no recibió la lectura
+var ErrLecturaNotFound = errors.New("lectura no encontrada")
+
+// ErrLecturaSinRaiz se devuelve al pedir la prueba de una lectura cuyo lote
+// sigue abierto y aún no tiene raíz
+var ErrLecturaSinRaiz = errors.New("el lote de la lectura sigue abierto")
+
+// Anclador envía las raíces Merkle a los contratos LoteTracing. Lo implementa
+// services.BlockchainService.
+type Anclador interface {
+	AnclarLecturas(firmante signer.Signer, contractAddress string, anclaje services.AnclajeLecturas) (*models.TransaccionEnviada, error)
+	TimestampAnclaje(contractAddress string, raiz common.Hash) (uint64, error)
+}
+
+// Options configura el oráculo de sensores
+type Options struct {
+	// Ventana es el tiempo que un lote acepta lecturas desde la primera
+	Ventana time.Duration
+	// MaxLecturas cierra el lote antes de que termine la ventana
+	MaxLecturas int
+	// Sensores asigna cada sensor al contrato LoteTracing de su lote
+	Sensores map[string]string
+	// Store guarda los lotes y sus lecturas; nil los mantiene en memoria
+	Store Store
+}
+
+// Oraculo agrupa las lecturas de los sensores por contrato LoteTracing
+// durante una ventana y ancla en el contrato la raíz Merkle de cada lote con
+// su mínimo y su máximo. Las lecturas se quedan fuera de la cadena y el
+// oráculo genera la prueba Merkle de cualquiera de ellas.
+type Oraculo struct {
+	anclador Anclador
+	firmante signer.Signer
+	options  Options
+
+	// anclarMu serializa los envíos de anclarLecturas
+	anclarMu sync.Mutex
+
+	mu       sync.RWMutex
+	sensores map[string]string
+	lotes    []*models.LoteLecturas
+	// abiertos es el lote que acepta lecturas de cada contrato
+	abiertos map[string]*models.LoteLecturas
+	// hojas descarta las lecturas repetidas, por ejemplo reentregas MQTT
+	hojas      map[common.Hash]bool
+	porLectura map[string]*models.LoteLecturas
+	siguiente  uint64
+}
+
+// New crea el oráculo, que firma con la cuenta indicada, y carga los lotes guardados
+func New(anclador Anclador, firmante signer.Signer, options Options) (*Oraculo, error) {
+	if options.Ventana <= 0 {
+		options.Ventana = 5 * time.Minute
+	}
+	if options.MaxLecturas <= 0 {
+		options.MaxLecturas = 500
+	}
+
+	o := &Oraculo{
+		anclador:   anclador,
+		firmante:   firmante,
+		options:    options,
+		sensores:   make(map[string]string),
+		abiertos:   make(map[string]*models.LoteLecturas),
+		hojas:      make(map[common.Hash]bool),
+		porLectura: make(map[string]*models.LoteLecturas),
+		siguiente:  1,
+	}
+	for sensor, contractAddress := range options.Sensores {
+		if err := o.AsignarSensor(sensor, contractAddress); err != nil {
+			return nil, err
+		}
+	}
+
+	if options.Store != nil {
+		lotes, err := options.Store.Load()
+		if err != nil {
+			return nil, err
+		}
+		for i := range lotes {
+			lote := &lotes[i]
+			o.lotes = append(o.lotes, lote)
+			if lote.Estado == models.LoteLecturasAbierto {
+				o.abiertos[lote.ContractAddress] = lote
+			}
+			for _, lectura := range lote.Lecturas {
+				hoja, err := HojaLectura(lectura)
+				if err != nil {
+					return nil, err
+				}
+				o.hojas[hoja] = true
+				o.porLectura[lectura.ID] = lote
+			}
+			if lote.ID >= o.siguiente {
+				o.siguiente = lote.ID + 1
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// Cuenta devuelve la dirección con la que el oráculo firma los anclajes
+func (o *Oraculo) Cuenta() common.Address {
+	return o.firmante.Address()
+}
+
+// AsignarSensor relaciona un sensor con el contrato LoteTracing de su lote
+func (o *Oraculo) AsignarSensor(sensorID, contractAddress string) error {
+	if sensorID == "" || !common.IsHexAddress(contractAddress) {
+		return fmt.Errorf("%w: asignación %s=%s", ErrLecturaInvalida, sensorID, contractAddress)
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.sensores[sensorID] = common.HexToAddress(contractAddress).Hex()
+	return nil
+}
+
+// Sensores devuelve la asignación de sensores a contratos
+func (o *Oraculo) Sensores() map[string]string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	sensores := make(map[string]string, len(o.sensores))
+	for sensor, contractAddress := range o.sensores {
+		sensores[sensor] = contractAddress
+	}
+	return sensores
+}
+
+// Agregar añade una lectura al lote abierto del contrato asignado a su
+// sensor. La asignación manda: un contractAddress en la lectura solo se
+// acepta si coincide con ella. Devuelve false si la lectura ya se había
+// recibido.
+func (o *Oraculo) Agregar(lectura models.LecturaSensor) (bool, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	asignado := o.sensores[lectura.SensorID]
+	if asignado == "" {
+		return false, fmt.Errorf("%w: %s", ErrSensorSinLote, lectura.SensorID)
+	}
+	if lectura.ContractAddress != "" {
+		if !common.IsHexAddress(lectura.ContractAddress) {
+			return false, fmt.Errorf("%w: dirección de contrato %s", ErrLecturaInvalida, lectura.ContractAddress)
+		}
+		if common.HexToAddress(lectura.ContractAddress).Hex() != asignado {
+			return false, fmt.Errorf("%w: %s está asignado a %s, no a %s", ErrSensorOtroLote, lectura.SensorID, asignado, lectura.ContractAddress)
+		}
+	}
+	lectura.ContractAddress = asignado
+
+	hoja, err := HojaLectura(lectura)
+	if err != nil {
+		return false, err
+	}
+	if o.hojas[hoja] {
+		return false, nil
+	}
+
+	lote, ok := o.abiertos[lectura.ContractAddress]
+	if !ok {
+		lote = &models.LoteLecturas{
+			ID:              o.siguiente,
+			ContractAddress: lectura.ContractAddress,
+			Estado:          models.LoteLecturasAbierto,
+			AbiertoEn:       time.Now().Unix(),
+		}
+		o.siguiente++
+		o.lotes = append(o.lotes, lote)
+		o.abiertos[lote.ContractAddress] = lote
+	}
+	lote.Lecturas = append(lote.Lecturas, lectura)
+	lote.TotalLecturas = len(lote.Lecturas)
+	o.hojas[hoja] = true
+	o.porLectura[lectura.ID] = lote
+
+	if lote.TotalLecturas >= o.options.MaxLecturas {
+		if err := o.cerrarLocked(lote); err != nil {
+			return true, err
+		}
+	}
+	o.saveLocked()
+	return true, nil
+}
+
+// Run cierra los lotes cuya ventana terminó y ancla los pendientes hasta que
+// se cancele el contexto
+func (o *Oraculo) Run(ctx context.Context) {
+	ticker := time.NewTicker(o.options.Ventana / 5)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := o.cerrarVencidos(time.Now()); err != nil {
+			log.Printf("Error cerrando lotes de lecturas: %v", err)
+		}
+		if _, err := o.AnclarPendientes(); err != nil {
+			log.Printf("Error anclando lotes de lecturas: %v", err)
+		}
+	}
+}
+
+// Consumir entrega al oráculo las lecturas de una fuente hasta que se cancele
+// el contexto. Los mensajes que no se pueden agregar se registran y se descartan.
+func (o *Oraculo) Consumir(ctx context.Context, fuente Fuente) error {
+	return fuente.Leer(ctx, func(payload []byte) {
+		lectura, err := DecodificarLectura(payload)
+		if err == nil {
+			_, err = o.Agregar(lectura)
+		}
+		if err != nil {
+			log.Printf("Lectura descartada por el oráculo: %v", err)
+		}
+	})
+}
+
+// AnclarAhora cierra todos los lotes abiertos sin esperar a su ventana y
+// ancla los pendientes. Devuelve el número de lotes anclados.
+func (o *Oraculo) AnclarAhora() (int, error) {
+	if err := o.cerrarVencidos(time.Time{}); err != nil {
+		return 0, err
+	}
+	return o.AnclarPendientes()
+}
+
+// cerrarVencidos cierra los lotes abiertos antes de limite menos la ventana;
+// con limite cero cierra todos
+func (o *Oraculo) cerrarVencidos(limite time.Time) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	cerrados := false
+	for _, lote := range o.abiertos {
+		if !limite.IsZero() && limite.Sub(time.Unix(lote.AbiertoEn, 0)) < o.options.Ventana {
+			continue
+		}
+		if err := o.cerrarLocked(lote); err != nil {
+			return err
+		}
+		cerrados = true
+	}
+	if cerrados {
+		o.saveLocked()
+	}
+	return nil
+}
+
+// cerrarLocked calcula la raíz y el resumen del lote y lo deja pendiente de anclar
+func (o *Oraculo) cerrarLocked(lote *models.LoteLecturas) error {
+	arbol, err := arbolLote(lote)
+	if err != nil {
+		return err
+	}
+
+	minima, maxima := math.Inf(1), math.Inf(-1)
+	lote.Desde, lote.Hasta = lote.Lecturas[0].Timestamp, lote.Lecturas[0].Timestamp
+	for _, lectura := range lote.Lecturas {
+		minima = math.Min(minima, lectura.Temperatura)
+		maxima = math.Max(maxima, lectura.Temperatura)
+		if lectura.Timestamp < lote.Desde {
+			lote.Desde = lectura.Timestamp
+		}
+		if lectura.Timestamp > lote.Hasta {
+			lote.Hasta = lectura.Timestamp
+		}
+	}
+	// El contrato guarda grados enteros: el mínimo se redondea hacia abajo y
+	// el máximo hacia arriba para no ocultar una excursión
+	lote.TempMin = gradosInt8(math.Floor(minima))
+	lote.TempMax = gradosInt8(math.Ceil(maxima))
+	lote.RaizMerkle = arbol.Raiz().Hex()
+	lote.Estado = models.LoteLecturasPendiente
+	delete(o.abiertos, lote.ContractAddress)
+	return nil
+}
+
+// AnclarPendientes envía anclarLecturas para cada lote pendiente. Los que
+// fallan siguen pendientes y se reintentan en la siguiente pasada. Devuelve
+// el número de lotes anclados y el último error.
+func (o *Oraculo) AnclarPendientes() (int, error) {
+	o.anclarMu.Lock()
+	defer o.anclarMu.Unlock()
+
+	type envio struct {
+		lote    *models.LoteLecturas
+		anclaje services.AnclajeLecturas
+	}
+	o.mu.RLock()
+	var envios []envio
+	for _, lote := range o.lotes {
+		if lote.Estado != models.LoteLecturasPendiente {
+			continue
+		}
+		envios = append(envios, envio{lote: lote, anclaje: services.AnclajeLecturas{
+			RaizMerkle:    common.HexToHash(lote.RaizMerkle),
+			TempMin:       lote.TempMin,
+			TempMax:       lote.TempMax,
+			TotalLecturas: uint32(lote.TotalLecturas),
+			Desde:         uint64(lote.Desde),
+			Hasta:         uint64(lote.Hasta),
+		}})
+	}
+	o.mu.RUnlock()
+
+	anclados := 0
+	var ultimoErr error
+	for _, e := range envios {
+		transaccion, err := o.anclador.AnclarLecturas(o.firmante, e.lote.ContractAddress, e.anclaje)
+
+		o.mu.Lock()
+		if err != nil {
+			e.lote.Error = err.Error()
+			ultimoErr = fmt.Errorf("error anclando lote de lecturas %d en %s: %w", e.lote.ID, e.lote.ContractAddress, err)
+		} else {
+			e.lote.Estado = models.LoteLecturasAnclado
+			e.lote.TxHash = transaccion.TxHash
+			e.lote.Error = ""
+			anclados++
+			log.Printf("Lote de lecturas %d anclado en %s: raíz %s, %d lecturas, tx %s",
+				e.lote.ID, e.lote.ContractAddress, e.lote.RaizMerkle, e.lote.TotalLecturas, transaccion.TxHash)
+		}
+		o.saveLocked()
+		o.mu.Unlock()
+	}
+	return anclados, ultimoErr
+}
+
+// Lotes devuelve los lotes de lecturas, sin las lecturas, en orden de
+// apertura. Con contractAddress solo los de ese contrato.
+func (o *Oraculo) Lotes(contractAddress string) []models.LoteLecturas {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	lotes := []models.LoteLecturas{}
+	for _, lote := range o.lotes {
+		if contractAddress != "" && !strings.EqualFold(lote.ContractAddress, contractAddress) {
+			continue
+		}
+		resumen := *lote
+		resumen.Lecturas = nil
+		lotes = append(lotes, resumen)
+	}
+	return lotes
+}
+
+// Lote devuelve un lote de lecturas con sus lecturas
+func (o *Oraculo) Lote(id uint64) (*models.LoteLecturas, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	i := sort.Search(len(o.lotes), func(i int) bool { return o.lotes[i].ID >= id })
+	if i == len(o.lotes) || o.lotes[i].ID != id {
+		return nil, fmt.Errorf("%w: %d", ErrLoteLecturasNotFound, id)
+	}
+	lote := *o.lotes[i]
+	lote.Lecturas = append([]models.LecturaSensor{}, lote.Lecturas...)
+	return &lote, nil
+}
+
+// Prueba genera la prueba Merkle de una lectura contra la raíz de su lote
+func (o *Oraculo) Prueba(lecturaID string) (*models.PruebaLectura, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	lote, ok := o.porLectura[lecturaID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrLecturaNotFound, lecturaID)
+	}
+	if lote.Estado == models.LoteLecturasAbierto {
+		return nil, fmt.Errorf("%w: la prueba de %s estará disponible al cerrarse el lote %d", ErrLecturaSinRaiz, lecturaID, lote.ID)
+	}
+
+	arbol, err := arbolLote(lote)
+	if err != nil {
+		return nil, err
+	}
+	// Con un ID repetido en el lote se prueba la última lectura recibida
+	indice := 0
+	for i, lectura := range lote.Lecturas {
+		if lectura.ID == lecturaID {
+			indice = i
+		}
+	}
+
+	prueba := []string{}
+	for _, hermano := range arbol.Prueba(indice) {
+		prueba = append(prueba, hermano.Hex())
+	}
+	return &models.PruebaLectura{
+		Lectura:         lote.Lecturas[indice],
+		Hoja:            arbol.niveles[0][indice].Hex(),
+		Prueba:          prueba,
+		RaizMerkle:      lote.RaizMerkle,
+		ContractAddress: lote.ContractAddress,
+		LoteLecturas:    lote.ID,
+		Estado:          lote.Estado,
+		TxHash:          lote.TxHash,
+	}, nil
+}
+
+// Verificar comprueba que la prueba lleva de la lectura a la raíz y que la
+// raíz está anclada en el contrato de la lectura. No necesita que el oráculo
+// conozca la lectura: basta con los datos originales y la prueba.
+func (o *Oraculo) Verificar(req models.VerificarLecturaRequest) (*models.VerificacionLectura, error) {
+	if !common.IsHexAddress(req.Lectura.ContractAddress) {
+		return nil, fmt.Errorf("%w: dirección de contrato %s", ErrLecturaInvalida, req.Lectura.ContractAddress)
+	}
+	raiz, err := hashHex(req.RaizMerkle)
+	if err != nil {
+		return nil, err
+	}
+	var prueba []common.Hash
+	for _, hermano := range req.Prueba {
+		hash, err := hashHex(hermano)
+		if err != nil {
+			return nil, err
+		}
+		prueba = append(prueba, hash)
+	}
+
+	hoja, err := HojaLectura(req.Lectura)
+	if err != nil {
+		return nil, err
+	}
+	verificacion := &models.VerificacionLectura{
+		Hoja:       hoja.Hex(),
+		RaizMerkle: raiz.Hex(),
+		Incluida:   VerificarPrueba(hoja, prueba, raiz),
+	}
+	if verificacion.Incluida {
+		timestamp, err := o.anclador.TimestampAnclaje(req.Lectura.ContractAddress, raiz)
+		if err != nil {
+			return nil, err
+		}
+		verificacion.Anclada = timestamp != 0
+		verificacion.TimestampAnclaje = timestamp
+	}
+	verificacion.Valida = verificacion.Incluida && verificacion.Anclada
+	return verificacion, nil
+}
+
+func (o *Oraculo) saveLocked() {
+	if o.options.Store == nil {
+		return
+	}
+
+	lotes := make([]models.LoteLecturas, 0, len(o.lotes))
+	for _, lote := range o.lotes {
+		lotes = append(lotes, *lote)
+	}
+	if err := o.options.Store.Save(lotes); err != nil {
+		log.Printf("Error guardando lotes de lecturas: %v", err)
+	}
+}
+
+// arbolLote construye el árbol Merkle de las lecturas de un lote
+func arbolLote(lote *models.LoteLecturas) (*ArbolMerkle, error) {
+	hojas := make([]common.Hash, 0, len(lote.Lecturas))
+	for _, lectura := range lote.Lecturas {
+		hoja, err := HojaLectura(lectura)
+		if err != nil {
+			return nil, err
+		}
+		hojas = append(hojas, hoja)
+	}
+	return NuevoArbolMerkle(hojas), nil
+}
+
+// gradosInt8 limita una temperatura al rango int8 del contrato
+func gradosInt8(grados float64) int8 {
+	return int8(math.Max(math.MinInt8, math.Min(math.MaxInt8, grados)))
+}
+
+func hashHex(valor string) (common.Hash, error) {
+	bytes, err := hexutil.Decode(valor)
+	if err != nil || len(bytes) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("%w: hash %s", ErrLecturaInvalida, valor)
+	}
+	return common.BytesToHash(bytes), nil
+}
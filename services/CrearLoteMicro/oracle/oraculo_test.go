@@ -0,0 +1,207 @@
+package oracle
+
+import (
+	"CrearLoteMicro/bindings"
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/services"
+	"CrearLoteMicro/signer"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// newTestLote despliega un lote con rango 2..8 y otorga el rol oraculo
+func newTestLote(t *testing.T) (*services.BlockchainService, signer.Signer, string) {
+	t.Helper()
+	fabricante, _ := signer.NewDevSigner("fabricante")
+	oraculo, _ := signer.NewDevSigner("oraculo")
+
+	chain := services.NewSimulatedChain(services.SimulatedChainOptions{Accounts: []common.Address{fabricante.Address(), oraculo.Address()}})
+	t.Cleanup(func() { chain.Close() })
+	bs, err := services.NewBlockchainServiceWithClient(chain, 1337, services.NonceManagerOptions{}, services.TxTrackerOptions{}, services.EventIndexerOptions{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	contractAddress, _, err := bs.DeployContract(fabricante, "LOTE001", 2, 8)
+	if err != nil {
+		t.Fatalf("Failed to deploy contract: %v", err)
+	}
+	if _, err := bs.OtorgarRol(fabricante, contractAddress, services.RolOraculo, oraculo.Address().Hex()); err != nil {
+		t.Fatalf("Expected oracle role to be granted, got %v", err)
+	}
+	return bs, oraculo, contractAddress
+}
+
+func lecturaSensor(i int, temperatura float64) models.LecturaSensor {
+	return models.LecturaSensor{
+		ID:          fmt.Sprintf("evt_%d", i),
+		SensorID:    "temperature_sensor_03",
+		Temperatura: temperatura,
+		Humedad:     50,
+		Timestamp:   1700000000 + int64(i),
+	}
+}
+
+func TestOraculo_AnchorsBatchAndProvesReadings(t *testing.T) {
+	bs, firmante, contractAddress := newTestLote(t)
+	oraculo, err := New(bs, firmante, Options{
+		Ventana:  time.Hour,
+		Sensores: map[string]string{"temperature_sensor_03": contractAddress},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create oracle: %v", err)
+	}
+
+	temperaturas := []float64{4.2, 2.5, 7.9, 5.1, 3.3}
+	for i, temperatura := range temperaturas {
+		if nueva, err := oraculo.Agregar(lecturaSensor(i, temperatura)); err != nil || !nueva {
+			t.Fatalf("Expected reading %d to be added, got %t %v", i, nueva, err)
+		}
+	}
+	if nueva, _ := oraculo.Agregar(lecturaSensor(1, 2.5)); nueva {
+		t.Error("Expected a redelivered reading to be ignored")
+	}
+	if _, err := oraculo.Prueba("evt_0"); !errors.Is(err, ErrLecturaSinRaiz) {
+		t.Errorf("Expected ErrLecturaSinRaiz while the batch is open, got %v", err)
+	}
+
+	anclados, err := oraculo.AnclarAhora()
+	if err != nil || anclados != 1 {
+		t.Fatalf("Expected one batch anchored, got %d %v", anclados, err)
+	}
+	lotes := oraculo.Lotes(contractAddress)
+	if len(lotes) != 1 || lotes[0].Estado != models.LoteLecturasAnclado || lotes[0].TxHash == "" || lotes[0].TotalLecturas != 5 {
+		t.Fatalf("Expected an anchored batch of 5 readings, got %+v", lotes)
+	}
+	if lotes[0].TempMin != 2 || lotes[0].TempMax != 8 || lotes[0].Desde != 1700000000 || lotes[0].Hasta != 1700000004 {
+		t.Errorf("Expected range 2..8 over the reading timestamps, got %+v", lotes[0])
+	}
+
+	// El contrato guarda la raíz y el rango registrado del lote
+	caller, _ := bindings.NewLoteTracingCaller(common.HexToAddress(contractAddress), bs.Client)
+	callOpts := &bind.CallOpts{}
+	if timestamp, err := bs.TimestampAnclaje(contractAddress, common.HexToHash(lotes[0].RaizMerkle)); err != nil || timestamp == 0 {
+		t.Errorf("Expected the root to be anchored on-chain, got %d %v", timestamp, err)
+	}
+	if minima, _ := caller.TempRegMinima(callOpts); minima != 2 {
+		t.Errorf("Expected tempRegMinima 2, got %d", minima)
+	}
+	if comprometido, _ := caller.Comprometido(callOpts); comprometido {
+		t.Error("Expected the lote to stay in range")
+	}
+
+	for i := range temperaturas {
+		prueba, err := oraculo.Prueba(fmt.Sprintf("evt_%d", i))
+		if err != nil {
+			t.Fatalf("Expected proof for reading %d, got %v", i, err)
+		}
+		verificacion, err := oraculo.Verificar(models.VerificarLecturaRequest{
+			Lectura:    prueba.Lectura,
+			Prueba:     prueba.Prueba,
+			RaizMerkle: prueba.RaizMerkle,
+		})
+		if err != nil || !verificacion.Valida || verificacion.TimestampAnclaje == 0 {
+			t.Errorf("Expected reading %d to verify against the anchored root, got %+v %v", i, verificacion, err)
+		}
+	}
+
+	// Una lectura alterada no se verifica con la prueba original
+	prueba, _ := oraculo.Prueba("evt_2")
+	alterada := prueba.Lectura
+	alterada.Temperatura = 12
+	verificacion, err := oraculo.Verificar(models.VerificarLecturaRequest{Lectura: alterada, Prueba: prueba.Prueba, RaizMerkle: prueba.RaizMerkle})
+	if err != nil || verificacion.Incluida || verificacion.Valida {
+		t.Errorf("Expected a tampered reading to fail, got %+v %v", verificacion, err)
+	}
+}
+
+func TestOraculo_OutOfRangeBatchCompromisesLote(t *testing.T) {
+	bs, firmante, contractAddress := newTestLote(t)
+	oraculo, _ := New(bs, firmante, Options{Ventana: time.Hour, MaxLecturas: 2, Sensores: map[string]string{"temperature_sensor_03": contractAddress}})
+
+	lectura := lecturaSensor(0, 5)
+	lectura.ContractAddress = contractAddress
+	oraculo.Agregar(lectura)
+	oraculo.Agregar(lecturaSensor(1, 8.4))
+
+	// MaxLecturas cierra el lote sin esperar a la ventana
+	lotes := oraculo.Lotes("")
+	if len(lotes) != 1 || lotes[0].Estado != models.LoteLecturasPendiente || lotes[0].TempMax != 9 {
+		t.Fatalf("Expected a pending batch with max rounded up to 9, got %+v", lotes)
+	}
+	if anclados, err := oraculo.AnclarPendientes(); err != nil || anclados != 1 {
+		t.Fatalf("Expected the batch to be anchored, got %d %v", anclados, err)
+	}
+
+	caller, _ := bindings.NewLoteTracingCaller(common.HexToAddress(contractAddress), bs.Client)
+	if comprometido, _ := caller.Comprometido(&bind.CallOpts{}); !comprometido {
+		t.Error("Expected an out-of-range batch to compromise the lote")
+	}
+}
+
+func TestOraculo_KeepsBatchPendingWithoutRole(t *testing.T) {
+	bs, _, contractAddress := newTestLote(t)
+	sinRol, _ := signer.NewDevSigner("fabricante")
+	oraculo, _ := New(bs, sinRol, Options{Ventana: time.Hour, Sensores: map[string]string{"temperature_sensor_03": contractAddress}})
+
+	if _, err := oraculo.Agregar(models.LecturaSensor{ID: "x", SensorID: "sin_asignar", Temperatura: 1}); !errors.Is(err, ErrSensorSinLote) {
+		t.Errorf("Expected ErrSensorSinLote, got %v", err)
+	}
+	// El mensaje no puede llevar la lectura a otro contrato que el asignado
+	otroLote := lecturaSensor(0, 40)
+	otroLote.ContractAddress = sinRol.Address().Hex()
+	if _, err := oraculo.Agregar(otroLote); !errors.Is(err, ErrSensorOtroLote) {
+		t.Errorf("Expected ErrSensorOtroLote, got %v", err)
+	}
+	if lotes := oraculo.Lotes(""); len(lotes) != 0 {
+		t.Errorf("Expected the rejected reading to open no batch, got %+v", lotes)
+	}
+	oraculo.Agregar(lecturaSensor(0, 4))
+
+	if _, err := oraculo.AnclarAhora(); !errors.Is(err, services.ErrRolRequerido) {
+		t.Fatalf("Expected ErrRolRequerido, got %v", err)
+	}
+	lotes := oraculo.Lotes(contractAddress)
+	if len(lotes) != 1 || lotes[0].Estado != models.LoteLecturasPendiente || lotes[0].Error == "" {
+		t.Errorf("Expected the batch to stay pending with its error, got %+v", lotes)
+	}
+}
+
+func TestOraculo_ReloadsBatchesFromStore(t *testing.T) {
+	bs, firmante, contractAddress := newTestLote(t)
+	store := NewFileStore(filepath.Join(t.TempDir(), "oracle.json"))
+	options := Options{Ventana: time.Hour, Store: store, Sensores: map[string]string{"temperature_sensor_03": contractAddress}}
+
+	oraculo, _ := New(bs, firmante, options)
+	oraculo.Agregar(lecturaSensor(0, 4))
+	oraculo.Agregar(lecturaSensor(1, 5))
+	if _, err := oraculo.AnclarAhora(); err != nil {
+		t.Fatalf("Expected the batch to be anchored, got %v", err)
+	}
+	oraculo.Agregar(lecturaSensor(2, 6))
+
+	recargado, err := New(bs, firmante, options)
+	if err != nil {
+		t.Fatalf("Failed to reload oracle: %v", err)
+	}
+	prueba, err := recargado.Prueba("evt_1")
+	if err != nil || prueba.Estado != models.LoteLecturasAnclado || prueba.LoteLecturas != 1 {
+		t.Fatalf("Expected the anchored proof after reload, got %+v %v", prueba, err)
+	}
+	if nueva, _ := recargado.Agregar(lecturaSensor(0, 4)); nueva {
+		t.Error("Expected readings from the store to be recognised as duplicates")
+	}
+	if nueva, _ := recargado.Agregar(lecturaSensor(3, 7)); !nueva {
+		t.Error("Expected a new reading to be added after reload")
+	}
+	lote, err := recargado.Lote(2)
+	if err != nil || lote.Estado != models.LoteLecturasAbierto || lote.TotalLecturas != 2 {
+		t.Errorf("Expected the open batch to keep accepting readings, got %+v %v", lote, err)
+	}
+}
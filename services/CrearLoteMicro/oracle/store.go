@@ -0,0 +1,49 @@
+package oracle
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Store guarda los lotes de lecturas entre reinicios. Las lecturas solo
+// existen fuera de la cadena, así que sin ellas no se pueden generar pruebas.
+type Store interface {
+	Load() ([]models.LoteLecturas, error)
+	Save(lotes []models.LoteLecturas) error
+}
+
+// FileStore guarda los lotes de lecturas en un archivo JSON
+type FileStore struct {
+	path string
+}
+
+// NewFileStore crea un almacén en el archivo indicado
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Load lee los lotes guardados; un archivo inexistente equivale a ningún lote
+func (s *FileStore) Load() ([]models.LoteLecturas, error) {
+	content, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo %s: %v", s.path, err)
+	}
+
+	var lotes []models.LoteLecturas
+	if err := json.Unmarshal(content, &lotes); err != nil {
+		return nil, fmt.Errorf("error parseando %s: %v", s.path, err)
+	}
+	return lotes, nil
+}
+
+// Save reemplaza el archivo de forma atómica
+func (s *FileStore) Save(lotes []models.LoteLecturas) error {
+	return utils.GuardarJSONAtomico(s.path, lotes)
+}
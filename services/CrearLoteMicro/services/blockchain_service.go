@@ -1,16 +1,18 @@
 package services
 
 import (
-	"CrearLoteMicro/assets/contracts"
+	"CrearLoteMicro/bindings"
 	"CrearLoteMicro/models"
+	"CrearLoteMicro/signer"
 	"context"
-	"crypto/ecdsa"
+	"errors"
 	"fmt"
+	"log"
 	"math/big"
-	"strings"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -18,101 +20,138 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
+// ErrChainIDMismatch se devuelve cuando el nodo no pertenece a la red configurada
+var ErrChainIDMismatch = errors.New("el chain ID no coincide")
+
 type BlockchainService struct {
-	Client  *ethclient.Client
+	Client  ChainClient
 	chainID *big.Int
+	nonces  *NonceManager
+	tracker *TxTracker
+	indexer *EventIndexer
+	lotes   *LoteRegistry
+
+	// factory es la LoteTracingFactory de la red; vacía si no hay ninguna
+	factoryMu sync.RWMutex
+	factory   common.Address
 }
 
-// Funciones para obtener ABI y Bytecode desde assets
-func getContractABI() string {
-	return contracts.GetLoteTracingABI()
-}
-
-func getContractBytecode() string {
-	return contracts.GetLoteTracingBytecode()
-}
-
-func NewBlockchainService(rpcURL string, chainID int64) (*BlockchainService, error) {
+func NewBlockchainService(rpcURL string, chainID int64, txOptions NonceManagerOptions, trackerOptions TxTrackerOptions, indexerOptions EventIndexerOptions, loteStore LoteStore) (*BlockchainService, error) {
 	client, err := ethclient.Dial(rpcURL)
 	if err != nil {
 		return nil, fmt.Errorf("error conectando a la blockchain: %v", err)
 	}
 
-	return &BlockchainService{
-		Client:  client,
-		chainID: big.NewInt(chainID),
-	}, nil
+	return NewBlockchainServiceWithClient(client, chainID, txOptions, trackerOptions, indexerOptions, loteStore)
 }
 
-func (bs *BlockchainService) DeployContract(privateKeyHex, loteID string, tempMin, tempMax int8) (string, string, error) {
-	// Parsear la clave privada
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+// NewBlockchainServiceWithClient crea el servicio sobre un cliente ya conectado,
+// por ejemplo una SimulatedChain. Falla si el nodo no está en la red chainID.
+func NewBlockchainServiceWithClient(client ChainClient, chainID int64, txOptions NonceManagerOptions, trackerOptions TxTrackerOptions, indexerOptions EventIndexerOptions, loteStore LoteStore) (*BlockchainService, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	remoteChainID, err := client.ChainID(ctx)
 	if err != nil {
-		return "", "", fmt.Errorf("error parseando clave privada: %v", err)
+		return nil, fmt.Errorf("error obteniendo eth_chainId: %v", err)
 	}
-
-	// Obtener la dirección pública
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return "", "", fmt.Errorf("error obteniendo clave pública")
+	if remoteChainID.Cmp(big.NewInt(chainID)) != 0 {
+		return nil, fmt.Errorf("%w: configurado %d, el nodo devuelve %s", ErrChainIDMismatch, chainID, remoteChainID)
 	}
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
 
-	// Obtener nonce
-	nonce, err := bs.Client.PendingNonceAt(context.Background(), fromAddress)
+	lotes, err := NewLoteRegistry(loteStore)
 	if err != nil {
-		return "", "", fmt.Errorf("error obteniendo nonce: %v", err)
+		return nil, err
 	}
 
-	// Configurar gas
-	gasPrice, err := bs.Client.SuggestGasPrice(context.Background())
+	// Un despliegue revertido o cancelado se retira del registro
+	trackerOptions.OnFinal = lotes.ResolverDespliegue
+	tracker, err := NewTxTracker(client, trackerOptions)
 	if err != nil {
-		return "", "", fmt.Errorf("error obteniendo gas price: %v", err)
+		return nil, err
 	}
+	go tracker.Run(context.Background())
 
-	// Parsear ABI
-	parsedABI, err := abi.JSON(strings.NewReader(getContractABI()))
+	indexerOptions.OnUpdate = lotes.Actualizar
+	indexer, err := NewEventIndexer(client, indexerOptions)
 	if err != nil {
-		return "", "", fmt.Errorf("error parseando ABI: %v", err)
+		return nil, err
 	}
-
-	// Crear transactor
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, bs.chainID)
-	if err != nil {
-		return "", "", fmt.Errorf("error creando transactor: %v", err)
+	// El registro se completa con el historial ya indexado y el indexador
+	// sigue los contratos que el registro conoce
+	lotes.Completar(indexer.Contratos())
+	for contractAddress, desde := range lotes.Contratos() {
+		indexer.Registrar(common.HexToAddress(contractAddress), desde)
 	}
+	go indexer.Run(context.Background())
+
+	return &BlockchainService{
+		Client:  client,
+		chainID: big.NewInt(chainID),
+		nonces:  NewNonceManager(client, big.NewInt(chainID), txOptions),
+		tracker: tracker,
+		indexer: indexer,
+		lotes:   lotes,
+	}, nil
+}
 
-	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)
-	auth.GasLimit = uint64(3000000)
-	auth.GasPrice = gasPrice
+// ChainID devuelve el chain ID con el que se firman las transacciones
+func (bs *BlockchainService) ChainID() *big.Int {
+	return new(big.Int).Set(bs.chainID)
+}
 
-	// Preparar datos del constructor
-	input, err := parsedABI.Pack("", loteID, tempMin, tempMax)
+// enviar envía la transacción en la cola de su cuenta y empieza a seguir su recibo
+func (bs *BlockchainService) enviar(req TxRequest) (*SentTx, error) {
+	sent, err := bs.nonces.Submit(context.Background(), req)
 	if err != nil {
-		return "", "", fmt.Errorf("error empaquetando datos del constructor: %v", err)
+		return nil, err
 	}
+	bs.tracker.Track(sent)
+	return sent, nil
+}
 
-	// Crear transacción de deploy
-	data := append(common.FromHex(getContractBytecode()), input...)
-	
-	tx := types.NewContractCreation(nonce, big.NewInt(0), 3000000, gasPrice, data)
-	
-	// Firmar transacción
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(bs.chainID), privateKey)
+func (bs *BlockchainService) DeployContract(firmante signer.Signer, loteID string, tempMin, tempMax int8) (string, *models.TransaccionEnviada, error) {
+	// Dirección de la cuenta que firma
+	fromAddress := firmante.Address()
+
+	// Bytecode del contrato seguido de los argumentos del constructor
+	data, err := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		_, tx, _, err := bindings.DeployLoteTracing(opts, nil, loteID, tempMin, tempMax)
+		return tx, err
+	})
 	if err != nil {
-		return "", "", fmt.Errorf("error firmando transacción: %v", err)
+		return "", nil, err
 	}
 
-	// Enviar transacción
-	err = bs.Client.SendTransaction(context.Background(), signedTx)
+	// Bloque desde el que el indexador sigue el contrato nuevo; si falla, el
+	// indexador lo descubre en la primera consulta de su cadena
+	desde, errBloque := bs.Client.BlockNumber(context.Background())
+
+	// Firmar y enviar en la cola de la cuenta, que asigna el nonce
+	sent, err := bs.enviar(TxRequest{
+		Signer:    firmante,
+		Operation: OpDeploy,
+		Data:      data,
+	})
 	if err != nil {
-		return "", "", fmt.Errorf("error enviando transacción: %v", err)
+		return "", nil, err
 	}
+	signedTx := sent.Tx
+	nonce := signedTx.Nonce()
 
 	// Calcular dirección del contrato
 	contractAddress := crypto.CreateAddress(fromAddress, nonce)
+	if errBloque == nil {
+		bs.indexer.Registrar(contractAddress, desde)
+	}
+	bs.lotes.RegistrarDespliegue(models.RegistroLote{
+		LoteID:            loteID,
+		ContractAddress:   contractAddress.Hex(),
+		Fabricante:        fromAddress.Hex(),
+		PropietarioActual: fromAddress.Hex(),
+		TemperaturaMinima: tempMin,
+		TemperaturaMaxima: tempMax,
+		TxHash:            signedTx.Hash().Hex(),
+	})
 	
 	fmt.Printf("[DEBUG] Deploy completado:\n")
 	fmt.Printf("[DEBUG] - From Address: %s\n", fromAddress.Hex())
@@ -120,201 +159,138 @@ func (bs *BlockchainService) DeployContract(privateKeyHex, loteID string, tempMi
 	fmt.Printf("[DEBUG] - Contract Address calculada: %s\n", contractAddress.Hex())
 	fmt.Printf("[DEBUG] - Transaction Hash: %s\n", signedTx.Hash().Hex())
 	fmt.Printf("[DEBUG] - Gas Limit: %d\n", signedTx.Gas())
-	fmt.Printf("[DEBUG] - Gas Price: %s\n", signedTx.GasPrice().String())
+	fmt.Printf("[DEBUG] - Max Fee Per Gas: %s\n", signedTx.GasFeeCap().String())
 
-	return contractAddress.Hex(), signedTx.Hash().Hex(), nil
+	return contractAddress.Hex(), sent.Info, nil
 }
 
-func (bs *BlockchainService) RegistrarTemperatura(privateKeyHex, contractAddress string, tempMin, tempMax int8) (string, error) {
-	// Parsear la clave privada
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
-	if err != nil {
-		return "", fmt.Errorf("error parseando clave privada: %v", err)
-	}
-
-	// Obtener la dirección pública
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return "", fmt.Errorf("error obteniendo clave pública")
-	}
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-
-	// Obtener nonce
-	nonce, err := bs.Client.PendingNonceAt(context.Background(), fromAddress)
-	if err != nil {
-		return "", fmt.Errorf("error obteniendo nonce: %v", err)
+func (bs *BlockchainService) RegistrarTemperatura(firmante signer.Signer, contractAddress string, tempMin, tempMax int8, sensorID string) (*models.TransaccionEnviada, error) {
+	toAddress := common.HexToAddress(contractAddress)
+	// Solo los oráculos de sensores registran lecturas
+	if err := bs.verificarRol(toAddress, firmante.Address(), RolOraculo); err != nil {
+		return nil, err
 	}
-
-	// Configurar gas
-	gasPrice, err := bs.Client.SuggestGasPrice(context.Background())
+	contract, err := loteTracingTransactor(toAddress)
 	if err != nil {
-		return "", fmt.Errorf("error obteniendo gas price: %v", err)
+		return nil, err
 	}
-
-	// Parsear ABI
-	parsedABI, err := abi.JSON(strings.NewReader(getContractABI()))
+	code, err := bs.Client.CodeAt(context.Background(), toAddress, nil)
 	if err != nil {
-		return "", fmt.Errorf("error parseando ABI: %v", err)
+		return nil, fmt.Errorf("error verificando contrato: %v", err)
 	}
 
-	// Preparar datos de la función
-	data, err := parsedABI.Pack("registrarTemperatura", tempMin, tempMax)
-	if err != nil {
-		return "", fmt.Errorf("error empaquetando datos de la función: %v", err)
+	// Preparar datos de la función. Los contratos anteriores al evento
+	// TemperaturaRegistrada no reciben el sensor.
+	var data []byte
+	if len(code) > 0 && !registraSensor(code) {
+		log.Printf("El contrato %s no registra sensores: la lectura de %s se registra sin él", toAddress.Hex(), sensorID)
+		data, err = calldataTemperaturaSinSensor(tempMin, tempMax)
+	} else {
+		data, err = calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return contract.RegistrarTemperatura(opts, tempMin, tempMax, sensorID)
+		})
 	}
-
-	// Crear transacción
-	toAddress := common.HexToAddress(contractAddress)
-	tx := types.NewTransaction(nonce, toAddress, big.NewInt(0), 300000, gasPrice, data)
-
-	// Firmar transacción
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(bs.chainID), privateKey)
 	if err != nil {
-		return "", fmt.Errorf("error firmando transacción: %v", err)
+		return nil, err
 	}
 
-	// Enviar transacción
-	err = bs.Client.SendTransaction(context.Background(), signedTx)
+	// Firmar y enviar en la cola de la cuenta, que asigna el nonce
+	sent, err := bs.enviar(TxRequest{
+		Signer:    firmante,
+		Operation: OpRegistrarTemperatura,
+		To:        &toAddress,
+		Data:      data,
+	})
 	if err != nil {
-		return "", fmt.Errorf("error enviando transacción: %v", err)
+		return nil, err
 	}
 
-	return signedTx.Hash().Hex(), nil
+	return sent.Info, nil
 }
 
-func (bs *BlockchainService) TransferirCustodia(privateKeyHex, contractAddress, nuevoPropietario string) (string, error) {
-	// Parsear la clave privada
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
-	if err != nil {
-		return "", fmt.Errorf("error parseando clave privada: %v", err)
-	}
-
-	// Obtener la dirección pública
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return "", fmt.Errorf("error obteniendo clave pública")
-	}
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-
-	// Obtener nonce
-	nonce, err := bs.Client.PendingNonceAt(context.Background(), fromAddress)
-	if err != nil {
-		return "", fmt.Errorf("error obteniendo nonce: %v", err)
+func (bs *BlockchainService) CrearNuevoLote(firmante signer.Signer, contractAddress, loteID string, tempMin, tempMax int8) (*models.TransaccionEnviada, error) {
+	toAddress := common.HexToAddress(contractAddress)
+	// Solo los fabricantes reinician el contrato para otro lote
+	if err := bs.verificarRol(toAddress, firmante.Address(), RolFabricante); err != nil {
+		return nil, err
 	}
-
-	// Configurar gas
-	gasPrice, err := bs.Client.SuggestGasPrice(context.Background())
-	if err != nil {
-		return "", fmt.Errorf("error obteniendo gas price: %v", err)
+	if err := bs.verificarReinicio(toAddress, firmante.Address()); err != nil {
+		return nil, err
 	}
-
-	// Parsear ABI
-	parsedABI, err := abi.JSON(strings.NewReader(getContractABI()))
+	contract, err := loteTracingTransactor(toAddress)
 	if err != nil {
-		return "", fmt.Errorf("error parseando ABI: %v", err)
+		return nil, err
 	}
 
-	// Preparar datos de la función
-	nuevoPropietarioAddr := common.HexToAddress(nuevoPropietario)
-	data, err := parsedABI.Pack("transferirCustodia", nuevoPropietarioAddr)
+	// Preparar datos de la función crearNuevoLote
+	data, err := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.CrearNuevoLote(opts, loteID, tempMin, tempMax)
+	})
 	if err != nil {
-		return "", fmt.Errorf("error empaquetando datos de la función: %v", err)
+		return nil, err
 	}
 
-	// Crear transacción
-	toAddress := common.HexToAddress(contractAddress)
-	tx := types.NewTransaction(nonce, toAddress, big.NewInt(0), 300000, gasPrice, data)
-
-	// Firmar transacción
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(bs.chainID), privateKey)
+	// Firmar y enviar en la cola de la cuenta, que asigna el nonce
+	sent, err := bs.enviar(TxRequest{
+		Signer:    firmante,
+		Operation: OpCrearNuevoLote,
+		To:        &toAddress,
+		Data:      data,
+	})
 	if err != nil {
-		return "", fmt.Errorf("error firmando transacción: %v", err)
+		return nil, err
 	}
 
-	// Enviar transacción
-	err = bs.Client.SendTransaction(context.Background(), signedTx)
-	if err != nil {
-		return "", fmt.Errorf("error enviando transacción: %v", err)
-	}
+	fmt.Printf("[DEBUG] CrearNuevoLote completado:\n")
+	fmt.Printf("[DEBUG] - Contract Address: %s\n", contractAddress)
+	fmt.Printf("[DEBUG] - Lote ID: %s\n", loteID)
+	fmt.Printf("[DEBUG] - Transaction Hash: %s\n", sent.Tx.Hash().Hex())
 
-	return signedTx.Hash().Hex(), nil
+	return sent.Info, nil
 }
 
-func (bs *BlockchainService) CrearNuevoLote(privateKeyHex, contractAddress, loteID string, tempMin, tempMax int8) (string, error) {
-	// Parsear la clave privada
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
-	if err != nil {
-		return "", fmt.Errorf("error parseando clave privada: %v", err)
-	}
-
-	// Obtener la dirección pública
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return "", fmt.Errorf("error obteniendo clave pública")
-	}
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-
-	// Obtener nonce
-	nonce, err := bs.Client.PendingNonceAt(context.Background(), fromAddress)
-	if err != nil {
-		return "", fmt.Errorf("error obteniendo nonce: %v", err)
-	}
-
-	// Configurar gas
-	gasPrice, err := bs.Client.SuggestGasPrice(context.Background())
-	if err != nil {
-		return "", fmt.Errorf("error obteniendo gas price: %v", err)
-	}
-
-	// Parsear ABI
-	parsedABI, err := abi.JSON(strings.NewReader(getContractABI()))
+// AcelerarTransaccion reemplaza una transacción pendiente de la cuenta por otra
+// idéntica con mayor precio de gas
+func (bs *BlockchainService) AcelerarTransaccion(firmante signer.Signer, nonce uint64) (*models.TransaccionEnviada, error) {
+	sent, err := bs.nonces.SpeedUp(context.Background(), firmante, nonce)
 	if err != nil {
-		return "", fmt.Errorf("error parseando ABI: %v", err)
+		return nil, err
 	}
+	bs.tracker.Track(sent)
+	return sent.Info, nil
+}
 
-	// Preparar datos de la función crearNuevoLote
-	data, err := parsedABI.Pack("crearNuevoLote", loteID, tempMin, tempMax)
-	if err != nil {
-		return "", fmt.Errorf("error empaquetando datos de la función: %v", err)
-	}
-
-	// Crear transacción
-	toAddress := common.HexToAddress(contractAddress)
-	tx := types.NewTransaction(nonce, toAddress, big.NewInt(0), 300000, gasPrice, data)
-
-	// Firmar transacción
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(bs.chainID), privateKey)
+// CancelarTransaccion reemplaza una transacción pendiente de la cuenta por una
+// transferencia vacía a sí misma con mayor precio de gas
+func (bs *BlockchainService) CancelarTransaccion(firmante signer.Signer, nonce uint64) (*models.TransaccionEnviada, error) {
+	sent, err := bs.nonces.Cancel(context.Background(), firmante, nonce)
 	if err != nil {
-		return "", fmt.Errorf("error firmando transacción: %v", err)
+		return nil, err
 	}
+	bs.tracker.Track(sent)
+	return sent.Info, nil
+}
 
-	// Enviar transacción
-	err = bs.Client.SendTransaction(context.Background(), signedTx)
-	if err != nil {
-		return "", fmt.Errorf("error enviando transacción: %v", err)
-	}
+// EstadoTransaccion devuelve el estado de confirmación de una transacción enviada por el servicio
+func (bs *BlockchainService) EstadoTransaccion(txHash string) (*models.EstadoTransaccion, error) {
+	return bs.tracker.Estado(common.HexToHash(txHash))
+}
 
-	fmt.Printf("[DEBUG] CrearNuevoLote completado:\n")
-	fmt.Printf("[DEBUG] - Contract Address: %s\n", contractAddress)
-	fmt.Printf("[DEBUG] - Lote ID: %s\n", loteID)
-	fmt.Printf("[DEBUG] - Transaction Hash: %s\n", signedTx.Hash().Hex())
+// EsperarTransaccion espera, como mucho el tiempo configurado, a que la
+// transacción alcance las confirmaciones requeridas
+func (bs *BlockchainService) EsperarTransaccion(ctx context.Context, txHash string) (*models.EstadoTransaccion, error) {
+	ctx, cancel := context.WithTimeout(ctx, bs.tracker.options.WaitTimeout)
+	defer cancel()
+	return bs.tracker.Esperar(ctx, common.HexToHash(txHash))
+}
 
-	return signedTx.Hash().Hex(), nil
+// TransaccionesPendientes lista las transacciones enviadas por la cuenta aún sin confirmar
+func (bs *BlockchainService) TransaccionesPendientes(address common.Address) []models.TransaccionPendiente {
+	return bs.nonces.Pendientes(address)
 }
 
 func (bs *BlockchainService) ObtenerInfoLote(contractAddress string) (*models.LoteInfoResponse, error) {
 	fmt.Printf("[DEBUG] Iniciando ObtenerInfoLote para dirección: %s\n", contractAddress)
-	
-	// Parsear ABI
-	parsedABI, err := abi.JSON(strings.NewReader(getContractABI()))
-	if err != nil {
-		fmt.Printf("[ERROR] Error parseando ABI: %v\n", err)
-		return nil, fmt.Errorf("error parseando ABI: %v", err)
-	}
-	fmt.Printf("[DEBUG] ABI parseado exitosamente\n")
 
 	// Dirección del contrato
 	contractAddr := common.HexToAddress(contractAddress)
@@ -327,99 +303,63 @@ func (bs *BlockchainService) ObtenerInfoLote(contractAddress string) (*models.Lo
 		return nil, fmt.Errorf("error verificando contrato: %v", err)
 	}
 	fmt.Printf("[DEBUG] Código del contrato obtenido, longitud: %d bytes\n", len(code))
-	
+
 	if len(code) == 0 {
 		fmt.Printf("[ERROR] No se encontró código en la dirección del contrato\n")
 		return nil, fmt.Errorf("no se encontró contrato en la dirección especificada")
 	}
 
-	// Obtener el número de bloque actual para contexto
-	currentBlock, err := bs.Client.BlockNumber(context.Background())
+	// Crear binding del contrato para llamadas de solo lectura
+	contract, err := bindings.NewLoteTracingCaller(contractAddr, bs.Client)
 	if err != nil {
-		fmt.Printf("[WARNING] No se pudo obtener el bloque actual: %v\n", err)
-	} else {
-		fmt.Printf("[DEBUG] Bloque actual: %d\n", currentBlock)
+		return nil, fmt.Errorf("error creando binding del contrato: %v", err)
 	}
-
-	// Crear instancia del contrato para llamadas de solo lectura
-	contract := bind.NewBoundContract(contractAddr, parsedABI, bs.Client, bs.Client, bs.Client)
-	fmt.Printf("[DEBUG] Instancia del contrato creada\n")
-
-	// Realizar llamadas a las funciones públicas del contrato
 	callOpts := &bind.CallOpts{Context: context.Background()}
-	fmt.Printf("[DEBUG] CallOpts configurado, iniciando llamadas a funciones\n")
 
-	// Obtener loteId
-	fmt.Printf("[DEBUG] Intentando obtener loteId...\n")
-	var loteIdResult []interface{}
-	err = contract.Call(callOpts, &loteIdResult, "loteId")
+	// Realizar llamadas a las funciones públicas del contrato
+	loteId, err := contract.LoteId(callOpts)
 	if err != nil {
 		fmt.Printf("[ERROR] Error obteniendo loteId: %v\n", err)
 		return nil, fmt.Errorf("error obteniendo loteId: %v", err)
 	}
-	fmt.Printf("[DEBUG] loteIdResult obtenido: %+v\n", loteIdResult)
-	loteId := loteIdResult[0].(string)
-	fmt.Printf("[DEBUG] loteId extraído: %s\n", loteId)
+	fmt.Printf("[DEBUG] loteId obtenido: %s\n", loteId)
 
-	// Obtener fabricante
-	fmt.Printf("[DEBUG] Intentando obtener fabricante...\n")
-	var fabricanteResult []interface{}
-	err = contract.Call(callOpts, &fabricanteResult, "fabricante")
+	fabricante, err := contract.Fabricante(callOpts)
 	if err != nil {
 		fmt.Printf("[ERROR] Error obteniendo fabricante: %v\n", err)
 		return nil, fmt.Errorf("error obteniendo fabricante: %v", err)
 	}
-	fmt.Printf("[DEBUG] fabricanteResult obtenido: %+v\n", fabricanteResult)
-	fabricante := fabricanteResult[0].(common.Address)
-	fmt.Printf("[DEBUG] fabricante extraído: %s\n", fabricante.Hex())
+	fmt.Printf("[DEBUG] fabricante obtenido: %s\n", fabricante.Hex())
 
-	// Obtener propietario actual
-	var propietarioResult []interface{}
-	err = contract.Call(callOpts, &propietarioResult, "propietarioActual")
+	propietarioActual, err := contract.PropietarioActual(callOpts)
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo propietario actual: %v", err)
 	}
-	propietarioActual := propietarioResult[0].(common.Address)
 
-	// Obtener temperatura mínima
-	var tempMinResult []interface{}
-	err = contract.Call(callOpts, &tempMinResult, "temperaturaMinima")
+	temperaturaMinima, err := contract.TemperaturaMinima(callOpts)
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo temperatura mínima: %v", err)
 	}
-	temperaturaMinima := tempMinResult[0].(int8)
 
-	// Obtener temperatura máxima
-	var tempMaxResult []interface{}
-	err = contract.Call(callOpts, &tempMaxResult, "temperaturaMaxima")
+	temperaturaMaxima, err := contract.TemperaturaMaxima(callOpts)
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo temperatura máxima: %v", err)
 	}
-	temperaturaMaxima := tempMaxResult[0].(int8)
 
-	// Obtener estado comprometido
-	var comprometidoResult []interface{}
-	err = contract.Call(callOpts, &comprometidoResult, "comprometido")
+	comprometido, err := contract.Comprometido(callOpts)
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo estado comprometido: %v", err)
 	}
-	comprometido := comprometidoResult[0].(bool)
 
-	// Obtener temperatura registrada mínima
-	var tempRegMinResult []interface{}
-	err = contract.Call(callOpts, &tempRegMinResult, "tempRegMinima")
+	tempRegMinima, err := contract.TempRegMinima(callOpts)
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo temperatura registrada mínima: %v", err)
 	}
-	tempRegMinima := tempRegMinResult[0].(int8)
 
-	// Obtener temperatura registrada máxima
-	var tempRegMaxResult []interface{}
-	err = contract.Call(callOpts, &tempRegMaxResult, "tempRegMaxima")
+	tempRegMaxima, err := contract.TempRegMaxima(callOpts)
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo temperatura registrada máxima: %v", err)
 	}
-	tempRegMaxima := tempRegMaxResult[0].(int8)
 
 	// Crear respuesta
 	response := &models.LoteInfoResponse{
@@ -437,13 +377,9 @@ func (bs *BlockchainService) ObtenerInfoLote(contractAddress string) (*models.Lo
 	return response, nil
 }
 
+// ObtenerCadenaBlockchain devuelve el historial de eventos del contrato desde
+// el índice de eventos
 func (bs *BlockchainService) ObtenerCadenaBlockchain(contractAddress string) (*models.CadenaBlockchainResponse, error) {
-	// Parsear ABI
-	parsedABI, err := abi.JSON(strings.NewReader(getContractABI()))
-	if err != nil {
-		return nil, fmt.Errorf("error parseando ABI: %v", err)
-	}
-
 	// Dirección del contrato
 	contractAddr := common.HexToAddress(contractAddress)
 
@@ -453,200 +389,56 @@ func (bs *BlockchainService) ObtenerCadenaBlockchain(contractAddress string) (*m
 		return nil, fmt.Errorf("error verificando contrato: %v", err)
 	}
 	if len(code) == 0 {
-		return nil, fmt.Errorf("no se encontró contrato en la dirección especificada")
+		return nil, ErrContractNotFound
 	}
 
-	// Obtener el bloque actual
-	latestBlock, err := bs.Client.BlockNumber(context.Background())
+	contract, err := bindings.NewLoteTracing(contractAddr, bs.Client)
 	if err != nil {
-		return nil, fmt.Errorf("error obteniendo bloque actual: %v", err)
+		return nil, fmt.Errorf("error creando binding del contrato: %v", err)
 	}
 
-	// Obtener todos los logs usando consultas por lotes
-	logs, err := bs.obtenerLogsPorLotes(contractAddr, latestBlock)
+	// loteId está indexado en los eventos, así que se lee del contrato
+	loteID, err := contract.LoteId(&bind.CallOpts{Context: context.Background()})
 	if err != nil {
-		return nil, fmt.Errorf("error obteniendo logs: %v", err)
-	}
-
-	// Procesar eventos
-	var eventos []models.EventoBlockchain
-	var loteID string
-
-	for _, vLog := range logs {
-		// Obtener información del bloque para el timestamp
-		block, err := bs.Client.BlockByNumber(context.Background(), big.NewInt(int64(vLog.BlockNumber)))
-		if err != nil {
-			continue // Continuar con el siguiente log si hay error
-		}
-
-		evento := models.EventoBlockchain{
-			BlockNumber: vLog.BlockNumber,
-			TxHash:      vLog.TxHash.Hex(),
-			Timestamp:   block.Time(),
-			Datos:       make(map[string]interface{}),
-		}
-
-		// Procesar según el tipo de evento
-		switch vLog.Topics[0] {
-		case parsedABI.Events["LoteCreado"].ID:
-			evento.TipoEvento = "LoteCreado"
-			
-			// Decodificar evento LoteCreado
-			var loteCreado struct {
-				LoteId             string
-				Fabricante         common.Address
-				TemperaturaMinima  int8
-				TemperaturaMaxima  int8
-			}
-			
-			err := parsedABI.UnpackIntoInterface(&loteCreado, "LoteCreado", vLog.Data)
-			if err == nil {
-				loteID = loteCreado.LoteId // Guardar el loteID para la respuesta
-				evento.Datos["loteId"] = loteCreado.LoteId
-				evento.Datos["fabricante"] = loteCreado.Fabricante.Hex()
-				evento.Datos["temperaturaMinima"] = loteCreado.TemperaturaMinima
-				evento.Datos["temperaturaMaxima"] = loteCreado.TemperaturaMaxima
-			}
-
-		case parsedABI.Events["CustodiaTransferida"].ID:
-			evento.TipoEvento = "CustodiaTransferida"
-			
-			// Los topics indexados están en vLog.Topics[1] y vLog.Topics[2]
-			if len(vLog.Topics) >= 3 {
-				propietarioAnterior := common.HexToAddress(vLog.Topics[1].Hex())
-				nuevoPropietario := common.HexToAddress(vLog.Topics[2].Hex())
-				
-				evento.Datos["propietarioAnterior"] = propietarioAnterior.Hex()
-				evento.Datos["nuevoPropietario"] = nuevoPropietario.Hex()
-			}
-			
-			// Decodificar el campo comprometido del evento
-			var custodiaTransferida struct {
-				Comprometido bool
-			}
-			
-			err := parsedABI.UnpackIntoInterface(&custodiaTransferida, "CustodiaTransferida", vLog.Data)
-			if err == nil {
-				evento.Datos["comprometido"] = custodiaTransferida.Comprometido
-			}
-
-		case parsedABI.Events["LoteComprometido"].ID:
-			evento.TipoEvento = "LoteComprometido"
-			
-			// El propietario está indexado en vLog.Topics[1]
-			if len(vLog.Topics) >= 2 {
-				propietario := common.HexToAddress(vLog.Topics[1].Hex())
-				evento.Datos["propietario"] = propietario.Hex()
-			}
-			
-			// Decodificar evento LoteComprometido actualizado
-			var loteComprometido struct {
-				TempMin      int8
-				TempMax      int8
-				Comprometido bool
-				Motivo       string
-			}
-			
-			err := parsedABI.UnpackIntoInterface(&loteComprometido, "LoteComprometido", vLog.Data)
-			if err == nil {
-				evento.Datos["tempMin"] = loteComprometido.TempMin
-				evento.Datos["tempMax"] = loteComprometido.TempMax
-				evento.Datos["comprometido"] = loteComprometido.Comprometido
-				evento.Datos["motivo"] = loteComprometido.Motivo
-			}
-		}
-
-		eventos = append(eventos, evento)
+		loteID = ""
 	}
 
-	// Si no encontramos el loteID en los eventos, intentar obtenerlo del contrato
-	if loteID == "" {
-		// Crear instancia del contrato para llamadas de solo lectura
-		contract := bind.NewBoundContract(contractAddr, parsedABI, bs.Client, bs.Client, bs.Client)
-		callOpts := &bind.CallOpts{Context: context.Background()}
-
-		var loteIdResult []interface{}
-		err = contract.Call(callOpts, &loteIdResult, "loteId")
-		if err == nil && len(loteIdResult) > 0 {
-			loteID = loteIdResult[0].(string)
-		}
+	contrato, indexadoHasta, err := bs.indexer.Eventos(context.Background(), contractAddr)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo eventos indexados: %v", err)
 	}
 
 	response := &models.CadenaBlockchainResponse{
 		ContractAddress: contractAddress,
 		LoteID:          loteID,
-		TotalEventos:    len(eventos),
-		Eventos:         eventos,
+		TotalEventos:    len(contrato.Eventos),
+		Eventos:         contrato.Eventos,
+		IndexadoHasta:   indexadoHasta,
 	}
 
 	return response, nil
 }
 
-// obtenerLogsPorLotes obtiene logs del contrato usando consultas por lotes para evitar limitaciones de RPC gratuitos
-func (bs *BlockchainService) obtenerLogsPorLotes(contractAddr common.Address, latestBlock uint64) ([]types.Log, error) {
-	// Estrategia optimizada: buscar desde los bloques más recientes hacia atrás
-	// ya que los contratos suelen ser recientes
-	const batchSize = 10 // Usar lotes pequeños para compatibilidad con RPC gratuitos
-	var allLogs []types.Log
-	
-	// Empezar desde los últimos 50,000 bloques para optimizar la búsqueda
-	startBlock := uint64(0)
-	if latestBlock > 50000 {
-		startBlock = latestBlock - 50000
+// ObtenerLotePorID busca en el registro el contrato de un loteId. Antes de
+// responder se indexan los bloques nuevos del contrato para que el
+// propietario y el estado estén al día.
+func (bs *BlockchainService) ObtenerLotePorID(loteID string) (*models.RegistroLote, error) {
+	lote, err := bs.lotes.PorLoteID(loteID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Buscar en lotes pequeños
-	for fromBlock := startBlock; fromBlock <= latestBlock; fromBlock += batchSize {
-		toBlock := fromBlock + batchSize - 1
-		if toBlock > latestBlock {
-			toBlock = latestBlock
-		}
-
-		// Crear filtro para este lote
-		query := ethereum.FilterQuery{
-			FromBlock: big.NewInt(int64(fromBlock)),
-			ToBlock:   big.NewInt(int64(toBlock)),
-			Addresses: []common.Address{contractAddr},
-		}
-
-		// Obtener logs para este lote
-		logs, err := bs.Client.FilterLogs(context.Background(), query)
-		if err != nil {
-			// Si aún falla, intentar bloque por bloque
-			singleBlockLogs, singleErr := bs.obtenerLogsBloqueIndividual(contractAddr, fromBlock, toBlock)
-			if singleErr != nil {
-				return nil, fmt.Errorf("error en lote %d-%d: %v", fromBlock, toBlock, err)
-			}
-			allLogs = append(allLogs, singleBlockLogs...)
-		} else {
-			allLogs = append(allLogs, logs...)
-		}
+	// Un despliegue aún no minado no tiene código todavía
+	_, _, err = bs.indexer.Eventos(context.Background(), common.HexToAddress(lote.ContractAddress))
+	if err != nil && !errors.Is(err, ErrContractNotFound) {
+		return nil, fmt.Errorf("error actualizando lote: %v", err)
 	}
-
-	return allLogs, nil
+	return bs.lotes.PorLoteID(loteID)
 }
 
-// obtenerLogsBloqueIndividual maneja casos extremos donde necesitamos consultar bloque por bloque
-func (bs *BlockchainService) obtenerLogsBloqueIndividual(contractAddr common.Address, fromBlock, toBlock uint64) ([]types.Log, error) {
-	var allLogs []types.Log
-
-	for block := fromBlock; block <= toBlock; block++ {
-		query := ethereum.FilterQuery{
-			FromBlock: big.NewInt(int64(block)),
-			ToBlock:   big.NewInt(int64(block)),
-			Addresses: []common.Address{contractAddr},
-		}
-
-		logs, err := bs.Client.FilterLogs(context.Background(), query)
-		if err != nil {
-			// Si falla incluso con un solo bloque, continuar con el siguiente
-			continue
-		}
-
-		allLogs = append(allLogs, logs...)
-	}
-
-	return allLogs, nil
+// BuscarLotes devuelve los lotes del registro que cumplen el filtro
+func (bs *BlockchainService) BuscarLotes(filtro FiltroLotes) []models.RegistroLote {
+	return bs.lotes.Buscar(filtro)
 }
 
 // DiagnosticarContrato proporciona información detallada sobre el estado de un contrato
@@ -710,33 +502,39 @@ func (bs *BlockchainService) DiagnosticarContrato(contractAddress string) (map[s
 func (bs *BlockchainService) probarLlamadasContrato(contractAddr common.Address) map[string]interface{} {
 	calls := make(map[string]interface{})
 	
-	// Parsear ABI
-	parsedABI, err := abi.JSON(strings.NewReader(getContractABI()))
+	// Crear binding del contrato
+	contract, err := bindings.NewLoteTracingCaller(contractAddr, bs.Client)
 	if err != nil {
 		calls["abiError"] = err.Error()
 		return calls
 	}
-	
-	// Crear instancia del contrato
-	contract := bind.NewBoundContract(contractAddr, parsedABI, bs.Client, bs.Client, bs.Client)
 	callOpts := &bind.CallOpts{Context: context.Background()}
-	
+
 	// Intentar llamar a cada función de solo lectura
-	functions := []string{"loteId", "fabricante", "propietarioActual", "temperaturaMinima", "temperaturaMaxima", "tempRegMinima", "tempRegMaxima", "comprometido"}
-	
-	for _, funcName := range functions {
-		var result []interface{}
-		err := contract.Call(callOpts, &result, funcName)
+	functions := []struct {
+		name string
+		call func() (interface{}, error)
+	}{
+		{"loteId", func() (interface{}, error) { return contract.LoteId(callOpts) }},
+		{"fabricante", func() (interface{}, error) { return contract.Fabricante(callOpts) }},
+		{"propietarioActual", func() (interface{}, error) { return contract.PropietarioActual(callOpts) }},
+		{"temperaturaMinima", func() (interface{}, error) { return contract.TemperaturaMinima(callOpts) }},
+		{"temperaturaMaxima", func() (interface{}, error) { return contract.TemperaturaMaxima(callOpts) }},
+		{"tempRegMinima", func() (interface{}, error) { return contract.TempRegMinima(callOpts) }},
+		{"tempRegMaxima", func() (interface{}, error) { return contract.TempRegMaxima(callOpts) }},
+		{"comprometido", func() (interface{}, error) { return contract.Comprometido(callOpts) }},
+	}
+
+	for _, function := range functions {
+		value, err := function.call()
 		if err != nil {
-			calls[funcName+"_error"] = err.Error()
+			calls[function.name+"_error"] = err.Error()
 		} else {
-			calls[funcName+"_success"] = true
-			if len(result) > 0 {
-				calls[funcName+"_value"] = fmt.Sprintf("%v", result[0])
-			}
+			calls[function.name+"_success"] = true
+			calls[function.name+"_value"] = fmt.Sprintf("%v", value)
 		}
 	}
-	
+
 	return calls
 }
 
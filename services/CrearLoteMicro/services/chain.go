@@ -0,0 +1,22 @@
+package services
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ChainClient es lo que BlockchainService necesita de la blockchain. Lo
+// implementan ethclient.Client y SimulatedChain.
+type ChainClient interface {
+	bind.ContractBackend
+	TxBackend
+	ReceiptBackend
+	BlockNumber(ctx context.Context) (uint64, error)
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	ChainID(ctx context.Context) (*big.Int, error)
+}
@@ -0,0 +1,544 @@
+package services
+
+import (
+	"CrearLoteMicro/bindings"
+	"CrearLoteMicro/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrContractNotFound se devuelve cuando no hay código desplegado en la dirección
+var ErrContractNotFound = errors.New("no se encontró contrato en la dirección especificada")
+
+// errReorgDuranteIndexacion indica que un bloque cambió mientras se indexaba;
+// la pasada se descarta y la siguiente detecta la reorganización
+var errReorgDuranteIndexacion = errors.New("el bloque cambió durante la indexación")
+
+// IndexerBackend es lo que el indexador de eventos necesita de la blockchain.
+// Lo implementan ethclient.Client y SimulatedChain.
+type IndexerBackend interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+	TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error)
+}
+
+// EventIndexerOptions configura el indexador de eventos
+type EventIndexerOptions struct {
+	// PollInterval es la frecuencia con la que se siguen los bloques nuevos
+	PollInterval time.Duration
+	// BatchBlocks es el tamaño inicial de las ventanas de eth_getLogs; se
+	// reduce a la mitad cuando el proveedor rechaza la consulta
+	BatchBlocks uint64
+	// ReorgDepth es el número de bloques recientes guardados para detectar
+	// reorganizaciones
+	ReorgDepth int
+	// Store guarda el índice entre reinicios; nil lo mantiene en memoria
+	Store EventStore
+	// OnUpdate recibe una copia del historial de cada contrato que cambia,
+	// tanto por eventos nuevos como por una reorganización
+	OnUpdate func(contrato models.ContratoIndexado)
+}
+
+// EventIndexer sigue la cadena para los contratos LoteTracing conocidos y
+// guarda sus eventos decodificados, de modo que el historial de un lote se
+// sirve sin recorrer bloques en cada solicitud
+type EventIndexer struct {
+	backend  IndexerBackend
+	filterer *bindings.LoteTracingFilterer
+	options  EventIndexerOptions
+	eventIDs []common.Hash
+
+	// syncMu serializa las pasadas de indexación
+	syncMu  sync.Mutex
+	ventana uint64
+
+	mu        sync.RWMutex
+	bloques   []models.BloqueIndexado
+	contratos map[common.Address]*models.ContratoIndexado
+}
+
+// NewEventIndexer crea el indexador y carga el índice guardado
+func NewEventIndexer(backend IndexerBackend, options EventIndexerOptions) (*EventIndexer, error) {
+	if options.PollInterval <= 0 {
+		options.PollInterval = 15 * time.Second
+	}
+	if options.BatchBlocks == 0 {
+		options.BatchBlocks = 2000
+	}
+	if options.ReorgDepth <= 0 {
+		options.ReorgDepth = 64
+	}
+
+	filterer, err := bindings.NewLoteTracingFilterer(common.Address{}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creando binding del contrato: %v", err)
+	}
+	contractABI, err := bindings.LoteTracingMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("error parseando ABI: %v", err)
+	}
+
+	ix := &EventIndexer{
+		backend:   backend,
+		filterer:  filterer,
+		options:   options,
+		ventana:   options.BatchBlocks,
+		contratos: make(map[common.Address]*models.ContratoIndexado),
+	}
+	for _, name := range []string{"LoteCreado", "CustodiaTransferida", "TemperaturaRegistrada", "LecturasAncladas", "LoteComprometido", "CustodiaPropuesta", "CustodiaAceptada", "CustodiaRechazada", "CustodiaCancelada", "RolOtorgado", "RolRevocado", "AdminTransferido"} {
+		ix.eventIDs = append(ix.eventIDs, contractABI.Events[name].ID)
+	}
+
+	if options.Store != nil {
+		indice, err := options.Store.Load()
+		if err != nil {
+			return nil, err
+		}
+		if indice != nil {
+			ix.bloques = indice.Bloques
+			for i := range indice.Contratos {
+				contrato := indice.Contratos[i]
+				ix.contratos[common.HexToAddress(contrato.ContractAddress)] = &contrato
+			}
+		}
+	}
+
+	return ix, nil
+}
+
+// Registrar añade un contrato a seguir desde el bloque indicado. Los
+// contratos ya conocidos se ignoran.
+func (ix *EventIndexer) Registrar(contractAddr common.Address, desde uint64) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	if _, ok := ix.contratos[contractAddr]; ok {
+		return
+	}
+	ix.contratos[contractAddr] = &models.ContratoIndexado{
+		ContractAddress: contractAddr.Hex(),
+		DesdeBloque:     desde,
+		SiguienteBloque: desde,
+	}
+}
+
+// Run indexa los bloques nuevos cada PollInterval hasta que se cancele el contexto
+func (ix *EventIndexer) Run(ctx context.Context) {
+	ticker := time.NewTicker(ix.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := ix.Sincronizar(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Error indexando eventos: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Eventos devuelve el historial indexado de un contrato y el último bloque
+// incluido. Si la cadena avanzó desde la última pasada se indexan antes los
+// bloques nuevos, y un contrato desconocido se registra desde su bloque de
+// despliegue.
+func (ix *EventIndexer) Eventos(ctx context.Context, contractAddr common.Address) (*models.ContratoIndexado, uint64, error) {
+	head, err := ix.backend.BlockNumber(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error obteniendo bloque actual: %v", err)
+	}
+
+	ix.mu.RLock()
+	contrato, ok := ix.contratos[contractAddr]
+	tip, indexado := ix.tipLocked()
+	pendiente := !ok || !indexado || tip.Numero < head || contrato.SiguienteBloque <= tip.Numero
+	ix.mu.RUnlock()
+
+	if !ok {
+		desde, err := ix.bloqueDespliegue(ctx, contractAddr)
+		if err != nil {
+			return nil, 0, err
+		}
+		ix.Registrar(contractAddr, desde)
+	}
+	if pendiente {
+		if err := ix.Sincronizar(ctx); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	contrato = ix.contratos[contractAddr]
+	tip, _ = ix.tipLocked()
+
+	copia := *contrato
+	copia.Eventos = append([]models.EventoBlockchain(nil), contrato.Eventos...)
+	return &copia, tip.Numero, nil
+}
+
+// Sincronizar ejecuta una pasada: deshace los bloques reorganizados e indexa
+// desde el primer bloque pendiente de cada contrato hasta el último bloque
+func (ix *EventIndexer) Sincronizar(ctx context.Context) error {
+	ix.syncMu.Lock()
+	defer ix.syncMu.Unlock()
+
+	if err := ix.detectarReorg(ctx); err != nil {
+		return err
+	}
+
+	head, err := ix.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error obteniendo último bloque: %v", err)
+	}
+	if head == nil {
+		return fmt.Errorf("error obteniendo último bloque: %v", ethereum.NotFound)
+	}
+	hasta := head.Number.Uint64()
+
+	// Los contratos que van por el mismo bloque se consultan juntos, así que
+	// los que ya están al día comparten una sola consulta por ventana
+	grupos := make(map[uint64][]common.Address)
+	ix.mu.RLock()
+	for addr, contrato := range ix.contratos {
+		if contrato.SiguienteBloque <= hasta {
+			grupos[contrato.SiguienteBloque] = append(grupos[contrato.SiguienteBloque], addr)
+		}
+	}
+	ix.mu.RUnlock()
+
+	desdes := make([]uint64, 0, len(grupos))
+	for desde := range grupos {
+		desdes = append(desdes, desde)
+	}
+	sort.Slice(desdes, func(i, j int) bool { return desdes[i] < desdes[j] })
+
+	// Las ventanas alejadas de la cabeza se aplican al momento para no perder
+	// el avance de un recorrido largo; las recientes solo al final de la
+	// pasada, junto con el bloque que permite detectar reorganizaciones
+	recientes := make(map[common.Address][]models.EventoBlockchain)
+	var recientesAddrs []common.Address
+	timestamps := make(map[uint64]uint64)
+	for _, desde := range desdes {
+		addrs := grupos[desde]
+		err := ix.filtrar(ctx, addrs, desde, hasta, func(logs []types.Log, fin uint64) error {
+			eventos, err := ix.decodificar(ctx, logs, timestamps)
+			if err != nil {
+				return err
+			}
+			if fin+uint64(ix.options.ReorgDepth) <= hasta {
+				ix.aplicar(addrs, eventos, fin)
+				return nil
+			}
+			for addr, evs := range eventos {
+				recientes[addr] = append(recientes[addr], evs...)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		recientesAddrs = append(recientesAddrs, addrs...)
+	}
+
+	ix.mu.Lock()
+	ix.aplicarLocked(recientesAddrs, recientes, hasta)
+	if tip, ok := ix.tipLocked(); !ok || tip.Numero != hasta {
+		ix.bloques = append(ix.bloques, models.BloqueIndexado{Numero: hasta, Hash: head.Hash().Hex()})
+	}
+	if len(ix.bloques) > ix.options.ReorgDepth {
+		ix.bloques = ix.bloques[len(ix.bloques)-ix.options.ReorgDepth:]
+	}
+	ix.saveLocked()
+	ix.mu.Unlock()
+	return nil
+}
+
+// filtrar consulta los logs de los contratos entre desde y hasta en ventanas
+// y llama a procesar con cada una. Si el proveedor rechaza una ventana se
+// reduce a la mitad y se reintenta.
+func (ix *EventIndexer) filtrar(ctx context.Context, addrs []common.Address, desde, hasta uint64, procesar func(logs []types.Log, fin uint64) error) error {
+	for desde <= hasta {
+		fin := desde + ix.ventana - 1
+		if fin > hasta {
+			fin = hasta
+		}
+
+		logs, err := ix.backend.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(desde),
+			ToBlock:   new(big.Int).SetUint64(fin),
+			Addresses: addrs,
+			Topics:    [][]common.Hash{ix.eventIDs},
+		})
+		if err != nil {
+			if ix.ventana > 1 && ctx.Err() == nil {
+				ix.ventana /= 2
+				continue
+			}
+			return fmt.Errorf("error obteniendo logs %d-%d: %v", desde, fin, err)
+		}
+
+		if err := procesar(logs, fin); err != nil {
+			return err
+		}
+		desde = fin + 1
+	}
+	return nil
+}
+
+// decodificar convierte los logs en eventos con el timestamp de su bloque,
+// agrupados por contrato
+func (ix *EventIndexer) decodificar(ctx context.Context, logs []types.Log, timestamps map[uint64]uint64) (map[common.Address][]models.EventoBlockchain, error) {
+	eventos := make(map[common.Address][]models.EventoBlockchain)
+	for _, vLog := range logs {
+		if vLog.Removed {
+			continue
+		}
+
+		timestamp, ok := timestamps[vLog.BlockNumber]
+		if !ok {
+			header, err := ix.backend.HeaderByNumber(ctx, new(big.Int).SetUint64(vLog.BlockNumber))
+			if err != nil {
+				return nil, fmt.Errorf("error obteniendo bloque %d: %v", vLog.BlockNumber, err)
+			}
+			if header == nil || header.Hash() != vLog.BlockHash {
+				return nil, errReorgDuranteIndexacion
+			}
+			timestamp = header.Time
+			timestamps[vLog.BlockNumber] = timestamp
+		}
+
+		// loteId está indexado como hash; el valor en claro sale de la
+		// transacción que creó el lote
+		var loteID, from string
+		if len(vLog.Topics) > 0 && vLog.Topics[0] == ix.eventIDs[0] {
+			if tx, _, err := ix.backend.TransactionByHash(ctx, vLog.TxHash); err == nil {
+				loteID = loteIDDeCalldata(tx)
+				// Tras crearNuevoLote el propietario es quien envió la transacción
+				if sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); err == nil {
+					from = sender.Hex()
+				}
+			}
+		}
+
+		tipo, datos, err := decodificarEvento(ix.filterer, vLog, loteID)
+		if err != nil {
+			log.Printf("Evento no decodificado en %s: %v", vLog.TxHash.Hex(), err)
+			continue
+		}
+
+		eventos[vLog.Address] = append(eventos[vLog.Address], models.EventoBlockchain{
+			TipoEvento:  tipo,
+			BlockNumber: vLog.BlockNumber,
+			TxHash:      vLog.TxHash.Hex(),
+			Timestamp:   timestamp,
+			Datos:       datos,
+			BlockHash:   vLog.BlockHash.Hex(),
+			LogIndex:    vLog.Index,
+			From:        from,
+		})
+	}
+	return eventos, nil
+}
+
+func (ix *EventIndexer) aplicar(addrs []common.Address, eventos map[common.Address][]models.EventoBlockchain, fin uint64) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.aplicarLocked(addrs, eventos, fin)
+	ix.saveLocked()
+}
+
+// aplicarLocked añade los eventos a sus contratos y marca los contratos como
+// indexados hasta fin
+func (ix *EventIndexer) aplicarLocked(addrs []common.Address, eventos map[common.Address][]models.EventoBlockchain, fin uint64) {
+	for _, addr := range addrs {
+		contrato := ix.contratos[addr]
+		if contrato == nil || contrato.SiguienteBloque > fin {
+			continue
+		}
+		contrato.Eventos = append(contrato.Eventos, eventos[addr]...)
+		contrato.SiguienteBloque = fin + 1
+		if len(eventos[addr]) > 0 {
+			ix.notificarLocked(contrato)
+		}
+	}
+}
+
+// detectarReorg compara los bloques guardados con la cadena actual y, si el
+// último ya no es canónico, deshace lo indexado por encima del ancestro común
+func (ix *EventIndexer) detectarReorg(ctx context.Context) error {
+	ix.mu.RLock()
+	bloques := append([]models.BloqueIndexado(nil), ix.bloques...)
+	ix.mu.RUnlock()
+
+	for i := len(bloques) - 1; i >= 0; i-- {
+		header, err := ix.backend.HeaderByNumber(ctx, new(big.Int).SetUint64(bloques[i].Numero))
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return fmt.Errorf("error obteniendo bloque %d: %v", bloques[i].Numero, err)
+		}
+		if header != nil && header.Hash().Hex() == bloques[i].Hash {
+			if i < len(bloques)-1 {
+				ix.deshacer(bloques[i].Numero)
+			}
+			return nil
+		}
+	}
+
+	// Ningún bloque guardado sigue siendo canónico
+	if len(bloques) > 0 && bloques[0].Numero > 0 {
+		ix.deshacer(bloques[0].Numero - 1)
+	} else if len(bloques) > 0 {
+		ix.deshacer(0)
+	}
+	return nil
+}
+
+// deshacer descarta los eventos posteriores al bloque ancestro
+func (ix *EventIndexer) deshacer(ancestro uint64) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	log.Printf("Reorganización detectada: reindexando desde el bloque %d", ancestro+1)
+	for _, contrato := range ix.contratos {
+		eventos := contrato.Eventos[:0]
+		for _, evento := range contrato.Eventos {
+			if evento.BlockNumber <= ancestro {
+				eventos = append(eventos, evento)
+			}
+		}
+		if len(eventos) != len(contrato.Eventos) {
+			contrato.Eventos = eventos
+			ix.notificarLocked(contrato)
+		}
+		if contrato.SiguienteBloque > ancestro+1 {
+			contrato.SiguienteBloque = ancestro + 1
+			if contrato.SiguienteBloque < contrato.DesdeBloque {
+				contrato.SiguienteBloque = contrato.DesdeBloque
+			}
+		}
+	}
+
+	bloques := ix.bloques[:0]
+	for _, bloque := range ix.bloques {
+		if bloque.Numero <= ancestro {
+			bloques = append(bloques, bloque)
+		}
+	}
+	ix.bloques = bloques
+	ix.saveLocked()
+}
+
+// bloqueDespliegue busca el primer bloque con código en la dirección. Requiere
+// que el nodo conserve el estado histórico (nodo de archivo).
+func (ix *EventIndexer) bloqueDespliegue(ctx context.Context, contractAddr common.Address) (uint64, error) {
+	head, err := ix.backend.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error obteniendo bloque actual: %v", err)
+	}
+	code, err := ix.backend.CodeAt(ctx, contractAddr, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error verificando contrato: %v", err)
+	}
+	if len(code) == 0 {
+		return 0, ErrContractNotFound
+	}
+
+	bajo, alto := uint64(0), head
+	for bajo < alto {
+		medio := bajo + (alto-bajo)/2
+		code, err := ix.backend.CodeAt(ctx, contractAddr, new(big.Int).SetUint64(medio))
+		if err != nil {
+			return 0, fmt.Errorf("error buscando bloque de despliegue: %v", err)
+		}
+		if len(code) > 0 {
+			alto = medio
+		} else {
+			bajo = medio + 1
+		}
+	}
+	return bajo, nil
+}
+
+// Contratos devuelve una copia del historial de todos los contratos indexados
+func (ix *EventIndexer) Contratos() []models.ContratoIndexado {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	contratos := make([]models.ContratoIndexado, 0, len(ix.contratos))
+	for _, contrato := range ix.contratos {
+		copia := *contrato
+		copia.Eventos = append([]models.EventoBlockchain(nil), contrato.Eventos...)
+		contratos = append(contratos, copia)
+	}
+	return contratos
+}
+
+// Direcciones devuelve las direcciones de los contratos seguidos, ordenadas
+func (ix *EventIndexer) Direcciones() []common.Address {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	direcciones := make([]common.Address, 0, len(ix.contratos))
+	for addr := range ix.contratos {
+		direcciones = append(direcciones, addr)
+	}
+	sort.Slice(direcciones, func(i, j int) bool { return direcciones[i].Hex() < direcciones[j].Hex() })
+	return direcciones
+}
+
+// UltimoBloque devuelve el último bloque indexado; false si aún no hubo
+// ninguna pasada
+func (ix *EventIndexer) UltimoBloque() (uint64, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	tip, ok := ix.tipLocked()
+	return tip.Numero, ok
+}
+
+func (ix *EventIndexer) notificarLocked(contrato *models.ContratoIndexado) {
+	if ix.options.OnUpdate == nil {
+		return
+	}
+	copia := *contrato
+	copia.Eventos = append([]models.EventoBlockchain(nil), contrato.Eventos...)
+	ix.options.OnUpdate(copia)
+}
+
+func (ix *EventIndexer) tipLocked() (models.BloqueIndexado, bool) {
+	if len(ix.bloques) == 0 {
+		return models.BloqueIndexado{}, false
+	}
+	return ix.bloques[len(ix.bloques)-1], true
+}
+
+func (ix *EventIndexer) saveLocked() {
+	if ix.options.Store == nil {
+		return
+	}
+
+	indice := &models.IndiceEventos{Bloques: ix.bloques}
+	for _, contrato := range ix.contratos {
+		indice.Contratos = append(indice.Contratos, *contrato)
+	}
+	sort.Slice(indice.Contratos, func(i, j int) bool {
+		return indice.Contratos[i].ContractAddress < indice.Contratos[j].ContractAddress
+	})
+
+	if err := ix.options.Store.Save(indice); err != nil {
+		log.Printf("Error guardando índice de eventos: %v", err)
+	}
+}
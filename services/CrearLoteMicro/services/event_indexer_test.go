@@ -0,0 +1,197 @@
+package services
+
+import (
+	"CrearLoteMicro/bindings"
+	"CrearLoteMicro/signer"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+func newTestIndexer(t *testing.T, backend *flakyBackend, options EventIndexerOptions) *EventIndexer {
+	t.Helper()
+	indexer, err := NewEventIndexer(&SimulatedChain{SimulatedBackend: backend.SimulatedBackend}, options)
+	if err != nil {
+		t.Fatalf("Failed to create indexer: %v", err)
+	}
+	return indexer
+}
+
+// enviarLlamada envía una llamada al contrato sin minarla
+func enviarLlamada(t *testing.T, manager *NonceManager, firmante signer.Signer, contractAddr common.Address, call func(contract *bindings.LoteTracingTransactor, opts *bind.TransactOpts) (*types.Transaction, error)) {
+	t.Helper()
+	contract, _ := loteTracingTransactor(contractAddr)
+	data, err := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return call(contract, opts)
+	})
+	if err != nil {
+		t.Fatalf("Failed to pack call: %v", err)
+	}
+	if _, err := manager.Submit(context.Background(), TxRequest{Signer: firmante, To: &contractAddr, Data: data}); err != nil {
+		t.Fatalf("Expected call to be sent, got %v", err)
+	}
+}
+
+// otorgarRol envía, sin minarla, la concesión de un rol del contrato por su administrador
+func otorgarRol(t *testing.T, manager *NonceManager, admin signer.Signer, contractAddr common.Address, rol string, cuenta common.Address) {
+	t.Helper()
+	enviarLlamada(t, manager, admin, contractAddr, func(contract *bindings.LoteTracingTransactor, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.OtorgarRol(opts, idsRoles[rol], cuenta)
+	})
+}
+
+// cederCustodia envía, sin minarlas, la propuesta de custodia a la cuenta de
+// desarrollo "distribuidor" y su aceptación firmada, que retransmite el
+// propietario. Devuelve la cuenta que recibe la custodia.
+func cederCustodia(t *testing.T, manager *NonceManager, propietario signer.Signer, contractAddr common.Address, propuesta uint32) common.Address {
+	t.Helper()
+	key := devKey(t, "distribuidor")
+	receptor := crypto.PubkeyToAddress(key.PublicKey)
+	enviarLlamada(t, manager, propietario, contractAddr, func(contract *bindings.LoteTracingTransactor, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.ProponerCustodia(opts, receptor, 3600, "")
+	})
+
+	typedData := TypedDataAceptacionCustodia(params.AllDevChainProtocolChanges.ChainID, contractAddr, propuesta, receptor, crypto.Keccak256Hash(nil))
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		t.Fatalf("Failed to hash typed data: %v", err)
+	}
+	firma, _ := crypto.Sign(hash, key)
+	enviarLlamada(t, manager, propietario, contractAddr, func(contract *bindings.LoteTracingTransactor, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.AceptarCustodiaFirmada(opts, firma[64]+27, common.BytesToHash(firma[:32]), common.BytesToHash(firma[32:64]))
+	})
+	return receptor
+}
+
+func tiposEvento(t *testing.T, indexer *EventIndexer, contractAddr common.Address) (string, uint64) {
+	t.Helper()
+	contrato, tip, err := indexer.Eventos(context.Background(), contractAddr)
+	if err != nil {
+		t.Fatalf("Expected indexed events, got %v", err)
+	}
+	tipos := make([]string, 0, len(contrato.Eventos))
+	for _, evento := range contrato.Eventos {
+		tipos = append(tipos, evento.TipoEvento)
+	}
+	return strings.Join(tipos, ","), tip
+}
+
+func TestEventIndexer_IndexesLoteHistory(t *testing.T) {
+	manager, backend, firmante := newTestNonceManager(t)
+	store := NewFileEventStore(filepath.Join(t.TempDir(), "data", "events.json"))
+	indexer := newTestIndexer(t, backend, EventIndexerOptions{Store: store})
+	ctx := context.Background()
+
+	contractAddr := crypto.CreateAddress(firmante.Address(), 0)
+	indexer.Registrar(contractAddr, 0)
+	if err := indexer.Sincronizar(ctx); err != nil {
+		t.Fatalf("Expected empty pass, got %v", err)
+	}
+
+	if _, err := manager.Submit(ctx, TxRequest{Signer: firmante, Operation: OpDeploy, Data: deployData(t, "LOTE001", 2, 8)}); err != nil {
+		t.Fatalf("Expected deploy to be sent, got %v", err)
+	}
+	backend.Commit()
+	otorgarRol(t, manager, firmante, contractAddr, RolOraculo, firmante.Address())
+	otorgarRol(t, manager, firmante, contractAddr, RolDistribuidor, crypto.PubkeyToAddress(devKey(t, "distribuidor").PublicKey))
+	enviarLlamada(t, manager, firmante, contractAddr, func(contract *bindings.LoteTracingTransactor, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.RegistrarTemperatura(opts, 1, 12, "SENSOR-01")
+	})
+	cederCustodia(t, manager, firmante, contractAddr, 1)
+	backend.Commit()
+	if err := indexer.Sincronizar(ctx); err != nil {
+		t.Fatalf("Expected pass to succeed, got %v", err)
+	}
+
+	contrato, tip, err := indexer.Eventos(ctx, contractAddr)
+	if err != nil {
+		t.Fatalf("Expected indexed events, got %v", err)
+	}
+	if len(contrato.Eventos) != 10 || tip != 2 {
+		t.Fatalf("Expected 10 events up to block 2, got %d up to %d", len(contrato.Eventos), tip)
+	}
+	creado := contrato.Eventos[2]
+	if creado.TipoEvento != "LoteCreado" || creado.Datos["loteId"] != "LOTE001" || creado.Timestamp == 0 || creado.BlockNumber != 1 {
+		t.Errorf("Expected LoteCreado with loteId and timestamp, got %+v", creado)
+	}
+	if contrato.Eventos[9].TipoEvento != "CustodiaTransferida" || contrato.Eventos[9].LogIndex != 6 {
+		t.Errorf("Expected transfer as seventh log of block 2, got %+v", contrato.Eventos[9])
+	}
+
+	// Un nuevo proceso sirve el historial desde el archivo sin volver a indexar
+	reloaded := newTestIndexer(t, backend, EventIndexerOptions{Store: store})
+	if eventos := reloaded.contratos[contractAddr]; eventos == nil || len(eventos.Eventos) != 10 {
+		t.Fatalf("Expected events to be reloaded, got %+v", eventos)
+	}
+	if tipos, _ := tiposEvento(t, reloaded, contractAddr); tipos != "AdminTransferido,RolOtorgado,LoteCreado,RolOtorgado,RolOtorgado,TemperaturaRegistrada,LoteComprometido,CustodiaPropuesta,CustodiaAceptada,CustodiaTransferida" {
+		t.Errorf("Unexpected reloaded history %s", tipos)
+	}
+}
+
+func TestEventIndexer_HandlesReorg(t *testing.T) {
+	manager, backend, firmante := newTestNonceManager(t)
+	indexer := newTestIndexer(t, backend, EventIndexerOptions{})
+	ctx := context.Background()
+
+	if _, err := manager.Submit(ctx, TxRequest{Signer: firmante, Operation: OpDeploy, Data: deployData(t, "LOTE001", 2, 8)}); err != nil {
+		t.Fatalf("Expected deploy to be sent, got %v", err)
+	}
+	backend.Commit()
+	contractAddr := crypto.CreateAddress(firmante.Address(), 0)
+	indexer.Registrar(contractAddr, 0)
+
+	otorgarRol(t, manager, firmante, contractAddr, RolDistribuidor, crypto.PubkeyToAddress(devKey(t, "distribuidor").PublicKey))
+	cederCustodia(t, manager, firmante, contractAddr, 1)
+	backend.Commit()
+	if tipos, tip := tiposEvento(t, indexer, contractAddr); tipos != "AdminTransferido,RolOtorgado,LoteCreado,RolOtorgado,CustodiaPropuesta,CustodiaAceptada,CustodiaTransferida" || tip != 2 {
+		t.Fatalf("Expected deploy and transfer up to block 2, got %s up to %d", tipos, tip)
+	}
+
+	// Una cadena lateral más larga sin la transferencia pasa a ser canónica
+	if err := backend.Fork(ctx, backend.Blockchain().GetHeaderByNumber(1).Hash()); err != nil {
+		t.Fatalf("Failed to fork: %v", err)
+	}
+	backend.Commit()
+	backend.Commit()
+
+	if tipos, tip := tiposEvento(t, indexer, contractAddr); tipos != "AdminTransferido,RolOtorgado,LoteCreado" || tip != 3 {
+		t.Errorf("Expected transfer to be dropped after reorg, got %s up to %d", tipos, tip)
+	}
+}
+
+func TestEventIndexer_DiscoversUnknownContracts(t *testing.T) {
+	manager, backend, firmante := newTestNonceManager(t)
+	indexer := newTestIndexer(t, backend, EventIndexerOptions{BatchBlocks: 2, ReorgDepth: 1})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		backend.Commit()
+	}
+	if _, err := manager.Submit(ctx, TxRequest{Signer: firmante, Operation: OpDeploy, Data: deployData(t, "LOTE001", 2, 8)}); err != nil {
+		t.Fatalf("Expected deploy to be sent, got %v", err)
+	}
+	backend.Commit()
+	backend.Commit()
+	contractAddr := crypto.CreateAddress(firmante.Address(), 0)
+
+	contrato, tip, err := indexer.Eventos(ctx, contractAddr)
+	if err != nil {
+		t.Fatalf("Expected unknown contract to be indexed, got %v", err)
+	}
+	if contrato.DesdeBloque != 4 || len(contrato.Eventos) != 3 || contrato.Eventos[0].BlockNumber != 4 || tip != 5 {
+		t.Errorf("Expected deploy found in block 4, got %+v up to %d", contrato, tip)
+	}
+
+	if _, _, err := indexer.Eventos(ctx, recipient); !errors.Is(err, ErrContractNotFound) {
+		t.Errorf("Expected ErrContractNotFound for an account without code, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// EventStore guarda el índice de eventos para no volver a recorrer la cadena
+// en cada reinicio
+type EventStore interface {
+	Load() (*models.IndiceEventos, error)
+	Save(indice *models.IndiceEventos) error
+}
+
+// FileEventStore guarda el índice en un archivo JSON
+type FileEventStore struct {
+	path string
+}
+
+// NewFileEventStore crea un almacén en el archivo indicado
+func NewFileEventStore(path string) *FileEventStore {
+	return &FileEventStore{path: path}
+}
+
+// Load lee el índice guardado; un archivo inexistente equivale a un índice vacío
+func (s *FileEventStore) Load() (*models.IndiceEventos, error) {
+	content, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo %s: %v", s.path, err)
+	}
+
+	var indice models.IndiceEventos
+	if err := json.Unmarshal(content, &indice); err != nil {
+		return nil, fmt.Errorf("error parseando %s: %v", s.path, err)
+	}
+	return &indice, nil
+}
+
+// Save reemplaza el archivo de forma atómica
+func (s *FileEventStore) Save(indice *models.IndiceEventos) error {
+	return utils.GuardarJSONAtomico(s.path, indice)
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"CrearLoteMicro/models"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Operaciones de escritura, usadas para los techos de comisión
+const (
+	OpDeploy               = "deploy"
+	OpRegistrarTemperatura = "registrarTemperatura"
+	OpTransferirCustodia   = "transferirCustodia"
+	OpCrearNuevoLote       = "crearNuevoLote"
+	OpCancelar             = "cancelar"
+	OpDeployFactory        = "deployFactory"
+	OpCrearLote            = "crearLote"
+	OpAutorizarFabricante  = "autorizarFabricante"
+	OpOtorgarRol           = "otorgarRol"
+	OpRevocarRol           = "revocarRol"
+	OpAnclarLecturas       = "anclarLecturas"
+	OpProponerCustodia     = "proponerCustodia"
+	OpAceptarCustodia      = "aceptarCustodia"
+	OpRechazarCustodia     = "rechazarCustodia"
+	OpCancelarCustodia     = "cancelarCustodia"
+)
+
+// ErrFeeAboveCeiling se devuelve cuando la red exige una comisión mayor que el techo de la operación
+var ErrFeeAboveCeiling = errors.New("la comisión requerida supera el máximo configurado")
+
+// FeeOptions configura el cálculo de comisiones EIP-1559 y la estimación de gas
+type FeeOptions struct {
+	// GasMarginPercent es el margen de seguridad sumado al gas estimado
+	GasMarginPercent int64
+	// BaseFeeMultiplier multiplica la base fee del último bloque al calcular
+	// maxFeePerGas, para cubrir subidas mientras la transacción espera
+	BaseFeeMultiplier int64
+	// MaxFeePerGas es el techo por defecto de maxFeePerGas en wei (nil sin techo)
+	MaxFeePerGas *big.Int
+	// MaxFeePerGasByOperation sobrescribe el techo para una operación
+	MaxFeePerGasByOperation map[string]*big.Int
+	// Legacy usa siempre gasPrice aunque la red tenga base fee, para redes
+	// permisionadas (Besu/Quorum) configuradas sin mercado de comisiones
+	Legacy bool
+}
+
+// feeQuote son las comisiones elegidas para una transacción. Sin base fee
+// (redes anteriores a London) o con FeeOptions.Legacy se usa gasPrice legacy.
+type feeQuote struct {
+	baseFee  *big.Int
+	tipCap   *big.Int
+	feeCap   *big.Int
+	gasPrice *big.Int
+}
+
+func (q *feeQuote) dynamic() bool {
+	return q.baseFee != nil
+}
+
+// ceiling devuelve el techo de maxFeePerGas de la operación
+func (o FeeOptions) ceiling(operation string) *big.Int {
+	if limit, ok := o.MaxFeePerGasByOperation[operation]; ok && limit != nil {
+		return limit
+	}
+	return o.MaxFeePerGas
+}
+
+// quoteFees calcula las comisiones de una operación: tip sugerido por el nodo y
+// maxFeePerGas = base fee * multiplicador + tip, limitado por el techo
+func (m *NonceManager) quoteFees(ctx context.Context, operation string) (*feeQuote, error) {
+	ceiling := m.options.Fees.ceiling(operation)
+
+	header, err := m.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo último bloque: %v", err)
+	}
+
+	if header.BaseFee == nil || m.options.Fees.Legacy {
+		gasPrice, err := m.backend.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error obteniendo gas price: %v", err)
+		}
+		if ceiling != nil && gasPrice.Cmp(ceiling) > 0 {
+			return nil, fmt.Errorf("%w: gasPrice %s > %s wei para %s", ErrFeeAboveCeiling, gasPrice, ceiling, operation)
+		}
+		return &feeQuote{gasPrice: gasPrice}, nil
+	}
+
+	tipCap, err := m.backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo gas tip cap: %v", err)
+	}
+
+	feeCap := new(big.Int).Mul(header.BaseFee, big.NewInt(m.options.Fees.BaseFeeMultiplier))
+	feeCap.Add(feeCap, tipCap)
+
+	if ceiling != nil {
+		required := new(big.Int).Add(header.BaseFee, tipCap)
+		if required.Cmp(ceiling) > 0 {
+			return nil, fmt.Errorf("%w: baseFee %s + tip %s > %s wei para %s",
+				ErrFeeAboveCeiling, header.BaseFee, tipCap, ceiling, operation)
+		}
+		if feeCap.Cmp(ceiling) > 0 {
+			feeCap = new(big.Int).Set(ceiling)
+		}
+	}
+
+	return &feeQuote{baseFee: header.BaseFee, tipCap: tipCap, feeCap: feeCap}, nil
+}
+
+// bumpFees calcula las comisiones de un reemplazo: las de la transacción
+// original aumentadas PriceBumpPercent, o las actuales de la red si son mayores
+func (m *NonceManager) bumpFees(ctx context.Context, original *types.Transaction, operation string) (*feeQuote, error) {
+	current, err := m.quoteFees(ctx, operation)
+	if err != nil && !errors.Is(err, ErrFeeAboveCeiling) {
+		return nil, err
+	}
+	ceiling := m.options.Fees.ceiling(operation)
+	percent := m.options.PriceBumpPercent
+
+	if original.Type() != types.DynamicFeeTxType || (current != nil && !current.dynamic()) {
+		gasPrice := bumpGasPrice(original.GasPrice(), percent)
+		if current != nil && current.gasPrice != nil && current.gasPrice.Cmp(gasPrice) > 0 {
+			gasPrice = current.gasPrice
+		}
+		if ceiling != nil && gasPrice.Cmp(ceiling) > 0 {
+			return nil, fmt.Errorf("%w: gasPrice %s > %s wei para %s", ErrFeeAboveCeiling, gasPrice, ceiling, operation)
+		}
+		return &feeQuote{gasPrice: gasPrice}, nil
+	}
+
+	tipCap := bumpGasPrice(original.GasTipCap(), percent)
+	feeCap := bumpGasPrice(original.GasFeeCap(), percent)
+	if ceiling != nil && feeCap.Cmp(ceiling) > 0 {
+		return nil, fmt.Errorf("%w: el reemplazo requiere maxFeePerGas %s > %s wei para %s",
+			ErrFeeAboveCeiling, feeCap, ceiling, operation)
+	}
+
+	quote := &feeQuote{tipCap: tipCap, feeCap: feeCap}
+	if current != nil {
+		quote.baseFee = current.baseFee
+		if current.tipCap.Cmp(quote.tipCap) > 0 {
+			quote.tipCap = current.tipCap
+		}
+		if current.feeCap.Cmp(quote.feeCap) > 0 {
+			quote.feeCap = current.feeCap
+		}
+	}
+	if quote.tipCap.Cmp(quote.feeCap) > 0 {
+		quote.tipCap = quote.feeCap
+	}
+	return quote, nil
+}
+
+// estimateGas estima el gas de la transacción y le suma el margen de
+// seguridad. Si la ejecución revertiría, el error incluye el motivo.
+func (m *NonceManager) estimateGas(ctx context.Context, from common.Address, req TxRequest, value *big.Int) (uint64, uint64, error) {
+	estimate, err := m.backend.EstimateGas(ctx, ethereum.CallMsg{
+		From:  from,
+		To:    req.To,
+		Value: value,
+		Data:  req.Data,
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("error estimando gas: %v", err)
+	}
+
+	limit := estimate + estimate*uint64(m.options.Fees.GasMarginPercent)/100
+	return estimate, limit, nil
+}
+
+// buildTx crea la transacción con las comisiones elegidas
+func (q *feeQuote) buildTx(chainID *big.Int, nonce uint64, to *common.Address, value *big.Int, gas uint64, data []byte) *types.Transaction {
+	if !q.dynamic() && q.gasPrice != nil {
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			To:       to,
+			Value:    value,
+			Gas:      gas,
+			GasPrice: q.gasPrice,
+			Data:     data,
+		})
+	}
+
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		To:        to,
+		Value:     value,
+		Gas:       gas,
+		GasTipCap: q.tipCap,
+		GasFeeCap: q.feeCap,
+		Data:      data,
+	})
+}
+
+// describeTx resume las comisiones y el coste de una transacción enviada
+func describeTx(tx *types.Transaction, from common.Address, operation string, gasEstimate uint64, baseFee *big.Int) *models.TransaccionEnviada {
+	info := &models.TransaccionEnviada{
+		TxHash:      tx.Hash().Hex(),
+		From:        from.Hex(),
+		Nonce:       tx.Nonce(),
+		Operacion:   operation,
+		GasLimit:    tx.Gas(),
+		GasEstimado: gasEstimate,
+	}
+	if gasEstimate == 0 {
+		gasEstimate = tx.Gas()
+	}
+
+	maxCost := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasFeeCap())
+	expectedPrice := tx.GasPrice()
+
+	if tx.Type() == types.DynamicFeeTxType {
+		info.Tipo = "eip1559"
+		info.MaxFeePerGas = tx.GasFeeCap().String()
+		info.MaxPriorityFeePerGas = tx.GasTipCap().String()
+		if baseFee != nil {
+			info.BaseFee = baseFee.String()
+			expectedPrice = new(big.Int).Add(baseFee, tx.GasTipCap())
+			if expectedPrice.Cmp(tx.GasFeeCap()) > 0 {
+				expectedPrice = tx.GasFeeCap()
+			}
+		}
+	} else {
+		info.Tipo = "legacy"
+		info.GasPrice = tx.GasPrice().String()
+	}
+
+	estimatedCost := new(big.Int).Mul(new(big.Int).SetUint64(gasEstimate), expectedPrice)
+	info.CostoEstimadoWei = estimatedCost.String()
+	info.CostoMaximoWei = maxCost.String()
+	info.CostoEstimadoEth = weiToEth(estimatedCost)
+	info.CostoMaximoEth = weiToEth(maxCost)
+	return info
+}
+
+// weiToEth formatea una cantidad en wei como ETH sin ceros finales
+func weiToEth(wei *big.Int) string {
+	eth := new(big.Rat).SetFrac(wei, big.NewInt(params.Ether)).FloatString(18)
+	return strings.TrimRight(strings.TrimRight(eth, "0"), ".")
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestNonceManager_EstimatesGasWithMargin(t *testing.T) {
+	manager, _, firmante := newTestNonceManager(t)
+
+	req := transfer(firmante)
+	req.GasLimit = 0
+	sent, err := manager.Submit(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if sent.Info.GasEstimado != 21000 || sent.Tx.Gas() != 25200 {
+		t.Errorf("Expected estimate 21000 and limit 25200, got %d and %d", sent.Info.GasEstimado, sent.Tx.Gas())
+	}
+	if sent.Tx.Type() != types.DynamicFeeTxType || sent.Info.Tipo != "eip1559" {
+		t.Errorf("Expected EIP-1559 transaction, got type %d (%s)", sent.Tx.Type(), sent.Info.Tipo)
+	}
+	if sent.Info.BaseFee == "" || sent.Info.CostoMaximoWei == "" || sent.Info.CostoEstimadoEth == "" {
+		t.Errorf("Expected fee breakdown, got %+v", sent.Info)
+	}
+}
+
+func TestNonceManager_FeeCeiling(t *testing.T) {
+	manager, backend, firmante := newTestNonceManager(t)
+
+	header, err := backend.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Failed to get header: %v", err)
+	}
+	tipCap, _ := backend.SuggestGasTipCap(context.Background())
+	required := new(big.Int).Add(header.BaseFee, tipCap)
+
+	// Techo por debajo de lo que exige la red: se rechaza
+	manager.options.Fees.MaxFeePerGasByOperation = map[string]*big.Int{
+		OpRegistrarTemperatura: new(big.Int).Sub(required, big.NewInt(1)),
+	}
+	req := transfer(firmante)
+	req.Operation = OpRegistrarTemperatura
+	if _, err := manager.Submit(context.Background(), req); !errors.Is(err, ErrFeeAboveCeiling) {
+		t.Fatalf("Expected ErrFeeAboveCeiling, got %v", err)
+	}
+
+	// Otra operación sin techo propio no se ve afectada
+	if _, err := manager.Submit(context.Background(), transfer(firmante)); err != nil {
+		t.Fatalf("Expected no error for operation without ceiling, got %v", err)
+	}
+
+	// Techo suficiente pero menor que base fee * multiplicador: se recorta
+	manager.options.Fees.MaxFeePerGasByOperation[OpRegistrarTemperatura] = required
+	sent, err := manager.Submit(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sent.Tx.GasFeeCap().Cmp(required) != 0 {
+		t.Errorf("Expected fee cap clamped to %s, got %s", required, sent.Tx.GasFeeCap())
+	}
+}
+
+func TestNonceManager_LegacyGasPolicy(t *testing.T) {
+	manager, _, firmante := newTestNonceManager(t)
+	manager.options.Fees.Legacy = true
+
+	sent, err := manager.Submit(context.Background(), transfer(firmante))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sent.Tx.Type() != types.LegacyTxType || sent.Info.Tipo != "legacy" || sent.Info.GasPrice == "" {
+		t.Errorf("Expected legacy transaction on a network with base fee, got type %d (%+v)", sent.Tx.Type(), sent.Info)
+	}
+}
+
+func TestWeiToEth(t *testing.T) {
+	cases := map[string]*big.Int{
+		"0":        big.NewInt(0),
+		"1":        big.NewInt(1e18),
+		"0.000021": big.NewInt(21000 * 1e9),
+	}
+	for want, wei := range cases {
+		if got := weiToEth(wei); got != want {
+			t.Errorf("Expected %s for %s wei, got %s", want, wei, got)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/signer"
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// AnclajeLecturas es un lote de lecturas de sensores resumido en la raíz de
+// su árbol Merkle, con el rango de temperaturas y el intervalo que cubre
+type AnclajeLecturas struct {
+	RaizMerkle    common.Hash
+	TempMin       int8
+	TempMax       int8
+	TotalLecturas uint32
+	// Desde y Hasta son el timestamp de la primera y la última lectura (segundos Unix)
+	Desde uint64
+	Hasta uint64
+}
+
+// AnclarLecturas guarda en el contrato LoteTracing la raíz Merkle de un lote
+// de lecturas. Solo los oráculos de sensores pueden anclar lecturas.
+func (bs *BlockchainService) AnclarLecturas(firmante signer.Signer, contractAddress string, anclaje AnclajeLecturas) (*models.TransaccionEnviada, error) {
+	toAddress := common.HexToAddress(contractAddress)
+	if err := bs.verificarRol(toAddress, firmante.Address(), RolOraculo); err != nil {
+		return nil, err
+	}
+	contract, err := loteTracingTransactor(toAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.AnclarLecturas(opts, anclaje.RaizMerkle, anclaje.TempMin, anclaje.TempMax,
+			anclaje.TotalLecturas, anclaje.Desde, anclaje.Hasta)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sent, err := bs.enviar(TxRequest{
+		Signer:    firmante,
+		Operation: OpAnclarLecturas,
+		To:        &toAddress,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sent.Info, nil
+}
+
+// TimestampAnclaje devuelve el timestamp del bloque en que se ancló la raíz
+// Merkle en el contrato, o 0 si no está anclada
+func (bs *BlockchainService) TimestampAnclaje(contractAddress string, raiz common.Hash) (uint64, error) {
+	contract, err := bs.loteCaller(common.HexToAddress(contractAddress))
+	if err != nil {
+		return 0, err
+	}
+	timestamp, err := contract.Anclajes(&bind.CallOpts{Context: context.Background()}, raiz)
+	if err != nil {
+		return 0, fmt.Errorf("error consultando anclaje: %v", err)
+	}
+	return timestamp.Uint64(), nil
+}
@@ -0,0 +1,423 @@
+package services
+
+import (
+	"CrearLoteMicro/bindings"
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/signer"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// VersionCertificado es la versión del formato de CertificadoLote
+const VersionCertificado = 1
+
+// ErrCertificadoInvalido se devuelve cuando el documento no tiene el formato
+// de un certificado firmado
+var ErrCertificadoInvalido = errors.New("certificado inválido")
+
+// EmitirCertificado arma el certificado de cadena de frío de un contrato
+// LoteTracing a partir de su estado y de los eventos indexados desde la última
+// creación del lote, y lo firma con la cuenta del emisor
+func (bs *BlockchainService) EmitirCertificado(firmante signer.Signer, red, contractAddress string) (*models.CertificadoFirmado, error) {
+	textSigner, ok := firmante.(signer.TextSigner)
+	if !ok {
+		return nil, fmt.Errorf("la cuenta %s (%s) no firma mensajes", firmante.Address().Hex(), firmante.Kind())
+	}
+
+	ctx := context.Background()
+	contractAddr := common.HexToAddress(contractAddress)
+	contrato, indexadoHasta, err := bs.indexer.Eventos(ctx, contractAddr)
+	if err != nil {
+		if errors.Is(err, ErrContractNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error obteniendo eventos indexados: %v", err)
+	}
+	lote, err := bs.leerEstadoLote(ctx, contractAddr)
+	if err != nil {
+		return nil, err
+	}
+	header, err := bs.Client.HeaderByNumber(ctx, new(big.Int).SetUint64(indexadoHasta))
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo bloque de referencia %d: %v", indexadoHasta, err)
+	}
+
+	certificado := models.CertificadoLote{
+		Version:              VersionCertificado,
+		Red:                  red,
+		ChainID:              bs.chainID.Int64(),
+		ContractAddress:      contractAddr.Hex(),
+		Lote:                 *lote,
+		BloqueReferencia:     indexadoHasta,
+		HashBloqueReferencia: header.Hash().Hex(),
+		Emisor:               firmante.Address().Hex(),
+		EmitidoEn:            time.Now().Unix(),
+	}
+	resumirEventos(&certificado, eventosLoteVigente(contrato.Eventos))
+
+	hash, err := hashCertificado(certificado)
+	if err != nil {
+		return nil, err
+	}
+	firma, err := textSigner.SignText(hash.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("error firmando certificado: %v", err)
+	}
+
+	return &models.CertificadoFirmado{
+		Certificado: certificado,
+		Hash:        hash.Hex(),
+		Firma:       hexutil.Encode(firma),
+	}, nil
+}
+
+// VerificarCertificado comprueba la firma del certificado y vuelve a leer de
+// la cadena cada transacción que cita: que siga en el mismo bloque, que no
+// haya revertido y que el log sea el evento indicado del contrato. La
+// custodia, las excursiones y el total de lecturas se rehacen con esos logs y
+// se comparan con los del certificado. emisorEsperado es la cuenta que firma
+// los certificados de este servicio, o la dirección cero si no hay ninguna.
+func (bs *BlockchainService) VerificarCertificado(documento models.CertificadoFirmado, emisorEsperado common.Address) (*models.VerificacionCertificado, error) {
+	certificado := documento.Certificado
+	if !common.IsHexAddress(certificado.ContractAddress) {
+		return nil, fmt.Errorf("%w: dirección de contrato %q", ErrCertificadoInvalido, certificado.ContractAddress)
+	}
+	firma, err := hexutil.Decode(documento.Firma)
+	if err != nil || len(firma) != crypto.SignatureLength {
+		return nil, fmt.Errorf("%w: la firma debe tener %d bytes en hexadecimal", ErrCertificadoInvalido, crypto.SignatureLength)
+	}
+
+	ctx := context.Background()
+	contractAddr := common.HexToAddress(certificado.ContractAddress)
+	resultado := &models.VerificacionCertificado{
+		Transacciones: []models.VerificacionTransaccion{},
+	}
+	fallo := func(formato string, args ...interface{}) {
+		resultado.Errores = append(resultado.Errores, fmt.Sprintf(formato, args...))
+	}
+
+	// Integridad y firma
+	hash, err := hashCertificado(certificado)
+	if err != nil {
+		return nil, err
+	}
+	resultado.HashCoincide = hash.Hex() == documento.Hash
+	if !resultado.HashCoincide {
+		fallo("el hash del certificado es %s y el documento indica %s", hash.Hex(), documento.Hash)
+	}
+	if firma[crypto.RecoveryIDOffset] >= 27 {
+		firma[crypto.RecoveryIDOffset] -= 27
+	}
+	if pub, err := crypto.SigToPub(accounts.TextHash(hash.Bytes()), firma); err == nil {
+		emisor := crypto.PubkeyToAddress(*pub)
+		resultado.Emisor = emisor.Hex()
+		resultado.FirmaValida = common.IsHexAddress(certificado.Emisor) && emisor == common.HexToAddress(certificado.Emisor)
+		resultado.EmisorReconocido = emisorEsperado != (common.Address{}) && emisor == emisorEsperado
+	}
+	if !resultado.FirmaValida {
+		fallo("la firma no es del emisor %s", certificado.Emisor)
+	}
+
+	resultado.RedCoincide = certificado.ChainID == bs.chainID.Int64()
+	if !resultado.RedCoincide {
+		fallo("el certificado es de la cadena %d y la red consultada es %s", certificado.ChainID, bs.chainID)
+	}
+
+	ultimoBloque, err := bs.Client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo último bloque: %v", err)
+	}
+	header, err := bs.Client.HeaderByNumber(ctx, new(big.Int).SetUint64(certificado.BloqueReferencia))
+	resultado.BloqueReferenciaValido = err == nil && header != nil && header.Hash().Hex() == certificado.HashBloqueReferencia
+	if !resultado.BloqueReferenciaValido {
+		fallo("el bloque de referencia %d no está en la cadena con el hash %s", certificado.BloqueReferencia, certificado.HashBloqueReferencia)
+	}
+
+	// Transacciones citadas
+	filterer, err := bindings.NewLoteTracingFilterer(contractAddr, bs.Client)
+	if err != nil {
+		return nil, fmt.Errorf("error creando binding del contrato: %v", err)
+	}
+	recibos := make(map[common.Hash]*types.Receipt)
+	timestamps := make(map[uint64]uint64)
+	var eventos []models.EventoBlockchain
+	todasValidas := true
+	for _, referencia := range certificado.Transacciones {
+		verificacion := models.VerificacionTransaccion{
+			Evento:      referencia.Evento,
+			TxHash:      referencia.TxHash,
+			BlockNumber: referencia.BlockNumber,
+		}
+		evento, err := bs.verificarReferencia(ctx, filterer, contractAddr, referencia, certificado.Lote.LoteID, recibos, timestamps)
+		if err != nil {
+			verificacion.Error = err.Error()
+			todasValidas = false
+			fallo("%s en %s: %v", referencia.Evento, referencia.TxHash, err)
+		} else {
+			verificacion.Valida = true
+			verificacion.Confirmaciones = ultimoBloque - referencia.BlockNumber + 1
+			eventos = append(eventos, *evento)
+		}
+		resultado.Transacciones = append(resultado.Transacciones, verificacion)
+	}
+
+	// Contenido rehecho con los eventos citados
+	rehecho := models.CertificadoLote{Lote: certificado.Lote}
+	resumirEventos(&rehecho, eventos)
+	resultado.ContenidoCoincide = todasValidas && mismoResumen(certificado, rehecho)
+	if todasValidas && !resultado.ContenidoCoincide {
+		fallo("la custodia, las excursiones o las lecturas no coinciden con los eventos citados")
+	}
+
+	// Eventos posteriores al certificado
+	if contrato, _, err := bs.indexer.Eventos(ctx, contractAddr); err == nil {
+		for _, evento := range contrato.Eventos {
+			if evento.BlockNumber > certificado.BloqueReferencia {
+				resultado.EventosPosteriores++
+			}
+		}
+	} else if !errors.Is(err, ErrContractNotFound) {
+		return nil, fmt.Errorf("error obteniendo eventos indexados: %v", err)
+	}
+
+	resultado.Valido = resultado.HashCoincide && resultado.FirmaValida && resultado.RedCoincide &&
+		resultado.BloqueReferenciaValido && resultado.ContenidoCoincide
+	resultado.Vigente = resultado.Valido && resultado.EventosPosteriores == 0
+	return resultado, nil
+}
+
+// verificarReferencia lee el recibo de la transacción citada y devuelve su
+// evento decodificado como lo guarda el indexador
+func (bs *BlockchainService) verificarReferencia(ctx context.Context, filterer *bindings.LoteTracingFilterer, contractAddr common.Address, referencia models.ReferenciaTransaccion, loteID string, recibos map[common.Hash]*types.Receipt, timestamps map[uint64]uint64) (*models.EventoBlockchain, error) {
+	txHash := common.HexToHash(referencia.TxHash)
+	recibo, ok := recibos[txHash]
+	if !ok {
+		var err error
+		if recibo, err = bs.Client.TransactionReceipt(ctx, txHash); err != nil {
+			return nil, fmt.Errorf("transacción no encontrada: %v", err)
+		}
+		recibos[txHash] = recibo
+	}
+	if recibo.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("la transacción revirtió")
+	}
+	if recibo.BlockNumber.Uint64() != referencia.BlockNumber || recibo.BlockHash.Hex() != referencia.BlockHash {
+		return nil, fmt.Errorf("la transacción está en el bloque %d (%s)", recibo.BlockNumber.Uint64(), recibo.BlockHash.Hex())
+	}
+
+	var vLog *types.Log
+	for _, l := range recibo.Logs {
+		if l.Index == referencia.LogIndex {
+			vLog = l
+		}
+	}
+	if vLog == nil || vLog.Address != contractAddr {
+		return nil, fmt.Errorf("la transacción no tiene el log %d del contrato", referencia.LogIndex)
+	}
+	tipo, datos, err := decodificarEvento(filterer, *vLog, loteID)
+	if err != nil {
+		return nil, fmt.Errorf("log no decodificado: %v", err)
+	}
+	if tipo != referencia.Evento {
+		return nil, fmt.Errorf("el log %d es %s", referencia.LogIndex, tipo)
+	}
+
+	timestamp, ok := timestamps[vLog.BlockNumber]
+	if !ok {
+		header, err := bs.Client.HeaderByNumber(ctx, new(big.Int).SetUint64(vLog.BlockNumber))
+		if err != nil {
+			return nil, fmt.Errorf("error obteniendo bloque %d: %v", vLog.BlockNumber, err)
+		}
+		timestamp = header.Time
+		timestamps[vLog.BlockNumber] = timestamp
+	}
+
+	evento := &models.EventoBlockchain{
+		TipoEvento:  tipo,
+		BlockNumber: vLog.BlockNumber,
+		TxHash:      vLog.TxHash.Hex(),
+		Timestamp:   timestamp,
+		Datos:       datos,
+		BlockHash:   vLog.BlockHash.Hex(),
+		LogIndex:    vLog.Index,
+	}
+	// Tras crearNuevoLote el propietario es quien envió la transacción
+	if tipo == "LoteCreado" {
+		if tx, _, err := bs.Client.TransactionByHash(ctx, txHash); err == nil {
+			if sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); err == nil {
+				evento.From = sender.Hex()
+			}
+		}
+	}
+	return evento, nil
+}
+
+// leerEstadoLote lee el estado público de un contrato LoteTracing
+func (bs *BlockchainService) leerEstadoLote(ctx context.Context, contractAddr common.Address) (*models.LoteInfoResponse, error) {
+	contract, err := bs.loteCaller(contractAddr)
+	if err != nil {
+		return nil, err
+	}
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	lote := &models.LoteInfoResponse{ContractAddress: contractAddr.Hex()}
+	if lote.LoteID, err = contract.LoteId(callOpts); err != nil {
+		return nil, fmt.Errorf("error obteniendo loteId: %v", err)
+	}
+	fabricante, err := contract.Fabricante(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo fabricante: %v", err)
+	}
+	lote.Fabricante = fabricante.Hex()
+	propietario, err := contract.PropietarioActual(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo propietario actual: %v", err)
+	}
+	lote.PropietarioActual = propietario.Hex()
+	if lote.TemperaturaMinima, err = contract.TemperaturaMinima(callOpts); err != nil {
+		return nil, fmt.Errorf("error obteniendo temperatura mínima: %v", err)
+	}
+	if lote.TemperaturaMaxima, err = contract.TemperaturaMaxima(callOpts); err != nil {
+		return nil, fmt.Errorf("error obteniendo temperatura máxima: %v", err)
+	}
+	if lote.TempRegMinima, err = contract.TempRegMinima(callOpts); err != nil {
+		return nil, fmt.Errorf("error obteniendo temperatura registrada mínima: %v", err)
+	}
+	if lote.TempRegMaxima, err = contract.TempRegMaxima(callOpts); err != nil {
+		return nil, fmt.Errorf("error obteniendo temperatura registrada máxima: %v", err)
+	}
+	if lote.Comprometido, err = contract.Comprometido(callOpts); err != nil {
+		return nil, fmt.Errorf("error obteniendo estado comprometido: %v", err)
+	}
+	return lote, nil
+}
+
+// eventosLoteVigente devuelve los eventos desde el último LoteCreado:
+// crearNuevoLote reutiliza el contrato para otro lote
+func eventosLoteVigente(eventos []models.EventoBlockchain) []models.EventoBlockchain {
+	for i := len(eventos) - 1; i >= 0; i-- {
+		if eventos[i].TipoEvento == "LoteCreado" {
+			return eventos[i:]
+		}
+	}
+	return eventos
+}
+
+// resumirEventos completa la custodia, las excursiones, el total de lecturas
+// y las referencias del certificado con los eventos del lote
+func resumirEventos(certificado *models.CertificadoLote, eventos []models.EventoBlockchain) {
+	certificado.Custodia = []models.EslabonCustodia{}
+	certificado.Excursiones = []models.ExcursionTemperatura{}
+	certificado.Transacciones = []models.ReferenciaTransaccion{}
+	certificado.TotalLecturas = 0
+
+	// Los contratos anteriores a TemperaturaRegistrada solo emiten
+	// LoteComprometido; con lectura en la misma transacción no se repite
+	conLectura := make(map[string]bool)
+	for _, evento := range eventos {
+		if evento.TipoEvento == "TemperaturaRegistrada" || evento.TipoEvento == "LecturasAncladas" {
+			conLectura[evento.TxHash] = true
+		}
+	}
+
+	for _, evento := range eventos {
+		certificado.Transacciones = append(certificado.Transacciones, models.ReferenciaTransaccion{
+			Evento:      evento.TipoEvento,
+			TxHash:      evento.TxHash,
+			BlockNumber: evento.BlockNumber,
+			BlockHash:   evento.BlockHash,
+			LogIndex:    evento.LogIndex,
+		})
+
+		excursion := models.ExcursionTemperatura{
+			Evento:      evento.TipoEvento,
+			TempMin:     datoInt8(evento.Datos["tempMin"]),
+			TempMax:     datoInt8(evento.Datos["tempMax"]),
+			Timestamp:   evento.Timestamp,
+			BlockNumber: evento.BlockNumber,
+			TxHash:      evento.TxHash,
+		}
+		enRango, _ := evento.Datos["enRango"].(bool)
+
+		switch evento.TipoEvento {
+		case "LoteCreado":
+			propietario := evento.From
+			if propietario == "" {
+				propietario, _ = evento.Datos["fabricante"].(string)
+			}
+			motivo, _ := evento.Datos["motivo"].(string)
+			certificado.Custodia = append(certificado.Custodia, models.EslabonCustodia{
+				NuevoPropietario: propietario,
+				Motivo:           motivo,
+				Timestamp:        evento.Timestamp,
+				BlockNumber:      evento.BlockNumber,
+				TxHash:           evento.TxHash,
+			})
+
+		case "CustodiaTransferida":
+			eslabon := models.EslabonCustodia{
+				Timestamp:   evento.Timestamp,
+				BlockNumber: evento.BlockNumber,
+				TxHash:      evento.TxHash,
+			}
+			eslabon.PropietarioAnterior, _ = evento.Datos["propietarioAnterior"].(string)
+			eslabon.NuevoPropietario, _ = evento.Datos["nuevoPropietario"].(string)
+			eslabon.Motivo, _ = evento.Datos["motivo"].(string)
+			certificado.Custodia = append(certificado.Custodia, eslabon)
+
+		case "TemperaturaRegistrada":
+			certificado.TotalLecturas++
+			if !enRango {
+				excursion.SensorID, _ = evento.Datos["sensorId"].(string)
+				certificado.Excursiones = append(certificado.Excursiones, excursion)
+			}
+
+		case "LecturasAncladas":
+			total := uint32(datoUint64(evento.Datos["totalLecturas"]))
+			certificado.TotalLecturas += int(total)
+			if !enRango {
+				excursion.RaizMerkle, _ = evento.Datos["raizMerkle"].(string)
+				excursion.TotalLecturas = total
+				certificado.Excursiones = append(certificado.Excursiones, excursion)
+			}
+
+		case "LoteComprometido":
+			if !conLectura[evento.TxHash] {
+				certificado.Excursiones = append(certificado.Excursiones, excursion)
+			}
+		}
+	}
+
+	certificado.CadenaFrioIntacta = !certificado.Lote.Comprometido && len(certificado.Excursiones) == 0
+}
+
+// mismoResumen compara la parte del certificado que sale de los eventos
+func mismoResumen(a, b models.CertificadoLote) bool {
+	resumen := func(c models.CertificadoLote) []byte {
+		contenido, _ := json.Marshal([]interface{}{c.Custodia, c.Excursiones, c.TotalLecturas, c.CadenaFrioIntacta})
+		return contenido
+	}
+	return bytes.Equal(resumen(a), resumen(b))
+}
+
+// hashCertificado es el keccak256 del JSON del certificado. Los campos se
+// serializan en el orden de CertificadoLote, así que el hash es estable.
+func hashCertificado(certificado models.CertificadoLote) (common.Hash, error) {
+	contenido, err := json.Marshal(certificado)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("error serializando certificado: %v", err)
+	}
+	return crypto.Keccak256Hash(contenido), nil
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/signer"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// newTestCertificado despliega un lote con lecturas, un anclaje y una
+// transferencia de custodia
+func newTestCertificado(t *testing.T, excursion bool) (*BlockchainService, string, map[string]signer.Signer) {
+	t.Helper()
+	_, bs, contractAddress, cuentas := newTestCustodia(t)
+	fabricante := cuentas["fabricante"]
+	if _, err := bs.OtorgarRol(fabricante, contractAddress, RolOraculo, fabricante.Address().Hex()); err != nil {
+		t.Fatalf("Expected oracle role to be granted, got %v", err)
+	}
+
+	tempMax := int8(7)
+	if excursion {
+		tempMax = 11
+	}
+	for _, lectura := range [][2]int8{{3, 6}, {4, tempMax}} {
+		if _, err := bs.RegistrarTemperatura(fabricante, contractAddress, lectura[0], lectura[1], "SENSOR-01"); err != nil {
+			t.Fatalf("Failed to register temperature: %v", err)
+		}
+	}
+	anclaje := AnclajeLecturas{RaizMerkle: common.HexToHash("0x01"), TempMin: 3, TempMax: 7, TotalLecturas: 10, Desde: 1, Hasta: 2}
+	if _, err := bs.AnclarLecturas(fabricante, contractAddress, anclaje); err != nil {
+		t.Fatalf("Failed to anchor readings: %v", err)
+	}
+	if _, err := bs.ProponerCustodia(fabricante, contractAddress, cuentas["distribuidor"].Address().Hex(), time.Hour, models.DetallesEnvio{}); err != nil {
+		t.Fatalf("Failed to propose custody: %v", err)
+	}
+	if _, err := bs.AceptarCustodia(cuentas["distribuidor"], contractAddress); err != nil {
+		t.Fatalf("Failed to accept custody: %v", err)
+	}
+	return bs, contractAddress, cuentas
+}
+
+func TestCertificado_IssueAndVerify(t *testing.T) {
+	bs, contractAddress, cuentas := newTestCertificado(t, false)
+	emisor, _ := signer.NewDevSigner("certificador")
+
+	documento, err := bs.EmitirCertificado(emisor, "local", contractAddress)
+	if err != nil {
+		t.Fatalf("Failed to issue certificate: %v", err)
+	}
+	certificado := documento.Certificado
+	if !certificado.CadenaFrioIntacta || len(certificado.Excursiones) != 0 || certificado.TotalLecturas != 12 {
+		t.Errorf("Expected an intact cold chain with 12 readings, got %+v", certificado)
+	}
+	if len(certificado.Custodia) != 2 || certificado.Custodia[0].NuevoPropietario != cuentas["fabricante"].Address().Hex() ||
+		certificado.Custodia[1].NuevoPropietario != cuentas["distribuidor"].Address().Hex() {
+		t.Errorf("Unexpected custody chain %+v", certificado.Custodia)
+	}
+	if certificado.Transacciones[0].Evento != "LoteCreado" || certificado.Lote.PropietarioActual != cuentas["distribuidor"].Address().Hex() {
+		t.Errorf("Expected the certificate to start at LoteCreado, got %+v", certificado.Transacciones)
+	}
+
+	verificacion, err := bs.VerificarCertificado(*documento, emisor.Address())
+	if err != nil {
+		t.Fatalf("Failed to verify certificate: %v", err)
+	}
+	if !verificacion.Valido || !verificacion.Vigente || !verificacion.EmisorReconocido || len(verificacion.Errores) != 0 {
+		t.Errorf("Expected a valid certificate, got %+v", verificacion)
+	}
+	for _, transaccion := range verificacion.Transacciones {
+		if !transaccion.Valida || transaccion.Confirmaciones == 0 {
+			t.Errorf("Expected confirmed transaction, got %+v", transaccion)
+		}
+	}
+
+	// El documento sobrevive a la serialización
+	contenido, _ := json.Marshal(documento)
+	var copia models.CertificadoFirmado
+	json.Unmarshal(contenido, &copia)
+	if verificacion, _ := bs.VerificarCertificado(copia, common.Address{}); !verificacion.Valido || verificacion.EmisorReconocido {
+		t.Errorf("Expected the decoded certificate to be valid from an unknown issuer, got %+v", verificacion)
+	}
+
+	// Un evento nuevo deja el certificado sin vigencia
+	if _, err := bs.RegistrarTemperatura(cuentas["fabricante"], contractAddress, 3, 6, "SENSOR-01"); err != nil {
+		t.Fatalf("Failed to register temperature: %v", err)
+	}
+	verificacion, _ = bs.VerificarCertificado(*documento, emisor.Address())
+	if !verificacion.Valido || verificacion.Vigente || verificacion.EventosPosteriores != 1 {
+		t.Errorf("Expected a valid but outdated certificate, got %+v", verificacion)
+	}
+}
+
+func TestCertificado_ReportsExcursions(t *testing.T) {
+	bs, contractAddress, _ := newTestCertificado(t, true)
+	emisor, _ := signer.NewDevSigner("certificador")
+
+	documento, err := bs.EmitirCertificado(emisor, "local", contractAddress)
+	if err != nil {
+		t.Fatalf("Failed to issue certificate: %v", err)
+	}
+	certificado := documento.Certificado
+	if certificado.CadenaFrioIntacta || !certificado.Lote.Comprometido || len(certificado.Excursiones) != 1 {
+		t.Fatalf("Expected one excursion, got %+v", certificado.Excursiones)
+	}
+	if excursion := certificado.Excursiones[0]; excursion.SensorID != "SENSOR-01" || excursion.TempMax != 11 {
+		t.Errorf("Unexpected excursion %+v", excursion)
+	}
+}
+
+func TestCertificado_DetectsTampering(t *testing.T) {
+	bs, contractAddress, cuentas := newTestCertificado(t, true)
+	emisor, _ := signer.NewDevSigner("certificador")
+	documento, err := bs.EmitirCertificado(emisor, "local", contractAddress)
+	if err != nil {
+		t.Fatalf("Failed to issue certificate: %v", err)
+	}
+
+	// Ocultar la excursión cambia el hash
+	alterado := *documento
+	alterado.Certificado.Excursiones = []models.ExcursionTemperatura{}
+	alterado.Certificado.CadenaFrioIntacta = true
+	verificacion, _ := bs.VerificarCertificado(alterado, emisor.Address())
+	if verificacion.Valido || verificacion.HashCoincide {
+		t.Errorf("Expected a hash mismatch, got %+v", verificacion)
+	}
+
+	// Volver a firmarlo no basta: los eventos citados no coinciden
+	hash, _ := hashCertificado(alterado.Certificado)
+	firma, _ := crypto.Sign(crypto.Keccak256([]byte("\x19Ethereum Signed Message:\n32"), hash.Bytes()), devKey(t, "certificador"))
+	alterado.Hash, alterado.Firma = hash.Hex(), hexutil.Encode(firma)
+	verificacion, _ = bs.VerificarCertificado(alterado, emisor.Address())
+	if verificacion.Valido || !verificacion.FirmaValida || verificacion.ContenidoCoincide {
+		t.Errorf("Expected re-derived content to differ, got %+v", verificacion)
+	}
+
+	// Una referencia a otra transacción no se acepta
+	alterado = *documento
+	alterado.Certificado.Transacciones = append([]models.ReferenciaTransaccion(nil), documento.Certificado.Transacciones...)
+	alterado.Certificado.Transacciones[0].TxHash = alterado.Certificado.Transacciones[1].TxHash
+	hash, _ = hashCertificado(alterado.Certificado)
+	firma, _ = cuentas["fabricante"].(signer.TextSigner).SignText(hash.Bytes())
+	alterado.Hash, alterado.Firma, alterado.Certificado.Emisor = hash.Hex(), hexutil.Encode(firma), cuentas["fabricante"].Address().Hex()
+	verificacion, _ = bs.VerificarCertificado(alterado, emisor.Address())
+	if verificacion.Valido || verificacion.Transacciones[0].Valida || verificacion.EmisorReconocido {
+		t.Errorf("Expected the swapped reference to fail, got %+v", verificacion)
+	}
+
+	if _, err := bs.VerificarCertificado(models.CertificadoFirmado{Firma: "0x01"}, common.Address{}); !errors.Is(err, ErrCertificadoInvalido) {
+		t.Errorf("Expected ErrCertificadoInvalido, got %v", err)
+	}
+}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"CrearLoteMicro/bindings"
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// calldata devuelve los datos que genera una llamada de los bindings sin
+// firmarla ni enviarla. El nonce, el gas y las comisiones los asigna después
+// el NonceManager; los valores de opts solo evitan consultas a la red.
+func calldata(call func(opts *bind.TransactOpts) (*types.Transaction, error)) ([]byte, error) {
+	tx, err := call(&bind.TransactOpts{
+		Nonce:    big.NewInt(0),
+		GasPrice: big.NewInt(0),
+		GasLimit: 1,
+		NoSend:   true,
+		Signer: func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error empaquetando datos de la función: %v", err)
+	}
+	return tx.Data(), nil
+}
+
+// loteTracingTransactor crea el transactor del contrato, que solo se usa para
+// empaquetar llamadas con calldata
+func loteTracingTransactor(contractAddr common.Address) (*bindings.LoteTracingTransactor, error) {
+	transactor, err := bindings.NewLoteTracingTransactor(contractAddr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creando binding del contrato: %v", err)
+	}
+	return transactor, nil
+}
+
+// registraSensor indica si el código del contrato tiene
+// registrarTemperatura(int8,int8,string). Los contratos desplegados antes del
+// evento TemperaturaRegistrada solo tienen registrarTemperatura(int8,int8).
+// El despachador de funciones compara cada selector con un PUSH del código.
+func registraSensor(code []byte) bool {
+	contractABI, err := bindings.LoteTracingMetaData.GetAbi()
+	if err != nil {
+		return true
+	}
+	selector := bytes.TrimLeft(contractABI.Methods["registrarTemperatura"].ID, "\x00")
+	push := byte(0x5f + len(selector))
+	return bytes.Contains(code, append([]byte{push}, selector...))
+}
+
+// calldataTemperaturaSinSensor empaqueta registrarTemperatura(int8,int8) de
+// los contratos anteriores al evento TemperaturaRegistrada
+func calldataTemperaturaSinSensor(tempMin, tempMax int8) ([]byte, error) {
+	int8Type, err := abi.NewType("int8", "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error empaquetando datos de la función: %v", err)
+	}
+	args, err := abi.Arguments{{Type: int8Type}, {Type: int8Type}}.Pack(tempMin, tempMax)
+	if err != nil {
+		return nil, fmt.Errorf("error empaquetando datos de la función: %v", err)
+	}
+	return append(crypto.Keccak256([]byte("registrarTemperatura(int8,int8)"))[:4], args...), nil
+}
+
+// loteIDDeCalldata obtiene el loteId en claro de la transacción que emitió un
+// LoteCreado: los argumentos del constructor en un despliegue o los de
+// crearNuevoLote. Devuelve "" si la transacción no es ninguna de las dos.
+func loteIDDeCalldata(tx *types.Transaction) string {
+	contractABI, err := bindings.LoteTracingMetaData.GetAbi()
+	if err != nil {
+		return ""
+	}
+	data := tx.Data()
+
+	var args []interface{}
+	if tx.To() == nil {
+		bin := common.FromHex(bindings.LoteTracingMetaData.Bin)
+		if !bytes.HasPrefix(data, bin) {
+			return ""
+		}
+		args, err = contractABI.Constructor.Inputs.Unpack(data[len(bin):])
+	} else {
+		method, methodErr := contractABI.MethodById(data)
+		if methodErr != nil || method.Name != "crearNuevoLote" {
+			return ""
+		}
+		args, err = method.Inputs.Unpack(data[4:])
+	}
+	if err != nil || len(args) == 0 {
+		return ""
+	}
+
+	loteID, _ := args[0].(string)
+	return loteID
+}
+
+// decodificarEvento convierte un log de LoteTracing en su tipo y sus datos.
+// loteId está indexado como string, así que el log solo trae su hash
+// (loteIdHash); si loteID coincide con él también se incluye en claro.
+func decodificarEvento(filterer *bindings.LoteTracingFilterer, vLog types.Log, loteID string) (string, map[string]interface{}, error) {
+	contractABI, err := bindings.LoteTracingMetaData.GetAbi()
+	if err != nil {
+		return "", nil, fmt.Errorf("error parseando ABI: %v", err)
+	}
+	if len(vLog.Topics) == 0 {
+		return "", nil, fmt.Errorf("log sin topics")
+	}
+
+	datos := make(map[string]interface{})
+	switch vLog.Topics[0] {
+	case contractABI.Events["LoteCreado"].ID:
+		evento, err := filterer.ParseLoteCreado(vLog)
+		if err != nil {
+			return "", nil, err
+		}
+		datos["loteIdHash"] = evento.LoteId.Hex()
+		if loteID != "" && crypto.Keccak256Hash([]byte(loteID)) == evento.LoteId {
+			datos["loteId"] = loteID
+		}
+		datos["fabricante"] = evento.Fabricante.Hex()
+		datos["temperaturaMinima"] = evento.TemperaturaMinima
+		datos["temperaturaMaxima"] = evento.TemperaturaMaxima
+		datos["motivo"] = evento.Motivo
+		return "LoteCreado", datos, nil
+
+	case contractABI.Events["CustodiaTransferida"].ID:
+		evento, err := filterer.ParseCustodiaTransferida(vLog)
+		if err != nil {
+			return "", nil, err
+		}
+		datos["propietarioAnterior"] = evento.PropietarioAnterior.Hex()
+		datos["nuevoPropietario"] = evento.NuevoPropietario.Hex()
+		datos["comprometido"] = evento.Comprometido
+		datos["motivo"] = evento.Motivo
+		return "CustodiaTransferida", datos, nil
+
+	case contractABI.Events["CustodiaPropuesta"].ID:
+		evento, err := filterer.ParseCustodiaPropuesta(vLog)
+		if err != nil {
+			return "", nil, err
+		}
+		datos["propuesta"] = evento.Propuesta
+		datos["propietario"] = evento.Propietario.Hex()
+		datos["destinatario"] = evento.Destinatario.Hex()
+		datos["expira"] = evento.Expira
+		datos["envio"] = common.Hash(evento.Envio).Hex()
+		datos["detallesEnvio"] = evento.DetallesEnvio
+		return "CustodiaPropuesta", datos, nil
+
+	case contractABI.Events["CustodiaAceptada"].ID:
+		evento, err := filterer.ParseCustodiaAceptada(vLog)
+		if err != nil {
+			return "", nil, err
+		}
+		datos["propuesta"] = evento.Propuesta
+		datos["destinatario"] = evento.Destinatario.Hex()
+		datos["firmada"] = evento.Firmada
+		return "CustodiaAceptada", datos, nil
+
+	case contractABI.Events["CustodiaRechazada"].ID:
+		evento, err := filterer.ParseCustodiaRechazada(vLog)
+		if err != nil {
+			return "", nil, err
+		}
+		datos["propuesta"] = evento.Propuesta
+		datos["destinatario"] = evento.Destinatario.Hex()
+		datos["motivo"] = evento.Motivo
+		return "CustodiaRechazada", datos, nil
+
+	case contractABI.Events["CustodiaCancelada"].ID:
+		evento, err := filterer.ParseCustodiaCancelada(vLog)
+		if err != nil {
+			return "", nil, err
+		}
+		datos["propuesta"] = evento.Propuesta
+		datos["propietario"] = evento.Propietario.Hex()
+		return "CustodiaCancelada", datos, nil
+
+	case contractABI.Events["TemperaturaRegistrada"].ID:
+		evento, err := filterer.ParseTemperaturaRegistrada(vLog)
+		if err != nil {
+			return "", nil, err
+		}
+		datos["oraculo"] = evento.Oraculo.Hex()
+		datos["sensorId"] = evento.SensorId
+		datos["tempMin"] = evento.TempMin
+		datos["tempMax"] = evento.TempMax
+		datos["enRango"] = evento.EnRango
+		datos["timestamp"] = evento.Timestamp.Uint64()
+		return "TemperaturaRegistrada", datos, nil
+
+	case contractABI.Events["LecturasAncladas"].ID:
+		evento, err := filterer.ParseLecturasAncladas(vLog)
+		if err != nil {
+			return "", nil, err
+		}
+		datos["oraculo"] = evento.Oraculo.Hex()
+		datos["raizMerkle"] = common.Hash(evento.RaizMerkle).Hex()
+		datos["tempMin"] = evento.TempMin
+		datos["tempMax"] = evento.TempMax
+		datos["totalLecturas"] = evento.TotalLecturas
+		datos["desde"] = evento.Desde
+		datos["hasta"] = evento.Hasta
+		datos["enRango"] = evento.EnRango
+		return "LecturasAncladas", datos, nil
+
+	case contractABI.Events["LoteComprometido"].ID:
+		evento, err := filterer.ParseLoteComprometido(vLog)
+		if err != nil {
+			return "", nil, err
+		}
+		datos["propietario"] = evento.Propietario.Hex()
+		datos["tempMin"] = evento.TempMin
+		datos["tempMax"] = evento.TempMax
+		datos["comprometido"] = evento.Comprometido
+		datos["motivo"] = evento.Motivo
+		return "LoteComprometido", datos, nil
+
+	case contractABI.Events["RolOtorgado"].ID:
+		evento, err := filterer.ParseRolOtorgado(vLog)
+		if err != nil {
+			return "", nil, err
+		}
+		datos["rol"] = nombreRol(evento.Rol)
+		datos["cuenta"] = evento.Cuenta.Hex()
+		datos["admin"] = evento.Admin.Hex()
+		return "RolOtorgado", datos, nil
+
+	case contractABI.Events["RolRevocado"].ID:
+		evento, err := filterer.ParseRolRevocado(vLog)
+		if err != nil {
+			return "", nil, err
+		}
+		datos["rol"] = nombreRol(evento.Rol)
+		datos["cuenta"] = evento.Cuenta.Hex()
+		datos["admin"] = evento.Admin.Hex()
+		return "RolRevocado", datos, nil
+
+	case contractABI.Events["AdminTransferido"].ID:
+		evento, err := filterer.ParseAdminTransferido(vLog)
+		if err != nil {
+			return "", nil, err
+		}
+		datos["adminAnterior"] = evento.AdminAnterior.Hex()
+		datos["nuevoAdmin"] = evento.NuevoAdmin.Hex()
+		return "AdminTransferido", datos, nil
+	}
+
+	return "", nil, fmt.Errorf("evento desconocido %s", vLog.Topics[0].Hex())
+}
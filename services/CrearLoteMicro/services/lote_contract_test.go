@@ -0,0 +1,114 @@
+package services
+
+import (
+	"CrearLoteMicro/bindings"
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func deployData(t *testing.T, loteID string, tempMin, tempMax int8) []byte {
+	t.Helper()
+	data, err := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		_, tx, _, err := bindings.DeployLoteTracing(opts, nil, loteID, tempMin, tempMax)
+		return tx, err
+	})
+	if err != nil {
+		t.Fatalf("Failed to pack deploy: %v", err)
+	}
+	return data
+}
+
+func TestDecodificarEvento(t *testing.T) {
+	manager, backend, firmante := newTestNonceManager(t)
+	ctx := context.Background()
+
+	if _, err := manager.Submit(ctx, TxRequest{Signer: firmante, Operation: OpDeploy, Data: deployData(t, "LOTE001", 2, 8)}); err != nil {
+		t.Fatalf("Expected deploy to be sent, got %v", err)
+	}
+	backend.Commit()
+	contractAddr := crypto.CreateAddress(firmante.Address(), 0)
+
+	receptor := crypto.PubkeyToAddress(devKey(t, "distribuidor").PublicKey)
+	contract, _ := loteTracingTransactor(contractAddr)
+	calls := []func(opts *bind.TransactOpts) (*types.Transaction, error){
+		func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return contract.OtorgarRol(opts, idsRoles[RolOraculo], firmante.Address())
+		},
+		func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return contract.OtorgarRol(opts, idsRoles[RolDistribuidor], receptor)
+		},
+		func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return contract.RegistrarTemperatura(opts, 1, 12, "SENSOR-01")
+		},
+	}
+	for _, call := range calls {
+		data, err := calldata(call)
+		if err != nil {
+			t.Fatalf("Failed to pack call: %v", err)
+		}
+		if _, err := manager.Submit(ctx, TxRequest{Signer: firmante, To: &contractAddr, Data: data}); err != nil {
+			t.Fatalf("Expected call to be sent, got %v", err)
+		}
+	}
+	cederCustodia(t, manager, firmante, contractAddr, 1)
+	backend.Commit()
+
+	logs, err := backend.FilterLogs(ctx, ethereum.FilterQuery{Addresses: []common.Address{contractAddr}})
+	if err != nil || len(logs) != 10 {
+		t.Fatalf("Expected 10 logs, got %d (%v)", len(logs), err)
+	}
+	filterer, _ := bindings.NewLoteTracingFilterer(contractAddr, backend)
+
+	// El despliegue nombra administrador y fabricante al remitente
+	tipo, datos, _ := decodificarEvento(filterer, logs[0], "")
+	if tipo != "AdminTransferido" || datos["nuevoAdmin"] != firmante.Address().Hex() || datos["adminAnterior"] != (common.Address{}).Hex() {
+		t.Errorf("Expected AdminTransferido, got %s %+v", tipo, datos)
+	}
+	tipo, datos, _ = decodificarEvento(filterer, logs[1], "")
+	if tipo != "RolOtorgado" || datos["rol"] != RolFabricante || datos["cuenta"] != firmante.Address().Hex() {
+		t.Errorf("Expected fabricante role, got %s %+v", tipo, datos)
+	}
+
+	tipo, datos, err = decodificarEvento(filterer, logs[2], "LOTE001")
+	if err != nil || tipo != "LoteCreado" {
+		t.Fatalf("Expected LoteCreado, got %s (%v)", tipo, err)
+	}
+	if datos["loteId"] != "LOTE001" || datos["motivo"] != "Lote Creado" || datos["temperaturaMaxima"] != int8(8) {
+		t.Errorf("Expected LoteCreado with loteId and motivo, got %+v", datos)
+	}
+	if _, datos, _ := decodificarEvento(filterer, logs[2], "OTRO"); datos["loteId"] != nil {
+		t.Errorf("Expected loteId only when its hash matches, got %+v", datos)
+	}
+
+	tipo, datos, _ = decodificarEvento(filterer, logs[4], "")
+	if tipo != "RolOtorgado" || datos["rol"] != RolDistribuidor || datos["cuenta"] != receptor.Hex() || datos["admin"] != firmante.Address().Hex() {
+		t.Errorf("Expected distribuidor role, got %s %+v", tipo, datos)
+	}
+
+	tipo, datos, _ = decodificarEvento(filterer, logs[5], "")
+	if tipo != "TemperaturaRegistrada" || datos["sensorId"] != "SENSOR-01" || datos["oraculo"] != firmante.Address().Hex() ||
+		datos["tempMin"] != int8(1) || datos["tempMax"] != int8(12) || datos["enRango"] != false || datos["timestamp"] == uint64(0) {
+		t.Errorf("Expected TemperaturaRegistrada for every reading, got %s %+v", tipo, datos)
+	}
+
+	tipo, datos, _ = decodificarEvento(filterer, logs[6], "")
+	if tipo != "LoteComprometido" || datos["tempMin"] != int8(1) || datos["motivo"] != "Temperatura fuera de rango" {
+		t.Errorf("Expected LoteComprometido, got %s %+v", tipo, datos)
+	}
+
+	tipo, datos, _ = decodificarEvento(filterer, logs[8], "")
+	if tipo != "CustodiaAceptada" || datos["destinatario"] != receptor.Hex() || datos["firmada"] != true {
+		t.Errorf("Expected signed CustodiaAceptada, got %s %+v", tipo, datos)
+	}
+
+	tipo, datos, _ = decodificarEvento(filterer, logs[9], "")
+	if tipo != "CustodiaTransferida" || datos["nuevoPropietario"] != receptor.Hex() || datos["comprometido"] != true {
+		t.Errorf("Expected CustodiaTransferida, got %s %+v", tipo, datos)
+	}
+}
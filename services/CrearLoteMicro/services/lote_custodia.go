@@ -0,0 +1,445 @@
+package services
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/signer"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Plazos de las propuestas de custodia. El contrato rechaza plazos mayores
+// que PLAZO_MAXIMO_CUSTODIA (30 días).
+const (
+	PlazoCustodiaPorDefecto = 72 * time.Hour
+	PlazoCustodiaMaximo     = 30 * 24 * time.Hour
+)
+
+// ErrSinPropuestaCustodia se devuelve al aceptar, rechazar o cancelar cuando
+// el lote no tiene una propuesta de custodia pendiente
+var ErrSinPropuestaCustodia = errors.New("el lote no tiene una propuesta de custodia pendiente")
+
+// ErrPropuestaVencida se devuelve al aceptar una propuesta cuyo plazo terminó
+var ErrPropuestaVencida = errors.New("la propuesta de custodia está vencida")
+
+// ErrNoDestinatario se devuelve cuando quien acepta o rechaza no es el
+// destinatario de la propuesta
+var ErrNoDestinatario = errors.New("la cuenta no es el destinatario de la propuesta")
+
+// ErrFirmaInvalida se devuelve cuando la firma de aceptación no es válida o no
+// la hizo el destinatario de la propuesta
+var ErrFirmaInvalida = errors.New("firma de aceptación inválida")
+
+// ErrPlazoCustodiaInvalido se devuelve con un plazo nulo o mayor que el máximo
+var ErrPlazoCustodiaInvalido = errors.New("plazo de custodia inválido")
+
+// estadoPropuesta es la propuesta de custodia guardada en el contrato
+type estadoPropuesta struct {
+	numero       uint32
+	destinatario common.Address
+	expira       uint64
+	envio        common.Hash
+}
+
+// pendiente indica si hay destinatario, aunque la propuesta esté vencida
+func (p *estadoPropuesta) pendiente() bool {
+	return p.destinatario != (common.Address{})
+}
+
+func (p *estadoPropuesta) vencida(ahora uint64) bool {
+	return p.pendiente() && ahora > p.expira
+}
+
+// leerPropuesta lee la propuesta de custodia del contrato
+func (bs *BlockchainService) leerPropuesta(contractAddr common.Address) (*estadoPropuesta, error) {
+	contract, err := bs.loteCaller(contractAddr)
+	if err != nil {
+		return nil, err
+	}
+	callOpts := &bind.CallOpts{Context: context.Background()}
+
+	propuesta := &estadoPropuesta{}
+	if propuesta.numero, err = contract.PropuestaCustodia(callOpts); err != nil {
+		return nil, fmt.Errorf("error consultando propuesta de custodia: %v", err)
+	}
+	if propuesta.destinatario, err = contract.CustodioPropuesto(callOpts); err != nil {
+		return nil, fmt.Errorf("error consultando destinatario de la propuesta: %v", err)
+	}
+	if propuesta.expira, err = contract.PropuestaExpira(callOpts); err != nil {
+		return nil, fmt.Errorf("error consultando plazo de la propuesta: %v", err)
+	}
+	if propuesta.envio, err = contract.EnvioPropuesto(callOpts); err != nil {
+		return nil, fmt.Errorf("error consultando envío de la propuesta: %v", err)
+	}
+	return propuesta, nil
+}
+
+// ahoraCadena devuelve el timestamp del último bloque, con el que el contrato
+// decide si una propuesta está vencida
+func (bs *BlockchainService) ahoraCadena() (uint64, error) {
+	header, err := bs.Client.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return 0, fmt.Errorf("error obteniendo último bloque: %v", err)
+	}
+	return header.Time, nil
+}
+
+// verificarDestinatario comprueba antes de enviar la transacción que haya una
+// propuesta pendiente y vigente para la cuenta. Los contratos desplegados
+// antes del traspaso en dos pasos no tienen la propuesta; en ese caso no se
+// comprueba y decide la estimación de gas.
+func (bs *BlockchainService) verificarDestinatario(contractAddr, cuenta common.Address, aceptar bool) (*estadoPropuesta, error) {
+	propuesta, err := bs.leerPropuesta(contractAddr)
+	if err != nil {
+		log.Printf("No se pudo comprobar la propuesta de custodia de %s: %v", contractAddr.Hex(), err)
+		return nil, nil
+	}
+	if !propuesta.pendiente() {
+		return nil, ErrSinPropuestaCustodia
+	}
+	if propuesta.destinatario != cuenta {
+		return nil, fmt.Errorf("%w: %s (destinatario %s)", ErrNoDestinatario, cuenta.Hex(), propuesta.destinatario.Hex())
+	}
+	if aceptar {
+		ahora, err := bs.ahoraCadena()
+		if err != nil {
+			return nil, err
+		}
+		if propuesta.vencida(ahora) {
+			return nil, fmt.Errorf("%w: expiró en %s", ErrPropuestaVencida, time.Unix(int64(propuesta.expira), 0).UTC().Format(time.RFC3339))
+		}
+	}
+	return propuesta, nil
+}
+
+// ProponerCustodia propone entregar el lote a un distribuidor o farmacia con
+// los detalles del envío. La custodia no cambia hasta que el destinatario
+// acepta la propuesta dentro del plazo. Solo el propietario actual.
+func (bs *BlockchainService) ProponerCustodia(firmante signer.Signer, contractAddress, destinatario string, plazo time.Duration, envio models.DetallesEnvio) (*models.TransaccionEnviada, error) {
+	if plazo < time.Second || plazo > PlazoCustodiaMaximo {
+		return nil, fmt.Errorf("%w: %s (máximo %s)", ErrPlazoCustodiaInvalido, plazo, PlazoCustodiaMaximo)
+	}
+	toAddress := common.HexToAddress(contractAddress)
+	// La custodia solo pasa a distribuidores y farmacias
+	if err := bs.verificarRol(toAddress, common.HexToAddress(destinatario), RolDistribuidor, RolFarmacia); err != nil {
+		return nil, err
+	}
+	contract, err := loteTracingTransactor(toAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	detalles, err := json.Marshal(envio)
+	if err != nil {
+		return nil, fmt.Errorf("error codificando detalles del envío: %v", err)
+	}
+	data, err := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.ProponerCustodia(opts, common.HexToAddress(destinatario), uint64(plazo/time.Second), string(detalles))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sent, err := bs.enviar(TxRequest{
+		Signer:    firmante,
+		Operation: OpProponerCustodia,
+		To:        &toAddress,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sent.Info, nil
+}
+
+// AceptarCustodia acepta la propuesta pendiente con la cuenta del destinatario,
+// que pasa a ser el propietario del lote
+func (bs *BlockchainService) AceptarCustodia(firmante signer.Signer, contractAddress string) (*models.TransaccionEnviada, error) {
+	toAddress := common.HexToAddress(contractAddress)
+	if _, err := bs.verificarDestinatario(toAddress, firmante.Address(), true); err != nil {
+		return nil, err
+	}
+	contract, err := loteTracingTransactor(toAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.AceptarCustodia(opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sent, err := bs.enviar(TxRequest{
+		Signer:    firmante,
+		Operation: OpAceptarCustodia,
+		To:        &toAddress,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sent.Info, nil
+}
+
+// AceptarCustodiaFirmada envía la aceptación que el destinatario firmó con
+// EIP-712 sobre hashAceptacionCustodia. firmante solo paga el gas. La firma se
+// comprueba antes de enviar para no gastar gas en una firma ajena.
+func (bs *BlockchainService) AceptarCustodiaFirmada(firmante signer.Signer, contractAddress string, firma []byte) (*models.TransaccionEnviada, error) {
+	if len(firma) != crypto.SignatureLength {
+		return nil, fmt.Errorf("%w: se esperaban %d bytes, se recibieron %d", ErrFirmaInvalida, crypto.SignatureLength, len(firma))
+	}
+	r, s, v := common.BytesToHash(firma[:32]), common.BytesToHash(firma[32:64]), firma[64]
+	// Las carteras firman con v = 27 o 28; ecrecover no acepta 0 o 1
+	if v < 27 {
+		v += 27
+	}
+
+	toAddress := common.HexToAddress(contractAddress)
+	contract, err := bs.loteCaller(toAddress)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := contract.HashAceptacionCustodia(&bind.CallOpts{Context: context.Background()})
+	if err != nil {
+		return nil, fmt.Errorf("error consultando hash de aceptación: %v", err)
+	}
+	receptor, err := recuperarFirmante(hash[:], r, s, v)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := bs.verificarDestinatario(toAddress, receptor, true); err != nil {
+		if errors.Is(err, ErrNoDestinatario) {
+			return nil, fmt.Errorf("%w: firmada por %s", ErrFirmaInvalida, receptor.Hex())
+		}
+		return nil, err
+	}
+
+	transactor, err := loteTracingTransactor(toAddress)
+	if err != nil {
+		return nil, err
+	}
+	data, err := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return transactor.AceptarCustodiaFirmada(opts, v, r, s)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sent, err := bs.enviar(TxRequest{
+		Signer:    firmante,
+		Operation: OpAceptarCustodia,
+		To:        &toAddress,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sent.Info, nil
+}
+
+// recuperarFirmante devuelve la cuenta que firmó hash, como ecrecover
+func recuperarFirmante(hash []byte, r, s common.Hash, v uint8) (common.Address, error) {
+	if v != 27 && v != 28 {
+		return common.Address{}, fmt.Errorf("%w: v = %d", ErrFirmaInvalida, v)
+	}
+	// El contrato rechaza las firmas maleables (s alto), como ecrecover con EIP-2
+	if !crypto.ValidateSignatureValues(v-27, r.Big(), s.Big(), true) {
+		return common.Address{}, fmt.Errorf("%w: r o s fuera de rango", ErrFirmaInvalida)
+	}
+	firma := append(append(r.Bytes(), s.Bytes()...), v-27)
+	pubKey, err := crypto.SigToPub(hash, firma)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrFirmaInvalida, err)
+	}
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
+
+// RechazarCustodia rechaza la propuesta pendiente con la cuenta del
+// destinatario; la custodia no cambia
+func (bs *BlockchainService) RechazarCustodia(firmante signer.Signer, contractAddress, motivo string) (*models.TransaccionEnviada, error) {
+	toAddress := common.HexToAddress(contractAddress)
+	if _, err := bs.verificarDestinatario(toAddress, firmante.Address(), false); err != nil {
+		return nil, err
+	}
+	contract, err := loteTracingTransactor(toAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.RechazarCustodia(opts, motivo)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sent, err := bs.enviar(TxRequest{
+		Signer:    firmante,
+		Operation: OpRechazarCustodia,
+		To:        &toAddress,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sent.Info, nil
+}
+
+// CancelarCustodia retira la propuesta pendiente. Solo el propietario actual.
+func (bs *BlockchainService) CancelarCustodia(firmante signer.Signer, contractAddress string) (*models.TransaccionEnviada, error) {
+	toAddress := common.HexToAddress(contractAddress)
+	if propuesta, err := bs.leerPropuesta(toAddress); err == nil && !propuesta.pendiente() {
+		return nil, ErrSinPropuestaCustodia
+	}
+	contract, err := loteTracingTransactor(toAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.CancelarCustodia(opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sent, err := bs.enviar(TxRequest{
+		Signer:    firmante,
+		Operation: OpCancelarCustodia,
+		To:        &toAddress,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sent.Info, nil
+}
+
+// ObtenerPropuestaCustodia devuelve la propuesta de custodia del contrato con
+// los detalles del envío, tomados del evento CustodiaPropuesta indexado, y los
+// datos tipados EIP-712 que firma el destinatario para aceptarla
+func (bs *BlockchainService) ObtenerPropuestaCustodia(contractAddress string) (*models.PropuestaCustodia, error) {
+	contractAddr := common.HexToAddress(contractAddress)
+	code, err := bs.Client.CodeAt(context.Background(), contractAddr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error verificando contrato: %v", err)
+	}
+	if len(code) == 0 {
+		return nil, ErrContractNotFound
+	}
+
+	contract, err := bs.loteCaller(contractAddr)
+	if err != nil {
+		return nil, err
+	}
+	callOpts := &bind.CallOpts{Context: context.Background()}
+	propietario, err := contract.PropietarioActual(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo propietario actual: %v", err)
+	}
+	propuesta, err := bs.leerPropuesta(contractAddr)
+	if err != nil {
+		return nil, err
+	}
+	ahora, err := bs.ahoraCadena()
+	if err != nil {
+		return nil, err
+	}
+
+	respuesta := &models.PropuestaCustodia{
+		ContractAddress:   contractAddr.Hex(),
+		PropietarioActual: propietario.Hex(),
+		Propuesta:         propuesta.numero,
+		Pendiente:         propuesta.pendiente() && !propuesta.vencida(ahora),
+		Vencida:           propuesta.vencida(ahora),
+	}
+	if !propuesta.pendiente() {
+		return respuesta, nil
+	}
+
+	respuesta.Destinatario = propuesta.destinatario.Hex()
+	respuesta.Expira = int64(propuesta.expira)
+	respuesta.Envio = propuesta.envio.Hex()
+	respuesta.DetallesEnvio = bs.detallesEnvio(contractAddr, propuesta)
+
+	typedData := TypedDataAceptacionCustodia(bs.chainID, contractAddr, propuesta.numero, propuesta.destinatario, propuesta.envio)
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("error calculando hash EIP-712: %v", err)
+	}
+	respuesta.HashAceptacion = common.BytesToHash(hash).Hex()
+	respuesta.TypedData = typedData
+	return respuesta, nil
+}
+
+// detallesEnvio busca en el índice de eventos los detalles publicados con la
+// propuesta. Devuelve nil si no están indexados o no coinciden con su hash.
+func (bs *BlockchainService) detallesEnvio(contractAddr common.Address, propuesta *estadoPropuesta) *models.DetallesEnvio {
+	contrato, _, err := bs.indexer.Eventos(context.Background(), contractAddr)
+	if err != nil {
+		log.Printf("No se pudieron obtener los eventos de %s: %v", contractAddr.Hex(), err)
+		return nil
+	}
+	for i := len(contrato.Eventos) - 1; i >= 0; i-- {
+		evento := contrato.Eventos[i]
+		if evento.TipoEvento != "CustodiaPropuesta" || datoUint64(evento.Datos["propuesta"]) != uint64(propuesta.numero) {
+			continue
+		}
+		texto, _ := evento.Datos["detallesEnvio"].(string)
+		if crypto.Keccak256Hash([]byte(texto)) != propuesta.envio {
+			return nil
+		}
+		var detalles models.DetallesEnvio
+		if err := json.Unmarshal([]byte(texto), &detalles); err != nil {
+			return &models.DetallesEnvio{Notas: texto}
+		}
+		return &detalles
+	}
+	return nil
+}
+
+// TypedDataAceptacionCustodia construye los datos tipados EIP-712 con los que
+// el destinatario acepta la propuesta, en el dominio del contrato LoteTracing
+func TypedDataAceptacionCustodia(chainID *big.Int, contractAddr common.Address, propuesta uint32, receptor common.Address, envio common.Hash) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"AceptacionCustodia": {
+				{Name: "propuesta", Type: "uint32"},
+				{Name: "receptor", Type: "address"},
+				{Name: "envio", Type: "bytes32"},
+			},
+		},
+		PrimaryType: "AceptacionCustodia",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "LoteTracing",
+			Version:           "1",
+			ChainId:           (*math.HexOrDecimal256)(new(big.Int).Set(chainID)),
+			VerifyingContract: contractAddr.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"propuesta": strconv.FormatUint(uint64(propuesta), 10),
+			"receptor":  receptor.Hex(),
+			"envio":     envio.Hex(),
+		},
+	}
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/signer"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// newTestCustodia despliega un lote con un distribuidor y una farmacia
+func newTestCustodia(t *testing.T) (*SimulatedChain, *BlockchainService, string, map[string]signer.Signer) {
+	t.Helper()
+	cuentas := make(map[string]signer.Signer)
+	var addresses []common.Address
+	for _, nombre := range []string{"fabricante", "distribuidor", "farmacia"} {
+		cuenta, _ := signer.NewDevSigner(nombre)
+		cuentas[nombre] = cuenta
+		addresses = append(addresses, cuenta.Address())
+	}
+
+	chain := NewSimulatedChain(SimulatedChainOptions{Accounts: addresses})
+	t.Cleanup(func() { chain.Close() })
+	bs, err := NewBlockchainServiceWithClient(chain, 1337, NonceManagerOptions{}, TxTrackerOptions{}, EventIndexerOptions{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	contractAddress, _, err := bs.DeployContract(cuentas["fabricante"], "LOTE001", 2, 8)
+	if err != nil {
+		t.Fatalf("Failed to deploy contract: %v", err)
+	}
+	for _, rol := range []string{RolDistribuidor, RolFarmacia} {
+		if _, err := bs.OtorgarRol(cuentas["fabricante"], contractAddress, rol, cuentas[rol].Address().Hex()); err != nil {
+			t.Fatalf("Expected role %s to be granted, got %v", rol, err)
+		}
+	}
+	return chain, bs, contractAddress, cuentas
+}
+
+func propietarioActual(t *testing.T, bs *BlockchainService, contractAddress string) common.Address {
+	t.Helper()
+	contract, _ := bs.loteCaller(common.HexToAddress(contractAddress))
+	propietario, err := contract.PropietarioActual(&bind.CallOpts{Context: context.Background()})
+	if err != nil {
+		t.Fatalf("Failed to read owner: %v", err)
+	}
+	return propietario
+}
+
+func TestCustodia_ProposeAndAccept(t *testing.T) {
+	_, bs, contractAddress, cuentas := newTestCustodia(t)
+	fabricante, distribuidor := cuentas["fabricante"], cuentas["distribuidor"]
+	envio := models.DetallesEnvio{Guia: "GUIA-001", Transportista: "FrioExpress", Bultos: 3}
+
+	if _, err := bs.ProponerCustodia(fabricante, contractAddress, distribuidor.Address().Hex(), 0, envio); !errors.Is(err, ErrPlazoCustodiaInvalido) {
+		t.Errorf("Expected ErrPlazoCustodiaInvalido, got %v", err)
+	}
+	if _, err := bs.AceptarCustodia(distribuidor, contractAddress); !errors.Is(err, ErrSinPropuestaCustodia) {
+		t.Errorf("Expected ErrSinPropuestaCustodia, got %v", err)
+	}
+	if _, err := bs.ProponerCustodia(fabricante, contractAddress, distribuidor.Address().Hex(), 48*time.Hour, envio); err != nil {
+		t.Fatalf("Expected custody to be proposed, got %v", err)
+	}
+	if _, err := bs.ProponerCustodia(fabricante, contractAddress, cuentas["farmacia"].Address().Hex(), time.Hour, envio); err == nil {
+		t.Error("Expected a second proposal to revert while the first is pending")
+	}
+
+	propuesta, err := bs.ObtenerPropuestaCustodia(contractAddress)
+	if err != nil {
+		t.Fatalf("Failed to get proposal: %v", err)
+	}
+	if !propuesta.Pendiente || propuesta.Propuesta != 1 || propuesta.Destinatario != distribuidor.Address().Hex() || propuesta.PropietarioActual != fabricante.Address().Hex() {
+		t.Errorf("Unexpected proposal %+v", propuesta)
+	}
+	if propuesta.DetallesEnvio == nil || *propuesta.DetallesEnvio != envio {
+		t.Errorf("Expected shipment details from the indexed event, got %+v", propuesta.DetallesEnvio)
+	}
+
+	// El hash de los datos tipados coincide con el del contrato
+	contract, _ := bs.loteCaller(common.HexToAddress(contractAddress))
+	hash, _ := contract.HashAceptacionCustodia(&bind.CallOpts{})
+	if propuesta.HashAceptacion != common.Hash(hash).Hex() {
+		t.Errorf("Expected EIP-712 hash %s, got %s", common.Hash(hash).Hex(), propuesta.HashAceptacion)
+	}
+
+	if _, err := bs.AceptarCustodia(cuentas["farmacia"], contractAddress); !errors.Is(err, ErrNoDestinatario) {
+		t.Errorf("Expected ErrNoDestinatario, got %v", err)
+	}
+	if propietarioActual(t, bs, contractAddress) != fabricante.Address() {
+		t.Error("Expected custody to stay with the owner until accepted")
+	}
+	if _, err := bs.AceptarCustodia(distribuidor, contractAddress); err != nil {
+		t.Fatalf("Expected custody to be accepted, got %v", err)
+	}
+	if propietarioActual(t, bs, contractAddress) != distribuidor.Address() {
+		t.Error("Expected the distributor to own the lote after accepting")
+	}
+
+	propuesta, _ = bs.ObtenerPropuestaCustodia(contractAddress)
+	if propuesta.Pendiente || propuesta.Destinatario != "" || propuesta.Propuesta != 1 {
+		t.Errorf("Expected the proposal to be cleared, got %+v", propuesta)
+	}
+}
+
+func TestCustodia_AcceptsRelayedSignature(t *testing.T) {
+	_, bs, contractAddress, cuentas := newTestCustodia(t)
+	fabricante, farmacia := cuentas["fabricante"], cuentas["farmacia"]
+
+	if _, err := bs.ProponerCustodia(fabricante, contractAddress, farmacia.Address().Hex(), time.Hour, models.DetallesEnvio{Guia: "GUIA-002"}); err != nil {
+		t.Fatalf("Expected custody to be proposed, got %v", err)
+	}
+	propuesta, err := bs.ObtenerPropuestaCustodia(contractAddress)
+	if err != nil {
+		t.Fatalf("Failed to get proposal: %v", err)
+	}
+	hash, _, err := apitypes.TypedDataAndHash(propuesta.TypedData.(apitypes.TypedData))
+	if err != nil {
+		t.Fatalf("Failed to hash typed data: %v", err)
+	}
+
+	// Una firma de otra cuenta se rechaza antes de enviar
+	ajena, _ := crypto.Sign(hash, devKey(t, "distribuidor"))
+	if _, err := bs.AceptarCustodiaFirmada(fabricante, contractAddress, ajena); !errors.Is(err, ErrFirmaInvalida) {
+		t.Errorf("Expected ErrFirmaInvalida for another account's signature, got %v", err)
+	}
+
+	firma, _ := crypto.Sign(hash, devKey(t, "farmacia"))
+
+	// La otra firma válida de la misma clave (s' = n - s) se rechaza, también en el contrato
+	maleable := append([]byte(nil), firma...)
+	copy(maleable[32:64], common.BigToHash(new(big.Int).Sub(crypto.S256().Params().N, new(big.Int).SetBytes(firma[32:64]))).Bytes())
+	maleable[64] ^= 1
+	if _, err := bs.AceptarCustodiaFirmada(fabricante, contractAddress, maleable); !errors.Is(err, ErrFirmaInvalida) {
+		t.Errorf("Expected ErrFirmaInvalida for a high-s signature, got %v", err)
+	}
+	toAddress := common.HexToAddress(contractAddress)
+	for nombre, v := range map[string][]byte{"high-s": maleable, "zero signer": make([]byte, 65)} {
+		transactor, _ := loteTracingTransactor(toAddress)
+		data, _ := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return transactor.AceptarCustodiaFirmada(opts, v[64]+27, common.BytesToHash(v[:32]), common.BytesToHash(v[32:64]))
+		})
+		if _, err := bs.enviar(TxRequest{Signer: fabricante, Operation: OpAceptarCustodia, To: &toAddress, Data: data}); err == nil ||
+			!strings.Contains(err.Error(), "Firma invalida") {
+			t.Errorf("Expected the contract to reject a %s signature, got %v", nombre, err)
+		}
+	}
+
+	if _, err := bs.AceptarCustodiaFirmada(fabricante, contractAddress, firma); err != nil {
+		t.Fatalf("Expected the relayed acceptance to succeed, got %v", err)
+	}
+	if propietarioActual(t, bs, contractAddress) != farmacia.Address() {
+		t.Error("Expected the pharmacy to own the lote")
+	}
+
+	// La firma no se puede reutilizar
+	if _, err := bs.AceptarCustodiaFirmada(fabricante, contractAddress, firma); !errors.Is(err, ErrSinPropuestaCustodia) {
+		t.Errorf("Expected a replayed signature to fail, got %v", err)
+	}
+
+	contrato, _, err := bs.indexer.Eventos(context.Background(), common.HexToAddress(contractAddress))
+	if err != nil {
+		t.Fatalf("Failed to get events: %v", err)
+	}
+	ultimos := contrato.Eventos[len(contrato.Eventos)-2:]
+	if ultimos[0].TipoEvento != "CustodiaAceptada" || ultimos[0].Datos["firmada"] != true || ultimos[1].TipoEvento != "CustodiaTransferida" {
+		t.Errorf("Expected CustodiaAceptada and CustodiaTransferida, got %+v", ultimos)
+	}
+}
+
+func TestCustodia_RejectCancelAndExpire(t *testing.T) {
+	chain, bs, contractAddress, cuentas := newTestCustodia(t)
+	fabricante, distribuidor := cuentas["fabricante"], cuentas["distribuidor"]
+	destinatario := distribuidor.Address().Hex()
+
+	if _, err := bs.ProponerCustodia(fabricante, contractAddress, destinatario, time.Hour, models.DetallesEnvio{}); err != nil {
+		t.Fatalf("Expected custody to be proposed, got %v", err)
+	}
+	if _, err := bs.RechazarCustodia(cuentas["farmacia"], contractAddress, "No es para mí"); !errors.Is(err, ErrNoDestinatario) {
+		t.Errorf("Expected ErrNoDestinatario, got %v", err)
+	}
+	if _, err := bs.RechazarCustodia(distribuidor, contractAddress, "Temperatura de llegada fuera de rango"); err != nil {
+		t.Fatalf("Expected the proposal to be rejected, got %v", err)
+	}
+	if _, err := bs.CancelarCustodia(fabricante, contractAddress); !errors.Is(err, ErrSinPropuestaCustodia) {
+		t.Errorf("Expected ErrSinPropuestaCustodia, got %v", err)
+	}
+
+	if _, err := bs.ProponerCustodia(fabricante, contractAddress, destinatario, time.Hour, models.DetallesEnvio{}); err != nil {
+		t.Fatalf("Expected a new proposal after the rejection, got %v", err)
+	}
+	if _, err := bs.CancelarCustodia(distribuidor, contractAddress); err == nil {
+		t.Error("Expected only the owner to cancel")
+	}
+	if _, err := bs.CancelarCustodia(fabricante, contractAddress); err != nil {
+		t.Fatalf("Expected the proposal to be cancelled, got %v", err)
+	}
+
+	if _, err := bs.ProponerCustodia(fabricante, contractAddress, destinatario, time.Hour, models.DetallesEnvio{}); err != nil {
+		t.Fatalf("Expected a third proposal, got %v", err)
+	}
+	chain.AdjustTime(2 * time.Hour)
+	chain.Commit()
+	if _, err := bs.AceptarCustodia(distribuidor, contractAddress); !errors.Is(err, ErrPropuestaVencida) {
+		t.Errorf("Expected ErrPropuestaVencida, got %v", err)
+	}
+	if propuesta, _ := bs.ObtenerPropuestaCustodia(contractAddress); !propuesta.Vencida || propuesta.Pendiente || propuesta.Propuesta != 3 {
+		t.Errorf("Expected an expired proposal, got %+v", propuesta)
+	}
+	// Una propuesta vencida no impide proponer otra
+	if _, err := bs.ProponerCustodia(fabricante, contractAddress, destinatario, time.Hour, models.DetallesEnvio{}); err != nil {
+		t.Errorf("Expected a new proposal after expiry, got %v", err)
+	}
+	if propietarioActual(t, bs, contractAddress) != fabricante.Address() {
+		t.Error("Expected the owner to keep custody")
+	}
+}
+
+// devKey deriva la clave de una cuenta de desarrollo como signer.NewDevSigner
+func devKey(t *testing.T, nombre string) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte("CrearLoteMicro/dev/" + nombre)))
+	if err != nil {
+		t.Fatalf("Failed to derive key: %v", err)
+	}
+	return key
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"CrearLoteMicro/models"
+	"context"
+	"fmt"
+	"time"
+)
+
+// EventosDominio convierte el historial indexado de los contratos en los
+// eventos de dominio que se publican a la plataforma: lote.compromised por
+// cada LoteComprometido y lote.custody_transferred por cada
+// CustodiaTransferida. Solo incluye los eventos con al menos confirmaciones
+// bloques, para no publicar nada que una reorganización pueda deshacer, y
+// devuelve también el último bloque confirmado. Antes se indexan los bloques
+// nuevos. Los eventos de cada contrato van en el orden de la cadena.
+func (bs *BlockchainService) EventosDominio(red string, confirmaciones uint64) ([]models.EventoDominioLote, uint64, error) {
+	if confirmaciones == 0 {
+		confirmaciones = 1
+	}
+	if err := bs.indexer.Sincronizar(context.Background()); err != nil {
+		return nil, 0, err
+	}
+	tip, ok := bs.indexer.UltimoBloque()
+	if !ok || tip+1 < confirmaciones {
+		return nil, 0, nil
+	}
+	confirmado := tip + 1 - confirmaciones
+
+	chainID := bs.chainID.Int64()
+	var eventos []models.EventoDominioLote
+	for _, contrato := range bs.indexer.Contratos() {
+		// El loteId de cada evento es el del último LoteCreado anterior
+		var loteID string
+		for _, evento := range contrato.Eventos {
+			if evento.BlockNumber > confirmado {
+				break
+			}
+
+			dominio := models.EventoDominioLote{
+				EventID:         fmt.Sprintf("%d:%s:%d", chainID, evento.TxHash, evento.LogIndex),
+				LoteID:          loteID,
+				ContractAddress: contrato.ContractAddress,
+				Network:         red,
+				ChainID:         chainID,
+				TxHash:          evento.TxHash,
+				BlockNumber:     evento.BlockNumber,
+				LogIndex:        evento.LogIndex,
+				Timestamp:       time.Unix(int64(evento.Timestamp), 0).UTC(),
+			}
+			motivo, _ := evento.Datos["motivo"].(string)
+			switch evento.TipoEvento {
+			case "LoteCreado":
+				loteID, _ = evento.Datos["loteId"].(string)
+				continue
+			case "LoteComprometido":
+				propietario, _ := evento.Datos["propietario"].(string)
+				dominio.EventType = models.EventoLoteComprometido
+				dominio.Compromise = &models.CompromisoLote{
+					Owner:   propietario,
+					TempMin: datoInt8(evento.Datos["tempMin"]),
+					TempMax: datoInt8(evento.Datos["tempMax"]),
+					Reason:  motivo,
+				}
+			case "CustodiaTransferida":
+				anterior, _ := evento.Datos["propietarioAnterior"].(string)
+				nuevo, _ := evento.Datos["nuevoPropietario"].(string)
+				comprometido, _ := evento.Datos["comprometido"].(bool)
+				dominio.EventType = models.EventoLoteCustodiaTransferida
+				dominio.Custody = &models.TraspasoCustodiaLote{
+					PreviousOwner: anterior,
+					NewOwner:      nuevo,
+					Compromised:   comprometido,
+					Reason:        motivo,
+				}
+			default:
+				continue
+			}
+			eventos = append(eventos, dominio)
+		}
+	}
+	return eventos, confirmado, nil
+}
@@ -0,0 +1,368 @@
+package services
+
+import (
+	"CrearLoteMicro/bindings"
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/signer"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrFactoryNotConfigured se devuelve cuando la red no tiene una LoteTracingFactory
+var ErrFactoryNotConfigured = errors.New("la red no tiene un contrato LoteTracingFactory configurado")
+
+// ErrLoteExists se devuelve al crear en la factory un loteId que ya existe
+var ErrLoteExists = errors.New("el loteId ya existe en la factory")
+
+// loteTracingFactoryTransactor crea el transactor de la factory, que solo se
+// usa para empaquetar llamadas con calldata
+func loteTracingFactoryTransactor(factoryAddr common.Address) (*bindings.LoteTracingFactoryTransactor, error) {
+	transactor, err := bindings.NewLoteTracingFactoryTransactor(factoryAddr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creando binding de la factory: %v", err)
+	}
+	return transactor, nil
+}
+
+// Factory devuelve la dirección de la LoteTracingFactory de la red
+func (bs *BlockchainService) Factory() (common.Address, error) {
+	bs.factoryMu.RLock()
+	defer bs.factoryMu.RUnlock()
+	if bs.factory == (common.Address{}) {
+		return common.Address{}, ErrFactoryNotConfigured
+	}
+	return bs.factory, nil
+}
+
+// UsarFactory configura la LoteTracingFactory ya desplegada de la red
+func (bs *BlockchainService) UsarFactory(factoryAddress string) error {
+	factoryAddr := common.HexToAddress(factoryAddress)
+	code, err := bs.Client.CodeAt(context.Background(), factoryAddr, nil)
+	if err != nil {
+		return fmt.Errorf("error verificando factory: %v", err)
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("%w: %s", ErrContractNotFound, factoryAddr.Hex())
+	}
+
+	bs.factoryMu.Lock()
+	defer bs.factoryMu.Unlock()
+	bs.factory = factoryAddr
+	return nil
+}
+
+// DesplegarFactory despliega una LoteTracingFactory cuyo administrador y primer
+// fabricante es el firmante, y la usa como factory de la red
+func (bs *BlockchainService) DesplegarFactory(firmante signer.Signer) (string, *models.TransaccionEnviada, error) {
+	data, err := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		_, tx, _, err := bindings.DeployLoteTracingFactory(opts, nil)
+		return tx, err
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	sent, err := bs.enviar(TxRequest{
+		Signer:    firmante,
+		Operation: OpDeployFactory,
+		Data:      data,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	factoryAddr := crypto.CreateAddress(firmante.Address(), sent.Tx.Nonce())
+	bs.factoryMu.Lock()
+	bs.factory = factoryAddr
+	bs.factoryMu.Unlock()
+
+	return factoryAddr.Hex(), sent.Info, nil
+}
+
+// enviarFactory empaqueta una llamada a la factory y la envía en la cola del firmante
+func (bs *BlockchainService) enviarFactory(firmante signer.Signer, operation string, call func(contract *bindings.LoteTracingFactoryTransactor, opts *bind.TransactOpts) (*types.Transaction, error)) (*models.TransaccionEnviada, error) {
+	factoryAddr, err := bs.Factory()
+	if err != nil {
+		return nil, err
+	}
+	contract, err := loteTracingFactoryTransactor(factoryAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return call(contract, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sent, err := bs.enviar(TxRequest{
+		Signer:    firmante,
+		Operation: operation,
+		To:        &factoryAddr,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sent.Info, nil
+}
+
+// factoryCaller crea el binding de solo lectura de la factory de la red
+func (bs *BlockchainService) factoryCaller() (common.Address, *bindings.LoteTracingFactoryCaller, error) {
+	factoryAddr, err := bs.Factory()
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	contract, err := bindings.NewLoteTracingFactoryCaller(factoryAddr, bs.Client)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("error creando binding de la factory: %v", err)
+	}
+	return factoryAddr, contract, nil
+}
+
+// CrearLoteFactory crea un lote en la factory. A diferencia de DeployContract
+// no despliega un contrato, y la factory rechaza los loteId ya creados.
+func (bs *BlockchainService) CrearLoteFactory(firmante signer.Signer, loteID string, tempMin, tempMax int8) (*models.TransaccionEnviada, error) {
+	_, contract, err := bs.factoryCaller()
+	if err != nil {
+		return nil, err
+	}
+	existe, err := contract.ExisteLote(&bind.CallOpts{Context: context.Background()}, loteID)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando lote en la factory: %v", err)
+	}
+	if existe {
+		return nil, fmt.Errorf("%w: %s", ErrLoteExists, loteID)
+	}
+
+	return bs.enviarFactory(firmante, OpCrearLote, func(contract *bindings.LoteTracingFactoryTransactor, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.CrearLote(opts, loteID, tempMin, tempMax)
+	})
+}
+
+// AutorizarFabricante concede o retira a una cuenta el permiso de crear
+// lotes en la factory; solo lo puede enviar el administrador
+func (bs *BlockchainService) AutorizarFabricante(firmante signer.Signer, cuenta string, autorizado bool) (*models.TransaccionEnviada, error) {
+	return bs.enviarFactory(firmante, OpAutorizarFabricante, func(contract *bindings.LoteTracingFactoryTransactor, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.AutorizarFabricante(opts, common.HexToAddress(cuenta), autorizado)
+	})
+}
+
+// InfoFactory devuelve el administrador y el número de lotes de la factory
+func (bs *BlockchainService) InfoFactory() (*models.FactoryInfo, error) {
+	factoryAddr, contract, err := bs.factoryCaller()
+	if err != nil {
+		return nil, err
+	}
+	callOpts := &bind.CallOpts{Context: context.Background()}
+
+	admin, err := contract.Admin(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo administrador de la factory: %v", err)
+	}
+	total, err := contract.TotalLotes(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo total de lotes: %v", err)
+	}
+
+	return &models.FactoryInfo{
+		FactoryAddress: factoryAddr.Hex(),
+		Admin:          admin.Hex(),
+		TotalLotes:     total.Uint64(),
+	}, nil
+}
+
+// EsFabricante indica si la cuenta puede crear lotes en la factory
+func (bs *BlockchainService) EsFabricante(cuenta string) (bool, error) {
+	_, contract, err := bs.factoryCaller()
+	if err != nil {
+		return false, err
+	}
+	autorizado, err := contract.Fabricantes(&bind.CallOpts{Context: context.Background()}, common.HexToAddress(cuenta))
+	if err != nil {
+		return false, fmt.Errorf("error consultando fabricante: %v", err)
+	}
+	return autorizado, nil
+}
+
+// ObtenerLoteFactory devuelve el estado de un lote de la factory
+func (bs *BlockchainService) ObtenerLoteFactory(loteID string) (*models.LoteFactoryInfo, error) {
+	factoryAddr, contract, err := bs.factoryCaller()
+	if err != nil {
+		return nil, err
+	}
+	callOpts := &bind.CallOpts{Context: context.Background()}
+
+	existe, err := contract.ExisteLote(callOpts, loteID)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando lote en la factory: %v", err)
+	}
+	if !existe {
+		return nil, ErrLoteNotFound
+	}
+
+	lote, err := contract.ObtenerLote(callOpts, loteID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo lote de la factory: %v", err)
+	}
+
+	return &models.LoteFactoryInfo{
+		LoteID:            loteID,
+		FactoryAddress:    factoryAddr.Hex(),
+		Fabricante:        lote.Fabricante.Hex(),
+		PropietarioActual: lote.PropietarioActual.Hex(),
+		TemperaturaMinima: lote.TemperaturaMinima,
+		TemperaturaMaxima: lote.TemperaturaMaxima,
+		TempRegMinima:     lote.TempRegMinima,
+		TempRegMaxima:     lote.TempRegMaxima,
+		Comprometido:      lote.Comprometido,
+		BloqueCreacion:    lote.BloqueCreacion,
+	}, nil
+}
+
+// ObtenerCadenaFactory devuelve el historial de eventos de un lote de la
+// factory. loteId es un topic de todos sus eventos, así que basta con
+// filtrar los logs de la factory desde el bloque de creación del lote.
+func (bs *BlockchainService) ObtenerCadenaFactory(loteID string) (*models.CadenaBlockchainResponse, error) {
+	lote, err := bs.ObtenerLoteFactory(loteID)
+	if err != nil {
+		return nil, err
+	}
+	factoryAddr := common.HexToAddress(lote.FactoryAddress)
+
+	ctx := context.Background()
+	hasta, err := bs.Client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo bloque actual: %v", err)
+	}
+
+	filterer, err := bindings.NewLoteTracingFactoryFilterer(factoryAddr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creando binding de la factory: %v", err)
+	}
+
+	loteIDHash := crypto.Keccak256Hash([]byte(loteID))
+	eventos := make([]models.EventoBlockchain, 0)
+	timestamps := make(map[uint64]uint64)
+	ventana := bs.indexer.options.BatchBlocks
+	for desde := lote.BloqueCreacion; desde <= hasta; {
+		fin := desde + ventana - 1
+		if fin > hasta {
+			fin = hasta
+		}
+
+		logs, err := bs.Client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(desde),
+			ToBlock:   new(big.Int).SetUint64(fin),
+			Addresses: []common.Address{factoryAddr},
+			Topics:    [][]common.Hash{nil, {loteIDHash}},
+		})
+		if err != nil {
+			// Igual que el indexador, se reduce la ventana si el proveedor la rechaza
+			if ventana > 1 {
+				ventana /= 2
+				continue
+			}
+			return nil, fmt.Errorf("error obteniendo logs %d-%d: %v", desde, fin, err)
+		}
+
+		for _, vLog := range logs {
+			tipo, datos, err := decodificarEventoFactory(filterer, vLog, loteID)
+			if err != nil {
+				continue
+			}
+			timestamp, ok := timestamps[vLog.BlockNumber]
+			if !ok {
+				header, err := bs.Client.HeaderByNumber(ctx, new(big.Int).SetUint64(vLog.BlockNumber))
+				if err != nil {
+					return nil, fmt.Errorf("error obteniendo bloque %d: %v", vLog.BlockNumber, err)
+				}
+				timestamp = header.Time
+				timestamps[vLog.BlockNumber] = timestamp
+			}
+			eventos = append(eventos, models.EventoBlockchain{
+				TipoEvento:  tipo,
+				BlockNumber: vLog.BlockNumber,
+				TxHash:      vLog.TxHash.Hex(),
+				Timestamp:   timestamp,
+				Datos:       datos,
+				BlockHash:   vLog.BlockHash.Hex(),
+				LogIndex:    vLog.Index,
+			})
+		}
+		desde = fin + 1
+	}
+
+	return &models.CadenaBlockchainResponse{
+		ContractAddress: factoryAddr.Hex(),
+		LoteID:          loteID,
+		TotalEventos:    len(eventos),
+		Eventos:         eventos,
+		IndexadoHasta:   hasta,
+	}, nil
+}
+
+// decodificarEventoFactory convierte un log de LoteTracingFactory en su tipo
+// y sus datos, con las mismas claves que decodificarEvento para LoteTracing
+func decodificarEventoFactory(filterer *bindings.LoteTracingFactoryFilterer, vLog types.Log, loteID string) (string, map[string]interface{}, error) {
+	contractABI, err := bindings.LoteTracingFactoryMetaData.GetAbi()
+	if err != nil {
+		return "", nil, fmt.Errorf("error parseando ABI: %v", err)
+	}
+	if len(vLog.Topics) == 0 {
+		return "", nil, fmt.Errorf("log sin topics")
+	}
+
+	datos := make(map[string]interface{})
+	switch vLog.Topics[0] {
+	case contractABI.Events["LoteCreado"].ID:
+		evento, err := filterer.ParseLoteCreado(vLog)
+		if err != nil {
+			return "", nil, err
+		}
+		datos["loteIdHash"] = evento.LoteId.Hex()
+		if loteID != "" && crypto.Keccak256Hash([]byte(loteID)) == evento.LoteId {
+			datos["loteId"] = loteID
+		}
+		datos["fabricante"] = evento.Fabricante.Hex()
+		datos["temperaturaMinima"] = evento.TemperaturaMinima
+		datos["temperaturaMaxima"] = evento.TemperaturaMaxima
+		datos["motivo"] = evento.Motivo
+		return "LoteCreado", datos, nil
+
+	case contractABI.Events["CustodiaTransferida"].ID:
+		evento, err := filterer.ParseCustodiaTransferida(vLog)
+		if err != nil {
+			return "", nil, err
+		}
+		datos["propietarioAnterior"] = evento.PropietarioAnterior.Hex()
+		datos["nuevoPropietario"] = evento.NuevoPropietario.Hex()
+		datos["comprometido"] = evento.Comprometido
+		datos["motivo"] = evento.Motivo
+		return "CustodiaTransferida", datos, nil
+
+	case contractABI.Events["LoteComprometido"].ID:
+		evento, err := filterer.ParseLoteComprometido(vLog)
+		if err != nil {
+			return "", nil, err
+		}
+		datos["propietario"] = evento.Propietario.Hex()
+		datos["tempMin"] = evento.TempMin
+		datos["tempMax"] = evento.TempMax
+		datos["comprometido"] = evento.Comprometido
+		datos["motivo"] = evento.Motivo
+		return "LoteComprometido", datos, nil
+	}
+
+	return "", nil, fmt.Errorf("evento desconocido %s", vLog.Topics[0].Hex())
+}
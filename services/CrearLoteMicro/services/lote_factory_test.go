@@ -0,0 +1,119 @@
+package services
+
+import (
+	"CrearLoteMicro/bindings"
+	"CrearLoteMicro/signer"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// newTestFactoryService crea un servicio sobre una blockchain simulada con la
+// factory desplegada por el fabricante
+func newTestFactoryService(t *testing.T) (*BlockchainService, signer.Signer, signer.Signer) {
+	t.Helper()
+	fabricante, _ := signer.NewDevSigner("fabricante")
+	distribuidor, _ := signer.NewDevSigner("distribuidor")
+
+	chain := NewSimulatedChain(SimulatedChainOptions{Accounts: []common.Address{fabricante.Address(), distribuidor.Address()}})
+	t.Cleanup(func() { chain.Close() })
+
+	bs, err := NewBlockchainServiceWithClient(chain, 1337, NonceManagerOptions{}, TxTrackerOptions{}, EventIndexerOptions{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	if _, err := bs.Factory(); !errors.Is(err, ErrFactoryNotConfigured) {
+		t.Fatalf("Expected ErrFactoryNotConfigured before deploying, got %v", err)
+	}
+	if _, _, err := bs.DesplegarFactory(fabricante); err != nil {
+		t.Fatalf("Failed to deploy factory: %v", err)
+	}
+	return bs, fabricante, distribuidor
+}
+
+func TestLoteFactory_CreatesLotesWithAccessControl(t *testing.T) {
+	bs, fabricante, distribuidor := newTestFactoryService(t)
+
+	if _, err := bs.CrearLoteFactory(fabricante, "LOTE001", 2, 8); err != nil {
+		t.Fatalf("Expected lote to be created, got %v", err)
+	}
+	if _, err := bs.CrearLoteFactory(fabricante, "LOTE001", 0, 4); !errors.Is(err, ErrLoteExists) {
+		t.Errorf("Expected ErrLoteExists for a duplicated loteId, got %v", err)
+	}
+	if _, err := bs.CrearLoteFactory(distribuidor, "LOTE002", 2, 8); err == nil || !strings.Contains(err.Error(), "Cuenta no autorizada como fabricante") {
+		t.Errorf("Expected unauthorised manufacturer to be rejected, got %v", err)
+	}
+	if _, err := bs.AutorizarFabricante(distribuidor, distribuidor.Address().Hex(), true); err == nil || !strings.Contains(err.Error(), "Accion solo permitida para el administrador") {
+		t.Errorf("Expected only the admin to authorise manufacturers, got %v", err)
+	}
+	if _, err := bs.AutorizarFabricante(fabricante, distribuidor.Address().Hex(), true); err != nil {
+		t.Fatalf("Expected admin to authorise manufacturer, got %v", err)
+	}
+	if _, err := bs.CrearLoteFactory(distribuidor, "LOTE002", -5, 5); err != nil {
+		t.Errorf("Expected authorised manufacturer to create lote, got %v", err)
+	}
+
+	info, err := bs.InfoFactory()
+	if err != nil || info.TotalLotes != 2 || info.Admin != fabricante.Address().Hex() {
+		t.Errorf("Expected 2 lotes administered by fabricante, got %+v (%v)", info, err)
+	}
+	lote, err := bs.ObtenerLoteFactory("LOTE002")
+	if err != nil || lote.Fabricante != distribuidor.Address().Hex() || lote.TemperaturaMinima != -5 || lote.BloqueCreacion == 0 {
+		t.Errorf("Expected LOTE002 made by distribuidor, got %+v (%v)", lote, err)
+	}
+	if _, err := bs.ObtenerLoteFactory("LOTE404"); !errors.Is(err, ErrLoteNotFound) {
+		t.Errorf("Expected ErrLoteNotFound, got %v", err)
+	}
+}
+
+func TestLoteFactory_LotesAreReadOnly(t *testing.T) {
+	bs, fabricante, distribuidor := newTestFactoryService(t)
+
+	if _, err := bs.CrearLoteFactory(fabricante, "LOTE001", 2, 8); err != nil {
+		t.Fatalf("Expected lote to be created, got %v", err)
+	}
+	if _, err := bs.CrearLoteFactory(fabricante, "LOTE002", 2, 8); err != nil {
+		t.Fatalf("Expected lote to be created, got %v", err)
+	}
+
+	// registrarTemperatura y transferirCustodia siguen en el ABI pero revierten
+	factoryAddr, _ := bs.Factory()
+	contractABI, _ := bindings.LoteTracingFactoryMetaData.GetAbi()
+	for _, call := range []struct {
+		method string
+		args   []interface{}
+	}{
+		{"registrarTemperatura", []interface{}{"LOTE001", int8(1), int8(12)}},
+		{"transferirCustodia", []interface{}{"LOTE001", distribuidor.Address()}},
+	} {
+		data, err := contractABI.Pack(call.method, call.args...)
+		if err != nil {
+			t.Fatalf("Failed to pack %s: %v", call.method, err)
+		}
+		_, err = bs.Client.CallContract(context.Background(), ethereum.CallMsg{From: fabricante.Address(), To: &factoryAddr, Data: data}, nil)
+		if err == nil || !strings.Contains(err.Error(), "Lotes de la factory de solo lectura") {
+			t.Errorf("Expected %s to revert, got %v", call.method, err)
+		}
+	}
+
+	lote, err := bs.ObtenerLoteFactory("LOTE001")
+	if err != nil || lote.Comprometido || lote.PropietarioActual != fabricante.Address().Hex() {
+		t.Errorf("Expected LOTE001 unchanged and owned by fabricante, got %+v (%v)", lote, err)
+	}
+
+	cadena, err := bs.ObtenerCadenaFactory("LOTE001")
+	if err != nil {
+		t.Fatalf("Expected lote history, got %v", err)
+	}
+	if cadena.TotalEventos != 1 || cadena.Eventos[0].TipoEvento != "LoteCreado" {
+		t.Fatalf("Expected only the LOTE001 creation, got %+v", cadena.Eventos)
+	}
+	creado := cadena.Eventos[0]
+	if creado.Datos["loteId"] != "LOTE001" || creado.Datos["temperaturaMaxima"] != int8(8) || creado.Timestamp == 0 {
+		t.Errorf("Expected decoded LoteCreado, got %+v", creado)
+	}
+}
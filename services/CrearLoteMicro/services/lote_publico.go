@@ -0,0 +1,52 @@
+package services
+
+import (
+	"CrearLoteMicro/models"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ResumenPublico devuelve el resumen de un contrato LoteTracing para la
+// verificación pública: el estado del lote y la custodia y las excursiones
+// desde su último LoteCreado, como en el certificado
+func (bs *BlockchainService) ResumenPublico(red, contractAddress string) (*models.ResumenPublicoLote, error) {
+	ctx := context.Background()
+	contractAddr := common.HexToAddress(contractAddress)
+	contrato, indexadoHasta, err := bs.indexer.Eventos(ctx, contractAddr)
+	if err != nil {
+		if errors.Is(err, ErrContractNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error obteniendo eventos indexados: %v", err)
+	}
+	lote, err := bs.leerEstadoLote(ctx, contractAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	certificado := models.CertificadoLote{Lote: *lote}
+	resumirEventos(&certificado, eventosLoteVigente(contrato.Eventos))
+
+	estado := models.EstadoLoteIntegro
+	if lote.Comprometido {
+		estado = models.EstadoLoteComprometido
+	}
+	return &models.ResumenPublicoLote{
+		LoteID:            lote.LoteID,
+		ContractAddress:   contractAddr.Hex(),
+		Red:               red,
+		Estado:            estado,
+		Comprometido:      lote.Comprometido,
+		CadenaFrioIntacta: certificado.CadenaFrioIntacta,
+		TemperaturaMinima: lote.TemperaturaMinima,
+		TemperaturaMaxima: lote.TemperaturaMaxima,
+		TotalLecturas:     certificado.TotalLecturas,
+		PropietarioActual: lote.PropietarioActual,
+		Custodia:          certificado.Custodia,
+		Excursiones:       certificado.Excursiones,
+		IndexadoHasta:     indexadoHasta,
+	}, nil
+}
@@ -0,0 +1,320 @@
+package services
+
+import (
+	"CrearLoteMicro/models"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrLoteNotFound se devuelve cuando el registro no conoce el loteId
+var ErrLoteNotFound = errors.New("lote no encontrado en el registro")
+
+// FiltroLotes selecciona entradas del registro; los campos vacíos no filtran
+type FiltroLotes struct {
+	Fabricante   string
+	Propietario  string
+	Comprometido *bool
+	// SoloVigentes descarta los lotes reemplazados por crearNuevoLote
+	SoloVigentes bool
+}
+
+// LoteRegistry relaciona cada loteId con su contrato LoteTracing. Se
+// alimenta de los despliegues del servicio y del historial del indexador de
+// eventos, por lo que también incluye lotes creados con otras herramientas.
+type LoteRegistry struct {
+	mu    sync.RWMutex
+	store LoteStore
+	// lotes agrupa por loteId; un mismo loteId puede estar en varios contratos
+	lotes map[string][]*models.RegistroLote
+}
+
+// NewLoteRegistry crea el registro y carga las entradas guardadas
+func NewLoteRegistry(store LoteStore) (*LoteRegistry, error) {
+	r := &LoteRegistry{
+		store: store,
+		lotes: make(map[string][]*models.RegistroLote),
+	}
+
+	if store != nil {
+		lotes, err := store.Load()
+		if err != nil {
+			return nil, err
+		}
+		for i := range lotes {
+			r.lotes[lotes[i].LoteID] = append(r.lotes[lotes[i].LoteID], &lotes[i])
+		}
+	}
+
+	return r, nil
+}
+
+// RegistrarDespliegue añade el lote de un contrato recién enviado. Queda con
+// BlockNumber 0 hasta que el indexador procese su LoteCreado.
+func (r *LoteRegistry) RegistrarDespliegue(lote models.RegistroLote) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existente := range r.lotes[lote.LoteID] {
+		if strings.EqualFold(existente.ContractAddress, lote.ContractAddress) {
+			return
+		}
+	}
+	lote.Vigente = true
+	r.lotes[lote.LoteID] = append(r.lotes[lote.LoteID], &lote)
+	r.saveLocked()
+}
+
+// ResolverDespliegue retira el lote de un despliegue aún no indexado cuando
+// su transacción es definitiva sin haber creado el contrato: revertida,
+// cancelada o con el nonce consumido por otra transacción. Si el servicio la
+// aceleró, la entrada pasa a seguir a la transacción que la reemplazó. Se usa
+// como TxTrackerOptions.OnFinal.
+func (r *LoteRegistry) ResolverDespliegue(estado models.EstadoTransaccion) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cambios := false
+	for loteID, lotes := range r.lotes {
+		restantes := lotes[:0]
+		for _, lote := range lotes {
+			if lote.BlockNumber != 0 || !strings.EqualFold(lote.TxHash, estado.TxHash) {
+				restantes = append(restantes, lote)
+				continue
+			}
+			switch {
+			case estado.Estado == models.EstadoConfirmada && estado.Operacion == OpDeploy:
+				// El indexador completará la entrada con su LoteCreado
+			case estado.Estado == models.EstadoReemplazada && estado.ReemplazadaPor != "":
+				lote.TxHash = estado.ReemplazadaPor
+				cambios = true
+			default:
+				log.Printf("Despliegue de %s en %s descartado: transacción %s %s", loteID, lote.ContractAddress, estado.TxHash, estado.Estado)
+				cambios = true
+				continue
+			}
+			restantes = append(restantes, lote)
+		}
+		if len(restantes) == 0 {
+			delete(r.lotes, loteID)
+		} else {
+			r.lotes[loteID] = restantes
+		}
+	}
+	if cambios {
+		r.saveLocked()
+	}
+}
+
+// Actualizar recalcula las entradas de un contrato a partir de su historial
+// de eventos. Se usa como EventIndexerOptions.OnUpdate.
+func (r *LoteRegistry) Actualizar(contrato models.ContratoIndexado) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.actualizarLocked(contrato)
+	r.saveLocked()
+}
+
+// Completar recalcula las entradas de todos los contratos ya indexados, por
+// ejemplo al arrancar con un índice de eventos existente
+func (r *LoteRegistry) Completar(contratos []models.ContratoIndexado) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, contrato := range contratos {
+		r.actualizarLocked(contrato)
+	}
+	r.saveLocked()
+}
+
+func (r *LoteRegistry) actualizarLocked(contrato models.ContratoIndexado) {
+	indexados := lotesDeContrato(contrato)
+
+	// Los despliegues aún no indexados se conservan mientras el contrato no
+	// tenga ningún lote en el historial, por ejemplo tras una reorganización
+	for loteID, lotes := range r.lotes {
+		restantes := lotes[:0]
+		for _, lote := range lotes {
+			pendiente := lote.BlockNumber == 0 && len(indexados) == 0
+			if !strings.EqualFold(lote.ContractAddress, contrato.ContractAddress) || pendiente {
+				restantes = append(restantes, lote)
+			}
+		}
+		if len(restantes) == 0 {
+			delete(r.lotes, loteID)
+		} else {
+			r.lotes[loteID] = restantes
+		}
+	}
+	for _, lote := range indexados {
+		r.lotes[lote.LoteID] = append(r.lotes[lote.LoteID], lote)
+	}
+}
+
+// lotesDeContrato reconstruye los lotes de un contrato recorriendo sus eventos
+// en orden; cada LoteCreado reemplaza al lote anterior
+func lotesDeContrato(contrato models.ContratoIndexado) []*models.RegistroLote {
+	var indexados []*models.RegistroLote
+	var actual *models.RegistroLote
+	for _, evento := range contrato.Eventos {
+		switch evento.TipoEvento {
+		case "LoteCreado":
+			if actual != nil {
+				actual.Vigente = false
+			}
+			actual = nil
+
+			// Sin loteId en claro no se puede registrar el lote
+			loteID, _ := evento.Datos["loteId"].(string)
+			if loteID == "" {
+				continue
+			}
+			fabricante, _ := evento.Datos["fabricante"].(string)
+			propietario := evento.From
+			if propietario == "" {
+				propietario = fabricante
+			}
+			actual = &models.RegistroLote{
+				LoteID:            loteID,
+				ContractAddress:   contrato.ContractAddress,
+				Fabricante:        fabricante,
+				PropietarioActual: propietario,
+				TemperaturaMinima: datoInt8(evento.Datos["temperaturaMinima"]),
+				TemperaturaMaxima: datoInt8(evento.Datos["temperaturaMaxima"]),
+				Vigente:           true,
+				TxHash:            evento.TxHash,
+				BlockNumber:       evento.BlockNumber,
+				Timestamp:         evento.Timestamp,
+			}
+			indexados = append(indexados, actual)
+		case "CustodiaTransferida":
+			if actual != nil {
+				actual.PropietarioActual, _ = evento.Datos["nuevoPropietario"].(string)
+				actual.Comprometido, _ = evento.Datos["comprometido"].(bool)
+			}
+		case "LoteComprometido":
+			if actual != nil {
+				actual.Comprometido = true
+			}
+		}
+	}
+	return indexados
+}
+
+// PorLoteID devuelve el contrato de un loteId. Si el loteId se usó en varios
+// contratos se prefiere el vigente más reciente.
+func (r *LoteRegistry) PorLoteID(loteID string) (*models.RegistroLote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var elegido *models.RegistroLote
+	for _, lote := range r.lotes[loteID] {
+		if elegido == nil || masReciente(lote, elegido) {
+			elegido = lote
+		}
+	}
+	if elegido == nil {
+		return nil, ErrLoteNotFound
+	}
+	copia := *elegido
+	return &copia, nil
+}
+
+// Buscar devuelve los lotes que cumplen el filtro, del más reciente al más antiguo
+func (r *LoteRegistry) Buscar(filtro FiltroLotes) []models.RegistroLote {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	resultado := make([]models.RegistroLote, 0)
+	for _, lotes := range r.lotes {
+		for _, lote := range lotes {
+			if filtro.Fabricante != "" && !strings.EqualFold(lote.Fabricante, filtro.Fabricante) {
+				continue
+			}
+			if filtro.Propietario != "" && !strings.EqualFold(lote.PropietarioActual, filtro.Propietario) {
+				continue
+			}
+			if filtro.Comprometido != nil && lote.Comprometido != *filtro.Comprometido {
+				continue
+			}
+			if filtro.SoloVigentes && !lote.Vigente {
+				continue
+			}
+			resultado = append(resultado, *lote)
+		}
+	}
+
+	sort.Slice(resultado, func(i, j int) bool { return masReciente(&resultado[i], &resultado[j]) })
+	return resultado
+}
+
+// Contratos devuelve el contrato y el bloque de creación de cada lote
+// indexado, para que el indexador los siga tras un reinicio
+func (r *LoteRegistry) Contratos() map[string]uint64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	contratos := make(map[string]uint64)
+	for _, lotes := range r.lotes {
+		for _, lote := range lotes {
+			if lote.BlockNumber == 0 {
+				continue
+			}
+			if desde, ok := contratos[lote.ContractAddress]; !ok || lote.BlockNumber < desde {
+				contratos[lote.ContractAddress] = lote.BlockNumber
+			}
+		}
+	}
+	return contratos
+}
+
+func (r *LoteRegistry) saveLocked() {
+	if r.store == nil {
+		return
+	}
+
+	var lotes []models.RegistroLote
+	for _, entradas := range r.lotes {
+		for _, lote := range entradas {
+			lotes = append(lotes, *lote)
+		}
+	}
+	sort.Slice(lotes, func(i, j int) bool {
+		if lotes[i].LoteID != lotes[j].LoteID {
+			return lotes[i].LoteID < lotes[j].LoteID
+		}
+		return lotes[i].ContractAddress < lotes[j].ContractAddress
+	})
+
+	if err := r.store.Save(lotes); err != nil {
+		log.Printf("Error guardando registro de lotes: %v", err)
+	}
+}
+
+// masReciente ordena primero los lotes vigentes y después por bloque; los
+// despliegues aún no indexados (bloque 0) son los más recientes
+func masReciente(a, b *models.RegistroLote) bool {
+	if a.Vigente != b.Vigente {
+		return a.Vigente
+	}
+	if (a.BlockNumber == 0) != (b.BlockNumber == 0) {
+		return a.BlockNumber == 0
+	}
+	if a.BlockNumber != b.BlockNumber {
+		return a.BlockNumber > b.BlockNumber
+	}
+	return a.ContractAddress < b.ContractAddress
+}
+
+// datoInt8 lee una temperatura de los datos de un evento, que tras cargarse
+// del archivo del índice llega como float64
+func datoInt8(valor interface{}) int8 {
+	switch v := valor.(type) {
+	case int8:
+		return v
+	case float64:
+		return int8(v)
+	}
+	return 0
+}
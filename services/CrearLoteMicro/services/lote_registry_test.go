@@ -0,0 +1,169 @@
+package services
+
+import (
+	"CrearLoteMicro/models"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	testContrato     = "0x00000000000000000000000000000000000C0DE1"
+	testFabricante   = "0x000000000000000000000000000000000000F4B1"
+	testDistribuidor = "0x000000000000000000000000000000000000D157"
+)
+
+func historialLote() models.ContratoIndexado {
+	return models.ContratoIndexado{
+		ContractAddress: testContrato,
+		Eventos: []models.EventoBlockchain{
+			{TipoEvento: "LoteCreado", BlockNumber: 10, TxHash: "0x01", Datos: map[string]interface{}{
+				"loteId": "LOTE001", "fabricante": testFabricante, "temperaturaMinima": int8(2), "temperaturaMaxima": int8(8),
+			}},
+			{TipoEvento: "CustodiaTransferida", BlockNumber: 11, Datos: map[string]interface{}{
+				"nuevoPropietario": testDistribuidor, "comprometido": false,
+			}},
+			{TipoEvento: "LoteComprometido", BlockNumber: 12, Datos: map[string]interface{}{}},
+			// crearNuevoLote reutiliza el contrato; los datos llegan como float64
+			// cuando el índice se carga del archivo
+			{TipoEvento: "LoteCreado", BlockNumber: 13, TxHash: "0x04", From: testDistribuidor, Datos: map[string]interface{}{
+				"loteId": "LOTE002", "fabricante": testFabricante, "temperaturaMinima": float64(-5), "temperaturaMaxima": float64(5),
+			}},
+		},
+	}
+}
+
+func TestLoteRegistry_FollowsContractHistory(t *testing.T) {
+	registry, err := NewLoteRegistry(nil)
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+
+	registry.RegistrarDespliegue(models.RegistroLote{LoteID: "LOTE001", ContractAddress: testContrato, Fabricante: testFabricante})
+	if lote, err := registry.PorLoteID("LOTE001"); err != nil || !lote.Vigente || lote.BlockNumber != 0 {
+		t.Fatalf("Expected pending deploy in registry, got %+v (%v)", lote, err)
+	}
+
+	registry.Actualizar(historialLote())
+
+	anterior, err := registry.PorLoteID("LOTE001")
+	if err != nil || anterior.Vigente || !anterior.Comprometido || anterior.PropietarioActual != testDistribuidor || anterior.BlockNumber != 10 {
+		t.Errorf("Expected replaced, compromised LOTE001 owned by distribuidor, got %+v (%v)", anterior, err)
+	}
+	actual, err := registry.PorLoteID("LOTE002")
+	if err != nil || !actual.Vigente || actual.Comprometido || actual.PropietarioActual != testDistribuidor || actual.TemperaturaMinima != -5 {
+		t.Errorf("Expected current LOTE002 created by distribuidor, got %+v (%v)", actual, err)
+	}
+
+	comprometido := true
+	if lotes := registry.Buscar(FiltroLotes{Comprometido: &comprometido}); len(lotes) != 1 || lotes[0].LoteID != "LOTE001" {
+		t.Errorf("Expected only LOTE001 to be compromised, got %+v", lotes)
+	}
+	if lotes := registry.Buscar(FiltroLotes{Fabricante: testFabricante, SoloVigentes: true}); len(lotes) != 1 || lotes[0].LoteID != "LOTE002" {
+		t.Errorf("Expected only LOTE002 to be current, got %+v", lotes)
+	}
+
+	// Una reorganización que elimina el historial también elimina los lotes
+	registry.Actualizar(models.ContratoIndexado{ContractAddress: testContrato})
+	if _, err := registry.PorLoteID("LOTE002"); !errors.Is(err, ErrLoteNotFound) {
+		t.Errorf("Expected ErrLoteNotFound after reorg, got %v", err)
+	}
+}
+
+func TestLoteRegistry_PersistsEntries(t *testing.T) {
+	store := NewFileLoteStore(filepath.Join(t.TempDir(), "data", "lotes.json"))
+	registry, err := NewLoteRegistry(store)
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+	registry.Completar([]models.ContratoIndexado{historialLote()})
+
+	reloaded, err := NewLoteRegistry(store)
+	if err != nil {
+		t.Fatalf("Failed to reload registry: %v", err)
+	}
+	if lote, err := reloaded.PorLoteID("LOTE002"); err != nil || lote.ContractAddress != testContrato || lote.TemperaturaMaxima != 5 {
+		t.Errorf("Expected LOTE002 to be reloaded, got %+v (%v)", lote, err)
+	}
+	if contratos := reloaded.Contratos(); contratos[testContrato] != 10 {
+		t.Errorf("Expected contract to be followed from block 10, got %v", contratos)
+	}
+}
+
+func TestLoteRegistry_DropsFailedDeploys(t *testing.T) {
+	registry, err := NewLoteRegistry(nil)
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+	registry.Actualizar(historialLote())
+
+	// Un nuevo despliegue de LOTE002 se prefiere al indexado mientras está pendiente
+	const redespliegue = "0x00000000000000000000000000000000000C0DE2"
+	registry.RegistrarDespliegue(models.RegistroLote{LoteID: "LOTE002", ContractAddress: redespliegue, TxHash: "0xaa"})
+	if lote, err := registry.PorLoteID("LOTE002"); err != nil || lote.ContractAddress != redespliegue {
+		t.Fatalf("Expected pending deploy to be preferred, got %+v (%v)", lote, err)
+	}
+
+	// Ni la transacción de otro despliegue ni la confirmación lo retiran
+	registry.ResolverDespliegue(models.EstadoTransaccion{TxHash: "0xbb", Operacion: OpDeploy, Estado: models.EstadoRevertida, Final: true})
+	registry.ResolverDespliegue(models.EstadoTransaccion{TxHash: "0xaa", Operacion: OpDeploy, Estado: models.EstadoConfirmada, Final: true})
+	if lote, err := registry.PorLoteID("LOTE002"); err != nil || lote.ContractAddress != redespliegue {
+		t.Fatalf("Expected confirmed deploy to be kept, got %+v (%v)", lote, err)
+	}
+
+	registry.ResolverDespliegue(models.EstadoTransaccion{TxHash: "0xaa", Operacion: OpDeploy, Estado: models.EstadoRevertida, Final: true})
+	if lote, err := registry.PorLoteID("LOTE002"); err != nil || lote.ContractAddress != testContrato {
+		t.Errorf("Expected reverted deploy to be dropped, got %+v (%v)", lote, err)
+	}
+	if lotes := registry.Buscar(FiltroLotes{}); len(lotes) != 2 {
+		t.Errorf("Expected only the indexed lotes, got %+v", lotes)
+	}
+}
+
+func TestLoteRegistry_DropsCancelledDeploy(t *testing.T) {
+	manager, backend, firmante := newTestNonceManager(t)
+	registry, err := NewLoteRegistry(NewFileLoteStore(filepath.Join(t.TempDir(), "lotes.json")))
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+	tracker, err := NewTxTracker(backend, TxTrackerOptions{Confirmations: 1, OnFinal: registry.ResolverDespliegue})
+	if err != nil {
+		t.Fatalf("Failed to create tracker: %v", err)
+	}
+	ctx := context.Background()
+
+	deploy, err := manager.Submit(ctx, TxRequest{
+		Signer:    firmante,
+		Operation: OpDeploy,
+		Data:      deployData(t, "LOTE001", 2, 8),
+	})
+	if err != nil {
+		t.Fatalf("Expected deploy to be sent, got %v", err)
+	}
+	tracker.Track(deploy)
+	registry.RegistrarDespliegue(models.RegistroLote{
+		LoteID:          "LOTE001",
+		ContractAddress: crypto.CreateAddress(firmante.Address(), deploy.Tx.Nonce()).Hex(),
+		TxHash:          deploy.Tx.Hash().Hex(),
+	})
+
+	// La cancelación consume el nonce: el contrato nunca se crea
+	backend.Rollback()
+	cancel, err := manager.Cancel(ctx, firmante, deploy.Tx.Nonce())
+	if err != nil {
+		t.Fatalf("Expected cancellation to be sent, got %v", err)
+	}
+	tracker.Track(cancel)
+	backend.Commit()
+	tracker.poll(ctx)
+
+	if estado := trackerEstado(t, tracker, deploy); estado.Estado != models.EstadoReemplazada || estado.ReemplazadaPor != cancel.Tx.Hash().Hex() {
+		t.Fatalf("Expected deploy replaced by the cancellation, got %+v", estado)
+	}
+	if _, err := registry.PorLoteID("LOTE001"); !errors.Is(err, ErrLoteNotFound) {
+		t.Errorf("Expected cancelled deploy to be dropped, got %v", err)
+	}
+}
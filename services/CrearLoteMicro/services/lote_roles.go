@@ -0,0 +1,287 @@
+package services
+
+import (
+	"CrearLoteMicro/bindings"
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/signer"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrRolRequerido se devuelve cuando la cuenta firmante no tiene el rol que
+// exige la operación en el contrato
+var ErrRolRequerido = errors.New("la cuenta no tiene el rol requerido")
+
+// ErrRolInvalido se devuelve con un nombre de rol desconocido
+var ErrRolInvalido = errors.New("rol desconocido")
+
+// ErrLoteNoReiniciable se devuelve al reiniciar un lote que ya cambió de
+// custodia o está comprometido
+var ErrLoteNoReiniciable = errors.New("el lote no se puede reiniciar")
+
+// Roles de LoteTracing. El contrato los identifica con el keccak256 de su
+// identificador (ROL_FABRICANTE = keccak256("FABRICANTE"), ...)
+const (
+	RolFabricante   = "fabricante"
+	RolDistribuidor = "distribuidor"
+	RolFarmacia     = "farmacia"
+	RolOraculo      = "oraculo"
+	RolAuditor      = "auditor"
+)
+
+// RolesLote son los nombres de los roles en el orden en que se listan
+var RolesLote = []string{RolFabricante, RolDistribuidor, RolFarmacia, RolOraculo, RolAuditor}
+
+var idsRoles = map[string]common.Hash{
+	RolFabricante:   crypto.Keccak256Hash([]byte("FABRICANTE")),
+	RolDistribuidor: crypto.Keccak256Hash([]byte("DISTRIBUIDOR")),
+	RolFarmacia:     crypto.Keccak256Hash([]byte("FARMACIA")),
+	RolOraculo:      crypto.Keccak256Hash([]byte("ORACULO_SENSOR")),
+	RolAuditor:      crypto.Keccak256Hash([]byte("AUDITOR")),
+}
+
+// IDRol devuelve el identificador bytes32 de un rol por su nombre
+func IDRol(nombre string) (common.Hash, error) {
+	id, ok := idsRoles[strings.ToLower(nombre)]
+	if !ok {
+		return common.Hash{}, fmt.Errorf("%w: %s (válidos: %s)", ErrRolInvalido, nombre, strings.Join(RolesLote, ", "))
+	}
+	return id, nil
+}
+
+// nombreRol devuelve el nombre de un identificador de rol, o su hex si no es
+// uno de los roles conocidos
+func nombreRol(id common.Hash) string {
+	for nombre, rolID := range idsRoles {
+		if rolID == id {
+			return nombre
+		}
+	}
+	return id.Hex()
+}
+
+// loteCaller crea el binding de solo lectura de un contrato LoteTracing
+func (bs *BlockchainService) loteCaller(contractAddr common.Address) (*bindings.LoteTracingCaller, error) {
+	contract, err := bindings.NewLoteTracingCaller(contractAddr, bs.Client)
+	if err != nil {
+		return nil, fmt.Errorf("error creando binding del contrato: %v", err)
+	}
+	return contract, nil
+}
+
+// verificarRol comprueba antes de enviar la transacción que la cuenta tenga
+// alguno de los roles. Los contratos desplegados antes de los roles no tienen
+// tieneRol; en ese caso no se comprueba y decide la estimación de gas.
+func (bs *BlockchainService) verificarRol(contractAddr, cuenta common.Address, roles ...string) error {
+	contract, err := bs.loteCaller(contractAddr)
+	if err != nil {
+		return err
+	}
+	callOpts := &bind.CallOpts{Context: context.Background()}
+
+	for _, nombre := range roles {
+		tiene, err := contract.TieneRol(callOpts, idsRoles[nombre], cuenta)
+		if err != nil {
+			log.Printf("No se pudieron comprobar los roles de %s en %s: %v", cuenta.Hex(), contractAddr.Hex(), err)
+			return nil
+		}
+		if tiene {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s necesita el rol %s", ErrRolRequerido, cuenta.Hex(), strings.Join(roles, " o "))
+}
+
+// verificarAdmin comprueba antes de enviar la transacción que la cuenta
+// administre los roles del contrato
+func (bs *BlockchainService) verificarAdmin(contractAddr, cuenta common.Address) error {
+	contract, err := bs.loteCaller(contractAddr)
+	if err != nil {
+		return err
+	}
+
+	admin, err := contract.Admin(&bind.CallOpts{Context: context.Background()})
+	if err != nil {
+		log.Printf("No se pudo comprobar el administrador de %s: %v", contractAddr.Hex(), err)
+		return nil
+	}
+	if admin != cuenta {
+		return fmt.Errorf("%w: %s no es el administrador del lote (%s)", ErrRolRequerido, cuenta.Hex(), admin.Hex())
+	}
+	return nil
+}
+
+// verificarReinicio comprueba antes de enviar la transacción que solo el
+// fabricante del lote lo reinicie, mientras conserve la custodia, sin
+// propuestas y con el lote sin comprometer, como exige crearNuevoLote
+func (bs *BlockchainService) verificarReinicio(contractAddr, cuenta common.Address) error {
+	contract, err := bs.loteCaller(contractAddr)
+	if err != nil {
+		return err
+	}
+	callOpts := &bind.CallOpts{Context: context.Background()}
+
+	fabricante, err := contract.Fabricante(callOpts)
+	if err != nil {
+		log.Printf("No se pudo comprobar el fabricante de %s: %v", contractAddr.Hex(), err)
+		return nil
+	}
+	if fabricante != cuenta {
+		return fmt.Errorf("%w: %s no es el fabricante del lote (%s)", ErrRolRequerido, cuenta.Hex(), fabricante.Hex())
+	}
+
+	propietario, err := contract.PropietarioActual(callOpts)
+	if err != nil {
+		return fmt.Errorf("error consultando propietario: %v", err)
+	}
+	if propietario != fabricante {
+		return fmt.Errorf("%w: el lote ya cambió de custodia", ErrLoteNoReiniciable)
+	}
+	// Los contratos anteriores al traspaso en dos pasos no tienen propuestas
+	if numero, err := contract.PropuestaCustodia(callOpts); err == nil && numero != 0 {
+		return fmt.Errorf("%w: el lote ya cambió de custodia", ErrLoteNoReiniciable)
+	}
+	comprometido, err := contract.Comprometido(callOpts)
+	if err != nil {
+		return fmt.Errorf("error consultando estado: %v", err)
+	}
+	if comprometido {
+		return fmt.Errorf("%w: el lote está comprometido", ErrLoteNoReiniciable)
+	}
+	return nil
+}
+
+// OtorgarRol asigna un rol del contrato a una cuenta. Solo el administrador.
+func (bs *BlockchainService) OtorgarRol(firmante signer.Signer, contractAddress, rol, cuenta string) (*models.TransaccionEnviada, error) {
+	return bs.cambiarRol(firmante, contractAddress, rol, cuenta, OpOtorgarRol,
+		func(contract *bindings.LoteTracingTransactor, opts *bind.TransactOpts, rolID common.Hash, cuenta common.Address) (*types.Transaction, error) {
+			return contract.OtorgarRol(opts, rolID, cuenta)
+		})
+}
+
+// RevocarRol retira un rol del contrato a una cuenta. Solo el administrador.
+func (bs *BlockchainService) RevocarRol(firmante signer.Signer, contractAddress, rol, cuenta string) (*models.TransaccionEnviada, error) {
+	return bs.cambiarRol(firmante, contractAddress, rol, cuenta, OpRevocarRol,
+		func(contract *bindings.LoteTracingTransactor, opts *bind.TransactOpts, rolID common.Hash, cuenta common.Address) (*types.Transaction, error) {
+			return contract.RevocarRol(opts, rolID, cuenta)
+		})
+}
+
+func (bs *BlockchainService) cambiarRol(firmante signer.Signer, contractAddress, rol, cuenta, operation string, call func(contract *bindings.LoteTracingTransactor, opts *bind.TransactOpts, rolID common.Hash, cuenta common.Address) (*types.Transaction, error)) (*models.TransaccionEnviada, error) {
+	rolID, err := IDRol(rol)
+	if err != nil {
+		return nil, err
+	}
+	toAddress := common.HexToAddress(contractAddress)
+	if err := bs.verificarAdmin(toAddress, firmante.Address()); err != nil {
+		return nil, err
+	}
+
+	contract, err := loteTracingTransactor(toAddress)
+	if err != nil {
+		return nil, err
+	}
+	data, err := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return call(contract, opts, rolID, common.HexToAddress(cuenta))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sent, err := bs.enviar(TxRequest{
+		Signer:    firmante,
+		Operation: operation,
+		To:        &toAddress,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sent.Info, nil
+}
+
+// RolesCuenta devuelve los roles que una cuenta tiene en el contrato
+func (bs *BlockchainService) RolesCuenta(contractAddress, cuenta string) ([]string, error) {
+	contractAddr := common.HexToAddress(contractAddress)
+	contract, err := bs.loteCaller(contractAddr)
+	if err != nil {
+		return nil, err
+	}
+	callOpts := &bind.CallOpts{Context: context.Background()}
+
+	roles := []string{}
+	for _, nombre := range RolesLote {
+		tiene, err := contract.TieneRol(callOpts, idsRoles[nombre], common.HexToAddress(cuenta))
+		if err != nil {
+			return nil, fmt.Errorf("error consultando rol %s: %v", nombre, err)
+		}
+		if tiene {
+			roles = append(roles, nombre)
+		}
+	}
+	return roles, nil
+}
+
+// ObtenerRoles devuelve el administrador del contrato y las cuentas de cada
+// rol. El mapping de roles no se puede recorrer, así que las asignaciones se
+// reconstruyen con los eventos RolOtorgado y RolRevocado del índice.
+func (bs *BlockchainService) ObtenerRoles(contractAddress string) (*models.RolesLoteResponse, error) {
+	contractAddr := common.HexToAddress(contractAddress)
+	code, err := bs.Client.CodeAt(context.Background(), contractAddr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error verificando contrato: %v", err)
+	}
+	if len(code) == 0 {
+		return nil, ErrContractNotFound
+	}
+
+	contract, err := bs.loteCaller(contractAddr)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := contract.Admin(&bind.CallOpts{Context: context.Background()})
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo administrador: %v", err)
+	}
+
+	contrato, _, err := bs.indexer.Eventos(context.Background(), contractAddr)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo eventos indexados: %v", err)
+	}
+
+	asignadas := make(map[string][]string)
+	for _, evento := range contrato.Eventos {
+		rol, _ := evento.Datos["rol"].(string)
+		cuenta, _ := evento.Datos["cuenta"].(string)
+		switch evento.TipoEvento {
+		case "RolOtorgado":
+			asignadas[rol] = append(asignadas[rol], cuenta)
+		case "RolRevocado":
+			cuentas := asignadas[rol][:0]
+			for _, c := range asignadas[rol] {
+				if c != cuenta {
+					cuentas = append(cuentas, c)
+				}
+			}
+			asignadas[rol] = cuentas
+		}
+	}
+
+	roles := make(map[string][]string, len(RolesLote))
+	for _, nombre := range RolesLote {
+		roles[nombre] = append([]string{}, asignadas[nombre]...)
+	}
+	return &models.RolesLoteResponse{
+		ContractAddress: contractAddr.Hex(),
+		Admin:           admin.Hex(),
+		Roles:           roles,
+	}, nil
+}
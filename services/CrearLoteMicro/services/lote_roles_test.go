@@ -0,0 +1,154 @@
+package services
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/signer"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestLoteRoles_GrantsAndChecksRoles(t *testing.T) {
+	fabricante, _ := signer.NewDevSigner("fabricante")
+	oraculo, _ := signer.NewDevSigner("oraculo")
+	farmacia, _ := signer.NewDevSigner("farmacia")
+
+	chain := NewSimulatedChain(SimulatedChainOptions{Accounts: []common.Address{fabricante.Address(), oraculo.Address(), farmacia.Address()}})
+	t.Cleanup(func() { chain.Close() })
+	bs, err := NewBlockchainServiceWithClient(chain, 1337, NonceManagerOptions{}, TxTrackerOptions{}, EventIndexerOptions{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	contractAddress, _, err := bs.DeployContract(fabricante, "LOTE001", 2, 8)
+	if err != nil {
+		t.Fatalf("Failed to deploy contract: %v", err)
+	}
+
+	// Las comprobaciones previas rechazan sin gastar gas
+	if _, err := bs.RegistrarTemperatura(oraculo, contractAddress, 3, 7, "SENSOR-01"); !errors.Is(err, ErrRolRequerido) {
+		t.Errorf("Expected ErrRolRequerido for a reading without the oracle role, got %v", err)
+	}
+	if _, err := bs.ProponerCustodia(fabricante, contractAddress, farmacia.Address().Hex(), time.Hour, models.DetallesEnvio{}); !errors.Is(err, ErrRolRequerido) {
+		t.Errorf("Expected ErrRolRequerido for a proposal to an account without role, got %v", err)
+	}
+	if _, err := bs.CrearNuevoLote(oraculo, contractAddress, "LOTE002", 2, 8); !errors.Is(err, ErrRolRequerido) {
+		t.Errorf("Expected ErrRolRequerido for a lote created by a non-manufacturer, got %v", err)
+	}
+	if _, err := bs.OtorgarRol(oraculo, contractAddress, RolOraculo, oraculo.Address().Hex()); !errors.Is(err, ErrRolRequerido) {
+		t.Errorf("Expected only the admin to grant roles, got %v", err)
+	}
+	if _, err := bs.OtorgarRol(fabricante, contractAddress, "transportista", oraculo.Address().Hex()); !errors.Is(err, ErrRolInvalido) {
+		t.Errorf("Expected ErrRolInvalido, got %v", err)
+	}
+
+	if _, err := bs.OtorgarRol(fabricante, contractAddress, RolOraculo, oraculo.Address().Hex()); err != nil {
+		t.Fatalf("Expected oracle role to be granted, got %v", err)
+	}
+	if _, err := bs.OtorgarRol(fabricante, contractAddress, RolFarmacia, farmacia.Address().Hex()); err != nil {
+		t.Fatalf("Expected pharmacy role to be granted, got %v", err)
+	}
+	if _, err := bs.RegistrarTemperatura(oraculo, contractAddress, 3, 7, "SENSOR-01"); err != nil {
+		t.Errorf("Expected oracle reading to be registered, got %v", err)
+	}
+	if _, err := bs.ProponerCustodia(fabricante, contractAddress, farmacia.Address().Hex(), time.Hour, models.DetallesEnvio{}); err != nil {
+		t.Errorf("Expected a proposal to a pharmacy, got %v", err)
+	}
+
+	roles, err := bs.RolesCuenta(contractAddress, fabricante.Address().Hex())
+	if err != nil || strings.Join(roles, ",") != RolFabricante {
+		t.Errorf("Expected deployer to hold only the manufacturer role, got %v (%v)", roles, err)
+	}
+
+	if _, err := bs.RevocarRol(fabricante, contractAddress, RolOraculo, oraculo.Address().Hex()); err != nil {
+		t.Fatalf("Expected oracle role to be revoked, got %v", err)
+	}
+	if _, err := bs.RegistrarTemperatura(oraculo, contractAddress, 3, 7, "SENSOR-01"); !errors.Is(err, ErrRolRequerido) {
+		t.Errorf("Expected revoked oracle to be rejected, got %v", err)
+	}
+
+	lote, err := bs.ObtenerRoles(contractAddress)
+	if err != nil {
+		t.Fatalf("Expected roles, got %v", err)
+	}
+	if lote.Admin != fabricante.Address().Hex() {
+		t.Errorf("Expected deployer as admin, got %s", lote.Admin)
+	}
+	if strings.Join(lote.Roles[RolFabricante], ",") != fabricante.Address().Hex() ||
+		strings.Join(lote.Roles[RolFarmacia], ",") != farmacia.Address().Hex() ||
+		len(lote.Roles[RolOraculo]) != 0 || lote.Roles[RolAuditor] == nil {
+		t.Errorf("Expected roles rebuilt from events, got %+v", lote.Roles)
+	}
+	if _, err := bs.ObtenerRoles(common.HexToAddress("0x1234").Hex()); !errors.Is(err, ErrContractNotFound) {
+		t.Errorf("Expected ErrContractNotFound, got %v", err)
+	}
+}
+
+func TestLoteRoles_OnlyOriginalManufacturerResetsLote(t *testing.T) {
+	fabricante, _ := signer.NewDevSigner("fabricante")
+	otroFabricante, _ := signer.NewDevSigner("otro-fabricante")
+	distribuidor, _ := signer.NewDevSigner("distribuidor")
+
+	chain := NewSimulatedChain(SimulatedChainOptions{Accounts: []common.Address{fabricante.Address(), otroFabricante.Address(), distribuidor.Address()}})
+	t.Cleanup(func() { chain.Close() })
+	bs, err := NewBlockchainServiceWithClient(chain, 1337, NonceManagerOptions{}, TxTrackerOptions{}, EventIndexerOptions{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	contractAddress, _, err := bs.DeployContract(fabricante, "LOTE001", 2, 8)
+	if err != nil {
+		t.Fatalf("Failed to deploy contract: %v", err)
+	}
+	for rol, cuenta := range map[string]signer.Signer{RolFabricante: otroFabricante, RolOraculo: fabricante, RolDistribuidor: distribuidor} {
+		if _, err := bs.OtorgarRol(fabricante, contractAddress, rol, cuenta.Address().Hex()); err != nil {
+			t.Fatalf("Expected role %s to be granted, got %v", rol, err)
+		}
+	}
+
+	// Otro fabricante no puede sobrescribir el lote, ni saltándose la comprobación previa
+	if _, err := bs.CrearNuevoLote(otroFabricante, contractAddress, "LOTE-AJENO", 2, 8); !errors.Is(err, ErrRolRequerido) {
+		t.Errorf("Expected ErrRolRequerido for another manufacturer, got %v", err)
+	}
+	toAddress := common.HexToAddress(contractAddress)
+	contract, _ := loteTracingTransactor(toAddress)
+	data, _ := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.CrearNuevoLote(opts, "LOTE-AJENO", 2, 8)
+	})
+	if _, err := bs.enviar(TxRequest{Signer: otroFabricante, Operation: OpCrearNuevoLote, To: &toAddress, Data: data}); err == nil ||
+		!strings.Contains(err.Error(), "execution reverted") {
+		t.Errorf("Expected the contract to reject another manufacturer, got %v", err)
+	}
+
+	if _, err := bs.CrearNuevoLote(fabricante, contractAddress, "LOTE002", 0, 10); err != nil {
+		t.Fatalf("Expected the original manufacturer to reset the lote, got %v", err)
+	}
+
+	// Un lote comprometido no se rehabilita
+	if _, err := bs.RegistrarTemperatura(fabricante, contractAddress, 0, 12, "SENSOR-01"); err != nil {
+		t.Fatalf("Expected reading to be registered, got %v", err)
+	}
+	if _, err := bs.CrearNuevoLote(fabricante, contractAddress, "LOTE003", 0, 20); !errors.Is(err, ErrLoteNoReiniciable) {
+		t.Errorf("Expected ErrLoteNoReiniciable for a compromised lote, got %v", err)
+	}
+
+	// Ni uno cuya custodia ya se propuso a otra cuenta
+	otroContrato, _, err := bs.DeployContract(fabricante, "LOTE010", 2, 8)
+	if err != nil {
+		t.Fatalf("Failed to deploy contract: %v", err)
+	}
+	if _, err := bs.OtorgarRol(fabricante, otroContrato, RolDistribuidor, distribuidor.Address().Hex()); err != nil {
+		t.Fatalf("Expected role to be granted, got %v", err)
+	}
+	if _, err := bs.ProponerCustodia(fabricante, otroContrato, distribuidor.Address().Hex(), time.Hour, models.DetallesEnvio{}); err != nil {
+		t.Fatalf("Expected custody proposal, got %v", err)
+	}
+	if _, err := bs.CrearNuevoLote(fabricante, otroContrato, "LOTE011", 2, 8); !errors.Is(err, ErrLoteNoReiniciable) {
+		t.Errorf("Expected ErrLoteNoReiniciable after a custody proposal, got %v", err)
+	}
+}
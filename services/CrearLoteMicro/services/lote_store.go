@@ -0,0 +1,48 @@
+package services
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// LoteStore guarda el registro de lotes entre reinicios
+type LoteStore interface {
+	Load() ([]models.RegistroLote, error)
+	Save(lotes []models.RegistroLote) error
+}
+
+// FileLoteStore guarda el registro en un archivo JSON
+type FileLoteStore struct {
+	path string
+}
+
+// NewFileLoteStore crea un almacén en el archivo indicado
+func NewFileLoteStore(path string) *FileLoteStore {
+	return &FileLoteStore{path: path}
+}
+
+// Load lee el registro guardado; un archivo inexistente equivale a un registro vacío
+func (s *FileLoteStore) Load() ([]models.RegistroLote, error) {
+	content, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo %s: %v", s.path, err)
+	}
+
+	var lotes []models.RegistroLote
+	if err := json.Unmarshal(content, &lotes); err != nil {
+		return nil, fmt.Errorf("error parseando %s: %v", s.path, err)
+	}
+	return lotes, nil
+}
+
+// Save reemplaza el archivo de forma atómica
+func (s *FileLoteStore) Save(lotes []models.RegistroLote) error {
+	return utils.GuardarJSONAtomico(s.path, lotes)
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"CrearLoteMicro/models"
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FiltroLecturas selecciona lecturas de temperatura. Los campos vacíos no filtran.
+type FiltroLecturas struct {
+	SensorID string
+	// Desde y Hasta limitan el timestamp de la lectura (segundos Unix, inclusive)
+	Desde uint64
+	Hasta uint64
+}
+
+func (f FiltroLecturas) cumple(lectura models.LecturaTemperatura) bool {
+	if f.SensorID != "" && lectura.SensorID != f.SensorID {
+		return false
+	}
+	if f.Desde != 0 && lectura.Timestamp < f.Desde {
+		return false
+	}
+	if f.Hasta != 0 && lectura.Timestamp > f.Hasta {
+		return false
+	}
+	return true
+}
+
+// ObtenerTemperaturas devuelve la serie temporal de lecturas de un contrato
+// LoteTracing, en orden de registro, a partir de los eventos
+// TemperaturaRegistrada del índice
+func (bs *BlockchainService) ObtenerTemperaturas(contractAddress string, filtro FiltroLecturas) (*models.TemperaturasLoteResponse, error) {
+	contractAddr := common.HexToAddress(contractAddress)
+	code, err := bs.Client.CodeAt(context.Background(), contractAddr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error verificando contrato: %v", err)
+	}
+	if len(code) == 0 {
+		return nil, ErrContractNotFound
+	}
+
+	contract, err := bs.loteCaller(contractAddr)
+	if err != nil {
+		return nil, err
+	}
+	callOpts := &bind.CallOpts{Context: context.Background()}
+	loteID, err := contract.LoteId(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo loteId: %v", err)
+	}
+	tempMin, err := contract.TemperaturaMinima(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo temperatura mínima: %v", err)
+	}
+	tempMax, err := contract.TemperaturaMaxima(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo temperatura máxima: %v", err)
+	}
+
+	contrato, indexadoHasta, err := bs.indexer.Eventos(context.Background(), contractAddr)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo eventos indexados: %v", err)
+	}
+
+	response := &models.TemperaturasLoteResponse{
+		ContractAddress:   contractAddr.Hex(),
+		LoteID:            loteID,
+		TemperaturaMinima: tempMin,
+		TemperaturaMaxima: tempMax,
+		Lecturas:          []models.LecturaTemperatura{},
+		IndexadoHasta:     indexadoHasta,
+	}
+	for _, evento := range contrato.Eventos {
+		if evento.TipoEvento != "TemperaturaRegistrada" {
+			continue
+		}
+		lectura := models.LecturaTemperatura{
+			TempMin:     datoInt8(evento.Datos["tempMin"]),
+			TempMax:     datoInt8(evento.Datos["tempMax"]),
+			Timestamp:   datoUint64(evento.Datos["timestamp"]),
+			BlockNumber: evento.BlockNumber,
+			TxHash:      evento.TxHash,
+		}
+		lectura.SensorID, _ = evento.Datos["sensorId"].(string)
+		lectura.Oraculo, _ = evento.Datos["oraculo"].(string)
+		lectura.EnRango, _ = evento.Datos["enRango"].(bool)
+		if !filtro.cumple(lectura) {
+			continue
+		}
+
+		response.Lecturas = append(response.Lecturas, lectura)
+		if !lectura.EnRango {
+			response.LecturasFueraDeRango++
+		}
+	}
+	response.TotalLecturas = len(response.Lecturas)
+
+	return response, nil
+}
+
+// datoUint64 lee un entero sin signo de los datos de un evento, que tras
+// cargarse del archivo del índice llega como float64
+func datoUint64(valor interface{}) uint64 {
+	switch v := valor.(type) {
+	case uint64:
+		return v
+	case uint32:
+		return uint64(v)
+	case float64:
+		return uint64(v)
+	}
+	return 0
+}
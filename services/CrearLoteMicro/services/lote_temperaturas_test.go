@@ -0,0 +1,144 @@
+package services
+
+import (
+	"CrearLoteMicro/bindings"
+	"CrearLoteMicro/signer"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestObtenerTemperaturas_ReturnsEveryReading(t *testing.T) {
+	fabricante, _ := signer.NewDevSigner("fabricante")
+	oraculo, _ := signer.NewDevSigner("oraculo")
+
+	chain := NewSimulatedChain(SimulatedChainOptions{Accounts: []common.Address{fabricante.Address(), oraculo.Address()}})
+	t.Cleanup(func() { chain.Close() })
+	bs, err := NewBlockchainServiceWithClient(chain, 1337, NonceManagerOptions{}, TxTrackerOptions{}, EventIndexerOptions{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	contractAddress, _, err := bs.DeployContract(fabricante, "LOTE001", 2, 8)
+	if err != nil {
+		t.Fatalf("Failed to deploy contract: %v", err)
+	}
+	if _, err := bs.OtorgarRol(fabricante, contractAddress, RolOraculo, oraculo.Address().Hex()); err != nil {
+		t.Fatalf("Expected oracle role to be granted, got %v", err)
+	}
+
+	lecturas := []struct {
+		sensor           string
+		tempMin, tempMax int8
+	}{
+		{"SENSOR-01", 3, 7},
+		{"SENSOR-02", 4, 6},
+		{"SENSOR-01", 1, 9},
+		{"SENSOR-01", 2, 8},
+	}
+	for _, lectura := range lecturas {
+		if _, err := bs.RegistrarTemperatura(oraculo, contractAddress, lectura.tempMin, lectura.tempMax, lectura.sensor); err != nil {
+			t.Fatalf("Expected reading to be registered, got %v", err)
+		}
+	}
+
+	serie, err := bs.ObtenerTemperaturas(contractAddress, FiltroLecturas{})
+	if err != nil {
+		t.Fatalf("Expected temperature series, got %v", err)
+	}
+	if serie.LoteID != "LOTE001" || serie.TemperaturaMinima != 2 || serie.TemperaturaMaxima != 8 {
+		t.Errorf("Expected lote range in the series, got %+v", serie)
+	}
+	if serie.TotalLecturas != 4 || serie.LecturasFueraDeRango != 1 {
+		t.Fatalf("Expected 4 readings with 1 out of range, got %d and %d", serie.TotalLecturas, serie.LecturasFueraDeRango)
+	}
+	for i, lectura := range serie.Lecturas {
+		if lectura.SensorID != lecturas[i].sensor || lectura.TempMin != lecturas[i].tempMin || lectura.TempMax != lecturas[i].tempMax {
+			t.Errorf("Reading %d: expected %+v, got %+v", i, lecturas[i], lectura)
+		}
+		if lectura.Oraculo != oraculo.Address().Hex() || lectura.Timestamp == 0 || lectura.TxHash == "" {
+			t.Errorf("Reading %d: expected oracle, timestamp and tx hash, got %+v", i, lectura)
+		}
+		if i > 0 && lectura.Timestamp < serie.Lecturas[i-1].Timestamp {
+			t.Errorf("Expected readings in time order, got %d after %d", lectura.Timestamp, serie.Lecturas[i-1].Timestamp)
+		}
+	}
+	if serie.Lecturas[2].EnRango || !serie.Lecturas[3].EnRango {
+		t.Errorf("Expected only the third reading out of range, got %+v", serie.Lecturas)
+	}
+
+	porSensor, _ := bs.ObtenerTemperaturas(contractAddress, FiltroLecturas{SensorID: "SENSOR-02"})
+	if porSensor.TotalLecturas != 1 || porSensor.Lecturas[0].TempMin != 4 {
+		t.Errorf("Expected one SENSOR-02 reading, got %+v", porSensor.Lecturas)
+	}
+	desde := serie.Lecturas[2].Timestamp
+	recientes, _ := bs.ObtenerTemperaturas(contractAddress, FiltroLecturas{Desde: desde, Hasta: desde})
+	if recientes.TotalLecturas != 1 || recientes.LecturasFueraDeRango != 1 {
+		t.Errorf("Expected the reading at %d only, got %+v", desde, recientes.Lecturas)
+	}
+
+	if _, err := bs.ObtenerTemperaturas(common.HexToAddress("0x1234").Hex(), FiltroLecturas{}); !errors.Is(err, ErrContractNotFound) {
+		t.Errorf("Expected ErrContractNotFound, got %v", err)
+	}
+}
+
+func TestDatoUint64_ReadsReloadedValues(t *testing.T) {
+	if datoUint64(uint64(1700000000)) != 1700000000 || datoUint64(float64(1700000000)) != 1700000000 || datoUint64(nil) != 0 {
+		t.Error("Expected uint64 and float64 timestamps to be read")
+	}
+}
+
+func TestRegistrarTemperatura_ContractWithoutSensor(t *testing.T) {
+	fabricante, _ := signer.NewDevSigner("fabricante")
+
+	chain := NewSimulatedChain(SimulatedChainOptions{Accounts: []common.Address{fabricante.Address()}})
+	t.Cleanup(func() { chain.Close() })
+	bs, err := NewBlockchainServiceWithClient(chain, 1337, NonceManagerOptions{}, TxTrackerOptions{}, EventIndexerOptions{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	// Contrato desplegado antes del evento TemperaturaRegistrada, con
+	// registrarTemperatura(int8,int8)
+	bytecode, err := os.ReadFile(filepath.Join("testdata", "LoteTracingSinSensor.bytecode"))
+	if err != nil {
+		t.Fatalf("Failed to read legacy bytecode: %v", err)
+	}
+	contractABI, _ := bindings.LoteTracingMetaData.GetAbi()
+	args, err := contractABI.Constructor.Inputs.Pack("LOTE001", int8(2), int8(8))
+	if err != nil {
+		t.Fatalf("Failed to pack constructor: %v", err)
+	}
+	sent, err := bs.enviar(TxRequest{Signer: fabricante, Operation: OpDeploy, Data: append(common.FromHex(strings.TrimSpace(string(bytecode))), args...)})
+	if err != nil {
+		t.Fatalf("Failed to deploy legacy contract: %v", err)
+	}
+	contractAddress := crypto.CreateAddress(fabricante.Address(), sent.Tx.Nonce())
+
+	code, _ := chain.CodeAt(context.Background(), contractAddress, nil)
+	if len(code) == 0 || registraSensor(code) {
+		t.Fatalf("Expected a deployed contract without the sensor signature")
+	}
+	deployed, _, _ := bs.DeployContract(fabricante, "LOTE002", 2, 8)
+	if code, _ := chain.CodeAt(context.Background(), common.HexToAddress(deployed), nil); !registraSensor(code) {
+		t.Errorf("Expected the current contract to take the sensor")
+	}
+
+	if _, err := bs.RegistrarTemperatura(fabricante, contractAddress.Hex(), 1, 9, "SENSOR-01"); err != nil {
+		t.Fatalf("Expected the reading to be registered without the sensor, got %v", err)
+	}
+	contract, _ := bs.loteCaller(contractAddress)
+	callOpts := &bind.CallOpts{Context: context.Background()}
+	tempMax, _ := contract.TempRegMaxima(callOpts)
+	comprometido, _ := contract.Comprometido(callOpts)
+	if tempMax != 9 || !comprometido {
+		t.Errorf("Expected the legacy contract to store the reading, got tempMax %d comprometido %v", tempMax, comprometido)
+	}
+}
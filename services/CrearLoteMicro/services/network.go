@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrNetworkNotFound se devuelve al pedir una red que no está configurada
+var ErrNetworkNotFound = errors.New("red no configurada")
+
+// Network es un perfil de red con su servicio de blockchain
+type Network struct {
+	Name      string
+	Type      string
+	ChainID   int64
+	GasPolicy string
+	// Confirmations es la profundidad a la que las transacciones son definitivas
+	Confirmations uint64
+	Service       *BlockchainService
+	// Websocket es nil si la red no tiene URL de WebSocket
+	Websocket *WebsocketWatcher
+}
+
+// NetworkRegistry contiene las redes configuradas y la red por defecto
+type NetworkRegistry struct {
+	networks    map[string]*Network
+	defaultName string
+}
+
+// NewNetworkRegistry crea un registro vacío cuya red por defecto es defaultName
+func NewNetworkRegistry(defaultName string) *NetworkRegistry {
+	return &NetworkRegistry{
+		networks:    make(map[string]*Network),
+		defaultName: defaultName,
+	}
+}
+
+// Register añade una red
+func (r *NetworkRegistry) Register(network *Network) error {
+	if _, exists := r.networks[network.Name]; exists {
+		return fmt.Errorf("la red %s ya está registrada", network.Name)
+	}
+	r.networks[network.Name] = network
+	return nil
+}
+
+// Get devuelve la red con ese nombre; vacío devuelve la red por defecto
+func (r *NetworkRegistry) Get(name string) (*Network, error) {
+	if name == "" {
+		name = r.defaultName
+	}
+	network, ok := r.networks[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNetworkNotFound, name)
+	}
+	return network, nil
+}
+
+// Default devuelve el nombre de la red por defecto
+func (r *NetworkRegistry) Default() string {
+	return r.defaultName
+}
+
+// All devuelve las redes ordenadas por nombre
+func (r *NetworkRegistry) All() []*Network {
+	networks := make([]*Network, 0, len(r.networks))
+	for _, network := range r.networks {
+		networks = append(networks, network)
+	}
+	sort.Slice(networks, func(i, j int) bool { return networks[i].Name < networks[j].Name })
+	return networks
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewBlockchainService_ValidatesChainID(t *testing.T) {
+	chain := NewSimulatedChain(SimulatedChainOptions{})
+	t.Cleanup(func() { chain.Close() })
+
+	if _, err := NewBlockchainServiceWithClient(chain, 11155111, NonceManagerOptions{}, TxTrackerOptions{}, EventIndexerOptions{}, nil); !errors.Is(err, ErrChainIDMismatch) {
+		t.Fatalf("Expected ErrChainIDMismatch for Sepolia config on chain 1337, got %v", err)
+	}
+
+	service, err := NewBlockchainServiceWithClient(chain, 1337, NonceManagerOptions{}, TxTrackerOptions{}, EventIndexerOptions{}, nil)
+	if err != nil {
+		t.Fatalf("Expected matching chain ID to be accepted, got %v", err)
+	}
+	if service.ChainID().Int64() != 1337 {
+		t.Errorf("Expected chain ID 1337, got %s", service.ChainID())
+	}
+}
+
+func TestNetworkRegistry_Get(t *testing.T) {
+	registry := NewNetworkRegistry("sepolia")
+	for _, name := range []string{"sepolia", "besu"} {
+		if err := registry.Register(&Network{Name: name}); err != nil {
+			t.Fatalf("Failed to register %s: %v", name, err)
+		}
+	}
+	if err := registry.Register(&Network{Name: "besu"}); err == nil {
+		t.Error("Expected duplicate network to be rejected")
+	}
+
+	if network, err := registry.Get(""); err != nil || network.Name != "sepolia" {
+		t.Errorf("Expected default network, got %+v (%v)", network, err)
+	}
+	if network, err := registry.Get("besu"); err != nil || network.Name != "besu" {
+		t.Errorf("Expected besu, got %+v (%v)", network, err)
+	}
+	if _, err := registry.Get("mainnet"); !errors.Is(err, ErrNetworkNotFound) {
+		t.Errorf("Expected ErrNetworkNotFound, got %v", err)
+	}
+	if all := registry.All(); len(all) != 2 || all[0].Name != "besu" {
+		t.Errorf("Expected networks sorted by name, got %+v", all)
+	}
+}
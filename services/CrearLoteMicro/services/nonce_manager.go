@@ -0,0 +1,457 @@
+package services
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/signer"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	// ErrTxQueueFull se devuelve cuando la cola de transacciones de una cuenta está llena
+	ErrTxQueueFull = errors.New("cola de transacciones llena")
+	// ErrTxNotPending se devuelve al reemplazar un nonce que no tiene transacción pendiente
+	ErrTxNotPending = errors.New("no hay transacción pendiente con ese nonce")
+	// ErrTxAlreadyMined se devuelve al reemplazar una transacción que ya fue minada
+	ErrTxAlreadyMined = errors.New("la transacción ya fue minada")
+)
+
+// TxBackend es lo que el gestor de nonces necesita de la blockchain. Lo
+// implementan ethclient.Client y el backend simulado de go-ethereum.
+type TxBackend interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+// NonceManagerOptions configura la cola y el reemplazo de transacciones
+type NonceManagerOptions struct {
+	// QueueSize es el máximo de transacciones en espera por cuenta
+	QueueSize int
+	// MaxRetries es el número de reintentos tras un error de nonce
+	MaxRetries int
+	// StuckAfter es el tiempo tras el cual una transacción pendiente se considera atascada
+	StuckAfter time.Duration
+	// PriceBumpPercent es el aumento mínimo del precio de gas al reemplazar
+	PriceBumpPercent int64
+	// Fees configura las comisiones EIP-1559 y la estimación de gas
+	Fees FeeOptions
+}
+
+// TxRequest describe una transacción a enviar; To nil despliega un contrato.
+// Con GasLimit 0 el gas se estima y se le suma el margen de seguridad.
+type TxRequest struct {
+	Signer    signer.Signer
+	Operation string
+	To        *common.Address
+	Data      []byte
+	Value     *big.Int
+	GasLimit  uint64
+}
+
+// SentTx es una transacción enviada junto con el resumen de sus comisiones
+type SentTx struct {
+	Tx   *types.Transaction
+	Info *models.TransaccionEnviada
+}
+
+// pendingTx es una transacción enviada que aún no se ha confirmado
+type pendingTx struct {
+	tx             *types.Transaction
+	operation      string
+	gasEstimate    uint64
+	sentAt         time.Time
+	replacedHashes []string
+}
+
+// accountQueue serializa las transacciones de una cuenta y guarda su nonce
+type accountQueue struct {
+	address common.Address
+	jobs    chan *txJob
+
+	// next y pending solo los modifica el worker de la cola; mu protege las lecturas externas
+	mu      sync.Mutex
+	next    uint64
+	pending map[uint64]*pendingTx
+}
+
+type txJob struct {
+	ctx    context.Context
+	run    func(ctx context.Context, q *accountQueue) (*SentTx, error)
+	result chan txResult
+}
+
+type txResult struct {
+	sent *SentTx
+	err  error
+}
+
+// NonceManager asigna nonces por cuenta y envía sus transacciones en serie, de
+// modo que las solicitudes concurrentes de una misma cuenta no compitan por el
+// mismo nonce. Detecta huecos de nonces, se resincroniza con la cadena ante
+// errores y permite acelerar o cancelar transacciones atascadas.
+type NonceManager struct {
+	backend TxBackend
+	chainID *big.Int
+	options NonceManagerOptions
+
+	mu     sync.Mutex
+	queues map[common.Address]*accountQueue
+}
+
+// NewNonceManager crea un nuevo NonceManager
+func NewNonceManager(backend TxBackend, chainID *big.Int, options NonceManagerOptions) *NonceManager {
+	if options.QueueSize <= 0 {
+		options.QueueSize = 100
+	}
+	if options.MaxRetries <= 0 {
+		options.MaxRetries = 2
+	}
+	if options.StuckAfter <= 0 {
+		options.StuckAfter = 3 * time.Minute
+	}
+	if options.PriceBumpPercent < 10 {
+		// Los nodos exigen al menos un 10% para aceptar un reemplazo
+		options.PriceBumpPercent = 10
+	}
+	if options.Fees.GasMarginPercent <= 0 {
+		options.Fees.GasMarginPercent = 20
+	}
+	if options.Fees.BaseFeeMultiplier <= 0 {
+		options.Fees.BaseFeeMultiplier = 2
+	}
+
+	return &NonceManager{
+		backend: backend,
+		chainID: chainID,
+		options: options,
+		queues:  make(map[common.Address]*accountQueue),
+	}
+}
+
+// Submit encola la transacción en la cola de su cuenta y espera a que se envíe
+func (m *NonceManager) Submit(ctx context.Context, req TxRequest) (*SentTx, error) {
+	return m.enqueue(ctx, req.Signer.Address(), func(ctx context.Context, q *accountQueue) (*SentTx, error) {
+		return m.send(ctx, q, req)
+	})
+}
+
+// SpeedUp reenvía la transacción pendiente con ese nonce con un precio de gas mayor
+func (m *NonceManager) SpeedUp(ctx context.Context, firmante signer.Signer, nonce uint64) (*SentTx, error) {
+	return m.enqueue(ctx, firmante.Address(), func(ctx context.Context, q *accountQueue) (*SentTx, error) {
+		return m.replace(ctx, q, firmante, nonce, false)
+	})
+}
+
+// Cancel reemplaza la transacción pendiente con ese nonce por una transferencia
+// de 0 ETH a la propia cuenta con un precio de gas mayor
+func (m *NonceManager) Cancel(ctx context.Context, firmante signer.Signer, nonce uint64) (*SentTx, error) {
+	return m.enqueue(ctx, firmante.Address(), func(ctx context.Context, q *accountQueue) (*SentTx, error) {
+		return m.replace(ctx, q, firmante, nonce, true)
+	})
+}
+
+// Pendientes lista las transacciones enviadas por la cuenta que aún no se han
+// confirmado, marcando las atascadas
+func (m *NonceManager) Pendientes(address common.Address) []models.TransaccionPendiente {
+	q := m.queue(address)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	m.pruneLocked(context.Background(), q)
+
+	pendientes := make([]models.TransaccionPendiente, 0, len(q.pending))
+	for nonce, p := range q.pending {
+		pendiente := models.TransaccionPendiente{
+			Nonce:         nonce,
+			TxHash:        p.tx.Hash().Hex(),
+			Operacion:     p.operation,
+			MaxFeePerGas:  p.tx.GasFeeCap().String(),
+			EnviadaEn:     p.sentAt,
+			Atascada:      time.Since(p.sentAt) > m.options.StuckAfter,
+			HashesPrevios: append([]string(nil), p.replacedHashes...),
+		}
+		if p.tx.To() != nil {
+			pendiente.To = p.tx.To().Hex()
+		}
+		pendientes = append(pendientes, pendiente)
+	}
+	sort.Slice(pendientes, func(i, j int) bool { return pendientes[i].Nonce < pendientes[j].Nonce })
+	return pendientes
+}
+
+// queue devuelve la cola de la cuenta, creándola y arrancando su worker si no existe
+func (m *NonceManager) queue(address common.Address) *accountQueue {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q, ok := m.queues[address]
+	if !ok {
+		q = &accountQueue{
+			address: address,
+			jobs:    make(chan *txJob, m.options.QueueSize),
+			pending: make(map[uint64]*pendingTx),
+		}
+		m.queues[address] = q
+		go m.worker(q)
+	}
+	return q
+}
+
+// enqueue añade un trabajo a la cola de la cuenta y espera su resultado
+func (m *NonceManager) enqueue(ctx context.Context, address common.Address, run func(ctx context.Context, q *accountQueue) (*SentTx, error)) (*SentTx, error) {
+	job := &txJob{ctx: ctx, run: run, result: make(chan txResult, 1)}
+
+	select {
+	case m.queue(address).jobs <- job:
+	default:
+		return nil, fmt.Errorf("%w para %s", ErrTxQueueFull, address.Hex())
+	}
+
+	select {
+	case res := <-job.result:
+		return res.sent, res.err
+	case <-ctx.Done():
+		// El worker descarta el trabajo si aún no ha empezado
+		return nil, ctx.Err()
+	}
+}
+
+// worker ejecuta en orden los trabajos de una cuenta
+func (m *NonceManager) worker(q *accountQueue) {
+	for job := range q.jobs {
+		if err := job.ctx.Err(); err != nil {
+			job.result <- txResult{err: err}
+			continue
+		}
+		sent, err := job.run(job.ctx, q)
+		job.result <- txResult{sent: sent, err: err}
+	}
+}
+
+// send estima el gas, asigna el siguiente nonce, calcula las comisiones, firma
+// y envía la transacción. Ante un error de nonce se resincroniza con la cadena
+// y reintenta.
+func (m *NonceManager) send(ctx context.Context, q *accountQueue, req TxRequest) (*SentTx, error) {
+	value := req.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+
+	gasEstimate, gasLimit := uint64(0), req.GasLimit
+	if gasLimit == 0 {
+		var err error
+		gasEstimate, gasLimit, err = m.estimateGas(ctx, q.address, req, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		nonce, err := m.syncNonce(ctx, q)
+		if err != nil {
+			return nil, fmt.Errorf("error obteniendo nonce: %v", err)
+		}
+
+		fees, err := m.quoteFees(ctx, req.Operation)
+		if err != nil {
+			return nil, err
+		}
+
+		tx := fees.buildTx(m.chainID, nonce, req.To, value, gasLimit, req.Data)
+		signedTx, err := req.Signer.SignTx(tx, m.chainID)
+		if err != nil {
+			return nil, fmt.Errorf("error firmando transacción: %v", err)
+		}
+
+		err = m.backend.SendTransaction(ctx, signedTx)
+		if err == nil || isAlreadyKnown(err) {
+			m.track(q, &pendingTx{tx: signedTx, operation: req.Operation, gasEstimate: gasEstimate}, nil)
+			return &SentTx{
+				Tx:   signedTx,
+				Info: describeTx(signedTx, q.address, req.Operation, gasEstimate, fees.baseFee),
+			}, nil
+		}
+
+		if isNonceError(err) && attempt < m.options.MaxRetries {
+			log.Printf("[NONCE] Error de nonce %d para %s, resincronizando: %v", nonce, q.address.Hex(), err)
+			continue
+		}
+		return nil, fmt.Errorf("error enviando transacción: %v", err)
+	}
+}
+
+// syncNonce compara el nonce local con el de la cadena y devuelve el siguiente
+// nonce a usar. Si la cadena va por delante se adopta su nonce (transacciones
+// enviadas fuera del servicio). Si va por detrás hay un hueco: se reenvían las
+// transacciones pendientes que lo cubren y, si alguna no se puede reenviar,
+// se reutilizan los nonces desde ese punto.
+func (m *NonceManager) syncNonce(ctx context.Context, q *accountQueue) (uint64, error) {
+	chainNonce, err := m.backend.PendingNonceAt(ctx, q.address)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	m.pruneLocked(ctx, q)
+
+	switch {
+	case chainNonce > q.next:
+		if q.next > 0 {
+			log.Printf("[NONCE] %s: nonce de la cadena %d por delante del local %d, adoptándolo", q.address.Hex(), chainNonce, q.next)
+		}
+		q.next = chainNonce
+
+	case chainNonce < q.next:
+		log.Printf("[NONCE] %s: hueco de nonces %d-%d, reenviando pendientes", q.address.Hex(), chainNonce, q.next-1)
+		filled := chainNonce
+		for ; filled < q.next; filled++ {
+			p, ok := q.pending[filled]
+			if !ok {
+				break
+			}
+			if err := m.backend.SendTransaction(ctx, p.tx); err != nil && !isAlreadyKnown(err) {
+				log.Printf("[NONCE] %s: no se pudo reenviar nonce %d: %v", q.address.Hex(), filled, err)
+				break
+			}
+		}
+		if filled < q.next {
+			for nonce := filled; nonce < q.next; nonce++ {
+				delete(q.pending, nonce)
+			}
+			log.Printf("[NONCE] %s: reutilizando nonces desde %d", q.address.Hex(), filled)
+			q.next = filled
+		}
+	}
+
+	return q.next, nil
+}
+
+// pruneLocked olvida las transacciones pendientes cuyo nonce ya fue minado; el
+// llamador debe tener q.mu
+func (m *NonceManager) pruneLocked(ctx context.Context, q *accountQueue) {
+	confirmed, err := m.backend.NonceAt(ctx, q.address, nil)
+	if err != nil {
+		return
+	}
+	for nonce := range q.pending {
+		if nonce < confirmed {
+			delete(q.pending, nonce)
+		}
+	}
+}
+
+// replace envía una transacción con el mismo nonce que una pendiente y un
+// precio de gas mayor, acelerándola o cancelándola
+func (m *NonceManager) replace(ctx context.Context, q *accountQueue, firmante signer.Signer, nonce uint64, cancel bool) (*SentTx, error) {
+	q.mu.Lock()
+	p, ok := q.pending[nonce]
+	q.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrTxNotPending, nonce)
+	}
+
+	confirmed, err := m.backend.NonceAt(ctx, q.address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo nonce confirmado: %v", err)
+	}
+	if nonce < confirmed {
+		q.mu.Lock()
+		delete(q.pending, nonce)
+		q.mu.Unlock()
+		return nil, fmt.Errorf("%w: nonce %d", ErrTxAlreadyMined, nonce)
+	}
+
+	operation := p.operation
+	if cancel {
+		operation = OpCancelar
+	}
+	fees, err := m.bumpFees(ctx, p.tx, operation)
+	if err != nil {
+		return nil, err
+	}
+
+	var tx *types.Transaction
+	gasEstimate := p.gasEstimate
+	if cancel {
+		self := q.address
+		gasEstimate = params.TxGas
+		tx = fees.buildTx(m.chainID, nonce, &self, big.NewInt(0), params.TxGas, nil)
+	} else {
+		tx = fees.buildTx(m.chainID, nonce, p.tx.To(), p.tx.Value(), p.tx.Gas(), p.tx.Data())
+	}
+
+	signedTx, err := firmante.SignTx(tx, m.chainID)
+	if err != nil {
+		return nil, fmt.Errorf("error firmando transacción: %v", err)
+	}
+
+	if err := m.backend.SendTransaction(ctx, signedTx); err != nil && !isAlreadyKnown(err) {
+		return nil, fmt.Errorf("error enviando reemplazo: %v", err)
+	}
+
+	log.Printf("[NONCE] %s: nonce %d reemplazado (cancelar=%t) %s -> %s, maxFeePerGas %s",
+		q.address.Hex(), nonce, cancel, p.tx.Hash().Hex(), signedTx.Hash().Hex(), signedTx.GasFeeCap().String())
+	m.track(q, &pendingTx{tx: signedTx, operation: operation, gasEstimate: gasEstimate}, p)
+	return &SentTx{
+		Tx:   signedTx,
+		Info: describeTx(signedTx, q.address, operation, gasEstimate, fees.baseFee),
+	}, nil
+}
+
+// track registra una transacción enviada como pendiente y avanza el nonce
+func (m *NonceManager) track(q *accountQueue, p *pendingTx, replaced *pendingTx) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	p.sentAt = time.Now()
+	if replaced != nil {
+		p.replacedHashes = append(append([]string(nil), replaced.replacedHashes...), replaced.tx.Hash().Hex())
+	}
+	nonce := p.tx.Nonce()
+	q.pending[nonce] = p
+
+	if nonce >= q.next {
+		q.next = nonce + 1
+	}
+}
+
+// bumpGasPrice aumenta el precio un porcentaje, redondeando hacia arriba
+func bumpGasPrice(price *big.Int, percent int64) *big.Int {
+	bumped := new(big.Int).Mul(price, big.NewInt(100+percent))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+// isNonceError indica que el nonce usado ya no es válido para la cadena
+func isNonceError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") ||
+		strings.Contains(msg, "nonce too high") ||
+		strings.Contains(msg, "invalid transaction nonce") ||
+		strings.Contains(msg, "replacement transaction underpriced")
+}
+
+// isAlreadyKnown indica que el nodo ya tenía la misma transacción
+func isAlreadyKnown(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction")
+}
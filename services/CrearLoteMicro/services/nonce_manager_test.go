@@ -0,0 +1,295 @@
+package services
+
+import (
+	"CrearLoteMicro/signer"
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var recipient = common.HexToAddress("0x000000000000000000000000000000000000bEEF")
+
+// flakyBackend envuelve el backend simulado y permite inyectar errores al enviar
+type flakyBackend struct {
+	*backends.SimulatedBackend
+
+	mu     sync.Mutex
+	failOn func(tx *types.Transaction) error
+}
+
+func (b *flakyBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	failOn := b.failOn
+	b.mu.Unlock()
+
+	if failOn != nil {
+		if err := failOn(tx); err != nil {
+			return err
+		}
+	}
+	return b.SimulatedBackend.SendTransaction(ctx, tx)
+}
+
+func (b *flakyBackend) setFailOn(failOn func(tx *types.Transaction) error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failOn = failOn
+}
+
+func newTestNonceManager(t *testing.T) (*NonceManager, *flakyBackend, signer.Signer) {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	firmante, err := signer.NewKeySigner(common.Bytes2Hex(crypto.FromECDSA(key)), signer.KindEnv)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+
+	sim := backends.NewSimulatedBackend(core.GenesisAlloc{
+		firmante.Address(): {Balance: new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))},
+	}, 30_000_000)
+	t.Cleanup(func() { sim.Close() })
+
+	backend := &flakyBackend{SimulatedBackend: sim}
+	manager := NewNonceManager(backend, params.AllDevChainProtocolChanges.ChainID, NonceManagerOptions{})
+	return manager, backend, firmante
+}
+
+func transfer(firmante signer.Signer) TxRequest {
+	return TxRequest{
+		Signer:   firmante,
+		To:       &recipient,
+		Value:    big.NewInt(1),
+		GasLimit: 21000,
+	}
+}
+
+func TestNonceManager_ConcurrentSubmits(t *testing.T) {
+	manager, backend, firmante := newTestNonceManager(t)
+
+	const total = 20
+	var wg sync.WaitGroup
+	nonces := make(chan uint64, total)
+	errs := make(chan error, total)
+	for i := 0; i < total; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sent, err := manager.Submit(context.Background(), transfer(firmante))
+			if err != nil {
+				errs <- err
+				return
+			}
+			nonces <- sent.Tx.Nonce()
+		}()
+	}
+	wg.Wait()
+	close(nonces)
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	seen := make(map[uint64]bool)
+	for nonce := range nonces {
+		if seen[nonce] {
+			t.Errorf("Expected unique nonces, got %d twice", nonce)
+		}
+		seen[nonce] = true
+	}
+	if len(seen) != total {
+		t.Fatalf("Expected %d transactions, got %d", total, len(seen))
+	}
+
+	backend.Commit()
+	mined, _ := backend.NonceAt(context.Background(), firmante.Address(), nil)
+	if mined != total {
+		t.Errorf("Expected %d mined transactions, got %d", total, mined)
+	}
+	if pending := manager.Pendientes(firmante.Address()); len(pending) != 0 {
+		t.Errorf("Expected no pending transactions after mining, got %d", len(pending))
+	}
+}
+
+func TestNonceManager_AdoptsChainNonceWhenAhead(t *testing.T) {
+	manager, backend, firmante := newTestNonceManager(t)
+
+	if _, err := manager.Submit(context.Background(), transfer(firmante)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Transacción enviada fuera del gestor con el nonce 1
+	gasPrice, _ := backend.SuggestGasPrice(context.Background())
+	external, _ := firmante.SignTx(types.NewTransaction(1, recipient, big.NewInt(1), 21000, gasPrice, nil), params.AllDevChainProtocolChanges.ChainID)
+	if err := backend.SendTransaction(context.Background(), external); err != nil {
+		t.Fatalf("Failed to send external transaction: %v", err)
+	}
+
+	sent, err := manager.Submit(context.Background(), transfer(firmante))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sent.Tx.Nonce() != 2 {
+		t.Errorf("Expected nonce 2, got %d", sent.Tx.Nonce())
+	}
+}
+
+func TestNonceManager_RetriesAfterNonceError(t *testing.T) {
+	manager, backend, firmante := newTestNonceManager(t)
+
+	failures := 0
+	backend.setFailOn(func(tx *types.Transaction) error {
+		if failures == 0 {
+			failures++
+			return errors.New("nonce too low")
+		}
+		return nil
+	})
+
+	sent, err := manager.Submit(context.Background(), transfer(firmante))
+	if err != nil {
+		t.Fatalf("Expected retry to succeed, got %v", err)
+	}
+	if failures != 1 || sent.Tx.Nonce() != 0 {
+		t.Errorf("Expected one failure and nonce 0, got %d failures and nonce %d", failures, sent.Tx.Nonce())
+	}
+}
+
+func TestNonceManager_RebroadcastsDroppedTransactions(t *testing.T) {
+	manager, backend, firmante := newTestNonceManager(t)
+
+	for i := 0; i < 2; i++ {
+		if _, err := manager.Submit(context.Background(), transfer(firmante)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	// El nodo descarta las transacciones pendientes: hueco en los nonces 0-1
+	backend.Rollback()
+
+	sent, err := manager.Submit(context.Background(), transfer(firmante))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sent.Tx.Nonce() != 2 {
+		t.Errorf("Expected nonce 2 after refilling the gap, got %d", sent.Tx.Nonce())
+	}
+
+	backend.Commit()
+	mined, _ := backend.NonceAt(context.Background(), firmante.Address(), nil)
+	if mined != 3 {
+		t.Errorf("Expected 3 mined transactions, got %d", mined)
+	}
+}
+
+func TestNonceManager_ReusesNoncesWhenGapCannotBeFilled(t *testing.T) {
+	manager, backend, firmante := newTestNonceManager(t)
+
+	dropped, err := manager.Submit(context.Background(), transfer(firmante))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	backend.Rollback()
+
+	// El reenvío de la transacción descartada falla
+	backend.setFailOn(func(tx *types.Transaction) error {
+		if tx.Hash() == dropped.Tx.Hash() {
+			return errors.New("insufficient funds for gas * price + value")
+		}
+		return nil
+	})
+
+	// Una transferencia distinta para que no coincida con el hash descartado
+	req := transfer(firmante)
+	req.Value = big.NewInt(2)
+	sent, err := manager.Submit(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sent.Tx.Nonce() != 0 {
+		t.Errorf("Expected nonce 0 to be reused, got %d", sent.Tx.Nonce())
+	}
+}
+
+func TestNonceManager_SpeedUp(t *testing.T) {
+	manager, backend, firmante := newTestNonceManager(t)
+
+	sent, err := manager.Submit(context.Background(), transfer(firmante))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	original := sent.Tx
+	backend.Rollback()
+
+	bumped, err := manager.SpeedUp(context.Background(), firmante, original.Nonce())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	replacement := bumped.Tx
+
+	minFeeCap := bumpGasPrice(original.GasFeeCap(), 10)
+	minTipCap := bumpGasPrice(original.GasTipCap(), 10)
+	if replacement.GasFeeCap().Cmp(minFeeCap) < 0 || replacement.GasTipCap().Cmp(minTipCap) < 0 {
+		t.Errorf("Expected fee cap >= %s and tip cap >= %s, got %s and %s",
+			minFeeCap, minTipCap, replacement.GasFeeCap(), replacement.GasTipCap())
+	}
+	if replacement.Nonce() != original.Nonce() || *replacement.To() != recipient || replacement.Value().Cmp(original.Value()) != 0 {
+		t.Errorf("Expected same nonce, recipient and value as the original")
+	}
+
+	pending := manager.Pendientes(firmante.Address())
+	if len(pending) != 1 || pending[0].TxHash != replacement.Hash().Hex() ||
+		len(pending[0].HashesPrevios) != 1 || pending[0].HashesPrevios[0] != original.Hash().Hex() {
+		t.Errorf("Expected pending replacement tracking the original hash, got %+v", pending)
+	}
+
+	backend.Commit()
+	if _, err := manager.SpeedUp(context.Background(), firmante, original.Nonce()); !errors.Is(err, ErrTxAlreadyMined) && !errors.Is(err, ErrTxNotPending) {
+		t.Errorf("Expected mined transaction to be rejected, got %v", err)
+	}
+}
+
+func TestNonceManager_Cancel(t *testing.T) {
+	manager, backend, firmante := newTestNonceManager(t)
+
+	original, err := manager.Submit(context.Background(), transfer(firmante))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	backend.Rollback()
+
+	sent, err := manager.Cancel(context.Background(), firmante, original.Tx.Nonce())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	cancel := sent.Tx
+	if sent.Info.Operacion != OpCancelar {
+		t.Errorf("Expected operation %s, got %s", OpCancelar, sent.Info.Operacion)
+	}
+	if *cancel.To() != firmante.Address() || cancel.Value().Sign() != 0 || len(cancel.Data()) != 0 {
+		t.Errorf("Expected empty self transfer, got to=%s value=%s", cancel.To().Hex(), cancel.Value())
+	}
+
+	if _, err := manager.Cancel(context.Background(), firmante, 5); !errors.Is(err, ErrTxNotPending) {
+		t.Errorf("Expected ErrTxNotPending, got %v", err)
+	}
+
+	backend.Commit()
+	balance, _ := backend.BalanceAt(context.Background(), recipient, nil)
+	if balance.Sign() != 0 {
+		t.Errorf("Expected cancelled transfer not to reach the recipient, got balance %s", balance)
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// SimulatedChain es una blockchain en memoria sobre el backend simulado de
+// go-ethereum, para usar el servicio sin Sepolia ni proveedor RPC. Cada
+// transacción recibida se mina de inmediato en su propio bloque.
+type SimulatedChain struct {
+	*backends.SimulatedBackend
+	blockTime time.Duration
+}
+
+// SimulatedChainOptions configura la blockchain simulada
+type SimulatedChainOptions struct {
+	// Accounts son las cuentas con saldo en el bloque génesis
+	Accounts []common.Address
+	// Balance es el saldo inicial de cada cuenta en wei
+	Balance *big.Int
+	// GasLimit es el límite de gas por bloque
+	GasLimit uint64
+	// BlockTime es el intervalo de los bloques vacíos que hacen avanzar las
+	// confirmaciones; 0 solo mina al recibir transacciones
+	BlockTime time.Duration
+}
+
+// NewSimulatedChain crea la blockchain con las cuentas indicadas ya financiadas
+func NewSimulatedChain(opts SimulatedChainOptions) *SimulatedChain {
+	if opts.Balance == nil {
+		opts.Balance = new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+	}
+	if opts.GasLimit == 0 {
+		opts.GasLimit = 30_000_000
+	}
+
+	alloc := make(core.GenesisAlloc)
+	for _, account := range opts.Accounts {
+		alloc[account] = core.GenesisAccount{Balance: opts.Balance}
+	}
+
+	return &SimulatedChain{
+		SimulatedBackend: backends.NewSimulatedBackend(alloc, opts.GasLimit),
+		blockTime:        opts.BlockTime,
+	}
+}
+
+// SendTransaction añade la transacción y la mina en un bloque nuevo
+func (c *SimulatedChain) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := c.SimulatedBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	c.Commit()
+	return nil
+}
+
+// BlockNumber devuelve el número del último bloque
+func (c *SimulatedChain) BlockNumber(ctx context.Context) (uint64, error) {
+	return c.Blockchain().CurrentBlock().Number.Uint64(), nil
+}
+
+// ChainID devuelve el chain ID de la blockchain simulada (1337)
+func (c *SimulatedChain) ChainID(ctx context.Context) (*big.Int, error) {
+	return c.Blockchain().Config().ChainID, nil
+}
+
+// Run mina un bloque vacío cada BlockTime hasta que se cancele el contexto
+func (c *SimulatedChain) Run(ctx context.Context) {
+	if c.blockTime <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(c.blockTime)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Commit()
+		}
+	}
+}
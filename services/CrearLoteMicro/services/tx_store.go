@@ -0,0 +1,52 @@
+package services
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// TxStore guarda el estado de las transacciones seguidas para que sobreviva a
+// los reinicios del servicio
+type TxStore interface {
+	Load() ([]models.EstadoTransaccion, error)
+	Save(estados []models.EstadoTransaccion) error
+}
+
+// FileTxStore guarda los estados en un archivo JSON
+type FileTxStore struct {
+	path string
+}
+
+// NewFileTxStore crea un almacén en el archivo indicado
+func NewFileTxStore(path string) *FileTxStore {
+	return &FileTxStore{path: path}
+}
+
+// Load lee los estados guardados; un archivo inexistente equivale a ninguno
+func (s *FileTxStore) Load() ([]models.EstadoTransaccion, error) {
+	content, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo %s: %v", s.path, err)
+	}
+
+	var estados []models.EstadoTransaccion
+	if err := json.Unmarshal(content, &estados); err != nil {
+		return nil, fmt.Errorf("error parseando %s: %v", s.path, err)
+	}
+	return estados, nil
+}
+
+// Save reemplaza el archivo de forma atómica
+func (s *FileTxStore) Save(estados []models.EstadoTransaccion) error {
+	sort.Slice(estados, func(i, j int) bool { return estados[i].EnviadaEn.Before(estados[j].EnviadaEn) })
+
+	return utils.GuardarJSONAtomico(s.path, estados)
+}
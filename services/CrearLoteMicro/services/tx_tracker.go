@@ -0,0 +1,417 @@
+package services
+
+import (
+	"CrearLoteMicro/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrTxNotTracked se devuelve al consultar una transacción que el servicio no envió
+var ErrTxNotTracked = errors.New("transacción no enviada por este servicio")
+
+// ReceiptBackend es lo que el seguimiento de transacciones necesita de la
+// blockchain. Lo implementan ethclient.Client y el backend simulado.
+type ReceiptBackend interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// TxTrackerOptions configura el seguimiento de transacciones
+type TxTrackerOptions struct {
+	// Confirmations es el número de bloques, contando el de la transacción,
+	// tras el cual su estado se considera definitivo
+	Confirmations uint64
+	// PollInterval es la frecuencia con la que se consultan los recibos
+	PollInterval time.Duration
+	// WaitTimeout es lo máximo que espera una solicitud con ?wait=true
+	WaitTimeout time.Duration
+	// Retention es cuánto se conservan las transacciones ya definitivas
+	Retention time.Duration
+	// Store persiste los estados; nil los mantiene solo en memoria
+	Store TxStore
+	// OnFinal recibe una copia de cada transacción que pasa a ser definitiva,
+	// en orden de nonce y, con el mismo nonce, las reemplazadas primero
+	OnFinal func(estado models.EstadoTransaccion)
+}
+
+// TxTracker sigue las transacciones enviadas hasta que alcanzan las
+// confirmaciones requeridas: detecta reversiones con su motivo, reemplazos del
+// nonce y reorganizaciones de la cadena, y guarda el estado en el TxStore.
+type TxTracker struct {
+	backend ReceiptBackend
+	options TxTrackerOptions
+
+	mu      sync.Mutex
+	estados map[common.Hash]*models.EstadoTransaccion
+	// changed se cierra y se reemplaza en cada actualización para despertar a los que esperan
+	changed chan struct{}
+}
+
+// NewTxTracker crea el seguimiento y carga los estados guardados
+func NewTxTracker(backend ReceiptBackend, options TxTrackerOptions) (*TxTracker, error) {
+	if options.Confirmations == 0 {
+		options.Confirmations = 1
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = 4 * time.Second
+	}
+	if options.WaitTimeout <= 0 {
+		options.WaitTimeout = 2 * time.Minute
+	}
+	if options.Retention <= 0 {
+		options.Retention = 7 * 24 * time.Hour
+	}
+
+	t := &TxTracker{
+		backend: backend,
+		options: options,
+		estados: make(map[common.Hash]*models.EstadoTransaccion),
+		changed: make(chan struct{}),
+	}
+
+	if options.Store != nil {
+		estados, err := options.Store.Load()
+		if err != nil {
+			return nil, fmt.Errorf("error cargando estados de transacciones: %v", err)
+		}
+		for i := range estados {
+			estado := estados[i]
+			t.estados[common.HexToHash(estado.TxHash)] = &estado
+		}
+	}
+
+	return t, nil
+}
+
+// Run consulta periódicamente las transacciones no definitivas hasta que se
+// cancela el contexto
+func (t *TxTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.poll(ctx)
+		}
+	}
+}
+
+// Track empieza a seguir una transacción enviada
+func (t *TxTracker) Track(sent *SentTx) {
+	now := time.Now()
+	estado := &models.EstadoTransaccion{
+		TxHash:                   sent.Info.TxHash,
+		From:                     sent.Info.From,
+		Nonce:                    sent.Info.Nonce,
+		Operacion:                sent.Info.Operacion,
+		Estado:                   models.EstadoPendiente,
+		ConfirmacionesRequeridas: t.options.Confirmations,
+		EnviadaEn:                now,
+		ActualizadaEn:            now,
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	hash := sent.Tx.Hash()
+	if _, ok := t.estados[hash]; ok {
+		return
+	}
+	t.estados[hash] = estado
+	t.saveLocked()
+}
+
+// Estado devuelve el último estado conocido de una transacción
+func (t *TxTracker) Estado(hash common.Hash) (*models.EstadoTransaccion, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	estado, ok := t.estados[hash]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTxNotTracked, hash.Hex())
+	}
+	copia := *estado
+	return &copia, nil
+}
+
+// Esperar bloquea hasta que la transacción sea definitiva o venza el contexto;
+// en ese caso devuelve el último estado conocido junto con el error del contexto
+func (t *TxTracker) Esperar(ctx context.Context, hash common.Hash) (*models.EstadoTransaccion, error) {
+	for {
+		t.mu.Lock()
+		estado, ok := t.estados[hash]
+		var copia models.EstadoTransaccion
+		if ok {
+			copia = *estado
+		}
+		changed := t.changed
+		t.mu.Unlock()
+
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTxNotTracked, hash.Hex())
+		}
+		if copia.Final {
+			return &copia, nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return &copia, ctx.Err()
+		}
+	}
+}
+
+// poll actualiza las transacciones que aún no son definitivas
+func (t *TxTracker) poll(ctx context.Context) {
+	t.mu.Lock()
+	var abiertas []models.EstadoTransaccion
+	for _, estado := range t.estados {
+		if !estado.Final {
+			abiertas = append(abiertas, *estado)
+		}
+	}
+	t.mu.Unlock()
+
+	if len(abiertas) > 0 {
+		head, err := t.backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			log.Printf("[TX] Error obteniendo último bloque: %v", err)
+			return
+		}
+		for i := range abiertas {
+			t.refresh(ctx, &abiertas[i], head.Number.Uint64())
+		}
+	}
+
+	t.mu.Lock()
+	for i := range abiertas {
+		estado := abiertas[i]
+		t.estados[common.HexToHash(estado.TxHash)] = &estado
+	}
+	t.linkReplacementsLocked()
+
+	var finales []models.EstadoTransaccion
+	for i := range abiertas {
+		if estado := t.estados[common.HexToHash(abiertas[i].TxHash)]; estado.Final {
+			finales = append(finales, *estado)
+		}
+	}
+	t.pruneLocked()
+	t.saveLocked()
+
+	close(t.changed)
+	t.changed = make(chan struct{})
+	t.mu.Unlock()
+
+	if t.options.OnFinal == nil {
+		return
+	}
+	sort.Slice(finales, func(i, j int) bool {
+		a, b := finales[i], finales[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.Nonce != b.Nonce {
+			return a.Nonce < b.Nonce
+		}
+		return a.Estado == models.EstadoReemplazada && b.Estado != models.EstadoReemplazada
+	})
+	for _, estado := range finales {
+		t.options.OnFinal(estado)
+	}
+}
+
+// refresh consulta el recibo de la transacción y actualiza su estado
+func (t *TxTracker) refresh(ctx context.Context, estado *models.EstadoTransaccion, head uint64) {
+	hash := common.HexToHash(estado.TxHash)
+	receipt, err := t.backend.TransactionReceipt(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		if estado.BlockHash != "" {
+			// El bloque que la incluía ya no es canónico
+			log.Printf("[TX] %s salió de la cadena canónica en el bloque %d (reorg)", estado.TxHash, estado.BlockNumber)
+			resetBlock(estado)
+		}
+
+		// Si otro envío consumió el nonce, esta transacción ya no se minará
+		confirmed, err := t.backend.NonceAt(ctx, common.HexToAddress(estado.From), nil)
+		if err == nil && confirmed > estado.Nonce {
+			estado.Estado = models.EstadoReemplazada
+			estado.Final = true
+		}
+		estado.ActualizadaEn = time.Now()
+		return
+	}
+	if err != nil {
+		log.Printf("[TX] Error obteniendo recibo de %s: %v", estado.TxHash, err)
+		return
+	}
+
+	if estado.BlockHash != "" && estado.BlockHash != receipt.BlockHash.Hex() {
+		log.Printf("[TX] %s pasó del bloque %s al %s (reorg)", estado.TxHash, estado.BlockHash, receipt.BlockHash.Hex())
+		resetBlock(estado)
+	}
+
+	estado.BlockNumber = receipt.BlockNumber.Uint64()
+	estado.BlockHash = receipt.BlockHash.Hex()
+	estado.GasUsado = receipt.GasUsed
+	if receipt.ContractAddress != (common.Address{}) {
+		estado.ContractAddress = receipt.ContractAddress.Hex()
+	}
+
+	if receipt.Status == types.ReceiptStatusFailed {
+		estado.Estado = models.EstadoRevertida
+		if estado.MotivoReversion == "" {
+			estado.MotivoReversion = t.revertReason(ctx, estado, receipt)
+		}
+	} else {
+		estado.Estado = models.EstadoMinada
+	}
+
+	estado.Confirmaciones = 0
+	if head >= estado.BlockNumber {
+		estado.Confirmaciones = head - estado.BlockNumber + 1
+	}
+	if estado.Confirmaciones >= estado.ConfirmacionesRequeridas {
+		estado.Final = true
+		if estado.Estado == models.EstadoMinada {
+			estado.Estado = models.EstadoConfirmada
+		}
+	}
+	estado.ActualizadaEn = time.Now()
+}
+
+// resetBlock vuelve la transacción a pendiente tras una reorganización
+func resetBlock(estado *models.EstadoTransaccion) {
+	estado.Reorgs++
+	estado.Estado = models.EstadoPendiente
+	estado.BlockNumber = 0
+	estado.BlockHash = ""
+	estado.Confirmaciones = 0
+	estado.GasUsado = 0
+	estado.ContractAddress = ""
+	estado.MotivoReversion = ""
+}
+
+// revertReason repite la llamada de una transacción revertida para obtener el
+// motivo del require. Primero sobre el estado del bloque anterior y, si el nodo
+// no guarda estado histórico, sobre el último bloque.
+func (t *TxTracker) revertReason(ctx context.Context, estado *models.EstadoTransaccion, receipt *types.Receipt) string {
+	tx, _, err := t.backend.TransactionByHash(ctx, receipt.TxHash)
+	if err != nil {
+		log.Printf("[TX] No se pudo obtener %s para el motivo de reversión: %v", estado.TxHash, err)
+		return ""
+	}
+
+	msg := ethereum.CallMsg{
+		From:  common.HexToAddress(estado.From),
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+
+	parent := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+	_, err = t.backend.CallContract(ctx, msg, parent)
+	if reason, ok := decodeRevertReason(err); ok {
+		return reason
+	}
+	_, err = t.backend.CallContract(ctx, msg, nil)
+	if reason, ok := decodeRevertReason(err); ok {
+		return reason
+	}
+
+	if receipt.GasUsed == tx.Gas() {
+		return "gas agotado"
+	}
+	return ""
+}
+
+// decodeRevertReason extrae el motivo de un error de ejecución revertida
+func decodeRevertReason(err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			if revert, decodeErr := hexutil.Decode(data); decodeErr == nil && len(revert) > 0 {
+				if reason, unpackErr := abi.UnpackRevert(revert); unpackErr == nil {
+					return reason, true
+				}
+				// Error personalizado: se devuelve el selector y los datos
+				return data, true
+			}
+		}
+	}
+
+	msg := err.Error()
+	if i := strings.Index(msg, "execution reverted"); i >= 0 {
+		reason := strings.TrimPrefix(msg[i:], "execution reverted")
+		return strings.TrimSpace(strings.TrimPrefix(reason, ":")), true
+	}
+	return "", false
+}
+
+// linkReplacementsLocked indica en las transacciones reemplazadas cuál minó
+// su nonce, si fue una enviada por el servicio; el llamador debe tener t.mu
+func (t *TxTracker) linkReplacementsLocked() {
+	for _, estado := range t.estados {
+		if estado.Estado != models.EstadoReemplazada || estado.ReemplazadaPor != "" {
+			continue
+		}
+		for _, otro := range t.estados {
+			if otro.From == estado.From && otro.Nonce == estado.Nonce && otro.BlockHash != "" {
+				estado.ReemplazadaPor = otro.TxHash
+				break
+			}
+		}
+	}
+}
+
+// pruneLocked olvida las transacciones definitivas más antiguas que la
+// retención; el llamador debe tener t.mu
+func (t *TxTracker) pruneLocked() {
+	limite := time.Now().Add(-t.options.Retention)
+	for hash, estado := range t.estados {
+		if estado.Final && estado.ActualizadaEn.Before(limite) {
+			delete(t.estados, hash)
+		}
+	}
+}
+
+// saveLocked persiste los estados; el llamador debe tener t.mu
+func (t *TxTracker) saveLocked() {
+	if t.options.Store == nil {
+		return
+	}
+
+	estados := make([]models.EstadoTransaccion, 0, len(t.estados))
+	for _, estado := range t.estados {
+		estados = append(estados, *estado)
+	}
+	if err := t.options.Store.Save(estados); err != nil {
+		log.Printf("[TX] Error guardando estados de transacciones: %v", err)
+	}
+}
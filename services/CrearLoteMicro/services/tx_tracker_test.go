@@ -0,0 +1,241 @@
+package services
+
+import (
+	"CrearLoteMicro/bindings"
+	"CrearLoteMicro/models"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func newTestTracker(t *testing.T, backend ReceiptBackend, confirmations uint64) *TxTracker {
+	t.Helper()
+	tracker, err := NewTxTracker(backend, TxTrackerOptions{Confirmations: confirmations})
+	if err != nil {
+		t.Fatalf("Failed to create tracker: %v", err)
+	}
+	return tracker
+}
+
+func trackerEstado(t *testing.T, tracker *TxTracker, sent *SentTx) *models.EstadoTransaccion {
+	t.Helper()
+	estado, err := tracker.Estado(sent.Tx.Hash())
+	if err != nil {
+		t.Fatalf("Expected tracked transaction, got %v", err)
+	}
+	return estado
+}
+
+func TestTxTracker_ConfirmsDeployAndDecodesRevert(t *testing.T) {
+	manager, backend, firmante := newTestNonceManager(t)
+	tracker := newTestTracker(t, backend, 2)
+	ctx := context.Background()
+
+	deploy, err := manager.Submit(ctx, TxRequest{
+		Signer:    firmante,
+		Operation: OpDeploy,
+		Data:      deployData(t, "LOTE001", 2, 8),
+	})
+	if err != nil {
+		t.Fatalf("Expected deploy to be sent, got %v", err)
+	}
+	tracker.Track(deploy)
+
+	backend.Commit()
+	tracker.poll(ctx)
+	estado := trackerEstado(t, tracker, deploy)
+	contractAddress := crypto.CreateAddress(firmante.Address(), 0)
+	if estado.Estado != models.EstadoMinada || estado.Final || estado.Confirmaciones != 1 {
+		t.Errorf("Expected mined with 1 confirmation, got %+v", estado)
+	}
+	if estado.ContractAddress != contractAddress.Hex() {
+		t.Errorf("Expected contract address %s, got %s", contractAddress.Hex(), estado.ContractAddress)
+	}
+
+	backend.Commit()
+	tracker.poll(ctx)
+	if estado := trackerEstado(t, tracker, deploy); estado.Estado != models.EstadoConfirmada || !estado.Final {
+		t.Errorf("Expected confirmed after 2 blocks, got %+v", estado)
+	}
+
+	// transferirCustodia ya no cambia la custodia y revierte con su motivo
+	contractABI, _ := bindings.LoteTracingMetaData.GetAbi()
+	data, err := contractABI.Pack("transferirCustodia", recipient)
+	if err != nil {
+		t.Fatalf("Failed to pack transferirCustodia: %v", err)
+	}
+
+	reverted, err := manager.Submit(ctx, TxRequest{
+		Signer:    firmante,
+		Operation: OpTransferirCustodia,
+		To:        &contractAddress,
+		Data:      data,
+		GasLimit:  200000,
+	})
+	if err != nil {
+		t.Fatalf("Expected reverting transaction to be sent, got %v", err)
+	}
+	tracker.Track(reverted)
+	backend.Commit()
+	tracker.poll(ctx)
+
+	estado = trackerEstado(t, tracker, reverted)
+	if estado.Estado != models.EstadoRevertida {
+		t.Fatalf("Expected reverted, got %+v", estado)
+	}
+	if estado.MotivoReversion != "Use proponerCustodia y aceptarCustodia" {
+		t.Errorf("Expected decoded revert reason, got %q", estado.MotivoReversion)
+	}
+}
+
+func TestTxTracker_DetectsReorg(t *testing.T) {
+	manager, backend, firmante := newTestNonceManager(t)
+	tracker := newTestTracker(t, backend, 3)
+	ctx := context.Background()
+
+	sent, err := manager.Submit(ctx, transfer(firmante))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	tracker.Track(sent)
+	backend.Commit()
+	tracker.poll(ctx)
+
+	minedIn := trackerEstado(t, tracker, sent).BlockHash
+	if minedIn == "" {
+		t.Fatal("Expected transaction to be mined")
+	}
+
+	// Una cadena lateral más larga sin la transacción pasa a ser canónica
+	genesis := backend.Blockchain().Genesis().Hash()
+	if err := backend.Fork(ctx, genesis); err != nil {
+		t.Fatalf("Failed to fork: %v", err)
+	}
+	backend.Commit()
+	backend.Commit()
+	tracker.poll(ctx)
+
+	estado := trackerEstado(t, tracker, sent)
+	if estado.Estado != models.EstadoPendiente || estado.Reorgs != 1 || estado.BlockHash != "" {
+		t.Fatalf("Expected pending after reorg, got %+v", estado)
+	}
+
+	// Se vuelve a incluir en otro bloque
+	if err := backend.SendTransaction(ctx, sent.Tx); err != nil {
+		t.Fatalf("Failed to resend transaction: %v", err)
+	}
+	backend.Commit()
+	tracker.poll(ctx)
+
+	estado = trackerEstado(t, tracker, sent)
+	if estado.Estado != models.EstadoMinada || estado.BlockHash == minedIn || estado.BlockNumber != 3 {
+		t.Errorf("Expected mined again in block 3, got %+v", estado)
+	}
+}
+
+func TestTxTracker_MarksReplacedTransactions(t *testing.T) {
+	manager, backend, firmante := newTestNonceManager(t)
+	tracker := newTestTracker(t, backend, 1)
+	ctx := context.Background()
+
+	original, err := manager.Submit(ctx, transfer(firmante))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	tracker.Track(original)
+	backend.Rollback()
+
+	replacement, err := manager.SpeedUp(ctx, firmante, original.Tx.Nonce())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	tracker.Track(replacement)
+	backend.Commit()
+	tracker.poll(ctx)
+
+	estado := trackerEstado(t, tracker, original)
+	if estado.Estado != models.EstadoReemplazada || !estado.Final || estado.ReemplazadaPor != replacement.Tx.Hash().Hex() {
+		t.Errorf("Expected original replaced by %s, got %+v", replacement.Tx.Hash().Hex(), estado)
+	}
+	if estado := trackerEstado(t, tracker, replacement); estado.Estado != models.EstadoConfirmada {
+		t.Errorf("Expected replacement confirmed, got %+v", estado)
+	}
+}
+
+func TestTxTracker_Esperar(t *testing.T) {
+	manager, backend, firmante := newTestNonceManager(t)
+	tracker := newTestTracker(t, backend, 1)
+	ctx := context.Background()
+
+	sent, err := manager.Submit(ctx, transfer(firmante))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	tracker.Track(sent)
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if estado, err := tracker.Esperar(timeout, sent.Tx.Hash()); !errors.Is(err, context.DeadlineExceeded) || estado.Estado != models.EstadoPendiente {
+		t.Errorf("Expected timeout with pending status, got %v and %+v", err, estado)
+	}
+
+	result := make(chan *models.EstadoTransaccion, 1)
+	go func() {
+		estado, _ := tracker.Esperar(ctx, sent.Tx.Hash())
+		result <- estado
+	}()
+	backend.Commit()
+	tracker.poll(ctx)
+
+	select {
+	case estado := <-result:
+		if estado.Estado != models.EstadoConfirmada {
+			t.Errorf("Expected confirmed, got %+v", estado)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected Esperar to return after the transaction was confirmed")
+	}
+
+	if _, err := tracker.Esperar(ctx, common.HexToHash("0x01")); !errors.Is(err, ErrTxNotTracked) {
+		t.Errorf("Expected ErrTxNotTracked, got %v", err)
+	}
+}
+
+func TestTxTracker_PersistsStatus(t *testing.T) {
+	manager, backend, firmante := newTestNonceManager(t)
+	store := NewFileTxStore(filepath.Join(t.TempDir(), "data", "tx_status.json"))
+	ctx := context.Background()
+
+	tracker, err := NewTxTracker(backend, TxTrackerOptions{Confirmations: 1, Store: store})
+	if err != nil {
+		t.Fatalf("Failed to create tracker: %v", err)
+	}
+	sent, err := manager.Submit(ctx, transfer(firmante))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	tracker.Track(sent)
+
+	// Un nuevo proceso recupera la transacción pendiente y sigue su recibo
+	restarted, err := NewTxTracker(backend, TxTrackerOptions{Confirmations: 1, Store: store})
+	if err != nil {
+		t.Fatalf("Failed to reload tracker: %v", err)
+	}
+	backend.Commit()
+	restarted.poll(ctx)
+
+	estado := trackerEstado(t, restarted, sent)
+	if estado.Estado != models.EstadoConfirmada || estado.Operacion != sent.Info.Operacion || estado.From != firmante.Address().Hex() {
+		t.Errorf("Expected reloaded transaction to be confirmed, got %+v", estado)
+	}
+
+	saved, err := store.Load()
+	if err != nil || len(saved) != 1 || saved[0].Estado != models.EstadoConfirmada {
+		t.Errorf("Expected confirmed status to be saved, got %+v (%v)", saved, err)
+	}
+}
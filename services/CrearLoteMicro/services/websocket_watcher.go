@@ -0,0 +1,332 @@
+package services
+
+import (
+	"CrearLoteMicro/bindings"
+	"CrearLoteMicro/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// errResuscribir indica que cambiaron los contratos a vigilar
+var errResuscribir = errors.New("contratos a vigilar actualizados")
+
+// WatcherBackend es lo que el vigilante necesita de una conexión WebSocket.
+// Lo implementa ethclient.Client.
+type WatcherBackend interface {
+	SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	Close()
+}
+
+// WebsocketWatcherOptions configura el vigilante WebSocket
+type WebsocketWatcherOptions struct {
+	// URL es el endpoint WebSocket del nodo
+	URL string
+	// Dial abre la conexión; nil usa ethclient con URL
+	Dial func(ctx context.Context) (WatcherBackend, error)
+	// ReconnectDelay es la espera tras el primer fallo; se duplica en cada
+	// intento hasta MaxReconnectDelay y vuelve a empezar al conectar
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+	// Refresco es cada cuánto se comprueba si hay contratos nuevos que
+	// suscribir, por ejemplo los que descubre el indexador al consultarlos
+	Refresco time.Duration
+}
+
+// WebsocketWatcher sigue por WebSocket los eventos de todos los contratos que
+// conoce el indexador de la red. Cada log recibido dispara una pasada del
+// indexador, de modo que el registro de lotes se actualiza sin esperar al
+// sondeo. Si la conexión cae reconecta con espera exponencial, vuelve a
+// suscribirse e indexa los bloques que llegaron mientras estaba caída.
+type WebsocketWatcher struct {
+	red         string
+	service     *BlockchainService
+	options     WebsocketWatcherOptions
+	comprometio common.Hash
+
+	// resuscribir avisa a la conexión actual de un contrato nuevo
+	resuscribir chan struct{}
+
+	// controlMu serializa Iniciar y Detener
+	controlMu sync.Mutex
+	cancel    context.CancelFunc
+	done      chan struct{}
+
+	mu     sync.Mutex
+	estado models.EstadoWebsocket
+}
+
+// NewWebsocketWatcher crea el vigilante de la red sin arrancarlo
+func NewWebsocketWatcher(red string, service *BlockchainService, options WebsocketWatcherOptions) (*WebsocketWatcher, error) {
+	if options.Dial == nil {
+		url := options.URL
+		options.Dial = func(ctx context.Context) (WatcherBackend, error) {
+			return ethclient.DialContext(ctx, url)
+		}
+	}
+	if options.ReconnectDelay <= 0 {
+		options.ReconnectDelay = time.Second
+	}
+	if options.MaxReconnectDelay < options.ReconnectDelay {
+		options.MaxReconnectDelay = time.Minute
+	}
+	if options.Refresco <= 0 {
+		options.Refresco = 30 * time.Second
+	}
+
+	contractABI, err := bindings.LoteTracingMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("error parseando ABI: %v", err)
+	}
+
+	return &WebsocketWatcher{
+		red:         red,
+		service:     service,
+		options:     options,
+		comprometio: contractABI.Events["LoteComprometido"].ID,
+		resuscribir: make(chan struct{}, 1),
+		estado:      models.EstadoWebsocket{Red: red, Contratos: []string{}},
+	}, nil
+}
+
+// Iniciar arranca el vigilante; false si ya estaba en marcha
+func (w *WebsocketWatcher) Iniciar() bool {
+	w.controlMu.Lock()
+	defer w.controlMu.Unlock()
+	if w.cancel != nil {
+		return false
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.done = make(chan struct{})
+	w.actualizar(func(e *models.EstadoWebsocket) { e.Activo = true })
+	go w.run(ctx, w.done)
+	return true
+}
+
+// Detener cierra la conexión y espera a que el vigilante termine; false si
+// no estaba en marcha
+func (w *WebsocketWatcher) Detener() bool {
+	w.controlMu.Lock()
+	defer w.controlMu.Unlock()
+	if w.cancel == nil {
+		return false
+	}
+
+	w.cancel()
+	<-w.done
+	w.cancel = nil
+	return true
+}
+
+// Vigilar pide suscribir un contrato recién desplegado sin esperar al
+// siguiente refresco. El contrato ya debe estar registrado en el indexador.
+func (w *WebsocketWatcher) Vigilar(contractAddress string) {
+	log.Printf("Red %s: vigilando %s", w.red, contractAddress)
+	select {
+	case w.resuscribir <- struct{}{}:
+	default:
+	}
+}
+
+// Estado devuelve una copia del estado del vigilante
+func (w *WebsocketWatcher) Estado() models.EstadoWebsocket {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	estado := w.estado
+	estado.Contratos = append([]string{}, w.estado.Contratos...)
+	return estado
+}
+
+// run mantiene la conexión hasta que se cancele el contexto
+func (w *WebsocketWatcher) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	defer w.actualizar(func(e *models.EstadoWebsocket) {
+		e.Activo = false
+		e.Conectado = false
+		e.ConectadoDesde = nil
+		e.ProximoIntento = nil
+		e.Contratos = []string{}
+	})
+
+	espera := w.options.ReconnectDelay
+	for {
+		conecto, err := w.conectar(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if conecto {
+			espera = w.options.ReconnectDelay
+		}
+
+		proximo := time.Now().Add(espera)
+		w.actualizar(func(e *models.EstadoWebsocket) {
+			e.Conectado = false
+			e.ConectadoDesde = nil
+			e.Contratos = []string{}
+			e.UltimoError = err.Error()
+			e.ProximoIntento = &proximo
+		})
+		log.Printf("Red %s: WebSocket desconectado, reintentando en %s: %v", w.red, espera, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(espera):
+		}
+		espera *= 2
+		if espera > w.options.MaxReconnectDelay {
+			espera = w.options.MaxReconnectDelay
+		}
+		w.actualizar(func(e *models.EstadoWebsocket) { e.Reconexiones++ })
+	}
+}
+
+// conectar abre una conexión, indexa los bloques perdidos y escucha hasta que
+// la conexión falle. Devuelve si llegó a conectarse.
+func (w *WebsocketWatcher) conectar(ctx context.Context) (bool, error) {
+	backend, err := w.options.Dial(ctx)
+	if err != nil {
+		return false, fmt.Errorf("error conectando al WebSocket: %v", err)
+	}
+	defer backend.Close()
+
+	heads := make(chan *types.Header, 16)
+	subHeads, err := backend.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return false, fmt.Errorf("error suscribiendo a bloques nuevos: %v", err)
+	}
+	defer subHeads.Unsubscribe()
+
+	head, err := backend.BlockNumber(ctx)
+	if err != nil {
+		return false, fmt.Errorf("error obteniendo bloque actual: %v", err)
+	}
+
+	// Los eventos de los bloques que llegaron sin conexión se indexan desde
+	// el siguiente bloque pendiente de cada contrato
+	ahora := time.Now()
+	w.actualizar(func(e *models.EstadoWebsocket) {
+		if e.UltimoBloque > 0 && head > e.UltimoBloque {
+			e.BloquesRecuperados += head - e.UltimoBloque
+		}
+		e.UltimoBloque = head
+		e.Conectado = true
+		e.ConectadoDesde = &ahora
+		e.UltimoError = ""
+		e.ProximoIntento = nil
+	})
+	if err := w.service.indexer.Sincronizar(ctx); err != nil {
+		return true, fmt.Errorf("error indexando bloques perdidos: %v", err)
+	}
+	log.Printf("Red %s: WebSocket conectado en el bloque %d", w.red, head)
+
+	for {
+		err := w.escuchar(ctx, backend, subHeads, heads)
+		if !errors.Is(err, errResuscribir) {
+			return true, err
+		}
+	}
+}
+
+// escuchar suscribe los logs de los contratos conocidos y los procesa hasta
+// que falle una suscripción o cambien los contratos
+func (w *WebsocketWatcher) escuchar(ctx context.Context, backend WatcherBackend, subHeads ethereum.Subscription, heads <-chan *types.Header) error {
+	direcciones := w.service.indexer.Direcciones()
+
+	// Sin contratos no hay suscripción de logs y los canales nil no reciben
+	var logs chan types.Log
+	var errLogs <-chan error
+	if len(direcciones) > 0 {
+		logs = make(chan types.Log, 64)
+		subLogs, err := backend.SubscribeFilterLogs(ctx, ethereum.FilterQuery{Addresses: direcciones}, logs)
+		if err != nil {
+			return fmt.Errorf("error suscribiendo a los logs: %v", err)
+		}
+		defer subLogs.Unsubscribe()
+		errLogs = subLogs.Err()
+	}
+
+	contratos := make([]string, len(direcciones))
+	for i, addr := range direcciones {
+		contratos[i] = addr.Hex()
+	}
+	w.actualizar(func(e *models.EstadoWebsocket) { e.Contratos = contratos })
+
+	refresco := time.NewTicker(w.options.Refresco)
+	defer refresco.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-subHeads.Err():
+			return fmt.Errorf("suscripción a bloques nuevos cerrada: %v", err)
+		case err := <-errLogs:
+			return fmt.Errorf("suscripción a logs cerrada: %v", err)
+		case header := <-heads:
+			w.actualizar(func(e *models.EstadoWebsocket) {
+				if n := header.Number.Uint64(); n > e.UltimoBloque {
+					e.UltimoBloque = n
+				}
+			})
+		case vLog := <-logs:
+			// Los logs de la misma ráfaga se indexan en una sola pasada
+			recibidos := []types.Log{vLog}
+			for pendiente := true; pendiente; {
+				select {
+				case vLog := <-logs:
+					recibidos = append(recibidos, vLog)
+				default:
+					pendiente = false
+				}
+			}
+			w.procesar(ctx, recibidos)
+		case <-w.resuscribir:
+			return errResuscribir
+		case <-refresco.C:
+			// El indexador no olvida contratos: basta con comparar cuántos hay
+			if len(w.service.indexer.Direcciones()) != len(direcciones) {
+				return errResuscribir
+			}
+		}
+	}
+}
+
+// procesar indexa los logs recibidos y avisa de los lotes comprometidos
+func (w *WebsocketWatcher) procesar(ctx context.Context, logs []types.Log) {
+	w.actualizar(func(e *models.EstadoWebsocket) {
+		e.EventosRecibidos += uint64(len(logs))
+		for _, vLog := range logs {
+			if vLog.BlockNumber > e.UltimoBloque {
+				e.UltimoBloque = vLog.BlockNumber
+			}
+		}
+	})
+
+	if err := w.service.indexer.Sincronizar(ctx); err != nil && ctx.Err() == nil {
+		log.Printf("Red %s: error indexando eventos recibidos: %v", w.red, err)
+	}
+	for _, vLog := range logs {
+		if !vLog.Removed && len(vLog.Topics) > 0 && vLog.Topics[0] == w.comprometio {
+			log.Printf("❌ Lote comprometido: red:%s, contractAddress:%s, txHash:%s", w.red, vLog.Address.Hex(), vLog.TxHash.Hex())
+		}
+	}
+}
+
+func (w *WebsocketWatcher) actualizar(cambio func(e *models.EstadoWebsocket)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	cambio(&w.estado)
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"CrearLoteMicro/models"
+	"CrearLoteMicro/signer"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// websocketPrueba sirve conexiones sobre la cadena simulada y permite
+// cortarlas y rechazar las siguientes, como un proveedor caído
+type websocketPrueba struct {
+	chain *SimulatedChain
+
+	mu         sync.Mutex
+	caido      bool
+	conexiones int
+	subs       []*suscripcionPrueba
+}
+
+func (w *websocketPrueba) dial(ctx context.Context) (WatcherBackend, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.caido {
+		return nil, errors.New("connection refused")
+	}
+	w.conexiones++
+	return &conexionPrueba{w}, nil
+}
+
+// cortar cierra las suscripciones abiertas con error y rechaza las conexiones
+func (w *websocketPrueba) cortar() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.caido = true
+	for _, sub := range w.subs {
+		sub.err <- errors.New("websocket: close 1006 (abnormal closure)")
+	}
+	w.subs = nil
+}
+
+func (w *websocketPrueba) restablecer() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.caido = false
+}
+
+func (w *websocketPrueba) registrar(sub ethereum.Subscription, err error) (ethereum.Subscription, error) {
+	if err != nil {
+		return nil, err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	prueba := &suscripcionPrueba{Subscription: sub, err: make(chan error, 1)}
+	w.subs = append(w.subs, prueba)
+	return prueba, nil
+}
+
+type conexionPrueba struct {
+	ws *websocketPrueba
+}
+
+func (c *conexionPrueba) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return c.ws.registrar(c.ws.chain.SubscribeFilterLogs(ctx, query, ch))
+}
+
+func (c *conexionPrueba) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return c.ws.registrar(c.ws.chain.SubscribeNewHead(ctx, ch))
+}
+
+func (c *conexionPrueba) BlockNumber(ctx context.Context) (uint64, error) {
+	return c.ws.chain.BlockNumber(ctx)
+}
+
+func (c *conexionPrueba) Close() {}
+
+// suscripcionPrueba es una suscripción real cuyo error controla el test
+type suscripcionPrueba struct {
+	ethereum.Subscription
+	err chan error
+}
+
+func (s *suscripcionPrueba) Err() <-chan error { return s.err }
+
+// newTestWatcher crea un servicio cuyo indexador no sondea, de modo que solo
+// el vigilante indexa los eventos nuevos, y despliega los lotes indicados
+func newTestWatcher(t *testing.T, loteIDs ...string) (*BlockchainService, *websocketPrueba, *WebsocketWatcher, signer.Signer, []string) {
+	t.Helper()
+	fabricante, _ := signer.NewDevSigner("fabricante")
+	chain := NewSimulatedChain(SimulatedChainOptions{Accounts: []common.Address{fabricante.Address()}})
+	t.Cleanup(func() { chain.Close() })
+	bs, err := NewBlockchainServiceWithClient(chain, 1337, NonceManagerOptions{}, TxTrackerOptions{}, EventIndexerOptions{PollInterval: time.Hour}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	var contratos []string
+	for _, loteID := range loteIDs {
+		contractAddress := desplegarLoteOraculo(t, bs, fabricante, loteID)
+		contratos = append(contratos, contractAddress)
+	}
+
+	ws := &websocketPrueba{chain: chain}
+	watcher, err := NewWebsocketWatcher("local", bs, WebsocketWatcherOptions{
+		Dial:              ws.dial,
+		ReconnectDelay:    10 * time.Millisecond,
+		MaxReconnectDelay: 40 * time.Millisecond,
+		Refresco:          time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create watcher: %v", err)
+	}
+	t.Cleanup(func() { watcher.Detener() })
+	return bs, ws, watcher, fabricante, contratos
+}
+
+// desplegarLoteOraculo despliega un lote con rango 2..8 en el que el
+// fabricante puede registrar temperaturas
+func desplegarLoteOraculo(t *testing.T, bs *BlockchainService, fabricante signer.Signer, loteID string) string {
+	t.Helper()
+	contractAddress, _, err := bs.DeployContract(fabricante, loteID, 2, 8)
+	if err != nil {
+		t.Fatalf("Failed to deploy contract: %v", err)
+	}
+	if _, err := bs.OtorgarRol(fabricante, contractAddress, RolOraculo, fabricante.Address().Hex()); err != nil {
+		t.Fatalf("Expected oracle role to be granted, got %v", err)
+	}
+	return contractAddress
+}
+
+// esperarWatcher espera hasta que el estado del vigilante cumpla la condición
+func esperarWatcher(t *testing.T, watcher *WebsocketWatcher, motivo string, condicion func(estado models.EstadoWebsocket) bool) models.EstadoWebsocket {
+	t.Helper()
+	limite := time.Now().Add(5 * time.Second)
+	for {
+		estado := watcher.Estado()
+		if condicion(estado) {
+			return estado
+		}
+		if time.Now().After(limite) {
+			t.Fatalf("Timed out waiting for %s, watcher state %+v", motivo, estado)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// esperarComprometido espera a que el registro de lotes refleje el compromiso
+func esperarComprometido(t *testing.T, bs *BlockchainService, loteID string) {
+	t.Helper()
+	limite := time.Now().Add(5 * time.Second)
+	for {
+		if lote, err := bs.lotes.PorLoteID(loteID); err == nil && lote.Comprometido {
+			return
+		}
+		if time.Now().After(limite) {
+			t.Fatalf("Timed out waiting for %s to be compromised in the registry", loteID)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWebsocketWatcher_WatchesAllRegistryContracts(t *testing.T) {
+	bs, _, watcher, fabricante, contratos := newTestWatcher(t, "LOTE001", "LOTE002")
+
+	if !watcher.Iniciar() || watcher.Iniciar() {
+		t.Fatal("Expected the watcher to start only once")
+	}
+	esperarWatcher(t, watcher, "both contracts to be subscribed", func(e models.EstadoWebsocket) bool {
+		return e.Conectado && len(e.Contratos) == 2
+	})
+
+	if _, err := bs.RegistrarTemperatura(fabricante, contratos[1], 3, 11, "SENSOR-02"); err != nil {
+		t.Fatalf("Failed to register temperature: %v", err)
+	}
+	esperarComprometido(t, bs, "LOTE002")
+	if lote, _ := bs.lotes.PorLoteID("LOTE001"); lote.Comprometido {
+		t.Error("Expected LOTE001 to stay uncompromised")
+	}
+
+	// Un lote nuevo se suscribe sin esperar al refresco
+	nuevo := desplegarLoteOraculo(t, bs, fabricante, "LOTE003")
+	watcher.Vigilar(nuevo)
+	estado := esperarWatcher(t, watcher, "the new contract to be subscribed", func(e models.EstadoWebsocket) bool {
+		return len(e.Contratos) == 3
+	})
+	if estado.EventosRecibidos == 0 || estado.Reconexiones != 0 {
+		t.Errorf("Unexpected watcher state %+v", estado)
+	}
+	if _, err := bs.RegistrarTemperatura(fabricante, nuevo, 1, 5, "SENSOR-03"); err != nil {
+		t.Fatalf("Failed to register temperature: %v", err)
+	}
+	esperarComprometido(t, bs, "LOTE003")
+}
+
+func TestWebsocketWatcher_ReconnectsAndBackfillsGap(t *testing.T) {
+	bs, ws, watcher, fabricante, contratos := newTestWatcher(t, "LOTE001")
+	watcher.Iniciar()
+	esperarWatcher(t, watcher, "the first connection", func(e models.EstadoWebsocket) bool {
+		return e.Conectado && len(e.Contratos) == 1
+	})
+
+	// Mientras el proveedor está caído el lote se compromete
+	ws.cortar()
+	esperarWatcher(t, watcher, "the disconnection", func(e models.EstadoWebsocket) bool {
+		return !e.Conectado && e.UltimoError != "" && e.Reconexiones >= 2
+	})
+	if _, err := bs.RegistrarTemperatura(fabricante, contratos[0], 3, 11, "SENSOR-01"); err != nil {
+		t.Fatalf("Failed to register temperature: %v", err)
+	}
+	if lote, _ := bs.lotes.PorLoteID("LOTE001"); lote.Comprometido {
+		t.Fatal("Expected no event to be indexed while disconnected")
+	}
+
+	ws.restablecer()
+	estado := esperarWatcher(t, watcher, "the reconnection", func(e models.EstadoWebsocket) bool {
+		return e.Conectado && len(e.Contratos) == 1
+	})
+	if estado.BloquesRecuperados != 1 || estado.UltimoError != "" || estado.ProximoIntento != nil {
+		t.Errorf("Expected one recovered block, got %+v", estado)
+	}
+	esperarComprometido(t, bs, "LOTE001")
+	if ws.conexiones != 2 {
+		t.Errorf("Expected 2 successful connections, got %d", ws.conexiones)
+	}
+}
+
+func TestWebsocketWatcher_StartStopStatus(t *testing.T) {
+	_, ws, watcher, _, _ := newTestWatcher(t, "LOTE001")
+	if estado := watcher.Estado(); estado.Activo || estado.Red != "local" {
+		t.Errorf("Expected an inactive watcher, got %+v", estado)
+	}
+	if watcher.Detener() {
+		t.Error("Expected stopping an inactive watcher to report false")
+	}
+
+	watcher.Iniciar()
+	esperarWatcher(t, watcher, "the connection", func(e models.EstadoWebsocket) bool { return e.Conectado })
+	if !watcher.Detener() {
+		t.Fatal("Expected the watcher to stop")
+	}
+	if estado := watcher.Estado(); estado.Activo || estado.Conectado || len(estado.Contratos) != 0 {
+		t.Errorf("Expected a stopped watcher, got %+v", estado)
+	}
+
+	// Tras detenerse se puede volver a iniciar con una conexión nueva
+	if !watcher.Iniciar() {
+		t.Fatal("Expected the watcher to restart")
+	}
+	esperarWatcher(t, watcher, "the new connection", func(e models.EstadoWebsocket) bool { return e.Activo && e.Conectado })
+	if ws.conexiones != 2 {
+		t.Errorf("Expected 2 connections, got %d", ws.conexiones)
+	}
+}
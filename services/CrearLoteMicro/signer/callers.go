@@ -0,0 +1,80 @@
+package signer
+
+import (
+	"CrearLoteMicro/config"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnauthenticated se devuelve cuando una solicitud que firma no trae una API key válida
+	ErrUnauthenticated = errors.New("se requiere una API key válida (Authorization: Bearer <clave> o X-API-Key)")
+	// ErrAccountForbidden se devuelve cuando el cliente no tiene permitida la cuenta
+	ErrAccountForbidden = errors.New("el cliente no tiene permitida la cuenta")
+)
+
+// todasLasCuentas en la lista de un cliente le permite usar cualquier cuenta
+const todasLasCuentas = "*"
+
+// Caller es un cliente de la API autenticado y las cuentas que puede usar
+type Caller struct {
+	Name     string
+	accounts map[string]bool
+}
+
+// CanUse indica si el cliente puede firmar con la cuenta con nombre
+func (c *Caller) CanUse(account string) bool {
+	return c.accounts[todasLasCuentas] || c.accounts[account]
+}
+
+// Callers resuelve las API keys de las solicitudes a clientes. Las claves se
+// guardan por su hash para no compararlas byte a byte.
+type Callers struct {
+	byKey map[[sha256.Size]byte]*Caller
+}
+
+// LoadCallers crea los clientes de la configuración
+func LoadCallers(cfg config.AuthConfig) (*Callers, error) {
+	callers := &Callers{byKey: make(map[[sha256.Size]byte]*Caller)}
+
+	for _, client := range cfg.Clients {
+		key, err := secretValue(client.Key, client.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cliente %s: %v", client.Name, err)
+		}
+		if key == "" {
+			return nil, fmt.Errorf("cliente %s: falta la API key", client.Name)
+		}
+		if len(client.Accounts) == 0 {
+			return nil, fmt.Errorf("cliente %s: no tiene cuentas permitidas", client.Name)
+		}
+
+		hash := sha256.Sum256([]byte(key))
+		if existing, exists := callers.byKey[hash]; exists {
+			return nil, fmt.Errorf("cliente %s: usa la misma API key que %s", client.Name, existing.Name)
+		}
+
+		caller := &Caller{Name: client.Name, accounts: make(map[string]bool)}
+		for _, account := range client.Accounts {
+			caller.accounts[account] = true
+		}
+		callers.byKey[hash] = caller
+	}
+
+	return callers, nil
+}
+
+// Authenticate devuelve el cliente de la API key, o false si no existe
+func (c *Callers) Authenticate(key string) (*Caller, bool) {
+	if key == "" {
+		return nil, false
+	}
+	caller, ok := c.byKey[sha256.Sum256([]byte(key))]
+	return caller, ok
+}
+
+// Len es el número de clientes configurados
+func (c *Callers) Len() int {
+	return len(c.byKey)
+}
@@ -0,0 +1,58 @@
+package signer
+
+import (
+	"CrearLoteMicro/config"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadCallers_AuthenticatesAndChecksAccounts(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "api-key")
+	if err := os.WriteFile(keyFile, []byte("clave-erp\n"), 0600); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+
+	callers, err := LoadCallers(config.AuthConfig{Clients: []config.APIClient{
+		{Name: "erp", KeyFile: keyFile, Accounts: []string{"fabricante", "distribuidor"}},
+		{Name: "admin", Key: "clave-admin", Accounts: []string{"*"}},
+	}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	erp, ok := callers.Authenticate("clave-erp")
+	if !ok || erp.Name != "erp" {
+		t.Fatalf("Expected the key file to authenticate erp, got %v %v", erp, ok)
+	}
+	if !erp.CanUse("fabricante") || erp.CanUse("oraculo") {
+		t.Error("Expected erp to use only its allowed accounts")
+	}
+
+	admin, ok := callers.Authenticate("clave-admin")
+	if !ok || !admin.CanUse("oraculo") {
+		t.Error("Expected '*' to allow every account")
+	}
+
+	if _, ok := callers.Authenticate("clave-otra"); ok {
+		t.Error("Expected an unknown key to be rejected")
+	}
+	if _, ok := callers.Authenticate(""); ok {
+		t.Error("Expected an empty key to be rejected")
+	}
+}
+
+func TestLoadCallers_InvalidClients(t *testing.T) {
+	cases := map[string][]config.APIClient{
+		"sin clave":       {{Name: "erp", Accounts: []string{"fabricante"}}},
+		"sin cuentas":     {{Name: "erp", Key: "clave"}},
+		"clave repetida":  {{Name: "erp", Key: "clave", Accounts: []string{"*"}}, {Name: "app", Key: "clave", Accounts: []string{"*"}}},
+		"archivo ausente": {{Name: "erp", KeyFile: filepath.Join(t.TempDir(), "no-existe"), Accounts: []string{"*"}}},
+	}
+
+	for name, clients := range cases {
+		if _, err := LoadCallers(config.AuthConfig{Clients: clients}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package signer
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// NewDevSigner crea una cuenta de desarrollo cuya clave se deriva de su
+// nombre, por lo que tiene la misma dirección en cada arranque. Las claves son
+// públicas: solo deben usarse con la blockchain simulada.
+func NewDevSigner(name string) (*KeySigner, error) {
+	privateKey, err := crypto.ToECDSA(crypto.Keccak256([]byte("CrearLoteMicro/dev/" + name)))
+	if err != nil {
+		return nil, fmt.Errorf("error derivando clave de desarrollo %s: %v", name, err)
+	}
+
+	return &KeySigner{
+		key:     privateKey,
+		address: crypto.PubkeyToAddress(privateKey.PublicKey),
+		kind:    KindDev,
+	}, nil
+}
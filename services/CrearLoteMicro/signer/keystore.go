@@ -0,0 +1,60 @@
+package signer
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// KeystoreSigner firma con una cuenta de un keystore cifrado de go-ethereum.
+// La cuenta se desbloquea una sola vez al arrancar y la clave descifrada
+// nunca sale del keystore.
+type KeystoreSigner struct {
+	keystore *keystore.KeyStore
+	account  accounts.Account
+}
+
+// NewKeystoreSigner busca la cuenta en el directorio del keystore y la desbloquea
+func NewKeystoreSigner(ks *keystore.KeyStore, address, password string) (*KeystoreSigner, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("dirección de cuenta inválida: %s", address)
+	}
+
+	account, err := ks.Find(accounts.Account{Address: common.HexToAddress(address)})
+	if err != nil {
+		return nil, fmt.Errorf("cuenta %s no encontrada en el keystore: %v", address, err)
+	}
+
+	if err := ks.Unlock(account, password); err != nil {
+		return nil, fmt.Errorf("error desbloqueando cuenta %s: %v", address, err)
+	}
+
+	return &KeystoreSigner{
+		keystore: ks,
+		account:  account,
+	}, nil
+}
+
+func (s *KeystoreSigner) Address() common.Address {
+	return s.account.Address
+}
+
+func (s *KeystoreSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.keystore.SignTx(s.account, tx, chainID)
+}
+
+func (s *KeystoreSigner) SignText(text []byte) ([]byte, error) {
+	firma, err := s.keystore.SignHash(s.account, accounts.TextHash(text))
+	if err != nil {
+		return nil, fmt.Errorf("error firmando mensaje con el keystore: %v", err)
+	}
+	return normalizarFirma(firma)
+}
+
+func (s *KeystoreSigner) Kind() string {
+	return KindKeystore
+}
@@ -0,0 +1,179 @@
+package signer
+
+import (
+	"CrearLoteMicro/config"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// ErrAccountNotFound se devuelve cuando la cuenta solicitada no está configurada
+	ErrAccountNotFound = errors.New("cuenta no configurada")
+	// ErrRawKeysDisabled se devuelve cuando una solicitud envía privateKey y la configuración no lo permite
+	ErrRawKeysDisabled = errors.New("el envío de claves privadas en la solicitud está deshabilitado; use 'account'")
+	// ErrSignerRequired se devuelve cuando la solicitud no indica cuenta ni clave
+	ErrSignerRequired = errors.New("se requiere 'account'")
+)
+
+// AccountInfo describe una cuenta configurada sin exponer su clave
+type AccountInfo struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Type    string `json:"type"`
+}
+
+// Registry resuelve los nombres de cuenta usados por los clientes a firmantes
+type Registry struct {
+	mu           sync.RWMutex
+	signers      map[string]Signer
+	allowRawKeys bool
+}
+
+// NewRegistry crea un registro vacío
+func NewRegistry(allowRawKeys bool) *Registry {
+	return &Registry{
+		signers:      make(map[string]Signer),
+		allowRawKeys: allowRawKeys,
+	}
+}
+
+// LoadRegistry crea el registro con las cuentas de la configuración
+func LoadRegistry(cfg config.SignerConfig) (*Registry, error) {
+	registry := NewRegistry(cfg.AllowRawPrivateKeys)
+
+	var ks *keystore.KeyStore
+	for _, account := range cfg.Accounts {
+		var s Signer
+		var err error
+
+		switch account.Type {
+		case KindKeystore:
+			if ks == nil {
+				ks = keystore.NewKeyStore(cfg.KeystoreDir, keystore.StandardScryptN, keystore.StandardScryptP)
+			}
+			password, readErr := secretValue(account.Password, account.PasswordFile)
+			if readErr != nil {
+				return nil, fmt.Errorf("cuenta %s: %v", account.Name, readErr)
+			}
+			s, err = NewKeystoreSigner(ks, account.Address, password)
+		case KindEnv:
+			privateKey, readErr := secretValue(account.PrivateKey, account.PrivateKeyFile)
+			if readErr != nil {
+				return nil, fmt.Errorf("cuenta %s: %v", account.Name, readErr)
+			}
+			if privateKey == "" {
+				return nil, fmt.Errorf("cuenta %s: falta la clave privada", account.Name)
+			}
+			s, err = NewKeySigner(privateKey, KindEnv)
+		case KindRemote:
+			if account.RemoteURL == "" || !common.IsHexAddress(account.Address) {
+				return nil, fmt.Errorf("cuenta %s: una cuenta remote requiere URL y dirección", account.Name)
+			}
+			s = NewClefSigner(common.HexToAddress(account.Address), account.RemoteURL)
+		default:
+			return nil, fmt.Errorf("cuenta %s: tipo de firmante desconocido %q", account.Name, account.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cuenta %s: %v", account.Name, err)
+		}
+
+		// Si se indicó la dirección, debe coincidir con la de la clave
+		if account.Address != "" && common.HexToAddress(account.Address) != s.Address() {
+			return nil, fmt.Errorf("cuenta %s: la dirección configurada %s no coincide con la clave (%s)",
+				account.Name, account.Address, s.Address().Hex())
+		}
+
+		if err := registry.Register(account.Name, s); err != nil {
+			return nil, err
+		}
+	}
+
+	return registry, nil
+}
+
+// Register añade una cuenta con nombre
+func (r *Registry) Register(name string, s Signer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.signers[name]; exists {
+		return fmt.Errorf("la cuenta %s ya está registrada", name)
+	}
+	r.signers[name] = s
+	return nil
+}
+
+// Get devuelve el firmante de una cuenta con nombre
+func (r *Registry) Get(name string) (Signer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.signers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, name)
+	}
+	return s, nil
+}
+
+// Resolve obtiene el firmante de una solicitud. La cuenta con nombre tiene
+// prioridad; la clave privada en el cuerpo solo se acepta si está permitida.
+func (r *Registry) Resolve(account, privateKey string) (Signer, error) {
+	if account != "" {
+		return r.Get(account)
+	}
+
+	if privateKey != "" {
+		if !r.allowRawKeys {
+			return nil, ErrRawKeysDisabled
+		}
+		return NewKeySigner(privateKey, KindRawKey)
+	}
+
+	if r.allowRawKeys {
+		return nil, fmt.Errorf("%w o 'privateKey'", ErrSignerRequired)
+	}
+	return nil, ErrSignerRequired
+}
+
+// AllowRawKeys indica si se aceptan claves privadas en las solicitudes
+func (r *Registry) AllowRawKeys() bool {
+	return r.allowRawKeys
+}
+
+// Accounts lista las cuentas configuradas ordenadas por nombre
+func (r *Registry) Accounts() []AccountInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	infos := make([]AccountInfo, 0, len(r.signers))
+	for name, s := range r.signers {
+		infos = append(infos, AccountInfo{
+			Name:    name,
+			Address: s.Address().Hex(),
+			Type:    s.Kind(),
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// secretValue devuelve el valor directo o, si no hay, el contenido del archivo
+// del secreto montado
+func secretValue(value, file string) (string, error) {
+	if value != "" || file == "" {
+		return value, nil
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("error leyendo secreto %s: %v", file, err)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
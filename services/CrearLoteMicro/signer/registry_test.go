@@ -0,0 +1,190 @@
+package signer
+
+import (
+	"CrearLoteMicro/config"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var testChainID = big.NewInt(11155111)
+
+func testTx() *types.Transaction {
+	return types.NewTransaction(0, common.HexToAddress("0x1"), big.NewInt(0), 21000, big.NewInt(1), nil)
+}
+
+func assertSignedBy(t *testing.T, s Signer, want common.Address) {
+	t.Helper()
+	signedTx, err := s.SignTx(testTx(), testChainID)
+	if err != nil {
+		t.Fatalf("Expected no error signing, got %v", err)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(testChainID), signedTx)
+	if err != nil {
+		t.Fatalf("Expected valid signature, got %v", err)
+	}
+	if from != want {
+		t.Errorf("Expected sender %s, got %s", want.Hex(), from.Hex())
+	}
+}
+
+func TestLoadRegistry_KeystoreAndEnvAccounts(t *testing.T) {
+	dir := t.TempDir()
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	keystoreAccount, err := ks.NewAccount("secreto")
+	if err != nil {
+		t.Fatalf("Failed to create keystore account: %v", err)
+	}
+
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(passwordFile, []byte("secreto\n"), 0600); err != nil {
+		t.Fatalf("Failed to write password file: %v", err)
+	}
+
+	envKey, _ := crypto.GenerateKey()
+	envKeyHex := common.Bytes2Hex(crypto.FromECDSA(envKey))
+
+	registry, err := LoadRegistry(config.SignerConfig{
+		KeystoreDir: dir,
+		Accounts: []config.SignerAccount{
+			{Name: "fabricante", Type: KindKeystore, Address: keystoreAccount.Address.Hex(), PasswordFile: passwordFile},
+			{Name: "distribuidor", Type: KindEnv, PrivateKey: "0x" + envKeyHex},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	fabricante, err := registry.Get("fabricante")
+	if err != nil {
+		t.Fatalf("Expected fabricante account, got %v", err)
+	}
+	assertSignedBy(t, fabricante, keystoreAccount.Address)
+
+	distribuidor, err := registry.Resolve("distribuidor", "")
+	if err != nil {
+		t.Fatalf("Expected distribuidor account, got %v", err)
+	}
+	assertSignedBy(t, distribuidor, crypto.PubkeyToAddress(envKey.PublicKey))
+
+	infos := registry.Accounts()
+	if len(infos) != 2 || infos[0].Name != "distribuidor" || infos[1].Type != KindKeystore {
+		t.Errorf("Expected sorted accounts without keys, got %+v", infos)
+	}
+}
+
+func TestLoadRegistry_WrongPassword(t *testing.T) {
+	dir := t.TempDir()
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, _ := ks.NewAccount("secreto")
+
+	_, err := LoadRegistry(config.SignerConfig{
+		KeystoreDir: dir,
+		Accounts: []config.SignerAccount{
+			{Name: "fabricante", Type: KindKeystore, Address: account.Address.Hex(), Password: "otro"},
+		},
+	})
+	if err == nil {
+		t.Error("Expected error unlocking with wrong password")
+	}
+}
+
+func TestLoadRegistry_AddressMismatch(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+
+	_, err := LoadRegistry(config.SignerConfig{
+		Accounts: []config.SignerAccount{
+			{Name: "fabricante", Type: KindEnv, PrivateKey: common.Bytes2Hex(crypto.FromECDSA(key)), Address: "0x000000000000000000000000000000000000dEaD"},
+		},
+	})
+	if err == nil {
+		t.Error("Expected error when configured address does not match the key")
+	}
+}
+
+func TestRegistry_Resolve(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	keyHex := common.Bytes2Hex(crypto.FromECDSA(key))
+
+	allowed := NewRegistry(true)
+	s, err := allowed.Resolve("", keyHex)
+	if err != nil {
+		t.Fatalf("Expected raw key to be accepted, got %v", err)
+	}
+	if s.Kind() != KindRawKey {
+		t.Errorf("Expected kind %s, got %s", KindRawKey, s.Kind())
+	}
+
+	disabled := NewRegistry(false)
+	if _, err := disabled.Resolve("", keyHex); !errors.Is(err, ErrRawKeysDisabled) {
+		t.Errorf("Expected ErrRawKeysDisabled, got %v", err)
+	}
+	if _, err := disabled.Resolve("", ""); !errors.Is(err, ErrSignerRequired) {
+		t.Errorf("Expected ErrSignerRequired, got %v", err)
+	}
+	if _, err := disabled.Resolve("desconocida", ""); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("Expected ErrAccountNotFound, got %v", err)
+	}
+}
+
+// fakeRemote firma con una clave local, como lo haría un firmante externo
+type fakeRemote struct {
+	key *KeySigner
+}
+
+func (f *fakeRemote) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return f.key.SignTx(tx, chainID)
+}
+
+func TestRemoteSigner_VerifiesSigningAccount(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	local, _ := NewKeySigner(common.Bytes2Hex(crypto.FromECDSA(key)), KindEnv)
+
+	remote := NewRemoteSigner(local.Address(), &fakeRemote{key: local})
+	assertSignedBy(t, remote, local.Address())
+
+	other := NewRemoteSigner(common.HexToAddress("0x000000000000000000000000000000000000dEaD"), &fakeRemote{key: local})
+	if _, err := other.SignTx(testTx(), testChainID); err == nil {
+		t.Error("Expected error when the remote signs with a different account")
+	}
+}
+
+func TestSignText_RecoversSigningAccount(t *testing.T) {
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	keystoreAccount, _ := ks.NewAccount("secreto")
+	keystoreSigner, err := NewKeystoreSigner(ks, keystoreAccount.Address.Hex(), "secreto")
+	if err != nil {
+		t.Fatalf("Failed to unlock keystore account: %v", err)
+	}
+	dev, _ := NewDevSigner("certificador")
+
+	mensaje := []byte("certificado")
+	for _, s := range []Signer{dev, keystoreSigner} {
+		firma, err := s.(TextSigner).SignText(mensaje)
+		if err != nil {
+			t.Fatalf("Expected %s signer to sign text, got %v", s.Kind(), err)
+		}
+		if firma[64] != 27 && firma[64] != 28 {
+			t.Errorf("Expected v to be 27 or 28, got %d", firma[64])
+		}
+		firma[64] -= 27
+		pub, err := crypto.SigToPub(accounts.TextHash(mensaje), firma)
+		if err != nil || crypto.PubkeyToAddress(*pub) != s.Address() {
+			t.Errorf("Expected %s signature to recover %s, got %v", s.Kind(), s.Address().Hex(), err)
+		}
+	}
+
+	// fakeRemote solo firma transacciones
+	remote := NewRemoteSigner(dev.Address(), &fakeRemote{key: dev})
+	if _, err := remote.SignText(mensaje); err == nil {
+		t.Error("Expected error when the remote backend cannot sign text")
+	}
+}
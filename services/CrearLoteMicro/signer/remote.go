@@ -0,0 +1,133 @@
+package signer
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// RemoteBackend es un servicio externo que custodia las claves y firma las
+// transacciones (Clef, un HSM o un KMS detrás de un adaptador)
+type RemoteBackend interface {
+	SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// remoteTextBackend es un RemoteBackend que además firma mensajes, como Clef
+// con account_signData
+type remoteTextBackend interface {
+	SignText(account accounts.Account, text []byte) ([]byte, error)
+}
+
+// RemoteSigner delega la firma en un RemoteBackend
+type RemoteSigner struct {
+	address common.Address
+	connect func() (RemoteBackend, error)
+
+	mu      sync.Mutex
+	backend RemoteBackend
+}
+
+// NewRemoteSigner crea un firmante sobre un backend ya conectado
+func NewRemoteSigner(address common.Address, backend RemoteBackend) *RemoteSigner {
+	return &RemoteSigner{
+		address: address,
+		backend: backend,
+	}
+}
+
+// NewClefSigner crea un firmante que usa la API account_signTransaction de un
+// firmante externo compatible con Clef. La conexión se abre en la primera firma
+// para que el servicio pueda arrancar aunque el firmante aún no esté disponible.
+func NewClefSigner(address common.Address, endpoint string) *RemoteSigner {
+	return &RemoteSigner{
+		address: address,
+		connect: func() (RemoteBackend, error) {
+			return external.NewExternalSigner(endpoint)
+		},
+	}
+}
+
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *RemoteSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	backend, err := s.getBackend()
+	if err != nil {
+		return nil, err
+	}
+
+	signedTx, err := backend.SignTx(accounts.Account{Address: s.address}, tx, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("error firmando con el firmante remoto: %v", err)
+	}
+
+	// Verificar que el firmante remoto firmó con la cuenta esperada
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+	if err != nil {
+		return nil, fmt.Errorf("firma remota inválida: %v", err)
+	}
+	if from != s.address {
+		return nil, fmt.Errorf("el firmante remoto firmó con %s en lugar de %s", from.Hex(), s.address.Hex())
+	}
+
+	return signedTx, nil
+}
+
+func (s *RemoteSigner) SignText(text []byte) ([]byte, error) {
+	backend, err := s.getBackend()
+	if err != nil {
+		return nil, err
+	}
+	textBackend, ok := backend.(remoteTextBackend)
+	if !ok {
+		return nil, fmt.Errorf("el firmante remoto no firma mensajes")
+	}
+
+	firma, err := textBackend.SignText(accounts.Account{Address: s.address}, text)
+	if err != nil {
+		return nil, fmt.Errorf("error firmando mensaje con el firmante remoto: %v", err)
+	}
+	if firma, err = normalizarFirma(firma); err != nil {
+		return nil, err
+	}
+
+	// Verificar que el firmante remoto firmó con la cuenta esperada
+	firmaRecuperacion := append([]byte(nil), firma...)
+	firmaRecuperacion[crypto.RecoveryIDOffset] -= 27
+	pub, err := crypto.SigToPub(accounts.TextHash(text), firmaRecuperacion)
+	if err != nil {
+		return nil, fmt.Errorf("firma remota inválida: %v", err)
+	}
+	if from := crypto.PubkeyToAddress(*pub); from != s.address {
+		return nil, fmt.Errorf("el firmante remoto firmó con %s en lugar de %s", from.Hex(), s.address.Hex())
+	}
+	return firma, nil
+}
+
+func (s *RemoteSigner) Kind() string {
+	return KindRemote
+}
+
+// getBackend devuelve el backend, conectándolo si aún no lo está
+func (s *RemoteSigner) getBackend() (RemoteBackend, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.backend != nil {
+		return s.backend, nil
+	}
+
+	backend, err := s.connect()
+	if err != nil {
+		return nil, fmt.Errorf("error conectando al firmante remoto: %v", err)
+	}
+	s.backend = backend
+	return backend, nil
+}
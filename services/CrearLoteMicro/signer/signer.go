@@ -0,0 +1,92 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tipos de firmante soportados
+const (
+	KindKeystore = "keystore"
+	KindEnv      = "env"
+	KindRemote   = "remote"
+	KindRawKey   = "raw"
+	KindDev      = "dev"
+)
+
+// Signer firma transacciones en nombre de una cuenta sin exponer su clave
+type Signer interface {
+	// Address devuelve la dirección de la cuenta que firma
+	Address() common.Address
+	// SignTx firma la transacción para la cadena indicada
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	// Kind indica el origen de la clave (keystore, env, remote, raw o dev)
+	Kind() string
+}
+
+// TextSigner firma mensajes con el prefijo de EIP-191 (personal_sign). La
+// firma tiene 65 bytes con v en 27 o 28, como la devuelven las wallets.
+type TextSigner interface {
+	SignText(text []byte) ([]byte, error)
+}
+
+// normalizarFirma deja v en 27 o 28
+func normalizarFirma(firma []byte) ([]byte, error) {
+	if len(firma) != crypto.SignatureLength {
+		return nil, fmt.Errorf("firma de longitud inválida: %d bytes", len(firma))
+	}
+	if firma[crypto.RecoveryIDOffset] < 27 {
+		firma[crypto.RecoveryIDOffset] += 27
+	}
+	return firma, nil
+}
+
+// KeySigner firma con una clave privada en memoria. Se usa para claves
+// inyectadas por entorno o secretos montados y para la compatibilidad con
+// solicitudes que aún envían la clave en el cuerpo.
+type KeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+	kind    string
+}
+
+// NewKeySigner crea un firmante a partir de una clave privada en hexadecimal
+func NewKeySigner(privateKeyHex, kind string) (*KeySigner, error) {
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(privateKeyHex), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("error parseando clave privada: %v", err)
+	}
+
+	return &KeySigner{
+		key:     privateKey,
+		address: crypto.PubkeyToAddress(privateKey.PublicKey),
+		kind:    kind,
+	}, nil
+}
+
+func (s *KeySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *KeySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+func (s *KeySigner) SignText(text []byte) ([]byte, error) {
+	firma, err := crypto.Sign(accounts.TextHash(text), s.key)
+	if err != nil {
+		return nil, fmt.Errorf("error firmando mensaje: %v", err)
+	}
+	return normalizarFirma(firma)
+}
+
+func (s *KeySigner) Kind() string {
+	return s.kind
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// GuardarJSONAtomico escribe v como JSON indentado en path sin dejar nunca el
+// archivo a medias: escribe un temporal en el mismo directorio, lo sincroniza
+// con el disco y lo renombra sobre path. Crea el directorio si no existe.
+func GuardarJSONAtomico(path string, v interface{}) error {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializando %s: %v", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error creando directorio de %s: %v", path, err)
+	}
+
+	// Un temporal único por escritura evita que dos escrituras se pisen
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creando temporal de %s: %v", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error escribiendo %s: %v", tmp.Name(), err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error sincronizando %s: %v", tmp.Name(), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error cerrando %s: %v", tmp.Name(), err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("error cambiando permisos de %s: %v", tmp.Name(), err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error reemplazando %s: %v", path, err)
+	}
+
+	// Sincronizar el directorio para que el renombrado sobreviva a un corte
+	// de energía; no todos los sistemas lo permiten
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGuardarJSONAtomico(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "lotes.json")
+
+	// Crea el directorio y escribe el JSON
+	if err := GuardarJSONAtomico(path, []string{"LOTE_001"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// Reemplaza el contenido anterior
+	if err := GuardarJSONAtomico(path, []string{"LOTE_001", "LOTE_002"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	var lotes []string
+	if err := json.Unmarshal(content, &lotes); err != nil || len(lotes) != 2 || lotes[1] != "LOTE_002" {
+		t.Errorf("Expected the last value, got %s (%v)", content, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0644 {
+		t.Errorf("Expected mode 0644, got %v (%v)", info.Mode().Perm(), err)
+	}
+
+	// Un valor que no se puede serializar no toca el archivo
+	if err := GuardarJSONAtomico(path, map[string]interface{}{"canal": make(chan int)}); err == nil {
+		t.Error("Expected an error for a value that cannot be encoded")
+	}
+	if after, _ := os.ReadFile(path); string(after) != string(content) {
+		t.Errorf("Expected the file to be unchanged, got %s", after)
+	}
+
+	// No quedan temporales en el directorio
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		names := make([]string, len(entries))
+		for i, entry := range entries {
+			names[i] = entry.Name()
+		}
+		t.Errorf("Expected only lotes.json, got %v", names)
+	}
+}
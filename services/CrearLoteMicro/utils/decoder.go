@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"CrearLoteMicro/bindings"
 	"fmt"
 	"math/big"
 	"strings"
@@ -10,94 +11,6 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
-// ABI del contrato LoteTracing para decodificación - Actualizado con nuevos campos
-const contractABI = `[
-	{
-		"inputs": [
-			{"internalType": "string", "name": "_loteId", "type": "string"},
-			{"internalType": "int8", "name": "_tempMin", "type": "int8"},
-			{"internalType": "int8", "name": "_tempMax", "type": "int8"}
-		],
-		"stateMutability": "nonpayable",
-		"type": "constructor"
-	},
-	{
-		"inputs": [],
-		"name": "comprometido",
-		"outputs": [{"internalType": "bool", "name": "", "type": "bool"}],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [],
-		"name": "fabricante",
-		"outputs": [{"internalType": "address", "name": "", "type": "address"}],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [],
-		"name": "loteId",
-		"outputs": [{"internalType": "string", "name": "", "type": "string"}],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [],
-		"name": "propietarioActual",
-		"outputs": [{"internalType": "address", "name": "", "type": "address"}],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{"internalType": "int8", "name": "_tempMin", "type": "int8"},
-			{"internalType": "int8", "name": "_tempMax", "type": "int8"}
-		],
-		"name": "registrarTemperatura",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	},
-	{
-		"inputs": [],
-		"name": "tempRegMaxima",
-		"outputs": [{"internalType": "int8", "name": "", "type": "int8"}],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [],
-		"name": "tempRegMinima",
-		"outputs": [{"internalType": "int8", "name": "", "type": "int8"}],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [],
-		"name": "temperaturaMaxima",
-		"outputs": [{"internalType": "int8", "name": "", "type": "int8"}],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [],
-		"name": "temperaturaMinima",
-		"outputs": [{"internalType": "int8", "name": "", "type": "int8"}],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{"internalType": "address", "name": "_nuevoPropietario", "type": "address"}
-		],
-		"name": "transferirCustodia",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	}
-]`
-
 // DecodedTransaction representa una transacción decodificada
 type DecodedTransaction struct {
 	FunctionName   string                 `json:"functionName"`
@@ -122,8 +35,8 @@ func DecodeInputData(inputData string) (*DecodedTransaction, error) {
 	functionSelector := inputData[:8]
 	parametersData := inputData[8:]
 
-	// Parsear el ABI
-	parsedABI, err := abi.JSON(strings.NewReader(contractABI))
+	// ABI de los bindings generados
+	parsedABI, err := bindings.LoteTracingMetaData.GetAbi()
 	if err != nil {
 		return nil, fmt.Errorf("error parseando ABI: %v", err)
 	}
@@ -182,15 +95,81 @@ func DecodeInputData(inputData string) (*DecodedTransaction, error) {
 	}, nil
 }
 
+// DecodedEvent representa un log de LoteTracing decodificado
+type DecodedEvent struct {
+	EventName  string                 `json:"eventName"`
+	EventSig   string                 `json:"eventSig"`
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+// DecodeEventLog decodifica un log del contrato a partir de sus topics y su
+// data en hexadecimal. Los parámetros indexados de tipo string o bytes solo
+// están en el log como hash.
+func DecodeEventLog(topics []string, data string) (*DecodedEvent, error) {
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("log sin topics")
+	}
+
+	parsedABI, err := bindings.LoteTracingMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("error parseando ABI: %v", err)
+	}
+
+	event, err := parsedABI.EventByID(common.HexToHash(topics[0]))
+	if err != nil {
+		return nil, fmt.Errorf("evento desconocido %s: %v", topics[0], err)
+	}
+
+	values := make(map[string]interface{})
+	if data != "" && data != "0x" {
+		dataBytes, err := hexutil.Decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("error decodificando data hex: %v", err)
+		}
+		if err := event.Inputs.NonIndexed().UnpackIntoMap(values, dataBytes); err != nil {
+			return nil, fmt.Errorf("error desempaquetando data: %v", err)
+		}
+	}
+
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	topicHashes := make([]common.Hash, 0, len(topics)-1)
+	for _, topic := range topics[1:] {
+		topicHashes = append(topicHashes, common.HexToHash(topic))
+	}
+	if err := abi.ParseTopicsIntoMap(values, indexed, topicHashes); err != nil {
+		return nil, fmt.Errorf("error desempaquetando topics: %v", err)
+	}
+
+	parameters := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		parameters[name] = formatValue(value)
+	}
+
+	return &DecodedEvent{
+		EventName:  event.Name,
+		EventSig:   event.Sig,
+		Parameters: parameters,
+	}, nil
+}
+
 // formatValue formatea un valor para que sea más legible
 func formatValue(value interface{}) interface{} {
 	switch v := value.(type) {
 	case common.Address:
 		return v.Hex()
+	case common.Hash:
+		return v.Hex()
 	case *big.Int:
 		return v.String()
 	case []byte:
 		return hexutil.Encode(v)
+	case [32]byte:
+		return hexutil.Encode(v[:])
 	case int8:
 		return v
 	case uint8:
@@ -213,7 +192,7 @@ func DecodeSpecificInputData() (*DecodedTransaction, error) {
 
 // GetFunctionSignatures retorna todas las signatures de funciones del contrato
 func GetFunctionSignatures() map[string]string {
-	parsedABI, err := abi.JSON(strings.NewReader(contractABI))
+	parsedABI, err := bindings.LoteTracingMetaData.GetAbi()
 	if err != nil {
 		return nil
 	}
@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// fixtureLog es un log de testdata/lote_tracing.json, con el formato de
+// eth_getLogs y eth_subscribe("logs")
+type fixtureLog struct {
+	Topics []string `json:"topics"`
+	Data   string   `json:"data"`
+}
+
+// loadFixtureLogs lee los logs que emitió LoteTracing en la cadena simulada:
+// despliegue de LOTE001 (2..8), roles, dos lecturas (la segunda fuera de
+// rango), un anclaje y propuestas de custodia canceladas, rechazadas y
+// aceptadas
+func loadFixtureLogs(t *testing.T) []fixtureLog {
+	t.Helper()
+	content, err := os.ReadFile("testdata/lote_tracing.json")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	var fixture struct {
+		Logs []fixtureLog `json:"logs"`
+	}
+	if err := json.Unmarshal(content, &fixture); err != nil {
+		t.Fatalf("Failed to parse fixture: %v", err)
+	}
+	return fixture.Logs
+}
+
+func TestDecodeEventLog(t *testing.T) {
+	logs := loadFixtureLogs(t)
+	const (
+		fabricante   = "0x57C863d904EAdFb6F56eb7676738CD13c62B4fC0"
+		distribuidor = "0x93F043862503ACd5FD3C00a8Ad12fDD60Df7AA4c"
+	)
+
+	tests := []struct {
+		log    int
+		event  string
+		params map[string]interface{}
+	}{
+		{0, "AdminTransferido", map[string]interface{}{
+			"adminAnterior": "0x0000000000000000000000000000000000000000",
+			"nuevoAdmin":    fabricante,
+		}},
+		{1, "RolOtorgado", map[string]interface{}{
+			"rol":    crypto.Keccak256Hash([]byte("FABRICANTE")).Hex(),
+			"cuenta": fabricante,
+			"admin":  fabricante,
+		}},
+		// El string indexado solo llega como su hash
+		{2, "LoteCreado", map[string]interface{}{
+			"loteId":            crypto.Keccak256Hash([]byte("LOTE001")).Hex(),
+			"fabricante":        fabricante,
+			"temperaturaMinima": int8(2),
+			"temperaturaMaxima": int8(8),
+			"motivo":            "Lote Creado",
+		}},
+		{6, "TemperaturaRegistrada", map[string]interface{}{
+			"oraculo":   fabricante,
+			"sensorId":  "SENSOR-01",
+			"tempMin":   int8(3),
+			"tempMax":   int8(7),
+			"enRango":   true,
+			"timestamp": "50",
+		}},
+		{7, "LecturasAncladas", map[string]interface{}{
+			"oraculo":       fabricante,
+			"raizMerkle":    crypto.Keccak256Hash([]byte("lecturas")).Hex(),
+			"tempMin":       int8(3),
+			"tempMax":       int8(6),
+			"totalLecturas": "12",
+			"desde":         "1700000000",
+			"hasta":         "1700003600",
+			"enRango":       true,
+		}},
+		{8, "CustodiaPropuesta", map[string]interface{}{
+			"propuesta":     "1",
+			"propietario":   fabricante,
+			"destinatario":  distribuidor,
+			"expira":        "3670",
+			"envio":         "0x228d03352152fbd12ad09f103358d2570110d3d78362b4e3cd3f6a4fe5f37a0f",
+			"detallesEnvio": `{"guia":"GUIA-001","transportista":"FrioExpress","bultos":3}`,
+		}},
+		{9, "CustodiaCancelada", map[string]interface{}{
+			"propuesta":   "1",
+			"propietario": fabricante,
+		}},
+		{11, "CustodiaRechazada", map[string]interface{}{
+			"propuesta":    "2",
+			"destinatario": distribuidor,
+			"motivo":       "Temperatura de llegada fuera de rango",
+		}},
+		{12, "RolRevocado", map[string]interface{}{
+			"rol":    crypto.Keccak256Hash([]byte("FARMACIA")).Hex(),
+			"cuenta": "0x1fDb9Eb995701E8fB2af1A974cB2e4704aB19628",
+			"admin":  fabricante,
+		}},
+		{13, "TemperaturaRegistrada", map[string]interface{}{
+			"oraculo":   fabricante,
+			"sensorId":  "SENSOR-02",
+			"tempMin":   int8(1),
+			"tempMax":   int8(12),
+			"enRango":   false,
+			"timestamp": "120",
+		}},
+		{14, "LoteComprometido", map[string]interface{}{
+			"propietario":  fabricante,
+			"tempMin":      int8(1),
+			"tempMax":      int8(12),
+			"comprometido": true,
+			"motivo":       "Temperatura fuera de rango",
+		}},
+		{16, "CustodiaAceptada", map[string]interface{}{
+			"propuesta":    "3",
+			"destinatario": distribuidor,
+			"firmada":      false,
+		}},
+		{17, "CustodiaTransferida", map[string]interface{}{
+			"propietarioAnterior": fabricante,
+			"nuevoPropietario":    distribuidor,
+			"comprometido":        true,
+			"motivo":              "Custodia Aceptada",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.event, func(t *testing.T) {
+			event, err := DecodeEventLog(logs[tt.log].Topics, logs[tt.log].Data)
+			if err != nil {
+				t.Fatalf("Expected log %d to decode, got %v", tt.log, err)
+			}
+			if event.EventName != tt.event {
+				t.Errorf("Expected %s, got %s", tt.event, event.EventName)
+			}
+			if !reflect.DeepEqual(event.Parameters, tt.params) {
+				t.Errorf("Unexpected parameters\n got: %#v\nwant: %#v", event.Parameters, tt.params)
+			}
+		})
+	}
+}
+
+func TestDecodeEventLog_Errors(t *testing.T) {
+	logs := loadFixtureLogs(t)
+	creado, temperatura := logs[2], logs[6]
+
+	tests := []struct {
+		name   string
+		topics []string
+		data   string
+		err    string
+	}{
+		{"no topics", nil, "0x", "log sin topics"},
+		{"unknown topic0", []string{crypto.Keccak256Hash([]byte("Desconocido()")).Hex()}, "0x", "evento desconocido"},
+		{"invalid hex data", temperatura.Topics, "0xzz", "error decodificando data hex"},
+		{"truncated data", temperatura.Topics, temperatura.Data[:len(temperatura.Data)-64], "error desempaquetando data"},
+		{"missing indexed topic", creado.Topics[:2], creado.Data, "error desempaquetando topics"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := DecodeEventLog(tt.topics, tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Expected error %q, got %v (%+v)", tt.err, err, event)
+			}
+		})
+	}
+}
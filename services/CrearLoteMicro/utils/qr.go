@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// QRPNG genera un código QR en PNG de tamano x tamano píxeles
+func QRPNG(contenido string, tamano int) ([]byte, error) {
+	codigo, err := qrcode.New(contenido, qrcode.Medium)
+	if err != nil {
+		return nil, fmt.Errorf("error generando código QR: %v", err)
+	}
+	return codigo.PNG(tamano)
+}
+
+// QRSVG genera un código QR en SVG con un módulo por unidad del viewBox, así
+// que escala sin perder nitidez; tamano es el ancho y alto en píxeles
+func QRSVG(contenido string, tamano int) ([]byte, error) {
+	codigo, err := qrcode.New(contenido, qrcode.Medium)
+	if err != nil {
+		return nil, fmt.Errorf("error generando código QR: %v", err)
+	}
+	modulos := codigo.Bitmap()
+
+	var svg bytes.Buffer
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		tamano, tamano, len(modulos), len(modulos))
+	svg.WriteString(`<rect width="100%" height="100%" fill="#fff"/><path fill="#000" d="`)
+	for y, fila := range modulos {
+		for x, oscuro := range fila {
+			if oscuro {
+				fmt.Fprintf(&svg, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	svg.WriteString(`"/></svg>`)
+	return svg.Bytes(), nil
+}
@@ -0,0 +1,412 @@
+package coldchain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"mqtt-order-event-client/rules"
+)
+
+// activationEnergyOverR is ΔH/R in Kelvin used by the Mean Kinetic Temperature
+// formula (ΔH = 83.144 kJ/mol, R = 8.3144 J/mol·K), as in USP <1079.2>
+const activationEnergyOverR = 10000.0
+
+const kelvinOffset = 273.15
+
+// Options configures the stream processor
+type Options struct {
+	// Window is the span of recent readings kept per sensor for window metrics
+	Window time.Duration
+	// MaxGap is the longest interval attributed to a reading; longer gaps are
+	// treated as missing data and not counted towards time-out-of-range or MKT
+	MaxGap time.Duration
+	// MaxSamples caps the readings kept per sensor window
+	MaxSamples int
+	// IdleTimeout forgets a sensor that sent no reading for this long, so a
+	// sensor reused on a later shipment starts with fresh metrics; zero keeps
+	// every sensor until Reset
+	IdleTimeout time.Duration
+}
+
+// DefaultOptions returns the processor defaults: 24h window, 30m maximum gap,
+// sensors forgotten after 72h without readings
+func DefaultOptions() Options {
+	return Options{
+		Window:      24 * time.Hour,
+		MaxGap:      30 * time.Minute,
+		MaxSamples:  5000,
+		IdleTimeout: 72 * time.Hour,
+	}
+}
+
+// Metrics are the cold-chain figures computed for a shipment
+type Metrics struct {
+	SensorID              string    `json:"sensorId"`
+	ShipmentID            string    `json:"shipmentId,omitempty"`
+	Profile               string    `json:"profile"`
+	Readings              int       `json:"readings"`
+	FirstReadingAt        time.Time `json:"firstReadingAt"`
+	LastReadingAt         time.Time `json:"lastReadingAt"`
+	MinTemperature        float64   `json:"minTemperature"`
+	MaxTemperature        float64   `json:"maxTemperature"`
+	MKT                   float64   `json:"mkt"`
+	TimeOutOfRangeSeconds float64   `json:"timeOutOfRangeSeconds"`
+	InRange               bool      `json:"inRange"`
+	Window                Window    `json:"window"`
+	Budget                *Budget   `json:"budget,omitempty"`
+}
+
+// Window holds metrics over the most recent readings only
+type Window struct {
+	Start          time.Time `json:"start"`
+	Readings       int       `json:"readings"`
+	MinTemperature float64   `json:"minTemperature"`
+	MaxTemperature float64   `json:"maxTemperature"`
+	MKT            float64   `json:"mkt"`
+}
+
+// Budget reports the excursion allowance and how much of it has been used
+type Budget struct {
+	MinTemperature           *float64 `json:"minTemperature,omitempty"`
+	MaxTemperature           *float64 `json:"maxTemperature,omitempty"`
+	MaxTimeOutOfRangeSeconds float64  `json:"maxTimeOutOfRangeSeconds,omitempty"`
+	MaxMKT                   *float64 `json:"maxMkt,omitempty"`
+	Exceeded                 []string `json:"exceeded,omitempty"`
+}
+
+// Decision tells whether an order damage event must be emitted for a reading
+type Decision struct {
+	Emit        bool
+	Severity    string
+	Description string
+	Metrics     Metrics
+}
+
+type sample struct {
+	at          time.Time
+	temperature float64
+}
+
+// shipmentState accumulates the readings of one sensor
+type shipmentState struct {
+	sensorID   string
+	shipmentID string
+	profile    string
+	budget     *rules.Budget
+
+	readings int
+	first    time.Time
+	last     sample
+	min, max float64
+
+	// time-weighted Arrhenius sum and total weight for the journey MKT
+	arrhenius float64
+	weight    float64
+
+	outOfRange time.Duration
+	window     []sample
+
+	// budget breaches and severity already reported, to emit once per escalation
+	reported         map[string]bool
+	reportedSeverity string
+}
+
+// Processor is a stateful per-sensor stream processor that aggregates readings
+// into excursion metrics and decides when excursion budgets are exceeded
+type Processor struct {
+	mu      sync.RWMutex
+	options Options
+	states  map[string]*shipmentState
+	// lastExpiry is the reading time of the last idle sensor sweep
+	lastExpiry time.Time
+}
+
+// NewProcessor creates a new Processor
+func NewProcessor(options Options) *Processor {
+	return &Processor{
+		options: options,
+		states:  make(map[string]*shipmentState),
+	}
+}
+
+// Process adds a reading, already evaluated by the rules engine, to its
+// shipment state. Without a budget in the profile the rules verdict is used
+// as is; with a budget, damage is only emitted once the budget is exceeded
+// or a critical rule fires.
+func (p *Processor) Process(reading rules.Reading, result rules.Result) Decision {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := reading.SensorID
+	if result.ShipmentID != "" {
+		key = result.ShipmentID + "|" + reading.SensorID
+	}
+
+	p.expire(result.Timestamp)
+
+	state, ok := p.states[key]
+	if ok && p.options.IdleTimeout > 0 && result.Timestamp.Sub(state.last.at) > p.options.IdleTimeout {
+		// A sensor silent for longer than the idle timeout is on a new journey
+		delete(p.states, key)
+		ok = false
+	}
+	if !ok {
+		// A sensor monitors one shipment at a time: the state of its previous
+		// shipment is dropped when it is reassigned
+		p.reset(reading.SensorID)
+		state = &shipmentState{
+			sensorID:   reading.SensorID,
+			shipmentID: result.ShipmentID,
+			reported:   make(map[string]bool),
+		}
+		p.states[key] = state
+	}
+	state.profile = result.Profile
+	state.budget = result.Budget
+
+	p.add(state, sample{at: result.Timestamp, temperature: reading.Temperature})
+	metrics := p.metrics(state)
+
+	if state.budget == nil {
+		return Decision{
+			Emit:        result.Damaged,
+			Severity:    result.Severity,
+			Description: result.Description,
+			Metrics:     metrics,
+		}
+	}
+
+	var newBreaches []string
+	for _, breach := range metrics.Budget.Exceeded {
+		if !state.reported[breach] {
+			newBreaches = append(newBreaches, breach)
+		}
+	}
+
+	severity := ""
+	if len(metrics.Budget.Exceeded) > 0 {
+		severity = state.budget.Severity
+	}
+	if result.Damaged && (result.Severity == rules.SeverityCritical || severity != "") && rules.MoreSevere(result.Severity, severity) {
+		severity = result.Severity
+	}
+
+	// Emit on a new breach or when the severity escalates
+	if severity == "" || (len(newBreaches) == 0 && !rules.MoreSevere(severity, state.reportedSeverity)) {
+		return Decision{Metrics: metrics}
+	}
+
+	for _, breach := range metrics.Budget.Exceeded {
+		state.reported[breach] = true
+	}
+	state.reportedSeverity = severity
+
+	description := fmt.Sprintf("Excursion budget exceeded: mkt=%.2fC, timeOutOfRange=%s, min=%.2fC, max=%.2fC",
+		metrics.MKT, time.Duration(metrics.TimeOutOfRangeSeconds*float64(time.Second)).Round(time.Second),
+		metrics.MinTemperature, metrics.MaxTemperature)
+	if len(metrics.Budget.Exceeded) > 0 {
+		description += "; budgets: " + strings.Join(metrics.Budget.Exceeded, ", ")
+	}
+	if result.Damaged {
+		description += "; " + result.Description
+	}
+
+	return Decision{
+		Emit:        true,
+		Severity:    severity,
+		Description: description,
+		Metrics:     metrics,
+	}
+}
+
+// Metrics returns the current metrics of every tracked shipment, ordered by sensor
+func (p *Processor) Metrics() []Metrics {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	metrics := make([]Metrics, 0, len(p.states))
+	for _, state := range p.states {
+		metrics = append(metrics, p.metrics(state))
+	}
+	sort.Slice(metrics, func(i, j int) bool {
+		if metrics[i].SensorID == metrics[j].SensorID {
+			return metrics[i].ShipmentID < metrics[j].ShipmentID
+		}
+		return metrics[i].SensorID < metrics[j].SensorID
+	})
+	return metrics
+}
+
+// Reset forgets the state of a sensor, e.g. when its shipment is delivered.
+// It reports whether the sensor was being tracked.
+func (p *Processor) Reset(sensorID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.reset(sensorID)
+}
+
+func (p *Processor) reset(sensorID string) bool {
+	found := false
+	for key, state := range p.states {
+		if state.sensorID == sensorID {
+			delete(p.states, key)
+			found = true
+		}
+	}
+	return found
+}
+
+// expire drops the sensors idle for longer than IdleTimeout. The sweep runs at
+// most once per minute of reading time.
+func (p *Processor) expire(now time.Time) {
+	if p.options.IdleTimeout <= 0 || now.Sub(p.lastExpiry) < time.Minute {
+		return
+	}
+	p.lastExpiry = now
+
+	for key, state := range p.states {
+		if now.Sub(state.last.at) > p.options.IdleTimeout {
+			delete(p.states, key)
+		}
+	}
+}
+
+// add folds a sample into the shipment state. The interval since the previous
+// reading is attributed to the previous temperature (sample-and-hold).
+func (p *Processor) add(state *shipmentState, s sample) {
+	if state.readings == 0 {
+		state.first = s.at
+		state.min, state.max = s.temperature, s.temperature
+	} else {
+		if s.at.Before(state.last.at) {
+			// Out-of-order readings only update extremes
+			state.min = math.Min(state.min, s.temperature)
+			state.max = math.Max(state.max, s.temperature)
+			state.readings++
+			return
+		}
+
+		interval := s.at.Sub(state.last.at)
+		if interval > 0 && interval <= p.options.MaxGap {
+			state.arrhenius += interval.Seconds() * arrhenius(state.last.temperature)
+			state.weight += interval.Seconds()
+			if state.budget != nil && outOfRange(state.budget, state.last.temperature) {
+				state.outOfRange += interval
+			}
+		}
+		state.min = math.Min(state.min, s.temperature)
+		state.max = math.Max(state.max, s.temperature)
+	}
+
+	state.readings++
+	state.last = s
+
+	state.window = append(state.window, s)
+	cutoff := s.at.Add(-p.options.Window)
+	drop := 0
+	for drop < len(state.window) && state.window[drop].at.Before(cutoff) {
+		drop++
+	}
+	if excess := len(state.window) - drop - p.options.MaxSamples; p.options.MaxSamples > 0 && excess > 0 {
+		drop += excess
+	}
+	state.window = state.window[drop:]
+}
+
+// metrics computes the metrics snapshot of a shipment
+func (p *Processor) metrics(state *shipmentState) Metrics {
+	m := Metrics{
+		SensorID:              state.sensorID,
+		ShipmentID:            state.shipmentID,
+		Profile:               state.profile,
+		Readings:              state.readings,
+		FirstReadingAt:        state.first,
+		LastReadingAt:         state.last.at,
+		MinTemperature:        state.min,
+		MaxTemperature:        state.max,
+		MKT:                   mkt(state.arrhenius, state.weight, state.last.temperature),
+		TimeOutOfRangeSeconds: state.outOfRange.Seconds(),
+		InRange:               true,
+		Window:                windowMetrics(state.window),
+	}
+
+	if b := state.budget; b != nil {
+		m.InRange = !outOfRange(b, state.last.temperature)
+		m.Budget = &Budget{
+			MinTemperature:           b.MinTemperature,
+			MaxTemperature:           b.MaxTemperature,
+			MaxTimeOutOfRangeSeconds: time.Duration(b.MaxTimeOutOfRange).Seconds(),
+			MaxMKT:                   b.MaxMKT,
+		}
+		if b.MaxTimeOutOfRange > 0 && state.outOfRange > time.Duration(b.MaxTimeOutOfRange) {
+			m.Budget.Exceeded = append(m.Budget.Exceeded, "timeOutOfRange")
+		}
+		if b.MaxMKT != nil && state.weight > 0 && m.MKT > *b.MaxMKT {
+			m.Budget.Exceeded = append(m.Budget.Exceeded, "mkt")
+		}
+	}
+	return m
+}
+
+// windowMetrics computes min/max and MKT over the window samples
+func windowMetrics(window []sample) Window {
+	if len(window) == 0 {
+		return Window{}
+	}
+
+	w := Window{
+		Start:          window[0].at,
+		Readings:       len(window),
+		MinTemperature: window[0].temperature,
+		MaxTemperature: window[0].temperature,
+	}
+
+	var sum, weight float64
+	for i, s := range window {
+		w.MinTemperature = math.Min(w.MinTemperature, s.temperature)
+		w.MaxTemperature = math.Max(w.MaxTemperature, s.temperature)
+		if i > 0 {
+			interval := s.at.Sub(window[i-1].at).Seconds()
+			sum += interval * arrhenius(window[i-1].temperature)
+			weight += interval
+		}
+	}
+	w.MKT = mkt(sum, weight, window[len(window)-1].temperature)
+	return w
+}
+
+// MKT computes the Mean Kinetic Temperature in °C of equally weighted readings
+func MKT(temperatures []float64) float64 {
+	if len(temperatures) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, t := range temperatures {
+		sum += arrhenius(t)
+	}
+	return mkt(sum, float64(len(temperatures)), temperatures[len(temperatures)-1])
+}
+
+// mkt turns a weighted Arrhenius sum into a temperature, falling back to the
+// last reading when there is no weighted data yet
+func mkt(sum, weight, fallback float64) float64 {
+	if weight == 0 {
+		return fallback
+	}
+	return activationEnergyOverR/-math.Log(sum/weight) - kelvinOffset
+}
+
+func arrhenius(celsius float64) float64 {
+	return math.Exp(-activationEnergyOverR / (celsius + kelvinOffset))
+}
+
+func outOfRange(b *rules.Budget, temperature float64) bool {
+	if b.MinTemperature != nil && temperature < *b.MinTemperature {
+		return true
+	}
+	return b.MaxTemperature != nil && temperature > *b.MaxTemperature
+}
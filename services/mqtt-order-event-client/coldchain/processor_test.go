@@ -0,0 +1,210 @@
+package coldchain
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"mqtt-order-event-client/rules"
+)
+
+const budgetRules = `
+version: "test-1"
+sensors:
+  sensor-1:
+    shipment: shipment-1
+    category: vaccines
+default:
+  rules: []
+categories:
+  vaccines:
+    budget:
+      minTemperature: 2
+      maxTemperature: 8
+      maxTimeOutOfRange: 30m
+      severity: major
+    rules:
+      - name: above-8c
+        metric: temperature
+        above: 8
+        severity: minor
+      - name: above-20c
+        metric: temperature
+        above: 20
+        severity: critical
+`
+
+// process evaluates a reading against the test rules (none are duration based)
+// and feeds it to the processor
+func process(p *Processor, rs *rules.RuleSet, at time.Time, temperature float64) Decision {
+	reading := rules.Reading{SensorID: "sensor-1", Temperature: temperature, Timestamp: at}
+	result := (&rules.Engine{}).DryRun(rs, []rules.Reading{reading})[0]
+	return p.Process(reading, result)
+}
+
+func TestMKT(t *testing.T) {
+	if got := MKT([]float64{5, 5, 5}); math.Abs(got-5) > 1e-9 {
+		t.Errorf("Expected MKT of constant 5°C to be 5, got %f", got)
+	}
+
+	// MKT weights high temperatures more than the arithmetic mean
+	got := MKT([]float64{2, 8, 14})
+	if got <= 8 || got >= 14 {
+		t.Errorf("Expected MKT of 2/8/14°C above the mean 8°C, got %f", got)
+	}
+}
+
+func TestSingleNoisySampleDoesNotEmit(t *testing.T) {
+	rs, _ := rules.Parse([]byte(budgetRules))
+	p := NewProcessor(DefaultOptions())
+	start := time.Date(2025, 10, 1, 10, 0, 0, 0, time.UTC)
+
+	process(p, rs, start, 5)
+	decision := process(p, rs, start.Add(5*time.Minute), 9)
+	if decision.Emit {
+		t.Fatalf("Expected a single excursion sample not to emit")
+	}
+	decision = process(p, rs, start.Add(10*time.Minute), 5)
+	if decision.Emit {
+		t.Fatalf("Expected short excursion not to emit")
+	}
+	if decision.Metrics.TimeOutOfRangeSeconds != 300 {
+		t.Errorf("Expected 300s out of range, got %f", decision.Metrics.TimeOutOfRangeSeconds)
+	}
+	if decision.Metrics.MaxTemperature != 9 || decision.Metrics.MinTemperature != 5 {
+		t.Errorf("Expected min/max 5/9, got %f/%f", decision.Metrics.MinTemperature, decision.Metrics.MaxTemperature)
+	}
+}
+
+func TestLongMildExcursionEmitsOnce(t *testing.T) {
+	rs, _ := rules.Parse([]byte(budgetRules))
+	p := NewProcessor(DefaultOptions())
+	start := time.Date(2025, 10, 1, 10, 0, 0, 0, time.UTC)
+
+	emitted := 0
+	var last Decision
+	for i := 0; i <= 12; i++ {
+		last = process(p, rs, start.Add(time.Duration(i)*5*time.Minute), 8.5)
+		if last.Emit {
+			emitted++
+			if last.Severity != rules.SeverityMajor {
+				t.Errorf("Expected budget severity major, got %s", last.Severity)
+			}
+		}
+	}
+
+	if emitted != 1 {
+		t.Errorf("Expected exactly one emission, got %d", emitted)
+	}
+	if last.Metrics.ShipmentID != "shipment-1" {
+		t.Errorf("Expected shipment-1, got %s", last.Metrics.ShipmentID)
+	}
+	if len(last.Metrics.Budget.Exceeded) != 1 || last.Metrics.Budget.Exceeded[0] != "timeOutOfRange" {
+		t.Errorf("Expected timeOutOfRange budget exceeded, got %v", last.Metrics.Budget.Exceeded)
+	}
+}
+
+func TestCriticalRuleBypassesBudget(t *testing.T) {
+	rs, _ := rules.Parse([]byte(budgetRules))
+	p := NewProcessor(DefaultOptions())
+
+	decision := process(p, rs, time.Date(2025, 10, 1, 10, 0, 0, 0, time.UTC), 25)
+	if !decision.Emit || decision.Severity != rules.SeverityCritical {
+		t.Errorf("Expected critical emission, got emit=%t severity=%s", decision.Emit, decision.Severity)
+	}
+}
+
+func TestGapsAreNotCounted(t *testing.T) {
+	rs, _ := rules.Parse([]byte(budgetRules))
+	p := NewProcessor(DefaultOptions())
+	start := time.Date(2025, 10, 1, 10, 0, 0, 0, time.UTC)
+
+	process(p, rs, start, 9)
+	decision := process(p, rs, start.Add(3*time.Hour), 5)
+	if decision.Emit || decision.Metrics.TimeOutOfRangeSeconds != 0 {
+		t.Errorf("Expected data gap not to count, got %fs out of range", decision.Metrics.TimeOutOfRangeSeconds)
+	}
+}
+
+func TestDefaultBudgetIgnoresSingleNoisySample(t *testing.T) {
+	rs := rules.Default()
+	p := NewProcessor(DefaultOptions())
+	start := time.Date(2025, 10, 1, 10, 0, 0, 0, time.UTC)
+
+	process(p, rs, start, 15)
+	if decision := process(p, rs, start.Add(5*time.Minute), 9); decision.Emit {
+		t.Fatalf("Expected a single sample below 10°C not to emit with the default budget")
+	}
+	process(p, rs, start.Add(10*time.Minute), 15)
+
+	// A sustained excursion still emits once the budget is spent
+	emitted := 0
+	for i := 3; i <= 12; i++ {
+		if decision := process(p, rs, start.Add(time.Duration(i)*5*time.Minute), 9); decision.Emit {
+			emitted++
+			if decision.Severity != rules.SeverityMinor {
+				t.Errorf("Expected default budget severity minor, got %s", decision.Severity)
+			}
+		}
+	}
+	if emitted != 1 {
+		t.Errorf("Expected one emission after 30 minutes below 10°C, got %d", emitted)
+	}
+}
+
+func TestReassignedSensorStartsFresh(t *testing.T) {
+	rs, _ := rules.Parse([]byte(budgetRules))
+	p := NewProcessor(DefaultOptions())
+	start := time.Date(2025, 10, 1, 10, 0, 0, 0, time.UTC)
+
+	process(p, rs, start, 9)
+	process(p, rs, start.Add(20*time.Minute), 9)
+
+	rs.Sensors["sensor-1"] = rules.SensorInfo{Shipment: "shipment-2", Category: "vaccines"}
+	decision := process(p, rs, start.Add(25*time.Minute), 5)
+	if decision.Metrics.ShipmentID != "shipment-2" || decision.Metrics.Readings != 1 || decision.Metrics.TimeOutOfRangeSeconds != 0 {
+		t.Errorf("Expected fresh metrics for shipment-2, got %+v", decision.Metrics)
+	}
+	if metrics := p.Metrics(); len(metrics) != 1 {
+		t.Errorf("Expected the previous shipment state to be dropped, got %d states", len(metrics))
+	}
+}
+
+func TestIdleSensorsExpire(t *testing.T) {
+	rs, _ := rules.Parse([]byte(budgetRules))
+	options := DefaultOptions()
+	options.IdleTimeout = time.Hour
+	p := NewProcessor(options)
+	start := time.Date(2025, 10, 1, 10, 0, 0, 0, time.UTC)
+
+	process(p, rs, start, 9)
+	process(p, rs, start.Add(20*time.Minute), 9)
+
+	// The same sensor back after the idle timeout starts a new journey
+	decision := process(p, rs, start.Add(3*time.Hour), 9)
+	if decision.Metrics.Readings != 1 || decision.Metrics.MinTemperature != 9 {
+		t.Errorf("Expected fresh metrics after the idle timeout, got %+v", decision.Metrics)
+	}
+
+	// Other idle sensors are swept by later readings
+	other := rules.Reading{SensorID: "sensor-2", Temperature: 5, Timestamp: start.Add(4 * time.Hour)}
+	p.Process(other, (&rules.Engine{}).DryRun(rs, []rules.Reading{other})[0])
+	late := rules.Reading{SensorID: "sensor-2", Temperature: 5, Timestamp: start.Add(6 * time.Hour)}
+	p.Process(late, (&rules.Engine{}).DryRun(rs, []rules.Reading{late})[0])
+	if metrics := p.Metrics(); len(metrics) != 1 || metrics[0].SensorID != "sensor-2" {
+		t.Errorf("Expected only sensor-2 to remain tracked, got %+v", metrics)
+	}
+}
+
+func TestReset(t *testing.T) {
+	rs, _ := rules.Parse([]byte(budgetRules))
+	p := NewProcessor(DefaultOptions())
+
+	process(p, rs, time.Date(2025, 10, 1, 10, 0, 0, 0, time.UTC), 5)
+	if !p.Reset("sensor-1") {
+		t.Fatal("Expected sensor-1 to be tracked")
+	}
+	if p.Reset("sensor-1") || len(p.Metrics()) != 0 {
+		t.Error("Expected sensor-1 to be forgotten after reset")
+	}
+}
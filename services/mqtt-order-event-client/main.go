@@ -12,14 +12,14 @@ import (
 	"syscall"
 	"time"
 
+	"mqtt-order-event-client/coldchain"
 	publisher "mqtt-order-event-client/publisher"
+	"mqtt-order-event-client/rules"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 	"github.com/gin-gonic/gin"
 )
 
-var minTemperature float64 = 10.0
-
 // Event represents the structure of events received from mqtt-event-generator
 type Event struct {
 	ID        string    `json:"id"`
@@ -96,6 +96,15 @@ func (es *EventStore) GetEventCount() int {
 
 var eventStore *EventStore
 var orderPublisher *publisher.MqttPublisher
+var rulesEngine *rules.Engine
+var coldChain *coldchain.Processor
+
+// EvaluateRulesRequest is the payload of the dry-run evaluation endpoint.
+// When Rules is set the readings are evaluated against that candidate rule set.
+type EvaluateRulesRequest struct {
+	Readings []rules.Reading `json:"readings" binding:"required"`
+	Rules    json.RawMessage `json:"rules,omitempty"`
+}
 
 func main() {
 	// Initialize event store with max 1000 events
@@ -110,6 +119,37 @@ func main() {
 
 	// HTTP Server Configuration
 	httpPort := getEnv("HTTP_PORT", "8080")
+
+	// Damage rules configuration
+	rulesFile := getEnv("RULES_FILE", "")
+	rulesReloadInterval, err := time.ParseDuration(getEnv("RULES_RELOAD_INTERVAL", "30s"))
+	if err != nil {
+		log.Fatalf("Invalid RULES_RELOAD_INTERVAL: %v", err)
+	}
+
+	rulesEngine, err = rules.NewEngine(rulesFile)
+	if err != nil {
+		log.Fatalf("Error loading damage rules: %v", err)
+	}
+	log.Printf("Damage rules loaded from %s (version %s)", rulesEngine.Source(), rulesEngine.RuleSet().Version)
+
+	// Cold-chain stream processor configuration
+	coldChainOptions := coldchain.DefaultOptions()
+	if coldChainOptions.Window, err = time.ParseDuration(getEnv("COLDCHAIN_WINDOW", "24h")); err != nil {
+		log.Fatalf("Invalid COLDCHAIN_WINDOW: %v", err)
+	}
+	if coldChainOptions.MaxGap, err = time.ParseDuration(getEnv("COLDCHAIN_MAX_GAP", "30m")); err != nil {
+		log.Fatalf("Invalid COLDCHAIN_MAX_GAP: %v", err)
+	}
+	if coldChainOptions.IdleTimeout, err = time.ParseDuration(getEnv("COLDCHAIN_IDLE_TIMEOUT", "72h")); err != nil {
+		log.Fatalf("Invalid COLDCHAIN_IDLE_TIMEOUT: %v", err)
+	}
+	coldChain = coldchain.NewProcessor(coldChainOptions)
+
+	rulesCtx, stopRulesWatch := context.WithCancel(context.Background())
+	defer stopRulesWatch()
+	go rulesEngine.Watch(rulesCtx, rulesReloadInterval)
+
 	// Configure MQTT client options
 	opts := mqtt.NewClientOptions()
 	opts.AddBroker(broker)
@@ -144,7 +184,6 @@ func main() {
 	log.Printf("Subscribed to topic: %s", topic)
 
 	// Initialize MQTT Order Publisher from environment
-	var err error
 	orderPublisher, err = publisher.NewMqttPublisherFromEnv()
 	if err != nil {
 		log.Printf("Warning: could not initialize MQTT publisher: %v", err)
@@ -271,6 +310,68 @@ func startHTTPServer(port string) {
 			"average_humidity":    fmt.Sprintf("%.2f", avgHumidity),
 			"active_sensors":      activeCount,
 			"latest_event":        events[len(events)-1],
+			"cold_chain":          coldChain.Metrics(),
+		})
+	})
+
+	// Damage rules endpoints
+	router.GET("/rules", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"source": rulesEngine.Source(),
+			"rules":  rulesEngine.RuleSet(),
+		})
+	})
+
+	router.POST("/rules/reload", func(c *gin.Context) {
+		reloaded, err := rulesEngine.Reload(true)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   err.Error(),
+				"version": rulesEngine.RuleSet().Version,
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"reloaded": reloaded,
+			"source":   rulesEngine.Source(),
+			"version":  rulesEngine.RuleSet().Version,
+		})
+	})
+
+	router.POST("/rules/evaluate", func(c *gin.Context) {
+		var req EvaluateRulesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		var candidate *rules.RuleSet
+		if len(req.Rules) > 0 {
+			rs, err := rules.Parse(req.Rules)
+			if err != nil {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return
+			}
+			candidate = rs
+		}
+
+		results := rulesEngine.DryRun(candidate, req.Readings)
+		c.JSON(http.StatusOK, gin.H{
+			"results": results,
+			"count":   len(results),
+		})
+	})
+
+	// Forget the cold-chain metrics of a sensor once its shipment is delivered
+	router.POST("/coldchain/sensors/:sensorId/reset", func(c *gin.Context) {
+		sensorID := c.Param("sensorId")
+		if !coldChain.Reset(sensorID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "sensor not tracked", "sensor_id": sensorID})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"message":   "Cold-chain state reset",
+			"sensor_id": sensorID,
 		})
 	})
 
@@ -310,15 +411,39 @@ var messageHandler mqtt.MessageHandler = func(client mqtt.Client, msg mqtt.Messa
 
 	log.Printf("Event stored: ID=%s, Type=%s, Source=%s, Temp=%.2f°C, Humidity=%.2f%%",
 		event.ID, event.Type, event.Source, event.Data.Temperature, event.Data.Humidity)
-	// Publish order damage event to Kafka after logging and storing
-	if event.Data.Temperature < minTemperature {
-		log.Printf("Publishing order damage event for sensor/order id=%s", event.ID)
+	// Evaluate the reading against the damage rules, aggregate it into the shipment
+	// cold-chain metrics and publish an order damage event when the budget is exceeded
+	reading := rules.Reading{
+		SensorID:    event.Source,
+		Temperature: event.Data.Temperature,
+		Humidity:    event.Data.Humidity,
+		Timestamp:   event.Timestamp,
+	}
+	result := rulesEngine.Evaluate(reading)
+	decision := coldChain.Process(reading, result)
+	if decision.Emit {
+		log.Printf("Publishing order damage event for sensor=%s event=%s severity=%s rule=%s mkt=%.2f", event.Source, event.ID, decision.Severity, result.Rule, decision.Metrics.MKT)
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		if err := orderPublisher.PublishOrderDamageFromSensor(
 			ctx,
 			event.ID,
+			event.Source,
 			"mqtt-order-event-client",
+			publisher.Damage{
+				Severity:     decision.Severity,
+				Description:  decision.Description,
+				Rule:         result.Rule,
+				RulesVersion: result.Version,
+				ShipmentID:   result.ShipmentID,
+				Excursion: &publisher.Excursion{
+					MKT:                   decision.Metrics.MKT,
+					MinTemperature:        decision.Metrics.MinTemperature,
+					MaxTemperature:        decision.Metrics.MaxTemperature,
+					TimeOutOfRangeSeconds: decision.Metrics.TimeOutOfRangeSeconds,
+					Readings:              decision.Metrics.Readings,
+				},
+			},
 			event.Data.Temperature,
 			event.Data.Humidity,
 			event.Data.Status,
@@ -326,7 +451,7 @@ var messageHandler mqtt.MessageHandler = func(client mqtt.Client, msg mqtt.Messa
 		); err != nil {
 			log.Printf("Error publishing order damage event: %v", err)
 		} else {
-			log.Printf("Order damage event published for sensor/order id=%s", event.ID)
+			log.Printf("Order damage event published for sensor=%s event=%s", event.Source, event.ID)
 		}
 	}
 }
@@ -3,7 +3,6 @@ package publisher
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"os"
 	"strings"
 	"time"
@@ -19,17 +18,41 @@ type OrderDamageEvent struct {
 	Type        string        `json:"type"`   // e.g. "order.damage"
 	Source      string        `json:"source"` // service/source name
 	OccurredAt  time.Time     `json:"occurredAt"`
-	OrderID     string        `json:"orderId"`
+	OrderID     string        `json:"orderId,omitempty"` // set only when the order is known upstream
+	SensorID    string        `json:"sensorId"`
+	ShipmentID  string        `json:"shipmentId,omitempty"`
 	Severity    string        `json:"severity"` // minor|major|critical
 	Description string        `json:"description"`
 	Details     DamageDetails `json:"details"`
 }
 
 type DamageDetails struct {
-	Temperature float64 `json:"temperature"`
-	Humidity    float64 `json:"humidity"`
-	Status      string  `json:"status"`
-	MqttTopic   string  `json:"mqttTopic"`
+	Temperature  float64    `json:"temperature"`
+	Humidity     float64    `json:"humidity"`
+	Status       string     `json:"status"`
+	MqttTopic    string     `json:"mqttTopic"`
+	Rule         string     `json:"rule,omitempty"`
+	RulesVersion string     `json:"rulesVersion,omitempty"`
+	Excursion    *Excursion `json:"excursion,omitempty"`
+}
+
+// Excursion summarizes the cold-chain metrics of the shipment when damage was decided
+type Excursion struct {
+	MKT                   float64 `json:"mkt"`
+	MinTemperature        float64 `json:"minTemperature"`
+	MaxTemperature        float64 `json:"maxTemperature"`
+	TimeOutOfRangeSeconds float64 `json:"timeOutOfRangeSeconds"`
+	Readings              int     `json:"readings"`
+}
+
+// Damage carries the rules engine verdict used to build an OrderDamageEvent
+type Damage struct {
+	Severity     string
+	Description  string
+	Rule         string
+	RulesVersion string
+	ShipmentID   string
+	Excursion    *Excursion
 }
 
 type LoteInfo struct {
@@ -89,26 +112,31 @@ func (p *Publisher) Close() error {
 	return p.writer.Close()
 }
 
-// PublishOrderDamageFromSensor builds and publishes an OrderDamageEvent using sensor data.
-func (p *Publisher) PublishOrderDamageFromSensor(ctx context.Context, sensorID, source string, temperature, humidity float64, status, mqttTopic string) error {
+// PublishOrderDamageFromSensor builds and publishes an OrderDamageEvent using sensor data
+// and the severity decided by the damage rules engine.
+// The order is not known at the edge: the order service resolves it from the sensor ID.
+func (p *Publisher) PublishOrderDamageFromSensor(ctx context.Context, eventID, sensorID, source string, damage Damage, temperature, humidity float64, status, mqttTopic string) error {
 	if p == nil || p.writer == nil {
 		return nil
 	}
 
-	severity := deriveSeverity(temperature, humidity)
 	evt := OrderDamageEvent{
-		EventID:     sensorID,
+		EventID:     eventID,
 		Type:        "order.damage",
 		Source:      source,
 		OccurredAt:  time.Now().UTC(),
-		OrderID:     sensorID,
-		Severity:    severity,
-		Description: fmt.Sprintf("Potential damage detected: temp=%.2fC, humidity=%.2f%%", temperature, humidity),
+		SensorID:    sensorID,
+		ShipmentID:  damage.ShipmentID,
+		Severity:    damage.Severity,
+		Description: damage.Description,
 		Details: DamageDetails{
-			Temperature: temperature,
-			Humidity:    humidity,
-			Status:      status,
-			MqttTopic:   mqttTopic,
+			Temperature:  temperature,
+			Humidity:     humidity,
+			Status:       status,
+			MqttTopic:    mqttTopic,
+			Rule:         damage.Rule,
+			RulesVersion: damage.RulesVersion,
+			Excursion:    damage.Excursion,
 		},
 	}
 
@@ -118,22 +146,11 @@ func (p *Publisher) PublishOrderDamageFromSensor(ctx context.Context, sensorID,
 	}
 
 	return p.writer.WriteMessages(ctx, kafka.Message{
-		Key:   []byte(evt.OrderID),
+		Key:   []byte(evt.SensorID),
 		Value: payload,
 	})
 }
 
-func deriveSeverity(temperature, humidity float64) string {
-	severity := "minor"
-	if temperature >= 30 || humidity >= 80 {
-		severity = "major"
-	}
-	if temperature >= 40 || humidity >= 90 {
-		severity = "critical"
-	}
-	return severity
-}
-
 func getEnv(key, def string) string {
 	if val := os.Getenv(key); val != "" {
 		return val
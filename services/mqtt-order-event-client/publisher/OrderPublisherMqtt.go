@@ -57,26 +57,31 @@ func NewMqttPublisherFromEnv() (*MqttPublisher, error) {
 	return &MqttPublisher{client: client, Topic: topic}, nil
 }
 
-// PublishOrderDamageFromSensor builds an OrderDamageEvent and publishes it as JSON to MQTT.
-func (p *MqttPublisher) PublishOrderDamageFromSensor(ctx context.Context, sensorID, source string, temperature, humidity float64, status, mqttTopic string) error {
+// PublishOrderDamageFromSensor builds an OrderDamageEvent from the sensor data and the
+// severity decided by the damage rules engine, and publishes it as JSON to MQTT.
+// The order is not known at the edge: the order service resolves it from the sensor ID.
+func (p *MqttPublisher) PublishOrderDamageFromSensor(ctx context.Context, eventID, sensorID, source string, damage Damage, temperature, humidity float64, status, mqttTopic string) error {
 	if p == nil || p.client == nil {
 		return nil
 	}
 
-	severity := deriveSeverity(temperature, humidity)
 	evt := OrderDamageEvent{
-		EventID:     sensorID,
+		EventID:     eventID,
 		Type:        "order.damage",
 		Source:      source,
 		OccurredAt:  time.Now().UTC(),
-		OrderID:     sensorID,
-		Severity:    severity,
-		Description: fmt.Sprintf("Potential damage detected: temp=%.2fC, humidity=%.2f%%", temperature, humidity),
+		SensorID:    sensorID,
+		ShipmentID:  damage.ShipmentID,
+		Severity:    damage.Severity,
+		Description: damage.Description,
 		Details: DamageDetails{
-			Temperature: temperature,
-			Humidity:    humidity,
-			Status:      status,
-			MqttTopic:   mqttTopic,
+			Temperature:  temperature,
+			Humidity:     humidity,
+			Status:       status,
+			MqttTopic:    mqttTopic,
+			Rule:         damage.Rule,
+			RulesVersion: damage.RulesVersion,
+			Excursion:    damage.Excursion,
 		},
 	}
 
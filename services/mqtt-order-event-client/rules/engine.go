@@ -0,0 +1,233 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reading is a single sensor sample evaluated by the engine
+type Reading struct {
+	SensorID    string    `json:"sensorId"`
+	Product     string    `json:"product,omitempty"`
+	Category    string    `json:"category,omitempty"`
+	Temperature float64   `json:"temperature"`
+	Humidity    float64   `json:"humidity"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// Result is the outcome of evaluating a reading
+type Result struct {
+	Damaged     bool      `json:"damaged"`
+	Severity    string    `json:"severity,omitempty"`
+	Rule        string    `json:"rule,omitempty"`
+	FiredRules  []string  `json:"firedRules,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Profile     string    `json:"profile"`
+	Version     string    `json:"version"`
+	SensorID    string    `json:"sensorId"`
+	ShipmentID  string    `json:"shipmentId,omitempty"`
+	Budget      *Budget   `json:"budget,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// Engine evaluates readings against the active rule set. It keeps per-sensor
+// excursion state so that duration-based rules only fire once a condition has
+// held for long enough.
+type Engine struct {
+	mu      sync.RWMutex
+	ruleSet *RuleSet
+	path    string
+	modTime time.Time
+	state   *excursionState
+}
+
+// excursionState tracks when each sensor/rule condition started to hold
+type excursionState struct {
+	since map[string]time.Time
+}
+
+func newExcursionState() *excursionState {
+	return &excursionState{since: make(map[string]time.Time)}
+}
+
+// NewEngine creates an engine from a rules file, or from the built-in rules when path is empty
+func NewEngine(path string) (*Engine, error) {
+	engine := &Engine{path: path, state: newExcursionState()}
+
+	if path == "" {
+		engine.ruleSet = Default()
+		return engine, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read rules file: %w", err)
+	}
+	rs, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	engine.ruleSet = rs
+	engine.modTime = info.ModTime()
+	return engine, nil
+}
+
+// RuleSet returns the active rule set
+func (e *Engine) RuleSet() *RuleSet {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.ruleSet
+}
+
+// Source returns the rules file path, or "builtin" for the embedded rules
+func (e *Engine) Source() string {
+	if e.path == "" {
+		return "builtin"
+	}
+	return e.path
+}
+
+// Evaluate judges a live reading, updating the excursion state
+func (e *Engine) Evaluate(reading Reading) Result {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return evaluate(e.ruleSet, e.state, reading)
+}
+
+// DryRun evaluates a sequence of readings with fresh state, without touching
+// the live excursion state. When candidate is nil the active rule set is used.
+func (e *Engine) DryRun(candidate *RuleSet, readings []Reading) []Result {
+	rs := candidate
+	if rs == nil {
+		rs = e.RuleSet()
+	}
+
+	state := newExcursionState()
+	results := make([]Result, 0, len(readings))
+	for _, reading := range readings {
+		results = append(results, evaluate(rs, state, reading))
+	}
+	return results
+}
+
+// Reload re-reads the rules file if it changed. Invalid files are rejected and
+// the previous rule set stays active. It reports whether a new set was loaded.
+func (e *Engine) Reload(force bool) (bool, error) {
+	if e.path == "" {
+		return false, nil
+	}
+
+	info, err := os.Stat(e.path)
+	if err != nil {
+		return false, fmt.Errorf("cannot read rules file: %w", err)
+	}
+
+	e.mu.RLock()
+	unchanged := info.ModTime().Equal(e.modTime)
+	e.mu.RUnlock()
+	if unchanged && !force {
+		return false, nil
+	}
+
+	rs, err := LoadFile(e.path)
+	if err != nil {
+		return false, err
+	}
+
+	e.mu.Lock()
+	previous := e.ruleSet.Version
+	e.ruleSet = rs
+	e.modTime = info.ModTime()
+	e.mu.Unlock()
+
+	log.Printf("Damage rules reloaded from %s: version %s -> %s", e.path, previous, rs.Version)
+	return true, nil
+}
+
+// Watch polls the rules file and hot-reloads it until the context is cancelled
+func (e *Engine) Watch(ctx context.Context, interval time.Duration) {
+	if e.path == "" || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := e.Reload(false); err != nil {
+				log.Printf("Error reloading damage rules, keeping version %s: %v", e.RuleSet().Version, err)
+			}
+		}
+	}
+}
+
+// evaluate applies a rule set to a reading. The most severe fired rule wins.
+func evaluate(rs *RuleSet, state *excursionState, reading Reading) Result {
+	product, category, shipment := reading.Product, reading.Category, ""
+	if info, ok := rs.Sensors[reading.SensorID]; ok {
+		shipment = info.Shipment
+		if product == "" {
+			product = info.Product
+		}
+		if category == "" {
+			category = info.Category
+		}
+	}
+
+	at := reading.Timestamp
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	profile, scope := rs.profileFor(product, category)
+	result := Result{
+		Profile:    scope,
+		Version:    rs.Version,
+		SensorID:   reading.SensorID,
+		ShipmentID: shipment,
+		Budget:     profile.Budget,
+		Timestamp:  at,
+	}
+
+	var descriptions []string
+	for _, rule := range profile.Rules {
+		key := reading.SensorID + "|" + scope + "|" + rule.Name
+		if !rule.matches(reading.Temperature, reading.Humidity) {
+			delete(state.since, key)
+			continue
+		}
+
+		since, ok := state.since[key]
+		if !ok || at.Before(since) {
+			since = at
+			state.since[key] = since
+		}
+		if at.Sub(since) < time.Duration(rule.For) {
+			continue
+		}
+
+		result.FiredRules = append(result.FiredRules, rule.Name)
+		descriptions = append(descriptions, fmt.Sprintf("%s (%s)", rule.Name, rule.describe()))
+		if severityRank[rule.Severity] > severityRank[result.Severity] {
+			result.Severity = rule.Severity
+			result.Rule = rule.Name
+		}
+	}
+
+	if len(result.FiredRules) > 0 {
+		result.Damaged = true
+		result.Description = fmt.Sprintf("Potential damage detected: temp=%.2fC, humidity=%.2f%%; rules: %s",
+			reading.Temperature, reading.Humidity, strings.Join(descriptions, ", "))
+	}
+	return result
+}
@@ -0,0 +1,162 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const vaccineRules = `
+version: "test-1"
+sensors:
+  sensor-1:
+    category: vaccines
+default:
+  rules: []
+categories:
+  vaccines:
+    rules:
+      - name: above-8c
+        metric: temperature
+        above: 8
+        severity: minor
+      - name: above-8c-30m
+        metric: temperature
+        above: 8
+        for: 30m
+        severity: major
+products:
+  tablets-x:
+    rules:
+      - name: out-of-range
+        metric: temperature
+        below: 15
+        above: 25
+        severity: minor
+`
+
+func TestDefaultRulesMatchHistoricalThresholds(t *testing.T) {
+	engine, err := NewEngine("")
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	cases := []struct {
+		temperature, humidity float64
+		damaged               bool
+		severity              string
+	}{
+		{temperature: 12, humidity: 50, damaged: false},
+		{temperature: 9, humidity: 50, damaged: true, severity: SeverityMinor},
+		{temperature: 9, humidity: 85, damaged: true, severity: SeverityMajor},
+		{temperature: 9, humidity: 95, damaged: true, severity: SeverityCritical},
+		// Humidity thresholds were inclusive
+		{temperature: 9, humidity: 79.9, damaged: true, severity: SeverityMinor},
+		{temperature: 9, humidity: 80, damaged: true, severity: SeverityMajor},
+		{temperature: 9, humidity: 89.9, damaged: true, severity: SeverityMajor},
+		{temperature: 9, humidity: 90, damaged: true, severity: SeverityCritical},
+		// Only readings below 10°C were reported, whatever the humidity
+		{temperature: 9.99, humidity: 50, damaged: true, severity: SeverityMinor},
+		{temperature: 10, humidity: 50, damaged: false},
+		{temperature: 10, humidity: 80, damaged: false},
+		{temperature: 10, humidity: 95, damaged: false},
+		{temperature: 12, humidity: 85, damaged: false},
+		{temperature: 35, humidity: 50, damaged: false},
+		{temperature: 45, humidity: 95, damaged: false},
+	}
+
+	for _, tc := range cases {
+		result := engine.Evaluate(Reading{SensorID: "s", Temperature: tc.temperature, Humidity: tc.humidity})
+		if result.Damaged != tc.damaged || result.Severity != tc.severity {
+			t.Errorf("temp=%.1f humidity=%.1f: expected damaged=%t severity=%q, got damaged=%t severity=%q",
+				tc.temperature, tc.humidity, tc.damaged, tc.severity, result.Damaged, result.Severity)
+		}
+	}
+}
+
+func TestDurationBasedExcursion(t *testing.T) {
+	rs, err := Parse([]byte(vaccineRules))
+	if err != nil {
+		t.Fatalf("Failed to parse rules: %v", err)
+	}
+
+	engine := &Engine{ruleSet: rs, state: newExcursionState()}
+	start := time.Date(2025, 10, 1, 10, 0, 0, 0, time.UTC)
+
+	first := engine.Evaluate(Reading{SensorID: "sensor-1", Temperature: 9, Timestamp: start})
+	if first.Severity != SeverityMinor || first.Profile != "category:vaccines" {
+		t.Fatalf("Expected minor from vaccines profile, got %q from %s", first.Severity, first.Profile)
+	}
+
+	later := engine.Evaluate(Reading{SensorID: "sensor-1", Temperature: 9, Timestamp: start.Add(31 * time.Minute)})
+	if later.Severity != SeverityMajor || later.Rule != "above-8c-30m" {
+		t.Errorf("Expected major from above-8c-30m after 31 minutes, got %q from %q", later.Severity, later.Rule)
+	}
+
+	// Returning to range resets the excursion
+	engine.Evaluate(Reading{SensorID: "sensor-1", Temperature: 5, Timestamp: start.Add(32 * time.Minute)})
+	again := engine.Evaluate(Reading{SensorID: "sensor-1", Temperature: 9, Timestamp: start.Add(33 * time.Minute)})
+	if again.Severity != SeverityMinor {
+		t.Errorf("Expected excursion to restart as minor, got %q", again.Severity)
+	}
+}
+
+func TestProductProfileTakesPrecedence(t *testing.T) {
+	rs, _ := Parse([]byte(vaccineRules))
+	engine := &Engine{ruleSet: rs, state: newExcursionState()}
+
+	results := engine.DryRun(nil, []Reading{
+		{SensorID: "sensor-1", Product: "tablets-x", Temperature: 20},
+		{SensorID: "sensor-1", Product: "tablets-x", Temperature: 26},
+	})
+
+	if results[0].Damaged || results[0].Profile != "product:tablets-x" {
+		t.Errorf("Expected 20°C to be within tablets-x range, got %+v", results[0])
+	}
+	if !results[1].Damaged {
+		t.Errorf("Expected 26°C to be out of tablets-x range")
+	}
+	if len(engine.state.since) != 0 {
+		t.Errorf("Expected dry run not to touch live state")
+	}
+}
+
+func TestParseRejectsInvalidRules(t *testing.T) {
+	invalid := []string{
+		`default: {rules: []}`,
+		`{"version": "1", "default": {"rules": [{"name": "x", "metric": "pressure", "above": 1, "severity": "minor"}]}}`,
+		`{"version": "1", "default": {"rules": [{"name": "x", "metric": "temperature", "severity": "minor"}]}}`,
+		`{"version": "1", "default": {"rules": [{"name": "x", "metric": "temperature", "above": 1, "severity": "fatal"}]}}`,
+		`{"version": "1", "default": {"rules": [{"name": "x", "metric": "humidity", "atLeast": 80, "when": [{"metric": "pressure", "below": 1}], "severity": "minor"}]}}`,
+		`{"version": "1", "default": {"rules": [{"name": "x", "metric": "humidity", "atLeast": 80, "when": [{"metric": "temperature"}], "severity": "minor"}]}}`,
+	}
+
+	for _, doc := range invalid {
+		if _, err := Parse([]byte(doc)); err == nil {
+			t.Errorf("Expected error parsing %s", doc)
+		}
+	}
+}
+
+func TestReloadKeepsPreviousRulesOnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte(vaccineRules), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine, err := NewEngine(path)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("version: \"\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.Reload(true); err == nil {
+		t.Fatal("Expected reload error for invalid rules")
+	}
+	if engine.RuleSet().Version != "test-1" {
+		t.Errorf("Expected previous version to remain active, got %s", engine.RuleSet().Version)
+	}
+}
@@ -0,0 +1,331 @@
+package rules
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed default_rules.yaml
+var defaultRules []byte
+
+// Supported metrics and severities. Severities are ordered from least to most severe.
+const (
+	MetricTemperature = "temperature"
+	MetricHumidity    = "humidity"
+
+	SeverityMinor    = "minor"
+	SeverityMajor    = "major"
+	SeverityCritical = "critical"
+)
+
+var severityRank = map[string]int{
+	SeverityMinor:    1,
+	SeverityMajor:    2,
+	SeverityCritical: 3,
+}
+
+// RuleSet is a versioned set of damage rules. Profiles are looked up by
+// product first, then by category, and finally the default profile applies.
+type RuleSet struct {
+	Version    string                `json:"version"`
+	Default    Profile               `json:"default"`
+	Categories map[string]Profile    `json:"categories,omitempty"`
+	Products   map[string]Profile    `json:"products,omitempty"`
+	Sensors    map[string]SensorInfo `json:"sensors,omitempty"`
+}
+
+// SensorInfo tells which shipment, product and category a sensor is monitoring
+type SensorInfo struct {
+	Shipment string `json:"shipment,omitempty"`
+	Product  string `json:"product,omitempty"`
+	Category string `json:"category,omitempty"`
+}
+
+// Profile groups the rules that apply to a product or category
+type Profile struct {
+	Description string  `json:"description,omitempty"`
+	Rules       []Rule  `json:"rules"`
+	Budget      *Budget `json:"budget,omitempty"`
+}
+
+// Budget is the excursion allowance of a shipment over its whole journey.
+// Damage is only reported once the budget is exceeded; critical rules bypass it.
+// Either temperature bound may be omitted to budget a single side of the range.
+type Budget struct {
+	MinTemperature    *float64 `json:"minTemperature,omitempty"`
+	MaxTemperature    *float64 `json:"maxTemperature,omitempty"`
+	MaxTimeOutOfRange Duration `json:"maxTimeOutOfRange,omitempty"`
+	MaxMKT            *float64 `json:"maxMkt,omitempty"`
+	Severity          string   `json:"severity"`
+}
+
+// Threshold is violated when a value is above or below (strict) or at least
+// or at most (inclusive) any of the configured limits
+type Threshold struct {
+	Above   *float64 `json:"above,omitempty"`
+	Below   *float64 `json:"below,omitempty"`
+	AtLeast *float64 `json:"atLeast,omitempty"`
+	AtMost  *float64 `json:"atMost,omitempty"`
+}
+
+// Rule fires when a metric violates its threshold for at least the given
+// duration, and only while every condition in When holds as well
+type Rule struct {
+	Name   string `json:"name"`
+	Metric string `json:"metric"`
+	Threshold
+	When     []Condition `json:"when,omitempty"`
+	For      Duration    `json:"for,omitempty"`
+	Severity string      `json:"severity"`
+}
+
+// Condition restricts a rule to readings where another metric violates a threshold
+type Condition struct {
+	Metric string `json:"metric"`
+	Threshold
+}
+
+// Duration is a time.Duration that decodes from strings such as "30m"
+type Duration time.Duration
+
+// UnmarshalJSON accepts a Go duration string or a number of seconds
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	switch v := raw.(type) {
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", v, err)
+		}
+		*d = Duration(parsed)
+	case float64:
+		*d = Duration(time.Duration(v * float64(time.Second)))
+	case nil:
+		*d = 0
+	default:
+		return fmt.Errorf("invalid duration %v", raw)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the duration as a Go duration string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// MoreSevere reports whether severity a ranks above severity b
+func MoreSevere(a, b string) bool {
+	return severityRank[a] > severityRank[b]
+}
+
+// metricValue returns the reading value of a metric
+func metricValue(metric string, temperature, humidity float64) float64 {
+	if metric == MetricHumidity {
+		return humidity
+	}
+	return temperature
+}
+
+// violated reports whether a value violates any of the threshold limits
+func (t Threshold) violated(value float64) bool {
+	if t.Above != nil && value > *t.Above {
+		return true
+	}
+	if t.Below != nil && value < *t.Below {
+		return true
+	}
+	if t.AtLeast != nil && value >= *t.AtLeast {
+		return true
+	}
+	if t.AtMost != nil && value <= *t.AtMost {
+		return true
+	}
+	return false
+}
+
+func (t Threshold) empty() bool {
+	return t.Above == nil && t.Below == nil && t.AtLeast == nil && t.AtMost == nil
+}
+
+// describe renders the threshold of a metric for damage descriptions
+func (t Threshold) describe(metric string) string {
+	var parts []string
+	if t.Above != nil {
+		parts = append(parts, fmt.Sprintf("%s above %.2f", metric, *t.Above))
+	}
+	if t.Below != nil {
+		parts = append(parts, fmt.Sprintf("%s below %.2f", metric, *t.Below))
+	}
+	if t.AtLeast != nil {
+		parts = append(parts, fmt.Sprintf("%s at least %.2f", metric, *t.AtLeast))
+	}
+	if t.AtMost != nil {
+		parts = append(parts, fmt.Sprintf("%s at most %.2f", metric, *t.AtMost))
+	}
+	return strings.Join(parts, " or ")
+}
+
+// matches reports whether a reading violates the rule threshold while all of
+// its conditions hold
+func (r Rule) matches(temperature, humidity float64) bool {
+	if !r.violated(metricValue(r.Metric, temperature, humidity)) {
+		return false
+	}
+	for _, condition := range r.When {
+		if !condition.violated(metricValue(condition.Metric, temperature, humidity)) {
+			return false
+		}
+	}
+	return true
+}
+
+// describe renders the rule condition for damage descriptions
+func (r Rule) describe() string {
+	condition := r.Threshold.describe(r.Metric)
+	for _, when := range r.When {
+		condition += fmt.Sprintf(" when %s", when.Threshold.describe(when.Metric))
+	}
+	if r.For > 0 {
+		condition += fmt.Sprintf(" for %s", time.Duration(r.For))
+	}
+	return condition
+}
+
+// Validate checks that every rule in the set is well formed
+func (rs *RuleSet) Validate() error {
+	if rs.Version == "" {
+		return fmt.Errorf("rule set version is required")
+	}
+	if err := rs.Default.validate("default"); err != nil {
+		return err
+	}
+	for name, profile := range rs.Categories {
+		if err := profile.validate("category " + name); err != nil {
+			return err
+		}
+	}
+	for name, profile := range rs.Products {
+		if err := profile.validate("product " + name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p Profile) validate(scope string) error {
+	if b := p.Budget; b != nil {
+		if b.MinTemperature == nil && b.MaxTemperature == nil {
+			return fmt.Errorf("%s: budget needs minTemperature or maxTemperature", scope)
+		}
+		if b.MinTemperature != nil && b.MaxTemperature != nil && *b.MinTemperature >= *b.MaxTemperature {
+			return fmt.Errorf("%s: budget minTemperature must be lower than maxTemperature", scope)
+		}
+		if b.MaxTimeOutOfRange <= 0 && b.MaxMKT == nil {
+			return fmt.Errorf("%s: budget needs maxTimeOutOfRange or maxMkt", scope)
+		}
+		if _, ok := severityRank[b.Severity]; !ok {
+			return fmt.Errorf("%s: budget has unknown severity %q", scope, b.Severity)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, rule := range p.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("%s: rule %d has no name", scope, i)
+		}
+		if seen[rule.Name] {
+			return fmt.Errorf("%s: duplicated rule name %q", scope, rule.Name)
+		}
+		seen[rule.Name] = true
+		if rule.Metric != MetricTemperature && rule.Metric != MetricHumidity {
+			return fmt.Errorf("%s: rule %q has unknown metric %q", scope, rule.Name, rule.Metric)
+		}
+		if rule.empty() {
+			return fmt.Errorf("%s: rule %q needs an above, below, atLeast or atMost threshold", scope, rule.Name)
+		}
+		for _, condition := range rule.When {
+			if condition.Metric != MetricTemperature && condition.Metric != MetricHumidity {
+				return fmt.Errorf("%s: rule %q has a condition on unknown metric %q", scope, rule.Name, condition.Metric)
+			}
+			if condition.empty() {
+				return fmt.Errorf("%s: rule %q has a condition without threshold", scope, rule.Name)
+			}
+		}
+		if _, ok := severityRank[rule.Severity]; !ok {
+			return fmt.Errorf("%s: rule %q has unknown severity %q", scope, rule.Name, rule.Severity)
+		}
+		if rule.For < 0 {
+			return fmt.Errorf("%s: rule %q has a negative duration", scope, rule.Name)
+		}
+	}
+	return nil
+}
+
+// profileFor resolves the profile and its scope name for a reading
+func (rs *RuleSet) profileFor(product, category string) (Profile, string) {
+	if profile, ok := rs.Products[product]; ok && product != "" {
+		return profile, "product:" + product
+	}
+	if profile, ok := rs.Categories[category]; ok && category != "" {
+		return profile, "category:" + category
+	}
+	return rs.Default, "default"
+}
+
+// Parse decodes a rule set from YAML or JSON. JSON is valid YAML, so the
+// document is always read as YAML and re-encoded to reuse the JSON tags.
+func Parse(data []byte) (*RuleSet, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid rules document: %w", err)
+	}
+
+	normalized, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rules document: %w", err)
+	}
+
+	var rs RuleSet
+	if err := json.Unmarshal(normalized, &rs); err != nil {
+		return nil, fmt.Errorf("invalid rules document: %w", err)
+	}
+
+	if err := rs.Validate(); err != nil {
+		return nil, err
+	}
+	return &rs, nil
+}
+
+// LoadFile reads a rule set from a .yaml, .yml or .json file
+func LoadFile(path string) (*RuleSet, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return nil, fmt.Errorf("unsupported rules file extension: %s", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Default returns the built-in rule set, equivalent to the historical hard-coded thresholds
+func Default() *RuleSet {
+	rs, err := Parse(defaultRules)
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded default rules: %v", err))
+	}
+	return rs
+}
@@ -1,8 +1,10 @@
 package application
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/MATI-MBIT/arqnewgen-medisupply-eda/simple-service/oder/src/domain"
@@ -13,13 +15,30 @@ import (
 type OrderService struct {
 	orderRepo      domain.OrderRepository
 	eventPublisher domain.OrderEventPublisher
+	sensorMappings *SensorMappingService
+	reviewRepo     domain.DamageReviewRepository
+	severityPolicy domain.DamageSeverityPolicy
+
+	// reviewMutex serializes resolving and discarding damage reviews so a
+	// review is closed, and its damage applied, only once
+	reviewMutex sync.Mutex
 }
 
+var (
+	// ErrDamageReviewClosed is returned when resolving or discarding a review that is no longer pending
+	ErrDamageReviewClosed = errors.New("damage review already closed")
+	// ErrOrderNotFound is returned when an operation references an order that does not exist
+	ErrOrderNotFound = errors.New("order not found")
+)
+
 // NewOrderService creates a new OrderService
-func NewOrderService(orderRepo domain.OrderRepository, eventPublisher domain.OrderEventPublisher) *OrderService {
+func NewOrderService(orderRepo domain.OrderRepository, eventPublisher domain.OrderEventPublisher, sensorMappings *SensorMappingService, reviewRepo domain.DamageReviewRepository, severityPolicy domain.DamageSeverityPolicy) *OrderService {
 	return &OrderService{
 		orderRepo:      orderRepo,
 		eventPublisher: eventPublisher,
+		sensorMappings: sensorMappings,
+		reviewRepo:     reviewRepo,
+		severityPolicy: severityPolicy,
 	}
 }
 
@@ -122,62 +141,79 @@ func (s *OrderService) HandleOrderEvent(event domain.OrderEvent) error {
 	return nil
 }
 
-// HandleOrderDamageEvent processes incoming order damage events from MQTT
+// HandleOrderDamageEvent processes incoming order damage events from MQTT.
+// Events that cannot be correlated to an existing order are parked in the
+// damage review queue instead of fabricating an order.
 func (s *OrderService) HandleOrderDamageEvent(event domain.OrderDamageEvent) error {
-	log.Printf("Processing order damage event: EventID=%s, OrderID=%s, Severity=%s, OccurredAt=%s", 
-		event.EventID, event.OrderID, event.Severity, event.OccurredAt.Format("2006-01-02 15:04:05"))
+	log.Printf("Processing order damage event: EventID=%s, OrderID=%s, SensorID=%s, ShipmentID=%s, Severity=%s, OccurredAt=%s", 
+		event.EventID, event.OrderID, event.SensorID, event.ShipmentID, event.Severity, event.OccurredAt.Format("2006-01-02 15:04:05"))
 	
 	log.Printf("Damage details: Temperature=%.2f°C, Humidity=%d%%, Status=%s", 
 		event.Details.Temperature, event.Details.Humidity, event.Details.Status)
 	
 	log.Printf("Damage description: %s", event.Description)
 	
-	// Check if order exists, if not create a new one
-	order, err := s.orderRepo.FindByID(event.OrderID)
+	order, err := s.correlateDamageEvent(event)
 	if err != nil {
-		log.Printf("Order %s not found, creating new order from damage event", event.OrderID)
-		
-		// Create new order with the received order ID
-		newOrder := domain.Order{
-			ID:          event.OrderID,
-			CustomerID:  "unknown", // Default value since not provided in damage event
-			ProductID:   "unknown", // Default value since not provided in damage event
-			Quantity:    1,         // Default value
-			Status:      "created_from_damage_event",
-			TotalAmount: 0.0,       // Default value
-			CreatedAt:   event.OccurredAt,
-			UpdatedAt:   time.Now(),
-		}
-		
-		// Save the new order
-		if err := s.orderRepo.Save(newOrder); err != nil {
-			return fmt.Errorf("failed to create order from damage event: %w", err)
+		log.Printf("Damage event %s could not be correlated to an order: %v", event.EventID, err)
+		return s.parkDamageEvent(event, err.Error())
+	}
+	
+	return s.applyDamage(order, event)
+}
+
+// correlateDamageEvent finds the order affected by a damage event, first by
+// its order ID and then through the sensor/shipment assignments
+func (s *OrderService) correlateDamageEvent(event domain.OrderDamageEvent) (*domain.Order, error) {
+	if event.OrderID != "" {
+		if order, err := s.orderRepo.FindByID(event.OrderID); err == nil {
+			log.Printf("Found existing order %s", event.OrderID)
+			return order, nil
 		}
-		
-		log.Printf("Created new order from damage event: ID=%s", newOrder.ID)
-		order = &newOrder
-	} else {
-		log.Printf("Found existing order %s", event.OrderID)
 	}
 	
+	assignment, err := s.sensorMappings.Resolve(event)
+	if err != nil {
+		return nil, err
+	}
+	
+	order, err := s.orderRepo.FindByID(assignment.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("sensor %s is assigned to missing order %s", assignment.SensorID, assignment.OrderID)
+	}
+	
+	log.Printf("Resolved damage event %s to order %s via sensor %s", event.EventID, order.ID, assignment.SensorID)
+	return order, nil
+}
+
+// parkDamageEvent stores an unresolvable damage event for manual review
+func (s *OrderService) parkDamageEvent(event domain.OrderDamageEvent, reason string) error {
+	review := domain.DamageReview{
+		ID:         uuid.New().String(),
+		Event:      event,
+		Reason:     reason,
+		Status:     domain.DamageReviewPending,
+		ReceivedAt: time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	
+	if err := s.reviewRepo.Save(review); err != nil {
+		return fmt.Errorf("failed to park damage event for review: %w", err)
+	}
+	
+	log.Printf("Damage event %s parked for review: ReviewID=%s", event.EventID, review.ID)
+	return nil
+}
+
+// applyDamage updates the order status according to the damage severity and
+// publishes the processed event
+func (s *OrderService) applyDamage(order *domain.Order, event domain.OrderDamageEvent) error {
 	// Determine the new status based on damage severity
-	var newStatus string
-	switch event.Severity {
-	case "minor":
-		log.Printf("Minor damage detected for order %s - monitoring required", event.OrderID)
-		newStatus = "damage_detected_minor"
-		
-	case "major":
-		log.Printf("Major damage detected for order %s - immediate action required", event.OrderID)
-		newStatus = "damage_detected_major"
-		
-	case "critical":
-		log.Printf("Critical damage detected for order %s - order should be cancelled", event.OrderID)
-		newStatus = "cancelled_damage"
-		
-	default:
-		log.Printf("Unknown damage severity: %s for order %s", event.Severity, event.OrderID)
-		newStatus = "damage_detected_unknown"
+	newStatus, known := s.severityPolicy.StatusFor(event.Severity)
+	if !known {
+		log.Printf("Unknown damage severity: %s for order %s", event.Severity, order.ID)
+	} else {
+		log.Printf("%s damage detected for order %s - moving to %s", event.Severity, order.ID, newStatus)
 	}
 	
 	// Update order status
@@ -209,4 +245,83 @@ func (s *OrderService) HandleOrderDamageEvent(event domain.OrderDamageEvent) err
 	// - Updating inventory status
 	
 	return nil
-}
\ No newline at end of file
+}
+
+// GetDamageReviews retrieves parked damage events, optionally filtered by status
+func (s *OrderService) GetDamageReviews(status string) ([]domain.DamageReview, error) {
+	if status == "" {
+		return s.reviewRepo.FindAll()
+	}
+	return s.reviewRepo.FindByStatus(domain.DamageReviewStatus(status))
+}
+
+// GetDamageReview retrieves a parked damage event by ID
+func (s *OrderService) GetDamageReview(id string) (*domain.DamageReview, error) {
+	return s.reviewRepo.FindByID(id)
+}
+
+// ResolveDamageReview applies a parked damage event to the order chosen by an operator
+func (s *OrderService) ResolveDamageReview(id, orderID string) (*domain.DamageReview, error) {
+	s.reviewMutex.Lock()
+	defer s.reviewMutex.Unlock()
+	
+	review, err := s.pendingDamageReview(id)
+	if err != nil {
+		return nil, err
+	}
+	
+	order, err := s.orderRepo.FindByID(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOrderNotFound, err)
+	}
+	
+	if err := s.applyDamage(order, review.Event); err != nil {
+		return nil, err
+	}
+	
+	review.Status = domain.DamageReviewResolved
+	review.ResolvedOrderID = order.ID
+	review.UpdatedAt = time.Now()
+	
+	if err := s.reviewRepo.Update(*review); err != nil {
+		return nil, fmt.Errorf("failed to update damage review: %w", err)
+	}
+	
+	log.Printf("Damage review %s resolved to order %s", review.ID, order.ID)
+	return review, nil
+}
+
+// DiscardDamageReview marks a parked damage event as not applicable to any order
+func (s *OrderService) DiscardDamageReview(id string) (*domain.DamageReview, error) {
+	s.reviewMutex.Lock()
+	defer s.reviewMutex.Unlock()
+	
+	review, err := s.pendingDamageReview(id)
+	if err != nil {
+		return nil, err
+	}
+	
+	review.Status = domain.DamageReviewDiscarded
+	review.UpdatedAt = time.Now()
+	
+	if err := s.reviewRepo.Update(*review); err != nil {
+		return nil, fmt.Errorf("failed to update damage review: %w", err)
+	}
+	
+	log.Printf("Damage review %s discarded", review.ID)
+	return review, nil
+}
+
+// pendingDamageReview retrieves a damage review that has not been handled yet
+func (s *OrderService) pendingDamageReview(id string) (*domain.DamageReview, error) {
+	review, err := s.reviewRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	
+	if review.Status != domain.DamageReviewPending {
+		return nil, fmt.Errorf("%w: review %s is %s", ErrDamageReviewClosed, review.ID, review.Status)
+	}
+	
+	return review, nil
+}
@@ -0,0 +1,215 @@
+package application
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MATI-MBIT/arqnewgen-medisupply-eda/simple-service/oder/src/domain"
+	drivenadapters "github.com/MATI-MBIT/arqnewgen-medisupply-eda/simple-service/oder/src/infrastructure/driven-adapters"
+)
+
+// recordingPublisher records the events the service publishes
+type recordingPublisher struct {
+	mutex  sync.Mutex
+	events []domain.OrderEvent
+}
+
+func (p *recordingPublisher) PublishOrderEvent(event domain.OrderEvent) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.events = append(p.events, event)
+	return nil
+}
+
+// published returns a copy of the recorded events
+func (p *recordingPublisher) published() []domain.OrderEvent {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]domain.OrderEvent(nil), p.events...)
+}
+
+func newTestOrderService() (*OrderService, *SensorMappingService, *recordingPublisher) {
+	orderRepo := drivenadapters.NewMemoryOrderRepository()
+	publisher := &recordingPublisher{}
+	sensorMappings := NewSensorMappingService(drivenadapters.NewMemorySensorAssignmentRepository(), orderRepo)
+	service := NewOrderService(orderRepo, publisher, sensorMappings, drivenadapters.NewMemoryDamageReviewRepository(), domain.DefaultDamageSeverityPolicy())
+	return service, sensorMappings, publisher
+}
+
+func damageEvent(sensorID, severity string) domain.OrderDamageEvent {
+	return domain.OrderDamageEvent{
+		EventID:    "evt_1",
+		Type:       "order.damage",
+		OccurredAt: time.Now(),
+		SensorID:   sensorID,
+		Severity:   severity,
+	}
+}
+
+func TestOrderService_HandleOrderDamageEvent_ResolvesThroughSensor(t *testing.T) {
+	service, sensorMappings, publisher := newTestOrderService()
+
+	order, err := service.CreateOrder("customer-1", "vaccine-1", 10, 100)
+	if err != nil {
+		t.Fatalf("Failed to create order: %v", err)
+	}
+	if _, err := sensorMappings.AssignSensor("temperature_sensor_03", "shipment-1", order.ID, "LOTE-1", "0xabc"); err != nil {
+		t.Fatalf("Failed to assign sensor: %v", err)
+	}
+
+	if err := service.HandleOrderDamageEvent(damageEvent("temperature_sensor_03", "critical")); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	updated, _ := service.GetOrder(order.ID)
+	if updated.Status != "cancelled_damage" {
+		t.Errorf("Expected status cancelled_damage, got %s", updated.Status)
+	}
+
+	events := publisher.published()
+	last := events[len(events)-1]
+	if last.EventType != "order.damage_processed" || last.OrderID != order.ID {
+		t.Errorf("Expected damage_processed event for %s, got %s for %s", order.ID, last.EventType, last.OrderID)
+	}
+
+	reviews, _ := service.GetDamageReviews("")
+	if len(reviews) != 0 {
+		t.Errorf("Expected no parked reviews, got %d", len(reviews))
+	}
+}
+
+func TestOrderService_HandleOrderDamageEvent_ParksUnresolvedEvent(t *testing.T) {
+	service, _, _ := newTestOrderService()
+
+	if err := service.HandleOrderDamageEvent(damageEvent("unknown_sensor", "major")); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	orders, _ := service.GetAllOrders()
+	if len(orders) != 0 {
+		t.Errorf("Expected no order to be created, got %d", len(orders))
+	}
+
+	reviews, _ := service.GetDamageReviews(string(domain.DamageReviewPending))
+	if len(reviews) != 1 {
+		t.Fatalf("Expected 1 pending review, got %d", len(reviews))
+	}
+	if reviews[0].Event.SensorID != "unknown_sensor" {
+		t.Errorf("Expected parked event for unknown_sensor, got %s", reviews[0].Event.SensorID)
+	}
+}
+
+func TestOrderService_ResolveDamageReview(t *testing.T) {
+	service, _, _ := newTestOrderService()
+
+	order, _ := service.CreateOrder("customer-1", "vaccine-1", 10, 100)
+	service.HandleOrderDamageEvent(damageEvent("unknown_sensor", "minor"))
+	reviews, _ := service.GetDamageReviews(string(domain.DamageReviewPending))
+
+	review, err := service.ResolveDamageReview(reviews[0].ID, order.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if review.Status != domain.DamageReviewResolved || review.ResolvedOrderID != order.ID {
+		t.Errorf("Expected review resolved to %s, got %s/%s", order.ID, review.Status, review.ResolvedOrderID)
+	}
+
+	updated, _ := service.GetOrder(order.ID)
+	if updated.Status != "damage_detected_minor" {
+		t.Errorf("Expected status damage_detected_minor, got %s", updated.Status)
+	}
+
+	if _, err := service.DiscardDamageReview(review.ID); err == nil {
+		t.Error("Expected error discarding an already resolved review")
+	}
+}
+
+func TestOrderService_ResolveDamageReview_ConcurrentResolvesApplyDamageOnce(t *testing.T) {
+	service, _, publisher := newTestOrderService()
+
+	order, _ := service.CreateOrder("customer-1", "vaccine-1", 10, 100)
+	service.HandleOrderDamageEvent(damageEvent("unknown_sensor", "major"))
+	reviews, _ := service.GetDamageReviews(string(domain.DamageReviewPending))
+
+	const resolvers = 8
+	errs := make(chan error, resolvers)
+	var wg sync.WaitGroup
+	for i := 0; i < resolvers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.ResolveDamageReview(reviews[0].ID, order.ID)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	resolved := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			resolved++
+		case !errors.Is(err, ErrDamageReviewClosed):
+			t.Errorf("Expected ErrDamageReviewClosed, got %v", err)
+		}
+	}
+	if resolved != 1 {
+		t.Errorf("Expected exactly 1 successful resolve, got %d", resolved)
+	}
+
+	damageEvents := 0
+	for _, event := range publisher.published() {
+		if event.EventType == "order.damage_processed" {
+			damageEvents++
+		}
+	}
+	if damageEvents != 1 {
+		t.Errorf("Expected damage to be applied once, got %d damage_processed events", damageEvents)
+	}
+}
+
+func TestOrderService_ResolveDamageReview_UnknownOrder(t *testing.T) {
+	service, _, _ := newTestOrderService()
+
+	service.HandleOrderDamageEvent(damageEvent("unknown_sensor", "minor"))
+	reviews, _ := service.GetDamageReviews(string(domain.DamageReviewPending))
+
+	if _, err := service.ResolveDamageReview(reviews[0].ID, "missing-order"); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("Expected ErrOrderNotFound, got %v", err)
+	}
+
+	review, _ := service.GetDamageReview(reviews[0].ID)
+	if review.Status != domain.DamageReviewPending {
+		t.Errorf("Expected review to stay pending, got %s", review.Status)
+	}
+}
+
+func TestSensorMappingService_AssignSensorToUnknownOrder(t *testing.T) {
+	_, sensorMappings, _ := newTestOrderService()
+
+	if _, err := sensorMappings.AssignSensor("sensor-1", "", "missing-order", "", ""); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("Expected ErrOrderNotFound, got %v", err)
+	}
+}
+
+func TestSensorMappingService_ReleasedSensorNoLongerResolves(t *testing.T) {
+	service, sensorMappings, _ := newTestOrderService()
+
+	order, _ := service.CreateOrder("customer-1", "vaccine-1", 10, 100)
+	sensorMappings.AssignSensor("sensor-1", "", order.ID, "", "")
+
+	if _, err := sensorMappings.AssignSensor("sensor-1", "", order.ID, "", ""); err == nil {
+		t.Error("Expected error assigning an already assigned sensor")
+	}
+
+	if _, err := sensorMappings.ReleaseSensor("sensor-1"); err != nil {
+		t.Fatalf("Failed to release sensor: %v", err)
+	}
+
+	if _, err := sensorMappings.Resolve(damageEvent("sensor-1", "minor")); err == nil {
+		t.Error("Expected released sensor not to resolve")
+	}
+}
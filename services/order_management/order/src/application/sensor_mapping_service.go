@@ -0,0 +1,100 @@
+package application
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/MATI-MBIT/arqnewgen-medisupply-eda/simple-service/oder/src/domain"
+)
+
+// SensorMappingService manages which sensor is attached to which shipment,
+// order and lote contract, and resolves damage events through those mappings
+type SensorMappingService struct {
+	assignmentRepo domain.SensorAssignmentRepository
+	orderRepo      domain.OrderRepository
+}
+
+// NewSensorMappingService creates a new SensorMappingService
+func NewSensorMappingService(assignmentRepo domain.SensorAssignmentRepository, orderRepo domain.OrderRepository) *SensorMappingService {
+	return &SensorMappingService{
+		assignmentRepo: assignmentRepo,
+		orderRepo:      orderRepo,
+	}
+}
+
+// AssignSensor registers a sensor as monitoring the shipment of an existing order
+func (s *SensorMappingService) AssignSensor(sensorID, shipmentID, orderID, loteID, loteContractAddress string) (*domain.SensorAssignment, error) {
+	if _, err := s.orderRepo.FindByID(orderID); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOrderNotFound, err)
+	}
+
+	assignment := domain.SensorAssignment{
+		SensorID:            sensorID,
+		ShipmentID:          shipmentID,
+		OrderID:             orderID,
+		LoteID:              loteID,
+		LoteContractAddress: loteContractAddress,
+		AssignedAt:          time.Now(),
+	}
+
+	if err := s.assignmentRepo.Save(assignment); err != nil {
+		return nil, fmt.Errorf("failed to save sensor assignment: %w", err)
+	}
+
+	log.Printf("Sensor assigned: SensorID=%s, ShipmentID=%s, OrderID=%s, LoteID=%s",
+		sensorID, shipmentID, orderID, loteID)
+
+	return &assignment, nil
+}
+
+// ReleaseSensor detaches a sensor from its current shipment
+func (s *SensorMappingService) ReleaseSensor(sensorID string) (*domain.SensorAssignment, error) {
+	assignment, err := s.assignmentRepo.FindActiveBySensorID(sensorID)
+	if err != nil {
+		return nil, err
+	}
+
+	releasedAt := time.Now()
+	assignment.ReleasedAt = &releasedAt
+
+	if err := s.assignmentRepo.Update(*assignment); err != nil {
+		return nil, fmt.Errorf("failed to release sensor: %w", err)
+	}
+
+	log.Printf("Sensor released: SensorID=%s, OrderID=%s", sensorID, assignment.OrderID)
+
+	return assignment, nil
+}
+
+// GetActiveAssignment retrieves the current assignment of a sensor
+func (s *SensorMappingService) GetActiveAssignment(sensorID string) (*domain.SensorAssignment, error) {
+	return s.assignmentRepo.FindActiveBySensorID(sensorID)
+}
+
+// GetAllAssignments retrieves all sensor assignments, including released ones
+func (s *SensorMappingService) GetAllAssignments() ([]domain.SensorAssignment, error) {
+	return s.assignmentRepo.FindAll()
+}
+
+// Resolve finds the active assignment matching a damage event. The shipment
+// takes precedence over the sensor; the order ID is tried as a sensor ID as well
+// because older publishers sent the sensor identifier in that field.
+func (s *SensorMappingService) Resolve(event domain.OrderDamageEvent) (*domain.SensorAssignment, error) {
+	if event.ShipmentID != "" {
+		if assignment, err := s.assignmentRepo.FindActiveByShipmentID(event.ShipmentID); err == nil {
+			return assignment, nil
+		}
+	}
+
+	for _, sensorID := range []string{event.SensorID, event.OrderID} {
+		if sensorID == "" {
+			continue
+		}
+		if assignment, err := s.assignmentRepo.FindActiveBySensorID(sensorID); err == nil {
+			return assignment, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no active assignment for sensor=%q shipment=%q", event.SensorID, event.ShipmentID)
+}
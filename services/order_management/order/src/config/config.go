@@ -3,12 +3,18 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MATI-MBIT/arqnewgen-medisupply-eda/simple-service/oder/src/domain"
 )
 
 // Config holds all configuration for the application
 type Config struct {
 	RabbitMQ RabbitMQConfig
 	HTTP     HTTPConfig
+	Damage   DamageConfig
 }
 
 // RabbitMQConfig holds RabbitMQ-specific configuration
@@ -21,6 +27,28 @@ type RabbitMQConfig struct {
 	// Publisher configuration (for publishing order events)
 	PublisherQueueName   string
 	PublisherRoutingKey  string
+	// Consumer resilience configuration
+	Consumer ConsumerConfig
+	// Publisher confirms and buffering configuration
+	Publisher PublisherConfig
+}
+
+// PublisherConfig holds the confirmation, buffering and reconnection settings of the publisher
+type PublisherConfig struct {
+	BufferSize        int
+	ConfirmTimeout    time.Duration
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+}
+
+// ConsumerConfig holds the delivery, retry and reconnection settings of the consumer
+type ConsumerConfig struct {
+	Prefetch          int
+	Concurrency       int
+	MaxRetries        int
+	RetryDelay        time.Duration
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
 }
 
 // HTTPConfig holds HTTP server configuration
@@ -28,6 +56,11 @@ type HTTPConfig struct {
 	Port string
 }
 
+// DamageConfig holds how damage severities translate into order statuses
+type DamageConfig struct {
+	SeverityPolicy domain.DamageSeverityPolicy
+}
+
 // LoadConfig loads configuration from environment variables
 func LoadConfig() *Config {
 	// Construct RabbitMQ URL from components if individual parts are provided
@@ -54,11 +87,48 @@ func LoadConfig() *Config {
 			// Publisher configuration (for publishing order events)
 			PublisherQueueName:   getEnv("RABBITMQ_PUBLISHER_QUEUE", "order-events-queue"),
 			PublisherRoutingKey:  getEnv("RABBITMQ_PUBLISHER_ROUTING_KEY", "order.events"),
+			// Consumer resilience configuration
+			Consumer: ConsumerConfig{
+				Prefetch:          getEnvInt("RABBITMQ_CONSUMER_PREFETCH", 10),
+				Concurrency:       getEnvInt("RABBITMQ_CONSUMER_CONCURRENCY", 1),
+				MaxRetries:        getEnvInt("RABBITMQ_CONSUMER_MAX_RETRIES", 3),
+				RetryDelay:        getEnvDuration("RABBITMQ_CONSUMER_RETRY_DELAY", 5*time.Second),
+				ReconnectDelay:    getEnvDuration("RABBITMQ_RECONNECT_DELAY", time.Second),
+				MaxReconnectDelay: getEnvDuration("RABBITMQ_MAX_RECONNECT_DELAY", 30*time.Second),
+			},
+			// Publisher confirms and buffering configuration
+			Publisher: PublisherConfig{
+				BufferSize:        getEnvInt("RABBITMQ_PUBLISHER_BUFFER_SIZE", 1000),
+				ConfirmTimeout:    getEnvDuration("RABBITMQ_PUBLISHER_CONFIRM_TIMEOUT", 5*time.Second),
+				ReconnectDelay:    getEnvDuration("RABBITMQ_RECONNECT_DELAY", time.Second),
+				MaxReconnectDelay: getEnvDuration("RABBITMQ_MAX_RECONNECT_DELAY", 30*time.Second),
+			},
 		},
 		HTTP: HTTPConfig{
 			Port: getEnv("HTTP_PORT", "8081"),
 		},
+		Damage: DamageConfig{
+			SeverityPolicy: loadSeverityPolicy(getEnv("DAMAGE_SEVERITY_STATUSES", "")),
+		},
+	}
+}
+
+// loadSeverityPolicy overrides the default severity to status mapping with
+// entries in the form "minor=damage_detected_minor,critical=cancelled_damage"
+func loadSeverityPolicy(value string) domain.DamageSeverityPolicy {
+	policy := domain.DefaultDamageSeverityPolicy()
+	for _, entry := range strings.Split(value, ",") {
+		severity, status, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || severity == "" || status == "" {
+			continue
+		}
+		if severity == "unknown" {
+			policy.UnknownStatus = status
+			continue
+		}
+		policy.Statuses[severity] = status
 	}
+	return policy
 }
 
 // getEnv returns environment variable value or default if not set
@@ -67,4 +137,26 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
+}
+
+// getEnvInt returns environment variable as a positive integer or default if not set or invalid
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed >= 0 {
+			return parsed
+		}
+		fmt.Printf("WARNING: Invalid value for %s: %s, using default %d\n", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+// getEnvDuration returns environment variable as a duration (e.g. "5s") or default if not set or invalid
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			return parsed
+		}
+		fmt.Printf("WARNING: Invalid value for %s: %s, using default %s\n", key, value, defaultValue)
+	}
+	return defaultValue
 }
\ No newline at end of file
@@ -0,0 +1,28 @@
+package domain
+
+// DamageSeverityPolicy maps the severity of a damage event to the status the
+// affected order is moved to
+type DamageSeverityPolicy struct {
+	Statuses      map[string]string
+	UnknownStatus string
+}
+
+// DefaultDamageSeverityPolicy returns the standard severity to status mapping
+func DefaultDamageSeverityPolicy() DamageSeverityPolicy {
+	return DamageSeverityPolicy{
+		Statuses: map[string]string{
+			"minor":    "damage_detected_minor",
+			"major":    "damage_detected_major",
+			"critical": "cancelled_damage",
+		},
+		UnknownStatus: "damage_detected_unknown",
+	}
+}
+
+// StatusFor returns the order status for a severity and whether the severity is known
+func (p DamageSeverityPolicy) StatusFor(severity string) (string, bool) {
+	if status, ok := p.Statuses[severity]; ok {
+		return status, true
+	}
+	return p.UnknownStatus, false
+}
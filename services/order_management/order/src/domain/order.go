@@ -30,7 +30,9 @@ type OrderDamageEvent struct {
 	Type        string                 `json:"type"`
 	Source      string                 `json:"source"`
 	OccurredAt  time.Time              `json:"occurredAt"`
-	OrderID     string                 `json:"orderId"`
+	OrderID     string                 `json:"orderId,omitempty"`
+	SensorID    string                 `json:"sensorId,omitempty"`
+	ShipmentID  string                 `json:"shipmentId,omitempty"`
 	Severity    string                 `json:"severity"`
 	Description string                 `json:"description"`
 	Details     OrderDamageDetails     `json:"details"`
@@ -38,10 +40,12 @@ type OrderDamageEvent struct {
 
 // OrderDamageDetails contains the sensor data that triggered the damage event
 type OrderDamageDetails struct {
-	Temperature float64 `json:"temperature"`
-	Humidity    int     `json:"humidity"`
-	Status      string  `json:"status"`
-	MqttTopic   string  `json:"mqttTopic"`
+	Temperature  float64 `json:"temperature"`
+	Humidity     int     `json:"humidity"`
+	Status       string  `json:"status"`
+	MqttTopic    string  `json:"mqttTopic"`
+	Rule         string  `json:"rule,omitempty"`
+	RulesVersion string  `json:"rulesVersion,omitempty"`
 }
 
 // MQTTOrderEvent represents the wrapper structure for MQTT events
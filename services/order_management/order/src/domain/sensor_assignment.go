@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+)
+
+// SensorAssignment links a physical sensor to the shipment, order and lote
+// contract it is monitoring. Damage events are correlated to real orders
+// through these assignments.
+type SensorAssignment struct {
+	SensorID            string     `json:"sensor_id"`
+	ShipmentID          string     `json:"shipment_id,omitempty"`
+	OrderID             string     `json:"order_id"`
+	LoteID              string     `json:"lote_id,omitempty"`
+	LoteContractAddress string     `json:"lote_contract_address,omitempty"`
+	AssignedAt          time.Time  `json:"assigned_at"`
+	ReleasedAt          *time.Time `json:"released_at,omitempty"`
+}
+
+// IsActive reports whether the sensor is still attached to the shipment
+func (a SensorAssignment) IsActive() bool {
+	return a.ReleasedAt == nil
+}
+
+// DamageReviewStatus represents the state of a parked damage event
+type DamageReviewStatus string
+
+const (
+	DamageReviewPending   DamageReviewStatus = "pending"
+	DamageReviewResolved  DamageReviewStatus = "resolved"
+	DamageReviewDiscarded DamageReviewStatus = "discarded"
+)
+
+// DamageReview is a damage event that could not be correlated to an order
+// and is waiting for an operator to resolve or discard it
+type DamageReview struct {
+	ID              string             `json:"id"`
+	Event           OrderDamageEvent   `json:"event"`
+	Reason          string             `json:"reason"`
+	Status          DamageReviewStatus `json:"status"`
+	ResolvedOrderID string             `json:"resolved_order_id,omitempty"`
+	ReceivedAt      time.Time          `json:"received_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+// SensorAssignmentRepository defines the contract for sensor assignment persistence
+type SensorAssignmentRepository interface {
+	Save(assignment SensorAssignment) error
+	FindActiveBySensorID(sensorID string) (*SensorAssignment, error)
+	FindActiveByShipmentID(shipmentID string) (*SensorAssignment, error)
+	FindAll() ([]SensorAssignment, error)
+	Update(assignment SensorAssignment) error
+}
+
+// DamageReviewRepository defines the contract for the damage review queue
+type DamageReviewRepository interface {
+	Save(review DamageReview) error
+	FindByID(id string) (*DamageReview, error)
+	FindByStatus(status DamageReviewStatus) ([]DamageReview, error)
+	FindAll() ([]DamageReview, error)
+	Update(review DamageReview) error
+}
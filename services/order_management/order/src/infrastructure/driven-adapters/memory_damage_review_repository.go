@@ -0,0 +1,94 @@
+package drivenadapters
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/MATI-MBIT/arqnewgen-medisupply-eda/simple-service/oder/src/domain"
+)
+
+// MemoryDamageReviewRepository is an in-memory implementation of the DamageReviewRepository
+type MemoryDamageReviewRepository struct {
+	reviews map[string]domain.DamageReview
+	mutex   sync.RWMutex
+}
+
+// NewMemoryDamageReviewRepository creates a new MemoryDamageReviewRepository
+func NewMemoryDamageReviewRepository() *MemoryDamageReviewRepository {
+	return &MemoryDamageReviewRepository{
+		reviews: make(map[string]domain.DamageReview),
+	}
+}
+
+// Save stores a damage review in memory
+func (r *MemoryDamageReviewRepository) Save(review domain.DamageReview) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.reviews[review.ID] = review
+	return nil
+}
+
+// FindByID retrieves a damage review by its ID
+func (r *MemoryDamageReviewRepository) FindByID(id string) (*domain.DamageReview, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	review, exists := r.reviews[id]
+	if !exists {
+		return nil, fmt.Errorf("damage review with ID %s not found", id)
+	}
+
+	return &review, nil
+}
+
+// FindByStatus retrieves all damage reviews with a specific status, oldest first
+func (r *MemoryDamageReviewRepository) FindByStatus(status domain.DamageReviewStatus) ([]domain.DamageReview, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	reviews := make([]domain.DamageReview, 0)
+	for _, review := range r.reviews {
+		if review.Status == status {
+			reviews = append(reviews, review)
+		}
+	}
+
+	sortReviews(reviews)
+	return reviews, nil
+}
+
+// FindAll retrieves all damage reviews, oldest first
+func (r *MemoryDamageReviewRepository) FindAll() ([]domain.DamageReview, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	reviews := make([]domain.DamageReview, 0, len(r.reviews))
+	for _, review := range r.reviews {
+		reviews = append(reviews, review)
+	}
+
+	sortReviews(reviews)
+	return reviews, nil
+}
+
+// Update updates an existing damage review
+func (r *MemoryDamageReviewRepository) Update(review domain.DamageReview) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.reviews[review.ID]; !exists {
+		return fmt.Errorf("damage review with ID %s not found", review.ID)
+	}
+
+	r.reviews[review.ID] = review
+	return nil
+}
+
+// sortReviews orders reviews by reception time
+func sortReviews(reviews []domain.DamageReview) {
+	sort.Slice(reviews, func(i, j int) bool {
+		return reviews[i].ReceivedAt.Before(reviews[j].ReceivedAt)
+	})
+}
@@ -0,0 +1,91 @@
+package drivenadapters
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/MATI-MBIT/arqnewgen-medisupply-eda/simple-service/oder/src/domain"
+)
+
+// MemorySensorAssignmentRepository is an in-memory implementation of the SensorAssignmentRepository.
+// Released assignments are kept as history; only one active assignment per sensor is allowed.
+type MemorySensorAssignmentRepository struct {
+	assignments []domain.SensorAssignment
+	mutex       sync.RWMutex
+}
+
+// NewMemorySensorAssignmentRepository creates a new MemorySensorAssignmentRepository
+func NewMemorySensorAssignmentRepository() *MemorySensorAssignmentRepository {
+	return &MemorySensorAssignmentRepository{
+		assignments: make([]domain.SensorAssignment, 0),
+	}
+}
+
+// Save stores a new sensor assignment
+func (r *MemorySensorAssignmentRepository) Save(assignment domain.SensorAssignment) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, existing := range r.assignments {
+		if existing.SensorID == assignment.SensorID && existing.IsActive() {
+			return fmt.Errorf("sensor %s is already assigned to order %s", assignment.SensorID, existing.OrderID)
+		}
+	}
+
+	r.assignments = append(r.assignments, assignment)
+	return nil
+}
+
+// FindActiveBySensorID retrieves the active assignment of a sensor
+func (r *MemorySensorAssignmentRepository) FindActiveBySensorID(sensorID string) (*domain.SensorAssignment, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, assignment := range r.assignments {
+		if assignment.SensorID == sensorID && assignment.IsActive() {
+			return &assignment, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no active assignment for sensor %s", sensorID)
+}
+
+// FindActiveByShipmentID retrieves the active assignment of a shipment
+func (r *MemorySensorAssignmentRepository) FindActiveByShipmentID(shipmentID string) (*domain.SensorAssignment, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, assignment := range r.assignments {
+		if assignment.ShipmentID == shipmentID && assignment.IsActive() {
+			return &assignment, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no active assignment for shipment %s", shipmentID)
+}
+
+// FindAll retrieves all assignments, including released ones
+func (r *MemorySensorAssignmentRepository) FindAll() ([]domain.SensorAssignment, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	assignments := make([]domain.SensorAssignment, len(r.assignments))
+	copy(assignments, r.assignments)
+
+	return assignments, nil
+}
+
+// Update replaces the active assignment of the same sensor
+func (r *MemorySensorAssignmentRepository) Update(assignment domain.SensorAssignment) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, existing := range r.assignments {
+		if existing.SensorID == assignment.SensorID && existing.IsActive() {
+			r.assignments[i] = assignment
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no active assignment for sensor %s", assignment.SensorID)
+}
@@ -1,151 +1,354 @@
-package drivenadapters
-
-import (
-	"context"
-	"encoding/json"
-	"log"
-
-	"github.com/MATI-MBIT/arqnewgen-medisupply-eda/simple-service/oder/src/domain"
-	amqp "github.com/rabbitmq/amqp091-go"
-)
-
-// RabbitMQPublisher handles message publication to RabbitMQ
-type RabbitMQPublisher struct {
-	conn         *amqp.Connection
-	channel      *amqp.Channel
-	exchangeName string
-	queueName    string
-	routingKey   string
-}
-
-// NewRabbitMQPublisher creates a new RabbitMQPublisher
-func NewRabbitMQPublisher(rabbitMQURL, exchangeName, queueName, routingKey string) (*RabbitMQPublisher, error) {
-	conn, err := amqp.Dial(rabbitMQURL)
-	if err != nil {
-		return nil, err
-	}
-
-	channel, err := conn.Channel()
-	if err != nil {
-		conn.Close()
-		return nil, err
-	}
-
-	// Declare the exchange
-	err = channel.ExchangeDeclare(
-		exchangeName, // name
-		"direct",     // type
-		true,         // durable
-		false,        // auto-deleted
-		false,        // internal
-		false,        // no-wait
-		nil,          // arguments
-	)
-	if err != nil {
-		channel.Close()
-		conn.Close()
-		return nil, err
-	}
-
-	// Declare the queue for order events
-	_, err = channel.QueueDeclare(
-		queueName, // name
-		true,      // durable
-		false,     // delete when unused
-		false,     // exclusive
-		false,     // no-wait
-		nil,       // arguments
-	)
-	if err != nil {
-		channel.Close()
-		conn.Close()
-		return nil, err
-	}
-
-	// Bind the queue to the exchange
-	err = channel.QueueBind(
-		queueName,    // queue name
-		routingKey,   // routing key
-		exchangeName, // exchange
-		false,
-		nil,
-	)
-	if err != nil {
-		channel.Close()
-		conn.Close()
-		return nil, err
-	}
-
-	log.Printf("RabbitMQ Publisher initialized - Exchange: %s, Queue: %s, RoutingKey: %s", 
-		exchangeName, queueName, routingKey)
-
-	return &RabbitMQPublisher{
-		conn:         conn,
-		channel:      channel,
-		exchangeName: exchangeName,
-		queueName:    queueName,
-		routingKey:   routingKey,
-	}, nil
-}
-
-// PublishOrderEvent publishes an order event to RabbitMQ
-func (p *RabbitMQPublisher) PublishOrderEvent(event domain.OrderEvent) error {
-	// Marshal the event to JSON
-	body, err := json.Marshal(event)
-	if err != nil {
-		return err
-	}
-
-	// Publish the message
-	err = p.channel.PublishWithContext(
-		context.Background(),
-		p.exchangeName, // exchange
-		p.routingKey,   // routing key
-		false,          // mandatory
-		false,          // immediate
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-		},
-	)
-
-	if err != nil {
-		log.Printf("Failed to publish order event: %v", err)
-		return err
-	}
-
-	log.Printf("Order event published successfully: Type=%s, OrderID=%s", event.EventType, event.OrderID)
-	return nil
-}
-
-// PublishMessage publishes a simple message to RabbitMQ (for demo purposes)
-func (p *RabbitMQPublisher) PublishMessage(ctx context.Context, message string) error {
-	err := p.channel.PublishWithContext(ctx,
-		p.exchangeName, // exchange
-		p.routingKey,   // routing key
-		false,          // mandatory
-		false,          // immediate
-		amqp.Publishing{
-			ContentType: "text/plain",
-			Body:        []byte(message),
-		})
-
-	if err != nil {
-		log.Printf("Failed to publish message: %v", err)
-		return err
-	}
-
-	log.Printf("Message published successfully: %s", message)
-	return nil
-}
-
-// Close closes the RabbitMQ connection and channel
-func (p *RabbitMQPublisher) Close() error {
-	if p.channel != nil {
-		p.channel.Close()
-	}
-	if p.conn != nil {
-		return p.conn.Close()
-	}
-	return nil
+package drivenadapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/MATI-MBIT/arqnewgen-medisupply-eda/simple-service/oder/src/domain"
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+var (
+	// ErrPublishBufferFull is returned when the broker is unavailable and the local buffer is full
+	ErrPublishBufferFull = errors.New("publish buffer full")
+	// ErrPublishBuffered is returned by publish when the message was not confirmed
+	// yet and waits in the local buffer to be retried
+	ErrPublishBuffered = errors.New("message buffered until the broker confirms it")
+	// ErrPublisherClosed is returned when publishing after Close
+	ErrPublisherClosed = errors.New("publisher closed")
+)
+
+// PublisherOptions configures confirmation, buffering and reconnection of the publisher
+type PublisherOptions struct {
+	// BufferSize is the maximum number of messages kept locally while they cannot be confirmed
+	BufferSize int
+	// ConfirmTimeout bounds how long a publish waits for the broker confirmation
+	ConfirmTimeout time.Duration
+	// ReconnectDelay and MaxReconnectDelay bound the reconnection backoff; buffered
+	// messages are also retried every ReconnectDelay while connected
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+}
+
+// bufferedMessage is a message waiting to be confirmed by the broker
+type bufferedMessage struct {
+	routingKey string
+	msg        amqp.Publishing
+}
+
+// RabbitMQPublisher handles message publication to RabbitMQ with publisher
+// confirms and mandatory routing. Messages that cannot be confirmed because the
+// broker nacks them or the connection is down are kept in a bounded local
+// buffer and flushed once the connection is recovered.
+type RabbitMQPublisher struct {
+	dial         sessionDialer
+	exchangeName string
+	queueName    string
+	routingKey   string
+	options      PublisherOptions
+
+	mutex   sync.Mutex
+	session publisherSession // nil while disconnected
+	buffer  []bufferedMessage
+	closed  bool
+	done    chan struct{}
+
+	flushMutex sync.Mutex
+}
+
+// NewRabbitMQPublisher creates a new RabbitMQPublisher and opens its first session
+func NewRabbitMQPublisher(rabbitMQURL, exchangeName, queueName, routingKey string, options PublisherOptions) (*RabbitMQPublisher, error) {
+	p := newRabbitMQPublisher(amqpSessionDialer(rabbitMQURL, exchangeName, queueName, routingKey),
+		exchangeName, queueName, routingKey, options)
+
+	if err := p.start(); err != nil {
+		return nil, err
+	}
+
+	log.Printf("RabbitMQ Publisher initialized - Exchange: %s, Queue: %s, RoutingKey: %s, Buffer: %d",
+		exchangeName, queueName, routingKey, p.options.BufferSize)
+
+	return p, nil
+}
+
+// newRabbitMQPublisher creates a publisher on top of any session dialer
+func newRabbitMQPublisher(dial sessionDialer, exchangeName, queueName, routingKey string, options PublisherOptions) *RabbitMQPublisher {
+	if options.BufferSize <= 0 {
+		options.BufferSize = 1000
+	}
+	if options.ConfirmTimeout <= 0 {
+		options.ConfirmTimeout = 5 * time.Second
+	}
+	if options.ReconnectDelay <= 0 {
+		options.ReconnectDelay = time.Second
+	}
+	if options.MaxReconnectDelay < options.ReconnectDelay {
+		options.MaxReconnectDelay = options.ReconnectDelay
+	}
+
+	return &RabbitMQPublisher{
+		dial:         dial,
+		exchangeName: exchangeName,
+		queueName:    queueName,
+		routingKey:   routingKey,
+		options:      options,
+		buffer:       make([]bufferedMessage, 0),
+		done:         make(chan struct{}),
+	}
+}
+
+// start opens the first session and begins supervising it
+func (p *RabbitMQPublisher) start() error {
+	session, err := p.dial()
+	if err != nil {
+		return err
+	}
+
+	p.mutex.Lock()
+	p.session = session
+	p.mutex.Unlock()
+
+	go p.supervise(session)
+	return nil
+}
+
+// supervise watches a session, retries buffered messages periodically and
+// reconnects with exponential backoff when the session closes
+func (p *RabbitMQPublisher) supervise(session publisherSession) {
+	ticker := time.NewTicker(p.options.ReconnectDelay)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.flush()
+			continue
+		case reason := <-session.Closed():
+			log.Printf("RabbitMQ publisher session closed: %v", reason)
+		}
+
+		p.mutex.Lock()
+		if p.session == session {
+			p.session = nil
+		}
+		p.mutex.Unlock()
+		session.Close()
+
+		session = p.reconnect()
+		if session == nil {
+			return
+		}
+	}
+}
+
+// reconnect dials until a session is open or the publisher is closed, then
+// flushes the buffer. It returns nil when the publisher was closed.
+func (p *RabbitMQPublisher) reconnect() publisherSession {
+	delay := p.options.ReconnectDelay
+	for {
+		select {
+		case <-p.done:
+			return nil
+		case <-time.After(delay):
+		}
+
+		session, err := p.dial()
+		if err != nil {
+			delay *= 2
+			if delay > p.options.MaxReconnectDelay {
+				delay = p.options.MaxReconnectDelay
+			}
+			log.Printf("Failed to reconnect RabbitMQ publisher, retrying in %s: %v", delay, err)
+			continue
+		}
+
+		p.mutex.Lock()
+		if p.closed {
+			p.mutex.Unlock()
+			session.Close()
+			return nil
+		}
+		p.session = session
+		pending := len(p.buffer)
+		p.mutex.Unlock()
+
+		log.Printf("RabbitMQ publisher reconnected, flushing %d buffered messages", pending)
+		p.flush()
+		return session
+	}
+}
+
+// PublishOrderEvent publishes an order event to RabbitMQ
+func (p *RabbitMQPublisher) PublishOrderEvent(event domain.OrderEvent) error {
+	// Marshal the event to JSON
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	err = p.publish(amqp.Publishing{
+		ContentType: "application/json",
+		Body:        body,
+	})
+	if errors.Is(err, ErrPublishBuffered) {
+		log.Printf("Order event buffered until the broker confirms it: Type=%s, OrderID=%s", event.EventType, event.OrderID)
+		return nil
+	}
+	if err != nil {
+		log.Printf("Failed to publish order event: %v", err)
+		return err
+	}
+
+	log.Printf("Order event published successfully: Type=%s, OrderID=%s", event.EventType, event.OrderID)
+	return nil
+}
+
+// PublishMessage publishes a simple message to RabbitMQ (for demo purposes)
+func (p *RabbitMQPublisher) PublishMessage(ctx context.Context, message string) error {
+	err := p.publish(amqp.Publishing{
+		ContentType: "text/plain",
+		Body:        []byte(message),
+	})
+	if errors.Is(err, ErrPublishBuffered) {
+		log.Printf("Message buffered until the broker confirms it: %s", message)
+		return nil
+	}
+	if err != nil {
+		log.Printf("Failed to publish message: %v", err)
+		return err
+	}
+
+	log.Printf("Message published successfully: %s", message)
+	return nil
+}
+
+// publish sends a message and waits for its confirmation. It returns nil only
+// once the broker confirmed the message; when it cannot be confirmed the
+// message is buffered and ErrPublishBuffered is returned, or
+// ErrPublishBufferFull when there is no room. Unroutable messages are not
+// buffered.
+func (p *RabbitMQPublisher) publish(msg amqp.Publishing) error {
+	msg.MessageId = uuid.New().String()
+	msg.DeliveryMode = amqp.Persistent
+	msg.Timestamp = time.Now()
+
+	p.mutex.Lock()
+	if p.closed {
+		p.mutex.Unlock()
+		return ErrPublisherClosed
+	}
+	session := p.session
+	// Keep ordering: while older messages are pending, new ones queue behind them
+	if session == nil || len(p.buffer) > 0 {
+		err := p.enqueueLocked(bufferedMessage{routingKey: p.routingKey, msg: msg})
+		p.mutex.Unlock()
+		if err != nil {
+			return err
+		}
+		if session != nil {
+			go p.flush()
+		}
+		return ErrPublishBuffered
+	}
+	p.mutex.Unlock()
+
+	err := p.send(session, p.routingKey, msg)
+	if err == nil || errors.Is(err, ErrPublishUnroutable) {
+		return err
+	}
+
+	log.Printf("Publish of message %s not confirmed, buffering for retry: %v", msg.MessageId, err)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err := p.enqueueLocked(bufferedMessage{routingKey: p.routingKey, msg: msg}); err != nil {
+		return err
+	}
+	return ErrPublishBuffered
+}
+
+// send publishes on a session bounded by the confirm timeout
+func (p *RabbitMQPublisher) send(session publisherSession, routingKey string, msg amqp.Publishing) error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.options.ConfirmTimeout)
+	defer cancel()
+
+	return session.Publish(ctx, p.exchangeName, routingKey, msg)
+}
+
+// enqueueLocked appends a message to the buffer; the caller must hold the mutex
+func (p *RabbitMQPublisher) enqueueLocked(message bufferedMessage) error {
+	if len(p.buffer) >= p.options.BufferSize {
+		return fmt.Errorf("%w (%d messages)", ErrPublishBufferFull, len(p.buffer))
+	}
+	p.buffer = append(p.buffer, message)
+	return nil
+}
+
+// flush sends buffered messages in order until one fails to be confirmed
+func (p *RabbitMQPublisher) flush() {
+	p.flushMutex.Lock()
+	defer p.flushMutex.Unlock()
+
+	for {
+		p.mutex.Lock()
+		if len(p.buffer) == 0 || p.session == nil {
+			p.mutex.Unlock()
+			return
+		}
+		session := p.session
+		next := p.buffer[0]
+		p.mutex.Unlock()
+
+		err := p.send(session, next.routingKey, next.msg)
+		if err != nil && !errors.Is(err, ErrPublishUnroutable) {
+			log.Printf("Flush of buffered message %s failed, will retry: %v", next.msg.MessageId, err)
+			return
+		}
+		if err != nil {
+			log.Printf("Dropping buffered message %s: %v", next.msg.MessageId, err)
+		}
+
+		p.mutex.Lock()
+		if len(p.buffer) > 0 && p.buffer[0].msg.MessageId == next.msg.MessageId {
+			p.buffer = p.buffer[1:]
+		}
+		p.mutex.Unlock()
+	}
+}
+
+// Buffered returns the number of messages waiting to be confirmed
+func (p *RabbitMQPublisher) Buffered() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return len(p.buffer)
+}
+
+// Close stops reconnection and closes the current session
+func (p *RabbitMQPublisher) Close() error {
+	p.mutex.Lock()
+	if p.closed {
+		p.mutex.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.done)
+	session := p.session
+	p.session = nil
+	pending := len(p.buffer)
+	p.mutex.Unlock()
+
+	if pending > 0 {
+		log.Printf("RabbitMQ publisher closing with %d unconfirmed buffered messages", pending)
+	}
+	if session != nil {
+		return session.Close()
+	}
+	return nil
 }
\ No newline at end of file
@@ -0,0 +1,249 @@
+package drivenadapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MATI-MBIT/arqnewgen-medisupply-eda/simple-service/oder/src/domain"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// fakeBroker is a local AMQP stand-in: it hands out sessions that record
+// confirmed messages and can nack, return or drop the connection on demand
+type fakeBroker struct {
+	mutex      sync.Mutex
+	confirmed  []amqp.Publishing
+	nackNext   int
+	unroutable map[string]bool
+	down       bool
+	dials      int
+	current    *fakeSession
+}
+
+type fakeSession struct {
+	broker *fakeBroker
+	closed chan *amqp.Error
+	once   sync.Once
+}
+
+func newFakeBroker() *fakeBroker {
+	return &fakeBroker{unroutable: make(map[string]bool)}
+}
+
+func (b *fakeBroker) dial() (publisherSession, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.dials++
+	if b.down {
+		return nil, errors.New("connection refused")
+	}
+	b.current = &fakeSession{broker: b, closed: make(chan *amqp.Error, 1)}
+	return b.current, nil
+}
+
+// dropConnection simulates the broker closing the connection and refusing new ones
+func (b *fakeBroker) dropConnection() {
+	b.mutex.Lock()
+	b.down = true
+	session := b.current
+	b.mutex.Unlock()
+
+	session.once.Do(func() {
+		session.closed <- &amqp.Error{Code: amqp.ConnectionForced, Reason: "broker shutdown"}
+		close(session.closed)
+	})
+}
+
+func (b *fakeBroker) restart() {
+	b.mutex.Lock()
+	b.down = false
+	b.mutex.Unlock()
+}
+
+func (b *fakeBroker) confirmedBodies() []string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	bodies := make([]string, len(b.confirmed))
+	for i, msg := range b.confirmed {
+		bodies[i] = string(msg.Body)
+	}
+	return bodies
+}
+
+func (s *fakeSession) Publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+	s.broker.mutex.Lock()
+	defer s.broker.mutex.Unlock()
+
+	select {
+	case <-s.closed:
+		return ErrSessionClosed
+	default:
+	}
+	if s.broker.unroutable[routingKey] {
+		return ErrPublishUnroutable
+	}
+	if s.broker.nackNext > 0 {
+		s.broker.nackNext--
+		return ErrPublishNacked
+	}
+	s.broker.confirmed = append(s.broker.confirmed, msg)
+	return nil
+}
+
+func (s *fakeSession) Closed() <-chan *amqp.Error { return s.closed }
+
+func (s *fakeSession) Close() error {
+	s.once.Do(func() { close(s.closed) })
+	return nil
+}
+
+func newTestPublisher(t *testing.T, broker *fakeBroker, bufferSize int) *RabbitMQPublisher {
+	t.Helper()
+	p := newRabbitMQPublisher(broker.dial, "events", "order-events-queue", "order.events", PublisherOptions{
+		BufferSize:        bufferSize,
+		ConfirmTimeout:    time.Second,
+		ReconnectDelay:    10 * time.Millisecond,
+		MaxReconnectDelay: 20 * time.Millisecond,
+	})
+	if err := p.start(); err != nil {
+		t.Fatalf("Failed to start publisher: %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRabbitMQPublisher_PublishConfirmed(t *testing.T) {
+	broker := newFakeBroker()
+	p := newTestPublisher(t, broker, 10)
+
+	event := domain.OrderEvent{EventType: "order.damage_processed", OrderID: "order-1"}
+	if err := p.PublishOrderEvent(event); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	bodies := broker.confirmedBodies()
+	if len(bodies) != 1 {
+		t.Fatalf("Expected 1 confirmed message, got %d", len(bodies))
+	}
+
+	var published domain.OrderEvent
+	json.Unmarshal([]byte(bodies[0]), &published)
+	if published.OrderID != "order-1" {
+		t.Errorf("Expected order-1, got %s", published.OrderID)
+	}
+	if broker.confirmed[0].MessageId == "" || broker.confirmed[0].DeliveryMode != amqp.Persistent {
+		t.Errorf("Expected persistent message with an ID, got %+v", broker.confirmed[0])
+	}
+}
+
+func TestRabbitMQPublisher_NackedMessageIsRetried(t *testing.T) {
+	broker := newFakeBroker()
+	broker.nackNext = 1
+	p := newTestPublisher(t, broker, 10)
+
+	if err := p.publish(amqp.Publishing{Body: []byte("first")}); !errors.Is(err, ErrPublishBuffered) {
+		t.Fatalf("Expected nacked message to be buffered, got %v", err)
+	}
+
+	waitFor(t, func() bool { return p.Buffered() == 0 })
+	if bodies := broker.confirmedBodies(); len(bodies) != 1 || bodies[0] != "first" {
+		t.Errorf("Expected nacked message to be confirmed on retry, got %v", bodies)
+	}
+}
+
+func TestRabbitMQPublisher_BuffersWhileDisconnectedAndFlushesInOrder(t *testing.T) {
+	broker := newFakeBroker()
+	p := newTestPublisher(t, broker, 10)
+
+	broker.dropConnection()
+	waitFor(t, func() bool {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		return p.session == nil
+	})
+
+	for _, message := range []string{"a", "b", "c"} {
+		if err := p.publish(amqp.Publishing{Body: []byte(message)}); !errors.Is(err, ErrPublishBuffered) {
+			t.Fatalf("Expected ErrPublishBuffered, got %v", err)
+		}
+	}
+	if p.Buffered() != 3 {
+		t.Fatalf("Expected 3 buffered messages, got %d", p.Buffered())
+	}
+
+	broker.restart()
+	waitFor(t, func() bool { return p.Buffered() == 0 })
+
+	bodies := broker.confirmedBodies()
+	if len(bodies) != 3 || bodies[0] != "a" || bodies[1] != "b" || bodies[2] != "c" {
+		t.Errorf("Expected a, b, c flushed in order, got %v", bodies)
+	}
+}
+
+func TestRabbitMQPublisher_BufferedOrderEventIsNotAFailure(t *testing.T) {
+	broker := newFakeBroker()
+	p := newTestPublisher(t, broker, 10)
+
+	broker.dropConnection()
+	waitFor(t, func() bool {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		return p.session == nil
+	})
+
+	event := domain.OrderEvent{EventType: "order.updated", OrderID: "order-1"}
+	if err := p.PublishOrderEvent(event); err != nil {
+		t.Fatalf("Expected buffered event to be accepted, got %v", err)
+	}
+	if p.Buffered() != 1 || len(broker.confirmedBodies()) != 0 {
+		t.Errorf("Expected the event buffered and not confirmed, got %d buffered", p.Buffered())
+	}
+}
+
+func TestRabbitMQPublisher_BufferIsBounded(t *testing.T) {
+	broker := newFakeBroker()
+	p := newTestPublisher(t, broker, 2)
+
+	broker.dropConnection()
+	waitFor(t, func() bool {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		return p.session == nil
+	})
+
+	p.PublishMessage(context.Background(), "a")
+	p.PublishMessage(context.Background(), "b")
+	if err := p.PublishMessage(context.Background(), "c"); !errors.Is(err, ErrPublishBufferFull) {
+		t.Errorf("Expected ErrPublishBufferFull, got %v", err)
+	}
+}
+
+func TestRabbitMQPublisher_UnroutableMessageIsReported(t *testing.T) {
+	broker := newFakeBroker()
+	broker.unroutable["order.events"] = true
+	p := newTestPublisher(t, broker, 10)
+
+	if err := p.PublishMessage(context.Background(), "lost"); !errors.Is(err, ErrPublishUnroutable) {
+		t.Errorf("Expected ErrPublishUnroutable, got %v", err)
+	}
+	if p.Buffered() != 0 {
+		t.Errorf("Expected unroutable message not to be buffered, got %d", p.Buffered())
+	}
+}
@@ -0,0 +1,192 @@
+package drivenadapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+var (
+	// ErrPublishNacked is returned when the broker negatively acknowledges a message
+	ErrPublishNacked = errors.New("message nacked by broker")
+	// ErrPublishUnroutable is returned when a mandatory message is returned by the broker
+	ErrPublishUnroutable = errors.New("message returned by broker: no queue bound for routing key")
+	// ErrSessionClosed is returned when publishing on a session whose channel is closed
+	ErrSessionClosed = errors.New("rabbitmq session closed")
+)
+
+// publisherSession is an open channel in confirm mode. Publish blocks until the
+// broker confirms the message, reporting nacks and returned messages as errors.
+type publisherSession interface {
+	Publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error
+	Closed() <-chan *amqp.Error
+	Close() error
+}
+
+// sessionDialer opens a new publisher session with its topology declared
+type sessionDialer func() (publisherSession, error)
+
+// amqpSession is the publisherSession backed by a real RabbitMQ connection
+type amqpSession struct {
+	conn    *amqp.Connection
+	channel *amqp.Channel
+	returns chan amqp.Return
+	closed  chan *amqp.Error
+
+	// publishing is serialized so that a returned message can be matched to the
+	// publish that is waiting for its confirmation
+	mutex sync.Mutex
+}
+
+// amqpSessionDialer returns a dialer that connects to RabbitMQ, declares the
+// exchange, queue and binding, and puts the channel in confirm mode
+func amqpSessionDialer(rabbitMQURL, exchangeName, queueName, routingKey string) sessionDialer {
+	return func() (publisherSession, error) {
+		conn, err := amqp.Dial(rabbitMQURL)
+		if err != nil {
+			return nil, err
+		}
+
+		channel, err := conn.Channel()
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		if err := declarePublisherTopology(channel, exchangeName, queueName, routingKey); err != nil {
+			channel.Close()
+			conn.Close()
+			return nil, err
+		}
+
+		if err := channel.Confirm(false); err != nil {
+			channel.Close()
+			conn.Close()
+			return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+		}
+
+		session := &amqpSession{
+			conn:    conn,
+			channel: channel,
+			returns: channel.NotifyReturn(make(chan amqp.Return, 16)),
+			closed:  make(chan *amqp.Error, 1),
+		}
+
+		// Forward the first connection or channel closure to a single channel
+		connClosed := conn.NotifyClose(make(chan *amqp.Error, 1))
+		channelClosed := channel.NotifyClose(make(chan *amqp.Error, 1))
+		go func() {
+			var reason *amqp.Error
+			select {
+			case reason = <-connClosed:
+			case reason = <-channelClosed:
+			}
+			session.closed <- reason
+			close(session.closed)
+		}()
+
+		return session, nil
+	}
+}
+
+// declarePublisherTopology declares the exchange and the queue for order events
+func declarePublisherTopology(channel *amqp.Channel, exchangeName, queueName, routingKey string) error {
+	// Declare the exchange
+	err := channel.ExchangeDeclare(
+		exchangeName, // name
+		"direct",     // type
+		true,         // durable
+		false,        // auto-deleted
+		false,        // internal
+		false,        // no-wait
+		nil,          // arguments
+	)
+	if err != nil {
+		return err
+	}
+
+	// Declare the queue for order events
+	_, err = channel.QueueDeclare(
+		queueName, // name
+		true,      // durable
+		false,     // delete when unused
+		false,     // exclusive
+		false,     // no-wait
+		nil,       // arguments
+	)
+	if err != nil {
+		return err
+	}
+
+	// Bind the queue to the exchange
+	return channel.QueueBind(
+		queueName,    // queue name
+		routingKey,   // routing key
+		exchangeName, // exchange
+		false,
+		nil,
+	)
+}
+
+// Publish sends a mandatory message and waits for its confirmation
+func (s *amqpSession) Publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	confirmation, err := s.channel.PublishWithDeferredConfirmWithContext(ctx,
+		exchange,   // exchange
+		routingKey, // routing key
+		true,       // mandatory
+		false,      // immediate
+		msg,
+	)
+	if err != nil {
+		if errors.Is(err, amqp.ErrClosed) {
+			return ErrSessionClosed
+		}
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The broker sends basic.return before the confirmation of an unroutable
+	// message, so any return for this message is already buffered
+	for drained := false; !drained; {
+		select {
+		case returned := <-s.returns:
+			if returned.MessageId == msg.MessageId {
+				log.Printf("Message %s returned by broker: %d %s", msg.MessageId, returned.ReplyCode, returned.ReplyText)
+				return ErrPublishUnroutable
+			}
+		default:
+			drained = true
+		}
+	}
+
+	if !acked {
+		return ErrPublishNacked
+	}
+	return nil
+}
+
+// Closed is notified when the connection or the channel closes
+func (s *amqpSession) Closed() <-chan *amqp.Error {
+	return s.closed
+}
+
+// Close closes the channel and the connection
+func (s *amqpSession) Close() error {
+	s.channel.Close()
+	err := s.conn.Close()
+	if err != nil && !errors.Is(err, amqp.ErrClosed) {
+		return err
+	}
+	return nil
+}
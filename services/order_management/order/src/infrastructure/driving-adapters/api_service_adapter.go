@@ -2,6 +2,7 @@ package drivingadapters
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
 	"time"
@@ -13,10 +14,11 @@ import (
 // ApiServiceAdapter is responsible for exposing the order management capabilities
 // over HTTP protocol through RESTful web service endpoints
 type ApiServiceAdapter struct {
-	server       *http.Server
-	router       *gin.Engine
-	port         string
-	orderService *application.OrderService
+	server               *http.Server
+	router               *gin.Engine
+	port                 string
+	orderService         *application.OrderService
+	sensorMappingService *application.SensorMappingService
 }
 
 // CreateOrderRequest represents the request payload for creating an order
@@ -32,8 +34,22 @@ type UpdateOrderStatusRequest struct {
 	Status string `json:"status" binding:"required"`
 }
 
+// AssignSensorRequest represents the request payload for attaching a sensor to a shipment
+type AssignSensorRequest struct {
+	SensorID            string `json:"sensor_id" binding:"required"`
+	ShipmentID          string `json:"shipment_id"`
+	OrderID             string `json:"order_id" binding:"required"`
+	LoteID              string `json:"lote_id"`
+	LoteContractAddress string `json:"lote_contract_address"`
+}
+
+// ResolveDamageReviewRequest represents the request payload for resolving a parked damage event
+type ResolveDamageReviewRequest struct {
+	OrderID string `json:"order_id" binding:"required"`
+}
+
 // NewApiServiceAdapter creates a new ApiServiceAdapter
-func NewApiServiceAdapter(port string, orderService *application.OrderService) *ApiServiceAdapter {
+func NewApiServiceAdapter(port string, orderService *application.OrderService, sensorMappingService *application.SensorMappingService) *ApiServiceAdapter {
 	// Set gin to release mode for production
 	gin.SetMode(gin.ReleaseMode)
 	
@@ -44,9 +60,10 @@ func NewApiServiceAdapter(port string, orderService *application.OrderService) *
 	router.Use(gin.Recovery())
 	
 	adapter := &ApiServiceAdapter{
-		router:       router,
-		port:         port,
-		orderService: orderService,
+		router:               router,
+		port:                 port,
+		orderService:         orderService,
+		sensorMappingService: sensorMappingService,
 	}
 	
 	// Setup routes
@@ -73,6 +90,18 @@ func (adapter *ApiServiceAdapter) setupRoutes() {
 		v1.GET("/orders", adapter.getAllOrdersHandler)
 		v1.GET("/orders/:id", adapter.getOrderHandler)
 		v1.PUT("/orders/:id/status", adapter.updateOrderStatusHandler)
+
+		// Sensor to shipment/order mapping
+		v1.POST("/sensor-assignments", adapter.assignSensorHandler)
+		v1.GET("/sensor-assignments", adapter.getAllSensorAssignmentsHandler)
+		v1.GET("/sensor-assignments/:sensorId", adapter.getSensorAssignmentHandler)
+		v1.DELETE("/sensor-assignments/:sensorId", adapter.releaseSensorHandler)
+
+		// Damage events that could not be correlated to an order
+		v1.GET("/damage-reviews", adapter.getDamageReviewsHandler)
+		v1.GET("/damage-reviews/:id", adapter.getDamageReviewHandler)
+		v1.POST("/damage-reviews/:id/resolve", adapter.resolveDamageReviewHandler)
+		v1.POST("/damage-reviews/:id/discard", adapter.discardDamageReviewHandler)
 	}
 }
 
@@ -151,6 +180,134 @@ func (adapter *ApiServiceAdapter) updateOrderStatusHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, order)
 }
 
+// assignSensorHandler handles requests to attach a sensor to an order shipment
+func (adapter *ApiServiceAdapter) assignSensorHandler(c *gin.Context) {
+	var req AssignSensorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignment, err := adapter.sensorMappingService.AssignSensor(req.SensorID, req.ShipmentID, req.OrderID, req.LoteID, req.LoteContractAddress)
+	if err != nil {
+		log.Printf("Error assigning sensor %s: %v", req.SensorID, err)
+		status := http.StatusConflict
+		if errors.Is(err, application.ErrOrderNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, assignment)
+}
+
+// getAllSensorAssignmentsHandler handles requests to list sensor assignments
+func (adapter *ApiServiceAdapter) getAllSensorAssignmentsHandler(c *gin.Context) {
+	assignments, err := adapter.sensorMappingService.GetAllAssignments()
+	if err != nil {
+		log.Printf("Error getting sensor assignments: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get sensor assignments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, assignments)
+}
+
+// getSensorAssignmentHandler handles requests to get the active assignment of a sensor
+func (adapter *ApiServiceAdapter) getSensorAssignmentHandler(c *gin.Context) {
+	sensorID := c.Param("sensorId")
+
+	assignment, err := adapter.sensorMappingService.GetActiveAssignment(sensorID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sensor assignment not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, assignment)
+}
+
+// releaseSensorHandler handles requests to detach a sensor from its shipment
+func (adapter *ApiServiceAdapter) releaseSensorHandler(c *gin.Context) {
+	sensorID := c.Param("sensorId")
+
+	assignment, err := adapter.sensorMappingService.ReleaseSensor(sensorID)
+	if err != nil {
+		log.Printf("Error releasing sensor %s: %v", sensorID, err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sensor assignment not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, assignment)
+}
+
+// getDamageReviewsHandler handles requests to list parked damage events
+func (adapter *ApiServiceAdapter) getDamageReviewsHandler(c *gin.Context) {
+	reviews, err := adapter.orderService.GetDamageReviews(c.Query("status"))
+	if err != nil {
+		log.Printf("Error getting damage reviews: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get damage reviews"})
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// getDamageReviewHandler handles requests to get a parked damage event
+func (adapter *ApiServiceAdapter) getDamageReviewHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	review, err := adapter.orderService.GetDamageReview(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Damage review not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// resolveDamageReviewHandler handles requests to apply a parked damage event to an order
+func (adapter *ApiServiceAdapter) resolveDamageReviewHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	var req ResolveDamageReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := adapter.orderService.ResolveDamageReview(id, req.OrderID)
+	if err != nil {
+		log.Printf("Error resolving damage review %s: %v", id, err)
+		c.JSON(damageReviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// discardDamageReviewHandler handles requests to discard a parked damage event
+func (adapter *ApiServiceAdapter) discardDamageReviewHandler(c *gin.Context) {
+	id := c.Param("id")
+
+	review, err := adapter.orderService.DiscardDamageReview(id)
+	if err != nil {
+		log.Printf("Error discarding damage review %s: %v", id, err)
+		c.JSON(damageReviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// damageReviewErrorStatus maps damage review errors to HTTP status codes
+func damageReviewErrorStatus(err error) int {
+	if errors.Is(err, application.ErrDamageReviewClosed) {
+		return http.StatusConflict
+	}
+	return http.StatusNotFound
+}
+
 // Start begins the HTTP server
 func (adapter *ApiServiceAdapter) Start(ctx context.Context) {
 	log.Printf("Starting HTTP API service adapter on port %s...", adapter.port)
@@ -0,0 +1,179 @@
+package drivingadapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+var (
+	// ErrRepublishNacked is returned when the broker negatively acknowledges a retried or dead-lettered message
+	ErrRepublishNacked = errors.New("republished message nacked by broker")
+	// ErrRepublishUnroutable is returned when the broker returns a retried or dead-lettered message
+	ErrRepublishUnroutable = errors.New("republished message returned by broker: no queue bound for routing key")
+)
+
+// consumerSession is an open channel with the consumer topology declared.
+// Deliveries are acknowledged through their own Acknowledger. Publish blocks
+// until the broker confirms the message, so a delivery is only acked once its
+// retry or dead-letter copy is safe.
+type consumerSession interface {
+	Consume(queue string) (<-chan amqp.Delivery, error)
+	Publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error
+	Closed() <-chan *amqp.Error
+	Close() error
+}
+
+// consumerDialer opens a new consumer session
+type consumerDialer func() (consumerSession, error)
+
+// amqpConsumerSession is the consumerSession backed by a real RabbitMQ
+// connection. Deliveries arrive on channel; retries and dead letters are
+// published on publishChannel, which is in confirm mode.
+type amqpConsumerSession struct {
+	conn           *amqp.Connection
+	channel        *amqp.Channel
+	publishChannel *amqp.Channel
+	returns        chan amqp.Return
+	closed         chan *amqp.Error
+
+	// publishing is serialized so that a returned message can be matched to the
+	// publish that is waiting for its confirmation
+	mutex sync.Mutex
+}
+
+// amqpConsumerDialer returns a dialer that connects to RabbitMQ and declares
+// the topology on the new channel
+func amqpConsumerDialer(rabbitMQURL string, declareTopology func(*amqp.Channel) error) consumerDialer {
+	return func() (consumerSession, error) {
+		conn, err := amqp.Dial(rabbitMQURL)
+		if err != nil {
+			return nil, err
+		}
+
+		channel, err := conn.Channel()
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		if err := declareTopology(channel); err != nil {
+			channel.Close()
+			conn.Close()
+			return nil, err
+		}
+
+		publishChannel, err := conn.Channel()
+		if err != nil {
+			channel.Close()
+			conn.Close()
+			return nil, err
+		}
+		if err := publishChannel.Confirm(false); err != nil {
+			publishChannel.Close()
+			channel.Close()
+			conn.Close()
+			return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+		}
+
+		session := &amqpConsumerSession{
+			conn:           conn,
+			channel:        channel,
+			publishChannel: publishChannel,
+			returns:        publishChannel.NotifyReturn(make(chan amqp.Return, 16)),
+			closed:         make(chan *amqp.Error, 1),
+		}
+
+		// Forward the first connection or channel closure to a single channel
+		connClosed := conn.NotifyClose(make(chan *amqp.Error, 1))
+		channelClosed := channel.NotifyClose(make(chan *amqp.Error, 1))
+		publishChannelClosed := publishChannel.NotifyClose(make(chan *amqp.Error, 1))
+		go func() {
+			var reason *amqp.Error
+			select {
+			case reason = <-connClosed:
+			case reason = <-channelClosed:
+			case reason = <-publishChannelClosed:
+			}
+			session.closed <- reason
+			close(session.closed)
+		}()
+
+		return session, nil
+	}
+}
+
+// Consume registers a manually acknowledged consumer on the queue
+func (s *amqpConsumerSession) Consume(queue string) (<-chan amqp.Delivery, error) {
+	return s.channel.Consume(
+		queue, // queue
+		"",    // consumer
+		false, // auto-ack is false, we will manually acknowledge
+		false, // exclusive
+		false, // no-local
+		false, // no-wait
+		nil,   // args
+	)
+}
+
+// Publish sends a mandatory message on the confirm channel and waits for its confirmation
+func (s *amqpConsumerSession) Publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	confirmation, err := s.publishChannel.PublishWithDeferredConfirmWithContext(ctx,
+		exchange,   // exchange
+		routingKey, // routing key
+		true,       // mandatory
+		false,      // immediate
+		msg,
+	)
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The broker sends basic.return before the confirmation of an unroutable
+	// message, so any return for this message is already buffered
+	for drained := false; !drained; {
+		select {
+		case returned := <-s.returns:
+			if returned.MessageId == msg.MessageId {
+				log.Printf("Republished message %s returned by broker: %d %s", msg.MessageId, returned.ReplyCode, returned.ReplyText)
+				return ErrRepublishUnroutable
+			}
+		default:
+			drained = true
+		}
+	}
+
+	if !acked {
+		return ErrRepublishNacked
+	}
+	return nil
+}
+
+// Closed is notified when the connection or the channel closes
+func (s *amqpConsumerSession) Closed() <-chan *amqp.Error {
+	return s.closed
+}
+
+// Close closes the channels and the connection; closing the consumer channel
+// ends the deliveries stream
+func (s *amqpConsumerSession) Close() error {
+	s.channel.Close()
+	s.publishChannel.Close()
+	err := s.conn.Close()
+	if err != nil && !errors.Is(err, amqp.ErrClosed) {
+		return err
+	}
+	return nil
+}
@@ -1,206 +1,473 @@
-package drivingadapters
-
-import (
-	"context"
-	"encoding/json"
-	"log"
-	"time"
-
-	"github.com/MATI-MBIT/arqnewgen-medisupply-eda/simple-service/oder/src/domain"
-	amqp "github.com/rabbitmq/amqp091-go"
-)
-
-// OrderConsumerAdapter is responsible for consuming order events from RabbitMQ
-// and translating them into domain events for the application layer
-type OrderConsumerAdapter struct {
-	conn         *amqp.Connection
-	channel      *amqp.Channel
-	queueName    string
-	exchangeName string
-	routingKey   string
-	eventHandler domain.OrderEventHandler
-}
-
-// NewOrderConsumerAdapter creates a new OrderConsumerAdapter
-func NewOrderConsumerAdapter(rabbitMQURL, exchangeName, queueName, routingKey string, eventHandler domain.OrderEventHandler) (*OrderConsumerAdapter, error) {
-	conn, err := amqp.Dial(rabbitMQURL)
-	if err != nil {
-		return nil, err
-	}
-
-	channel, err := conn.Channel()
-	if err != nil {
-		conn.Close()
-		return nil, err
-	}
-
-	// Declare the exchange
-	err = channel.ExchangeDeclare(
-		exchangeName, // name
-		"direct",     // type
-		true,         // durable
-		false,        // auto-deleted
-		false,        // internal
-		false,        // no-wait
-		nil,          // arguments
-	)
-	if err != nil {
-		channel.Close()
-		conn.Close()
-		return nil, err
-	}
-
-	// Declare the queue
-	_, err = channel.QueueDeclare(
-		queueName, // name
-		true,      // durable
-		false,     // delete when unused
-		false,     // exclusive
-		false,     // no-wait
-		nil,       // arguments
-	)
-	if err != nil {
-		channel.Close()
-		conn.Close()
-		return nil, err
-	}
-
-	// Bind the queue to the exchange
-	err = channel.QueueBind(
-		queueName,    // queue name
-		routingKey,   // routing key
-		exchangeName, // exchange
-		false,
-		nil,
-	)
-	if err != nil {
-		channel.Close()
-		conn.Close()
-		return nil, err
-	}
-
-	return &OrderConsumerAdapter{
-		conn:         conn,
-		channel:      channel,
-		queueName:    queueName,
-		exchangeName: exchangeName,
-		routingKey:   routingKey,
-		eventHandler: eventHandler,
-	}, nil
-}
-
-// Start begins consuming events from RabbitMQ
-func (adapter *OrderConsumerAdapter) Start(ctx context.Context) {
-	log.Println("Starting order consumer adapter...")
-
-	// Start consuming messages
-	msgs, err := adapter.channel.Consume(
-		adapter.queueName, // queue
-		"",                // consumer
-		false,             // auto-ack is false, we will manually acknowledge
-		false,             // exclusive
-		false,             // no-local
-		false,             // no-wait
-		nil,               // args
-	)
-	if err != nil {
-		log.Printf("Failed to register a consumer: %v", err)
-		return
-	}
-
-	for {
-		select {
-		case <-ctx.Done():
-			log.Println("Order consumer adapter stopping...")
-			adapter.Close()
-			return
-		case delivery, ok := <-msgs:
-			if !ok {
-				log.Println("Message channel closed")
-				return
-			}
-
-			// Translate RabbitMQ message to domain event
-			event, err := adapter.translateMessage(delivery.Body)
-			if err != nil {
-				log.Printf("Error translating message: %v", err)
-				delivery.Nack(false, false) // Reject and don't requeue
-				continue
-			}
-
-			// Handle the event through the application layer based on event type
-			var handlingErr error
-			switch e := event.(type) {
-			case domain.OrderDamageEvent:
-				handlingErr = adapter.eventHandler.HandleOrderDamageEvent(e)
-			case domain.OrderEvent:
-				handlingErr = adapter.eventHandler.HandleOrderEvent(e)
-			default:
-				log.Printf("Unknown event type received: %T", e)
-				delivery.Nack(false, false) // Reject unknown event types
-				continue
-			}
-
-			if handlingErr != nil {
-				log.Printf("Error handling event: %v", handlingErr)
-				delivery.Nack(false, true) // Reject and requeue for retry
-			} else {
-				delivery.Ack(false) // Acknowledge successful processing
-			}
-		}
-	}
-}
-
-// translateMessage converts a RabbitMQ message to a domain order event
-func (adapter *OrderConsumerAdapter) translateMessage(body []byte) (interface{}, error) {
-	// First try to unmarshal as MQTT order event (for order damage events)
-	var mqttEvent domain.MQTTOrderEvent
-	if err := json.Unmarshal(body, &mqttEvent); err == nil {
-		// Check if this is an order damage event
-		if mqttEvent.MqttTopic == "events/order-damage" {
-			return adapter.handleOrderDamageEvent(mqttEvent)
-		}
-	}
-
-	// Try to unmarshal as regular order event
-	var event domain.OrderEvent
-	if err := json.Unmarshal(body, &event); err == nil {
-		return event, nil
-	}
-
-	// If JSON unmarshaling fails, create a simple event from the message body
-	event = domain.OrderEvent{
-		EventType: "order.message",
-		OrderID:   "unknown",
-		Timestamp: time.Now(),
-	}
-
-	log.Printf("Received non-JSON message, created simple event: %s", string(body))
-	return event, nil
-}
-
-// handleOrderDamageEvent processes order damage events from MQTT
-func (adapter *OrderConsumerAdapter) handleOrderDamageEvent(mqttEvent domain.MQTTOrderEvent) (domain.OrderDamageEvent, error) {
-	var damageEvent domain.OrderDamageEvent
-	
-	// Parse the nested JSON payload
-	if err := json.Unmarshal([]byte(mqttEvent.Payload), &damageEvent); err != nil {
-		return damageEvent, err
-	}
-
-	log.Printf("Received order damage event: OrderID=%s, Severity=%s, Description=%s", 
-		damageEvent.OrderID, damageEvent.Severity, damageEvent.Description)
-	
-	return damageEvent, nil
-}
-
-// Close closes the RabbitMQ connection and channel
-func (adapter *OrderConsumerAdapter) Close() error {
-	if adapter.channel != nil {
-		adapter.channel.Close()
-	}
-	if adapter.conn != nil {
-		return adapter.conn.Close()
-	}
-	return nil
+package drivingadapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/MATI-MBIT/arqnewgen-medisupply-eda/simple-service/oder/src/domain"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// retryCountHeader carries how many times a message has been retried
+const retryCountHeader = "x-retry-count"
+
+// ConsumerOptions configures delivery, retry and reconnection behaviour of the consumer
+type ConsumerOptions struct {
+	// Prefetch is the maximum number of unacknowledged deliveries (QoS)
+	Prefetch int
+	// Concurrency is the number of workers handling deliveries
+	Concurrency int
+	// MaxRetries is how many times a failing message is retried before dead-lettering
+	MaxRetries int
+	// RetryDelay is the delay of the first retry; each further retry doubles it
+	RetryDelay time.Duration
+	// ReconnectDelay and MaxReconnectDelay bound the reconnection backoff
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+}
+
+// OrderConsumerAdapter is responsible for consuming order events from RabbitMQ
+// and translating them into domain events for the application layer.
+// It reconnects when the connection drops, retries failing messages through
+// delayed retry queues and dead-letters messages that exceed the retry limit.
+type OrderConsumerAdapter struct {
+	dial         consumerDialer
+	queueName    string
+	exchangeName string
+	routingKey   string
+	options      ConsumerOptions
+	eventHandler domain.OrderEventHandler
+
+	mutex   sync.Mutex
+	session consumerSession
+}
+
+// NewOrderConsumerAdapter creates a new OrderConsumerAdapter and performs the
+// initial connection so that misconfiguration is detected at startup
+func NewOrderConsumerAdapter(rabbitMQURL, exchangeName, queueName, routingKey string, options ConsumerOptions, eventHandler domain.OrderEventHandler) (*OrderConsumerAdapter, error) {
+	adapter := newOrderConsumerAdapter(nil, exchangeName, queueName, routingKey, options, eventHandler)
+	adapter.dial = amqpConsumerDialer(rabbitMQURL, adapter.declareTopology)
+
+	if err := adapter.connect(); err != nil {
+		return nil, err
+	}
+
+	return adapter, nil
+}
+
+// newOrderConsumerAdapter creates a consumer on top of any session dialer
+func newOrderConsumerAdapter(dial consumerDialer, exchangeName, queueName, routingKey string, options ConsumerOptions, eventHandler domain.OrderEventHandler) *OrderConsumerAdapter {
+	if options.Concurrency < 1 {
+		options.Concurrency = 1
+	}
+	if options.ReconnectDelay <= 0 {
+		options.ReconnectDelay = time.Second
+	}
+	if options.MaxReconnectDelay < options.ReconnectDelay {
+		options.MaxReconnectDelay = options.ReconnectDelay
+	}
+
+	return &OrderConsumerAdapter{
+		dial:         dial,
+		queueName:    queueName,
+		exchangeName: exchangeName,
+		routingKey:   routingKey,
+		options:      options,
+		eventHandler: eventHandler,
+	}
+}
+
+// Topology names derived from the consumer queue and exchange
+func (adapter *OrderConsumerAdapter) deadLetterExchange() string {
+	return adapter.exchangeName + ".dlx"
+}
+func (adapter *OrderConsumerAdapter) deadLetterQueue() string { return adapter.queueName + ".dlq" }
+func (adapter *OrderConsumerAdapter) retryQueue(attempt int) string {
+	return fmt.Sprintf("%s.retry.%d", adapter.queueName, attempt)
+}
+
+// retryDelay returns the delay before the given retry attempt (1-based)
+func (adapter *OrderConsumerAdapter) retryDelay(attempt int) time.Duration {
+	return adapter.options.RetryDelay * time.Duration(1<<(attempt-1))
+}
+
+// connect opens a new session with QoS applied and the full topology declared
+func (adapter *OrderConsumerAdapter) connect() error {
+	session, err := adapter.dial()
+	if err != nil {
+		return err
+	}
+
+	adapter.mutex.Lock()
+	adapter.session = session
+	adapter.mutex.Unlock()
+
+	log.Printf("Order consumer connected - Queue: %s, Prefetch: %d, Concurrency: %d, MaxRetries: %d",
+		adapter.queueName, adapter.options.Prefetch, adapter.options.Concurrency, adapter.options.MaxRetries)
+	return nil
+}
+
+// declareTopology declares the exchange, consumer queue, delayed retry queues
+// and the dead-letter exchange and queue on a fresh channel
+func (adapter *OrderConsumerAdapter) declareTopology(channel *amqp.Channel) error {
+	if adapter.options.Prefetch > 0 {
+		if err := channel.Qos(adapter.options.Prefetch, 0, false); err != nil {
+			return fmt.Errorf("failed to set QoS: %w", err)
+		}
+	}
+
+	// Declare the exchange
+	err := channel.ExchangeDeclare(
+		adapter.exchangeName, // name
+		"direct",             // type
+		true,                 // durable
+		false,                // auto-deleted
+		false,                // internal
+		false,                // no-wait
+		nil,                  // arguments
+	)
+	if err != nil {
+		return err
+	}
+
+	// Declare the queue
+	_, err = channel.QueueDeclare(
+		adapter.queueName, // name
+		true,              // durable
+		false,             // delete when unused
+		false,             // exclusive
+		false,             // no-wait
+		nil,               // arguments
+	)
+	if err != nil {
+		return err
+	}
+
+	// Bind the queue to the exchange
+	err = channel.QueueBind(
+		adapter.queueName,    // queue name
+		adapter.routingKey,   // routing key
+		adapter.exchangeName, // exchange
+		false,
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Delayed retry queues: messages wait for the TTL and are dead-lettered
+	// back to the consumer queue through the main exchange
+	for attempt := 1; attempt <= adapter.options.MaxRetries; attempt++ {
+		_, err = channel.QueueDeclare(
+			adapter.retryQueue(attempt), // name
+			true,                        // durable
+			false,                       // delete when unused
+			false,                       // exclusive
+			false,                       // no-wait
+			amqp.Table{
+				"x-message-ttl":             adapter.retryDelay(attempt).Milliseconds(),
+				"x-dead-letter-exchange":    adapter.exchangeName,
+				"x-dead-letter-routing-key": adapter.routingKey,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to declare retry queue: %w", err)
+		}
+	}
+
+	// Dead-letter exchange and queue for messages that exceed the retry limit
+	err = channel.ExchangeDeclare(
+		adapter.deadLetterExchange(), // name
+		"direct",                     // type
+		true,                         // durable
+		false,                        // auto-deleted
+		false,                        // internal
+		false,                        // no-wait
+		nil,                          // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare dead-letter exchange: %w", err)
+	}
+
+	_, err = channel.QueueDeclare(
+		adapter.deadLetterQueue(), // name
+		true,                      // durable
+		false,                     // delete when unused
+		false,                     // exclusive
+		false,                     // no-wait
+		nil,                       // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare dead-letter queue: %w", err)
+	}
+
+	return channel.QueueBind(
+		adapter.deadLetterQueue(),    // queue name
+		adapter.routingKey,           // routing key
+		adapter.deadLetterExchange(), // exchange
+		false,
+		nil,
+	)
+}
+
+// Start begins consuming events from RabbitMQ, reconnecting with exponential
+// backoff whenever the connection or channel is lost
+func (adapter *OrderConsumerAdapter) Start(ctx context.Context) {
+	log.Println("Starting order consumer adapter...")
+
+	delay := adapter.options.ReconnectDelay
+	for {
+		err := adapter.consume(ctx)
+		if ctx.Err() != nil {
+			log.Println("Order consumer adapter stopping...")
+			adapter.Close()
+			return
+		}
+		log.Printf("Order consumer interrupted: %v", err)
+
+		// Reconnect with backoff until the connection is restored or the context is cancelled
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("Order consumer adapter stopping...")
+				adapter.Close()
+				return
+			case <-time.After(delay):
+			}
+
+			adapter.Close()
+			if err := adapter.connect(); err != nil {
+				delay *= 2
+				if delay > adapter.options.MaxReconnectDelay {
+					delay = adapter.options.MaxReconnectDelay
+				}
+				log.Printf("Failed to reconnect to RabbitMQ, retrying in %s: %v", delay, err)
+				continue
+			}
+
+			log.Println("Order consumer reconnected to RabbitMQ")
+			delay = adapter.options.ReconnectDelay
+			break
+		}
+	}
+}
+
+// consume registers the consumer on the current session and dispatches
+// deliveries to the workers until the session closes or the context is cancelled
+func (adapter *OrderConsumerAdapter) consume(ctx context.Context) error {
+	adapter.mutex.Lock()
+	session := adapter.session
+	adapter.mutex.Unlock()
+
+	if session == nil {
+		return errors.New("no open session")
+	}
+
+	// Start consuming messages
+	msgs, err := session.Consume(adapter.queueName)
+	if err != nil {
+		return fmt.Errorf("failed to register a consumer: %w", err)
+	}
+
+	var workers sync.WaitGroup
+	for i := 0; i < adapter.options.Concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for delivery := range msgs {
+				adapter.processDelivery(session, delivery)
+			}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		// Closing the session ends the deliveries stream and stops the workers
+		session.Close()
+		workers.Wait()
+		return ctx.Err()
+	case amqpErr, ok := <-session.Closed():
+		workers.Wait()
+		if !ok || amqpErr == nil {
+			return errors.New("channel closed")
+		}
+		return amqpErr
+	}
+}
+
+// processDelivery translates and handles a single delivery, then acks it,
+// schedules a retry or dead-letters it
+func (adapter *OrderConsumerAdapter) processDelivery(session consumerSession, delivery amqp.Delivery) {
+	// Translate RabbitMQ message to domain event
+	event, err := adapter.translateMessage(delivery.Body)
+	if err != nil {
+		log.Printf("Error translating message: %v", err)
+		adapter.deadLetter(session, delivery, fmt.Sprintf("translation error: %v", err))
+		return
+	}
+
+	// Handle the event through the application layer based on event type
+	var handlingErr error
+	switch e := event.(type) {
+	case domain.OrderDamageEvent:
+		handlingErr = adapter.eventHandler.HandleOrderDamageEvent(e)
+	case domain.OrderEvent:
+		handlingErr = adapter.eventHandler.HandleOrderEvent(e)
+	default:
+		log.Printf("Unknown event type received: %T", e)
+		adapter.deadLetter(session, delivery, fmt.Sprintf("unknown event type %T", e))
+		return
+	}
+
+	if handlingErr != nil {
+		log.Printf("Error handling event: %v", handlingErr)
+		adapter.retry(session, delivery, handlingErr)
+		return
+	}
+
+	delivery.Ack(false) // Acknowledge successful processing
+}
+
+// retry republishes a failed message to the next delayed retry queue, or to
+// the dead-letter exchange once the retry limit is reached
+func (adapter *OrderConsumerAdapter) retry(session consumerSession, delivery amqp.Delivery, cause error) {
+	attempt := retryCount(delivery.Headers) + 1
+	if attempt > adapter.options.MaxRetries {
+		adapter.deadLetter(session, delivery, fmt.Sprintf("retry limit of %d exceeded: %v", adapter.options.MaxRetries, cause))
+		return
+	}
+
+	headers := copyHeaders(delivery.Headers)
+	headers[retryCountHeader] = int32(attempt)
+	headers["x-last-error"] = cause.Error()
+
+	// Publish through the default exchange straight into the retry queue
+	if err := adapter.republish(session, "", adapter.retryQueue(attempt), delivery, headers); err != nil {
+		log.Printf("Failed to schedule retry %d, requeueing: %v", attempt, err)
+		delivery.Nack(false, true)
+		return
+	}
+
+	log.Printf("Message scheduled for retry %d/%d in %s", attempt, adapter.options.MaxRetries, adapter.retryDelay(attempt))
+	delivery.Ack(false)
+}
+
+// deadLetter publishes a message to the dead-letter exchange with the failure reason
+func (adapter *OrderConsumerAdapter) deadLetter(session consumerSession, delivery amqp.Delivery, reason string) {
+	headers := copyHeaders(delivery.Headers)
+	headers["x-dead-letter-reason"] = reason
+	headers["x-original-routing-key"] = delivery.RoutingKey
+
+	if err := adapter.republish(session, adapter.deadLetterExchange(), adapter.routingKey, delivery, headers); err != nil {
+		log.Printf("Failed to dead-letter message, requeueing: %v", err)
+		delivery.Nack(false, true)
+		return
+	}
+
+	log.Printf("Message dead-lettered to %s: %s", adapter.deadLetterQueue(), reason)
+	delivery.Ack(false)
+}
+
+// republish sends a copy of a delivery with new headers and returns once the
+// broker confirmed it; only then may the original delivery be acked
+func (adapter *OrderConsumerAdapter) republish(session consumerSession, exchange, routingKey string, delivery amqp.Delivery, headers amqp.Table) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return session.Publish(ctx, exchange, routingKey, amqp.Publishing{
+		Headers:      headers,
+		ContentType:  delivery.ContentType,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    delivery.MessageId,
+		Timestamp:    delivery.Timestamp,
+		Body:         delivery.Body,
+	})
+}
+
+// retryCount reads the retry counter header, accepting the integer types AMQP may decode
+func retryCount(headers amqp.Table) int {
+	switch v := headers[retryCountHeader].(type) {
+	case int:
+		return v
+	case int8:
+		return int(v)
+	case int16:
+		return int(v)
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	default:
+		return 0
+	}
+}
+
+// copyHeaders returns a mutable copy of the delivery headers
+func copyHeaders(headers amqp.Table) amqp.Table {
+	copied := make(amqp.Table, len(headers)+2)
+	for k, v := range headers {
+		copied[k] = v
+	}
+	return copied
+}
+
+// translateMessage converts a RabbitMQ message to a domain order event
+func (adapter *OrderConsumerAdapter) translateMessage(body []byte) (interface{}, error) {
+	// First try to unmarshal as MQTT order event (for order damage events)
+	var mqttEvent domain.MQTTOrderEvent
+	if err := json.Unmarshal(body, &mqttEvent); err == nil {
+		// Check if this is an order damage event
+		if mqttEvent.MqttTopic == "events/order-damage" {
+			return adapter.handleOrderDamageEvent(mqttEvent)
+		}
+	}
+
+	// Try to unmarshal as regular order event
+	var event domain.OrderEvent
+	if err := json.Unmarshal(body, &event); err == nil {
+		return event, nil
+	}
+
+	// If JSON unmarshaling fails, create a simple event from the message body
+	event = domain.OrderEvent{
+		EventType: "order.message",
+		OrderID:   "unknown",
+		Timestamp: time.Now(),
+	}
+
+	log.Printf("Received non-JSON message, created simple event: %s", string(body))
+	return event, nil
+}
+
+// handleOrderDamageEvent processes order damage events from MQTT
+func (adapter *OrderConsumerAdapter) handleOrderDamageEvent(mqttEvent domain.MQTTOrderEvent) (domain.OrderDamageEvent, error) {
+	var damageEvent domain.OrderDamageEvent
+
+	// Parse the nested JSON payload
+	if err := json.Unmarshal([]byte(mqttEvent.Payload), &damageEvent); err != nil {
+		return damageEvent, err
+	}
+
+	log.Printf("Received order damage event: OrderID=%s, Severity=%s, Description=%s",
+		damageEvent.OrderID, damageEvent.Severity, damageEvent.Description)
+
+	return damageEvent, nil
+}
+
+// Close closes the RabbitMQ connection and channel
+func (adapter *OrderConsumerAdapter) Close() error {
+	adapter.mutex.Lock()
+	defer adapter.mutex.Unlock()
+
+	if adapter.session == nil {
+		return nil
+	}
+	err := adapter.session.Close()
+	adapter.session = nil
+	return err
 }
\ No newline at end of file
@@ -0,0 +1,393 @@
+package drivingadapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MATI-MBIT/arqnewgen-medisupply-eda/simple-service/oder/src/domain"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestRetryCount(t *testing.T) {
+	cases := []struct {
+		headers  amqp.Table
+		expected int
+	}{
+		{headers: nil, expected: 0},
+		{headers: amqp.Table{}, expected: 0},
+		{headers: amqp.Table{retryCountHeader: int32(2)}, expected: 2},
+		{headers: amqp.Table{retryCountHeader: int64(3)}, expected: 3},
+		{headers: amqp.Table{retryCountHeader: "bogus"}, expected: 0},
+	}
+
+	for _, tc := range cases {
+		if got := retryCount(tc.headers); got != tc.expected {
+			t.Errorf("Expected retry count %d for %v, got %d", tc.expected, tc.headers, got)
+		}
+	}
+}
+
+func TestRetryTopology(t *testing.T) {
+	adapter := &OrderConsumerAdapter{
+		exchangeName: "events",
+		queueName:    "order-damage-queue",
+		options:      ConsumerOptions{RetryDelay: 5 * time.Second, MaxRetries: 3},
+	}
+
+	if got := adapter.retryQueue(2); got != "order-damage-queue.retry.2" {
+		t.Errorf("Unexpected retry queue name: %s", got)
+	}
+	if got := adapter.deadLetterQueue(); got != "order-damage-queue.dlq" {
+		t.Errorf("Unexpected dead-letter queue name: %s", got)
+	}
+	if got := adapter.deadLetterExchange(); got != "events.dlx" {
+		t.Errorf("Unexpected dead-letter exchange name: %s", got)
+	}
+
+	expected := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second}
+	for i, delay := range expected {
+		if got := adapter.retryDelay(i + 1); got != delay {
+			t.Errorf("Expected retry %d delay %s, got %s", i+1, delay, got)
+		}
+	}
+}
+
+func TestCopyHeadersDoesNotMutateOriginal(t *testing.T) {
+	original := amqp.Table{retryCountHeader: int32(1)}
+	copied := copyHeaders(original)
+	copied[retryCountHeader] = int32(2)
+
+	if original[retryCountHeader] != int32(1) {
+		t.Errorf("Expected original headers to be untouched, got %v", original)
+	}
+}
+
+// fakeBroker is a local AMQP stand-in for the consumer: it delivers messages
+// on the current session, routes retries straight back to the consumer queue
+// (as if the retry TTL had expired) and records dead-lettered messages. The
+// next nackPublishes republished messages are nacked instead of confirmed.
+type fakeBroker struct {
+	mutex         sync.Mutex
+	queueName     string
+	deadLettered  []amqp.Publishing
+	nackPublishes int
+	acks          int
+	nacks         int
+	requeued      int
+	nextTag      uint64
+	down         bool
+	dials        int
+	current      *fakeSession
+}
+
+type fakeSession struct {
+	broker     *fakeBroker
+	deliveries chan amqp.Delivery
+	closed     chan *amqp.Error
+	done       bool
+}
+
+func newFakeBroker(queueName string) *fakeBroker {
+	return &fakeBroker{queueName: queueName}
+}
+
+func (b *fakeBroker) dial() (consumerSession, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.dials++
+	if b.down {
+		return nil, errors.New("connection refused")
+	}
+	b.current = &fakeSession{
+		broker:     b,
+		deliveries: make(chan amqp.Delivery, 16),
+		closed:     make(chan *amqp.Error, 1),
+	}
+	return b.current, nil
+}
+
+// deliver enqueues a message on the current session
+func (b *fakeBroker) deliver(body string, headers amqp.Table) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.deliverLocked(amqp.Publishing{Body: []byte(body), Headers: headers})
+}
+
+func (b *fakeBroker) deliverLocked(msg amqp.Publishing) {
+	if b.current == nil || b.current.done {
+		return
+	}
+	b.nextTag++
+	b.current.deliveries <- amqp.Delivery{
+		Acknowledger: b,
+		DeliveryTag:  b.nextTag,
+		Headers:      msg.Headers,
+		RoutingKey:   "order.damage",
+		Body:         msg.Body,
+	}
+}
+
+// dropConnection simulates the broker closing the connection and refusing new ones
+func (b *fakeBroker) dropConnection() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.down = true
+	b.current.shutdown(&amqp.Error{Code: amqp.ConnectionForced, Reason: "broker shutdown"})
+}
+
+// connected reports whether the consumer holds a live session
+func (b *fakeBroker) connected() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return !b.down && b.current != nil && !b.current.done
+}
+
+func (b *fakeBroker) restart() {
+	b.mutex.Lock()
+	b.down = false
+	b.mutex.Unlock()
+}
+
+func (b *fakeBroker) snapshot() (deadLettered []amqp.Publishing, acks, nacks, dials int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return append([]amqp.Publishing(nil), b.deadLettered...), b.acks, b.nacks, b.dials
+}
+
+func (b *fakeBroker) Ack(tag uint64, multiple bool) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.acks++
+	return nil
+}
+
+func (b *fakeBroker) Nack(tag uint64, multiple, requeue bool) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.nacks++
+	if requeue {
+		b.requeued++
+	}
+	return nil
+}
+
+func (b *fakeBroker) requeuedCount() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.requeued
+}
+
+func (b *fakeBroker) Reject(tag uint64, requeue bool) error {
+	return b.Nack(tag, false, requeue)
+}
+
+// shutdown ends the deliveries stream and reports the closure; the caller holds the broker mutex
+func (s *fakeSession) shutdown(reason *amqp.Error) {
+	if s.done {
+		return
+	}
+	s.done = true
+	close(s.deliveries)
+	if reason != nil {
+		s.closed <- reason
+	}
+	close(s.closed)
+}
+
+func (s *fakeSession) Consume(queue string) (<-chan amqp.Delivery, error) {
+	return s.deliveries, nil
+}
+
+func (s *fakeSession) Publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+	s.broker.mutex.Lock()
+	defer s.broker.mutex.Unlock()
+
+	if s.done {
+		return amqp.ErrClosed
+	}
+	if s.broker.nackPublishes > 0 {
+		s.broker.nackPublishes--
+		return ErrRepublishNacked
+	}
+	switch {
+	case exchange == "" && strings.HasPrefix(routingKey, s.broker.queueName+".retry."):
+		s.broker.deliverLocked(msg)
+	case strings.HasSuffix(exchange, ".dlx"):
+		s.broker.deadLettered = append(s.broker.deadLettered, msg)
+	default:
+		return fmt.Errorf("unexpected publish to %q/%q", exchange, routingKey)
+	}
+	return nil
+}
+
+func (s *fakeSession) Closed() <-chan *amqp.Error { return s.closed }
+
+func (s *fakeSession) Close() error {
+	s.broker.mutex.Lock()
+	defer s.broker.mutex.Unlock()
+	s.shutdown(nil)
+	return nil
+}
+
+// recordingHandler fails the first failures events and records every call
+type recordingHandler struct {
+	mutex    sync.Mutex
+	failures int
+	calls    []domain.OrderEvent
+}
+
+func (h *recordingHandler) HandleOrderEvent(event domain.OrderEvent) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.calls = append(h.calls, event)
+	if h.failures > 0 {
+		h.failures--
+		return errors.New("database unavailable")
+	}
+	return nil
+}
+
+func (h *recordingHandler) HandleOrderDamageEvent(event domain.OrderDamageEvent) error {
+	return nil
+}
+
+func (h *recordingHandler) handled() []domain.OrderEvent {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return append([]domain.OrderEvent(nil), h.calls...)
+}
+
+func startTestConsumer(t *testing.T, broker *fakeBroker, handler domain.OrderEventHandler, maxRetries int) {
+	t.Helper()
+	adapter := newOrderConsumerAdapter(broker.dial, "events", broker.queueName, "order.damage", ConsumerOptions{
+		Concurrency:       1,
+		MaxRetries:        maxRetries,
+		RetryDelay:        time.Millisecond,
+		ReconnectDelay:    10 * time.Millisecond,
+		MaxReconnectDelay: 20 * time.Millisecond,
+	}, handler)
+	if err := adapter.connect(); err != nil {
+		t.Fatalf("Failed to connect consumer: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		adapter.Start(ctx)
+		close(stopped)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-stopped
+	})
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestOrderConsumer_FailingMessageIsRetriedThenDeadLettered(t *testing.T) {
+	broker := newFakeBroker("order-damage-queue")
+	handler := &recordingHandler{failures: 100}
+	startTestConsumer(t, broker, handler, 3)
+
+	broker.deliver(`{"event_type":"order.created","order_id":"order-1"}`, nil)
+
+	waitFor(t, func() bool {
+		deadLettered, _, _, _ := broker.snapshot()
+		return len(deadLettered) == 1
+	})
+
+	// The first attempt plus three retries reach the handler
+	if calls := handler.handled(); len(calls) != 4 {
+		t.Errorf("Expected 4 handling attempts, got %d", len(calls))
+	}
+
+	deadLettered, acks, nacks, _ := broker.snapshot()
+	headers := deadLettered[0].Headers
+	if retryCount(headers) != 3 {
+		t.Errorf("Expected dead-lettered message to carry 3 retries, got %v", headers[retryCountHeader])
+	}
+	if reason, _ := headers["x-dead-letter-reason"].(string); !strings.Contains(reason, "retry limit of 3 exceeded") {
+		t.Errorf("Unexpected dead-letter reason: %q", reason)
+	}
+	if headers["x-original-routing-key"] != "order.damage" {
+		t.Errorf("Expected original routing key header, got %v", headers["x-original-routing-key"])
+	}
+	if acks != 4 || nacks != 0 {
+		t.Errorf("Expected every delivery to be acked once it was moved, got %d acks and %d nacks", acks, nacks)
+	}
+}
+
+func TestOrderConsumer_MessageSucceedsOnRetry(t *testing.T) {
+	broker := newFakeBroker("order-damage-queue")
+	handler := &recordingHandler{failures: 1}
+	startTestConsumer(t, broker, handler, 3)
+
+	broker.deliver(`{"event_type":"order.created","order_id":"order-1"}`, nil)
+
+	waitFor(t, func() bool { return len(handler.handled()) == 2 })
+	waitFor(t, func() bool {
+		_, acks, _, _ := broker.snapshot()
+		return acks == 2
+	})
+	if deadLettered, _, _, _ := broker.snapshot(); len(deadLettered) != 0 {
+		t.Errorf("Expected nothing dead-lettered, got %d", len(deadLettered))
+	}
+}
+
+func TestOrderConsumer_ResumesConsumingAfterConnectionDrop(t *testing.T) {
+	broker := newFakeBroker("order-damage-queue")
+	handler := &recordingHandler{}
+	startTestConsumer(t, broker, handler, 3)
+
+	broker.deliver(`{"event_type":"order.created","order_id":"before"}`, nil)
+	waitFor(t, func() bool { return len(handler.handled()) == 1 })
+
+	broker.dropConnection()
+	// Let a few reconnection attempts fail while the broker is down
+	waitFor(t, func() bool {
+		_, _, _, dials := broker.snapshot()
+		return dials >= 3
+	})
+	broker.restart()
+
+	waitFor(t, broker.connected)
+	broker.deliver(`{"event_type":"order.created","order_id":"after"}`, nil)
+
+	waitFor(t, func() bool { return len(handler.handled()) == 2 })
+	if calls := handler.handled(); calls[1].OrderID != "after" {
+		t.Errorf("Expected the message after reconnecting to be handled, got %s", calls[1].OrderID)
+	}
+}
+
+func TestOrderConsumer_UnconfirmedRepublishKeepsTheOriginal(t *testing.T) {
+	broker := newFakeBroker("order-damage-queue")
+	broker.nackPublishes = 2
+	handler := &recordingHandler{failures: 100}
+	startTestConsumer(t, broker, handler, 1)
+
+	// Neither a nacked dead letter nor a nacked retry may ack the original
+	broker.deliver(`{"event_type":"order.created","order_id":"order-1"}`, amqp.Table{retryCountHeader: int32(1)})
+	broker.deliver(`{"event_type":"order.created","order_id":"order-2"}`, nil)
+
+	waitFor(t, func() bool { return broker.requeuedCount() == 2 })
+	deadLettered, acks, _, _ := broker.snapshot()
+	if acks != 0 || len(deadLettered) != 0 {
+		t.Errorf("Expected both deliveries requeued and none acked, got %d acks and %d dead-lettered", acks, len(deadLettered))
+	}
+}
@@ -36,6 +36,9 @@ func main() {
 	// Initialize driven adapters (infrastructure)
 	// Order repository for data persistence
 	orderRepo := drivenadapters.NewMemoryOrderRepository()
+	// Sensor assignments and damage review queue for damage event correlation
+	sensorAssignmentRepo := drivenadapters.NewMemorySensorAssignmentRepository()
+	damageReviewRepo := drivenadapters.NewMemoryDamageReviewRepository()
 	
 	// RabbitMQ publisher for event publishing
 	eventPublisher, err := drivenadapters.NewRabbitMQPublisher(
@@ -43,6 +46,12 @@ func main() {
 		cfg.RabbitMQ.ExchangeName,
 		cfg.RabbitMQ.PublisherQueueName,
 		cfg.RabbitMQ.PublisherRoutingKey,
+		drivenadapters.PublisherOptions{
+			BufferSize:        cfg.RabbitMQ.Publisher.BufferSize,
+			ConfirmTimeout:    cfg.RabbitMQ.Publisher.ConfirmTimeout,
+			ReconnectDelay:    cfg.RabbitMQ.Publisher.ReconnectDelay,
+			MaxReconnectDelay: cfg.RabbitMQ.Publisher.MaxReconnectDelay,
+		},
 	)
 	if err != nil {
 		log.Fatalf("Failed to create RabbitMQ publisher: %v", err)
@@ -50,7 +59,8 @@ func main() {
 	defer eventPublisher.Close()
 
 	// Initialize application layer (business logic)
-	orderService := application.NewOrderService(orderRepo, eventPublisher)
+	sensorMappingService := application.NewSensorMappingService(sensorAssignmentRepo, orderRepo)
+	orderService := application.NewOrderService(orderRepo, eventPublisher, sensorMappingService, damageReviewRepo, cfg.Damage.SeverityPolicy)
 
 	// Initialize driving adapters
 	// Order consumer adapter for async event processing
@@ -59,6 +69,14 @@ func main() {
 		cfg.RabbitMQ.ExchangeName,
 		cfg.RabbitMQ.ConsumerQueueName,
 		cfg.RabbitMQ.ConsumerRoutingKey,
+		drivingadapters.ConsumerOptions{
+			Prefetch:          cfg.RabbitMQ.Consumer.Prefetch,
+			Concurrency:       cfg.RabbitMQ.Consumer.Concurrency,
+			MaxRetries:        cfg.RabbitMQ.Consumer.MaxRetries,
+			RetryDelay:        cfg.RabbitMQ.Consumer.RetryDelay,
+			ReconnectDelay:    cfg.RabbitMQ.Consumer.ReconnectDelay,
+			MaxReconnectDelay: cfg.RabbitMQ.Consumer.MaxReconnectDelay,
+		},
 		orderService,
 	)
 	if err != nil {
@@ -67,7 +85,7 @@ func main() {
 	defer orderConsumerAdapter.Close()
 	
 	// API service adapter for synchronous HTTP requests
-	apiServiceAdapter := drivingadapters.NewApiServiceAdapter(cfg.HTTP.Port, orderService)
+	apiServiceAdapter := drivingadapters.NewApiServiceAdapter(cfg.HTTP.Port, orderService, sensorMappingService)
 
 	// Start the order consumer adapter in a goroutine
 	go orderConsumerAdapter.Start(ctx)
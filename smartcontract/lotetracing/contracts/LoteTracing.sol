@@ -8,6 +8,29 @@ pragma solidity ^0.8.28;
  * centrada en la integridad de la cadena de frío y la transferencia de custodia.
  */
 contract LoteTracing {
+    //==============================================================
+    // ROLES
+    //==============================================================
+
+    bytes32 public constant ROL_FABRICANTE = keccak256("FABRICANTE");
+    bytes32 public constant ROL_DISTRIBUIDOR = keccak256("DISTRIBUIDOR");
+    bytes32 public constant ROL_FARMACIA = keccak256("FARMACIA");
+    bytes32 public constant ROL_ORACULO = keccak256("ORACULO_SENSOR");
+    bytes32 public constant ROL_AUDITOR = keccak256("AUDITOR");
+
+    //==============================================================
+    // FIRMAS EIP-712
+    //==============================================================
+
+    bytes32 public constant ACEPTACION_CUSTODIA_TYPEHASH =
+        keccak256("AceptacionCustodia(uint32 propuesta,address receptor,bytes32 envio)");
+    bytes32 private constant DOMINIO_TYPEHASH =
+        keccak256("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)");
+    uint64 public constant PLAZO_MAXIMO_CUSTODIA = 30 days;
+    // Mitad del orden de secp256k1; ecrecover acepta las dos s de cada firma
+    uint256 private constant MITAD_ORDEN_SECP256K1 =
+        0x7FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF5D576E7357A4501DDFE92F46681B20A0;
+
     //==============================================================
     // VARIABLES DE ESTADO
     //==============================================================
@@ -24,6 +47,19 @@ contract LoteTracing {
     address public propietarioActual;
     bool public comprometido; // Simplificación: true si la cadena de frío se rompió
 
+    // --- Control de Acceso ---
+    address public admin;
+    mapping(bytes32 => mapping(address => bool)) private roles;
+
+    // --- Lecturas ancladas por el oráculo (raíz Merkle => timestamp del anclaje) ---
+    mapping(bytes32 => uint256) public anclajes;
+
+    // --- Traspaso de custodia en dos pasos ---
+    address public custodioPropuesto; // Destinatario de la propuesta pendiente, o cero
+    uint64 public propuestaExpira; // Timestamp a partir del cual la propuesta ya no se acepta
+    uint32 public propuestaCustodia; // Número de la última propuesta; las firmas lo incluyen
+    bytes32 public envioPropuesto; // keccak256 de los detalles del envío propuesto
+
     //==============================================================
     // EVENTOS (El historial inmutable)
     //==============================================================
@@ -41,6 +77,35 @@ contract LoteTracing {
         bool comprometido,
         string motivo
     );
+    event TemperaturaRegistrada(
+        address indexed oraculo,
+        string sensorId,
+        int8 tempMin,
+        int8 tempMax,
+        bool enRango,
+        uint256 timestamp
+    );
+    event LecturasAncladas(
+        address indexed oraculo,
+        bytes32 indexed raizMerkle,
+        int8 tempMin,
+        int8 tempMax,
+        uint32 totalLecturas,
+        uint64 desde,
+        uint64 hasta,
+        bool enRango
+    );
+    event CustodiaPropuesta(
+        uint32 indexed propuesta,
+        address indexed propietario,
+        address indexed destinatario,
+        uint64 expira,
+        bytes32 envio,
+        string detallesEnvio
+    );
+    event CustodiaAceptada(uint32 indexed propuesta, address indexed destinatario, bool firmada);
+    event CustodiaRechazada(uint32 indexed propuesta, address indexed destinatario, string motivo);
+    event CustodiaCancelada(uint32 indexed propuesta, address indexed propietario);
     event LoteComprometido(
         address indexed propietario,
         int8 tempMin,
@@ -48,9 +113,12 @@ contract LoteTracing {
         bool comprometido,
         string motivo
     );
+    event RolOtorgado(bytes32 indexed rol, address indexed cuenta, address indexed admin);
+    event RolRevocado(bytes32 indexed rol, address indexed cuenta, address indexed admin);
+    event AdminTransferido(address indexed adminAnterior, address indexed nuevoAdmin);
 
     //==============================================================
-    // MODIFICADOR DE ACCESO
+    // MODIFICADORES DE ACCESO
     //==============================================================
 
     modifier soloPropietario() {
@@ -61,6 +129,16 @@ contract LoteTracing {
         _;
     }
 
+    modifier soloAdmin() {
+        require(msg.sender == admin, "Accion solo permitida para el administrador");
+        _;
+    }
+
+    modifier soloRol(bytes32 _rol) {
+        require(roles[_rol][msg.sender], "Cuenta sin el rol requerido");
+        _;
+    }
+
     //==============================================================
     // CONSTRUCTOR
     //==============================================================
@@ -75,23 +153,124 @@ contract LoteTracing {
         tempRegMinima = 0;
         tempRegMaxima = 0;
 
+        // El fabricante administra los roles del lote
+        admin = msg.sender;
+        emit AdminTransferido(address(0), msg.sender);
+        _otorgarRol(ROL_FABRICANTE, msg.sender);
+
         emit LoteCreado(_loteId, fabricante, _tempMin, _tempMax, "Lote Creado");
     }
 
+    //==============================================================
+    // ADMINISTRACIÓN DE ROLES
+    //==============================================================
+
+    function otorgarRol(bytes32 _rol, address _cuenta) external soloAdmin {
+        require(_cuenta != address(0), "Direccion invalida");
+        require(
+            _rol == ROL_FABRICANTE ||
+                _rol == ROL_DISTRIBUIDOR ||
+                _rol == ROL_FARMACIA ||
+                _rol == ROL_ORACULO ||
+                _rol == ROL_AUDITOR,
+            "Rol invalido"
+        );
+        _otorgarRol(_rol, _cuenta);
+    }
+
+    function revocarRol(bytes32 _rol, address _cuenta) external soloAdmin {
+        if (roles[_rol][_cuenta]) {
+            roles[_rol][_cuenta] = false;
+            emit RolRevocado(_rol, _cuenta, msg.sender);
+        }
+    }
+
+    function transferirAdmin(address _nuevoAdmin) external soloAdmin {
+        require(_nuevoAdmin != address(0), "Direccion invalida");
+        emit AdminTransferido(admin, _nuevoAdmin);
+        admin = _nuevoAdmin;
+    }
+
+    function tieneRol(bytes32 _rol, address _cuenta) external view returns (bool) {
+        return roles[_rol][_cuenta];
+    }
+
+    function _otorgarRol(bytes32 _rol, address _cuenta) private {
+        if (!roles[_rol][_cuenta]) {
+            roles[_rol][_cuenta] = true;
+            emit RolOtorgado(_rol, _cuenta, msg.sender);
+        }
+    }
+
     //==============================================================
     // FUNCIONES PRINCIPALES
     //==============================================================
 
     /**
-     * @notice Registra una lectura de temperatura. Solo el propietario actual puede hacerlo.
-     * Si la temperatura está fuera de rango, el lote se marca como comprometido.
+     * @notice Registra una lectura de temperatura del sensor `_sensorId`. Solo los oráculos
+     * de sensores autorizados pueden hacerlo. Cada lectura emite TemperaturaRegistrada, de
+     * modo que el historial on-chain prueba la monitorización continua; si la temperatura
+     * está fuera de rango, el lote se marca además como comprometido.
      */
-    function registrarTemperatura(int8 _tempMin, int8 _tempMax) external {
+    function registrarTemperatura(
+        int8 _tempMin,
+        int8 _tempMax,
+        string calldata _sensorId
+    ) external soloRol(ROL_ORACULO) {
         // require(!comprometido, "El lote ya esta comprometido");
 
+        bool fueraDeRango = _registrarRango(_tempMin, _tempMax);
+        emit TemperaturaRegistrada(
+            msg.sender,
+            _sensorId,
+            _tempMin,
+            _tempMax,
+            !fueraDeRango,
+            block.timestamp
+        );
+        _comprometerSiFuera(fueraDeRango, _tempMin, _tempMax);
+    }
+
+    /**
+     * @notice Ancla un lote de lecturas de sensores tomadas entre `_desde` y `_hasta`.
+     * Las lecturas se quedan fuera de la cadena: solo se guarda la raíz del árbol Merkle
+     * que las contiene, con su mínimo y máximo, de modo que cualquiera puede verificar
+     * con una prueba Merkle que una lectura concreta formaba parte del lote anclado.
+     */
+    function anclarLecturas(
+        bytes32 _raizMerkle,
+        int8 _tempMin,
+        int8 _tempMax,
+        uint32 _totalLecturas,
+        uint64 _desde,
+        uint64 _hasta
+    ) external soloRol(ROL_ORACULO) {
+        require(_raizMerkle != bytes32(0), "Raiz Merkle invalida");
+        require(anclajes[_raizMerkle] == 0, "Lecturas ya ancladas");
+        anclajes[_raizMerkle] = block.timestamp;
+
+        bool fueraDeRango = _registrarRango(_tempMin, _tempMax);
+        emit LecturasAncladas(
+            msg.sender,
+            _raizMerkle,
+            _tempMin,
+            _tempMax,
+            _totalLecturas,
+            _desde,
+            _hasta,
+            !fueraDeRango
+        );
+        _comprometerSiFuera(fueraDeRango, _tempMin, _tempMax);
+    }
+
+    function _registrarRango(int8 _tempMin, int8 _tempMax) private returns (bool) {
         tempRegMinima = _tempMin;
         tempRegMaxima = _tempMax;
-        if (_tempMin < temperaturaMinima || _tempMax > temperaturaMaxima) {
+        return _tempMin < temperaturaMinima || _tempMax > temperaturaMaxima;
+    }
+
+    function _comprometerSiFuera(bool _fueraDeRango, int8 _tempMin, int8 _tempMax) private {
+        if (_fueraDeRango) {
             comprometido = true;
             emit LoteComprometido(
                 msg.sender,
@@ -104,34 +283,169 @@ contract LoteTracing {
     }
 
     /**
-     * @notice Transfiere la propiedad y responsabilidad del lote a un nuevo custodio.
+     * @notice Obsoleta: la custodia solo cambia cuando el destinatario acepta una
+     * propuesta. Se mantiene para que las llamadas antiguas fallen con un motivo claro.
      */
-    function transferirCustodia(
-        address _nuevoPropietario
+    function transferirCustodia(address) external pure {
+        revert("Use proponerCustodia y aceptarCustodia");
+    }
+
+    //==============================================================
+    // TRASPASO DE CUSTODIA EN DOS PASOS
+    //==============================================================
+
+    /**
+     * @notice Propone entregar el lote a `_destinatario`, un distribuidor o una farmacia,
+     * con los detalles del envío. La custodia no cambia hasta que el destinatario acepta
+     * la propuesta, directamente o con una firma EIP-712, antes de `_plazo` segundos.
+     */
+    function proponerCustodia(
+        address _destinatario,
+        uint64 _plazo,
+        string calldata _detallesEnvio
     ) external soloPropietario {
-        require(_nuevoPropietario != address(0), "Direccion invalida");
+        require(_destinatario != address(0), "Direccion invalida");
+        require(
+            roles[ROL_DISTRIBUIDOR][_destinatario] || roles[ROL_FARMACIA][_destinatario],
+            "El nuevo propietario no es distribuidor ni farmacia"
+        );
+        require(_plazo > 0 && _plazo <= PLAZO_MAXIMO_CUSTODIA, "Plazo invalido");
+        require(
+            custodioPropuesto == address(0) || block.timestamp > propuestaExpira,
+            "Propuesta de custodia pendiente"
+        );
+
+        custodioPropuesto = _destinatario;
+        propuestaExpira = uint64(block.timestamp) + _plazo;
+        propuestaCustodia += 1;
+        envioPropuesto = keccak256(bytes(_detallesEnvio));
+
+        emit CustodiaPropuesta(
+            propuestaCustodia,
+            msg.sender,
+            _destinatario,
+            propuestaExpira,
+            envioPropuesto,
+            _detallesEnvio
+        );
+    }
+
+    /**
+     * @notice El destinatario acepta la propuesta pendiente y pasa a ser el propietario.
+     */
+    function aceptarCustodia() external {
+        _aceptarCustodia(msg.sender, false);
+    }
+
+    /**
+     * @notice Acepta la propuesta pendiente con la firma EIP-712 del destinatario sobre
+     * `AceptacionCustodia`. Cualquier cuenta puede enviar la firma y pagar el gas.
+     */
+    function aceptarCustodiaFirmada(uint8 _v, bytes32 _r, bytes32 _s) external {
+        // Firmas maleables (s alto) no valen
+        require(uint256(_s) <= MITAD_ORDEN_SECP256K1, "Firma invalida");
+        address firmante = ecrecover(hashAceptacionCustodia(), _v, _r, _s);
+        require(firmante != address(0), "Firma invalida");
+        _aceptarCustodia(firmante, true);
+    }
+
+    /**
+     * @notice El destinatario rechaza la propuesta pendiente; la custodia no cambia.
+     */
+    function rechazarCustodia(string calldata _motivo) external {
+        require(custodioPropuesto != address(0), "Sin propuesta de custodia");
+        require(msg.sender == custodioPropuesto, "Accion solo permitida para el destinatario");
+        _limpiarPropuesta();
+        emit CustodiaRechazada(propuestaCustodia, msg.sender, _motivo);
+    }
+
+    /**
+     * @notice El propietario retira la propuesta pendiente.
+     */
+    function cancelarCustodia() external soloPropietario {
+        require(custodioPropuesto != address(0), "Sin propuesta de custodia");
+        _limpiarPropuesta();
+        emit CustodiaCancelada(propuestaCustodia, msg.sender);
+    }
+
+    /**
+     * @notice Separador de dominio EIP-712 del contrato (nombre LoteTracing, versión 1).
+     */
+    function DOMAIN_SEPARATOR() public view returns (bytes32) {
+        return
+            keccak256(
+                abi.encode(
+                    DOMINIO_TYPEHASH,
+                    keccak256("LoteTracing"),
+                    keccak256("1"),
+                    block.chainid,
+                    address(this)
+                )
+            );
+    }
+
+    /**
+     * @notice Hash EIP-712 que firma el destinatario para aceptar la propuesta pendiente.
+     */
+    function hashAceptacionCustodia() public view returns (bytes32) {
+        bytes32 structHash = keccak256(
+            abi.encode(
+                ACEPTACION_CUSTODIA_TYPEHASH,
+                propuestaCustodia,
+                custodioPropuesto,
+                envioPropuesto
+            )
+        );
+        return keccak256(abi.encodePacked(bytes2(0x1901), DOMAIN_SEPARATOR(), structHash));
+    }
+
+    function _aceptarCustodia(address _receptor, bool _firmada) private {
+        require(custodioPropuesto != address(0), "Sin propuesta de custodia");
+        require(_receptor == custodioPropuesto, "Accion solo permitida para el destinatario");
+        require(block.timestamp <= propuestaExpira, "Propuesta de custodia vencida");
+        require(
+            roles[ROL_DISTRIBUIDOR][_receptor] || roles[ROL_FARMACIA][_receptor],
+            "El nuevo propietario no es distribuidor ni farmacia"
+        );
 
         address propietarioAnterior = propietarioActual;
-        propietarioActual = _nuevoPropietario;
+        propietarioActual = _receptor;
+        _limpiarPropuesta();
 
+        emit CustodiaAceptada(propuestaCustodia, _receptor, _firmada);
         emit CustodiaTransferida(
             propietarioAnterior,
-            _nuevoPropietario,
+            _receptor,
             comprometido,
-            "Custodia Transferida"
+            "Custodia Aceptada"
         );
     }
 
+    function _limpiarPropuesta() private {
+        custodioPropuesto = address(0);
+        propuestaExpira = 0;
+    }
+
     /**
-     * @notice Registra una lectura de temperatura. Solo el propietario actual puede hacerlo.
-     * Si la temperatura está fuera de rango, el lote se marca como comprometido.
+     * @notice Reinicia el contrato para un nuevo lote. Solo el fabricante del lote
+     * puede hacerlo, mientras conserve la custodia, antes de proponerla a nadie y
+     * si el lote no está comprometido: un lote comprometido nunca se rehabilita.
      */
-    function crearNuevoLote(string memory _loteId, int8 _tempMin, int8 _tempMax) external {
+    function crearNuevoLote(
+        string memory _loteId,
+        int8 _tempMin,
+        int8 _tempMax
+    ) external soloRol(ROL_FABRICANTE) {
+        require(msg.sender == fabricante, "Accion solo permitida para el fabricante del lote");
+        require(
+            propietarioActual == fabricante && propuestaCustodia == 0,
+            "El lote ya cambio de custodia"
+        );
+        require(!comprometido, "Lote comprometido");
+
         loteId = _loteId;
-        propietarioActual = msg.sender; // El fabricante es el primer propietario
         temperaturaMinima = _tempMin;
         temperaturaMaxima = _tempMax;
-        comprometido = false;
         tempRegMinima = 0;
         tempRegMaxima = 0;
 
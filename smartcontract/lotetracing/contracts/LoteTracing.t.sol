@@ -10,6 +10,7 @@ contract LoteTracingTest is Test {
     address fabricante = address(0x1);
     address distribuidor = address(0x2);
     address farmacia = address(0x3);
+    address oraculo = address(0x4);
     
     string constant LOTE_ID = "LOT-2024-001";
     int8 constant TEMP_MIN = 2;
@@ -22,6 +23,14 @@ contract LoteTracingTest is Test {
             TEMP_MIN,
             TEMP_MAX
         );
+
+        // El fabricante administra los roles y también opera un sensor
+        vm.startPrank(fabricante);
+        lote.otorgarRol(lote.ROL_ORACULO(), fabricante);
+        lote.otorgarRol(lote.ROL_ORACULO(), oraculo);
+        lote.otorgarRol(lote.ROL_DISTRIBUIDOR(), distribuidor);
+        lote.otorgarRol(lote.ROL_FARMACIA(), farmacia);
+        vm.stopPrank();
     }
 
     function test_InitialValues() public view {
@@ -31,12 +40,14 @@ contract LoteTracingTest is Test {
         assertEq(lote.temperaturaMinima(), TEMP_MIN);
         assertEq(lote.temperaturaMaxima(), TEMP_MAX);
         assertEq(lote.comprometido(), false);
+        assertEq(lote.admin(), fabricante);
+        assertTrue(lote.tieneRol(lote.ROL_FABRICANTE(), fabricante));
     }
 
     function test_RegistrarTemperatura_Valida() public {
-        // Registrar rango de temperatura válido (solo el propietario puede hacerlo)
+        // Registrar rango de temperatura válido (solo cuentas con rol de oráculo)
         vm.prank(fabricante);
-        lote.registrarTemperatura(TEMP_MIN, TEMP_MAX);
+        lote.registrarTemperatura(TEMP_MIN, TEMP_MAX, "SENSOR-01");
         
         // Verificar que el lote no está comprometido
         assertEq(lote.comprometido(), false);
@@ -44,52 +55,305 @@ contract LoteTracingTest is Test {
 
 
 
+    function test_RegistrarTemperatura_SoloOraculo() public {
+        vm.prank(distribuidor);
+        vm.expectRevert("Cuenta sin el rol requerido");
+        lote.registrarTemperatura(TEMP_MIN, TEMP_MAX, "SENSOR-01");
+    }
+
+    event TemperaturaRegistrada(
+        address indexed oraculo,
+        string sensorId,
+        int8 tempMin,
+        int8 tempMax,
+        bool enRango,
+        uint256 timestamp
+    );
+
+    function test_RegistrarTemperatura_EmiteLectura() public {
+        // Cada lectura deja constancia on-chain, también dentro de rango
+        vm.expectEmit(true, false, false, true, address(lote));
+        emit TemperaturaRegistrada(oraculo, "SENSOR-01", 3, 7, true, block.timestamp);
+        vm.prank(oraculo);
+        lote.registrarTemperatura(3, 7, "SENSOR-01");
+    }
+
+    event LecturasAncladas(
+        address indexed oraculo,
+        bytes32 indexed raizMerkle,
+        int8 tempMin,
+        int8 tempMax,
+        uint32 totalLecturas,
+        uint64 desde,
+        uint64 hasta,
+        bool enRango
+    );
+
+    function test_AnclarLecturas() public {
+        bytes32 raiz = keccak256("lecturas-SENSOR-01");
+        vm.expectEmit(true, true, false, true, address(lote));
+        emit LecturasAncladas(oraculo, raiz, 3, 7, 60, 1700000000, 1700000300, true);
+        vm.prank(oraculo);
+        lote.anclarLecturas(raiz, 3, 7, 60, 1700000000, 1700000300);
+
+        assertEq(lote.anclajes(raiz), block.timestamp);
+        assertEq(lote.tempRegMaxima(), 7);
+        assertEq(lote.comprometido(), false);
+    }
+
+    function test_AnclarLecturas_SoloUnaVez() public {
+        bytes32 raiz = keccak256("lecturas-SENSOR-01");
+        vm.startPrank(oraculo);
+        lote.anclarLecturas(raiz, 3, 7, 60, 1700000000, 1700000300);
+        vm.expectRevert("Lecturas ya ancladas");
+        lote.anclarLecturas(raiz, 3, 7, 60, 1700000000, 1700000300);
+        vm.expectRevert("Raiz Merkle invalida");
+        lote.anclarLecturas(bytes32(0), 3, 7, 60, 1700000000, 1700000300);
+        vm.stopPrank();
+    }
+
+    function test_AnclarLecturas_FueraDeRango() public {
+        vm.prank(oraculo);
+        lote.anclarLecturas(keccak256("lecturas-SENSOR-01"), 1, 7, 60, 1700000000, 1700000300);
+        assertEq(lote.comprometido(), true);
+    }
+
+    function test_AnclarLecturas_SoloOraculo() public {
+        vm.prank(distribuidor);
+        vm.expectRevert("Cuenta sin el rol requerido");
+        lote.anclarLecturas(keccak256("lecturas-SENSOR-01"), 3, 7, 60, 1700000000, 1700000300);
+    }
+
     function test_RegistrarTemperatura_RangoInvalido() public {
         // Registrar rango que está fuera de los límites del contrato
         vm.prank(fabricante);
-        lote.registrarTemperatura(10, 15); // Rango 10-15 está fuera de 2-8
+        lote.registrarTemperatura(10, 15, "SENSOR-01"); // Rango 10-15 está fuera de 2-8
         
         // Verificar que el lote se marcó como comprometido
         assertEq(lote.comprometido(), true);
     }
 
-    function test_TransferirCustodia() public {
-        vm.prank(fabricante);
+    function test_TransferirCustodia_Retirada() public {
+        // La custodia solo cambia cuando el destinatario acepta
+        vm.expectRevert("Use proponerCustodia y aceptarCustodia");
         lote.transferirCustodia(distribuidor);
-        
+        assertEq(lote.propietarioActual(), fabricante);
+    }
+
+    function test_ProponerYAceptarCustodia() public {
+        vm.prank(fabricante);
+        lote.proponerCustodia(distribuidor, 2 days, '{"guia":"GUIA-001"}');
+
+        // La custodia no cambia hasta que el destinatario acepta
+        assertEq(lote.propietarioActual(), fabricante);
+        assertEq(lote.custodioPropuesto(), distribuidor);
+        assertEq(lote.propuestaExpira(), block.timestamp + 2 days);
+        assertEq(lote.envioPropuesto(), keccak256('{"guia":"GUIA-001"}'));
+
+        vm.prank(distribuidor);
+        lote.aceptarCustodia();
         assertEq(lote.propietarioActual(), distribuidor);
+        assertEq(lote.custodioPropuesto(), address(0));
+        assertEq(lote.propuestaCustodia(), 1);
     }
 
-    function test_TransferirCustodia_SoloPropietario() public {
+    function test_ProponerCustodia_Validaciones() public {
         vm.prank(distribuidor);
         vm.expectRevert("Accion solo permitida para el propietario actual");
-        lote.transferirCustodia(farmacia);
+        lote.proponerCustodia(farmacia, 1 days, "");
+
+        vm.startPrank(fabricante);
+        vm.expectRevert("Direccion invalida");
+        lote.proponerCustodia(address(0), 1 days, "");
+        vm.expectRevert("El nuevo propietario no es distribuidor ni farmacia");
+        lote.proponerCustodia(oraculo, 1 days, "");
+        vm.expectRevert("Plazo invalido");
+        lote.proponerCustodia(farmacia, 31 days, "");
+        lote.proponerCustodia(farmacia, 1 days, "");
+        vm.expectRevert("Propuesta de custodia pendiente");
+        lote.proponerCustodia(distribuidor, 1 days, "");
+        vm.stopPrank();
     }
 
-    function test_TransferirCustodia_DireccionInvalida() public {
+    function test_AceptarCustodia_SoloDestinatarioYEnPlazo() public {
         vm.prank(fabricante);
-        vm.expectRevert("Direccion invalida");
-        lote.transferirCustodia(address(0));
+        lote.proponerCustodia(distribuidor, 1 hours, "");
+
+        vm.prank(farmacia);
+        vm.expectRevert("Accion solo permitida para el destinatario");
+        lote.aceptarCustodia();
+
+        vm.warp(block.timestamp + 2 hours);
+        vm.prank(distribuidor);
+        vm.expectRevert("Propuesta de custodia vencida");
+        lote.aceptarCustodia();
+
+        // Una propuesta vencida no impide proponer otra
+        vm.prank(fabricante);
+        lote.proponerCustodia(farmacia, 1 hours, "");
+        assertEq(lote.propuestaCustodia(), 2);
+    }
+
+    function test_AceptarCustodiaFirmada() public {
+        uint256 clave = 0xA11CE;
+        address receptor = vm.addr(clave);
+        vm.startPrank(fabricante);
+        lote.otorgarRol(lote.ROL_FARMACIA(), receptor);
+        lote.proponerCustodia(receptor, 1 days, "");
+        vm.stopPrank();
+
+        bytes32 structHash = keccak256(
+            abi.encode(lote.ACEPTACION_CUSTODIA_TYPEHASH(), uint32(1), receptor, lote.envioPropuesto())
+        );
+        bytes32 digest = keccak256(abi.encodePacked("\x19\x01", lote.DOMAIN_SEPARATOR(), structHash));
+        assertEq(lote.hashAceptacionCustodia(), digest);
+
+        // Una firma de otra cuenta no acepta la propuesta
+        (uint8 v, bytes32 r, bytes32 s) = vm.sign(0xB0B, digest);
+        vm.expectRevert("Accion solo permitida para el destinatario");
+        lote.aceptarCustodiaFirmada(v, r, s);
+
+        // Cualquier cuenta puede enviar la firma del destinatario
+        (v, r, s) = vm.sign(clave, digest);
+        vm.prank(distribuidor);
+        lote.aceptarCustodiaFirmada(v, r, s);
+        assertEq(lote.propietarioActual(), receptor);
+
+        vm.expectRevert("Sin propuesta de custodia");
+        lote.aceptarCustodiaFirmada(v, r, s);
+    }
+
+    function test_AceptarCustodiaFirmada_FirmaMaleableOSinFirmante() public {
+        uint256 clave = 0xA11CE;
+        address receptor = vm.addr(clave);
+        vm.startPrank(fabricante);
+        lote.otorgarRol(lote.ROL_FARMACIA(), receptor);
+        lote.proponerCustodia(receptor, 1 days, "");
+        vm.stopPrank();
+
+        // La otra firma válida de la misma clave, con s' = n - s, no vale
+        (uint8 v, bytes32 r, bytes32 s) = vm.sign(clave, lote.hashAceptacionCustodia());
+        uint256 n = 0xFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141;
+        vm.expectRevert("Firma invalida");
+        lote.aceptarCustodiaFirmada(v == 27 ? 28 : 27, r, bytes32(n - uint256(s)));
+
+        // Una firma que no recupera ninguna cuenta tampoco
+        vm.expectRevert("Firma invalida");
+        lote.aceptarCustodiaFirmada(27, bytes32(0), bytes32(0));
+
+        lote.aceptarCustodiaFirmada(v, r, s);
+        assertEq(lote.propietarioActual(), receptor);
+    }
+
+    function test_RechazarYCancelarCustodia() public {
+        vm.prank(fabricante);
+        lote.proponerCustodia(distribuidor, 1 days, "");
+        vm.prank(farmacia);
+        vm.expectRevert("Accion solo permitida para el destinatario");
+        lote.rechazarCustodia("No es para mi");
+        vm.prank(distribuidor);
+        lote.rechazarCustodia("Embalaje danado");
+        assertEq(lote.custodioPropuesto(), address(0));
+        assertEq(lote.propietarioActual(), fabricante);
+
+        vm.startPrank(fabricante);
+        vm.expectRevert("Sin propuesta de custodia");
+        lote.cancelarCustodia();
+        lote.proponerCustodia(distribuidor, 1 days, "");
+        lote.cancelarCustodia();
+        vm.stopPrank();
+
+        vm.prank(distribuidor);
+        vm.expectRevert("Sin propuesta de custodia");
+        lote.aceptarCustodia();
+    }
+
+    function test_OtorgarRol_SoloAdmin() public {
+        bytes32 rolOraculo = lote.ROL_ORACULO();
+        vm.prank(distribuidor);
+        vm.expectRevert("Accion solo permitida para el administrador");
+        lote.otorgarRol(rolOraculo, distribuidor);
+    }
+
+    function test_RevocarRol() public {
+        bytes32 rolOraculo = lote.ROL_ORACULO();
+        vm.prank(fabricante);
+        lote.revocarRol(rolOraculo, oraculo);
+        assertFalse(lote.tieneRol(rolOraculo, oraculo));
+
+        vm.prank(oraculo);
+        vm.expectRevert("Cuenta sin el rol requerido");
+        lote.registrarTemperatura(TEMP_MIN, TEMP_MAX, "SENSOR-01");
+    }
+
+    function test_CrearNuevoLote_SoloFabricante() public {
+        vm.prank(distribuidor);
+        vm.expectRevert("Cuenta sin el rol requerido");
+        lote.crearNuevoLote("LOT-DIST-001", TEMP_MIN, TEMP_MAX);
+    }
+
+    function test_CrearNuevoLote_FabricanteOriginal() public {
+        vm.prank(fabricante);
+        lote.crearNuevoLote("LOT-2024-002", 0, 10);
+
+        assertEq(lote.loteId(), "LOT-2024-002");
+        assertEq(lote.propietarioActual(), fabricante);
+        assertEq(lote.temperaturaMaxima(), 10);
+    }
+
+    function test_CrearNuevoLote_OtroFabricante() public {
+        address otroFabricante = address(0x5);
+        vm.prank(fabricante);
+        lote.otorgarRol(lote.ROL_FABRICANTE(), otroFabricante);
+
+        vm.prank(otroFabricante);
+        vm.expectRevert("Accion solo permitida para el fabricante del lote");
+        lote.crearNuevoLote("LOT-AJENO-001", TEMP_MIN, TEMP_MAX);
+        assertEq(lote.loteId(), LOTE_ID);
+    }
+
+    function test_CrearNuevoLote_LoteComprometido() public {
+        vm.prank(oraculo);
+        lote.registrarTemperatura(TEMP_MIN, TEMP_MAX + 5, "SENSOR-01");
+        assertTrue(lote.comprometido());
+
+        vm.prank(fabricante);
+        vm.expectRevert("Lote comprometido");
+        lote.crearNuevoLote("LOT-2024-002", TEMP_MIN, TEMP_MAX);
+        assertTrue(lote.comprometido());
+    }
+
+    function test_CrearNuevoLote_TrasProponerCustodia() public {
+        vm.prank(fabricante);
+        lote.proponerCustodia(distribuidor, 1 hours, "");
+
+        vm.prank(fabricante);
+        vm.expectRevert("El lote ya cambio de custodia");
+        lote.crearNuevoLote("LOT-2024-002", TEMP_MIN, TEMP_MAX);
     }
 
     function test_CicloCompleto() public {
         // 1. Fabricante registra rangos de temperatura válidos
         vm.prank(fabricante);
-        lote.registrarTemperatura(TEMP_MIN, TEMP_MAX);
+        lote.registrarTemperatura(TEMP_MIN, TEMP_MAX, "SENSOR-01");
         vm.prank(fabricante);
-        lote.registrarTemperatura(3, 7); // Rango válido dentro de 2-8
+        lote.registrarTemperatura(3, 7, "SENSOR-01"); // Rango válido dentro de 2-8
         
-        // 2. Transferir a distribuidor
+        // 2. Ceder la custodia al distribuidor, que la acepta
         vm.prank(fabricante);
-        lote.transferirCustodia(distribuidor);
-        
-        // 3. Distribuidor registra rango de temperatura
+        lote.proponerCustodia(distribuidor, 1 days, "");
         vm.prank(distribuidor);
-        lote.registrarTemperatura(TEMP_MIN, TEMP_MAX);
+        lote.aceptarCustodia();
+        
+        // 3. El oráculo registra rango de temperatura
+        vm.prank(oraculo);
+        lote.registrarTemperatura(TEMP_MIN, TEMP_MAX, "SENSOR-01");
         
-        // 4. Transferir a farmacia
+        // 4. Ceder la custodia a la farmacia, que la acepta
         vm.prank(distribuidor);
-        lote.transferirCustodia(farmacia);
+        lote.proponerCustodia(farmacia, 1 days, "");
+        vm.prank(farmacia);
+        lote.aceptarCustodia();
         
         // Verificaciones finales
         assertEq(lote.propietarioActual(), farmacia);
@@ -102,7 +366,7 @@ contract LoteTracingTest is Test {
         
         // Registrar rango de temperatura como propietario
         vm.prank(fabricante);
-        lote.registrarTemperatura(tempMin, tempMax);
+        lote.registrarTemperatura(tempMin, tempMax, "SENSOR-01");
         
         // Verificar estado según si el rango está dentro de los límites del contrato
         if (tempMin < TEMP_MIN || tempMax > TEMP_MAX) {
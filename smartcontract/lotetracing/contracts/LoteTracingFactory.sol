@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.28;
+
+/**
+ * @title LoteTracingFactory
+ * @author Grupo 2 - ArqNewGen - MATI for MediSupply
+ * @notice Registro de lotes en un único contrato. Crear un lote solo escribe
+ * dos slots de almacenamiento en lugar de desplegar un LoteTracing, y un
+ * loteId no se puede volver a crear, por lo que su historial es inmutable.
+ * Sus lotes son de solo lectura: las lecturas del oráculo, los roles y el
+ * traspaso de custodia en dos pasos solo existen en LoteTracing.
+ */
+contract LoteTracingFactory {
+    //==============================================================
+    // TIPOS
+    //==============================================================
+
+    struct Lote {
+        // --- Inmutables (Acta de Nacimiento del Lote) ---
+        address fabricante;
+        uint64 bloqueCreacion;
+        // --- Dinámicas (Estado Actual) ---
+        address propietarioActual;
+        int8 temperaturaMinima;
+        int8 temperaturaMaxima;
+        int8 tempRegMinima;
+        int8 tempRegMaxima;
+        bool comprometido;
+    }
+
+    //==============================================================
+    // VARIABLES DE ESTADO
+    //==============================================================
+
+    address public admin;
+    mapping(address => bool) public fabricantes;
+    // Indexado por keccak256(loteId), el mismo valor que los topics de los eventos
+    mapping(bytes32 => Lote) private lotes;
+    uint256 public totalLotes;
+
+    //==============================================================
+    // EVENTOS (El historial inmutable)
+    //==============================================================
+
+    event LoteCreado(
+        string indexed loteId,
+        address indexed fabricante,
+        int8 temperaturaMinima,
+        int8 temperaturaMaxima,
+        string motivo
+    );
+    // CustodiaTransferida y LoteComprometido ya no se emiten; se conservan para
+    // decodificar el historial de las factories desplegadas antes
+    event CustodiaTransferida(
+        string indexed loteId,
+        address indexed propietarioAnterior,
+        address indexed nuevoPropietario,
+        bool comprometido,
+        string motivo
+    );
+    event LoteComprometido(
+        string indexed loteId,
+        address indexed propietario,
+        int8 tempMin,
+        int8 tempMax,
+        bool comprometido,
+        string motivo
+    );
+    event FabricanteAutorizado(address indexed cuenta, bool autorizado);
+    event AdminTransferido(address indexed adminAnterior, address indexed nuevoAdmin);
+
+    //==============================================================
+    // MODIFICADORES DE ACCESO
+    //==============================================================
+
+    modifier soloAdmin() {
+        require(msg.sender == admin, "Accion solo permitida para el administrador");
+        _;
+    }
+
+    modifier soloFabricante() {
+        require(fabricantes[msg.sender], "Cuenta no autorizada como fabricante");
+        _;
+    }
+
+    //==============================================================
+    // CONSTRUCTOR
+    //==============================================================
+
+    constructor() {
+        admin = msg.sender;
+        fabricantes[msg.sender] = true;
+
+        emit AdminTransferido(address(0), msg.sender);
+        emit FabricanteAutorizado(msg.sender, true);
+    }
+
+    //==============================================================
+    // ADMINISTRACIÓN
+    //==============================================================
+
+    function autorizarFabricante(address _cuenta, bool _autorizado) external soloAdmin {
+        require(_cuenta != address(0), "Direccion invalida");
+        fabricantes[_cuenta] = _autorizado;
+        emit FabricanteAutorizado(_cuenta, _autorizado);
+    }
+
+    function transferirAdmin(address _nuevoAdmin) external soloAdmin {
+        require(_nuevoAdmin != address(0), "Direccion invalida");
+        emit AdminTransferido(admin, _nuevoAdmin);
+        admin = _nuevoAdmin;
+    }
+
+    //==============================================================
+    // FUNCIONES PRINCIPALES
+    //==============================================================
+
+    /**
+     * @notice Crea un lote nuevo con el remitente como fabricante y primer
+     * propietario. Un loteId solo se puede crear una vez.
+     */
+    function crearLote(string calldata _loteId, int8 _tempMin, int8 _tempMax) external soloFabricante {
+        require(bytes(_loteId).length > 0, "El loteId no puede estar vacio");
+        require(_tempMin <= _tempMax, "Rango de temperatura invalido");
+
+        Lote storage lote = lotes[keccak256(bytes(_loteId))];
+        require(lote.fabricante == address(0), "El lote ya existe");
+
+        lote.fabricante = msg.sender;
+        lote.bloqueCreacion = uint64(block.number);
+        lote.propietarioActual = msg.sender;
+        lote.temperaturaMinima = _tempMin;
+        lote.temperaturaMaxima = _tempMax;
+        totalLotes++;
+
+        emit LoteCreado(_loteId, msg.sender, _tempMin, _tempMax, "Lote Creado");
+    }
+
+    /**
+     * @notice Obsoleta: los lotes de la factory son de solo lectura. Las lecturas
+     * las firma el oráculo y la custodia cambia en dos pasos en un LoteTracing;
+     * se mantienen para que las llamadas antiguas fallen con un motivo claro.
+     */
+    function registrarTemperatura(string calldata, int8, int8) external pure {
+        revert("Lotes de la factory de solo lectura: use LoteTracing");
+    }
+
+    /**
+     * @notice Obsoleta: ver registrarTemperatura.
+     */
+    function transferirCustodia(string calldata, address) external pure {
+        revert("Lotes de la factory de solo lectura: use LoteTracing");
+    }
+
+    //==============================================================
+    // CONSULTAS
+    //==============================================================
+
+    function existeLote(string calldata _loteId) external view returns (bool) {
+        return lotes[keccak256(bytes(_loteId))].fabricante != address(0);
+    }
+
+    function obtenerLote(string calldata _loteId)
+        external
+        view
+        returns (
+            address fabricante,
+            address propietarioActual,
+            int8 temperaturaMinima,
+            int8 temperaturaMaxima,
+            int8 tempRegMinima,
+            int8 tempRegMaxima,
+            bool comprometido,
+            uint64 bloqueCreacion
+        )
+    {
+        Lote storage lote = _lote(_loteId);
+        return (
+            lote.fabricante,
+            lote.propietarioActual,
+            lote.temperaturaMinima,
+            lote.temperaturaMaxima,
+            lote.tempRegMinima,
+            lote.tempRegMaxima,
+            lote.comprometido,
+            lote.bloqueCreacion
+        );
+    }
+
+    function _lote(string calldata _loteId) private view returns (Lote storage lote) {
+        lote = lotes[keccak256(bytes(_loteId))];
+        require(lote.fabricante != address(0), "Lote no encontrado");
+    }
+}
@@ -0,0 +1,10 @@
+import { buildModule } from "@nomicfoundation/hardhat-ignition/modules";
+
+export default buildModule("LoteTracingFactoryModule", (m) => {
+  // The deployer becomes the admin and the first authorised manufacturer
+  const loteTracingFactory = m.contract("LoteTracingFactory");
+
+  return {
+    loteTracingFactory,
+  };
+});
@@ -1,4 +1,5 @@
 import { network } from "hardhat";
+import { keccak256, toHex } from "viem";
 
 console.log("=== Demo LoteTracing PoC - Trazabilidad Simplificada ===\n");
 
@@ -66,12 +67,28 @@ const lote = await viem.deployContract("LoteTracing", [
 console.log(`   ✅ Lote creado en: ${lote.address}`);
 console.log(`   📅 Fecha de creación: ${new Date().toISOString()}\n`);
 
+// El fabricante administra los roles: cada actor opera su propio sensor
+console.log("   🔑 Fabricante asigna los roles del lote...");
+const ROL_ORACULO = keccak256(toHex("ORACULO_SENSOR"));
+const asignaciones = [
+  [ROL_ORACULO, fabricante],
+  [ROL_ORACULO, distribuidor],
+  [ROL_ORACULO, farmacia],
+  [keccak256(toHex("DISTRIBUIDOR")), distribuidor],
+  [keccak256(toHex("FARMACIA")), farmacia],
+];
+for (const [rol, actor] of asignaciones) {
+  const hash = await lote.write.otorgarRol([rol, actor.account.address]);
+  await publicClient.waitForTransactionReceipt({ hash });
+}
+console.log(`   ✅ Roles de oráculo, distribuidor y farmacia asignados\n`);
+
 // 2. Register temperature ranges during manufacturing
 console.log("2. 🌡️  Fabricante registra rangos de temperatura durante fabricación...");
 const rangosIniciales = [[TEMP_MIN, TEMP_MAX], [3, 7], [2, 6]]; // All within valid range
 for (let i = 0; i < rangosIniciales.length; i++) {
   const [min, max] = rangosIniciales[i];
-  const hash = await lote.write.registrarTemperatura([min, max]);
+  const hash = await lote.write.registrarTemperatura([min, max, "SENSOR-01"]);
   await publicClient.waitForTransactionReceipt({ hash });
 
   console.log(`   📊 Rango registrado: ${min}°C - ${max}°C`);
@@ -86,9 +103,19 @@ console.log(
 
 // 3. Transfer to distributor
 console.log("3. 🚚 Transferencia a distribuidor...");
-const transferHash = await lote.write.transferirCustodia([
+// The owner proposes the handover and the distributor accepts it
+const propuestaHash = await lote.write.proponerCustodia([
   distribuidor.account.address,
+  86400n,
+  "",
 ]);
+await publicClient.waitForTransactionReceipt({ hash: propuestaHash });
+const transferHash = await distribuidor.writeContract({
+  address: lote.address,
+  abi: lote.abi,
+  functionName: "aceptarCustodia",
+  args: [],
+});
 await publicClient.waitForTransactionReceipt({ hash: transferHash });
 const propietarioActual = await lote.read.propietarioActual();
 console.log(`   ✅ Custodia transferida a: ${propietarioActual}`);
@@ -104,7 +131,7 @@ for (let i = 0; i < rangosTransporte.length; i++) {
     address: lote.address,
     abi: lote.abi,
     functionName: "registrarTemperatura",
-    args: [min, max],
+    args: [min, max, "SENSOR-01"],
   });
   await publicClient.waitForTransactionReceipt({ hash });
   console.log(`   📊 Rango en tránsito: ${min}°C - ${max}°C`);
@@ -119,11 +146,18 @@ console.log(
 
 // 5. Transfer to pharmacy
 console.log("5. 🏥 Transferencia a farmacia...");
-const transferHash2 = await distribuidor.writeContract({
+const propuestaHash2 = await distribuidor.writeContract({
   address: lote.address,
   abi: lote.abi,
-  functionName: "transferirCustodia",
-  args: [farmacia.account.address],
+  functionName: "proponerCustodia",
+  args: [farmacia.account.address, 86400n, ""],
+});
+await publicClient.waitForTransactionReceipt({ hash: propuestaHash2 });
+const transferHash2 = await farmacia.writeContract({
+  address: lote.address,
+  abi: lote.abi,
+  functionName: "aceptarCustodia",
+  args: [],
 });
 await publicClient.waitForTransactionReceipt({ hash: transferHash2 });
 const propietarioFinal = await lote.read.propietarioActual();
@@ -138,7 +172,7 @@ for (let i = 0; i < rangosFarmacia.length; i++) {
     address: lote.address,
     abi: lote.abi,
     functionName: "registrarTemperatura",
-    args: [min, max],
+    args: [min, max, "SENSOR-01"],
   });
   await publicClient.waitForTransactionReceipt({ hash });
   console.log(`   📊 Rango en farmacia: ${min}°C - ${max}°C`);
@@ -164,7 +198,7 @@ try {
     address: lote.address,
     abi: lote.abi,
     functionName: "registrarTemperatura",
-    args: [10, 15], // Range doesn't include contract's 2-8
+    args: [10, 15, "SENSOR-01"], // Range doesn't include contract's 2-8
   });
   await publicClient.waitForTransactionReceipt({ hash });
 
@@ -181,7 +215,7 @@ try {
       address: lote.address,
       abi: lote.abi,
       functionName: "registrarTemperatura",
-      args: [TEMP_MIN, TEMP_MAX],
+      args: [TEMP_MIN, TEMP_MAX, "SENSOR-01"],
     });
     await publicClient.waitForTransactionReceipt({ hash: hash2 });
     console.log(`   ✅ Registro permitido: Validación de lote comprometido deshabilitada`);
@@ -205,12 +239,12 @@ const NEW_TEMP_MAX = 15;
 console.log(`   📦 Creando nuevo lote: ${NEW_LOTE_ID}`);
 console.log(`   🌡️  Nuevo rango: ${NEW_TEMP_MIN}°C - ${NEW_TEMP_MAX}°C`);
 
-const newLoteHash = await farmacia.writeContract({
-  address: lote.address,
-  abi: lote.abi,
-  functionName: "crearNuevoLote",
-  args: [NEW_LOTE_ID, NEW_TEMP_MIN, NEW_TEMP_MAX],
-});
+// Solo las cuentas con rol de fabricante pueden crear lotes
+const newLoteHash = await lote.write.crearNuevoLote([
+  NEW_LOTE_ID,
+  NEW_TEMP_MIN,
+  NEW_TEMP_MAX,
+]);
 await publicClient.waitForTransactionReceipt({ hash: newLoteHash });
 
 // Verify new lot state
@@ -233,9 +267,9 @@ console.log(
 console.log(`📍 Dirección del contrato: ${lote.address}`);
 console.log(`🔍 Funcionalidades demostradas:`);
 console.log(`   - ✅ Creación de lote con parámetros de temperatura`);
-console.log(`   - ✅ Registro de rangos de temperatura por cualquier usuario`);
+console.log(`   - ✅ Registro de rangos de temperatura por cuentas con rol de oráculo`);
 console.log(`   - ✅ Transferencia de custodia entre actores`);
 console.log(`   - ✅ Validación de rangos contra temperaturas del contrato`);
 console.log(`   - ✅ Registro permitido en lotes comprometidos (validación deshabilitada)`);
-console.log(`   - ✅ Control de acceso para transferencia de custodia`);
+console.log(`   - ✅ Control de acceso por roles para temperatura, custodia y nuevos lotes`);
 console.log(`   - ✅ Creación de nuevo lote con parámetros diferentes`);
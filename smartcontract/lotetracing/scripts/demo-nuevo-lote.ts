@@ -1,4 +1,5 @@
 import { network } from "hardhat";
+import { keccak256, toHex } from "viem";
 
 console.log("=== Demo Función crearNuevoLote() ===\n");
 
@@ -31,11 +32,23 @@ console.log(`   ✅ Contrato desplegado en: ${lote.address}`);
 console.log(`   📋 Lote inicial: ${LOTE_INICIAL}`);
 console.log(`   🌡️  Rango inicial: ${TEMP_MIN_INICIAL}°C - ${TEMP_MAX_INICIAL}°C\n`);
 
-// Register some temperatures and transfer custody
+// Only accounts with the manufacturer role can create lots, and only
+// oracles register temperatures
+const ROL_FABRICANTE = keccak256(toHex("FABRICANTE"));
+const ROL_ORACULO = keccak256(toHex("ORACULO_SENSOR"));
+for (const usuario of [fabricante, usuario1, usuario2]) {
+  if (usuario !== fabricante) {
+    await lote.write.otorgarRol([ROL_FABRICANTE, usuario.account.address]);
+  }
+  await lote.write.otorgarRol([ROL_ORACULO, usuario.account.address]);
+}
+await lote.write.otorgarRol([keccak256(toHex("DISTRIBUIDOR")), usuario1.account.address]);
+
+// Register some temperatures; the manufacturer keeps custody, otherwise the
+// lot could no longer be reset
 console.log("2. 🔄 Operaciones iniciales...");
-await lote.write.registrarTemperatura([3, 7]);
-await lote.write.transferirCustodia([usuario1.account.address]);
-console.log(`   ✅ Temperatura registrada y custodia transferida a Usuario 1\n`);
+await lote.write.registrarTemperatura([3, 7, "SENSOR-01"]);
+console.log(`   ✅ Temperatura registrada\n`);
 
 // Demonstrate crearNuevoLote by different users
 const lotes = [
@@ -69,13 +82,19 @@ for (let i = 0; i < lotes.length; i++) {
   console.log(`   📦 Nuevo lote: ${loteId}`);
   console.log(`   🌡️  Nuevo rango: ${tempMin}°C - ${tempMax}°C`);
   
-  const hash = await usuario.writeContract({
-    address: lote.address,
-    abi: lote.abi,
-    functionName: "crearNuevoLote",
-    args: [loteId, tempMin, tempMax],
-  });
-  await publicClient.waitForTransactionReceipt({ hash });
+  // Only the lot's own manufacturer can reset it
+  try {
+    const hash = await usuario.writeContract({
+      address: lote.address,
+      abi: lote.abi,
+      functionName: "crearNuevoLote",
+      args: [loteId, tempMin, tempMax],
+    });
+    await publicClient.waitForTransactionReceipt({ hash });
+  } catch (error: any) {
+    console.log(`   ❌ Rechazado: ${error.shortMessage ?? error.message}\n`);
+    continue;
+  }
   
   // Verify state after creation
   const estadoActual = {
@@ -101,7 +120,7 @@ for (let i = 0; i < lotes.length; i++) {
     address: lote.address,
     abi: lote.abi,
     functionName: "registrarTemperatura",
-    args: testTemp,
+    args: [...testTemp, "SENSOR-01"],
   });
   
   const comprometidoDespues = await lote.read.comprometido();
@@ -110,17 +129,14 @@ for (let i = 0; i < lotes.length; i++) {
 
 console.log("=== Resumen de Funcionalidad crearNuevoLote() ===");
 console.log("✅ Características demostradas:");
-console.log("   - Cualquier usuario puede crear un nuevo lote");
-console.log("   - Se reinician todos los parámetros del lote");
-console.log("   - El creador se convierte en el nuevo propietario");
+console.log("   - Solo el fabricante del lote puede reiniciarlo, aunque otras cuentas tengan el rol");
+console.log("   - Solo mientras conserve la custodia y no la haya propuesto a nadie");
+console.log("   - Un lote comprometido no se puede reiniciar");
 console.log("   - Se pueden establecer nuevos rangos de temperatura");
-console.log("   - El estado se resetea completamente (no comprometido)");
 console.log("   - Las temperaturas registradas se reinician a 0");
 console.log("\n⚠️  Consideraciones de seguridad:");
-console.log("   - Esta función rompe la inmutabilidad del contrato");
-console.log("   - Permite sobrescribir el historial del lote");
-console.log("   - No hay restricciones de acceso");
-console.log("   - Podría comprometer la trazabilidad");
+console.log("   - Esta función sobrescribe el loteId y los rangos del contrato");
+console.log("   - Para lotes nuevos es preferible la LoteTracingFactory");
 
 console.log(`\n📍 Dirección del contrato: ${lote.address}`);
 console.log("🎉 Demo completado exitosamente");
\ No newline at end of file
@@ -2,6 +2,7 @@ import assert from "node:assert/strict";
 import { describe, it } from "node:test";
 
 import { network } from "hardhat";
+import { keccak256, toHex } from "viem";
 
 describe("LoteTracing PoC", async function () {
   const networkConnection = await network.connect();
@@ -9,13 +10,51 @@ describe("LoteTracing PoC", async function () {
   const publicClient = await viem.getPublicClient();
 
   // Test addresses
-  const [fabricante, distribuidor, farmacia] = await viem.getWalletClients();
+  const [fabricante, distribuidor, farmacia, oraculo] =
+    await viem.getWalletClients();
 
   // Contract parameters
   const LOTE_ID = "LOT-2024-001";
   const TEMP_MIN = 2;
   const TEMP_MAX = 8;
 
+  // Role identifiers
+  const ROL_FABRICANTE = keccak256(toHex("FABRICANTE"));
+  const ROL_DISTRIBUIDOR = keccak256(toHex("DISTRIBUIDOR"));
+  const ROL_FARMACIA = keccak256(toHex("FARMACIA"));
+  const ROL_ORACULO = keccak256(toHex("ORACULO_SENSOR"));
+
+  // Deploys a lot with the supply chain roles granted: the fabricante also
+  // operates a sensor gateway so it can register readings directly
+  async function deployLote(loteId = LOTE_ID) {
+    const lote = await viem.deployContract("LoteTracing", [
+      loteId,
+      TEMP_MIN,
+      TEMP_MAX,
+    ]);
+    await lote.write.otorgarRol([ROL_ORACULO, fabricante.account.address]);
+    await lote.write.otorgarRol([ROL_ORACULO, oraculo.account.address]);
+    await lote.write.otorgarRol([ROL_DISTRIBUIDOR, distribuidor.account.address]);
+    await lote.write.otorgarRol([ROL_FARMACIA, farmacia.account.address]);
+    return lote;
+  }
+
+  // Hands custody over in two steps: the owner proposes it and the receiver accepts
+  async function cederCustodia(lote: any, propietario: any, receptor: any) {
+    await propietario.writeContract({
+      address: lote.address,
+      abi: lote.abi,
+      functionName: "proponerCustodia",
+      args: [receptor.account.address, 86400n, ""],
+    });
+    await receptor.writeContract({
+      address: lote.address,
+      abi: lote.abi,
+      functionName: "aceptarCustodia",
+      args: [],
+    });
+  }
+
   it("Should deploy and initialize correctly", async function () {
     const lote = await viem.deployContract("LoteTracing", [
       LOTE_ID,
@@ -38,59 +77,63 @@ describe("LoteTracing PoC", async function () {
       fabricante.account.address.toLowerCase()
     );
     assert.equal(comprometido, false);
+
+    // The deployer administers the roles and is the manufacturer
+    assert.equal(
+      (await lote.read.admin()).toLowerCase(),
+      fabricante.account.address.toLowerCase()
+    );
+    assert.equal(
+      await lote.read.tieneRol([ROL_FABRICANTE, fabricante.account.address]),
+      true
+    );
+    assert.equal(
+      await lote.read.tieneRol([ROL_ORACULO, fabricante.account.address]),
+      false
+    );
   });
 
   it("Should register valid temperature range correctly", async function () {
-    const lote = await viem.deployContract("LoteTracing", [
-      LOTE_ID,
-      TEMP_MIN,
-      TEMP_MAX,
-    ]);
+    const lote = await deployLote();
 
     // Register valid temperature range within contract's limits
-    await lote.write.registrarTemperatura([TEMP_MIN, TEMP_MAX]); // Range 2-8 is within limits
+    await lote.write.registrarTemperatura([TEMP_MIN, TEMP_MAX, "SENSOR-01"]); // Range 2-8 is within limits
 
     const comprometido = await lote.read.comprometido();
     assert.equal(comprometido, false);
   });
 
   it("Should mark lot as compromised when temperature range is invalid", async function () {
-    const lote = await viem.deployContract("LoteTracing", [
-      LOTE_ID,
-      TEMP_MIN,
-      TEMP_MAX,
-    ]);
+    const lote = await deployLote();
 
     // Register temperature range outside contract's limits (tempMax > 8)
-    await lote.write.registrarTemperatura([10, 15]); // Range 10-15 exceeds contract's max of 8
+    await lote.write.registrarTemperatura([10, 15, "SENSOR-01"]); // Range 10-15 exceeds contract's max of 8
 
     const comprometido = await lote.read.comprometido();
     assert.equal(comprometido, true);
   });
 
   it("Should mark lot as compromised when tempMin is below limit", async function () {
-    const lote = await viem.deployContract("LoteTracing", [
-      LOTE_ID,
-      TEMP_MIN,
-      TEMP_MAX,
-    ]);
+    const lote = await deployLote();
 
     // Register temperature range with tempMin below contract's limit (tempMin < 2)
-    await lote.write.registrarTemperatura([0, 6]); // Range 0-6, tempMin=0 < 2
+    await lote.write.registrarTemperatura([0, 6, "SENSOR-01"]); // Range 0-6, tempMin=0 < 2
 
     const comprometido = await lote.read.comprometido();
     assert.equal(comprometido, true);
   });
 
   it("Should transfer custody correctly", async function () {
-    const lote = await viem.deployContract("LoteTracing", [
-      LOTE_ID,
-      TEMP_MIN,
-      TEMP_MAX,
-    ]);
+    const lote = await deployLote();
+
+    // Single-step transfers are retired
+    await assert.rejects(
+      lote.write.transferirCustodia([distribuidor.account.address]),
+      /Use proponerCustodia y aceptarCustodia/
+    );
 
     // Transfer to distributor
-    await lote.write.transferirCustodia([distribuidor.account.address]);
+    await cederCustodia(lote, fabricante, distribuidor);
 
     const nuevoPropietario = await lote.read.propietarioActual();
     assert.equal(
@@ -100,34 +143,25 @@ describe("LoteTracing PoC", async function () {
   });
 
   it("Should complete full traceability cycle", async function () {
-    const lote = await viem.deployContract("LoteTracing", [
-      LOTE_ID,
-      TEMP_MIN,
-      TEMP_MAX,
-    ]);
+    const lote = await deployLote();
 
     // 1. Register valid temperature ranges as fabricante
-    await lote.write.registrarTemperatura([TEMP_MIN, TEMP_MAX]);
-    await lote.write.registrarTemperatura([3, 7]); // Another valid range within limits
+    await lote.write.registrarTemperatura([TEMP_MIN, TEMP_MAX, "SENSOR-01"]);
+    await lote.write.registrarTemperatura([3, 7, "SENSOR-01"]); // Another valid range within limits
 
     // 2. Transfer custody: Fabricante -> Distribuidor
-    await lote.write.transferirCustodia([distribuidor.account.address]);
+    await cederCustodia(lote, fabricante, distribuidor);
 
-    // 3. Register temperature range as distribuidor
-    await distribuidor.writeContract({
+    // 3. Register temperature range from the sensor oracle
+    await oraculo.writeContract({
       address: lote.address,
       abi: lote.abi,
       functionName: "registrarTemperatura",
-      args: [TEMP_MIN, TEMP_MAX],
+      args: [TEMP_MIN, TEMP_MAX, "SENSOR-01"],
     });
 
     // 4. Transfer custody: Distribuidor -> Farmacia
-    await distribuidor.writeContract({
-      address: lote.address,
-      abi: lote.abi,
-      functionName: "transferirCustodia",
-      args: [farmacia.account.address],
-    });
+    await cederCustodia(lote, distribuidor, farmacia);
 
     // Verify final state
     const propietarioFinal = await lote.read.propietarioActual();
@@ -140,48 +174,55 @@ describe("LoteTracing PoC", async function () {
     assert.equal(comprometido, false);
   });
 
-  it("Should allow anyone to register temperature but restrict custody transfer", async function () {
-    const lote = await viem.deployContract("LoteTracing", [
-      LOTE_ID,
-      TEMP_MIN,
-      TEMP_MAX,
-    ]);
+  it("Should restrict temperature registration to oracles and custody transfer to the owner", async function () {
+    const lote = await deployLote();
 
-    // Anyone can register temperature (this is the intended behavior)
-    await distribuidor.writeContract({
+    // Only accounts with the oracle role can register temperature
+    await assert.rejects(
+      distribuidor.writeContract({
+        address: lote.address,
+        abi: lote.abi,
+        functionName: "registrarTemperatura",
+        args: [TEMP_MIN, TEMP_MAX, "SENSOR-01"],
+      }),
+      /Cuenta sin el rol requerido/
+    );
+    await oraculo.writeContract({
       address: lote.address,
       abi: lote.abi,
       functionName: "registrarTemperatura",
-      args: [TEMP_MIN, TEMP_MAX],
+      args: [TEMP_MIN, TEMP_MAX, "SENSOR-01"],
     });
 
-    // But only the owner can transfer custody
+    // Custody only goes to distributors or pharmacies
+    await assert.rejects(
+      lote.write.proponerCustodia([oraculo.account.address, 86400n, ""]),
+      /El nuevo propietario no es distribuidor ni farmacia/
+    );
+
+    // And only the owner can transfer custody
     await assert.rejects(
       distribuidor.writeContract({
         address: lote.address,
         abi: lote.abi,
-        functionName: "transferirCustodia",
-        args: [farmacia.account.address],
+        functionName: "proponerCustodia",
+        args: [farmacia.account.address, 86400n, ""],
       }),
       /Accion solo permitida para el propietario actual/
     );
   });
 
   it("Should allow temperature registration even on compromised lot", async function () {
-    const lote = await viem.deployContract("LoteTracing", [
-      LOTE_ID,
-      TEMP_MIN,
-      TEMP_MAX,
-    ]);
+    const lote = await deployLote();
 
     // Compromise the lot with invalid range
-    await lote.write.registrarTemperatura([10, 15]); // Range 10-15 exceeds contract's max of 8
+    await lote.write.registrarTemperatura([10, 15, "SENSOR-01"]); // Range 10-15 exceeds contract's max of 8
 
     const comprometido = await lote.read.comprometido();
     assert.equal(comprometido, true);
 
     // Should still allow temperature registration (validation is commented out)
-    await lote.write.registrarTemperatura([TEMP_MIN, TEMP_MAX]);
+    await lote.write.registrarTemperatura([TEMP_MIN, TEMP_MAX, "SENSOR-01"]);
 
     // Verify the temperature was registered
     const tempRegMinima = await lote.read.tempRegMinima();
@@ -193,21 +234,17 @@ describe("LoteTracing PoC", async function () {
   it("Should emit events correctly", async function () {
     const deploymentBlockNumber = await publicClient.getBlockNumber();
 
-    const lote = await viem.deployContract("LoteTracing", [
-      LOTE_ID,
-      TEMP_MIN,
-      TEMP_MAX,
-    ]);
+    const lote = await deployLote();
 
     // Transfer custody to trigger event
-    await lote.write.transferirCustodia([distribuidor.account.address]);
+    await cederCustodia(lote, fabricante, distribuidor);
 
     // Register invalid temperature range to trigger compromised event
-    await distribuidor.writeContract({
+    await oraculo.writeContract({
       address: lote.address,
       abi: lote.abi,
       functionName: "registrarTemperatura",
-      args: [10, 15], // Range 10-15 exceeds contract's max of 8
+      args: [10, 15, "SENSOR-01"], // Range 10-15 exceeds contract's max of 8
     });
 
     // Check for custody transfer event
@@ -233,46 +270,219 @@ describe("LoteTracing PoC", async function () {
     assert.equal(compromisedEvents.length, 1);
   });
 
+  it("Should emit a reading event for every temperature registration", async function () {
+    const deploymentBlockNumber = await publicClient.getBlockNumber();
+
+    const lote = await deployLote();
+
+    // One reading within range and one outside it
+    await oraculo.writeContract({
+      address: lote.address,
+      abi: lote.abi,
+      functionName: "registrarTemperatura",
+      args: [3, 7, "SENSOR-01"],
+    });
+    await oraculo.writeContract({
+      address: lote.address,
+      abi: lote.abi,
+      functionName: "registrarTemperatura",
+      args: [1, 9, "SENSOR-02"],
+    });
+
+    const readingEvents = await publicClient.getContractEvents({
+      address: lote.address,
+      abi: lote.abi,
+      eventName: "TemperaturaRegistrada",
+      fromBlock: deploymentBlockNumber,
+      strict: true,
+    });
+
+    assert.equal(readingEvents.length, 2);
+    assert.equal(
+      readingEvents[0].args.oraculo.toLowerCase(),
+      oraculo.account.address.toLowerCase()
+    );
+    assert.equal(readingEvents[0].args.sensorId, "SENSOR-01");
+    assert.equal(readingEvents[0].args.enRango, true);
+    assert.equal(readingEvents[1].args.sensorId, "SENSOR-02");
+    assert.equal(readingEvents[1].args.tempMin, 1);
+    assert.equal(readingEvents[1].args.tempMax, 9);
+    assert.equal(readingEvents[1].args.enRango, false);
+
+    const block = await publicClient.getBlock({
+      blockNumber: readingEvents[1].blockNumber,
+    });
+    assert.equal(readingEvents[1].args.timestamp, block.timestamp);
+  });
+
+  it("Should anchor a batch of readings by its Merkle root", async function () {
+    const deploymentBlockNumber = await publicClient.getBlockNumber();
+
+    const lote = await deployLote();
+    const raiz = keccak256(toHex("lecturas-SENSOR-01"));
+
+    // Only oracles can anchor readings
+    await assert.rejects(
+      distribuidor.writeContract({
+        address: lote.address,
+        abi: lote.abi,
+        functionName: "anclarLecturas",
+        args: [raiz, 3, 7, 60, 1700000000n, 1700000300n],
+      }),
+      /Cuenta sin el rol requerido/
+    );
+    await oraculo.writeContract({
+      address: lote.address,
+      abi: lote.abi,
+      functionName: "anclarLecturas",
+      args: [raiz, 3, 7, 60, 1700000000n, 1700000300n],
+    });
+    assert.notEqual(await lote.read.anclajes([raiz]), 0n);
+    assert.equal(await lote.read.tempRegMinima(), 3);
+    assert.equal(await lote.read.comprometido(), false);
+
+    // A root can only be anchored once
+    await assert.rejects(
+      oraculo.writeContract({
+        address: lote.address,
+        abi: lote.abi,
+        functionName: "anclarLecturas",
+        args: [raiz, 3, 7, 60, 1700000000n, 1700000300n],
+      }),
+      /Lecturas ya ancladas/
+    );
+
+    // A batch outside the range compromises the lot
+    await oraculo.writeContract({
+      address: lote.address,
+      abi: lote.abi,
+      functionName: "anclarLecturas",
+      args: [keccak256(toHex("lecturas-SENSOR-02")), 1, 7, 60, 1700000300n, 1700000600n],
+    });
+    assert.equal(await lote.read.comprometido(), true);
+
+    const batchEvents = await publicClient.getContractEvents({
+      address: lote.address,
+      abi: lote.abi,
+      eventName: "LecturasAncladas",
+      fromBlock: deploymentBlockNumber,
+      strict: true,
+    });
+    assert.equal(batchEvents.length, 2);
+    assert.equal(batchEvents[0].args.raizMerkle, raiz);
+    assert.equal(batchEvents[0].args.totalLecturas, 60);
+    assert.equal(batchEvents[0].args.hasta, 1700000300n);
+    assert.equal(batchEvents[0].args.enRango, true);
+    assert.equal(batchEvents[1].args.enRango, false);
+  });
+
+  it("Should hand over custody only when the recipient accepts", async function () {
+    const lote = await deployLote();
+    const detalles = JSON.stringify({ guia: "GUIA-001", transportista: "FrioExpress" });
+
+    await lote.write.proponerCustodia([distribuidor.account.address, 86400n, detalles]);
+    assert.equal(
+      (await lote.read.propietarioActual()).toLowerCase(),
+      fabricante.account.address.toLowerCase()
+    );
+    assert.equal(await lote.read.envioPropuesto(), keccak256(toHex(detalles)));
+
+    // Only the recipient can accept or reject
+    await assert.rejects(
+      farmacia.writeContract({
+        address: lote.address,
+        abi: lote.abi,
+        functionName: "aceptarCustodia",
+        args: [],
+      }),
+      /Accion solo permitida para el destinatario/
+    );
+    await distribuidor.writeContract({
+      address: lote.address,
+      abi: lote.abi,
+      functionName: "aceptarCustodia",
+      args: [],
+    });
+    assert.equal(
+      (await lote.read.propietarioActual()).toLowerCase(),
+      distribuidor.account.address.toLowerCase()
+    );
+    assert.equal(
+      await lote.read.custodioPropuesto(),
+      "0x0000000000000000000000000000000000000000"
+    );
+  });
+
+  it("Should accept custody with a relayed EIP-712 signature", async function () {
+    const lote = await deployLote();
+    await lote.write.proponerCustodia([farmacia.account.address, 3600n, ""]);
+
+    const firma = await farmacia.signTypedData({
+      domain: {
+        name: "LoteTracing",
+        version: "1",
+        chainId: await publicClient.getChainId(),
+        verifyingContract: lote.address,
+      },
+      types: {
+        AceptacionCustodia: [
+          { name: "propuesta", type: "uint32" },
+          { name: "receptor", type: "address" },
+          { name: "envio", type: "bytes32" },
+        ],
+      },
+      primaryType: "AceptacionCustodia",
+      message: {
+        propuesta: 1,
+        receptor: farmacia.account.address,
+        envio: await lote.read.envioPropuesto(),
+      },
+    });
+    const r = `0x${firma.slice(2, 66)}` as `0x${string}`;
+    const s = `0x${firma.slice(66, 130)}` as `0x${string}`;
+    const v = parseInt(firma.slice(130, 132), 16);
+
+    // The fabricante relays the pharmacy's signature and pays the gas
+    await lote.write.aceptarCustodiaFirmada([v, r, s]);
+    assert.equal(
+      (await lote.read.propietarioActual()).toLowerCase(),
+      farmacia.account.address.toLowerCase()
+    );
+
+    const aceptadas = await publicClient.getContractEvents({
+      address: lote.address,
+      abi: lote.abi,
+      eventName: "CustodiaAceptada",
+      strict: true,
+    });
+    assert.equal(aceptadas.length, 1);
+    assert.equal(aceptadas[0].args.firmada, true);
+  });
+
   it("Should handle edge cases correctly", async function () {
-    const lote = await viem.deployContract("LoteTracing", [
-      LOTE_ID,
-      TEMP_MIN,
-      TEMP_MAX,
-    ]);
+    const lote = await deployLote();
 
     // Test exact boundary values - should NOT compromise
-    await lote.write.registrarTemperatura([TEMP_MIN, TEMP_MAX]); // Exactly 2-8
+    await lote.write.registrarTemperatura([TEMP_MIN, TEMP_MAX, "SENSOR-01"]); // Exactly 2-8
     assert.equal(await lote.read.comprometido(), false);
 
     // Deploy new contract for next test
-    const lote2 = await viem.deployContract("LoteTracing", [
-      LOTE_ID + "-2",
-      TEMP_MIN,
-      TEMP_MAX,
-    ]);
+    const lote2 = await deployLote(LOTE_ID + "-2");
 
     // Test just outside boundaries - should compromise (tempMin = 1 < 2)
-    await lote2.write.registrarTemperatura([1, TEMP_MAX]); // 1-8, tempMin=1 < 2
+    await lote2.write.registrarTemperatura([1, TEMP_MAX, "SENSOR-01"]); // 1-8, tempMin=1 < 2
     assert.equal(await lote2.read.comprometido(), true);
 
     // Deploy new contract for next test
-    const lote3 = await viem.deployContract("LoteTracing", [
-      LOTE_ID + "-3",
-      TEMP_MIN,
-      TEMP_MAX,
-    ]);
+    const lote3 = await deployLote(LOTE_ID + "-3");
 
     // Test just outside boundaries - should compromise (tempMax = 9 > 8)
-    await lote3.write.registrarTemperatura([TEMP_MIN, 9]); // 2-9, tempMax=9 > 8
+    await lote3.write.registrarTemperatura([TEMP_MIN, 9, "SENSOR-01"]); // 2-9, tempMax=9 > 8
     assert.equal(await lote3.read.comprometido(), true);
   });
 
   it("Should create new lot with different parameters", async function () {
-    const lote = await viem.deployContract("LoteTracing", [
-      LOTE_ID,
-      TEMP_MIN,
-      TEMP_MAX,
-    ]);
+    const lote = await deployLote();
 
     // Verify initial state
     assert.equal(await lote.read.loteId(), LOTE_ID);
@@ -302,31 +512,113 @@ describe("LoteTracing PoC", async function () {
     );
   });
 
-  it("Should allow anyone to create new lot", async function () {
-    const lote = await viem.deployContract("LoteTracing", [
-      LOTE_ID,
-      TEMP_MIN,
-      TEMP_MAX,
-    ]);
-
-    // Transfer custody to distributor first
-    await lote.write.transferirCustodia([distribuidor.account.address]);
+  it("Should restrict new lots to the lot's manufacturer", async function () {
+    const lote = await deployLote();
 
-    // Distributor creates new lot
+    // A distributor without the manufacturer role cannot create a lot
     const NEW_LOTE_ID = "LOT-DIST-001";
-    await distribuidor.writeContract({
+    await assert.rejects(
+      distribuidor.writeContract({
+        address: lote.address,
+        abi: lote.abi,
+        functionName: "crearNuevoLote",
+        args: [NEW_LOTE_ID, -10, 20],
+      }),
+      /Cuenta sin el rol requerido/
+    );
+
+    // Nor can another manufacturer overwrite this lot
+    await lote.write.otorgarRol([ROL_FABRICANTE, distribuidor.account.address]);
+    await assert.rejects(
+      distribuidor.writeContract({
+        address: lote.address,
+        abi: lote.abi,
+        functionName: "crearNuevoLote",
+        args: [NEW_LOTE_ID, -10, 20],
+      }),
+      /Accion solo permitida para el fabricante del lote/
+    );
+    assert.equal(await lote.read.loteId(), LOTE_ID);
+  });
+
+  it("Should not reset a compromised lot", async function () {
+    const lote = await deployLote();
+
+    await lote.write.registrarTemperatura([TEMP_MIN, 9, "SENSOR-01"]);
+    assert.equal(await lote.read.comprometido(), true);
+
+    await assert.rejects(
+      lote.write.crearNuevoLote(["LOT-2024-002", TEMP_MIN, TEMP_MAX]),
+      /Lote comprometido/
+    );
+    assert.equal(await lote.read.comprometido(), true);
+  });
+
+  it("Should let only the admin manage roles", async function () {
+    const deploymentBlockNumber = await publicClient.getBlockNumber();
+    const lote = await deployLote();
+
+    // Only the admin grants roles
+    await assert.rejects(
+      distribuidor.writeContract({
+        address: lote.address,
+        abi: lote.abi,
+        functionName: "otorgarRol",
+        args: [ROL_ORACULO, distribuidor.account.address],
+      }),
+      /Accion solo permitida para el administrador/
+    );
+    await assert.rejects(
+      lote.write.otorgarRol([keccak256(toHex("TRANSPORTISTA")), oraculo.account.address]),
+      /Rol invalido/
+    );
+
+    // Revoking the oracle role stops its readings
+    await lote.write.revocarRol([ROL_ORACULO, oraculo.account.address]);
+    assert.equal(
+      await lote.read.tieneRol([ROL_ORACULO, oraculo.account.address]),
+      false
+    );
+    await assert.rejects(
+      oraculo.writeContract({
+        address: lote.address,
+        abi: lote.abi,
+        functionName: "registrarTemperatura",
+        args: [TEMP_MIN, TEMP_MAX, "SENSOR-01"],
+      }),
+      /Cuenta sin el rol requerido/
+    );
+
+    // The new admin takes over role management
+    await lote.write.transferirAdmin([farmacia.account.address]);
+    await assert.rejects(
+      lote.write.otorgarRol([ROL_ORACULO, oraculo.account.address]),
+      /Accion solo permitida para el administrador/
+    );
+    await farmacia.writeContract({
       address: lote.address,
       abi: lote.abi,
-      functionName: "crearNuevoLote",
-      args: [NEW_LOTE_ID, -10, 20],
+      functionName: "otorgarRol",
+      args: [ROL_ORACULO, oraculo.account.address],
     });
 
-    // Verify distributor is now the owner
-    const propietarioActual = await lote.read.propietarioActual();
-    assert.equal(
-      propietarioActual.toLowerCase(),
-      distribuidor.account.address.toLowerCase()
-    );
-    assert.equal(await lote.read.loteId(), NEW_LOTE_ID);
+    const grantedEvents = await publicClient.getContractEvents({
+      address: lote.address,
+      abi: lote.abi,
+      eventName: "RolOtorgado",
+      fromBlock: deploymentBlockNumber,
+      strict: true,
+    });
+    // fabricante on deploy, four roles in deployLote and the new admin's grant
+    assert.equal(grantedEvents.length, 6);
+
+    const revokedEvents = await publicClient.getContractEvents({
+      address: lote.address,
+      abi: lote.abi,
+      eventName: "RolRevocado",
+      fromBlock: deploymentBlockNumber,
+      strict: true,
+    });
+    assert.equal(revokedEvents.length, 1);
   });
 });
@@ -0,0 +1,149 @@
+import assert from "node:assert/strict";
+import { describe, it } from "node:test";
+
+import { network } from "hardhat";
+
+describe("LoteTracingFactory", async function () {
+  const networkConnection = await network.connect();
+  const { viem } = networkConnection as any;
+  const publicClient = await viem.getPublicClient();
+
+  // Test addresses
+  const [fabricante, distribuidor] = await viem.getWalletClients();
+
+  // Lote parameters
+  const LOTE_ID = "LOT-2024-001";
+  const TEMP_MIN = 2;
+  const TEMP_MAX = 8;
+
+  it("Should create a lote owned by its manufacturer", async function () {
+    const factory = await viem.deployContract("LoteTracingFactory");
+
+    await factory.write.crearLote([LOTE_ID, TEMP_MIN, TEMP_MAX]);
+
+    const [fabricanteAddr, propietarioActual, tempMin, tempMax, , , comprometido] =
+      await factory.read.obtenerLote([LOTE_ID]);
+    assert.equal(
+      fabricanteAddr.toLowerCase(),
+      fabricante.account.address.toLowerCase()
+    );
+    assert.equal(
+      propietarioActual.toLowerCase(),
+      fabricante.account.address.toLowerCase()
+    );
+    assert.equal(tempMin, TEMP_MIN);
+    assert.equal(tempMax, TEMP_MAX);
+    assert.equal(comprometido, false);
+    assert.equal(await factory.read.totalLotes(), 1n);
+    assert.equal(await factory.read.existeLote([LOTE_ID]), true);
+  });
+
+  it("Should not allow a loteId to be created twice", async function () {
+    const factory = await viem.deployContract("LoteTracingFactory");
+
+    await factory.write.crearLote([LOTE_ID, TEMP_MIN, TEMP_MAX]);
+
+    await assert.rejects(
+      factory.write.crearLote([LOTE_ID, 0, 4]),
+      /El lote ya existe/
+    );
+  });
+
+  it("Should only allow authorised manufacturers to create lotes", async function () {
+    const factory = await viem.deployContract("LoteTracingFactory");
+
+    await assert.rejects(
+      distribuidor.writeContract({
+        address: factory.address,
+        abi: factory.abi,
+        functionName: "crearLote",
+        args: [LOTE_ID, TEMP_MIN, TEMP_MAX],
+      }),
+      /Cuenta no autorizada como fabricante/
+    );
+
+    // Only the admin can authorise manufacturers
+    await assert.rejects(
+      distribuidor.writeContract({
+        address: factory.address,
+        abi: factory.abi,
+        functionName: "autorizarFabricante",
+        args: [distribuidor.account.address, true],
+      }),
+      /Accion solo permitida para el administrador/
+    );
+
+    await factory.write.autorizarFabricante([distribuidor.account.address, true]);
+    await distribuidor.writeContract({
+      address: factory.address,
+      abi: factory.abi,
+      functionName: "crearLote",
+      args: [LOTE_ID, TEMP_MIN, TEMP_MAX],
+    });
+
+    assert.equal(await factory.read.existeLote([LOTE_ID]), true);
+  });
+
+  it("Should keep factory lotes read-only", async function () {
+    const factory = await viem.deployContract("LoteTracingFactory");
+
+    await factory.write.crearLote([LOTE_ID, TEMP_MIN, TEMP_MAX]);
+
+    // Readings and custody changes only exist in LoteTracing
+    await assert.rejects(
+      factory.write.registrarTemperatura([LOTE_ID, 10, 15]),
+      /Lotes de la factory de solo lectura: use LoteTracing/
+    );
+    await assert.rejects(
+      factory.write.transferirCustodia([LOTE_ID, distribuidor.account.address]),
+      /Lotes de la factory de solo lectura: use LoteTracing/
+    );
+
+    const [, propietarioActual, , , tempRegMinima, tempRegMaxima, comprometido] =
+      await factory.read.obtenerLote([LOTE_ID]);
+    assert.equal(
+      propietarioActual.toLowerCase(),
+      fabricante.account.address.toLowerCase()
+    );
+    assert.equal(tempRegMinima, 0);
+    assert.equal(tempRegMaxima, 0);
+    assert.equal(comprometido, false);
+  });
+
+  it("Should emit events filtered by loteId", async function () {
+    const deploymentBlockNumber = await publicClient.getBlockNumber();
+
+    const factory = await viem.deployContract("LoteTracingFactory");
+
+    await factory.write.crearLote([LOTE_ID, TEMP_MIN, TEMP_MAX]);
+    await factory.write.crearLote(["LOT-2024-002", TEMP_MIN, TEMP_MAX]);
+
+    const createdEvents = await publicClient.getContractEvents({
+      address: factory.address,
+      abi: factory.abi,
+      eventName: "LoteCreado",
+      args: { loteId: LOTE_ID },
+      fromBlock: deploymentBlockNumber,
+      strict: true,
+    });
+
+    assert.equal(createdEvents.length, 1);
+  });
+
+  it("Should reject unknown lotes and invalid parameters", async function () {
+    const factory = await viem.deployContract("LoteTracingFactory");
+
+    await assert.rejects(
+      factory.read.obtenerLote(["LOT-UNKNOWN"]),
+      /Lote no encontrado/
+    );
+    await assert.rejects(
+      factory.write.crearLote(["", TEMP_MIN, TEMP_MAX]),
+      /El loteId no puede estar vacio/
+    );
+    await assert.rejects(
+      factory.write.crearLote([LOTE_ID, TEMP_MAX, TEMP_MIN]),
+      /Rango de temperatura invalido/
+    );
+  });
+});